@@ -8,5 +8,17 @@ import (
 
 func main() {
 	app := app.New(os.Getenv("CONFIG_PATH"))
+
+	// avito-pvz verify-audit checks the audit chain and exits
+	if len(os.Args) > 1 && os.Args[1] == "verify-audit" {
+		os.Exit(app.VerifyAudit())
+	}
+
+	// avito-pvz create-user <email> <role> creates a user, e.g. the first
+	// moderator, and exits
+	if len(os.Args) > 3 && os.Args[1] == "create-user" {
+		os.Exit(app.CreateUser(os.Args[2], os.Args[3]))
+	}
+
 	app.Start()
 }
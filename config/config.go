@@ -9,20 +9,50 @@ import (
 
 type (
 	Config struct {
-		App        App        `yaml:"app"`
-		HTTP       HTTP       `yaml:"http"`
-		Postgres   Postgres   `yaml:"postgres"`
-		Log        Log        `yaml:"logger"`
-		Prometheus Prometheus `yaml:"prometheus"`
+		App          App          `yaml:"app"`
+		HTTP         HTTP         `yaml:"http"`
+		Postgres     Postgres     `yaml:"postgres"`
+		Log          Log          `yaml:"logger"`
+		Prometheus   Prometheus   `yaml:"prometheus"`
+		Notifier     Notifier     `yaml:"notifier"`
+		Pickup       Pickup       `yaml:"pickup"`
+		Auth         Auth         `yaml:"auth"`
+		Password     Password     `yaml:"password"`
+		Login        Login        `yaml:"login"`
+		Registration Registration `yaml:"registration"`
+		APIKeys      APIKeys      `yaml:"api_keys"`
+		OIDC         OIDC         `yaml:"oidc"`
+		MFA          MFA          `yaml:"mfa"`
+		Audit        Audit        `yaml:"audit"`
+		Idempotency  Idempotency  `yaml:"idempotency"`
 	}
 
 	App struct {
 		Name    string `env-required:"true" yaml:"name" env:"APP_NAME"`
 		Version string `env-required:"true" yaml:"version" env:"APP_VERSION"`
+		DevMode bool   `yaml:"dev_mode" env:"APP_DEV_MODE" env-default:"false"`
 	}
 
 	HTTP struct {
-		Port string `env-required:"true" yaml:"port" env:"SERVER_PORT"`
+		Port    string  `env-required:"true" yaml:"port" env:"SERVER_PORT"`
+		OpenAPI OpenAPI `yaml:"openapi"`
+		Legacy  Legacy  `yaml:"legacy"`
+		// TrustedProxies are the CIDR ranges whose X-Forwarded-For names
+		// the client; empty uses the peer address.
+		TrustedProxies []string `yaml:"trusted_proxies" env:"HTTP_TRUSTED_PROXIES"`
+	}
+	// Legacy are the root paths served before the API moved under /api/v1,
+	// kept as deprecated aliases of v1 until Sunset.
+	Legacy struct {
+		Enabled      bool      `yaml:"enabled" env:"HTTP_LEGACY_ENABLED" env-default:"true"`
+		DeprecatedAt time.Time `yaml:"deprecated_at" env:"HTTP_LEGACY_DEPRECATED_AT" env-default:"2026-11-01T00:00:00Z"`
+		Sunset       time.Time `yaml:"sunset" env:"HTTP_LEGACY_SUNSET" env-default:"2027-05-01T00:00:00Z"`
+	}
+	// OpenAPI checks the operations described in api/swagger.yaml against
+	// it. Response validation buffers every response, it is meant for tests.
+	OpenAPI struct {
+		ValidateRequests  bool `yaml:"validate_requests" env:"OPENAPI_VALIDATE_REQUESTS" env-default:"true"`
+		ValidateResponses bool `yaml:"validate_responses" env:"OPENAPI_VALIDATE_RESPONSES" env-default:"false"`
 	}
 
 	Postgres struct {
@@ -31,10 +61,100 @@ type (
 	}
 	Log struct {
 		Level string `env-required:"true" yaml:"level" env:"LOG_LEVEL"`
+		// Format is json, for log collectors, or text
+		Format string `yaml:"format" env:"LOG_FORMAT" env-default:"json"`
 	}
 	Prometheus struct {
 		Port string `env-required:"true" yaml:"port" env:"PROMETHEUS_PORT"`
 	}
+	Notifier struct {
+		Type     string `yaml:"type" env:"NOTIFIER_TYPE" env-default:"log"`
+		FilePath string `yaml:"file_path" env:"NOTIFIER_FILE_PATH"`
+	}
+	Pickup struct {
+		CodeTTL      time.Duration `yaml:"code_ttl" env:"PICKUP_CODE_TTL" env-default:"72h"`
+		MaxAttempts  int           `yaml:"max_attempts" env:"PICKUP_MAX_ATTEMPTS" env-default:"5"`
+		LockDuration time.Duration `yaml:"lock_duration" env:"PICKUP_LOCK_DURATION" env-default:"15m"`
+	}
+	Auth struct {
+		RevocationSyncInterval time.Duration `yaml:"revocation_sync_interval" env:"AUTH_REVOCATION_SYNC_INTERVAL" env-default:"1m"`
+		PermissionSyncInterval time.Duration `yaml:"permission_sync_interval" env:"AUTH_PERMISSION_SYNC_INTERVAL" env-default:"30s"`
+		Algorithm              string        `yaml:"algorithm" env:"JWT_ALGORITHM" env-default:"HS256"`
+		AccessSecret           string        `yaml:"-" env:"JWT_SECRET"`
+		RefreshSecret          string        `yaml:"-" env:"REFRESH_SECRET"`
+		SigningKeyID           string        `yaml:"signing_key_id" env:"JWT_SIGNING_KEY_ID"`
+		Keys                   []AuthKey     `yaml:"keys"`
+	}
+	AuthKey struct {
+		ID             string `yaml:"id"`
+		PrivateKeyFile string `yaml:"private_key_file"`
+		PublicKeyFile  string `yaml:"public_key_file"`
+	}
+	Password struct {
+		MinLength      int           `yaml:"min_length" env:"PASSWORD_MIN_LENGTH" env-default:"8"`
+		MaxLength      int           `yaml:"max_length" env:"PASSWORD_MAX_LENGTH" env-default:"72"`
+		RequireUpper   bool          `yaml:"require_upper" env:"PASSWORD_REQUIRE_UPPER" env-default:"true"`
+		RequireLower   bool          `yaml:"require_lower" env:"PASSWORD_REQUIRE_LOWER" env-default:"true"`
+		RequireDigit   bool          `yaml:"require_digit" env:"PASSWORD_REQUIRE_DIGIT" env-default:"true"`
+		RequireSpecial bool          `yaml:"require_special" env:"PASSWORD_REQUIRE_SPECIAL" env-default:"false"`
+		ResetTokenTTL  time.Duration `yaml:"reset_token_ttl" env:"PASSWORD_RESET_TOKEN_TTL" env-default:"30m"`
+		Argon2         Argon2        `yaml:"argon2"`
+	}
+	Argon2 struct {
+		Memory      uint32 `yaml:"memory" env:"PASSWORD_ARGON2_MEMORY" env-default:"65536"`
+		Iterations  uint32 `yaml:"iterations" env:"PASSWORD_ARGON2_ITERATIONS" env-default:"3"`
+		Parallelism uint8  `yaml:"parallelism" env:"PASSWORD_ARGON2_PARALLELISM" env-default:"4"`
+	}
+	Login struct {
+		MaxEmailFailures int           `yaml:"max_email_failures" env:"LOGIN_MAX_EMAIL_FAILURES" env-default:"5"`
+		MaxIPFailures    int           `yaml:"max_ip_failures" env:"LOGIN_MAX_IP_FAILURES" env-default:"50"`
+		BaseDelay        time.Duration `yaml:"base_delay" env:"LOGIN_BASE_DELAY" env-default:"1s"`
+		LockDuration     time.Duration `yaml:"lock_duration" env:"LOGIN_LOCK_DURATION" env-default:"15m"`
+		FailureWindow    time.Duration `yaml:"failure_window" env:"LOGIN_FAILURE_WINDOW" env-default:"1h"`
+	}
+	Registration struct {
+		Open          bool          `yaml:"open" env:"REGISTRATION_OPEN" env-default:"false"`
+		InvitationTTL time.Duration `yaml:"invitation_ttl" env:"REGISTRATION_INVITATION_TTL" env-default:"72h"`
+	}
+	APIKeys struct {
+		DefaultTTL    time.Duration `yaml:"default_ttl" env:"API_KEYS_DEFAULT_TTL" env-default:"2160h"`
+		RotationGrace time.Duration `yaml:"rotation_grace" env:"API_KEYS_ROTATION_GRACE" env-default:"24h"`
+	}
+	OIDC struct {
+		Enabled      bool              `yaml:"enabled" env:"OIDC_ENABLED" env-default:"false"`
+		Issuer       string            `yaml:"issuer" env:"OIDC_ISSUER"`
+		ClientID     string            `yaml:"client_id" env:"OIDC_CLIENT_ID"`
+		ClientSecret string            `yaml:"-" env:"OIDC_CLIENT_SECRET"`
+		RedirectURL  string            `yaml:"redirect_url" env:"OIDC_REDIRECT_URL"`
+		Scopes       []string          `yaml:"scopes" env:"OIDC_SCOPES" env-default:"openid,email,profile"`
+		RoleClaim    string            `yaml:"role_claim" env:"OIDC_ROLE_CLAIM" env-default:"groups"`
+		RoleMappings []OIDCRoleMapping `yaml:"role_mappings"`
+		DefaultRole  string            `yaml:"default_role" env:"OIDC_DEFAULT_ROLE"`
+		StateTTL     time.Duration     `yaml:"state_ttl" env:"OIDC_STATE_TTL" env-default:"10m"`
+		MFAMethods   []string          `yaml:"mfa_methods" env:"OIDC_MFA_METHODS"`
+		Timeout      time.Duration     `yaml:"timeout" env:"OIDC_TIMEOUT" env-default:"10s"`
+	}
+	OIDCRoleMapping struct {
+		Value string `yaml:"value"`
+		Role  string `yaml:"role"`
+	}
+	MFA struct {
+		EncryptionKey string        `yaml:"-" env:"MFA_ENCRYPTION_KEY"`
+		Issuer        string        `yaml:"issuer" env:"MFA_ISSUER" env-default:"avito-pvz"`
+		RequiredRoles []string      `yaml:"required_roles" env:"MFA_REQUIRED_ROLES"`
+		ChallengeTTL  time.Duration `yaml:"challenge_ttl" env:"MFA_CHALLENGE_TTL" env-default:"5m"`
+		MaxAttempts   int           `yaml:"max_attempts" env:"MFA_MAX_ATTEMPTS" env-default:"5"`
+		RecoveryCodes int           `yaml:"recovery_codes" env:"MFA_RECOVERY_CODES" env-default:"10"`
+	}
+	Audit struct {
+		SigningKey         string        `yaml:"-" env:"AUDIT_SIGNING_KEY"`
+		SealInterval       time.Duration `yaml:"seal_interval" env:"AUDIT_SEAL_INTERVAL" env-default:"5s"`
+		CheckpointInterval time.Duration `yaml:"checkpoint_interval" env:"AUDIT_CHECKPOINT_INTERVAL" env-default:"1h"`
+	}
+	Idempotency struct {
+		TTL             time.Duration `yaml:"ttl" env:"IDEMPOTENCY_TTL" env-default:"24h"`
+		CleanupInterval time.Duration `yaml:"cleanup_interval" env:"IDEMPOTENCY_CLEANUP_INTERVAL" env-default:"1h"`
+	}
 )
 
 func New(configPath string) (*Config, error) {
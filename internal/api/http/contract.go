@@ -1,7 +1,97 @@
 package api
 
-import "github.com/labstack/echo/v4"
+import (
+	"context"
+
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/labstack/echo/v4"
+)
 
 type Handler interface {
 	Handle(c echo.Context) error
 }
+
+// The operations of api/swagger.yaml are served through the generated
+// strict server: each handler package implements one of them, and
+// StrictServer puts them together.
+
+type PostDummyLoginHandler interface {
+	PostDummyLogin(ctx context.Context, request dto.PostDummyLoginRequestObject) (dto.PostDummyLoginResponseObject, error)
+}
+
+type PostRegisterHandler interface {
+	PostRegister(ctx context.Context, request dto.PostRegisterRequestObject) (dto.PostRegisterResponseObject, error)
+}
+
+type PostLoginHandler interface {
+	PostLogin(ctx context.Context, request dto.PostLoginRequestObject) (dto.PostLoginResponseObject, error)
+}
+
+type GetPvzHandler interface {
+	GetPvz(ctx context.Context, request dto.GetPvzRequestObject) (dto.GetPvzResponseObject, error)
+}
+
+type PostPvzHandler interface {
+	PostPvz(ctx context.Context, request dto.PostPvzRequestObject) (dto.PostPvzResponseObject, error)
+}
+
+type PostPvzPvzIdCloseLastReceptionHandler interface {
+	PostPvzPvzIdCloseLastReception(ctx context.Context, request dto.PostPvzPvzIdCloseLastReceptionRequestObject) (dto.PostPvzPvzIdCloseLastReceptionResponseObject, error)
+}
+
+type PostPvzPvzIdDeleteLastProductHandler interface {
+	PostPvzPvzIdDeleteLastProduct(ctx context.Context, request dto.PostPvzPvzIdDeleteLastProductRequestObject) (dto.PostPvzPvzIdDeleteLastProductResponseObject, error)
+}
+
+type PostReceptionsHandler interface {
+	PostReceptions(ctx context.Context, request dto.PostReceptionsRequestObject) (dto.PostReceptionsResponseObject, error)
+}
+
+type PostProductsHandler interface {
+	PostProducts(ctx context.Context, request dto.PostProductsRequestObject) (dto.PostProductsResponseObject, error)
+}
+
+type PostProductsProductIdStoreHandler interface {
+	PostProductsProductIdStore(ctx context.Context, request dto.PostProductsProductIdStoreRequestObject) (dto.PostProductsProductIdStoreResponseObject, error)
+}
+
+type PostProductsProductIdIssueHandler interface {
+	PostProductsProductIdIssue(ctx context.Context, request dto.PostProductsProductIdIssueRequestObject) (dto.PostProductsProductIdIssueResponseObject, error)
+}
+
+type PostProductsProductIdReturnHandler interface {
+	PostProductsProductIdReturn(ctx context.Context, request dto.PostProductsProductIdReturnRequestObject) (dto.PostProductsProductIdReturnResponseObject, error)
+}
+
+type PostProductsProductIdWriteOffHandler interface {
+	PostProductsProductIdWriteOff(ctx context.Context, request dto.PostProductsProductIdWriteOffRequestObject) (dto.PostProductsProductIdWriteOffResponseObject, error)
+}
+
+// ProductStatusHandler serves the operations moving a product between
+// statuses, which differ only in the target status.
+type ProductStatusHandler interface {
+	PostProductsProductIdStoreHandler
+	PostProductsProductIdIssueHandler
+	PostProductsProductIdReturnHandler
+	PostProductsProductIdWriteOffHandler
+}
+
+type GetProductsProductIdHistoryHandler interface {
+	GetProductsProductIdHistory(ctx context.Context, request dto.GetProductsProductIdHistoryRequestObject) (dto.GetProductsProductIdHistoryResponseObject, error)
+}
+
+type StrictServer struct {
+	PostDummyLoginHandler
+	PostRegisterHandler
+	PostLoginHandler
+	GetPvzHandler
+	PostPvzHandler
+	PostPvzPvzIdCloseLastReceptionHandler
+	PostPvzPvzIdDeleteLastProductHandler
+	PostReceptionsHandler
+	PostProductsHandler
+	ProductStatusHandler
+	GetProductsProductIdHistoryHandler
+}
+
+var _ dto.StrictServerInterface = StrictServer{}
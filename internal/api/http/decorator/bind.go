@@ -5,8 +5,8 @@ import (
 	"net/http"
 
 	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
 	"github.com/labstack/echo/v4"
-	"github.com/sirupsen/logrus"
 )
 
 type handler[T any] interface {
@@ -22,17 +22,18 @@ func NewBindAndValidateDerocator[T any](inner handler[T]) api.Handler {
 }
 
 func (d *bindAndValidateDecorator[T]) Handle(c echo.Context) error {
-	logrus.Infof("HTTP %s %s from %s", c.Request().Method, c.Path(), c.Request().RemoteAddr)
+	log := logger.FromContext(c.Request().Context())
+	log.Infof("HTTP %s %s from %s", c.Request().Method, c.Path(), c.Request().RemoteAddr)
 
 	var in T
 
 	if err := c.Bind(&in); err != nil {
-		logrus.Errorf("Failed to bind request: %v", err)
+		log.Errorf("Failed to bind request: %v", err)
 		return d.handleError(err, err.Error())
 	}
 
 	if err := c.Validate(in); err != nil {
-		logrus.Errorf("Failed to validate request: %v", err)
+		log.Errorf("Failed to validate request: %v", err)
 		return d.handleError(err, err.Error())
 	}
 
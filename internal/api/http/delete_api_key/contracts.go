@@ -0,0 +1,13 @@
+package delete_api_key
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type ServiceAccountService interface {
+	RevokeAPIKey(ctx context.Context, actorID uuid.UUID, accountID uuid.UUID, keyID uuid.UUID) error
+}
@@ -0,0 +1,102 @@
+package delete_api_key_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/delete_api_key"
+	mock_delete_api_key "github.com/4udiwe/avito-pvz/internal/api/http/delete_api_key/mocks"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/service/service_account"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		moderatorID  = uuid.New()
+		accountID    = uuid.New()
+		keyID        = uuid.New()
+	)
+
+	type MockBehavior func(s *mock_delete_api_key.MockServiceAccountService)
+
+	for _, tc := range []struct {
+		name         string
+		body         string
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name: "success",
+			mockBehavior: func(s *mock_delete_api_key.MockServiceAccountService) {
+				s.EXPECT().RevokeAPIKey(gomock.Any(), moderatorID, accountID, keyID).Return(nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   "",
+		},
+		{
+			name: "no key found",
+			mockBehavior: func(s *mock_delete_api_key.MockServiceAccountService) {
+				s.EXPECT().RevokeAPIKey(gomock.Any(), moderatorID, accountID, keyID).Return(service_account.ErrNoAPIKeyFound).Times(1)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   service_account.ErrNoAPIKeyFound.Error(),
+		},
+		{
+			name: "internal error",
+			mockBehavior: func(s *mock_delete_api_key.MockServiceAccountService) {
+				s.EXPECT().RevokeAPIKey(gomock.Any(), moderatorID, accountID, keyID).Return(arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+			req := httptest.NewRequest(http.MethodDelete, "/", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+			ctx.SetParamNames("accountId", "keyId")
+			ctx.SetParamValues(accountID.String(), keyID.String())
+
+			ctx.Set(middleware.USER_CLAIMS_KEY, &auth.TokenClaims{UserID: moderatorID, Role: entity.RoleModerator})
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_delete_api_key.NewMockServiceAccountService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := delete_api_key.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, strings.Trim(rec.Body.String(), "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: contracts.go
+//
+// Generated by this command:
+//
+//	mockgen -source=contracts.go -destination=mocks/mock_service.go
+//
+
+// Package mock_delete_api_key is a generated GoMock package.
+package mock_delete_api_key
+
+import (
+	context "context"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockServiceAccountService is a mock of ServiceAccountService interface.
+type MockServiceAccountService struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceAccountServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockServiceAccountServiceMockRecorder is the mock recorder for MockServiceAccountService.
+type MockServiceAccountServiceMockRecorder struct {
+	mock *MockServiceAccountService
+}
+
+// NewMockServiceAccountService creates a new mock instance.
+func NewMockServiceAccountService(ctrl *gomock.Controller) *MockServiceAccountService {
+	mock := &MockServiceAccountService{ctrl: ctrl}
+	mock.recorder = &MockServiceAccountServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockServiceAccountService) EXPECT() *MockServiceAccountServiceMockRecorder {
+	return m.recorder
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockServiceAccountService) RevokeAPIKey(ctx context.Context, actorID, accountID, keyID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, actorID, accountID, keyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockServiceAccountServiceMockRecorder) RevokeAPIKey(ctx, actorID, accountID, keyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockServiceAccountService)(nil).RevokeAPIKey), ctx, actorID, accountID, keyID)
+}
@@ -9,5 +9,5 @@ import (
 //go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
 
 type ProductService interface {
-	DeleteLastProductFromReception(ctx context.Context, pointID uuid.UUID) error
+	DeleteLastProductFromReception(ctx context.Context, actorID uuid.UUID, pointID uuid.UUID) error
 }
@@ -1,13 +1,14 @@
 package delete_product
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
 	api "github.com/4udiwe/avito-pvz/internal/api/http"
-	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/dto"
 	service "github.com/4udiwe/avito-pvz/internal/service/product"
-	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
@@ -15,31 +16,35 @@ type handler struct {
 	s ProductService
 }
 
-func New(productService ProductService) api.Handler {
-	return decorator.NewBindAndValidateDerocator(&handler{
+func New(productService ProductService) api.PostPvzPvzIdDeleteLastProductHandler {
+	return &handler{
 		s: productService,
-	})
+	}
 }
 
-type Request struct {
-	PointID uuid.UUID `param:"pvzId" validate:"required"`
-}
+func (h *handler) PostPvzPvzIdDeleteLastProduct(
+	ctx context.Context,
+	request dto.PostPvzPvzIdDeleteLastProductRequestObject,
+) (dto.PostPvzPvzIdDeleteLastProductResponseObject, error) {
+	claims, err := middleware.UserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-func (h *handler) Handle(ctx echo.Context, in Request) error {
-	err := h.s.DeleteLastProductFromReception(ctx.Request().Context(), in.PointID)
+	err = h.s.DeleteLastProductFromReception(ctx, claims.UserID, request.PvzId)
 
 	if err != nil {
 		if errors.Is(err, service.ErrNoPointFound) {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
 		}
 		if errors.Is(err, service.ErrReceptionAlreadyClosed) {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
 		}
 		if errors.Is(err, service.ErrNoReceptionFound) {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
 		}
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return nil, echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
 	}
 
-	return ctx.NoContent(http.StatusOK)
+	return dto.PostPvzPvzIdDeleteLastProduct200Response{}, nil
 }
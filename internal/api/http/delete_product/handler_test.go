@@ -6,10 +6,14 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
 	"github.com/4udiwe/avito-pvz/internal/api/http/delete_product"
 	mock_delete_product "github.com/4udiwe/avito-pvz/internal/api/http/delete_product/mocks"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
 	service "github.com/4udiwe/avito-pvz/internal/service/product"
-	"github.com/4udiwe/avito-pvz/pkg/validator"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
@@ -19,6 +23,7 @@ import (
 
 func TestHandle(t *testing.T) {
 	var (
+		actorID      = uuid.New()
 		pointID      = uuid.New()
 		arbitraryErr = errors.New("arbitrary error")
 	)
@@ -36,7 +41,7 @@ func TestHandle(t *testing.T) {
 			name:    "success",
 			pointID: pointID.String(),
 			mockBehavior: func(s *mock_delete_product.MockProductService) {
-				s.EXPECT().DeleteLastProductFromReception(gomock.Any(), pointID).Return(nil).Times(1)
+				s.EXPECT().DeleteLastProductFromReception(gomock.Any(), actorID, pointID).Return(nil).Times(1)
 			},
 			wantStatus: http.StatusOK,
 			wantBody:   "",
@@ -45,7 +50,7 @@ func TestHandle(t *testing.T) {
 			name:    "point not found",
 			pointID: pointID.String(),
 			mockBehavior: func(s *mock_delete_product.MockProductService) {
-				s.EXPECT().DeleteLastProductFromReception(gomock.Any(), pointID).Return(service.ErrNoPointFound).Times(1)
+				s.EXPECT().DeleteLastProductFromReception(gomock.Any(), actorID, pointID).Return(service.ErrNoPointFound).Times(1)
 			},
 			wantStatus: http.StatusBadRequest,
 			wantBody:   service.ErrNoPointFound.Error(),
@@ -54,7 +59,7 @@ func TestHandle(t *testing.T) {
 			name:    "reception already closed",
 			pointID: pointID.String(),
 			mockBehavior: func(s *mock_delete_product.MockProductService) {
-				s.EXPECT().DeleteLastProductFromReception(gomock.Any(), pointID).Return(service.ErrReceptionAlreadyClosed).Times(1)
+				s.EXPECT().DeleteLastProductFromReception(gomock.Any(), actorID, pointID).Return(service.ErrReceptionAlreadyClosed).Times(1)
 			},
 			wantStatus: http.StatusBadRequest,
 			wantBody:   service.ErrReceptionAlreadyClosed.Error(),
@@ -63,7 +68,7 @@ func TestHandle(t *testing.T) {
 			name:    "no reception found",
 			pointID: pointID.String(),
 			mockBehavior: func(s *mock_delete_product.MockProductService) {
-				s.EXPECT().DeleteLastProductFromReception(gomock.Any(), pointID).Return(service.ErrNoReceptionFound).Times(1)
+				s.EXPECT().DeleteLastProductFromReception(gomock.Any(), actorID, pointID).Return(service.ErrNoReceptionFound).Times(1)
 			},
 			wantStatus: http.StatusBadRequest,
 			wantBody:   service.ErrNoReceptionFound.Error(),
@@ -72,27 +77,27 @@ func TestHandle(t *testing.T) {
 			name:    "internal error",
 			pointID: pointID.String(),
 			mockBehavior: func(s *mock_delete_product.MockProductService) {
-				s.EXPECT().DeleteLastProductFromReception(gomock.Any(), pointID).Return(arbitraryErr).Times(1)
+				s.EXPECT().DeleteLastProductFromReception(gomock.Any(), actorID, pointID).Return(arbitraryErr).Times(1)
 			},
 			wantStatus: http.StatusInternalServerError,
-			wantBody:   arbitraryErr.Error(),
+			wantBody:   http.StatusText(http.StatusInternalServerError),
 		},
 		{
 			name:         "invalid pvz id provided",
 			pointID:      "123",
 			mockBehavior: func(s *mock_delete_product.MockProductService) {},
 			wantStatus:   http.StatusBadRequest,
-			wantBody:     "invalid UUID length: 3",
+			wantBody:     "Invalid format for parameter pvzId: error unmarshaling '123' text as *uuid.UUID: invalid UUID length: 3",
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
 			e := echo.New()
-			e.Validator = validator.NewCustomValidator()
 			req := httptest.NewRequest(http.MethodPost, "/", nil)
 			rec := httptest.NewRecorder()
 			ctx := e.NewContext(req, rec)
+			ctx.SetRequest(req.WithContext(middleware.NewUserContext(req.Context(), &auth.TokenClaims{UserID: actorID, Role: entity.RoleEmployee})))
 
 			ctx.SetParamNames("pvzId")
 			ctx.SetParamValues(tc.pointID)
@@ -101,9 +106,9 @@ func TestHandle(t *testing.T) {
 			MockService := mock_delete_product.NewMockProductService(ctrl)
 			tc.mockBehavior(MockService)
 
-			handler := delete_product.New(MockService)
+			server := &dto.ServerInterfaceWrapper{Handler: dto.NewStrictHandler(api.StrictServer{PostPvzPvzIdDeleteLastProductHandler: delete_product.New(MockService)}, nil)}
 
-			err := handler.Handle(ctx)
+			err := server.PostPvzPvzIdDeleteLastProduct(ctx)
 
 			if tc.wantStatus >= 400 {
 				require.Error(t, err)
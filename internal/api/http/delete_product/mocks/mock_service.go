@@ -42,15 +42,15 @@ func (m *MockProductService) EXPECT() *MockProductServiceMockRecorder {
 }
 
 // DeleteLastProductFromReception mocks base method.
-func (m *MockProductService) DeleteLastProductFromReception(ctx context.Context, pointID uuid.UUID) error {
+func (m *MockProductService) DeleteLastProductFromReception(ctx context.Context, actorID, pointID uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteLastProductFromReception", ctx, pointID)
+	ret := m.ctrl.Call(m, "DeleteLastProductFromReception", ctx, actorID, pointID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteLastProductFromReception indicates an expected call of DeleteLastProductFromReception.
-func (mr *MockProductServiceMockRecorder) DeleteLastProductFromReception(ctx, pointID any) *gomock.Call {
+func (mr *MockProductServiceMockRecorder) DeleteLastProductFromReception(ctx, actorID, pointID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLastProductFromReception", reflect.TypeOf((*MockProductService)(nil).DeleteLastProductFromReception), ctx, pointID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLastProductFromReception", reflect.TypeOf((*MockProductService)(nil).DeleteLastProductFromReception), ctx, actorID, pointID)
 }
@@ -0,0 +1,13 @@
+package delete_session
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type UserService interface {
+	RevokeSession(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) error
+}
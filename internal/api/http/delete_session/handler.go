@@ -0,0 +1,42 @@
+package delete_session
+
+import (
+	"errors"
+	"net/http"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/service/user"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s UserService
+}
+
+func New(userService UserService) api.Handler {
+	return decorator.NewBindAndValidateDerocator(&handler{s: userService})
+}
+
+type Request struct {
+	SessionID uuid.UUID `param:"sessionId" validate:"required"`
+}
+
+func (h *handler) Handle(ctx echo.Context, in Request) error {
+	claims, err := middleware.GetUserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = h.s.RevokeSession(ctx.Request().Context(), claims.UserID, in.SessionID)
+
+	if err != nil {
+		if errors.Is(err, user.ErrNoSessionFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return ctx.NoContent(http.StatusOK)
+}
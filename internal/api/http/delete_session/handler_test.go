@@ -0,0 +1,96 @@
+package delete_session_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/delete_session"
+	mock_delete_session "github.com/4udiwe/avito-pvz/internal/api/http/delete_session/mocks"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/service/user"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		userID       = uuid.New()
+		sessionID    = uuid.New()
+	)
+
+	type MockBehavior func(s *mock_delete_session.MockUserService)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name: "success",
+			mockBehavior: func(s *mock_delete_session.MockUserService) {
+				s.EXPECT().RevokeSession(gomock.Any(), userID, sessionID).Return(nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "no session found",
+			mockBehavior: func(s *mock_delete_session.MockUserService) {
+				s.EXPECT().RevokeSession(gomock.Any(), userID, sessionID).Return(user.ErrNoSessionFound).Times(1)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   user.ErrNoSessionFound.Error(),
+		},
+		{
+			name: "internal error",
+			mockBehavior: func(s *mock_delete_session.MockUserService) {
+				s.EXPECT().RevokeSession(gomock.Any(), userID, sessionID).Return(arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+			req := httptest.NewRequest(http.MethodDelete, "/", nil)
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctx.SetParamNames("sessionId")
+			ctx.SetParamValues(sessionID.String())
+			ctx.Set(middleware.USER_CLAIMS_KEY, &auth.TokenClaims{UserID: userID, Role: entity.RoleEmployee})
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_delete_session.NewMockUserService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := delete_session.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package errorhandler
+
+import (
+	"net/http"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/service/audit"
+	"github.com/4udiwe/avito-pvz/internal/service/cell"
+	"github.com/4udiwe/avito-pvz/internal/service/order"
+	"github.com/4udiwe/avito-pvz/internal/service/point"
+	"github.com/4udiwe/avito-pvz/internal/service/product"
+	"github.com/4udiwe/avito-pvz/internal/service/reception"
+	"github.com/4udiwe/avito-pvz/internal/service/service_account"
+	"github.com/4udiwe/avito-pvz/internal/service/transfer"
+	"github.com/4udiwe/avito-pvz/internal/service/user"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// domainError maps a service error to its machine-readable code and the
+// status used when a handler returns the error as is. Handlers choosing
+// another status keep the code.
+type domainError struct {
+	err    error
+	status int
+	code   string
+}
+
+// Codes are part of the API: clients match on them, so never change one.
+// The first match wins, so more specific errors come first.
+var domainErrors = []domainError{
+	// Points
+	{point.ErrNoCityFound, http.StatusBadRequest, "city_not_found"},
+	{point.ErrNoPointFound, http.StatusNotFound, "point_not_found"},
+	{cell.ErrNoPointFound, http.StatusNotFound, "point_not_found"},
+	{order.ErrNoPointFound, http.StatusNotFound, "point_not_found"},
+	{product.ErrNoPointFound, http.StatusNotFound, "point_not_found"},
+	{reception.ErrNoPointFound, http.StatusNotFound, "point_not_found"},
+	{transfer.ErrNoPointFound, http.StatusNotFound, "point_not_found"},
+	{user.ErrNoPointFound, http.StatusNotFound, "point_not_found"},
+
+	// Receptions
+	{reception.ErrNoReceptionFound, http.StatusNotFound, "reception_not_found"},
+	{product.ErrNoReceptionFound, http.StatusNotFound, "reception_not_found"},
+	{reception.ErrLastReceptionNotClosed, http.StatusBadRequest, "reception_not_closed"},
+	{product.ErrReceptionNotClosed, http.StatusBadRequest, "reception_not_closed"},
+	{reception.ErrLastReceptionAlreadyClosed, http.StatusBadRequest, "reception_already_closed"},
+	{product.ErrReceptionAlreadyClosed, http.StatusBadRequest, "reception_already_closed"},
+	{reception.ErrCannotCloseEmptyReception, http.StatusBadRequest, "reception_empty"},
+
+	// Products
+	{product.ErrNoProductFound, http.StatusNotFound, "product_not_found"},
+	{cell.ErrNoProductFound, http.StatusNotFound, "product_not_found"},
+	{product.ErrInvalidTransition, http.StatusConflict, "invalid_status_transition"},
+	{product.ErrReasonRequired, http.StatusBadRequest, "reason_required"},
+	{product.ErrBarcodeAlreadyExists, http.StatusConflict, "barcode_already_exists"},
+
+	// Storage cells
+	{cell.ErrNoCellFound, http.StatusNotFound, "cell_not_found"},
+	{cell.ErrCellAlreadyExists, http.StatusConflict, "cell_already_exists"},
+	{cell.ErrProductNotPresent, http.StatusConflict, "product_not_present"},
+	{cell.ErrCellWrongPoint, http.StatusBadRequest, "cell_wrong_point"},
+	{cell.ErrCellIncompatible, http.StatusBadRequest, "cell_incompatible"},
+	{cell.ErrCellFull, http.StatusConflict, "cell_full"},
+	{cell.ErrNoFreeCell, http.StatusConflict, "no_free_cell"},
+	{cell.ErrProductNotPlaced, http.StatusNotFound, "product_not_placed"},
+
+	// Orders
+	{order.ErrNoOrderFound, http.StatusNotFound, "order_not_found"},
+	{order.ErrOrderAlreadyExists, http.StatusConflict, "order_already_exists"},
+	{order.ErrProductsUnavailable, http.StatusConflict, "products_unavailable"},
+	{order.ErrProductAlreadyInOrder, http.StatusConflict, "product_already_in_order"},
+	{order.ErrProductsNotStored, http.StatusConflict, "products_not_stored"},
+	{order.ErrOrderNotAssembling, http.StatusConflict, "order_not_assembling"},
+	{order.ErrOrderNotReady, http.StatusConflict, "order_not_ready"},
+	{order.ErrInvalidPickupCode, http.StatusForbidden, "invalid_pickup_code"},
+	{order.ErrPickupCodeExpired, http.StatusForbidden, "pickup_code_expired"},
+	{order.ErrTooManyAttempts, http.StatusTooManyRequests, "too_many_attempts"},
+	{order.ErrPickupCodeNotSent, http.StatusFailedDependency, "pickup_code_not_sent"},
+
+	// Transfers
+	{transfer.ErrNoTransferFound, http.StatusNotFound, "transfer_not_found"},
+	{transfer.ErrSamePoint, http.StatusBadRequest, "same_point"},
+	{transfer.ErrProductsUnavailable, http.StatusConflict, "products_unavailable"},
+	{transfer.ErrTransferNotCreated, http.StatusConflict, "transfer_already_dispatched"},
+	{transfer.ErrTransferNotDispatched, http.StatusConflict, "transfer_not_dispatched"},
+	{transfer.ErrNoTransferReception, http.StatusConflict, "no_transfer_reception"},
+	{transfer.ErrUnknownProduct, http.StatusBadRequest, "unknown_product"},
+
+	// Users
+	{user.ErrNoUserFound, http.StatusNotFound, "user_not_found"},
+	{user.ErrUserAlreadyExists, http.StatusConflict, "user_already_exists"},
+	{user.ErrInvalidCredentials, http.StatusUnauthorized, "invalid_credentials"},
+	{user.ErrInvalidRefreshToken, http.StatusUnauthorized, "invalid_refresh_token"},
+	{user.ErrNoSessionFound, http.StatusNotFound, "session_not_found"},
+	{user.ErrInvalidResetToken, http.StatusBadRequest, "invalid_reset_token"},
+	{user.ErrUserDisabled, http.StatusForbidden, "user_disabled"},
+	{user.ErrCannotModifySelf, http.StatusConflict, "cannot_modify_self"},
+	{user.ErrRegistrationClosed, http.StatusForbidden, "registration_closed"},
+	{user.ErrInvalidInvitation, http.StatusBadRequest, "invalid_invitation"},
+	{user.ErrUnknownRole, http.StatusBadRequest, "unknown_role"},
+	{user.ErrOIDCDisabled, http.StatusNotFound, "oidc_disabled"},
+	{user.ErrInvalidOIDCState, http.StatusBadRequest, "invalid_oidc_state"},
+	{user.ErrOIDCLoginFailed, http.StatusUnauthorized, "oidc_login_failed"},
+	{user.ErrNoRoleMapped, http.StatusForbidden, "no_role_mapped"},
+	{user.ErrInvalidMFAToken, http.StatusUnauthorized, "invalid_mfa_token"},
+	{user.ErrInvalidMFACode, http.StatusUnauthorized, "invalid_mfa_code"},
+	{user.ErrMFANotEnrolled, http.StatusForbidden, "mfa_not_enrolled"},
+	{user.ErrMFARequired, http.StatusForbidden, "mfa_required"},
+	{user.ErrNoMFAEnrollment, http.StatusNotFound, "mfa_enrollment_not_found"},
+	{user.ErrNoMFAFound, http.StatusNotFound, "mfa_not_enabled"},
+	{user.ErrMFAEnabled, http.StatusConflict, "mfa_already_enabled"},
+
+	// Service accounts
+	{service_account.ErrNoServiceAccountFound, http.StatusNotFound, "service_account_not_found"},
+	{service_account.ErrServiceAccountAlreadyExists, http.StatusConflict, "service_account_already_exists"},
+	{service_account.ErrNoAPIKeyFound, http.StatusNotFound, "api_key_not_found"},
+	{service_account.ErrAPIKeyInactive, http.StatusConflict, "api_key_inactive"},
+	{service_account.ErrUnknownScope, http.StatusBadRequest, "unknown_scope"},
+	{service_account.ErrScopeNotGrantable, http.StatusBadRequest, "scope_not_grantable"},
+	{service_account.ErrInvalidExpiry, http.StatusBadRequest, "invalid_expiry"},
+	{service_account.ErrInvalidAPIKey, http.StatusUnauthorized, "invalid_api_key"},
+
+	// Audit
+	{audit.ErrInvalidPeriod, http.StatusBadRequest, "invalid_period"},
+
+	// Idempotency keys
+	{middleware.ErrIdempotencyKeyReused, http.StatusUnprocessableEntity, "idempotency_key_reused"},
+	{middleware.ErrInvalidIdempotencyKey, http.StatusBadRequest, "invalid_idempotency_key"},
+
+	// Access tokens
+	{auth.ErrExpiredToken, http.StatusUnauthorized, "token_expired"},
+	{jwt.ErrTokenExpired, http.StatusUnauthorized, "token_expired"},
+	{auth.ErrInvalidAccessToken, http.StatusUnauthorized, "invalid_access_token"},
+	{auth.ErrRevokedToken, http.StatusUnauthorized, "token_revoked"},
+	{auth.ErrDisabledUser, http.StatusUnauthorized, "user_disabled"},
+}
@@ -0,0 +1,16 @@
+package errorhandler
+
+type DomainError struct {
+	Err    error
+	Status int
+	Code   string
+}
+
+// DomainErrors lists the registered errors, so tests can cover each one.
+func DomainErrors() []DomainError {
+	out := make([]DomainError, 0, len(domainErrors))
+	for _, d := range domainErrors {
+		out = append(out, DomainError{Err: d.err, Status: d.status, Code: d.code})
+	}
+	return out
+}
@@ -0,0 +1,95 @@
+package errorhandler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/labstack/echo/v4"
+)
+
+// Handle is the echo HTTPErrorHandler: every error response is a dto.Error.
+// Domain errors get their own code, other client errors one derived from
+// the status. Server errors are logged and answered with the status text
+// only, so no database or other internal detail reaches the client.
+func Handle(err error, c echo.Context) {
+	req := c.Request()
+
+	if c.Response().Committed {
+		// A streamed response cannot change its status any more
+		logger.FromContext(req.Context()).Errorf("HTTP %s %s failed after the response started: %v", req.Method, c.Path(), err)
+		return
+	}
+
+	status, body := Resolve(err)
+	if status >= http.StatusInternalServerError {
+		logger.FromContext(req.Context()).Errorf("HTTP %s %s failed: %v", req.Method, c.Path(), err)
+	}
+
+	if req.Method == http.MethodHead {
+		err = c.NoContent(status)
+	} else {
+		err = c.JSON(status, body)
+	}
+	if err != nil {
+		logger.FromContext(req.Context()).Errorf("Failed to write error response: %v", err)
+	}
+}
+
+// Resolve returns the status and body of the response to err. A status set
+// by a handler with echo.HTTPError wins over the default of a domain error,
+// which the handler attaches with SetInternal.
+func Resolve(err error) (int, dto.Error) {
+	known, isDomain := lookup(err)
+
+	var httpErr *echo.HTTPError
+	isHTTP := errors.As(err, &httpErr)
+
+	status := http.StatusInternalServerError
+	switch {
+	case isHTTP:
+		status = httpErr.Code
+	case isDomain:
+		status = known.status
+	}
+
+	switch {
+	case status >= http.StatusInternalServerError:
+		return status, dto.Error{Message: http.StatusText(status), Code: statusCode(status)}
+	case isDomain:
+		return status, dto.Error{Message: known.err.Error(), Code: known.code}
+	default:
+		return status, dto.Error{Message: message(httpErr), Code: statusCode(status)}
+	}
+}
+
+func lookup(err error) (domainError, bool) {
+	for _, d := range domainErrors {
+		if errors.Is(err, d.err) {
+			return d, true
+		}
+	}
+	return domainError{}, false
+}
+
+func message(httpErr *echo.HTTPError) string {
+	switch m := httpErr.Message.(type) {
+	case string:
+		return m
+	case error:
+		return m.Error()
+	default:
+		return http.StatusText(httpErr.Code)
+	}
+}
+
+// statusCode turns the status text into a code: 404 is not_found.
+func statusCode(status int) string {
+	text := http.StatusText(status)
+	if text == "" {
+		return "error"
+	}
+	return strings.NewReplacer(" ", "_", "-", "_", "'", "").Replace(strings.ToLower(text))
+}
@@ -0,0 +1,357 @@
+package errorhandler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/errorhandler"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/service/point"
+	"github.com/4udiwe/avito-pvz/internal/service/service_account"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// internalDetail stands for what must never reach a client
+const internalDetail = `ERROR: duplicate key value violates unique constraint "pvz_pkey" (SQLSTATE 23505)`
+
+// errorSchema is the Error schema of the API description
+func errorSchema(t *testing.T) *openapi3.Schema {
+	t.Helper()
+
+	doc, err := openapi3.NewLoader().LoadFromFile("../../../../api/swagger.yaml")
+	require.NoError(t, err)
+
+	ref, ok := doc.Components.Schemas["Error"]
+	require.True(t, ok, "swagger has no Error schema")
+	return ref.Value
+}
+
+// checkContract asserts that rec holds an Error with the wanted status and
+// code, and returns its message
+func checkContract(t *testing.T, schema *openapi3.Schema, rec *httptest.ResponseRecorder, wantStatus int, wantCode string) string {
+	t.Helper()
+
+	assert.Equal(t, wantStatus, rec.Code)
+	assert.Equal(t, echo.MIMEApplicationJSON, rec.Header().Get(echo.HeaderContentType))
+
+	var body any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body), rec.Body.String())
+	require.NoError(t, schema.VisitJSON(body), rec.Body.String())
+
+	fields := body.(map[string]any)
+	assert.Equal(t, wantCode, fields["code"])
+	assert.NotContains(t, rec.Body.String(), "SQLSTATE")
+	return fields["message"].(string)
+}
+
+func handle(err error, method string) *httptest.ResponseRecorder {
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	errorhandler.Handle(err, e.NewContext(httptest.NewRequest(method, "/", nil), rec))
+	return rec
+}
+
+func TestHandleDomainErrors(t *testing.T) {
+	schema := errorSchema(t)
+
+	for _, d := range errorhandler.DomainErrors() {
+		t.Run(d.Code+"/"+d.Err.Error(), func(t *testing.T) {
+			// Returned as is
+			rec := handle(d.Err, http.MethodPost)
+			assert.Equal(t, d.Err.Error(), checkContract(t, schema, rec, d.Status, d.Code))
+
+			// Wrapped by a repository with internal details
+			rec = handle(fmt.Errorf("%s: %w", internalDetail, d.Err), http.MethodPost)
+			assert.Equal(t, d.Err.Error(), checkContract(t, schema, rec, d.Status, d.Code))
+
+			// Mapped by a handler to its own status
+			rec = handle(echo.NewHTTPError(http.StatusTeapot, d.Err.Error()).SetInternal(d.Err), http.MethodPost)
+			assert.Equal(t, d.Err.Error(), checkContract(t, schema, rec, http.StatusTeapot, d.Code))
+
+			// Mapped to a server error
+			rec = handle(echo.NewHTTPError(http.StatusInternalServerError, d.Err.Error()).SetInternal(d.Err), http.MethodPost)
+			assert.Equal(t, "Internal Server Error", checkContract(t, schema, rec, http.StatusInternalServerError, "internal_server_error"))
+		})
+	}
+}
+
+func TestHandleOtherErrors(t *testing.T) {
+	schema := errorSchema(t)
+	unknown := errors.New(internalDetail)
+
+	for _, tc := range []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantCode    string
+		wantMessage string
+	}{
+		{
+			name:        "unknown error",
+			err:         unknown,
+			wantStatus:  http.StatusInternalServerError,
+			wantCode:    "internal_server_error",
+			wantMessage: "Internal Server Error",
+		},
+		{
+			name:        "internal error with details",
+			err:         echo.NewHTTPError(http.StatusInternalServerError, internalDetail).SetInternal(unknown),
+			wantStatus:  http.StatusInternalServerError,
+			wantCode:    "internal_server_error",
+			wantMessage: "Internal Server Error",
+		},
+		{
+			name:        "bad gateway",
+			err:         echo.NewHTTPError(http.StatusBadGateway, "discovery failed: "+internalDetail),
+			wantStatus:  http.StatusBadGateway,
+			wantCode:    "bad_gateway",
+			wantMessage: "Bad Gateway",
+		},
+		{
+			name:        "validation error",
+			err:         echo.NewHTTPError(http.StatusBadRequest, "field city is required"),
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    "bad_request",
+			wantMessage: "field city is required",
+		},
+		{
+			name:        "error message",
+			err:         echo.NewHTTPError(http.StatusBadRequest, errors.New("invalid body")),
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    "bad_request",
+			wantMessage: "invalid body",
+		},
+		{
+			name:        "route not found",
+			err:         echo.ErrNotFound,
+			wantStatus:  http.StatusNotFound,
+			wantCode:    "not_found",
+			wantMessage: "Not Found",
+		},
+		{
+			name:        "method not allowed",
+			err:         echo.ErrMethodNotAllowed,
+			wantStatus:  http.StatusMethodNotAllowed,
+			wantCode:    "method_not_allowed",
+			wantMessage: "Method Not Allowed",
+		},
+		{
+			name:        "too many requests",
+			err:         echo.NewHTTPError(http.StatusTooManyRequests),
+			wantStatus:  http.StatusTooManyRequests,
+			wantCode:    "too_many_requests",
+			wantMessage: "Too Many Requests",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := handle(tc.err, http.MethodGet)
+			assert.Equal(t, tc.wantMessage, checkContract(t, schema, rec, tc.wantStatus, tc.wantCode))
+		})
+	}
+}
+
+func TestHandleHead(t *testing.T) {
+	rec := handle(echo.ErrNotFound, http.MethodHead)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Empty(t, rec.Body.String())
+}
+
+func TestHandleCommitted(t *testing.T) {
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	require.NoError(t, ctx.String(http.StatusOK, "partial"))
+
+	errorhandler.Handle(errors.New(internalDetail), ctx)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "partial", rec.Body.String())
+}
+
+type fakeAuth struct {
+	claims *auth.TokenClaims
+	err    error
+}
+
+func (f fakeAuth) ValidateAccessToken(string) (*auth.TokenClaims, error) { return f.claims, f.err }
+
+type fakeRevocations bool
+
+func (f fakeRevocations) IsRevoked(string) bool { return bool(f) }
+
+type fakeDisabled bool
+
+func (f fakeDisabled) IsDisabled(uuid.UUID) bool { return bool(f) }
+
+type fakeAPIKeys struct{ err error }
+
+func (f fakeAPIKeys) AuthenticateAPIKey(context.Context, string) (*auth.TokenClaims, error) {
+	return &auth.TokenClaims{APIKeyID: uuid.New()}, f.err
+}
+
+type fakePermissions bool
+
+func (f fakePermissions) Allows(entity.UserRole, entity.Permission) bool { return bool(f) }
+
+// TestMiddlewareErrors sends requests through an echo instance set up like
+// the router, so the error bodies of the middleware are checked too.
+func TestMiddlewareErrors(t *testing.T) {
+	schema := errorSchema(t)
+	employee := &auth.TokenClaims{UserID: uuid.New(), Role: entity.RoleEmployee}
+
+	for _, tc := range []struct {
+		name        string
+		auth        fakeAuth
+		revoked     bool
+		disabled    bool
+		apiKeyErr   error
+		allowed     bool
+		method      string
+		header      map[string]string
+		wantStatus  int
+		wantCode    string
+		wantMessage string
+	}{
+		{
+			name:        "no authorization header",
+			wantStatus:  http.StatusUnauthorized,
+			wantCode:    "unauthorized",
+			wantMessage: "Authorization header required",
+		},
+		{
+			name:        "malformed authorization header",
+			header:      map[string]string{echo.HeaderAuthorization: "Token abc"},
+			wantStatus:  http.StatusUnauthorized,
+			wantCode:    "unauthorized",
+			wantMessage: "Invalid authorization header format",
+		},
+		{
+			name:        "invalid access token",
+			auth:        fakeAuth{err: errors.New("token contains an invalid number of segments")},
+			header:      map[string]string{echo.HeaderAuthorization: "Bearer abc"},
+			wantStatus:  http.StatusUnauthorized,
+			wantCode:    "invalid_access_token",
+			wantMessage: auth.ErrInvalidAccessToken.Error(),
+		},
+		{
+			name:        "expired access token",
+			auth:        fakeAuth{err: &jwt.ValidationError{Errors: jwt.ValidationErrorExpired, Inner: jwt.ErrTokenExpired}},
+			header:      map[string]string{echo.HeaderAuthorization: "Bearer abc"},
+			wantStatus:  http.StatusUnauthorized,
+			wantCode:    "token_expired",
+			wantMessage: jwt.ErrTokenExpired.Error(),
+		},
+		{
+			name:        "revoked access token",
+			auth:        fakeAuth{claims: employee},
+			revoked:     true,
+			header:      map[string]string{echo.HeaderAuthorization: "Bearer abc"},
+			wantStatus:  http.StatusUnauthorized,
+			wantCode:    "token_revoked",
+			wantMessage: auth.ErrRevokedToken.Error(),
+		},
+		{
+			name:        "disabled user",
+			auth:        fakeAuth{claims: employee},
+			disabled:    true,
+			header:      map[string]string{echo.HeaderAuthorization: "Bearer abc"},
+			wantStatus:  http.StatusUnauthorized,
+			wantCode:    "user_disabled",
+			wantMessage: auth.ErrDisabledUser.Error(),
+		},
+		{
+			name:        "invalid api key",
+			apiKeyErr:   service_account.ErrInvalidAPIKey,
+			header:      map[string]string{middleware.API_KEY_HEADER: "pvz_abc"},
+			wantStatus:  http.StatusUnauthorized,
+			wantCode:    "invalid_api_key",
+			wantMessage: service_account.ErrInvalidAPIKey.Error(),
+		},
+		{
+			name:        "api key lookup failed",
+			apiKeyErr:   errors.New(internalDetail),
+			header:      map[string]string{middleware.API_KEY_HEADER: "pvz_abc"},
+			wantStatus:  http.StatusUnauthorized,
+			wantCode:    "unauthorized",
+			wantMessage: "Invalid API key",
+		},
+		{
+			name:        "permission denied",
+			auth:        fakeAuth{claims: employee},
+			header:      map[string]string{echo.HeaderAuthorization: "Bearer abc"},
+			wantStatus:  http.StatusForbidden,
+			wantCode:    "forbidden",
+			wantMessage: "Access denied",
+		},
+		{
+			name:        "service account on a users only route",
+			allowed:     true,
+			method:      http.MethodPost,
+			header:      map[string]string{middleware.API_KEY_HEADER: "pvz_abc"},
+			wantStatus:  http.StatusForbidden,
+			wantCode:    "forbidden",
+			wantMessage: "Access denied",
+		},
+		{
+			name:        "handler domain error",
+			auth:        fakeAuth{claims: employee},
+			allowed:     true,
+			header:      map[string]string{echo.HeaderAuthorization: "Bearer abc"},
+			wantStatus:  http.StatusNotFound,
+			wantCode:    "point_not_found",
+			wantMessage: point.ErrNoPointFound.Error(),
+		},
+		{
+			name:        "unknown route",
+			method:      http.MethodDelete,
+			wantStatus:  http.StatusMethodNotAllowed,
+			wantCode:    "method_not_allowed",
+			wantMessage: "Method Not Allowed",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			authMW := middleware.New(tc.auth, fakeRevocations(tc.revoked), fakeDisabled(tc.disabled), fakeAPIKeys{tc.apiKeyErr})
+			can := middleware.NewPermissionMiddleware(fakePermissions(tc.allowed)).Require
+
+			e := echo.New()
+			e.HTTPErrorHandler = errorhandler.Handle
+			e.GET("/pvz", func(c echo.Context) error {
+				return echo.NewHTTPError(http.StatusNotFound, point.ErrNoPointFound.Error()).SetInternal(point.ErrNoPointFound)
+			}, authMW.Middleware, can(entity.PermissionPointRead))
+			e.POST("/pvz", func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			}, authMW.Middleware, middleware.UsersOnly)
+
+			method := tc.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req := httptest.NewRequest(method, "/pvz", nil)
+			for k, v := range tc.header {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.wantMessage, checkContract(t, schema, rec, tc.wantStatus, tc.wantCode))
+		})
+	}
+}
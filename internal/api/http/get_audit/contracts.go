@@ -0,0 +1,13 @@
+package get_audit
+
+import (
+	"context"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type AuditService interface {
+	List(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditRecord, error)
+}
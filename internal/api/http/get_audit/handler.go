@@ -0,0 +1,72 @@
+package get_audit
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	service "github.com/4udiwe/avito-pvz/internal/service/audit"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/lo"
+)
+
+type handler struct {
+	s AuditService
+}
+
+func New(auditService AuditService) api.Handler {
+	return decorator.NewBindAndValidateDerocator(&handler{s: auditService})
+}
+
+type Request struct {
+	ActorID    *uuid.UUID `query:"actorId"`
+	Action     string     `query:"action" validate:"max=64"`
+	TargetType string     `query:"targetType" validate:"max=32"`
+	TargetID   *uuid.UUID `query:"targetId"`
+	RequestID  string     `query:"requestId" validate:"max=128"`
+	From       *time.Time `query:"from"`
+	To         *time.Time `query:"to"`
+	Page       int        `query:"page" validate:"omitempty,min=1"`
+	Limit      int        `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+type Response struct {
+	Records []dto.AuditRecord `json:"records"`
+}
+
+func (h *handler) Handle(ctx echo.Context, in Request) error {
+	filter := entity.AuditFilter{
+		ActorID:   in.ActorID,
+		TargetID:  in.TargetID,
+		RequestID: in.RequestID,
+		From:      in.From,
+		To:        in.To,
+		Page:      in.Page,
+		Limit:     in.Limit,
+	}
+	if in.Action != "" {
+		filter.Action = lo.ToPtr(entity.AuditAction(in.Action))
+	}
+	if in.TargetType != "" {
+		filter.TargetType = lo.ToPtr(entity.AuditTarget(in.TargetType))
+	}
+
+	records, err := h.s.List(ctx.Request().Context(), filter)
+
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidPeriod) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return ctx.JSON(http.StatusOK, Response{
+		Records: lo.Map(records, func(r entity.AuditRecord, _ int) dto.AuditRecord {
+			return *dto.EntityAuditRecordToDTO(&r)
+		}),
+	})
+}
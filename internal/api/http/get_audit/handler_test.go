@@ -0,0 +1,138 @@
+package get_audit_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_audit"
+	mock_get_audit "github.com/4udiwe/avito-pvz/internal/api/http/get_audit/mocks"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	service "github.com/4udiwe/avito-pvz/internal/service/audit"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		actorID      = uuid.New()
+		targetID     = uuid.New()
+		from         = time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+		to           = time.Date(2025, 12, 2, 0, 0, 0, 0, time.UTC)
+		records      = []entity.AuditRecord{{
+			ID:         uuid.New(),
+			ActorID:    actorID,
+			Action:     entity.AuditActionReceptionClosed,
+			TargetType: entity.AuditTargetReception,
+			TargetID:   targetID,
+			Before:     json.RawMessage(`{"status":"in_progress"}`),
+			After:      json.RawMessage(`{"status":"close"}`),
+			RequestID:  "req-1",
+			CreatedAt:  from,
+		}}
+	)
+
+	responseJSON, _ := json.Marshal(get_audit.Response{
+		Records: []dto.AuditRecord{*dto.EntityAuditRecordToDTO(&records[0])},
+	})
+
+	type MockBehavior func(s *mock_get_audit.MockAuditService)
+
+	for _, tc := range []struct {
+		name         string
+		query        string
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name: "success with filters",
+			query: "actorId=" + actorID.String() + "&action=reception.closed&targetType=reception&targetId=" + targetID.String() +
+				"&requestId=req-1&from=2025-12-01T00:00:00Z&to=2025-12-02T00:00:00Z&page=2&limit=10",
+			mockBehavior: func(s *mock_get_audit.MockAuditService) {
+				s.EXPECT().List(gomock.Any(), entity.AuditFilter{
+					ActorID:    &actorID,
+					Action:     lo.ToPtr(entity.AuditActionReceptionClosed),
+					TargetType: lo.ToPtr(entity.AuditTargetReception),
+					TargetID:   &targetID,
+					RequestID:  "req-1",
+					From:       &from,
+					To:         &to,
+					Page:       2,
+					Limit:      10,
+				}).Return(records, nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   string(responseJSON),
+		},
+		{
+			name:  "success without filters",
+			query: "",
+			mockBehavior: func(s *mock_get_audit.MockAuditService) {
+				s.EXPECT().List(gomock.Any(), entity.AuditFilter{}).Return(records, nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   string(responseJSON),
+		},
+		{
+			name:  "invalid period",
+			query: "from=2025-12-02T00:00:00Z&to=2025-12-01T00:00:00Z",
+			mockBehavior: func(s *mock_get_audit.MockAuditService) {
+				s.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, service.ErrInvalidPeriod).Times(1)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   service.ErrInvalidPeriod.Error(),
+		},
+		{
+			name:  "internal error",
+			query: "",
+			mockBehavior: func(s *mock_get_audit.MockAuditService) {
+				s.EXPECT().List(gomock.Any(), entity.AuditFilter{}).Return(nil, arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+			req := httptest.NewRequest(http.MethodGet, "/?"+tc.query, nil)
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_get_audit.NewMockAuditService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := get_audit.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, strings.Trim(rec.Body.String(), "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package get_audit_export
+
+import (
+	"context"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type AuditService interface {
+	Export(ctx context.Context, filter entity.AuditFilter, fn func(entity.AuditRecord) error) error
+}
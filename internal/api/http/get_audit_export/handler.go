@@ -0,0 +1,144 @@
+package get_audit_export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	service "github.com/4udiwe/avito-pvz/internal/service/audit"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/lo"
+)
+
+const (
+	formatCSV   = "csv"
+	formatJSONL = "jsonl"
+)
+
+var csvHeader = []string{"id", "created_at", "actor_id", "action", "target_type", "target_id", "request_id", "before", "after"}
+
+type handler struct {
+	s AuditService
+}
+
+func New(auditService AuditService) api.Handler {
+	return decorator.NewBindAndValidateDerocator(&handler{s: auditService})
+}
+
+type Request struct {
+	Format     string     `query:"format" validate:"omitempty,oneof=csv jsonl"`
+	ActorID    *uuid.UUID `query:"actorId"`
+	Action     string     `query:"action" validate:"max=64"`
+	TargetType string     `query:"targetType" validate:"max=32"`
+	TargetID   *uuid.UUID `query:"targetId"`
+	RequestID  string     `query:"requestId" validate:"max=128"`
+	From       *time.Time `query:"from"`
+	To         *time.Time `query:"to"`
+}
+
+// Handle streams the records as an attachment, CSV by default. The response
+// is started with the first record, so an invalid filter still gets a
+// proper error status.
+func (h *handler) Handle(ctx echo.Context, in Request) error {
+	filter := entity.AuditFilter{
+		ActorID:   in.ActorID,
+		TargetID:  in.TargetID,
+		RequestID: in.RequestID,
+		From:      in.From,
+		To:        in.To,
+	}
+	if in.Action != "" {
+		filter.Action = lo.ToPtr(entity.AuditAction(in.Action))
+	}
+	if in.TargetType != "" {
+		filter.TargetType = lo.ToPtr(entity.AuditTarget(in.TargetType))
+	}
+
+	w := &exportWriter{ctx: ctx, format: lo.CoalesceOrEmpty(in.Format, formatCSV)}
+	err := h.s.Export(ctx.Request().Context(), filter, w.write)
+	if err == nil {
+		err = w.close()
+	}
+
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidPeriod) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return nil
+}
+
+type exportWriter struct {
+	ctx     echo.Context
+	format  string
+	started bool
+	csv     *csv.Writer
+	json    *json.Encoder
+}
+
+func (w *exportWriter) start() error {
+	w.started = true
+
+	resp := w.ctx.Response()
+	resp.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="audit.%s"`, w.format))
+
+	if w.format == formatJSONL {
+		resp.Header().Set(echo.HeaderContentType, "application/x-ndjson")
+		resp.WriteHeader(http.StatusOK)
+		w.json = json.NewEncoder(resp)
+		return nil
+	}
+
+	resp.Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	resp.WriteHeader(http.StatusOK)
+	w.csv = csv.NewWriter(resp)
+	return w.csv.Write(csvHeader)
+}
+
+func (w *exportWriter) write(record entity.AuditRecord) error {
+	if !w.started {
+		if err := w.start(); err != nil {
+			return err
+		}
+	}
+
+	if w.json != nil {
+		return w.json.Encode(dto.EntityAuditRecordToDTO(&record))
+	}
+	return w.csv.Write([]string{
+		record.ID.String(),
+		record.CreatedAt.UTC().Format(time.RFC3339Nano),
+		record.ActorID.String(),
+		string(record.Action),
+		string(record.TargetType),
+		record.TargetID.String(),
+		record.RequestID,
+		string(record.Before),
+		string(record.After),
+	})
+}
+
+// close starts an empty export if there were no records and flushes the
+// CSV buffer.
+func (w *exportWriter) close() error {
+	if !w.started {
+		if err := w.start(); err != nil {
+			return err
+		}
+	}
+
+	if w.csv != nil {
+		w.csv.Flush()
+		return w.csv.Error()
+	}
+	return nil
+}
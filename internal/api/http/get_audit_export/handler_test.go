@@ -0,0 +1,148 @@
+package get_audit_export_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_audit_export"
+	mock_get_audit_export "github.com/4udiwe/avito-pvz/internal/api/http/get_audit_export/mocks"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	service "github.com/4udiwe/avito-pvz/internal/service/audit"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		recordID     = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+		actorID      = uuid.MustParse("00000000-0000-0000-0000-000000000002")
+		targetID     = uuid.MustParse("00000000-0000-0000-0000-000000000003")
+		createdAt    = time.Date(2025, 12, 1, 10, 0, 0, 0, time.UTC)
+		record       = entity.AuditRecord{
+			ID:         recordID,
+			ActorID:    actorID,
+			Action:     entity.AuditActionProductStatusChanged,
+			TargetType: entity.AuditTargetProduct,
+			TargetID:   targetID,
+			Before:     json.RawMessage(`{"status":"received"}`),
+			After:      json.RawMessage(`{"status":"stored"}`),
+			RequestID:  "req-1",
+			CreatedAt:  createdAt,
+		}
+	)
+
+	// stream feeds the record to the handler as the service does
+	stream := func(_ context.Context, _ entity.AuditFilter, fn func(entity.AuditRecord) error) error {
+		return fn(record)
+	}
+
+	type MockBehavior func(s *mock_get_audit_export.MockAuditService)
+
+	for _, tc := range []struct {
+		name            string
+		query           string
+		mockBehavior    MockBehavior
+		wantStatus      int
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:  "csv by default",
+			query: "action=product.status_changed",
+			mockBehavior: func(s *mock_get_audit_export.MockAuditService) {
+				s.EXPECT().Export(gomock.Any(), entity.AuditFilter{
+					Action: lo.ToPtr(entity.AuditActionProductStatusChanged),
+				}, gomock.Any()).DoAndReturn(stream).Times(1)
+			},
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/csv; charset=utf-8",
+			wantBody: "id,created_at,actor_id,action,target_type,target_id,request_id,before,after\n" +
+				recordID.String() + ",2025-12-01T10:00:00Z," + actorID.String() + ",product.status_changed,product," +
+				targetID.String() + `,req-1,"{""status"":""received""}","{""status"":""stored""}"` + "\n",
+		},
+		{
+			name:  "json lines",
+			query: "format=jsonl",
+			mockBehavior: func(s *mock_get_audit_export.MockAuditService) {
+				s.EXPECT().Export(gomock.Any(), entity.AuditFilter{}, gomock.Any()).DoAndReturn(stream).Times(1)
+			},
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/x-ndjson",
+			wantBody: `{"id":"` + recordID.String() + `","actorId":"` + actorID.String() +
+				`","action":"product.status_changed","targetType":"product","targetId":"` + targetID.String() +
+				`","before":{"status":"received"},"after":{"status":"stored"},"requestId":"req-1","createdAt":"2025-12-01T10:00:00Z"}` + "\n",
+		},
+		{
+			name:  "empty csv",
+			query: "format=csv",
+			mockBehavior: func(s *mock_get_audit_export.MockAuditService) {
+				s.EXPECT().Export(gomock.Any(), entity.AuditFilter{}, gomock.Any()).Return(nil).Times(1)
+			},
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/csv; charset=utf-8",
+			wantBody:        "id,created_at,actor_id,action,target_type,target_id,request_id,before,after\n",
+		},
+		{
+			name:  "invalid period",
+			query: "from=2025-12-02T00:00:00Z&to=2025-12-01T00:00:00Z",
+			mockBehavior: func(s *mock_get_audit_export.MockAuditService) {
+				s.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).Return(service.ErrInvalidPeriod).Times(1)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   service.ErrInvalidPeriod.Error(),
+		},
+		{
+			name:  "internal error",
+			query: "",
+			mockBehavior: func(s *mock_get_audit_export.MockAuditService) {
+				s.EXPECT().Export(gomock.Any(), entity.AuditFilter{}, gomock.Any()).Return(arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+			req := httptest.NewRequest(http.MethodGet, "/?"+tc.query, nil)
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_get_audit_export.NewMockAuditService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := get_audit_export.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantContentType, rec.Header().Get(echo.HeaderContentType))
+				assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), "attachment")
+				assert.Equal(t, tc.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
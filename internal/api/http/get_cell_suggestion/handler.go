@@ -0,0 +1,40 @@
+package get_cell_suggestion
+
+import (
+	"errors"
+	"net/http"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	service "github.com/4udiwe/avito-pvz/internal/service/cell"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s CellService
+}
+
+func New(cellService CellService) api.Handler {
+	return decorator.NewBindAndValidateDerocator(&handler{s: cellService})
+}
+
+type Request struct {
+	ProductID uuid.UUID `param:"productId" validate:"required"`
+}
+
+func (h *handler) Handle(ctx echo.Context, in Request) error {
+	cell, err := h.s.SuggestCell(ctx.Request().Context(), in.ProductID)
+
+	if err != nil {
+		if errors.Is(err, service.ErrNoProductFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrNoFreeCell) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return ctx.JSON(http.StatusOK, dto.EntityCellToDTO(&cell))
+}
@@ -0,0 +1,109 @@
+package get_cell_suggestion_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_cell_suggestion"
+	mock_get_cell_suggestion "github.com/4udiwe/avito-pvz/internal/api/http/get_cell_suggestion/mocks"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	service "github.com/4udiwe/avito-pvz/internal/service/cell"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		productID    = uuid.New()
+
+		cell = entity.StorageCell{ID: uuid.New(), PointID: uuid.New(), Rack: "C", Shelf: "3", Bin: "1", Capacity: 8}
+	)
+
+	responseJSON, _ := json.Marshal(dto.EntityCellToDTO(&cell))
+
+	type MockBehavior func(s *mock_get_cell_suggestion.MockCellService)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name: "success",
+			mockBehavior: func(s *mock_get_cell_suggestion.MockCellService) {
+				s.EXPECT().SuggestCell(gomock.Any(), productID).Return(cell, nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   string(responseJSON),
+		},
+		{
+			name: "no product found",
+			mockBehavior: func(s *mock_get_cell_suggestion.MockCellService) {
+				s.EXPECT().SuggestCell(gomock.Any(), productID).Return(entity.StorageCell{}, service.ErrNoProductFound).Times(1)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   service.ErrNoProductFound.Error(),
+		},
+		{
+			name: "no free cell",
+			mockBehavior: func(s *mock_get_cell_suggestion.MockCellService) {
+				s.EXPECT().SuggestCell(gomock.Any(), productID).Return(entity.StorageCell{}, service.ErrNoFreeCell).Times(1)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   service.ErrNoFreeCell.Error(),
+		},
+		{
+			name: "internal error",
+			mockBehavior: func(s *mock_get_cell_suggestion.MockCellService) {
+				s.EXPECT().SuggestCell(gomock.Any(), productID).Return(entity.StorageCell{}, arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctx.SetParamNames("productId")
+			ctx.SetParamValues(productID.String())
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_get_cell_suggestion.NewMockCellService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := get_cell_suggestion.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, strings.Trim(rec.Body.String(), "\n"))
+			}
+		})
+	}
+}
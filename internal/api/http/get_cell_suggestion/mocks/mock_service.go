@@ -0,0 +1,58 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: contracts.go
+//
+// Generated by this command:
+//
+//	mockgen -source=contracts.go -destination=mocks/mock_service.go
+//
+
+// Package mock_get_cell_suggestion is a generated GoMock package.
+package mock_get_cell_suggestion
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/4udiwe/avito-pvz/internal/entity"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCellService is a mock of CellService interface.
+type MockCellService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCellServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockCellServiceMockRecorder is the mock recorder for MockCellService.
+type MockCellServiceMockRecorder struct {
+	mock *MockCellService
+}
+
+// NewMockCellService creates a new mock instance.
+func NewMockCellService(ctrl *gomock.Controller) *MockCellService {
+	mock := &MockCellService{ctrl: ctrl}
+	mock.recorder = &MockCellServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCellService) EXPECT() *MockCellServiceMockRecorder {
+	return m.recorder
+}
+
+// SuggestCell mocks base method.
+func (m *MockCellService) SuggestCell(ctx context.Context, productID uuid.UUID) (entity.StorageCell, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SuggestCell", ctx, productID)
+	ret0, _ := ret[0].(entity.StorageCell)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SuggestCell indicates an expected call of SuggestCell.
+func (mr *MockCellServiceMockRecorder) SuggestCell(ctx, productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuggestCell", reflect.TypeOf((*MockCellService)(nil).SuggestCell), ctx, productID)
+}
@@ -0,0 +1,14 @@
+package get_cells
+
+import (
+	"context"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/google/uuid"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type CellService interface {
+	GetCells(ctx context.Context, pointID uuid.UUID) ([]entity.StorageCell, error)
+}
@@ -0,0 +1,39 @@
+package get_cells
+
+import (
+	"net/http"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/lo"
+)
+
+type handler struct {
+	s CellService
+}
+
+func New(cellService CellService) api.Handler {
+	return decorator.NewBindAndValidateDerocator(&handler{s: cellService})
+}
+
+type Request struct {
+	PointID uuid.UUID `param:"pvzId" validate:"required"`
+}
+
+func (h *handler) Handle(ctx echo.Context, in Request) error {
+	cells, err := h.s.GetCells(ctx.Request().Context(), in.PointID)
+
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return ctx.JSON(
+		http.StatusOK,
+		lo.Map(cells, func(item entity.StorageCell, _ int) dto.StorageCell {
+			return *dto.EntityCellToDTO(&item)
+		}),
+	)
+}
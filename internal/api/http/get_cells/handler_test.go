@@ -0,0 +1,103 @@
+package get_cells_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_cells"
+	mock_get_cells "github.com/4udiwe/avito-pvz/internal/api/http/get_cells/mocks"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		pointID      = uuid.New()
+
+		cells = []entity.StorageCell{
+			{ID: uuid.New(), PointID: pointID, Rack: "A", Shelf: "1", Bin: "1", Capacity: 5, Occupied: 2},
+			{ID: uuid.New(), PointID: pointID, Rack: "A", Shelf: "1", Bin: "2", Capacity: 5},
+		}
+	)
+
+	responseJSON, _ := json.Marshal([]dto.StorageCell{*dto.EntityCellToDTO(&cells[0]), *dto.EntityCellToDTO(&cells[1])})
+
+	type MockBehavior func(s *mock_get_cells.MockCellService)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name: "success",
+			mockBehavior: func(s *mock_get_cells.MockCellService) {
+				s.EXPECT().GetCells(gomock.Any(), pointID).Return(cells, nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   string(responseJSON),
+		},
+		{
+			name: "no cells",
+			mockBehavior: func(s *mock_get_cells.MockCellService) {
+				s.EXPECT().GetCells(gomock.Any(), pointID).Return(nil, nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   "[]",
+		},
+		{
+			name: "internal error",
+			mockBehavior: func(s *mock_get_cells.MockCellService) {
+				s.EXPECT().GetCells(gomock.Any(), pointID).Return(nil, arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctx.SetParamNames("pvzId")
+			ctx.SetParamValues(pointID.String())
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_get_cells.NewMockCellService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := get_cells.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, strings.Trim(rec.Body.String(), "\n"))
+			}
+		})
+	}
+}
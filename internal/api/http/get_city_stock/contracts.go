@@ -0,0 +1,13 @@
+package get_city_stock
+
+import (
+	"context"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type PointService interface {
+	GetCityStock(ctx context.Context, city string) ([]entity.Stock, error)
+}
@@ -0,0 +1,41 @@
+package get_city_stock
+
+import (
+	"errors"
+	"net/http"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	service "github.com/4udiwe/avito-pvz/internal/service/point"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s PointService
+}
+
+func New(pointService PointService) api.Handler {
+	return decorator.NewBindAndValidateDerocator(&handler{s: pointService})
+}
+
+type Request struct {
+	City string `query:"city" validate:"required"`
+}
+
+type Response struct {
+	City  string          `json:"city"`
+	Stock []dto.StockItem `json:"stock"`
+}
+
+func (h *handler) Handle(ctx echo.Context, in Request) error {
+	stock, err := h.s.GetCityStock(ctx.Request().Context(), in.City)
+
+	if err != nil {
+		if errors.Is(err, service.ErrNoCityFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return ctx.JSON(http.StatusOK, Response{City: in.City, Stock: dto.EntityStockToDTO(stock)})
+}
@@ -0,0 +1,100 @@
+package get_city_stock_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_city_stock"
+	mock_get_city_stock "github.com/4udiwe/avito-pvz/internal/api/http/get_city_stock/mocks"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	service "github.com/4udiwe/avito-pvz/internal/service/point"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		city         = "Москва"
+		stock        = []entity.Stock{
+			{Type: entity.ProductTypeElectronics, Amount: 7},
+			{Type: entity.ProductTypeShoes, Amount: 1},
+		}
+	)
+
+	responseJSON, _ := json.Marshal(get_city_stock.Response{City: city, Stock: dto.EntityStockToDTO(stock)})
+
+	type MockBehavior func(s *mock_get_city_stock.MockPointService)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name: "success",
+			mockBehavior: func(s *mock_get_city_stock.MockPointService) {
+				s.EXPECT().GetCityStock(gomock.Any(), city).Return(stock, nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   string(responseJSON),
+		},
+		{
+			name: "no city found",
+			mockBehavior: func(s *mock_get_city_stock.MockPointService) {
+				s.EXPECT().GetCityStock(gomock.Any(), city).Return(nil, service.ErrNoCityFound).Times(1)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   service.ErrNoCityFound.Error(),
+		},
+		{
+			name: "internal error",
+			mockBehavior: func(s *mock_get_city_stock.MockPointService) {
+				s.EXPECT().GetCityStock(gomock.Any(), city).Return(nil, arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+			req := httptest.NewRequest(http.MethodGet, "/?city="+url.QueryEscape(city), nil)
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_get_city_stock.NewMockPointService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := get_city_stock.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, strings.Trim(rec.Body.String(), "\n"))
+			}
+		})
+	}
+}
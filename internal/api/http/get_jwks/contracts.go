@@ -0,0 +1,9 @@
+package get_jwks
+
+import "github.com/4udiwe/avito-pvz/internal/auth"
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type KeyProvider interface {
+	JWKS() auth.JWKS
+}
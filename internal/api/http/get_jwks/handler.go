@@ -0,0 +1,24 @@
+package get_jwks
+
+import (
+	"net/http"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	k KeyProvider
+}
+
+func New(keyProvider KeyProvider) api.Handler {
+	return decorator.NewBindAndValidateDerocator(&handler{k: keyProvider})
+}
+
+type Request struct{}
+
+func (h *handler) Handle(ctx echo.Context, in Request) error {
+	ctx.Response().Header().Set(echo.HeaderCacheControl, "public, max-age=300")
+	return ctx.JSON(http.StatusOK, h.k.JWKS())
+}
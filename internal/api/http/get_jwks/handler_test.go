@@ -0,0 +1,68 @@
+package get_jwks_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_jwks"
+	mock_get_jwks "github.com/4udiwe/avito-pvz/internal/api/http/get_jwks/mocks"
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	jwks := auth.JWKS{Keys: []auth.JWK{
+		{Kty: "OKP", Kid: "2025-10", Use: "sig", Alg: auth.AlgorithmEdDSA, Crv: "Ed25519", X: "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"},
+	}}
+
+	responseJSON, _ := json.Marshal(jwks)
+
+	for _, tc := range []struct {
+		name       string
+		jwks       auth.JWKS
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "published keys",
+			jwks:       jwks,
+			wantStatus: http.StatusOK,
+			wantBody:   string(responseJSON),
+		},
+		{
+			name:       "symmetric keys are not published",
+			jwks:       auth.JWKS{Keys: []auth.JWK{}},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"keys":[]}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctrl := gomock.NewController(t)
+			MockProvider := mock_get_jwks.NewMockKeyProvider(ctrl)
+			MockProvider.EXPECT().JWKS().Return(tc.jwks).Times(1)
+
+			handler := get_jwks.New(MockProvider)
+
+			err := handler.Handle(ctx)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantStatus, rec.Code)
+			assert.Equal(t, tc.wantBody, strings.Trim(rec.Body.String(), "\n"))
+		})
+	}
+}
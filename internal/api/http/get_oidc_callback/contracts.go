@@ -0,0 +1,14 @@
+package get_oidc_callback
+
+import (
+	"context"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/service/user"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type UserService interface {
+	CompleteOIDCLogin(ctx context.Context, code string, state string, client entity.ClientInfo) (*user.LoginResult, error)
+}
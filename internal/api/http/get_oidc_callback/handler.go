@@ -0,0 +1,71 @@
+package get_oidc_callback
+
+import (
+	"errors"
+	"net/http"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/service/user"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s UserService
+}
+
+func New(userService UserService) api.Handler {
+	return decorator.NewBindAndValidateDerocator(&handler{s: userService})
+}
+
+// Request is the redirect from the identity provider: either a code or an
+// error (RFC 6749, 4.1.2).
+type Request struct {
+	Code             string `query:"code" validate:"max=2048"`
+	State            string `query:"state" validate:"required,max=256"`
+	Error            string `query:"error" validate:"max=256"`
+	ErrorDescription string `query:"error_description" validate:"max=1024"`
+}
+
+func (h *handler) Handle(ctx echo.Context, in Request) error {
+	if in.Error != "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "identity provider error: "+in.Error)
+	}
+	if in.Code == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "code is required")
+	}
+
+	result, err := h.s.CompleteOIDCLogin(
+		ctx.Request().Context(),
+		in.Code,
+		in.State,
+		entity.ClientInfo{UserAgent: ctx.Request().UserAgent(), IP: ctx.RealIP()},
+	)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrInvalidOIDCState):
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		case errors.Is(err, user.ErrOIDCLoginFailed):
+			return echo.NewHTTPError(http.StatusUnauthorized, err.Error()).SetInternal(err)
+		case errors.Is(err, user.ErrNoRoleMapped), errors.Is(err, user.ErrUserDisabled):
+			return echo.NewHTTPError(http.StatusForbidden, err.Error()).SetInternal(err)
+		case errors.Is(err, user.ErrUserAlreadyExists):
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		case errors.Is(err, user.ErrOIDCDisabled):
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	// The second factor is finished at POST /login/mfa, as for password logins
+	if result.MFA != nil {
+		return ctx.JSON(http.StatusAccepted, dto.MFAPending{
+			MfaToken:           result.MFA.Token,
+			ExpiresAt:          result.MFA.ExpiresAt,
+			EnrollmentRequired: result.MFA.EnrollmentRequired,
+		})
+	}
+	return ctx.JSON(http.StatusOK, result.Tokens)
+}
@@ -0,0 +1,175 @@
+package get_oidc_callback_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_oidc_callback"
+	mock_get_oidc_callback "github.com/4udiwe/avito-pvz/internal/api/http/get_oidc_callback/mocks"
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/service/user"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		client       = entity.ClientInfo{UserAgent: "browser/1.0", IP: "10.0.0.1"}
+		code         = "authorization-code"
+		state        = "state"
+		query        = url.Values{"code": {code}, "state": {state}}
+		out          = auth.Tokens{AccessToken: "access", RefreshToken: "refresh", ExpiresIn: 900}
+		pending      = user.MFAPending{Token: "mfa-token", ExpiresAt: time.Date(2025, 11, 20, 12, 0, 0, 0, time.UTC), EnrollmentRequired: true}
+	)
+
+	responseJSON, _ := json.Marshal(out)
+	pendingJSON := `{"enrollment_required":true,"expires_at":"2025-11-20T12:00:00Z","mfa_token":"mfa-token"}`
+
+	type MockBehavior func(s *mock_get_oidc_callback.MockUserService)
+
+	for _, tc := range []struct {
+		name         string
+		query        url.Values
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name:  "success",
+			query: query,
+			mockBehavior: func(s *mock_get_oidc_callback.MockUserService) {
+				s.EXPECT().CompleteOIDCLogin(gomock.Any(), code, state, client).Return(&user.LoginResult{Tokens: &out}, nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   string(responseJSON),
+		},
+		{
+			name:  "mfa required",
+			query: query,
+			mockBehavior: func(s *mock_get_oidc_callback.MockUserService) {
+				s.EXPECT().CompleteOIDCLogin(gomock.Any(), code, state, client).Return(&user.LoginResult{MFA: &pending}, nil).Times(1)
+			},
+			wantStatus: http.StatusAccepted,
+			wantBody:   pendingJSON,
+		},
+		{
+			name:         "provider error",
+			query:        url.Values{"error": {"access_denied"}, "state": {state}},
+			mockBehavior: func(s *mock_get_oidc_callback.MockUserService) {},
+			wantStatus:   http.StatusUnauthorized,
+			wantBody:     "identity provider error: access_denied",
+		},
+		{
+			name:         "no code",
+			query:        url.Values{"state": {state}},
+			mockBehavior: func(s *mock_get_oidc_callback.MockUserService) {},
+			wantStatus:   http.StatusBadRequest,
+			wantBody:     "code is required",
+		},
+		{
+			name:         "no state",
+			query:        url.Values{"code": {code}},
+			mockBehavior: func(s *mock_get_oidc_callback.MockUserService) {},
+			wantStatus:   http.StatusBadRequest,
+			wantBody:     "field State is required",
+		},
+		{
+			name:  "invalid state",
+			query: query,
+			mockBehavior: func(s *mock_get_oidc_callback.MockUserService) {
+				s.EXPECT().CompleteOIDCLogin(gomock.Any(), code, state, client).Return(nil, user.ErrInvalidOIDCState).Times(1)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   user.ErrInvalidOIDCState.Error(),
+		},
+		{
+			name:  "login failed",
+			query: query,
+			mockBehavior: func(s *mock_get_oidc_callback.MockUserService) {
+				s.EXPECT().CompleteOIDCLogin(gomock.Any(), code, state, client).Return(nil, user.ErrOIDCLoginFailed).Times(1)
+			},
+			wantStatus: http.StatusUnauthorized,
+			wantBody:   user.ErrOIDCLoginFailed.Error(),
+		},
+		{
+			name:  "no role mapped",
+			query: query,
+			mockBehavior: func(s *mock_get_oidc_callback.MockUserService) {
+				s.EXPECT().CompleteOIDCLogin(gomock.Any(), code, state, client).Return(nil, user.ErrNoRoleMapped).Times(1)
+			},
+			wantStatus: http.StatusForbidden,
+			wantBody:   user.ErrNoRoleMapped.Error(),
+		},
+		{
+			name:  "disabled user",
+			query: query,
+			mockBehavior: func(s *mock_get_oidc_callback.MockUserService) {
+				s.EXPECT().CompleteOIDCLogin(gomock.Any(), code, state, client).Return(nil, user.ErrUserDisabled).Times(1)
+			},
+			wantStatus: http.StatusForbidden,
+			wantBody:   user.ErrUserDisabled.Error(),
+		},
+		{
+			name:  "email taken",
+			query: query,
+			mockBehavior: func(s *mock_get_oidc_callback.MockUserService) {
+				s.EXPECT().CompleteOIDCLogin(gomock.Any(), code, state, client).Return(nil, user.ErrUserAlreadyExists).Times(1)
+			},
+			wantStatus: http.StatusConflict,
+			wantBody:   user.ErrUserAlreadyExists.Error(),
+		},
+		{
+			name:  "internal error",
+			query: query,
+			mockBehavior: func(s *mock_get_oidc_callback.MockUserService) {
+				s.EXPECT().CompleteOIDCLogin(gomock.Any(), code, state, client).Return(nil, arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+			req := httptest.NewRequest(http.MethodGet, "/?"+tc.query.Encode(), nil)
+			req.Header.Set("User-Agent", client.UserAgent)
+			req.Header.Set("X-Real-Ip", client.IP)
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_get_oidc_callback.NewMockUserService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := get_oidc_callback.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, strings.Trim(rec.Body.String(), "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+package get_oidc_login
+
+import "context"
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type UserService interface {
+	BeginOIDCLogin(ctx context.Context) (string, error)
+}
@@ -0,0 +1,38 @@
+package get_oidc_login
+
+import (
+	"errors"
+	"net/http"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/service/user"
+	"github.com/4udiwe/avito-pvz/pkg/oidc"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s UserService
+}
+
+func New(userService UserService) api.Handler {
+	return decorator.NewBindAndValidateDerocator(&handler{s: userService})
+}
+
+type Request struct{}
+
+// Handle redirects the browser to the login page of the identity provider.
+func (h *handler) Handle(ctx echo.Context, in Request) error {
+	url, err := h.s.BeginOIDCLogin(ctx.Request().Context())
+
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrOIDCDisabled):
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		case errors.Is(err, oidc.ErrDiscovery):
+			return echo.NewHTTPError(http.StatusBadGateway, oidc.ErrDiscovery.Error())
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return ctx.Redirect(http.StatusFound, url)
+}
@@ -0,0 +1,97 @@
+package get_oidc_login_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_oidc_login"
+	mock_get_oidc_login "github.com/4udiwe/avito-pvz/internal/api/http/get_oidc_login/mocks"
+	"github.com/4udiwe/avito-pvz/internal/service/user"
+	"github.com/4udiwe/avito-pvz/pkg/oidc"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		loginURL     = "https://idp.example.com/authorize?client_id=pvz&state=abc"
+	)
+
+	type MockBehavior func(s *mock_get_oidc_login.MockUserService)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name: "success",
+			mockBehavior: func(s *mock_get_oidc_login.MockUserService) {
+				s.EXPECT().BeginOIDCLogin(gomock.Any()).Return(loginURL, nil).Times(1)
+			},
+			wantStatus: http.StatusFound,
+		},
+		{
+			name: "disabled",
+			mockBehavior: func(s *mock_get_oidc_login.MockUserService) {
+				s.EXPECT().BeginOIDCLogin(gomock.Any()).Return("", user.ErrOIDCDisabled).Times(1)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   user.ErrOIDCDisabled.Error(),
+		},
+		{
+			name: "provider unavailable",
+			mockBehavior: func(s *mock_get_oidc_login.MockUserService) {
+				s.EXPECT().BeginOIDCLogin(gomock.Any()).Return("", errors.Join(oidc.ErrDiscovery, arbitraryErr)).Times(1)
+			},
+			wantStatus: http.StatusBadGateway,
+			wantBody:   oidc.ErrDiscovery.Error(),
+		},
+		{
+			name: "internal error",
+			mockBehavior: func(s *mock_get_oidc_login.MockUserService) {
+				s.EXPECT().BeginOIDCLogin(gomock.Any()).Return("", arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_get_oidc_login.NewMockUserService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := get_oidc_login.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, loginURL, rec.Header().Get("Location"))
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package get_order
+
+import (
+	"context"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type OrderService interface {
+	GetOrderByNumber(ctx context.Context, number string) (entity.Order, error)
+}
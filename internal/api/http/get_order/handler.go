@@ -0,0 +1,36 @@
+package get_order
+
+import (
+	"errors"
+	"net/http"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	service "github.com/4udiwe/avito-pvz/internal/service/order"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s OrderService
+}
+
+func New(orderService OrderService) api.Handler {
+	return decorator.NewBindAndValidateDerocator(&handler{s: orderService})
+}
+
+type Request struct {
+	Number string `query:"number" validate:"required"`
+}
+
+func (h *handler) Handle(ctx echo.Context, in Request) error {
+	order, err := h.s.GetOrderByNumber(ctx.Request().Context(), in.Number)
+
+	if err != nil {
+		if errors.Is(err, service.ErrNoOrderFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return ctx.JSON(http.StatusOK, dto.EntityOrderToDTO(&order))
+}
@@ -0,0 +1,117 @@
+package get_order_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_order"
+	mock_get_order "github.com/4udiwe/avito-pvz/internal/api/http/get_order/mocks"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	service "github.com/4udiwe/avito-pvz/internal/service/order"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		number       = "ORD-1"
+
+		order = entity.Order{
+			ID:         uuid.New(),
+			PointID:    uuid.New(),
+			Number:     number,
+			Status:     entity.OrderStatusReady,
+			ProductIDs: []uuid.UUID{uuid.New()},
+			CreatedAt:  time.Now(),
+		}
+	)
+
+	responseJSON, _ := json.Marshal(dto.EntityOrderToDTO(&order))
+
+	type MockBehavior func(s *mock_get_order.MockOrderService)
+
+	for _, tc := range []struct {
+		name         string
+		number       string
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name:   "success",
+			number: number,
+			mockBehavior: func(s *mock_get_order.MockOrderService) {
+				s.EXPECT().GetOrderByNumber(gomock.Any(), number).Return(order, nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   string(responseJSON),
+		},
+		{
+			name:   "no order found",
+			number: number,
+			mockBehavior: func(s *mock_get_order.MockOrderService) {
+				s.EXPECT().GetOrderByNumber(gomock.Any(), number).Return(entity.Order{}, service.ErrNoOrderFound).Times(1)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   service.ErrNoOrderFound.Error(),
+		},
+		{
+			name:   "internal error",
+			number: number,
+			mockBehavior: func(s *mock_get_order.MockOrderService) {
+				s.EXPECT().GetOrderByNumber(gomock.Any(), number).Return(entity.Order{}, arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+		{
+			name:         "no number provided",
+			number:       "",
+			mockBehavior: func(s *mock_get_order.MockOrderService) {},
+			wantStatus:   http.StatusBadRequest,
+			wantBody:     "field Number is required",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+			req := httptest.NewRequest(http.MethodGet, "/?number="+tc.number, nil)
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_get_order.NewMockOrderService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := get_order.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, strings.Trim(rec.Body.String(), "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package get_point_stock
+
+import (
+	"errors"
+	"net/http"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	service "github.com/4udiwe/avito-pvz/internal/service/point"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s PointService
+}
+
+func New(pointService PointService) api.Handler {
+	return decorator.NewBindAndValidateDerocator(&handler{s: pointService})
+}
+
+type Request struct {
+	PointID uuid.UUID `param:"pvzId" validate:"required"`
+}
+
+type Response struct {
+	PvzId uuid.UUID       `json:"pvzId"`
+	Stock []dto.StockItem `json:"stock"`
+}
+
+func (h *handler) Handle(ctx echo.Context, in Request) error {
+	stock, err := h.s.GetStock(ctx.Request().Context(), in.PointID)
+
+	if err != nil {
+		if errors.Is(err, service.ErrNoPointFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return ctx.JSON(http.StatusOK, Response{PvzId: in.PointID, Stock: dto.EntityStockToDTO(stock)})
+}
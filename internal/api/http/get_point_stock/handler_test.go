@@ -0,0 +1,103 @@
+package get_point_stock_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_point_stock"
+	mock_get_point_stock "github.com/4udiwe/avito-pvz/internal/api/http/get_point_stock/mocks"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	service "github.com/4udiwe/avito-pvz/internal/service/point"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		pointID      = uuid.New()
+		stock        = []entity.Stock{
+			{PointID: pointID, Type: entity.ProductTypeElectronics, Amount: 2},
+			{PointID: pointID, Type: entity.ProductTypeShoes, Amount: 0},
+		}
+	)
+
+	responseJSON, _ := json.Marshal(get_point_stock.Response{PvzId: pointID, Stock: dto.EntityStockToDTO(stock)})
+
+	type MockBehavior func(s *mock_get_point_stock.MockPointService)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name: "success",
+			mockBehavior: func(s *mock_get_point_stock.MockPointService) {
+				s.EXPECT().GetStock(gomock.Any(), pointID).Return(stock, nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   string(responseJSON),
+		},
+		{
+			name: "no point found",
+			mockBehavior: func(s *mock_get_point_stock.MockPointService) {
+				s.EXPECT().GetStock(gomock.Any(), pointID).Return(nil, service.ErrNoPointFound).Times(1)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   service.ErrNoPointFound.Error(),
+		},
+		{
+			name: "internal error",
+			mockBehavior: func(s *mock_get_point_stock.MockPointService) {
+				s.EXPECT().GetStock(gomock.Any(), pointID).Return(nil, arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctx.SetParamNames("pvzId")
+			ctx.SetParamValues(pointID.String())
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_get_point_stock.NewMockPointService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := get_point_stock.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, strings.Trim(rec.Body.String(), "\n"))
+			}
+		})
+	}
+}
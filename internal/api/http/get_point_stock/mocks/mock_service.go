@@ -0,0 +1,58 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: contracts.go
+//
+// Generated by this command:
+//
+//	mockgen -source=contracts.go -destination=mocks/mock_service.go
+//
+
+// Package mock_get_point_stock is a generated GoMock package.
+package mock_get_point_stock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/4udiwe/avito-pvz/internal/entity"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPointService is a mock of PointService interface.
+type MockPointService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPointServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockPointServiceMockRecorder is the mock recorder for MockPointService.
+type MockPointServiceMockRecorder struct {
+	mock *MockPointService
+}
+
+// NewMockPointService creates a new mock instance.
+func NewMockPointService(ctrl *gomock.Controller) *MockPointService {
+	mock := &MockPointService{ctrl: ctrl}
+	mock.recorder = &MockPointServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPointService) EXPECT() *MockPointServiceMockRecorder {
+	return m.recorder
+}
+
+// GetStock mocks base method.
+func (m *MockPointService) GetStock(ctx context.Context, pointID uuid.UUID) ([]entity.Stock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStock", ctx, pointID)
+	ret0, _ := ret[0].([]entity.Stock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStock indicates an expected call of GetStock.
+func (mr *MockPointServiceMockRecorder) GetStock(ctx, pointID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStock", reflect.TypeOf((*MockPointService)(nil).GetStock), ctx, pointID)
+}
@@ -9,5 +9,5 @@ import (
 //go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
 
 type PointService interface {
-	GetAllPointsFullInfo(ctx context.Context) ([]entity.PointFullInfo, error)
+	GetPointsFullInfo(ctx context.Context, filter entity.PointFilter) ([]entity.PointFullInfo, error)
 }
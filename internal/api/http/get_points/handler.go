@@ -1,10 +1,10 @@
 package get_points
 
 import (
+	"context"
 	"net/http"
 
 	api "github.com/4udiwe/avito-pvz/internal/api/http"
-	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
 	"github.com/4udiwe/avito-pvz/internal/dto"
 	"github.com/4udiwe/avito-pvz/internal/entity"
 	"github.com/labstack/echo/v4"
@@ -15,48 +15,34 @@ type handler struct {
 	s PointService
 }
 
-func New(pointService PointService) api.Handler {
-	return decorator.NewBindAndValidateDerocator(&handler{
+func New(pointService PointService) api.GetPvzHandler {
+	return &handler{
 		s: pointService,
-	})
-}
-
-type Request struct{}
-
-type Response struct {
-	Info []PointWithReceptions
-}
-
-type PointWithReceptions struct {
-	Pvz        dto.PVZ                 `json:"pvz"`
-	Receptions []ReceptionWithProducts `json:"receptions"`
-}
-
-type ReceptionWithProducts struct {
-	Reception dto.Reception `json:"reception"`
-	Products  []dto.Product `json:"products"`
+	}
 }
 
-func (h *handler) Handle(ctx echo.Context, in Request) error {
-	pointsInfo, err := h.s.GetAllPointsFullInfo(ctx.Request().Context())
+func (h *handler) GetPvz(ctx context.Context, request dto.GetPvzRequestObject) (dto.GetPvzResponseObject, error) {
+	pointsInfo, err := h.s.GetPointsFullInfo(ctx, entity.PointFilter{
+		StartDate: request.Params.StartDate,
+		EndDate:   request.Params.EndDate,
+		Page:      lo.FromPtr(request.Params.Page),
+		Limit:     lo.FromPtr(request.Params.Limit),
+	})
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return nil, echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
 	}
-	return ctx.JSON(http.StatusOK,
-		Response{
-			Info: lo.Map(pointsInfo, func(item entity.PointFullInfo, _ int) PointWithReceptions {
-				return PointWithReceptions{
-					Pvz: *dto.EntityPointToDTO(&item.Point),
-					Receptions: lo.Map(item.Receptions, func(e entity.ReceptionWithProducts, _ int) ReceptionWithProducts {
-						return ReceptionWithProducts{
-							Reception: *dto.EntityReceptionToDTO(&e.Reception),
-							Products: lo.Map(e.Products, func(p entity.Product, _ int) dto.Product {
-								return *dto.EntityProductToDTO(&p)
-							}),
-						}
+	return dto.GetPvz200JSONResponse(lo.Map(pointsInfo, func(item entity.PointFullInfo, _ int) dto.PVZWithReceptions {
+		return dto.PVZWithReceptions{
+			Pvz: *dto.EntityPointToDTO(&item.Point),
+			Receptions: lo.Map(item.Receptions, func(e entity.ReceptionWithProducts, _ int) dto.ReceptionWithProducts {
+				return dto.ReceptionWithProducts{
+					Reception: *dto.EntityReceptionToDTO(&e.Reception),
+					Products: lo.Map(e.Products, func(p entity.Product, _ int) dto.Product {
+						return *dto.EntityProductToDTO(&p)
 					}),
 				}
 			}),
-		})
+		}
+	})), nil
 }
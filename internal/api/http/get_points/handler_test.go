@@ -1,16 +1,21 @@
 package get_points_test
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
 	"github.com/4udiwe/avito-pvz/internal/api/http/get_points"
 	mock_get_points "github.com/4udiwe/avito-pvz/internal/api/http/get_points/mocks"
+	"github.com/4udiwe/avito-pvz/internal/dto"
 	"github.com/4udiwe/avito-pvz/internal/entity"
-	"github.com/4udiwe/avito-pvz/pkg/validator"
 	"github.com/go-playground/assert/v2"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -19,38 +24,76 @@ import (
 func TestHandle(t *testing.T) {
 	var (
 		arbitraryErr = errors.New("arbitrary error")
+		startDate    = time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+		endDate      = time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
 	)
 
+	point := entity.Point{ID: uuid.New(), City: "Казань", CreatedAt: startDate}
+	reception := entity.Reception{ID: uuid.New(), PointID: point.ID, CreatedAt: endDate, Status: entity.ReceptionStatusClosed}
+	product := entity.Product{ID: uuid.New(), ReceptionID: reception.ID, CreatedAt: endDate, Type: entity.ProductTypeShoes}
+	info := []entity.PointFullInfo{{
+		Point:      point,
+		Receptions: []entity.ReceptionWithProducts{{Reception: reception, Products: []entity.Product{product}}},
+	}}
+	responseJSON, _ := json.Marshal([]dto.PVZWithReceptions{{
+		Pvz: *dto.EntityPointToDTO(&point),
+		Receptions: []dto.ReceptionWithProducts{{
+			Reception: *dto.EntityReceptionToDTO(&reception),
+			Products:  []dto.Product{*dto.EntityProductToDTO(&product)},
+		}},
+	}})
+
 	type MockBehavior func(s *mock_get_points.MockPointService)
 
 	for _, tc := range []struct {
 		name         string
+		query        string
 		mockBehavior MockBehavior
 		wantStatus   int
 		wantBody     string
 	}{
 		{
-			name: "success",
+			name:  "success",
+			query: "?startDate=2025-12-01T00:00:00Z&endDate=2025-12-31T00:00:00Z&page=2&limit=5",
+			mockBehavior: func(s *mock_get_points.MockPointService) {
+				s.EXPECT().GetPointsFullInfo(gomock.Any(), entity.PointFilter{
+					StartDate: &startDate,
+					EndDate:   &endDate,
+					Page:      2,
+					Limit:     5,
+				}).Return(info, nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   string(responseJSON),
+		},
+		{
+			name: "no points",
 			mockBehavior: func(s *mock_get_points.MockPointService) {
-				s.EXPECT().GetAllPointsFullInfo(gomock.Any()).Return([]entity.PointFullInfo{}, nil).Times(1)
+				s.EXPECT().GetPointsFullInfo(gomock.Any(), entity.PointFilter{}).Return(nil, nil).Times(1)
 			},
 			wantStatus: http.StatusOK,
+			wantBody:   "[]",
+		},
+		{
+			name:         "invalid date",
+			query:        "?startDate=yesterday",
+			mockBehavior: func(s *mock_get_points.MockPointService) {},
+			wantStatus:   http.StatusBadRequest,
 		},
 		{
 			name: "internal error",
 			mockBehavior: func(s *mock_get_points.MockPointService) {
-				s.EXPECT().GetAllPointsFullInfo(gomock.Any()).Return(nil, arbitraryErr).Times(1)
+				s.EXPECT().GetPointsFullInfo(gomock.Any(), entity.PointFilter{}).Return(nil, arbitraryErr).Times(1)
 			},
-			wantStatus: 500,
-			wantBody:   arbitraryErr.Error(),
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
 			e := echo.New()
-			e.Validator = validator.NewCustomValidator()
-			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req := httptest.NewRequest(http.MethodGet, "/"+tc.query, nil)
 			rec := httptest.NewRecorder()
 			ctx := e.NewContext(req, rec)
 
@@ -58,9 +101,9 @@ func TestHandle(t *testing.T) {
 			MockService := mock_get_points.NewMockPointService(ctrl)
 			tc.mockBehavior(MockService)
 
-			handler := get_points.New(MockService)
+			server := &dto.ServerInterfaceWrapper{Handler: dto.NewStrictHandler(api.StrictServer{GetPvzHandler: get_points.New(MockService)}, nil)}
 
-			err := handler.Handle(ctx)
+			err := server.GetPvz(ctx)
 
 			if tc.wantStatus >= 400 {
 				require.Error(t, err)
@@ -68,10 +111,13 @@ func TestHandle(t *testing.T) {
 				ok := errors.As(err, &httpErr)
 				require.True(t, ok)
 				assert.Equal(t, tc.wantStatus, httpErr.Code)
-				assert.Equal(t, tc.wantBody, httpErr.Message)
+				if tc.wantBody != "" {
+					assert.Equal(t, tc.wantBody, httpErr.Message)
+				}
 			} else {
 				require.NoError(t, err)
 				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, strings.Trim(rec.Body.String(), "\n"))
 			}
 		})
 	}
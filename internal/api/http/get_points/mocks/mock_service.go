@@ -41,17 +41,17 @@ func (m *MockPointService) EXPECT() *MockPointServiceMockRecorder {
 	return m.recorder
 }
 
-// GetAllPointsFullInfo mocks base method.
-func (m *MockPointService) GetAllPointsFullInfo(ctx context.Context) ([]entity.PointFullInfo, error) {
+// GetPointsFullInfo mocks base method.
+func (m *MockPointService) GetPointsFullInfo(ctx context.Context, filter entity.PointFilter) ([]entity.PointFullInfo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllPointsFullInfo", ctx)
+	ret := m.ctrl.Call(m, "GetPointsFullInfo", ctx, filter)
 	ret0, _ := ret[0].([]entity.PointFullInfo)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAllPointsFullInfo indicates an expected call of GetAllPointsFullInfo.
-func (mr *MockPointServiceMockRecorder) GetAllPointsFullInfo(ctx any) *gomock.Call {
+// GetPointsFullInfo indicates an expected call of GetPointsFullInfo.
+func (mr *MockPointServiceMockRecorder) GetPointsFullInfo(ctx, filter any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllPointsFullInfo", reflect.TypeOf((*MockPointService)(nil).GetAllPointsFullInfo), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPointsFullInfo", reflect.TypeOf((*MockPointService)(nil).GetPointsFullInfo), ctx, filter)
 }
@@ -0,0 +1,14 @@
+package get_product_cell
+
+import (
+	"context"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/google/uuid"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type CellService interface {
+	LocateProduct(ctx context.Context, productID *uuid.UUID, barcode string) (entity.Product, entity.StorageCell, error)
+}
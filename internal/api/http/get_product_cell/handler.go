@@ -0,0 +1,55 @@
+package get_product_cell
+
+import (
+	"errors"
+	"net/http"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	service "github.com/4udiwe/avito-pvz/internal/service/cell"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+var errNoLookupKey = errors.New("productId or barcode is required")
+
+type handler struct {
+	s CellService
+}
+
+func New(cellService CellService) api.Handler {
+	return decorator.NewBindAndValidateDerocator(&handler{s: cellService})
+}
+
+type Request struct {
+	ProductID *uuid.UUID `query:"productId"`
+	Barcode   string     `query:"barcode" validate:"max=64"`
+}
+
+type Response struct {
+	Product dto.Product     `json:"product"`
+	Cell    dto.StorageCell `json:"cell"`
+}
+
+func (h *handler) Handle(ctx echo.Context, in Request) error {
+	if in.ProductID == nil && in.Barcode == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, errNoLookupKey.Error())
+	}
+
+	product, cell, err := h.s.LocateProduct(ctx.Request().Context(), in.ProductID, in.Barcode)
+
+	if err != nil {
+		if errors.Is(err, service.ErrNoProductFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrProductNotPlaced) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return ctx.JSON(http.StatusOK, Response{
+		Product: *dto.EntityProductToDTO(&product),
+		Cell:    *dto.EntityCellToDTO(&cell),
+	})
+}
@@ -0,0 +1,133 @@
+package get_product_cell_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_product_cell"
+	mock_get_product_cell "github.com/4udiwe/avito-pvz/internal/api/http/get_product_cell/mocks"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	service "github.com/4udiwe/avito-pvz/internal/service/cell"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		productID    = uuid.New()
+		cellID       = uuid.New()
+		barcode      = "4600000000017"
+
+		product = entity.Product{ID: productID, Type: entity.ProductTypeShoes, Barcode: &barcode, CellID: &cellID}
+		cell    = entity.StorageCell{ID: cellID, PointID: uuid.New(), Rack: "A", Shelf: "2", Bin: "3", Capacity: 4, Occupied: 1}
+	)
+
+	responseJSON, _ := json.Marshal(get_product_cell.Response{
+		Product: *dto.EntityProductToDTO(&product),
+		Cell:    *dto.EntityCellToDTO(&cell),
+	})
+
+	type MockBehavior func(s *mock_get_product_cell.MockCellService)
+
+	for _, tc := range []struct {
+		name         string
+		query        string
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name:  "by product id",
+			query: "productId=" + productID.String(),
+			mockBehavior: func(s *mock_get_product_cell.MockCellService) {
+				s.EXPECT().LocateProduct(gomock.Any(), &productID, "").Return(product, cell, nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   string(responseJSON),
+		},
+		{
+			name:  "by barcode",
+			query: "barcode=" + barcode,
+			mockBehavior: func(s *mock_get_product_cell.MockCellService) {
+				s.EXPECT().LocateProduct(gomock.Any(), nil, barcode).Return(product, cell, nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   string(responseJSON),
+		},
+		{
+			name:  "no product found",
+			query: "barcode=" + barcode,
+			mockBehavior: func(s *mock_get_product_cell.MockCellService) {
+				s.EXPECT().LocateProduct(gomock.Any(), nil, barcode).Return(entity.Product{}, entity.StorageCell{}, service.ErrNoProductFound).Times(1)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   service.ErrNoProductFound.Error(),
+		},
+		{
+			name:  "product not placed",
+			query: "productId=" + productID.String(),
+			mockBehavior: func(s *mock_get_product_cell.MockCellService) {
+				s.EXPECT().LocateProduct(gomock.Any(), &productID, "").Return(entity.Product{}, entity.StorageCell{}, service.ErrProductNotPlaced).Times(1)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   service.ErrProductNotPlaced.Error(),
+		},
+		{
+			name:  "internal error",
+			query: "productId=" + productID.String(),
+			mockBehavior: func(s *mock_get_product_cell.MockCellService) {
+				s.EXPECT().LocateProduct(gomock.Any(), &productID, "").Return(entity.Product{}, entity.StorageCell{}, arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+		{
+			name:         "no lookup key",
+			query:        "",
+			mockBehavior: func(s *mock_get_product_cell.MockCellService) {},
+			wantStatus:   http.StatusBadRequest,
+			wantBody:     "productId or barcode is required",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+			req := httptest.NewRequest(http.MethodGet, "/?"+tc.query, nil)
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_get_product_cell.NewMockCellService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := get_product_cell.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, strings.Trim(rec.Body.String(), "\n"))
+			}
+		})
+	}
+}
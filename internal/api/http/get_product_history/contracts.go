@@ -0,0 +1,14 @@
+package get_product_history
+
+import (
+	"context"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/google/uuid"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type ProductService interface {
+	GetHistory(ctx context.Context, productID uuid.UUID) ([]entity.ProductHistory, error)
+}
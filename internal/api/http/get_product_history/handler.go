@@ -0,0 +1,36 @@
+package get_product_history
+
+import (
+	"context"
+	"net/http"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/lo"
+)
+
+type handler struct {
+	s ProductService
+}
+
+func New(productService ProductService) api.GetProductsProductIdHistoryHandler {
+	return &handler{s: productService}
+}
+
+func (h *handler) GetProductsProductIdHistory(
+	ctx context.Context,
+	request dto.GetProductsProductIdHistoryRequestObject,
+) (dto.GetProductsProductIdHistoryResponseObject, error) {
+	history, err := h.s.GetHistory(ctx, request.ProductId)
+
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return dto.GetProductsProductIdHistory200JSONResponse(
+		lo.Map(history, func(item entity.ProductHistory, _ int) dto.ProductHistoryRecord {
+			return *dto.EntityProductHistoryToDTO(&item)
+		}),
+	), nil
+}
@@ -0,0 +1,125 @@
+package get_product_history_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_product_history"
+	mock_get_product_history "github.com/4udiwe/avito-pvz/internal/api/http/get_product_history/mocks"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		productID    = uuid.New()
+		actorID      = uuid.New()
+		recordID     = uuid.New()
+		now          = time.Now()
+		from         = entity.ProductStatusReceived
+
+		history = []entity.ProductHistory{
+			{
+				ID:         recordID,
+				ProductID:  productID,
+				FromStatus: &from,
+				ToStatus:   entity.ProductStatusStored,
+				ActorID:    actorID,
+				CreatedAt:  now,
+			},
+		}
+		fromDTO  = dto.ProductStatus(from)
+		response = []dto.ProductHistoryRecord{
+			{
+				Id:         recordID,
+				FromStatus: &fromDTO,
+				ToStatus:   dto.Stored,
+				ActorId:    actorID,
+				DateTime:   now,
+			},
+		}
+	)
+
+	responseJSON, _ := json.Marshal(response)
+
+	type MockBehavior func(s *mock_get_product_history.MockProductService)
+
+	for _, tc := range []struct {
+		name         string
+		productID    string
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name:      "success",
+			productID: productID.String(),
+			mockBehavior: func(s *mock_get_product_history.MockProductService) {
+				s.EXPECT().GetHistory(gomock.Any(), productID).Return(history, nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   string(responseJSON),
+		},
+		{
+			name:      "internal error",
+			productID: productID.String(),
+			mockBehavior: func(s *mock_get_product_history.MockProductService) {
+				s.EXPECT().GetHistory(gomock.Any(), productID).Return(nil, arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+		{
+			name:         "invalid product id provided",
+			productID:    "123",
+			mockBehavior: func(s *mock_get_product_history.MockProductService) {},
+			wantStatus:   http.StatusBadRequest,
+			wantBody:     "Invalid format for parameter productId: error unmarshaling '123' text as *uuid.UUID: invalid UUID length: 3",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctx.SetParamNames("productId")
+			ctx.SetParamValues(tc.productID)
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_get_product_history.NewMockProductService(ctrl)
+			tc.mockBehavior(MockService)
+
+			server := &dto.ServerInterfaceWrapper{Handler: dto.NewStrictHandler(api.StrictServer{GetProductsProductIdHistoryHandler: get_product_history.New(MockService)}, nil)}
+
+			err := server.GetProductsProductIdHistory(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, strings.Trim(rec.Body.String(), "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: contracts.go
+//
+// Generated by this command:
+//
+//	mockgen -source=contracts.go -destination=mocks/mock_service.go
+//
+
+// Package mock_get_product_history is a generated GoMock package.
+package mock_get_product_history
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/4udiwe/avito-pvz/internal/entity"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProductService is a mock of ProductService interface.
+type MockProductService struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockProductServiceMockRecorder is the mock recorder for MockProductService.
+type MockProductServiceMockRecorder struct {
+	mock *MockProductService
+}
+
+// NewMockProductService creates a new mock instance.
+func NewMockProductService(ctrl *gomock.Controller) *MockProductService {
+	mock := &MockProductService{ctrl: ctrl}
+	mock.recorder = &MockProductServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductService) EXPECT() *MockProductServiceMockRecorder {
+	return m.recorder
+}
+
+// GetHistory mocks base method.
+func (m *MockProductService) GetHistory(ctx context.Context, productID uuid.UUID) ([]entity.ProductHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", ctx, productID)
+	ret0, _ := ret[0].([]entity.ProductHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockProductServiceMockRecorder) GetHistory(ctx, productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockProductService)(nil).GetHistory), ctx, productID)
+}
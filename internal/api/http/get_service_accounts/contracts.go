@@ -0,0 +1,13 @@
+package get_service_accounts
+
+import (
+	"context"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type ServiceAccountService interface {
+	ListServiceAccounts(ctx context.Context) ([]entity.ServiceAccount, error)
+}
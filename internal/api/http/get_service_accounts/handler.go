@@ -0,0 +1,39 @@
+package get_service_accounts
+
+import (
+	"net/http"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/lo"
+)
+
+type handler struct {
+	s ServiceAccountService
+}
+
+func New(serviceAccountService ServiceAccountService) api.Handler {
+	return decorator.NewBindAndValidateDerocator(&handler{s: serviceAccountService})
+}
+
+type Request struct{}
+
+type Response struct {
+	ServiceAccounts []dto.ServiceAccount `json:"serviceAccounts"`
+}
+
+func (h *handler) Handle(ctx echo.Context, in Request) error {
+	accounts, err := h.s.ListServiceAccounts(ctx.Request().Context())
+
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return ctx.JSON(http.StatusOK, Response{
+		ServiceAccounts: lo.Map(accounts, func(a entity.ServiceAccount, _ int) dto.ServiceAccount {
+			return *dto.EntityServiceAccountToDTO(&a)
+		}),
+	})
+}
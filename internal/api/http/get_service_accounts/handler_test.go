@@ -0,0 +1,122 @@
+package get_service_accounts_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_service_accounts"
+	mock_get_service_accounts "github.com/4udiwe/avito-pvz/internal/api/http/get_service_accounts/mocks"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		moderatorID  = uuid.New()
+		now          = time.Now().UTC().Truncate(time.Second)
+		accountID    = uuid.New()
+		accounts     = []entity.ServiceAccount{
+			{
+				ID:        accountID,
+				Name:      "erp",
+				CreatedBy: moderatorID,
+				CreatedAt: now,
+				Keys: []entity.APIKey{{
+					ID:               uuid.New(),
+					ServiceAccountID: accountID,
+					Prefix:           "pvz_abcdefgh",
+					Scopes:           []entity.Permission{entity.PermissionPointRead},
+					CreatedAt:        now,
+					ExpiresAt:        now.Add(time.Hour),
+				}},
+			},
+		}
+	)
+
+	responseJSON, _ := json.Marshal(get_service_accounts.Response{
+		ServiceAccounts: []dto.ServiceAccount{*dto.EntityServiceAccountToDTO(&accounts[0])},
+	})
+
+	type MockBehavior func(s *mock_get_service_accounts.MockServiceAccountService)
+
+	for _, tc := range []struct {
+		name         string
+		body         string
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name: "success",
+			mockBehavior: func(s *mock_get_service_accounts.MockServiceAccountService) {
+				s.EXPECT().ListServiceAccounts(gomock.Any()).Return(accounts, nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   string(responseJSON),
+		},
+		{
+			name: "no accounts",
+			mockBehavior: func(s *mock_get_service_accounts.MockServiceAccountService) {
+				s.EXPECT().ListServiceAccounts(gomock.Any()).Return(nil, nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"serviceAccounts":[]}`,
+		},
+		{
+			name: "internal error",
+			mockBehavior: func(s *mock_get_service_accounts.MockServiceAccountService) {
+				s.EXPECT().ListServiceAccounts(gomock.Any()).Return(nil, arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+			req := httptest.NewRequest(http.MethodGet, "/", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctx.Set(middleware.USER_CLAIMS_KEY, &auth.TokenClaims{UserID: moderatorID, Role: entity.RoleModerator})
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_get_service_accounts.NewMockServiceAccountService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := get_service_accounts.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, strings.Trim(rec.Body.String(), "\n"))
+			}
+		})
+	}
+}
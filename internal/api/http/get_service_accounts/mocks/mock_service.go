@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: contracts.go
+//
+// Generated by this command:
+//
+//	mockgen -source=contracts.go -destination=mocks/mock_service.go
+//
+
+// Package mock_get_service_accounts is a generated GoMock package.
+package mock_get_service_accounts
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/4udiwe/avito-pvz/internal/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockServiceAccountService is a mock of ServiceAccountService interface.
+type MockServiceAccountService struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceAccountServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockServiceAccountServiceMockRecorder is the mock recorder for MockServiceAccountService.
+type MockServiceAccountServiceMockRecorder struct {
+	mock *MockServiceAccountService
+}
+
+// NewMockServiceAccountService creates a new mock instance.
+func NewMockServiceAccountService(ctrl *gomock.Controller) *MockServiceAccountService {
+	mock := &MockServiceAccountService{ctrl: ctrl}
+	mock.recorder = &MockServiceAccountServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockServiceAccountService) EXPECT() *MockServiceAccountServiceMockRecorder {
+	return m.recorder
+}
+
+// ListServiceAccounts mocks base method.
+func (m *MockServiceAccountService) ListServiceAccounts(ctx context.Context) ([]entity.ServiceAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListServiceAccounts", ctx)
+	ret0, _ := ret[0].([]entity.ServiceAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListServiceAccounts indicates an expected call of ListServiceAccounts.
+func (mr *MockServiceAccountServiceMockRecorder) ListServiceAccounts(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServiceAccounts", reflect.TypeOf((*MockServiceAccountService)(nil).ListServiceAccounts), ctx)
+}
@@ -0,0 +1,14 @@
+package get_sessions
+
+import (
+	"context"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/google/uuid"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type UserService interface {
+	GetSessions(ctx context.Context, userID uuid.UUID) ([]entity.Session, error)
+}
@@ -0,0 +1,42 @@
+package get_sessions
+
+import (
+	"net/http"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/lo"
+)
+
+type handler struct {
+	s UserService
+}
+
+func New(userService UserService) api.Handler {
+	return decorator.NewBindAndValidateDerocator(&handler{s: userService})
+}
+
+type Request struct{}
+
+func (h *handler) Handle(ctx echo.Context, in Request) error {
+	claims, err := middleware.GetUserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	sessions, err := h.s.GetSessions(ctx.Request().Context(), claims.UserID)
+
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return ctx.JSON(
+		http.StatusOK,
+		lo.Map(sessions, func(item entity.Session, _ int) dto.Session {
+			return *dto.EntitySessionToDTO(&item, claims.SessionID)
+		}),
+	)
+}
@@ -0,0 +1,102 @@
+package get_sessions_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_sessions"
+	mock_get_sessions "github.com/4udiwe/avito-pvz/internal/api/http/get_sessions/mocks"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		userID       = uuid.New()
+		currentID    = uuid.New()
+		now          = time.Now().UTC().Truncate(time.Second)
+
+		sessions = []entity.Session{
+			{ID: currentID, UserID: userID, UserAgent: "scanner/1.0", IP: "10.0.0.1", CreatedAt: now, LastUsedAt: now, ExpiresAt: now.Add(time.Hour)},
+			{ID: uuid.New(), UserID: userID, UserAgent: "scanner/2.0", IP: "10.0.0.2", CreatedAt: now, LastUsedAt: now, ExpiresAt: now.Add(time.Hour)},
+		}
+	)
+
+	responseJSON, _ := json.Marshal([]dto.Session{
+		*dto.EntitySessionToDTO(&sessions[0], currentID),
+		*dto.EntitySessionToDTO(&sessions[1], currentID),
+	})
+
+	type MockBehavior func(s *mock_get_sessions.MockUserService)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name: "success",
+			mockBehavior: func(s *mock_get_sessions.MockUserService) {
+				s.EXPECT().GetSessions(gomock.Any(), userID).Return(sessions, nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   string(responseJSON),
+		},
+		{
+			name: "internal error",
+			mockBehavior: func(s *mock_get_sessions.MockUserService) {
+				s.EXPECT().GetSessions(gomock.Any(), userID).Return(nil, arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctx.Set(middleware.USER_CLAIMS_KEY, &auth.TokenClaims{UserID: userID, Role: entity.RoleEmployee, SessionID: currentID})
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_get_sessions.NewMockUserService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := get_sessions.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, strings.Trim(rec.Body.String(), "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package get_transfer
+
+import (
+	"context"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/google/uuid"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type TransferService interface {
+	GetTransfer(ctx context.Context, transferID uuid.UUID) (entity.Transfer, error)
+}
@@ -0,0 +1,37 @@
+package get_transfer
+
+import (
+	"errors"
+	"net/http"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	service "github.com/4udiwe/avito-pvz/internal/service/transfer"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s TransferService
+}
+
+func New(transferService TransferService) api.Handler {
+	return decorator.NewBindAndValidateDerocator(&handler{s: transferService})
+}
+
+type Request struct {
+	TransferID uuid.UUID `param:"transferId" validate:"required"`
+}
+
+func (h *handler) Handle(ctx echo.Context, in Request) error {
+	transfer, err := h.s.GetTransfer(ctx.Request().Context(), in.TransferID)
+
+	if err != nil {
+		if errors.Is(err, service.ErrNoTransferFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return ctx.JSON(http.StatusOK, dto.EntityTransferToDTO(&transfer))
+}
@@ -0,0 +1,109 @@
+package get_transfer_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_transfer"
+	mock_get_transfer "github.com/4udiwe/avito-pvz/internal/api/http/get_transfer/mocks"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	service "github.com/4udiwe/avito-pvz/internal/service/transfer"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		transferID   = uuid.New()
+
+		transfer = entity.Transfer{
+			ID:                 transferID,
+			SourcePointID:      uuid.New(),
+			DestinationPointID: uuid.New(),
+			Status:             entity.TransferStatusCreated,
+			Products:           []entity.TransferProduct{{ProductID: uuid.New()}},
+			CreatedAt:          time.Now(),
+		}
+	)
+
+	responseJSON, _ := json.Marshal(dto.EntityTransferToDTO(&transfer))
+
+	type MockBehavior func(s *mock_get_transfer.MockTransferService)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name: "success",
+			mockBehavior: func(s *mock_get_transfer.MockTransferService) {
+				s.EXPECT().GetTransfer(gomock.Any(), transferID).Return(transfer, nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   string(responseJSON),
+		},
+		{
+			name: "no transfer found",
+			mockBehavior: func(s *mock_get_transfer.MockTransferService) {
+				s.EXPECT().GetTransfer(gomock.Any(), transferID).Return(entity.Transfer{}, service.ErrNoTransferFound).Times(1)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   service.ErrNoTransferFound.Error(),
+		},
+		{
+			name: "internal error",
+			mockBehavior: func(s *mock_get_transfer.MockTransferService) {
+				s.EXPECT().GetTransfer(gomock.Any(), transferID).Return(entity.Transfer{}, arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctx.SetParamNames("transferId")
+			ctx.SetParamValues(transferID.String())
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_get_transfer.NewMockTransferService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := get_transfer.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, strings.Trim(rec.Body.String(), "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: contracts.go
+//
+// Generated by this command:
+//
+//	mockgen -source=contracts.go -destination=mocks/mock_service.go
+//
+
+// Package mock_get_transfer is a generated GoMock package.
+package mock_get_transfer
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/4udiwe/avito-pvz/internal/entity"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTransferService is a mock of TransferService interface.
+type MockTransferService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransferServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockTransferServiceMockRecorder is the mock recorder for MockTransferService.
+type MockTransferServiceMockRecorder struct {
+	mock *MockTransferService
+}
+
+// NewMockTransferService creates a new mock instance.
+func NewMockTransferService(ctrl *gomock.Controller) *MockTransferService {
+	mock := &MockTransferService{ctrl: ctrl}
+	mock.recorder = &MockTransferServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransferService) EXPECT() *MockTransferServiceMockRecorder {
+	return m.recorder
+}
+
+// GetTransfer mocks base method.
+func (m *MockTransferService) GetTransfer(ctx context.Context, transferID uuid.UUID) (entity.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransfer", ctx, transferID)
+	ret0, _ := ret[0].(entity.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransfer indicates an expected call of GetTransfer.
+func (mr *MockTransferServiceMockRecorder) GetTransfer(ctx, transferID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfer", reflect.TypeOf((*MockTransferService)(nil).GetTransfer), ctx, transferID)
+}
@@ -0,0 +1,14 @@
+package get_user
+
+import (
+	"context"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/google/uuid"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type UserService interface {
+	GetUser(ctx context.Context, userID uuid.UUID) (entity.User, error)
+}
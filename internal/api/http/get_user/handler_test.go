@@ -0,0 +1,102 @@
+package get_user_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_user"
+	mock_get_user "github.com/4udiwe/avito-pvz/internal/api/http/get_user/mocks"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/service/user"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		userID       = uuid.New()
+		disabledAt   = time.Now().UTC().Truncate(time.Second)
+		out          = entity.User{ID: userID, Email: "a@mail.com", Role: entity.RoleEmployee, DisabledAt: &disabledAt}
+	)
+
+	responseJSON, _ := json.Marshal(dto.EntityUserToDTO(&out))
+
+	type MockBehavior func(s *mock_get_user.MockUserService)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name: "success",
+			mockBehavior: func(s *mock_get_user.MockUserService) {
+				s.EXPECT().GetUser(gomock.Any(), userID).Return(out, nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   string(responseJSON),
+		},
+		{
+			name: "no user found",
+			mockBehavior: func(s *mock_get_user.MockUserService) {
+				s.EXPECT().GetUser(gomock.Any(), userID).Return(entity.User{}, user.ErrNoUserFound).Times(1)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   user.ErrNoUserFound.Error(),
+		},
+		{
+			name: "internal error",
+			mockBehavior: func(s *mock_get_user.MockUserService) {
+				s.EXPECT().GetUser(gomock.Any(), userID).Return(entity.User{}, arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctx.SetParamNames("userId")
+			ctx.SetParamValues(userID.String())
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_get_user.NewMockUserService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := get_user.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, strings.Trim(rec.Body.String(), "\n"))
+			}
+		})
+	}
+}
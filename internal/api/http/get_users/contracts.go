@@ -0,0 +1,13 @@
+package get_users
+
+import (
+	"context"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type UserService interface {
+	ListUsers(ctx context.Context, filter entity.UserFilter) ([]entity.User, error)
+}
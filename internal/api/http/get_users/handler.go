@@ -0,0 +1,55 @@
+package get_users
+
+import (
+	"net/http"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/lo"
+)
+
+type handler struct {
+	s UserService
+}
+
+func New(userService UserService) api.Handler {
+	return decorator.NewBindAndValidateDerocator(&handler{s: userService})
+}
+
+type Request struct {
+	Query    string `query:"q" validate:"max=320"`
+	Role     string `query:"role" validate:"max=32"`
+	Disabled *bool  `query:"disabled"`
+	Page     int    `query:"page" validate:"omitempty,min=1"`
+	Limit    int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+type Response struct {
+	Users []dto.UserAccount `json:"users"`
+}
+
+func (h *handler) Handle(ctx echo.Context, in Request) error {
+	filter := entity.UserFilter{
+		Query:    in.Query,
+		Disabled: in.Disabled,
+		Page:     in.Page,
+		Limit:    in.Limit,
+	}
+	if in.Role != "" {
+		filter.Role = lo.ToPtr(entity.UserRole(in.Role))
+	}
+
+	users, err := h.s.ListUsers(ctx.Request().Context(), filter)
+
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return ctx.JSON(http.StatusOK, Response{
+		Users: lo.Map(users, func(u entity.User, _ int) dto.UserAccount {
+			return *dto.EntityUserToDTO(&u)
+		}),
+	})
+}
@@ -0,0 +1,118 @@
+package get_users_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_users"
+	mock_get_users "github.com/4udiwe/avito-pvz/internal/api/http/get_users/mocks"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		users        = []entity.User{{ID: uuid.New(), Email: "a@mail.com", Role: entity.RoleEmployee}}
+		moderator    = entity.RoleModerator
+	)
+
+	responseJSON, _ := json.Marshal(get_users.Response{
+		Users: []dto.UserAccount{*dto.EntityUserToDTO(&users[0])},
+	})
+
+	type MockBehavior func(s *mock_get_users.MockUserService)
+
+	for _, tc := range []struct {
+		name         string
+		query        string
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name:  "success with filters",
+			query: "q=mail&role=moderator&disabled=true&page=2&limit=10",
+			mockBehavior: func(s *mock_get_users.MockUserService) {
+				s.EXPECT().ListUsers(gomock.Any(), entity.UserFilter{
+					Query:    "mail",
+					Role:     &moderator,
+					Disabled: lo.ToPtr(true),
+					Page:     2,
+					Limit:    10,
+				}).Return(users, nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   string(responseJSON),
+		},
+		{
+			name:  "success without filters",
+			query: "",
+			mockBehavior: func(s *mock_get_users.MockUserService) {
+				s.EXPECT().ListUsers(gomock.Any(), entity.UserFilter{}).Return(users, nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   string(responseJSON),
+		},
+		{
+			name:  "role added in database",
+			query: "role=auditor",
+			mockBehavior: func(s *mock_get_users.MockUserService) {
+				s.EXPECT().ListUsers(gomock.Any(), entity.UserFilter{Role: lo.ToPtr(entity.RoleAuditor)}).Return(users, nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   string(responseJSON),
+		},
+		{
+			name:  "internal error",
+			query: "",
+			mockBehavior: func(s *mock_get_users.MockUserService) {
+				s.EXPECT().ListUsers(gomock.Any(), entity.UserFilter{}).Return(nil, arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+			req := httptest.NewRequest(http.MethodGet, "/?"+tc.query, nil)
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_get_users.NewMockUserService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := get_users.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, strings.Trim(rec.Body.String(), "\n"))
+			}
+		})
+	}
+}
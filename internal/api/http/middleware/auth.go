@@ -1,53 +1,142 @@
 package middleware
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
 )
 
 const USER_CLAIMS_KEY = "userClaims"
 
+// API_KEY_HEADER carries API keys of service accounts as an alternative to
+// a bearer token.
+const API_KEY_HEADER = "X-API-Key"
+
 type AuthRepo interface {
 	ValidateAccessToken(tokenString string) (*auth.TokenClaims, error)
 }
 
+type RevocationList interface {
+	IsRevoked(jti string) bool
+}
+
+type DisabledUsers interface {
+	IsDisabled(userID uuid.UUID) bool
+}
+
+type APIKeys interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (*auth.TokenClaims, error)
+}
+
 type AuthMiddleware struct {
-	auth AuthRepo
+	auth     AuthRepo
+	revoked  RevocationList
+	disabled DisabledUsers
+	apiKeys  APIKeys
 }
 
-func New(auth AuthRepo) *AuthMiddleware {
+func New(auth AuthRepo, revoked RevocationList, disabled DisabledUsers, apiKeys APIKeys) *AuthMiddleware {
 	return &AuthMiddleware{
-		auth: auth,
+		auth:     auth,
+		revoked:  revoked,
+		disabled: disabled,
+		apiKeys:  apiKeys,
 	}
 }
 
 func (m *AuthMiddleware) Middleware(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
+		if key := c.Request().Header.Get(API_KEY_HEADER); key != "" {
+			claims, err := m.apiKeys.AuthenticateAPIKey(c.Request().Context(), key)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid API key").SetInternal(err)
+			}
+
+			setUser(c, claims)
+
+			return next(c)
+		}
+
 		authHeader := c.Request().Header.Get("Authorization")
 		if authHeader == "" {
-			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Authorization header required"})
+			return echo.NewHTTPError(http.StatusUnauthorized, "Authorization header required")
 		}
 
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Invalid authorization header format"})
+			return echo.NewHTTPError(http.StatusUnauthorized, "Invalid authorization header format")
 		}
 
 		token := parts[1]
 		claims, err := m.auth.ValidateAccessToken(token)
 		if err != nil {
-			return c.JSON(http.StatusUnauthorized, err.Error())
+			return echo.NewHTTPError(http.StatusUnauthorized, "Invalid access token").SetInternal(fmt.Errorf("%w: %w", auth.ErrInvalidAccessToken, err))
+		}
+
+		if m.revoked.IsRevoked(claims.ID) || m.revoked.IsRevoked(auth.SessionRevocationID(claims.SessionID)) {
+			return echo.NewHTTPError(http.StatusUnauthorized, auth.ErrRevokedToken.Error()).SetInternal(auth.ErrRevokedToken)
+		}
+
+		if m.disabled.IsDisabled(claims.UserID) {
+			return echo.NewHTTPError(http.StatusUnauthorized, auth.ErrDisabledUser.Error()).SetInternal(auth.ErrDisabledUser)
 		}
 
-		c.Set(USER_CLAIMS_KEY, claims)
+		setUser(c, claims)
 
 		return next(c)
 	}
 }
 
+// UsersOnly rejects service accounts on routes that only make sense for
+// people, such as sessions and passwords. It must run after AuthMiddleware.
+func UsersOnly(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims, err := GetUserFromContext(c)
+		if err != nil {
+			return err
+		}
+
+		if claims.IsServiceAccount() {
+			return echo.NewHTTPError(http.StatusForbidden, "Access denied")
+		}
+
+		return next(c)
+	}
+}
+
+type userContextKey struct{}
+
+// setUser stores claims both in the echo.Context and in the request
+// context, which is all the strict handlers get, and tags the request's
+// log lines with the caller.
+func setUser(c echo.Context, claims *auth.TokenClaims) {
+	c.Set(USER_CLAIMS_KEY, claims)
+
+	ctx := NewUserContext(c.Request().Context(), claims)
+	ctx = logger.With(ctx, logrus.Fields{logger.UserID: claims.UserID})
+	c.SetRequest(c.Request().WithContext(ctx))
+}
+
+func NewUserContext(ctx context.Context, claims *auth.TokenClaims) context.Context {
+	return context.WithValue(ctx, userContextKey{}, claims)
+}
+
+// UserFromContext is GetUserFromContext for handlers of the strict server.
+func UserFromContext(ctx context.Context) (*auth.TokenClaims, error) {
+	claims, ok := ctx.Value(userContextKey{}).(*auth.TokenClaims)
+	if !ok {
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, "User not found in context")
+	}
+	return claims, nil
+}
+
 func GetUserFromContext(c echo.Context) (*auth.TokenClaims, error) {
 	claims, ok := c.Get(USER_CLAIMS_KEY).(*auth.TokenClaims)
 	if !ok {
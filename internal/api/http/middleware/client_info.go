@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/labstack/echo/v4"
+)
+
+type clientInfoContextKey struct{}
+
+// ClientInfo puts the user agent and address of the caller into the request
+// context, for handlers that do not see the echo.Context.
+func ClientInfo(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		client := entity.ClientInfo{UserAgent: c.Request().UserAgent(), IP: c.RealIP()}
+		c.SetRequest(c.Request().WithContext(NewClientInfoContext(c.Request().Context(), client)))
+
+		return next(c)
+	}
+}
+
+func NewClientInfoContext(ctx context.Context, client entity.ClientInfo) context.Context {
+	return context.WithValue(ctx, clientInfoContextKey{}, client)
+}
+
+// ClientInfoFromContext returns an empty ClientInfo outside of a request.
+func ClientInfoFromContext(ctx context.Context) entity.ClientInfo {
+	client, _ := ctx.Value(clientInfoContextKey{}).(entity.ClientInfo)
+	return client
+}
+
+// IPExtractor resolves the caller address behind the given proxies, CIDR
+// ranges whose X-Forwarded-For is trusted. Without proxies the peer address
+// is used and forwarding headers are ignored, as a client can set them to
+// anything.
+func IPExtractor(trustedProxies []string) (echo.IPExtractor, error) {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, proxy := range trustedProxies {
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q: %w", proxy, err)
+		}
+		options = append(options, echo.TrustIPRange(ipNet))
+	}
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
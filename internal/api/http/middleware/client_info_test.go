@@ -0,0 +1,81 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientInfoIP(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		trustedProxies []string
+		remoteAddr     string
+		headers        map[string]string
+		wantIP         string
+	}{
+		{
+			name:       "forged headers without proxies",
+			remoteAddr: "203.0.113.7:41000",
+			headers: map[string]string{
+				echo.HeaderXForwardedFor: "198.51.100.1",
+				echo.HeaderXRealIP:       "198.51.100.2",
+			},
+			wantIP: "203.0.113.7",
+		},
+		{
+			name:           "forged header from untrusted peer",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "203.0.113.7:41000",
+			headers:        map[string]string{echo.HeaderXForwardedFor: "198.51.100.1"},
+			wantIP:         "203.0.113.7",
+		},
+		{
+			name:           "forged header through trusted proxy",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "10.0.0.5:41000",
+			headers:        map[string]string{echo.HeaderXForwardedFor: "198.51.100.1, 203.0.113.7"},
+			wantIP:         "203.0.113.7",
+		},
+		{
+			name:           "private peer is not trusted by default",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "192.168.1.5:41000",
+			headers:        map[string]string{echo.HeaderXForwardedFor: "198.51.100.1"},
+			wantIP:         "192.168.1.5",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			extractor, err := middleware.IPExtractor(tc.trustedProxies)
+			require.NoError(t, err)
+
+			var ip string
+			e := echo.New()
+			e.IPExtractor = extractor
+			e.Use(middleware.ClientInfo)
+			e.POST("/login", func(c echo.Context) error {
+				ip = middleware.ClientInfoFromContext(c.Request().Context()).IP
+				return c.NoContent(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/login", nil)
+			req.RemoteAddr = tc.remoteAddr
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			e.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tc.wantIP, ip)
+		})
+	}
+}
+
+func TestIPExtractorInvalidProxy(t *testing.T) {
+	_, err := middleware.IPExtractor([]string{"10.0.0.5"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,212 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/4udiwe/avito-pvz/pkg/transactor"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	IDEMPOTENCY_KEY_HEADER = "Idempotency-Key"
+	// IDEMPOTENT_REPLAYED_HEADER marks a response replayed from the store.
+	IDEMPOTENT_REPLAYED_HEADER = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+var (
+	ErrIdempotencyKeyReused  = errors.New("idempotency key was used for another request")
+	ErrInvalidIdempotencyKey = errors.New("idempotency key must be 1 to 255 characters long")
+)
+
+//go:generate go tool mockgen -source=idempotency.go -destination=mocks/mock_idempotency.go -package=mocks
+type IdempotencyTransactor interface {
+	transactor.Transactor
+	WithinSavepoint(ctx context.Context, fn func(context.Context) error) error
+}
+
+type IdempotencyStore interface {
+	Acquire(ctx context.Context, key entity.IdempotencyKey) (entity.IdempotencyKey, bool, error)
+	SaveResponse(ctx context.Context, key entity.IdempotencyKey) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+// Idempotency makes retries of a request sent with an Idempotency-Key safe.
+// The key, the hash of the request and the response are stored in the
+// transaction the handler's changes join, so either both are committed or
+// neither is. A retry gets the stored response back without running the
+// handler; the same key with another request gets a 422. Only successes
+// and the 400 and 422 answers, which a retry would get again, are stored,
+// without what a failed handler changed. Any other response releases the
+// key, so the request may be retried. It must run after AuthMiddleware,
+// keys are scoped to the caller.
+type Idempotency struct {
+	txManager IdempotencyTransactor
+	store     IdempotencyStore
+	ttl       time.Duration
+}
+
+func NewIdempotency(txManager IdempotencyTransactor, store IdempotencyStore, ttl time.Duration) *Idempotency {
+	return &Idempotency{
+		txManager: txManager,
+		store:     store,
+		ttl:       ttl,
+	}
+}
+
+var (
+	// errNotStored rolls back a request whose response is not stored,
+	// releasing its key.
+	errNotStored = errors.New("response not stored")
+	// errRequestFailed rolls back what a failed handler changed.
+	errRequestFailed = errors.New("request failed")
+)
+
+// storable tells the responses a retry is answered with.
+func storable(status int) bool {
+	switch {
+	case status >= http.StatusOK && status < http.StatusMultipleChoices:
+		return true
+	case status == http.StatusBadRequest, status == http.StatusUnprocessableEntity:
+		return true
+	default:
+		return false
+	}
+}
+
+func (m *Idempotency) Middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		value, ok := req.Header[IDEMPOTENCY_KEY_HEADER]
+		if !ok {
+			return next(c)
+		}
+		if len(value) != 1 || value[0] == "" || len(value[0]) > maxIdempotencyKeyLength {
+			return echo.NewHTTPError(http.StatusBadRequest, ErrInvalidIdempotencyKey.Error()).SetInternal(ErrInvalidIdempotencyKey)
+		}
+
+		claims, err := UserFromContext(req.Context())
+		if err != nil {
+			return err
+		}
+
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Failed to read request body").SetInternal(err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+
+		key := entity.IdempotencyKey{
+			OwnerID:     claims.UserID,
+			Key:         value[0],
+			RequestHash: requestHash(req.Method, req.URL.Path, body),
+			ExpiresAt:   time.Now().Add(m.ttl),
+		}
+
+		res := c.Response()
+		writer := res.Writer
+		buffer := &responseBuffer{header: writer.Header(), status: http.StatusOK}
+
+		var replayed bool
+		err = m.txManager.WithinTransaction(req.Context(), func(ctx context.Context) error {
+			stored, found, err := m.store.Acquire(ctx, key)
+			if err != nil {
+				return err
+			}
+			if found {
+				if stored.RequestHash != key.RequestHash {
+					return echo.NewHTTPError(http.StatusUnprocessableEntity, ErrIdempotencyKeyReused.Error()).SetInternal(ErrIdempotencyKeyReused)
+				}
+				key, replayed = stored, true
+				return nil
+			}
+
+			// The handler joins the transaction through the request context,
+			// in a savepoint that undoes its changes when it fails
+			err = m.txManager.WithinSavepoint(ctx, func(ctx context.Context) error {
+				c.SetRequest(req.WithContext(ctx))
+				res.Writer = buffer
+				if err := next(c); err != nil {
+					c.Error(err)
+				}
+				res.Writer = writer
+				c.SetRequest(req)
+
+				if buffer.status >= http.StatusBadRequest {
+					return errRequestFailed
+				}
+				return nil
+			})
+			if err != nil && !errors.Is(err, errRequestFailed) {
+				return err
+			}
+
+			if !storable(buffer.status) {
+				return errNotStored
+			}
+
+			key.Status = buffer.status
+			key.ContentType = buffer.header.Get(echo.HeaderContentType)
+			key.Body = buffer.body.Bytes()
+			return m.store.SaveResponse(ctx, key)
+		})
+		// Whatever was buffered is sent below or replaced by the error
+		res.Committed = false
+		res.Size = 0
+		if err != nil && !errors.Is(err, errNotStored) {
+			writer.Header().Del(echo.HeaderContentType)
+			writer.Header().Del(echo.HeaderContentLength)
+			return err
+		}
+
+		status, body := buffer.status, buffer.body.Bytes()
+		if replayed {
+			logger.FromContext(req.Context()).Infof("Replaying response %d of idempotency key %s of %s", key.Status, key.Key, key.OwnerID)
+			res.Header().Set(IDEMPOTENT_REPLAYED_HEADER, "true")
+			if key.ContentType != "" {
+				res.Header().Set(echo.HeaderContentType, key.ContentType)
+			}
+			status, body = key.Status, key.Body
+		}
+
+		res.WriteHeader(status)
+		_, err = res.Write(body)
+		return err
+	}
+}
+
+// requestHash tells the retry of a request from another request reusing
+// its key.
+func requestHash(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Run prunes expired keys every interval until ctx is cancelled.
+func (m *Idempotency) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := m.store.DeleteExpired(ctx); err != nil {
+				logger.FromContext(ctx).Errorf("Idempotency - DeleteExpired: %v", err)
+			}
+		}
+	}
+}
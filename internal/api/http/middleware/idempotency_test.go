@@ -0,0 +1,314 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/errorhandler"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware/mocks"
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type (
+	txKey        struct{}
+	savepointKey struct{}
+)
+
+func TestIdempotency(t *testing.T) {
+	const (
+		body    = `{"pvzId":"c0a7dd3d-6ec0-4ff4-a5a4-0c7cd1e3f1e5","type":"обувь"}`
+		created = `{"id":"1"}`
+	)
+	ownerID := uuid.New()
+	arbitraryErr := errors.New("arbitrary error")
+
+	type MockBehavior func(tx *mocks.MockIdempotencyTransactor, store *mocks.MockIdempotencyStore)
+
+	for _, tc := range []struct {
+		name         string
+		key          []string
+		body         string
+		handlerErr   error
+		mockBehavior MockBehavior
+		wantCalls    int
+		wantStatus   int
+		wantBody     string
+		wantReplayed bool
+	}{
+		{
+			name:         "no key",
+			body:         body,
+			mockBehavior: func(tx *mocks.MockIdempotencyTransactor, store *mocks.MockIdempotencyStore) {},
+			wantCalls:    1,
+			wantStatus:   http.StatusCreated,
+			wantBody:     created,
+		},
+		{
+			name: "new key",
+			key:  []string{"key-1"},
+			body: body,
+			mockBehavior: func(tx *mocks.MockIdempotencyTransactor, store *mocks.MockIdempotencyStore) {
+				tx.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(withinTransaction)
+				tx.EXPECT().WithinSavepoint(gomock.Any(), gomock.Any()).DoAndReturn(withinSavepoint)
+				store.EXPECT().Acquire(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, key entity.IdempotencyKey) (entity.IdempotencyKey, bool, error) {
+						assert.Equal(t, ownerID, key.OwnerID)
+						assert.Equal(t, "key-1", key.Key)
+						assert.WithinDuration(t, time.Now().Add(time.Hour), key.ExpiresAt, time.Minute)
+						return key, false, nil
+					})
+				store.EXPECT().SaveResponse(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, key entity.IdempotencyKey) error {
+						assert.Equal(t, true, ctx.Value(txKey{}), "response must be saved in the transaction")
+						assert.Equal(t, http.StatusCreated, key.Status)
+						assert.Equal(t, echo.MIMEApplicationJSON, key.ContentType)
+						assert.JSONEq(t, created, string(key.Body))
+						return nil
+					})
+			},
+			wantCalls:  1,
+			wantStatus: http.StatusCreated,
+			wantBody:   created,
+		},
+		{
+			name: "retry replays the stored response",
+			key:  []string{"key-1"},
+			body: body,
+			mockBehavior: func(tx *mocks.MockIdempotencyTransactor, store *mocks.MockIdempotencyStore) {
+				tx.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(withinTransaction)
+				store.EXPECT().Acquire(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, key entity.IdempotencyKey) (entity.IdempotencyKey, bool, error) {
+						key.Status = http.StatusCreated
+						key.ContentType = echo.MIMEApplicationJSON
+						key.Body = []byte(created)
+						return key, true, nil
+					})
+			},
+			wantCalls:    0,
+			wantStatus:   http.StatusCreated,
+			wantBody:     created,
+			wantReplayed: true,
+		},
+		{
+			name: "key reused with another body",
+			key:  []string{"key-1"},
+			body: body,
+			mockBehavior: func(tx *mocks.MockIdempotencyTransactor, store *mocks.MockIdempotencyStore) {
+				tx.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(withinTransaction)
+				store.EXPECT().Acquire(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, key entity.IdempotencyKey) (entity.IdempotencyKey, bool, error) {
+						key.RequestHash = strings.Repeat("0", 64)
+						key.Status = http.StatusCreated
+						return key, true, nil
+					})
+			},
+			wantCalls:  0,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody:   `{"code":"idempotency_key_reused","message":"idempotency key was used for another request"}`,
+		},
+		{
+			name:       "bad requests are stored",
+			key:        []string{"key-1"},
+			body:       body,
+			handlerErr: echo.NewHTTPError(http.StatusBadRequest, "bad request"),
+			mockBehavior: func(tx *mocks.MockIdempotencyTransactor, store *mocks.MockIdempotencyStore) {
+				tx.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(withinTransaction)
+				tx.EXPECT().WithinSavepoint(gomock.Any(), gomock.Any()).DoAndReturn(withinSavepoint)
+				store.EXPECT().Acquire(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, key entity.IdempotencyKey) (entity.IdempotencyKey, bool, error) {
+						return key, false, nil
+					})
+				store.EXPECT().SaveResponse(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, key entity.IdempotencyKey) error {
+						assert.Equal(t, http.StatusBadRequest, key.Status)
+						return nil
+					})
+			},
+			wantCalls:  1,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"code":"bad_request","message":"bad request"}`,
+		},
+		{
+			name:       "server errors are rolled back",
+			key:        []string{"key-1"},
+			body:       body,
+			handlerErr: arbitraryErr,
+			mockBehavior: func(tx *mocks.MockIdempotencyTransactor, store *mocks.MockIdempotencyStore) {
+				tx.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(withinTransaction)
+				tx.EXPECT().WithinSavepoint(gomock.Any(), gomock.Any()).DoAndReturn(withinSavepoint)
+				store.EXPECT().Acquire(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, key entity.IdempotencyKey) (entity.IdempotencyKey, bool, error) {
+						return key, false, nil
+					})
+			},
+			wantCalls:  1,
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `{"code":"internal_server_error","message":"Internal Server Error"}`,
+		},
+		{
+			name:       "unprocessable requests are stored",
+			key:        []string{"key-1"},
+			body:       body,
+			handlerErr: echo.NewHTTPError(http.StatusUnprocessableEntity, "unprocessable"),
+			mockBehavior: func(tx *mocks.MockIdempotencyTransactor, store *mocks.MockIdempotencyStore) {
+				tx.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(withinTransaction)
+				tx.EXPECT().WithinSavepoint(gomock.Any(), gomock.Any()).DoAndReturn(withinSavepoint)
+				store.EXPECT().Acquire(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, key entity.IdempotencyKey) (entity.IdempotencyKey, bool, error) {
+						return key, false, nil
+					})
+				store.EXPECT().SaveResponse(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, key entity.IdempotencyKey) error {
+						assert.Nil(t, ctx.Value(savepointKey{}), "response must be saved outside the rolled back savepoint")
+						assert.Equal(t, http.StatusUnprocessableEntity, key.Status)
+						return nil
+					})
+			},
+			wantCalls:  1,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody:   `{"code":"unprocessable_entity","message":"unprocessable"}`,
+		},
+		{
+			name:       "other client errors release the key",
+			key:        []string{"key-1"},
+			body:       body,
+			handlerErr: echo.NewHTTPError(http.StatusNotFound, "not found"),
+			mockBehavior: func(tx *mocks.MockIdempotencyTransactor, store *mocks.MockIdempotencyStore) {
+				tx.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(withinTransaction)
+				tx.EXPECT().WithinSavepoint(gomock.Any(), gomock.Any()).DoAndReturn(withinSavepoint)
+				store.EXPECT().Acquire(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, key entity.IdempotencyKey) (entity.IdempotencyKey, bool, error) {
+						return key, false, nil
+					})
+			},
+			wantCalls:  1,
+			wantStatus: http.StatusNotFound,
+			wantBody:   `{"code":"not_found","message":"not found"}`,
+		},
+		{
+			name:       "conflicts release the key",
+			key:        []string{"key-1"},
+			body:       body,
+			handlerErr: echo.NewHTTPError(http.StatusConflict, "conflict"),
+			mockBehavior: func(tx *mocks.MockIdempotencyTransactor, store *mocks.MockIdempotencyStore) {
+				tx.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(withinTransaction)
+				tx.EXPECT().WithinSavepoint(gomock.Any(), gomock.Any()).DoAndReturn(withinSavepoint)
+				store.EXPECT().Acquire(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, key entity.IdempotencyKey) (entity.IdempotencyKey, bool, error) {
+						return key, false, nil
+					})
+			},
+			wantCalls:  1,
+			wantStatus: http.StatusConflict,
+			wantBody:   `{"code":"conflict","message":"conflict"}`,
+		},
+		{
+			name: "response not saved",
+			key:  []string{"key-1"},
+			body: body,
+			mockBehavior: func(tx *mocks.MockIdempotencyTransactor, store *mocks.MockIdempotencyStore) {
+				tx.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(withinTransaction)
+				tx.EXPECT().WithinSavepoint(gomock.Any(), gomock.Any()).DoAndReturn(withinSavepoint)
+				store.EXPECT().Acquire(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, key entity.IdempotencyKey) (entity.IdempotencyKey, bool, error) {
+						return key, false, nil
+					})
+				store.EXPECT().SaveResponse(gomock.Any(), gomock.Any()).Return(arbitraryErr)
+			},
+			wantCalls:  1,
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `{"code":"internal_server_error","message":"Internal Server Error"}`,
+		},
+		{
+			name:         "key too long",
+			key:          []string{strings.Repeat("k", 256)},
+			body:         body,
+			mockBehavior: func(tx *mocks.MockIdempotencyTransactor, store *mocks.MockIdempotencyStore) {},
+			wantCalls:    0,
+			wantStatus:   http.StatusBadRequest,
+			wantBody:     `{"code":"invalid_idempotency_key","message":"idempotency key must be 1 to 255 characters long"}`,
+		},
+		{
+			name:         "several keys",
+			key:          []string{"key-1", "key-2"},
+			body:         body,
+			mockBehavior: func(tx *mocks.MockIdempotencyTransactor, store *mocks.MockIdempotencyStore) {},
+			wantCalls:    0,
+			wantStatus:   http.StatusBadRequest,
+			wantBody:     `{"code":"invalid_idempotency_key","message":"idempotency key must be 1 to 255 characters long"}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			tx := mocks.NewMockIdempotencyTransactor(ctrl)
+			store := mocks.NewMockIdempotencyStore(ctrl)
+			tc.mockBehavior(tx, store)
+
+			calls := 0
+			handler := func(c echo.Context) error {
+				calls++
+				if tc.handlerErr != nil {
+					return tc.handlerErr
+				}
+				if len(tc.key) > 0 {
+					assert.Equal(t, true, c.Request().Context().Value(txKey{}), "handler must run in the transaction")
+					assert.Equal(t, true, c.Request().Context().Value(savepointKey{}), "handler must run in a savepoint")
+				}
+				return c.JSONBlob(http.StatusCreated, []byte(created))
+			}
+
+			e := echo.New()
+			e.HTTPErrorHandler = errorhandler.Handle
+			e.POST("/products", handler, authenticated(ownerID), middleware.NewIdempotency(tx, store, time.Hour).Middleware)
+
+			req := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			for _, key := range tc.key {
+				req.Header.Add(middleware.IDEMPOTENCY_KEY_HEADER, key)
+			}
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.wantCalls, calls)
+			assert.Equal(t, tc.wantStatus, rec.Code)
+			assert.JSONEq(t, tc.wantBody, rec.Body.String())
+			if tc.wantReplayed {
+				assert.Equal(t, "true", rec.Header().Get(middleware.IDEMPOTENT_REPLAYED_HEADER))
+			} else {
+				assert.Empty(t, rec.Header().Get(middleware.IDEMPOTENT_REPLAYED_HEADER))
+			}
+		})
+	}
+}
+
+// withinTransaction marks ctx the way a transaction would
+func withinTransaction(ctx context.Context, fn func(context.Context) error) error {
+	return fn(context.WithValue(ctx, txKey{}, true))
+}
+
+// withinSavepoint marks ctx the way a savepoint would
+func withinSavepoint(ctx context.Context, fn func(context.Context) error) error {
+	return fn(context.WithValue(ctx, savepointKey{}, true))
+}
+
+func authenticated(userID uuid.UUID) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims := &auth.TokenClaims{UserID: userID, Role: entity.RoleEmployee}
+			c.SetRequest(c.Request().WithContext(middleware.NewUserContext(c.Request().Context(), claims)))
+			return next(c)
+		}
+	}
+}
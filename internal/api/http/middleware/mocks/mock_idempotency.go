@@ -0,0 +1,139 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: idempotency.go
+//
+// Generated by this command:
+//
+//	mockgen -source=idempotency.go -destination=mocks/mock_idempotency.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/4udiwe/avito-pvz/internal/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIdempotencyTransactor is a mock of IdempotencyTransactor interface.
+type MockIdempotencyTransactor struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyTransactorMockRecorder
+	isgomock struct{}
+}
+
+// MockIdempotencyTransactorMockRecorder is the mock recorder for MockIdempotencyTransactor.
+type MockIdempotencyTransactorMockRecorder struct {
+	mock *MockIdempotencyTransactor
+}
+
+// NewMockIdempotencyTransactor creates a new mock instance.
+func NewMockIdempotencyTransactor(ctrl *gomock.Controller) *MockIdempotencyTransactor {
+	mock := &MockIdempotencyTransactor{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyTransactorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencyTransactor) EXPECT() *MockIdempotencyTransactorMockRecorder {
+	return m.recorder
+}
+
+// WithinSavepoint mocks base method.
+func (m *MockIdempotencyTransactor) WithinSavepoint(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinSavepoint", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithinSavepoint indicates an expected call of WithinSavepoint.
+func (mr *MockIdempotencyTransactorMockRecorder) WithinSavepoint(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinSavepoint", reflect.TypeOf((*MockIdempotencyTransactor)(nil).WithinSavepoint), ctx, fn)
+}
+
+// WithinTransaction mocks base method.
+func (m *MockIdempotencyTransactor) WithinTransaction(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinTransaction", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithinTransaction indicates an expected call of WithinTransaction.
+func (mr *MockIdempotencyTransactorMockRecorder) WithinTransaction(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinTransaction", reflect.TypeOf((*MockIdempotencyTransactor)(nil).WithinTransaction), ctx, fn)
+}
+
+// MockIdempotencyStore is a mock of IdempotencyStore interface.
+type MockIdempotencyStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockIdempotencyStoreMockRecorder is the mock recorder for MockIdempotencyStore.
+type MockIdempotencyStoreMockRecorder struct {
+	mock *MockIdempotencyStore
+}
+
+// NewMockIdempotencyStore creates a new mock instance.
+func NewMockIdempotencyStore(ctrl *gomock.Controller) *MockIdempotencyStore {
+	mock := &MockIdempotencyStore{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencyStore) EXPECT() *MockIdempotencyStoreMockRecorder {
+	return m.recorder
+}
+
+// Acquire mocks base method.
+func (m *MockIdempotencyStore) Acquire(ctx context.Context, key entity.IdempotencyKey) (entity.IdempotencyKey, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Acquire", ctx, key)
+	ret0, _ := ret[0].(entity.IdempotencyKey)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Acquire indicates an expected call of Acquire.
+func (mr *MockIdempotencyStoreMockRecorder) Acquire(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Acquire", reflect.TypeOf((*MockIdempotencyStore)(nil).Acquire), ctx, key)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockIdempotencyStore) DeleteExpired(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockIdempotencyStoreMockRecorder) DeleteExpired(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockIdempotencyStore)(nil).DeleteExpired), ctx)
+}
+
+// SaveResponse mocks base method.
+func (m *MockIdempotencyStore) SaveResponse(ctx context.Context, key entity.IdempotencyKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveResponse", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveResponse indicates an expected call of SaveResponse.
+func (mr *MockIdempotencyStoreMockRecorder) SaveResponse(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveResponse", reflect.TypeOf((*MockIdempotencyStore)(nil).SaveResponse), ctx, key)
+}
@@ -0,0 +1,158 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/labstack/echo/v4"
+)
+
+// OpenAPIValidator checks the operations described in api/swagger.yaml
+// against it; other routes pass unchecked. Invalid requests get a 400. It
+// belongs after AuthMiddleware, which answers unauthenticated requests
+// before their input is looked at.
+// Invalid responses are logged and replaced with a 500, so tests running
+// with response validation fail on any drift from the description.
+type OpenAPIValidator struct {
+	router            routers.Router
+	validateRequests  bool
+	validateResponses bool
+}
+
+func NewOpenAPIValidator(doc *openapi3.T, validateRequests, validateResponses bool) (*OpenAPIValidator, error) {
+	// Schema errors would otherwise quote the whole schema to the client
+	openapi3.SchemaErrorDetailsDisabled = true
+
+	router, err := legacy.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("OpenAPIValidator - NewRouter: %w", err)
+	}
+
+	return &OpenAPIValidator{
+		router:            router,
+		validateRequests:  validateRequests,
+		validateResponses: validateResponses,
+	}, nil
+}
+
+func (v *OpenAPIValidator) Middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+
+		route, pathParams, err := v.router.FindRoute(req)
+		if err != nil {
+			return next(c)
+		}
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+			Options: &openapi3filter.Options{
+				// Authentication is up to AuthMiddleware
+				AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+			},
+		}
+
+		if v.validateRequests {
+			if err = openapi3filter.ValidateRequest(req.Context(), input); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, requestErrorMessage(err)).SetInternal(err)
+			}
+		}
+
+		if !v.validateResponses {
+			return next(c)
+		}
+		return v.validateResponse(c, next, input)
+	}
+}
+
+// validateResponse renders the response, errors included, into a buffer and
+// sends it only when it matches the description.
+func (v *OpenAPIValidator) validateResponse(c echo.Context, next echo.HandlerFunc, input *openapi3filter.RequestValidationInput) error {
+	res := c.Response()
+	writer := res.Writer
+	buffer := &responseBuffer{header: writer.Header(), status: http.StatusOK}
+	res.Writer = buffer
+
+	if err := next(c); err != nil {
+		c.Error(err)
+	}
+	res.Writer = writer
+
+	err := openapi3filter.ValidateResponse(c.Request().Context(), &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 buffer.status,
+		Header:                 buffer.header,
+		Body:                   io.NopCloser(bytes.NewReader(buffer.body.Bytes())),
+		Options:                &openapi3filter.Options{IncludeResponseStatus: true},
+	})
+	if err != nil {
+		logger.FromContext(c.Request().Context()).Errorf("HTTP %s %s response does not match api/swagger.yaml: %v",
+			c.Request().Method, c.Path(), err)
+
+		// Let the error handler write the 500 from scratch
+		res.Committed = false
+		res.Size = 0
+		writer.Header().Del(echo.HeaderContentType)
+		writer.Header().Del(echo.HeaderContentLength)
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+
+	writer.WriteHeader(buffer.status)
+	_, err = writer.Write(buffer.body.Bytes())
+	return err
+}
+
+// requestErrorMessage keeps the reason of a request error without the
+// operation and schema details.
+func requestErrorMessage(err error) string {
+	var requestErr *openapi3filter.RequestError
+	if !errors.As(err, &requestErr) {
+		return err.Error()
+	}
+
+	var schemaErr *openapi3.SchemaError
+	switch {
+	case requestErr.Parameter != nil && errors.As(requestErr.Err, &schemaErr):
+		return fmt.Sprintf("parameter %s: %s", requestErr.Parameter.Name, schemaErr.Reason)
+	case requestErr.Parameter != nil:
+		return fmt.Sprintf("parameter %s: %s", requestErr.Parameter.Name, requestErr.Reason)
+	case errors.As(requestErr.Err, &schemaErr):
+		if field := strings.Join(schemaErr.JSONPointer(), "."); field != "" {
+			return fmt.Sprintf("field %s: %s", field, schemaErr.Reason)
+		}
+		return "request body: " + schemaErr.Reason
+	case requestErr.Reason != "":
+		return "request body: " + requestErr.Reason
+	default:
+		return "request body: " + requestErr.Err.Error()
+	}
+}
+
+type responseBuffer struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *responseBuffer) Header() http.Header {
+	return b.header
+}
+
+func (b *responseBuffer) WriteHeader(status int) {
+	b.status = status
+}
+
+func (b *responseBuffer) Write(p []byte) (int, error) {
+	return b.body.Write(p)
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/labstack/echo/v4"
+)
+
+type Permissions interface {
+	Allows(role entity.UserRole, permission entity.Permission) bool
+}
+
+// PermissionMiddleware checks that the role of the authenticated user is
+// granted a permission. Service accounts have no role and are limited to
+// the scopes of their API key. It must run after AuthMiddleware.
+type PermissionMiddleware struct {
+	permissions Permissions
+}
+
+func NewPermissionMiddleware(permissions Permissions) *PermissionMiddleware {
+	return &PermissionMiddleware{permissions: permissions}
+}
+
+func (m *PermissionMiddleware) Require(permission entity.Permission) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims, err := GetUserFromContext(c)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, "Unauthorized")
+			}
+
+			if !m.allows(claims, permission) {
+				return echo.NewHTTPError(http.StatusForbidden, "Access denied")
+			}
+
+			return next(c)
+		}
+	}
+}
+
+func (m *PermissionMiddleware) allows(claims *auth.TokenClaims, permission entity.Permission) bool {
+	if claims.IsServiceAccount() {
+		return slices.Contains(claims.Scopes, permission)
+	}
+	return m.permissions.Allows(claims.Role, permission)
+}
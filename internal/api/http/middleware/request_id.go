@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/4udiwe/avito-pvz/pkg/requestid"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// RequestID puts the X-Request-ID of the request, or a generated one, into
+// the request context and echoes it in the response. The context also gets
+// a logger tagging every line with the ID, the route and the point of
+// /pvz/{pvzId} routes, for the services and repositories to log through.
+func RequestID(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id := requestid.Resolve(c.Request().Header.Get(requestid.Header))
+
+		c.Response().Header().Set(requestid.Header, id)
+
+		fields := logrus.Fields{
+			logger.RequestID: id,
+			logger.Method:    c.Request().Method,
+			logger.Route:     c.Path(),
+		}
+		if pointID := c.Param("pvzId"); pointID != "" {
+			fields[logger.PointID] = pointID
+		}
+
+		ctx := requestid.NewContext(c.Request().Context(), id)
+		ctx = logger.With(ctx, fields)
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		return next(c)
+	}
+}
@@ -0,0 +1,58 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/4udiwe/avito-pvz/pkg/requestid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		header string
+		wantID func(t *testing.T, id string)
+	}{
+		{
+			name:   "kept from the client",
+			header: "req-1",
+			wantID: func(t *testing.T, id string) { assert.Equal(t, "req-1", id) },
+		},
+		{
+			name:   "generated",
+			wantID: func(t *testing.T, id string) { assert.NotEmpty(t, id) },
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var fields map[string]any
+
+			e := echo.New()
+			e.Use(middleware.RequestID)
+			e.GET("/pvz/:pvzId/cells", func(c echo.Context) error {
+				ctx := c.Request().Context()
+				assert.Equal(t, c.Response().Header().Get(requestid.Header), requestid.FromContext(ctx))
+				fields = logger.FromContext(ctx).Data
+				return c.NoContent(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/pvz/1/cells", nil)
+			if tc.header != "" {
+				req.Header.Set(requestid.Header, tc.header)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			id := rec.Header().Get(requestid.Header)
+			tc.wantID(t, id)
+			assert.Equal(t, id, fields[logger.RequestID])
+			assert.Equal(t, "/pvz/:pvzId/cells", fields[logger.Route])
+			assert.Equal(t, http.MethodGet, fields[logger.Method])
+			assert.Equal(t, "1", fields[logger.PointID])
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var apiVersionRequestsTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "http_api_version_requests_total",
+		Help: "Total number of HTTP requests per API version",
+	},
+	[]string{"version", "deprecated"},
+)
+
+// APIVersion is one mount of a version of the API. The same version may be
+// mounted twice, e.g. v1 under /api/v1 and as the deprecated root aliases.
+type APIVersion struct {
+	Name string
+	// Deprecated mounts answer with Deprecation (RFC 9745) and Sunset
+	// (RFC 8594) headers and link to the same path under Successor.
+	Deprecated   bool
+	DeprecatedAt time.Time
+	Sunset       time.Time
+	Successor    string
+}
+
+func (v APIVersion) Middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	deprecated := strconv.FormatBool(v.Deprecated)
+
+	return func(c echo.Context) error {
+		apiVersionRequestsTotal.WithLabelValues(v.Name, deprecated).Inc()
+
+		if v.Deprecated {
+			header := c.Response().Header()
+			header.Set("Deprecation", fmt.Sprintf("@%d", v.DeprecatedAt.Unix()))
+			header.Set("Sunset", v.Sunset.UTC().Format(http.TimeFormat))
+			if v.Successor != "" {
+				header.Set("Link", fmt.Sprintf(`<%s%s>; rel="successor-version"`, v.Successor, c.Request().URL.Path))
+			}
+		}
+
+		return next(c)
+	}
+}
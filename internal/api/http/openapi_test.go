@@ -0,0 +1,441 @@
+package api_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/delete_product"
+	mock_delete_product "github.com/4udiwe/avito-pvz/internal/api/http/delete_product/mocks"
+	"github.com/4udiwe/avito-pvz/internal/api/http/errorhandler"
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_points"
+	mock_get_points "github.com/4udiwe/avito-pvz/internal/api/http/get_points/mocks"
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_product_history"
+	mock_get_product_history "github.com/4udiwe/avito-pvz/internal/api/http/get_product_history/mocks"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/api/http/patch_reception"
+	mock_patch_reception "github.com/4udiwe/avito-pvz/internal/api/http/patch_reception/mocks"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_dummy_login"
+	mock_post_dummy_login "github.com/4udiwe/avito-pvz/internal/api/http/post_dummy_login/mocks"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_login"
+	mock_post_login "github.com/4udiwe/avito-pvz/internal/api/http/post_login/mocks"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_point"
+	mock_post_point "github.com/4udiwe/avito-pvz/internal/api/http/post_point/mocks"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_product"
+	mock_post_product "github.com/4udiwe/avito-pvz/internal/api/http/post_product/mocks"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_product_status"
+	mock_post_product_status "github.com/4udiwe/avito-pvz/internal/api/http/post_product_status/mocks"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_reception"
+	mock_post_reception "github.com/4udiwe/avito-pvz/internal/api/http/post_reception/mocks"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_register"
+	mock_post_register "github.com/4udiwe/avito-pvz/internal/api/http/post_register/mocks"
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/service/point"
+	productservice "github.com/4udiwe/avito-pvz/internal/service/product"
+	"github.com/4udiwe/avito-pvz/internal/service/user"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var (
+	now       = time.Date(2025, 12, 3, 9, 0, 0, 0, time.UTC)
+	pointID   = uuid.New()
+	reception = entity.Reception{
+		ID:        uuid.New(),
+		PointID:   pointID,
+		CreatedAt: now,
+		Status:    entity.ReceptionStatusClosed,
+		Kind:      entity.ReceptionKindRegular,
+	}
+	product = entity.Product{
+		ID:          uuid.New(),
+		ReceptionID: reception.ID,
+		CreatedAt:   now,
+		Type:        entity.ProductTypeShoes,
+		Status:      entity.ProductStatusReceived,
+	}
+)
+
+type services struct {
+	dummyLogin *mock_post_dummy_login.MockUserService
+	register   *mock_post_register.MockUserService
+	login      *mock_post_login.MockUserService
+	points     *mock_get_points.MockPointService
+	point      *mock_post_point.MockPointService
+	close      *mock_patch_reception.MockReceptionService
+	delete     *mock_delete_product.MockProductService
+	reception  *mock_post_reception.MockReceptionService
+	product    *mock_post_product.MockProductService
+	status     *mock_post_product_status.MockProductService
+	history    *mock_get_product_history.MockProductService
+}
+
+// newServer wires the strict server as the app does, with request and
+// response validation on and the caller already authenticated
+func newServer(t *testing.T, s services) *echo.Echo {
+	t.Helper()
+
+	doc, err := dto.GetSwagger()
+	require.NoError(t, err)
+	validator, err := middleware.NewOpenAPIValidator(doc, true, true)
+	require.NoError(t, err)
+
+	e := echo.New()
+	e.HTTPErrorHandler = errorhandler.Handle
+	e.Use(middleware.ClientInfo)
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims := &auth.TokenClaims{UserID: uuid.New(), Role: entity.RoleEmployee}
+			c.SetRequest(c.Request().WithContext(middleware.NewUserContext(c.Request().Context(), claims)))
+			return next(c)
+		}
+	})
+	e.Use(validator.Middleware)
+
+	dto.RegisterHandlers(e, dto.NewStrictHandler(api.StrictServer{
+		PostDummyLoginHandler:                 post_dummy_login.New(s.dummyLogin),
+		PostRegisterHandler:                   post_register.New(s.register),
+		PostLoginHandler:                      post_login.New(s.login),
+		GetPvzHandler:                         get_points.New(s.points),
+		PostPvzHandler:                        post_point.New(s.point),
+		PostPvzPvzIdCloseLastReceptionHandler: patch_reception.New(s.close),
+		PostPvzPvzIdDeleteLastProductHandler:  delete_product.New(s.delete),
+		PostReceptionsHandler:                 post_reception.New(s.reception),
+		PostProductsHandler:                   post_product.New(s.product),
+		ProductStatusHandler:                  post_product_status.New(s.status),
+		GetProductsProductIdHistoryHandler:    get_product_history.New(s.history),
+	}, nil))
+	return e
+}
+
+// TestOpenAPIContract sends every operation of api/swagger.yaml through
+// the validating middleware: a handler answering other than described
+// gets a 500 instead of the wanted status.
+func TestOpenAPIContract(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		mockBehavior func(s services)
+		wantStatus   int
+	}{
+		{
+			name:   "dummy login",
+			method: http.MethodPost,
+			path:   "/dummyLogin",
+			body:   `{"role":"moderator"}`,
+			mockBehavior: func(s services) {
+				s.dummyLogin.EXPECT().DummyLogin(gomock.Any(), entity.RoleModerator).Return("access", nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "register",
+			method: http.MethodPost,
+			path:   "/register",
+			body:   `{"email":"new@mail.com","password":"Passw0rd!"}`,
+			mockBehavior: func(s services) {
+				s.register.EXPECT().Register(gomock.Any(), "new@mail.com", "Passw0rd!", entity.UserRole(""), "").
+					Return(entity.User{ID: uuid.New(), Email: "new@mail.com", PasswordHash: "hash", Role: entity.RoleEmployee}, nil)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:   "register conflict",
+			method: http.MethodPost,
+			path:   "/register",
+			body:   `{"email":"new@mail.com","password":"Passw0rd!"}`,
+			mockBehavior: func(s services) {
+				s.register.EXPECT().Register(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(entity.User{}, user.ErrUserAlreadyExists)
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:   "login",
+			method: http.MethodPost,
+			path:   "/login",
+			body:   `{"email":"new@mail.com","password":"Passw0rd!"}`,
+			mockBehavior: func(s services) {
+				s.login.EXPECT().Authenticate(gomock.Any(), "new@mail.com", "Passw0rd!", gomock.Any()).
+					Return(&user.LoginResult{Tokens: &auth.Tokens{AccessToken: "access", RefreshToken: "refresh", ExpiresIn: 900}}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "login mfa required",
+			method: http.MethodPost,
+			path:   "/login",
+			body:   `{"email":"new@mail.com","password":"Passw0rd!"}`,
+			mockBehavior: func(s services) {
+				s.login.EXPECT().Authenticate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&user.LoginResult{MFA: &user.MFAPending{Token: "mfa", ExpiresAt: now}}, nil)
+			},
+			wantStatus: http.StatusAccepted,
+		},
+		{
+			name:   "login invalid credentials",
+			method: http.MethodPost,
+			path:   "/login",
+			body:   `{"email":"new@mail.com","password":"wrong"}`,
+			mockBehavior: func(s services) {
+				s.login.EXPECT().Authenticate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, user.ErrInvalidCredentials)
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "list points",
+			method: http.MethodGet,
+			path:   "/pvz?startDate=2025-12-01T00:00:00Z&page=1&limit=30",
+			mockBehavior: func(s services) {
+				s.points.EXPECT().GetPointsFullInfo(gomock.Any(), gomock.Any()).Return([]entity.PointFullInfo{{
+					Point:      entity.Point{ID: pointID, City: "Москва", CreatedAt: now},
+					Receptions: []entity.ReceptionWithProducts{{Reception: reception, Products: []entity.Product{product}}},
+				}}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "create point",
+			method: http.MethodPost,
+			path:   "/pvz",
+			body:   `{"city":"Казань"}`,
+			mockBehavior: func(s services) {
+				s.point.EXPECT().CreatePoint(gomock.Any(), gomock.Any(), "Казань").
+					Return(entity.Point{ID: pointID, City: "Казань", CreatedAt: now}, nil)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:   "create point in unknown city",
+			method: http.MethodPost,
+			path:   "/pvz",
+			body:   `{"city":"Казань"}`,
+			mockBehavior: func(s services) {
+				s.point.EXPECT().CreatePoint(gomock.Any(), gomock.Any(), gomock.Any()).Return(entity.Point{}, point.ErrNoCityFound)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "close last reception",
+			method: http.MethodPost,
+			path:   "/pvz/" + pointID.String() + "/close_last_reception",
+			mockBehavior: func(s services) {
+				s.close.EXPECT().CloseReception(gomock.Any(), gomock.Any(), pointID).Return(reception, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "delete last product",
+			method: http.MethodPost,
+			path:   "/pvz/" + pointID.String() + "/delete_last_product",
+			mockBehavior: func(s services) {
+				s.delete.EXPECT().DeleteLastProductFromReception(gomock.Any(), gomock.Any(), pointID).Return(nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "open reception",
+			method: http.MethodPost,
+			path:   "/receptions",
+			body:   `{"pvzId":"` + pointID.String() + `"}`,
+			mockBehavior: func(s services) {
+				s.reception.EXPECT().OpenReception(gomock.Any(), gomock.Any(), pointID, entity.ReceptionKindRegular).Return(reception, nil)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:   "add product",
+			method: http.MethodPost,
+			path:   "/products",
+			body:   `{"type":"обувь","pvzId":"` + pointID.String() + `"}`,
+			mockBehavior: func(s services) {
+				s.product.EXPECT().AddProduct(gomock.Any(), gomock.Any(), pointID, entity.ProductTypeShoes, "").Return(product, nil)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:   "store product",
+			method: http.MethodPost,
+			path:   "/products/" + product.ID.String() + "/store",
+			mockBehavior: func(s services) {
+				stored := product
+				stored.Status = entity.ProductStatusStored
+				s.status.EXPECT().ChangeStatus(gomock.Any(), product.ID, entity.ProductStatusStored, gomock.Any(), "").Return(stored, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "issue product",
+			method: http.MethodPost,
+			path:   "/products/" + product.ID.String() + "/issue",
+			body:   `{}`,
+			mockBehavior: func(s services) {
+				issued := product
+				issued.Status = entity.ProductStatusIssued
+				s.status.EXPECT().ChangeStatus(gomock.Any(), product.ID, entity.ProductStatusIssued, gomock.Any(), "").Return(issued, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "return product",
+			method: http.MethodPost,
+			path:   "/products/" + product.ID.String() + "/return",
+			body:   `{"reason":"customer refused"}`,
+			mockBehavior: func(s services) {
+				returned := product
+				returned.Status = entity.ProductStatusReturned
+				s.status.EXPECT().ChangeStatus(gomock.Any(), product.ID, entity.ProductStatusReturned, gomock.Any(), "customer refused").Return(returned, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "write off product without reason",
+			method: http.MethodPost,
+			path:   "/products/" + product.ID.String() + "/write_off",
+			body:   `{}`,
+			mockBehavior: func(s services) {
+				s.status.EXPECT().ChangeStatus(gomock.Any(), product.ID, entity.ProductStatusWrittenOff, gomock.Any(), "").Return(entity.Product{}, productservice.ErrReasonRequired)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid transition",
+			method: http.MethodPost,
+			path:   "/products/" + product.ID.String() + "/issue",
+			mockBehavior: func(s services) {
+				s.status.EXPECT().ChangeStatus(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(entity.Product{}, productservice.ErrInvalidTransition)
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:   "product history",
+			method: http.MethodGet,
+			path:   "/products/" + product.ID.String() + "/history",
+			mockBehavior: func(s services) {
+				from := entity.ProductStatusReceived
+				s.history.EXPECT().GetHistory(gomock.Any(), product.ID).Return([]entity.ProductHistory{
+					{ID: uuid.New(), ProductID: product.ID, ToStatus: entity.ProductStatusReceived, ActorID: uuid.New(), CreatedAt: now},
+					{ID: uuid.New(), ProductID: product.ID, FromStatus: &from, ToStatus: entity.ProductStatusStored, Reason: "shelf", ActorID: uuid.New(), CreatedAt: now},
+				}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:         "reason too long",
+			method:       http.MethodPost,
+			path:         "/products/" + product.ID.String() + "/return",
+			body:         `{"reason":"` + strings.Repeat("r", 1025) + `"}`,
+			mockBehavior: func(s services) {},
+			wantStatus:   http.StatusBadRequest,
+		},
+		{
+			name:         "unknown role",
+			method:       http.MethodPost,
+			path:         "/dummyLogin",
+			body:         `{"role":"admin"}`,
+			mockBehavior: func(s services) {},
+			wantStatus:   http.StatusBadRequest,
+		},
+		{
+			name:         "missing required field",
+			method:       http.MethodPost,
+			path:         "/products",
+			body:         `{"type":"обувь"}`,
+			mockBehavior: func(s services) {},
+			wantStatus:   http.StatusBadRequest,
+		},
+		{
+			name:         "limit above maximum",
+			method:       http.MethodGet,
+			path:         "/pvz?limit=31",
+			mockBehavior: func(s services) {},
+			wantStatus:   http.StatusBadRequest,
+		},
+		{
+			name:         "barcode too long",
+			method:       http.MethodPost,
+			path:         "/products",
+			body:         `{"type":"обувь","pvzId":"` + pointID.String() + `","barcode":"` + strings.Repeat("1", 65) + `"}`,
+			mockBehavior: func(s services) {},
+			wantStatus:   http.StatusBadRequest,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			s := services{
+				dummyLogin: mock_post_dummy_login.NewMockUserService(ctrl),
+				register:   mock_post_register.NewMockUserService(ctrl),
+				login:      mock_post_login.NewMockUserService(ctrl),
+				points:     mock_get_points.NewMockPointService(ctrl),
+				point:      mock_post_point.NewMockPointService(ctrl),
+				close:      mock_patch_reception.NewMockReceptionService(ctrl),
+				delete:     mock_delete_product.NewMockProductService(ctrl),
+				reception:  mock_post_reception.NewMockReceptionService(ctrl),
+				product:    mock_post_product.NewMockProductService(ctrl),
+				status:     mock_post_product_status.NewMockProductService(ctrl),
+				history:    mock_get_product_history.NewMockProductService(ctrl),
+			}
+			tc.mockBehavior(s)
+
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			if tc.body != "" {
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			}
+			rec := httptest.NewRecorder()
+
+			newServer(t, s).ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.wantStatus, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+// TestOpenAPIResponseDrift checks that a response other than described is
+// caught: registration used to answer with tokens.
+func TestOpenAPIResponseDrift(t *testing.T) {
+	doc, err := dto.GetSwagger()
+	require.NoError(t, err)
+	validator, err := middleware.NewOpenAPIValidator(doc, true, true)
+	require.NoError(t, err)
+
+	e := echo.New()
+	e.HTTPErrorHandler = errorhandler.Handle
+	e.Use(validator.Middleware)
+	register := func(c echo.Context) error {
+		return c.JSON(http.StatusCreated, auth.Tokens{AccessToken: "access", RefreshToken: "refresh", ExpiresIn: 900})
+	}
+	e.POST("/api/v1/register", register)
+	e.POST("/register", register)
+	e.GET("/undescribed", func(c echo.Context) error {
+		return c.JSON(http.StatusTeapot, map[string]string{"any": "thing"})
+	})
+
+	// Both the versioned path and its root alias are checked
+	for _, path := range []string{"/api/v1/register", "/register"} {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"email":"new@mail.com","password":"Passw0rd!"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code, path)
+		assert.JSONEq(t, `{"code":"internal_server_error","message":"Internal Server Error"}`, rec.Body.String(), path)
+	}
+
+	// Routes missing from the description pass unchecked
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/undescribed", nil))
+	assert.Equal(t, http.StatusTeapot, rec.Code)
+}
@@ -3,11 +3,12 @@ package patch_reception
 import (
 	"context"
 
+	"github.com/4udiwe/avito-pvz/internal/entity"
 	"github.com/google/uuid"
 )
 
 //go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
 
 type ReceptionService interface {
-	CloseReception(ctx context.Context, pointID uuid.UUID) error
+	CloseReception(ctx context.Context, actorID uuid.UUID, pointID uuid.UUID) (entity.Reception, error)
 }
@@ -1,13 +1,14 @@
 package patch_reception
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
 	api "github.com/4udiwe/avito-pvz/internal/api/http"
-	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/dto"
 	service "github.com/4udiwe/avito-pvz/internal/service/reception"
-	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
@@ -15,34 +16,35 @@ type handler struct {
 	s ReceptionService
 }
 
-func New(receptionService ReceptionService) api.Handler {
-	return decorator.NewBindAndValidateDerocator(&handler{s: receptionService})
+func New(receptionService ReceptionService) api.PostPvzPvzIdCloseLastReceptionHandler {
+	return &handler{s: receptionService}
 }
 
-type Request struct {
-	PointID uuid.UUID `param:"pvzId" validate:"required"`
-}
+func (h *handler) PostPvzPvzIdCloseLastReception(
+	ctx context.Context,
+	request dto.PostPvzPvzIdCloseLastReceptionRequestObject,
+) (dto.PostPvzPvzIdCloseLastReceptionResponseObject, error) {
+	claims, err := middleware.UserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-func (h *handler) Handle(
-	ctx echo.Context,
-	in Request,
-) error {
-	err := h.s.CloseReception(ctx.Request().Context(), in.PointID)
+	reception, err := h.s.CloseReception(ctx, claims.UserID, request.PvzId)
 
 	if err != nil {
 		if errors.Is(err, service.ErrNoPointFound) {
-			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+			return nil, echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
 		}
 		if errors.Is(err, service.ErrNoReceptionFound) {
-			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+			return nil, echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
 		}
 		if errors.Is(err, service.ErrLastReceptionAlreadyClosed) {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
 		}
 		if errors.Is(err, service.ErrCannotCloseEmptyReception) {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
 		}
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return nil, echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
 	}
-	return ctx.NoContent(http.StatusAccepted)
+	return dto.PostPvzPvzIdCloseLastReception200JSONResponse(*dto.EntityReceptionToDTO(&reception)), nil
 }
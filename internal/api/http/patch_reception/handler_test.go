@@ -1,15 +1,22 @@
 package patch_reception_test
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
 	"github.com/4udiwe/avito-pvz/internal/api/http/patch_reception"
 	mock_patch_reception "github.com/4udiwe/avito-pvz/internal/api/http/patch_reception/mocks"
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
 	service "github.com/4udiwe/avito-pvz/internal/service/reception"
-	"github.com/4udiwe/avito-pvz/pkg/validator"
 	"github.com/go-playground/assert/v2"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -19,10 +26,20 @@ import (
 
 func TestHandle(t *testing.T) {
 	var (
+		actorID      = uuid.New()
 		arbitraryErr = errors.New("arbitrary error")
 		pointID      = uuid.New()
 	)
 
+	reception := entity.Reception{
+		ID:        uuid.New(),
+		PointID:   pointID,
+		CreatedAt: time.Date(2025, 12, 3, 9, 0, 0, 0, time.UTC),
+		Status:    entity.ReceptionStatusClosed,
+		Kind:      entity.ReceptionKindRegular,
+	}
+	responseJSON, _ := json.Marshal(dto.EntityReceptionToDTO(&reception))
+
 	type MockBehavior func(s *mock_patch_reception.MockReceptionService)
 
 	for _, tc := range []struct {
@@ -34,15 +51,15 @@ func TestHandle(t *testing.T) {
 		{
 			name: "success",
 			mockBehavior: func(s *mock_patch_reception.MockReceptionService) {
-				s.EXPECT().CloseReception(gomock.Any(), pointID).Return(nil).Times(1)
+				s.EXPECT().CloseReception(gomock.Any(), actorID, pointID).Return(reception, nil).Times(1)
 			},
-			wantStatus: http.StatusAccepted,
-			wantBody:   "",
+			wantStatus: http.StatusOK,
+			wantBody:   string(responseJSON),
 		},
 		{
 			name: "no point found",
 			mockBehavior: func(s *mock_patch_reception.MockReceptionService) {
-				s.EXPECT().CloseReception(gomock.Any(), pointID).Return(service.ErrNoPointFound).Times(1)
+				s.EXPECT().CloseReception(gomock.Any(), actorID, pointID).Return(entity.Reception{}, service.ErrNoPointFound).Times(1)
 			},
 			wantStatus: http.StatusNotFound,
 			wantBody:   service.ErrNoPointFound.Error(),
@@ -50,7 +67,7 @@ func TestHandle(t *testing.T) {
 		{
 			name: "no reception found",
 			mockBehavior: func(s *mock_patch_reception.MockReceptionService) {
-				s.EXPECT().CloseReception(gomock.Any(), pointID).Return(service.ErrNoReceptionFound).Times(1)
+				s.EXPECT().CloseReception(gomock.Any(), actorID, pointID).Return(entity.Reception{}, service.ErrNoReceptionFound).Times(1)
 			},
 			wantStatus: http.StatusNotFound,
 			wantBody:   service.ErrNoReceptionFound.Error(),
@@ -58,7 +75,7 @@ func TestHandle(t *testing.T) {
 		{
 			name: "last reception already closed",
 			mockBehavior: func(s *mock_patch_reception.MockReceptionService) {
-				s.EXPECT().CloseReception(gomock.Any(), pointID).Return(service.ErrLastReceptionAlreadyClosed).Times(1)
+				s.EXPECT().CloseReception(gomock.Any(), actorID, pointID).Return(entity.Reception{}, service.ErrLastReceptionAlreadyClosed).Times(1)
 			},
 			wantStatus: http.StatusBadRequest,
 			wantBody:   service.ErrLastReceptionAlreadyClosed.Error(),
@@ -66,7 +83,7 @@ func TestHandle(t *testing.T) {
 		{
 			name: "cannot close empty reception",
 			mockBehavior: func(s *mock_patch_reception.MockReceptionService) {
-				s.EXPECT().CloseReception(gomock.Any(), pointID).Return(service.ErrCannotCloseEmptyReception).Times(1)
+				s.EXPECT().CloseReception(gomock.Any(), actorID, pointID).Return(entity.Reception{}, service.ErrCannotCloseEmptyReception).Times(1)
 			},
 			wantStatus: http.StatusBadRequest,
 			wantBody:   service.ErrCannotCloseEmptyReception.Error(),
@@ -74,20 +91,20 @@ func TestHandle(t *testing.T) {
 		{
 			name: "internal error",
 			mockBehavior: func(s *mock_patch_reception.MockReceptionService) {
-				s.EXPECT().CloseReception(gomock.Any(), pointID).Return(arbitraryErr).Times(1)
+				s.EXPECT().CloseReception(gomock.Any(), actorID, pointID).Return(entity.Reception{}, arbitraryErr).Times(1)
 			},
 			wantStatus: http.StatusInternalServerError,
-			wantBody:   arbitraryErr.Error(),
+			wantBody:   http.StatusText(http.StatusInternalServerError),
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
 			e := echo.New()
-			e.Validator = validator.NewCustomValidator()
 			req := httptest.NewRequest(http.MethodPatch, "/", nil)
 			rec := httptest.NewRecorder()
 			ctx := e.NewContext(req, rec)
+			ctx.SetRequest(req.WithContext(middleware.NewUserContext(req.Context(), &auth.TokenClaims{UserID: actorID, Role: entity.RoleEmployee})))
 
 			ctx.SetParamNames("pvzId")
 			ctx.SetParamValues(string(pointID.String()))
@@ -96,9 +113,9 @@ func TestHandle(t *testing.T) {
 			MockService := mock_patch_reception.NewMockReceptionService(ctrl)
 			tc.mockBehavior(MockService)
 
-			handler := patch_reception.New(MockService)
+			server := &dto.ServerInterfaceWrapper{Handler: dto.NewStrictHandler(api.StrictServer{PostPvzPvzIdCloseLastReceptionHandler: patch_reception.New(MockService)}, nil)}
 
-			err := handler.Handle(ctx)
+			err := server.PostPvzPvzIdCloseLastReception(ctx)
 
 			if tc.wantStatus >= 400 {
 				require.Error(t, err)
@@ -110,6 +127,7 @@ func TestHandle(t *testing.T) {
 			} else {
 				require.NoError(t, err)
 				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, strings.Trim(rec.Body.String(), "\n"))
 			}
 		})
 	}
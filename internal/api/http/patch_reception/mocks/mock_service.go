@@ -13,6 +13,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	entity "github.com/4udiwe/avito-pvz/internal/entity"
 	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -42,15 +43,16 @@ func (m *MockReceptionService) EXPECT() *MockReceptionServiceMockRecorder {
 }
 
 // CloseReception mocks base method.
-func (m *MockReceptionService) CloseReception(ctx context.Context, pointID uuid.UUID) error {
+func (m *MockReceptionService) CloseReception(ctx context.Context, actorID, pointID uuid.UUID) (entity.Reception, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CloseReception", ctx, pointID)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "CloseReception", ctx, actorID, pointID)
+	ret0, _ := ret[0].(entity.Reception)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // CloseReception indicates an expected call of CloseReception.
-func (mr *MockReceptionServiceMockRecorder) CloseReception(ctx, pointID any) *gomock.Call {
+func (mr *MockReceptionServiceMockRecorder) CloseReception(ctx, actorID, pointID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseReception", reflect.TypeOf((*MockReceptionService)(nil).CloseReception), ctx, pointID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseReception", reflect.TypeOf((*MockReceptionService)(nil).CloseReception), ctx, actorID, pointID)
 }
@@ -0,0 +1,15 @@
+package post_api_key
+
+import (
+	"context"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/google/uuid"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type ServiceAccountService interface {
+	CreateAPIKey(ctx context.Context, actorID uuid.UUID, accountID uuid.UUID, scopes []entity.Permission, expiresAt *time.Time) (entity.APIKey, string, error)
+}
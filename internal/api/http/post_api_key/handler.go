@@ -0,0 +1,53 @@
+package post_api_key
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/service/service_account"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/lo"
+)
+
+type handler struct {
+	s ServiceAccountService
+}
+
+func New(serviceAccountService ServiceAccountService) api.Handler {
+	return decorator.NewBindAndValidateDerocator(&handler{s: serviceAccountService})
+}
+
+type Request struct {
+	AccountID uuid.UUID  `param:"accountId" validate:"required"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1,dive,max=64"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+func (h *handler) Handle(ctx echo.Context, in Request) error {
+	claims, err := middleware.GetUserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	scopes := lo.Map(in.Scopes, func(s string, _ int) entity.Permission { return entity.Permission(s) })
+	key, secret, err := h.s.CreateAPIKey(ctx.Request().Context(), claims.UserID, in.AccountID, scopes, in.ExpiresAt)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, service_account.ErrNoServiceAccountFound):
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		case errors.Is(err, service_account.ErrUnknownScope), errors.Is(err, service_account.ErrScopeNotGrantable),
+			errors.Is(err, service_account.ErrInvalidExpiry):
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return ctx.JSON(http.StatusCreated, dto.EntityAPIKeyToDTO(&key, secret))
+}
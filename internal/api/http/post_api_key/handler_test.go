@@ -0,0 +1,169 @@
+package post_api_key_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_api_key"
+	mock_post_api_key "github.com/4udiwe/avito-pvz/internal/api/http/post_api_key/mocks"
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/service/service_account"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		moderatorID  = uuid.New()
+		accountID    = uuid.New()
+		now          = time.Now().UTC().Truncate(time.Second)
+		expiresAt    = now.Add(30 * 24 * time.Hour)
+		scopes       = []entity.Permission{entity.PermissionPointRead, entity.PermissionProductRead}
+		secret       = "pvz_secret"
+		out          = entity.APIKey{
+			ID:               uuid.New(),
+			ServiceAccountID: accountID,
+			Prefix:           "pvz_secret",
+			Scopes:           scopes,
+			CreatedBy:        moderatorID,
+			CreatedAt:        now,
+			ExpiresAt:        expiresAt,
+		}
+		body = `{"scopes":["point:read","product:read"]}`
+	)
+
+	responseJSON, _ := json.Marshal(dto.EntityAPIKeyToDTO(&out, secret))
+	expiryJSON, _ := json.Marshal(expiresAt)
+
+	type MockBehavior func(s *mock_post_api_key.MockServiceAccountService)
+
+	for _, tc := range []struct {
+		name         string
+		body         string
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name: "success",
+			body: body,
+			mockBehavior: func(s *mock_post_api_key.MockServiceAccountService) {
+				s.EXPECT().CreateAPIKey(gomock.Any(), moderatorID, accountID, scopes, nil).Return(out, secret, nil).Times(1)
+			},
+			wantStatus: http.StatusCreated,
+			wantBody:   string(responseJSON),
+		},
+		{
+			name: "success with expiry",
+			body: `{"scopes":["point:read","product:read"],"expiresAt":` + string(expiryJSON) + `}`,
+			mockBehavior: func(s *mock_post_api_key.MockServiceAccountService) {
+				s.EXPECT().CreateAPIKey(gomock.Any(), moderatorID, accountID, scopes, gomock.Cond(func(at *time.Time) bool {
+					return at != nil && at.Equal(expiresAt)
+				})).Return(out, secret, nil).Times(1)
+			},
+			wantStatus: http.StatusCreated,
+			wantBody:   string(responseJSON),
+		},
+		{
+			name:         "no scopes",
+			body:         `{"scopes":[]}`,
+			mockBehavior: func(s *mock_post_api_key.MockServiceAccountService) {},
+			wantStatus:   http.StatusBadRequest,
+			wantBody:     "field scopes must be at least 1 characters",
+		},
+		{
+			name: "no service account",
+			body: body,
+			mockBehavior: func(s *mock_post_api_key.MockServiceAccountService) {
+				s.EXPECT().CreateAPIKey(gomock.Any(), moderatorID, accountID, scopes, nil).Return(entity.APIKey{}, "", service_account.ErrNoServiceAccountFound).Times(1)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   service_account.ErrNoServiceAccountFound.Error(),
+		},
+		{
+			name: "unknown scope",
+			body: body,
+			mockBehavior: func(s *mock_post_api_key.MockServiceAccountService) {
+				s.EXPECT().CreateAPIKey(gomock.Any(), moderatorID, accountID, scopes, nil).Return(entity.APIKey{}, "", service_account.ErrUnknownScope).Times(1)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   service_account.ErrUnknownScope.Error(),
+		},
+		{
+			name: "scope not grantable",
+			body: body,
+			mockBehavior: func(s *mock_post_api_key.MockServiceAccountService) {
+				s.EXPECT().CreateAPIKey(gomock.Any(), moderatorID, accountID, scopes, nil).Return(entity.APIKey{}, "", service_account.ErrScopeNotGrantable).Times(1)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   service_account.ErrScopeNotGrantable.Error(),
+		},
+		{
+			name: "invalid expiry",
+			body: body,
+			mockBehavior: func(s *mock_post_api_key.MockServiceAccountService) {
+				s.EXPECT().CreateAPIKey(gomock.Any(), moderatorID, accountID, scopes, nil).Return(entity.APIKey{}, "", service_account.ErrInvalidExpiry).Times(1)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   service_account.ErrInvalidExpiry.Error(),
+		},
+		{
+			name: "internal error",
+			body: body,
+			mockBehavior: func(s *mock_post_api_key.MockServiceAccountService) {
+				s.EXPECT().CreateAPIKey(gomock.Any(), moderatorID, accountID, scopes, nil).Return(entity.APIKey{}, "", arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+			ctx.SetParamNames("accountId")
+			ctx.SetParamValues(accountID.String())
+
+			ctx.Set(middleware.USER_CLAIMS_KEY, &auth.TokenClaims{UserID: moderatorID, Role: entity.RoleModerator})
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_post_api_key.NewMockServiceAccountService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := post_api_key.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, strings.Trim(rec.Body.String(), "\n"))
+			}
+		})
+	}
+}
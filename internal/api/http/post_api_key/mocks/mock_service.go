@@ -0,0 +1,60 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: contracts.go
+//
+// Generated by this command:
+//
+//	mockgen -source=contracts.go -destination=mocks/mock_service.go
+//
+
+// Package mock_post_api_key is a generated GoMock package.
+package mock_post_api_key
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/4udiwe/avito-pvz/internal/entity"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockServiceAccountService is a mock of ServiceAccountService interface.
+type MockServiceAccountService struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceAccountServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockServiceAccountServiceMockRecorder is the mock recorder for MockServiceAccountService.
+type MockServiceAccountServiceMockRecorder struct {
+	mock *MockServiceAccountService
+}
+
+// NewMockServiceAccountService creates a new mock instance.
+func NewMockServiceAccountService(ctrl *gomock.Controller) *MockServiceAccountService {
+	mock := &MockServiceAccountService{ctrl: ctrl}
+	mock.recorder = &MockServiceAccountServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockServiceAccountService) EXPECT() *MockServiceAccountServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockServiceAccountService) CreateAPIKey(ctx context.Context, actorID, accountID uuid.UUID, scopes []entity.Permission, expiresAt *time.Time) (entity.APIKey, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, actorID, accountID, scopes, expiresAt)
+	ret0, _ := ret[0].(entity.APIKey)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockServiceAccountServiceMockRecorder) CreateAPIKey(ctx, actorID, accountID, scopes, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockServiceAccountService)(nil).CreateAPIKey), ctx, actorID, accountID, scopes, expiresAt)
+}
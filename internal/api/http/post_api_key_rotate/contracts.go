@@ -0,0 +1,14 @@
+package post_api_key_rotate
+
+import (
+	"context"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/google/uuid"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type ServiceAccountService interface {
+	RotateAPIKey(ctx context.Context, actorID uuid.UUID, accountID uuid.UUID, keyID uuid.UUID) (entity.APIKey, string, error)
+}
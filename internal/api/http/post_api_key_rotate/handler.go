@@ -0,0 +1,47 @@
+package post_api_key_rotate
+
+import (
+	"errors"
+	"net/http"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/service/service_account"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s ServiceAccountService
+}
+
+func New(serviceAccountService ServiceAccountService) api.Handler {
+	return decorator.NewBindAndValidateDerocator(&handler{s: serviceAccountService})
+}
+
+type Request struct {
+	AccountID uuid.UUID `param:"accountId" validate:"required"`
+	KeyID     uuid.UUID `param:"keyId" validate:"required"`
+}
+
+func (h *handler) Handle(ctx echo.Context, in Request) error {
+	claims, err := middleware.GetUserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	key, secret, err := h.s.RotateAPIKey(ctx.Request().Context(), claims.UserID, in.AccountID, in.KeyID)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, service_account.ErrNoAPIKeyFound):
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		case errors.Is(err, service_account.ErrAPIKeyInactive):
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return ctx.JSON(http.StatusCreated, dto.EntityAPIKeyToDTO(&key, secret))
+}
@@ -0,0 +1,126 @@
+package post_api_key_rotate_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_api_key_rotate"
+	mock_post_api_key_rotate "github.com/4udiwe/avito-pvz/internal/api/http/post_api_key_rotate/mocks"
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/service/service_account"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		moderatorID  = uuid.New()
+		accountID    = uuid.New()
+		keyID        = uuid.New()
+		now          = time.Now().UTC().Truncate(time.Second)
+		secret       = "pvz_secret"
+		out          = entity.APIKey{
+			ID:               uuid.New(),
+			ServiceAccountID: accountID,
+			Prefix:           "pvz_secret",
+			Scopes:           []entity.Permission{entity.PermissionOrderRead},
+			CreatedBy:        moderatorID,
+			CreatedAt:        now,
+			ExpiresAt:        now.Add(90 * 24 * time.Hour),
+		}
+	)
+
+	responseJSON, _ := json.Marshal(dto.EntityAPIKeyToDTO(&out, secret))
+
+	type MockBehavior func(s *mock_post_api_key_rotate.MockServiceAccountService)
+
+	for _, tc := range []struct {
+		name         string
+		body         string
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name: "success",
+			mockBehavior: func(s *mock_post_api_key_rotate.MockServiceAccountService) {
+				s.EXPECT().RotateAPIKey(gomock.Any(), moderatorID, accountID, keyID).Return(out, secret, nil).Times(1)
+			},
+			wantStatus: http.StatusCreated,
+			wantBody:   string(responseJSON),
+		},
+		{
+			name: "no key found",
+			mockBehavior: func(s *mock_post_api_key_rotate.MockServiceAccountService) {
+				s.EXPECT().RotateAPIKey(gomock.Any(), moderatorID, accountID, keyID).Return(entity.APIKey{}, "", service_account.ErrNoAPIKeyFound).Times(1)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   service_account.ErrNoAPIKeyFound.Error(),
+		},
+		{
+			name: "inactive key",
+			mockBehavior: func(s *mock_post_api_key_rotate.MockServiceAccountService) {
+				s.EXPECT().RotateAPIKey(gomock.Any(), moderatorID, accountID, keyID).Return(entity.APIKey{}, "", service_account.ErrAPIKeyInactive).Times(1)
+			},
+			wantStatus: http.StatusConflict,
+			wantBody:   service_account.ErrAPIKeyInactive.Error(),
+		},
+		{
+			name: "internal error",
+			mockBehavior: func(s *mock_post_api_key_rotate.MockServiceAccountService) {
+				s.EXPECT().RotateAPIKey(gomock.Any(), moderatorID, accountID, keyID).Return(entity.APIKey{}, "", arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+			ctx.SetParamNames("accountId", "keyId")
+			ctx.SetParamValues(accountID.String(), keyID.String())
+
+			ctx.Set(middleware.USER_CLAIMS_KEY, &auth.TokenClaims{UserID: moderatorID, Role: entity.RoleModerator})
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_post_api_key_rotate.NewMockServiceAccountService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := post_api_key_rotate.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, strings.Trim(rec.Body.String(), "\n"))
+			}
+		})
+	}
+}
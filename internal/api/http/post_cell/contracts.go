@@ -0,0 +1,13 @@
+package post_cell
+
+import (
+	"context"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type CellService interface {
+	CreateCell(ctx context.Context, cell entity.StorageCell) (entity.StorageCell, error)
+}
@@ -0,0 +1,53 @@
+package post_cell
+
+import (
+	"errors"
+	"net/http"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	service "github.com/4udiwe/avito-pvz/internal/service/cell"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s CellService
+}
+
+func New(cellService CellService) api.Handler {
+	return decorator.NewBindAndValidateDerocator(&handler{s: cellService})
+}
+
+type Request struct {
+	PointID     uuid.UUID           `param:"pvzId" validate:"required"`
+	Rack        string              `json:"rack" validate:"required,max=16"`
+	Shelf       string              `json:"shelf" validate:"required,max=16"`
+	Bin         string              `json:"bin" validate:"required,max=16"`
+	Capacity    int                 `json:"capacity" validate:"required,min=1"`
+	ProductType *entity.ProductType `json:"productType" validate:"omitempty,oneof=электроника одежда обувь"`
+}
+
+func (h *handler) Handle(ctx echo.Context, in Request) error {
+	cell, err := h.s.CreateCell(ctx.Request().Context(), entity.StorageCell{
+		PointID:     in.PointID,
+		Rack:        in.Rack,
+		Shelf:       in.Shelf,
+		Bin:         in.Bin,
+		Capacity:    in.Capacity,
+		ProductType: in.ProductType,
+	})
+
+	if err != nil {
+		if errors.Is(err, service.ErrNoPointFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrCellAlreadyExists) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return ctx.JSON(http.StatusCreated, dto.EntityCellToDTO(&cell))
+}
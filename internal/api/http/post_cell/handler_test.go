@@ -0,0 +1,137 @@
+package post_cell_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_cell"
+	mock_post_cell "github.com/4udiwe/avito-pvz/internal/api/http/post_cell/mocks"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	service "github.com/4udiwe/avito-pvz/internal/service/cell"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		pointID      = uuid.New()
+		shoes        = entity.ProductTypeShoes
+
+		toCreate = entity.StorageCell{PointID: pointID, Rack: "A", Shelf: "1", Bin: "2", Capacity: 10, ProductType: &shoes}
+		created  = entity.StorageCell{ID: uuid.New(), PointID: pointID, Rack: "A", Shelf: "1", Bin: "2", Capacity: 10, ProductType: &shoes}
+
+		validBody = map[string]any{"rack": "A", "shelf": "1", "bin": "2", "capacity": 10, "productType": shoes}
+	)
+
+	responseJSON, _ := json.Marshal(dto.EntityCellToDTO(&created))
+
+	type MockBehavior func(s *mock_post_cell.MockCellService)
+
+	for _, tc := range []struct {
+		name         string
+		body         map[string]any
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name: "success",
+			body: validBody,
+			mockBehavior: func(s *mock_post_cell.MockCellService) {
+				s.EXPECT().CreateCell(gomock.Any(), toCreate).Return(created, nil).Times(1)
+			},
+			wantStatus: http.StatusCreated,
+			wantBody:   string(responseJSON),
+		},
+		{
+			name: "no point found",
+			body: validBody,
+			mockBehavior: func(s *mock_post_cell.MockCellService) {
+				s.EXPECT().CreateCell(gomock.Any(), toCreate).Return(entity.StorageCell{}, service.ErrNoPointFound).Times(1)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   service.ErrNoPointFound.Error(),
+		},
+		{
+			name: "cell already exists",
+			body: validBody,
+			mockBehavior: func(s *mock_post_cell.MockCellService) {
+				s.EXPECT().CreateCell(gomock.Any(), toCreate).Return(entity.StorageCell{}, service.ErrCellAlreadyExists).Times(1)
+			},
+			wantStatus: http.StatusConflict,
+			wantBody:   service.ErrCellAlreadyExists.Error(),
+		},
+		{
+			name: "internal error",
+			body: validBody,
+			mockBehavior: func(s *mock_post_cell.MockCellService) {
+				s.EXPECT().CreateCell(gomock.Any(), toCreate).Return(entity.StorageCell{}, arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+		{
+			name:         "no capacity",
+			body:         map[string]any{"rack": "A", "shelf": "1", "bin": "2"},
+			mockBehavior: func(s *mock_post_cell.MockCellService) {},
+			wantStatus:   http.StatusBadRequest,
+			wantBody:     "field capacity is required",
+		},
+		{
+			name:         "unknown product type",
+			body:         map[string]any{"rack": "A", "shelf": "1", "bin": "2", "capacity": 1, "productType": "мебель"},
+			mockBehavior: func(s *mock_post_cell.MockCellService) {},
+			wantStatus:   http.StatusBadRequest,
+			wantBody:     "field productType is invalid",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+
+			requestBody, _ := json.Marshal(tc.body)
+
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctx.SetParamNames("pvzId")
+			ctx.SetParamValues(pointID.String())
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_post_cell.NewMockCellService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := post_cell.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, strings.Trim(rec.Body.String(), "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: contracts.go
+//
+// Generated by this command:
+//
+//	mockgen -source=contracts.go -destination=mocks/mock_service.go
+//
+
+// Package mock_post_cell is a generated GoMock package.
+package mock_post_cell
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/4udiwe/avito-pvz/internal/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCellService is a mock of CellService interface.
+type MockCellService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCellServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockCellServiceMockRecorder is the mock recorder for MockCellService.
+type MockCellServiceMockRecorder struct {
+	mock *MockCellService
+}
+
+// NewMockCellService creates a new mock instance.
+func NewMockCellService(ctrl *gomock.Controller) *MockCellService {
+	mock := &MockCellService{ctrl: ctrl}
+	mock.recorder = &MockCellServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCellService) EXPECT() *MockCellServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateCell mocks base method.
+func (m *MockCellService) CreateCell(ctx context.Context, cell entity.StorageCell) (entity.StorageCell, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCell", ctx, cell)
+	ret0, _ := ret[0].(entity.StorageCell)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCell indicates an expected call of CreateCell.
+func (mr *MockCellServiceMockRecorder) CreateCell(ctx, cell any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCell", reflect.TypeOf((*MockCellService)(nil).CreateCell), ctx, cell)
+}
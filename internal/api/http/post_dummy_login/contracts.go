@@ -6,6 +6,8 @@ import (
 	"github.com/4udiwe/avito-pvz/internal/entity"
 )
 
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
 type UserService interface {
 	DummyLogin(ctx context.Context, role entity.UserRole) (string, error)
 }
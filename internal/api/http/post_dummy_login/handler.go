@@ -1,10 +1,10 @@
 package post_dummy_login
 
 import (
+	"context"
 	"net/http"
 
 	api "github.com/4udiwe/avito-pvz/internal/api/http"
-	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
 	"github.com/4udiwe/avito-pvz/internal/dto"
 	"github.com/4udiwe/avito-pvz/internal/entity"
 	"github.com/labstack/echo/v4"
@@ -14,17 +14,15 @@ type handler struct {
 	s UserService
 }
 
-func New(userService UserService) api.Handler {
-	return decorator.NewBindAndValidateDerocator(&handler{s: userService})
+func New(userService UserService) api.PostDummyLoginHandler {
+	return &handler{s: userService}
 }
 
-type Request dto.PostDummyLoginJSONBody
-
-func (h *handler) Handle(ctx echo.Context, in Request) error {
-	token, err := h.s.DummyLogin(ctx.Request().Context(), entity.UserRole(in.Role))
+func (h *handler) PostDummyLogin(ctx context.Context, request dto.PostDummyLoginRequestObject) (dto.PostDummyLoginResponseObject, error) {
+	token, err := h.s.DummyLogin(ctx, entity.UserRole(request.Body.Role))
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return nil, echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
 	}
-	return ctx.JSON(http.StatusCreated, token)
+	return dto.PostDummyLogin200JSONResponse(token), nil
 }
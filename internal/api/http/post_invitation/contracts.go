@@ -0,0 +1,14 @@
+package post_invitation
+
+import (
+	"context"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/google/uuid"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type UserService interface {
+	CreateInvitation(ctx context.Context, actorID uuid.UUID, email string, role entity.UserRole, pointIDs []uuid.UUID) (entity.Invitation, string, error)
+}
@@ -0,0 +1,51 @@
+package post_invitation
+
+import (
+	"errors"
+	"net/http"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/service/user"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s UserService
+}
+
+func New(userService UserService) api.Handler {
+	return decorator.NewBindAndValidateDerocator(&handler{s: userService})
+}
+
+type Request struct {
+	Email  string      `json:"email" validate:"required,email"`
+	Role   string      `json:"role" validate:"required,max=32"`
+	PvzIds []uuid.UUID `json:"pvzIds"`
+}
+
+func (h *handler) Handle(ctx echo.Context, in Request) error {
+	claims, err := middleware.GetUserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	invitation, code, err := h.s.CreateInvitation(ctx.Request().Context(), claims.UserID, in.Email, entity.UserRole(in.Role), in.PvzIds)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrUserAlreadyExists):
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		case errors.Is(err, user.ErrUnknownRole):
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		case errors.Is(err, user.ErrNoPointFound):
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return ctx.JSON(http.StatusCreated, dto.EntityInvitationToDTO(&invitation, code))
+}
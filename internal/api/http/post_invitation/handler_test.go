@@ -0,0 +1,144 @@
+package post_invitation_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_invitation"
+	mock_post_invitation "github.com/4udiwe/avito-pvz/internal/api/http/post_invitation/mocks"
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/service/user"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		moderatorID  = uuid.New()
+		pointID      = uuid.New()
+		email        = "new@mail.com"
+		code         = "invitation-code"
+		now          = time.Now().UTC().Truncate(time.Second)
+		out          = entity.Invitation{
+			ID:        uuid.New(),
+			Email:     email,
+			Role:      entity.RoleEmployee,
+			PointIDs:  []uuid.UUID{pointID},
+			CreatedAt: now,
+			ExpiresAt: now.Add(72 * time.Hour),
+		}
+		body = `{"email":"new@mail.com","role":"employee","pvzIds":["` + pointID.String() + `"]}`
+	)
+
+	responseJSON, _ := json.Marshal(dto.EntityInvitationToDTO(&out, code))
+
+	type MockBehavior func(s *mock_post_invitation.MockUserService)
+
+	for _, tc := range []struct {
+		name         string
+		body         string
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name: "success",
+			body: body,
+			mockBehavior: func(s *mock_post_invitation.MockUserService) {
+				s.EXPECT().CreateInvitation(gomock.Any(), moderatorID, email, entity.RoleEmployee, []uuid.UUID{pointID}).Return(out, code, nil).Times(1)
+			},
+			wantStatus: http.StatusCreated,
+			wantBody:   string(responseJSON),
+		},
+		{
+			name:         "invalid email",
+			body:         `{"email":"new","role":"employee"}`,
+			mockBehavior: func(s *mock_post_invitation.MockUserService) {},
+			wantStatus:   http.StatusBadRequest,
+			wantBody:     "field email must be a valid email address",
+		},
+		{
+			name: "unknown role",
+			body: `{"email":"new@mail.com","role":"admin"}`,
+			mockBehavior: func(s *mock_post_invitation.MockUserService) {
+				s.EXPECT().CreateInvitation(gomock.Any(), moderatorID, email, entity.UserRole("admin"), nil).Return(entity.Invitation{}, "", user.ErrUnknownRole).Times(1)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   user.ErrUnknownRole.Error(),
+		},
+		{
+			name: "user already exists",
+			body: body,
+			mockBehavior: func(s *mock_post_invitation.MockUserService) {
+				s.EXPECT().CreateInvitation(gomock.Any(), moderatorID, email, entity.RoleEmployee, []uuid.UUID{pointID}).Return(entity.Invitation{}, "", user.ErrUserAlreadyExists).Times(1)
+			},
+			wantStatus: http.StatusConflict,
+			wantBody:   user.ErrUserAlreadyExists.Error(),
+		},
+		{
+			name: "no point found",
+			body: body,
+			mockBehavior: func(s *mock_post_invitation.MockUserService) {
+				s.EXPECT().CreateInvitation(gomock.Any(), moderatorID, email, entity.RoleEmployee, []uuid.UUID{pointID}).Return(entity.Invitation{}, "", user.ErrNoPointFound).Times(1)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   user.ErrNoPointFound.Error(),
+		},
+		{
+			name: "internal error",
+			body: body,
+			mockBehavior: func(s *mock_post_invitation.MockUserService) {
+				s.EXPECT().CreateInvitation(gomock.Any(), moderatorID, email, entity.RoleEmployee, []uuid.UUID{pointID}).Return(entity.Invitation{}, "", arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctx.Set(middleware.USER_CLAIMS_KEY, &auth.TokenClaims{UserID: moderatorID, Role: entity.RoleModerator})
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_post_invitation.NewMockUserService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := post_invitation.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, strings.Trim(rec.Body.String(), "\n"))
+			}
+		})
+	}
+}
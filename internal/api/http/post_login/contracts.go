@@ -3,11 +3,12 @@ package post_login
 import (
 	"context"
 
-	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/service/user"
 )
 
 //go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
 
 type UserService interface {
-	Authenticate(ctx context.Context, email, password string) (*auth.Tokens, error)
+	Authenticate(ctx context.Context, email, password string, client entity.ClientInfo) (*user.LoginResult, error)
 }
@@ -1,11 +1,12 @@
 package post_login
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
 	api "github.com/4udiwe/avito-pvz/internal/api/http"
-	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
 	"github.com/4udiwe/avito-pvz/internal/dto"
 	"github.com/4udiwe/avito-pvz/internal/service/user"
 	"github.com/labstack/echo/v4"
@@ -15,23 +16,33 @@ type handler struct {
 	s UserService
 }
 
-func New(userService UserService) api.Handler {
-	return decorator.NewBindAndValidateDerocator(&handler{s: userService})
+func New(userService UserService) api.PostLoginHandler {
+	return &handler{s: userService}
 }
 
-type Request dto.PostLoginJSONBody
-
-func (h *handler) Handle(ctx echo.Context, in Request) error {
-	tokens, err := h.s.Authenticate(ctx.Request().Context(), string(in.Email), in.Password)
+func (h *handler) PostLogin(ctx context.Context, request dto.PostLoginRequestObject) (dto.PostLoginResponseObject, error) {
+	result, err := h.s.Authenticate(
+		ctx,
+		string(request.Body.Email),
+		request.Body.Password,
+		middleware.ClientInfoFromContext(ctx),
+	)
 
 	if err != nil {
-		if errors.Is(err, user.ErrNoUserFound) {
-			return echo.NewHTTPError(http.StatusNotFound, err.Error())
-		}
-		if errors.Is(err, user.ErrInvalidCredentials) {
-			return echo.NewHTTPError(http.StatusForbidden, err.Error())
+		switch {
+		case errors.Is(err, user.ErrInvalidCredentials):
+			return nil, echo.NewHTTPError(http.StatusUnauthorized, err.Error()).SetInternal(err)
+		case errors.Is(err, user.ErrUserDisabled):
+			return nil, echo.NewHTTPError(http.StatusForbidden, err.Error()).SetInternal(err)
 		}
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return nil, echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	if result.MFA != nil {
+		return dto.PostLogin202JSONResponse{
+			MfaToken:           result.MFA.Token,
+			ExpiresAt:          result.MFA.ExpiresAt,
+			EnrollmentRequired: result.MFA.EnrollmentRequired,
+		}, nil
 	}
-	return ctx.JSON(http.StatusCreated, tokens)
+	return dto.PostLogin200JSONResponse(*dto.TokensToDTO(result.Tokens)), nil
 }
@@ -8,12 +8,16 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
 	"github.com/4udiwe/avito-pvz/internal/api/http/post_login"
 	mock_post_login "github.com/4udiwe/avito-pvz/internal/api/http/post_login/mocks"
 	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
 	"github.com/4udiwe/avito-pvz/internal/service/user"
-	"github.com/4udiwe/avito-pvz/pkg/validator"
 	"github.com/go-playground/assert/v2"
 	"github.com/labstack/echo/v4"
 	"github.com/oapi-codegen/runtime/types"
@@ -23,6 +27,7 @@ import (
 
 func TestHandle(t *testing.T) {
 	var (
+		client       = entity.ClientInfo{UserAgent: "scanner/1.0", IP: "10.0.0.1"}
 		arbitraryErr = errors.New("arbitrary error")
 		Email        = "example@gmail.gom"
 		Password     = "12345678"
@@ -31,7 +36,7 @@ func TestHandle(t *testing.T) {
 		ttl          = 100
 	)
 
-	request := post_login.Request{
+	request := dto.PostLoginJSONRequestBody{
 		Email:    types.Email(Email),
 		Password: Password,
 	}
@@ -40,7 +45,10 @@ func TestHandle(t *testing.T) {
 		AccessToken:  AccessToken,
 		ExpiresIn:    int64(ttl),
 	}
-	responseJSON, _ := json.Marshal(out)
+	responseJSON, _ := json.Marshal(dto.TokensToDTO(&out))
+
+	pending := user.MFAPending{Token: "mfa", ExpiresAt: time.Date(2025, 12, 3, 9, 0, 0, 0, time.UTC)}
+	pendingJSON := `{"enrollment_required":false,"expires_at":"2025-12-03T09:00:00Z","mfa_token":"mfa"}`
 
 	type MockBehavior func(s *mock_post_login.MockUserService)
 
@@ -53,41 +61,48 @@ func TestHandle(t *testing.T) {
 		{
 			name: "success",
 			mockBehavior: func(s *mock_post_login.MockUserService) {
-				s.EXPECT().Authenticate(gomock.Any(), string(request.Email), request.Password).Return(&out, nil).Times(1)
+				s.EXPECT().Authenticate(gomock.Any(), string(request.Email), request.Password, client).Return(&user.LoginResult{Tokens: &out}, nil).Times(1)
 			},
-			wantStatus: http.StatusCreated,
+			wantStatus: http.StatusOK,
 			wantBody:   string(responseJSON),
 		},
 		{
-			name: "no user found",
+			name: "mfa required",
 			mockBehavior: func(s *mock_post_login.MockUserService) {
-				s.EXPECT().Authenticate(gomock.Any(), string(request.Email), request.Password).Return(nil, user.ErrNoUserFound).Times(1)
+				s.EXPECT().Authenticate(gomock.Any(), string(request.Email), request.Password, client).Return(&user.LoginResult{MFA: &pending}, nil).Times(1)
 			},
-			wantStatus: http.StatusNotFound,
-			wantBody:   user.ErrNoUserFound.Error(),
+			wantStatus: http.StatusAccepted,
+			wantBody:   pendingJSON,
 		},
 		{
 			name: "invalid credentials",
 			mockBehavior: func(s *mock_post_login.MockUserService) {
-				s.EXPECT().Authenticate(gomock.Any(), string(request.Email), request.Password).Return(nil, user.ErrInvalidCredentials).Times(1)
+				s.EXPECT().Authenticate(gomock.Any(), string(request.Email), request.Password, client).Return(nil, user.ErrInvalidCredentials).Times(1)
 			},
-			wantStatus: http.StatusForbidden,
+			wantStatus: http.StatusUnauthorized,
 			wantBody:   user.ErrInvalidCredentials.Error(),
 		},
+		{
+			name: "disabled user",
+			mockBehavior: func(s *mock_post_login.MockUserService) {
+				s.EXPECT().Authenticate(gomock.Any(), string(request.Email), request.Password, client).Return(nil, user.ErrUserDisabled).Times(1)
+			},
+			wantStatus: http.StatusForbidden,
+			wantBody:   user.ErrUserDisabled.Error(),
+		},
 		{
 			name: "internal error",
 			mockBehavior: func(s *mock_post_login.MockUserService) {
-				s.EXPECT().Authenticate(gomock.Any(), string(request.Email), request.Password).Return(nil, arbitraryErr).Times(1)
+				s.EXPECT().Authenticate(gomock.Any(), string(request.Email), request.Password, client).Return(nil, arbitraryErr).Times(1)
 			},
 			wantStatus: http.StatusInternalServerError,
-			wantBody:   arbitraryErr.Error(),
+			wantBody:   http.StatusText(http.StatusInternalServerError),
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
 			e := echo.New()
-			e.Validator = validator.NewCustomValidator()
 
 			requestBody, _ := json.Marshal(request)
 
@@ -95,14 +110,15 @@ func TestHandle(t *testing.T) {
 			req.Header.Set("Content-Type", "application/json")
 			rec := httptest.NewRecorder()
 			ctx := e.NewContext(req, rec)
+			ctx.SetRequest(req.WithContext(middleware.NewClientInfoContext(req.Context(), client)))
 
 			ctrl := gomock.NewController(t)
 			MockService := mock_post_login.NewMockUserService(ctrl)
 			tc.mockBehavior(MockService)
 
-			handler := post_login.New(MockService)
+			server := &dto.ServerInterfaceWrapper{Handler: dto.NewStrictHandler(api.StrictServer{PostLoginHandler: post_login.New(MockService)}, nil)}
 
-			err := handler.Handle(ctx)
+			err := server.PostLogin(ctx)
 
 			if tc.wantStatus >= 400 {
 				require.Error(t, err)
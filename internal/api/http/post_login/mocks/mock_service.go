@@ -13,7 +13,8 @@ import (
 	context "context"
 	reflect "reflect"
 
-	auth "github.com/4udiwe/avito-pvz/internal/auth"
+	entity "github.com/4udiwe/avito-pvz/internal/entity"
+	user "github.com/4udiwe/avito-pvz/internal/service/user"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -42,16 +43,16 @@ func (m *MockUserService) EXPECT() *MockUserServiceMockRecorder {
 }
 
 // Authenticate mocks base method.
-func (m *MockUserService) Authenticate(ctx context.Context, email, password string) (*auth.Tokens, error) {
+func (m *MockUserService) Authenticate(ctx context.Context, email, password string, client entity.ClientInfo) (*user.LoginResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Authenticate", ctx, email, password)
-	ret0, _ := ret[0].(*auth.Tokens)
+	ret := m.ctrl.Call(m, "Authenticate", ctx, email, password, client)
+	ret0, _ := ret[0].(*user.LoginResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Authenticate indicates an expected call of Authenticate.
-func (mr *MockUserServiceMockRecorder) Authenticate(ctx, email, password any) *gomock.Call {
+func (mr *MockUserServiceMockRecorder) Authenticate(ctx, email, password, client any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockUserService)(nil).Authenticate), ctx, email, password)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockUserService)(nil).Authenticate), ctx, email, password, client)
 }
@@ -0,0 +1,14 @@
+package post_logout
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type UserService interface {
+	Logout(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID, jti string, expiresAt time.Time) error
+}
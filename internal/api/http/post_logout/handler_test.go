@@ -0,0 +1,99 @@
+package post_logout_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_logout"
+	mock_post_logout "github.com/4udiwe/avito-pvz/internal/api/http/post_logout/mocks"
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		userID       = uuid.New()
+		sessionID    = uuid.New()
+		jti          = uuid.NewString()
+		expiresAt    = time.Now().Add(10 * time.Minute).Truncate(time.Second)
+
+		claims = &auth.TokenClaims{
+			UserID:    userID,
+			Role:      entity.RoleEmployee,
+			SessionID: sessionID,
+			RegisteredClaims: jwt.RegisteredClaims{
+				ID:        jti,
+				ExpiresAt: jwt.NewNumericDate(expiresAt),
+			},
+		}
+	)
+
+	type MockBehavior func(s *mock_post_logout.MockUserService)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name: "success",
+			mockBehavior: func(s *mock_post_logout.MockUserService) {
+				s.EXPECT().Logout(gomock.Any(), userID, sessionID, jti, expiresAt).Return(nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "internal error",
+			mockBehavior: func(s *mock_post_logout.MockUserService) {
+				s.EXPECT().Logout(gomock.Any(), userID, sessionID, jti, expiresAt).Return(arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctx.Set(middleware.USER_CLAIMS_KEY, claims)
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_post_logout.NewMockUserService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := post_logout.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+			}
+		})
+	}
+}
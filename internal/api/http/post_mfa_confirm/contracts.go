@@ -0,0 +1,13 @@
+package post_mfa_confirm
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type UserService interface {
+	ConfirmMFAEnrollment(ctx context.Context, userID uuid.UUID, code string) ([]string, error)
+}
@@ -0,0 +1,47 @@
+package post_mfa_confirm
+
+import (
+	"errors"
+	"net/http"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/service/user"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s UserService
+}
+
+func New(userService UserService) api.Handler {
+	return decorator.NewBindAndValidateDerocator(&handler{s: userService})
+}
+
+type Request struct {
+	Code string `json:"code" validate:"required"`
+}
+
+func (h *handler) Handle(ctx echo.Context, in Request) error {
+	claims, err := middleware.GetUserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	recoveryCodes, err := h.s.ConfirmMFAEnrollment(ctx.Request().Context(), claims.UserID, in.Code)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrInvalidMFACode):
+			return echo.NewHTTPError(http.StatusForbidden, err.Error()).SetInternal(err)
+		case errors.Is(err, user.ErrNoUserFound):
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		case errors.Is(err, user.ErrNoMFAEnrollment):
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return ctx.JSON(http.StatusOK, dto.RecoveryCodes{RecoveryCodes: recoveryCodes})
+}
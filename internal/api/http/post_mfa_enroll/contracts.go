@@ -0,0 +1,14 @@
+package post_mfa_enroll
+
+import (
+	"context"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/google/uuid"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type UserService interface {
+	BeginMFAEnrollment(ctx context.Context, userID uuid.UUID, code string) (entity.MFAEnrollment, error)
+}
@@ -0,0 +1,47 @@
+package post_mfa_enroll
+
+import (
+	"errors"
+	"net/http"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/service/user"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s UserService
+}
+
+func New(userService UserService) api.Handler {
+	return decorator.NewBindAndValidateDerocator(&handler{s: userService})
+}
+
+// Request carries the current TOTP or recovery code; it is required only
+// when the user already has a second factor
+type Request struct {
+	Code string `json:"code"`
+}
+
+func (h *handler) Handle(ctx echo.Context, in Request) error {
+	claims, err := middleware.GetUserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	enrollment, err := h.s.BeginMFAEnrollment(ctx.Request().Context(), claims.UserID, in.Code)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrInvalidMFACode):
+			return echo.NewHTTPError(http.StatusForbidden, err.Error()).SetInternal(err)
+		case errors.Is(err, user.ErrNoUserFound):
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return ctx.JSON(http.StatusOK, dto.EntityMFAEnrollmentToDTO(&enrollment))
+}
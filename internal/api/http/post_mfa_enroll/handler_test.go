@@ -0,0 +1,112 @@
+package post_mfa_enroll_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_mfa_enroll"
+	mock_post_mfa_enroll "github.com/4udiwe/avito-pvz/internal/api/http/post_mfa_enroll/mocks"
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/service/user"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		userID       = uuid.New()
+		request      = post_mfa_enroll.Request{Code: "123456"}
+		enrollment   = entity.MFAEnrollment{Secret: "SECRET", URI: "otpauth://totp/x", QRCode: []byte("png")}
+	)
+
+	type MockBehavior func(s *mock_post_mfa_enroll.MockUserService)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name: "success",
+			mockBehavior: func(s *mock_post_mfa_enroll.MockUserService) {
+				s.EXPECT().BeginMFAEnrollment(gomock.Any(), userID, request.Code).Return(enrollment, nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"secret":"SECRET","otpauthUri":"otpauth://totp/x","qrCode":"data:image/png;base64,cG5n"}`,
+		},
+		{
+			name: "invalid code",
+			mockBehavior: func(s *mock_post_mfa_enroll.MockUserService) {
+				s.EXPECT().BeginMFAEnrollment(gomock.Any(), userID, request.Code).Return(entity.MFAEnrollment{}, user.ErrInvalidMFACode).Times(1)
+			},
+			wantStatus: http.StatusForbidden,
+			wantBody:   user.ErrInvalidMFACode.Error(),
+		},
+		{
+			name: "no user found",
+			mockBehavior: func(s *mock_post_mfa_enroll.MockUserService) {
+				s.EXPECT().BeginMFAEnrollment(gomock.Any(), userID, request.Code).Return(entity.MFAEnrollment{}, user.ErrNoUserFound).Times(1)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   user.ErrNoUserFound.Error(),
+		},
+		{
+			name: "internal error",
+			mockBehavior: func(s *mock_post_mfa_enroll.MockUserService) {
+				s.EXPECT().BeginMFAEnrollment(gomock.Any(), userID, request.Code).Return(entity.MFAEnrollment{}, arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+
+			requestBody, _ := json.Marshal(request)
+
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctx.Set(middleware.USER_CLAIMS_KEY, &auth.TokenClaims{UserID: userID, Role: entity.RoleEmployee})
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_post_mfa_enroll.NewMockUserService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := post_mfa_enroll.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, strings.Trim(rec.Body.String(), "\n"))
+			}
+		})
+	}
+}
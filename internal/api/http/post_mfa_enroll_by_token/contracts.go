@@ -0,0 +1,13 @@
+package post_mfa_enroll_by_token
+
+import (
+	"context"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type UserService interface {
+	BeginMFAEnrollmentByToken(ctx context.Context, mfaToken string) (entity.MFAEnrollment, error)
+}
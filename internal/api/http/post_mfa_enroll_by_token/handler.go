@@ -0,0 +1,41 @@
+package post_mfa_enroll_by_token
+
+import (
+	"errors"
+	"net/http"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/service/user"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s UserService
+}
+
+func New(userService UserService) api.Handler {
+	return decorator.NewBindAndValidateDerocator(&handler{s: userService})
+}
+
+type Request struct {
+	MfaToken string `json:"mfa_token" validate:"required"`
+}
+
+func (h *handler) Handle(ctx echo.Context, in Request) error {
+	enrollment, err := h.s.BeginMFAEnrollmentByToken(ctx.Request().Context(), in.MfaToken)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrInvalidMFAToken):
+			return echo.NewHTTPError(http.StatusUnauthorized, err.Error()).SetInternal(err)
+		case errors.Is(err, user.ErrUserDisabled):
+			return echo.NewHTTPError(http.StatusForbidden, err.Error()).SetInternal(err)
+		case errors.Is(err, user.ErrMFAEnabled):
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return ctx.JSON(http.StatusOK, dto.EntityMFAEnrollmentToDTO(&enrollment))
+}
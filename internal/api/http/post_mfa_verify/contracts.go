@@ -0,0 +1,14 @@
+package post_mfa_verify
+
+import (
+	"context"
+
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type UserService interface {
+	VerifyMFA(ctx context.Context, mfaToken string, code string, client entity.ClientInfo) (*auth.Tokens, []string, error)
+}
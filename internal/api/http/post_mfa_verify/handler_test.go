@@ -0,0 +1,126 @@
+package post_mfa_verify_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_mfa_verify"
+	mock_post_mfa_verify "github.com/4udiwe/avito-pvz/internal/api/http/post_mfa_verify/mocks"
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/service/user"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		client       = entity.ClientInfo{UserAgent: "scanner/1.0", IP: "10.0.0.1"}
+		arbitraryErr = errors.New("arbitrary error")
+		tokens       = &auth.Tokens{AccessToken: "access", RefreshToken: "refresh", ExpiresIn: 100}
+		request      = post_mfa_verify.Request{MfaToken: "mfa", Code: "123456"}
+	)
+
+	type MockBehavior func(s *mock_post_mfa_verify.MockUserService)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name: "success",
+			mockBehavior: func(s *mock_post_mfa_verify.MockUserService) {
+				s.EXPECT().VerifyMFA(gomock.Any(), request.MfaToken, request.Code, client).Return(tokens, nil, nil).Times(1)
+			},
+			wantStatus: http.StatusCreated,
+			wantBody:   `{"access_token":"access","refresh_token":"refresh","expires_in":100}`,
+		},
+		{
+			name: "enrolment confirmed",
+			mockBehavior: func(s *mock_post_mfa_verify.MockUserService) {
+				s.EXPECT().VerifyMFA(gomock.Any(), request.MfaToken, request.Code, client).Return(tokens, []string{"AAAA-BBBB-CCCC-DDDD"}, nil).Times(1)
+			},
+			wantStatus: http.StatusCreated,
+			wantBody:   `{"access_token":"access","refresh_token":"refresh","expires_in":100,"recovery_codes":["AAAA-BBBB-CCCC-DDDD"]}`,
+		},
+		{
+			name: "invalid token",
+			mockBehavior: func(s *mock_post_mfa_verify.MockUserService) {
+				s.EXPECT().VerifyMFA(gomock.Any(), request.MfaToken, request.Code, client).Return(nil, nil, user.ErrInvalidMFAToken).Times(1)
+			},
+			wantStatus: http.StatusUnauthorized,
+			wantBody:   user.ErrInvalidMFAToken.Error(),
+		},
+		{
+			name: "invalid code",
+			mockBehavior: func(s *mock_post_mfa_verify.MockUserService) {
+				s.EXPECT().VerifyMFA(gomock.Any(), request.MfaToken, request.Code, client).Return(nil, nil, user.ErrInvalidMFACode).Times(1)
+			},
+			wantStatus: http.StatusForbidden,
+			wantBody:   user.ErrInvalidMFACode.Error(),
+		},
+		{
+			name: "not enrolled",
+			mockBehavior: func(s *mock_post_mfa_verify.MockUserService) {
+				s.EXPECT().VerifyMFA(gomock.Any(), request.MfaToken, request.Code, client).Return(nil, nil, user.ErrMFANotEnrolled).Times(1)
+			},
+			wantStatus: http.StatusConflict,
+			wantBody:   user.ErrMFANotEnrolled.Error(),
+		},
+		{
+			name: "internal error",
+			mockBehavior: func(s *mock_post_mfa_verify.MockUserService) {
+				s.EXPECT().VerifyMFA(gomock.Any(), request.MfaToken, request.Code, client).Return(nil, nil, arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+
+			requestBody, _ := json.Marshal(request)
+
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("User-Agent", client.UserAgent)
+			req.Header.Set("X-Real-Ip", client.IP)
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_post_mfa_verify.NewMockUserService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := post_mfa_verify.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, strings.Trim(rec.Body.String(), "\n"))
+			}
+		})
+	}
+}
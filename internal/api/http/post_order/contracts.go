@@ -0,0 +1,20 @@
+package post_order
+
+import (
+	"context"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/google/uuid"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type OrderService interface {
+	CreateOrder(
+		ctx context.Context,
+		pointID uuid.UUID,
+		number string,
+		customerContact string,
+		productIDs []uuid.UUID,
+	) (entity.Order, error)
+}
@@ -0,0 +1,49 @@
+package post_order
+
+import (
+	"errors"
+	"net/http"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	service "github.com/4udiwe/avito-pvz/internal/service/order"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s OrderService
+}
+
+func New(orderService OrderService) api.Handler {
+	return decorator.NewBindAndValidateDerocator(&handler{s: orderService})
+}
+
+type Request struct {
+	PvzId           uuid.UUID   `json:"pvzId" validate:"required"`
+	Number          string      `json:"number" validate:"required,max=64"`
+	CustomerContact string      `json:"customerContact" validate:"required,max=256"`
+	ProductIds      []uuid.UUID `json:"productIds" validate:"required,min=1"`
+}
+
+func (h *handler) Handle(ctx echo.Context, in Request) error {
+	order, err := h.s.CreateOrder(ctx.Request().Context(), in.PvzId, in.Number, in.CustomerContact, in.ProductIds)
+
+	if err != nil {
+		if errors.Is(err, service.ErrNoPointFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrOrderAlreadyExists) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrProductAlreadyInOrder) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrProductsUnavailable) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return ctx.JSON(http.StatusCreated, dto.EntityOrderToDTO(&order))
+}
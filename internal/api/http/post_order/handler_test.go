@@ -0,0 +1,152 @@
+package post_order_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_order"
+	mock_post_order "github.com/4udiwe/avito-pvz/internal/api/http/post_order/mocks"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	service "github.com/4udiwe/avito-pvz/internal/service/order"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		pointID      = uuid.New()
+		productIDs   = []uuid.UUID{uuid.New()}
+
+		request = post_order.Request{
+			PvzId:           pointID,
+			Number:          "ORD-1",
+			CustomerContact: "customer@example.com",
+			ProductIds:      productIDs,
+		}
+		order = entity.Order{
+			ID:              uuid.New(),
+			PointID:         pointID,
+			Number:          request.Number,
+			CustomerContact: request.CustomerContact,
+			Status:          entity.OrderStatusAssembling,
+			ProductIDs:      productIDs,
+			CreatedAt:       time.Now(),
+		}
+	)
+
+	responseJSON, _ := json.Marshal(dto.EntityOrderToDTO(&order))
+
+	type MockBehavior func(s *mock_post_order.MockOrderService)
+
+	for _, tc := range []struct {
+		name         string
+		request      post_order.Request
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name:    "success",
+			request: request,
+			mockBehavior: func(s *mock_post_order.MockOrderService) {
+				s.EXPECT().CreateOrder(gomock.Any(), pointID, request.Number, request.CustomerContact, productIDs).Return(order, nil).Times(1)
+			},
+			wantStatus: http.StatusCreated,
+			wantBody:   string(responseJSON),
+		},
+		{
+			name:    "no point found",
+			request: request,
+			mockBehavior: func(s *mock_post_order.MockOrderService) {
+				s.EXPECT().CreateOrder(gomock.Any(), pointID, request.Number, request.CustomerContact, productIDs).Return(entity.Order{}, service.ErrNoPointFound).Times(1)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   service.ErrNoPointFound.Error(),
+		},
+		{
+			name:    "order already exists",
+			request: request,
+			mockBehavior: func(s *mock_post_order.MockOrderService) {
+				s.EXPECT().CreateOrder(gomock.Any(), pointID, request.Number, request.CustomerContact, productIDs).Return(entity.Order{}, service.ErrOrderAlreadyExists).Times(1)
+			},
+			wantStatus: http.StatusConflict,
+			wantBody:   service.ErrOrderAlreadyExists.Error(),
+		},
+		{
+			name:    "products unavailable",
+			request: request,
+			mockBehavior: func(s *mock_post_order.MockOrderService) {
+				s.EXPECT().CreateOrder(gomock.Any(), pointID, request.Number, request.CustomerContact, productIDs).Return(entity.Order{}, service.ErrProductsUnavailable).Times(1)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   service.ErrProductsUnavailable.Error(),
+		},
+		{
+			name:    "internal error",
+			request: request,
+			mockBehavior: func(s *mock_post_order.MockOrderService) {
+				s.EXPECT().CreateOrder(gomock.Any(), pointID, request.Number, request.CustomerContact, productIDs).Return(entity.Order{}, arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+		{
+			name: "no products provided",
+			request: post_order.Request{
+				PvzId:           pointID,
+				Number:          request.Number,
+				CustomerContact: request.CustomerContact,
+			},
+			mockBehavior: func(s *mock_post_order.MockOrderService) {},
+			wantStatus:   http.StatusBadRequest,
+			wantBody:     "field productIds is required",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+
+			requestBody, _ := json.Marshal(tc.request)
+
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_post_order.NewMockOrderService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := post_order.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, strings.Trim(rec.Body.String(), "\n"))
+			}
+		})
+	}
+}
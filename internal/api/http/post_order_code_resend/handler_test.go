@@ -0,0 +1,132 @@
+package post_order_code_resend_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_order_code_resend"
+	mock_post_order_code_resend "github.com/4udiwe/avito-pvz/internal/api/http/post_order_code_resend/mocks"
+	service "github.com/4udiwe/avito-pvz/internal/service/order"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		orderID      = uuid.New()
+		arbitraryErr = errors.New("arbitrary error")
+	)
+
+	type MockBehavior func(s *mock_post_order_code_resend.MockOrderService)
+
+	for _, tc := range []struct {
+		name         string
+		orderID      string
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name:    "success",
+			orderID: orderID.String(),
+			mockBehavior: func(s *mock_post_order_code_resend.MockOrderService) {
+				s.EXPECT().ResendPickupCode(gomock.Any(), orderID).Return(nil).Times(1)
+			},
+			wantStatus: http.StatusAccepted,
+			wantBody:   "",
+		},
+		{
+			name:    "no order found",
+			orderID: orderID.String(),
+			mockBehavior: func(s *mock_post_order_code_resend.MockOrderService) {
+				s.EXPECT().ResendPickupCode(gomock.Any(), orderID).Return(service.ErrNoOrderFound).Times(1)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   service.ErrNoOrderFound.Error(),
+		},
+		{
+			name:    "order not ready",
+			orderID: orderID.String(),
+			mockBehavior: func(s *mock_post_order_code_resend.MockOrderService) {
+				s.EXPECT().ResendPickupCode(gomock.Any(), orderID).Return(service.ErrOrderNotReady).Times(1)
+			},
+			wantStatus: http.StatusConflict,
+			wantBody:   service.ErrOrderNotReady.Error(),
+		},
+		{
+			name:    "order locked",
+			orderID: orderID.String(),
+			mockBehavior: func(s *mock_post_order_code_resend.MockOrderService) {
+				s.EXPECT().ResendPickupCode(gomock.Any(), orderID).Return(service.ErrTooManyAttempts).Times(1)
+			},
+			wantStatus: http.StatusTooManyRequests,
+			wantBody:   service.ErrTooManyAttempts.Error(),
+		},
+		{
+			name:    "code not sent",
+			orderID: orderID.String(),
+			mockBehavior: func(s *mock_post_order_code_resend.MockOrderService) {
+				s.EXPECT().ResendPickupCode(gomock.Any(), orderID).Return(fmt.Errorf("%w: %w", service.ErrPickupCodeNotSent, arbitraryErr)).Times(1)
+			},
+			wantStatus: http.StatusFailedDependency,
+			wantBody:   service.ErrPickupCodeNotSent.Error(),
+		},
+		{
+			name:    "internal error",
+			orderID: orderID.String(),
+			mockBehavior: func(s *mock_post_order_code_resend.MockOrderService) {
+				s.EXPECT().ResendPickupCode(gomock.Any(), orderID).Return(arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+		{
+			name:         "invalid order id provided",
+			orderID:      "123",
+			mockBehavior: func(s *mock_post_order_code_resend.MockOrderService) {},
+			wantStatus:   http.StatusBadRequest,
+			wantBody:     "invalid UUID length: 3",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctx.SetParamNames("orderId")
+			ctx.SetParamValues(tc.orderID)
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_post_order_code_resend.NewMockOrderService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := post_order_code_resend.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package post_order_issue
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type OrderService interface {
+	IssueOrder(ctx context.Context, orderID uuid.UUID, code string, actorID uuid.UUID) error
+}
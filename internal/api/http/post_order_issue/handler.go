@@ -0,0 +1,58 @@
+package post_order_issue
+
+import (
+	"errors"
+	"net/http"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	service "github.com/4udiwe/avito-pvz/internal/service/order"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s OrderService
+}
+
+func New(orderService OrderService) api.Handler {
+	return decorator.NewBindAndValidateDerocator(&handler{s: orderService})
+}
+
+type Request struct {
+	OrderID uuid.UUID `param:"orderId" validate:"required"`
+	Code    string    `json:"code" validate:"required,len=6"`
+}
+
+func (h *handler) Handle(ctx echo.Context, in Request) error {
+	claims, err := middleware.GetUserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = h.s.IssueOrder(ctx.Request().Context(), in.OrderID, in.Code, claims.UserID)
+
+	if err != nil {
+		if errors.Is(err, service.ErrNoOrderFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrOrderNotReady) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrProductsNotStored) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrInvalidPickupCode) {
+			return echo.NewHTTPError(http.StatusForbidden, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrPickupCodeExpired) {
+			return echo.NewHTTPError(http.StatusForbidden, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrTooManyAttempts) {
+			return echo.NewHTTPError(http.StatusTooManyRequests, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return ctx.NoContent(http.StatusOK)
+}
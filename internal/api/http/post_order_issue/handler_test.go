@@ -0,0 +1,143 @@
+package post_order_issue_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_order_issue"
+	mock_post_order_issue "github.com/4udiwe/avito-pvz/internal/api/http/post_order_issue/mocks"
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	service "github.com/4udiwe/avito-pvz/internal/service/order"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		orderID      = uuid.New()
+		actorID      = uuid.New()
+		code         = "123456"
+		arbitraryErr = errors.New("arbitrary error")
+	)
+
+	type MockBehavior func(s *mock_post_order_issue.MockOrderService)
+
+	for _, tc := range []struct {
+		name         string
+		code         string
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name: "success",
+			code: code,
+			mockBehavior: func(s *mock_post_order_issue.MockOrderService) {
+				s.EXPECT().IssueOrder(gomock.Any(), orderID, code, actorID).Return(nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   "",
+		},
+		{
+			name: "no order found",
+			code: code,
+			mockBehavior: func(s *mock_post_order_issue.MockOrderService) {
+				s.EXPECT().IssueOrder(gomock.Any(), orderID, code, actorID).Return(service.ErrNoOrderFound).Times(1)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   service.ErrNoOrderFound.Error(),
+		},
+		{
+			name: "order not ready",
+			code: code,
+			mockBehavior: func(s *mock_post_order_issue.MockOrderService) {
+				s.EXPECT().IssueOrder(gomock.Any(), orderID, code, actorID).Return(service.ErrOrderNotReady).Times(1)
+			},
+			wantStatus: http.StatusConflict,
+			wantBody:   service.ErrOrderNotReady.Error(),
+		},
+		{
+			name: "invalid code",
+			code: code,
+			mockBehavior: func(s *mock_post_order_issue.MockOrderService) {
+				s.EXPECT().IssueOrder(gomock.Any(), orderID, code, actorID).Return(service.ErrInvalidPickupCode).Times(1)
+			},
+			wantStatus: http.StatusForbidden,
+			wantBody:   service.ErrInvalidPickupCode.Error(),
+		},
+		{
+			name: "too many attempts",
+			code: code,
+			mockBehavior: func(s *mock_post_order_issue.MockOrderService) {
+				s.EXPECT().IssueOrder(gomock.Any(), orderID, code, actorID).Return(service.ErrTooManyAttempts).Times(1)
+			},
+			wantStatus: http.StatusTooManyRequests,
+			wantBody:   service.ErrTooManyAttempts.Error(),
+		},
+		{
+			name: "internal error",
+			code: code,
+			mockBehavior: func(s *mock_post_order_issue.MockOrderService) {
+				s.EXPECT().IssueOrder(gomock.Any(), orderID, code, actorID).Return(arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+		{
+			name:         "malformed code",
+			code:         "12",
+			mockBehavior: func(s *mock_post_order_issue.MockOrderService) {},
+			wantStatus:   http.StatusBadRequest,
+			wantBody:     "field code must be 6 characters length",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+
+			requestBody, _ := json.Marshal(map[string]string{"code": tc.code})
+
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctx.SetParamNames("orderId")
+			ctx.SetParamValues(orderID.String())
+			ctx.Set(middleware.USER_CLAIMS_KEY, &auth.TokenClaims{UserID: actorID, Role: entity.RoleEmployee})
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_post_order_issue.NewMockOrderService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := post_order_issue.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package post_order_ready
+
+import (
+	"errors"
+	"net/http"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	service "github.com/4udiwe/avito-pvz/internal/service/order"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s OrderService
+}
+
+func New(orderService OrderService) api.Handler {
+	return decorator.NewBindAndValidateDerocator(&handler{s: orderService})
+}
+
+type Request struct {
+	OrderID uuid.UUID `param:"orderId" validate:"required"`
+}
+
+func (h *handler) Handle(ctx echo.Context, in Request) error {
+	err := h.s.MarkReady(ctx.Request().Context(), in.OrderID)
+
+	if err != nil {
+		if errors.Is(err, service.ErrNoOrderFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrOrderNotAssembling) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrProductsNotStored) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrPickupCodeNotSent) {
+			return echo.NewHTTPError(http.StatusFailedDependency, service.ErrPickupCodeNotSent.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return ctx.NoContent(http.StatusAccepted)
+}
@@ -0,0 +1,132 @@
+package post_order_ready_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_order_ready"
+	mock_post_order_ready "github.com/4udiwe/avito-pvz/internal/api/http/post_order_ready/mocks"
+	service "github.com/4udiwe/avito-pvz/internal/service/order"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		orderID      = uuid.New()
+		arbitraryErr = errors.New("arbitrary error")
+	)
+
+	type MockBehavior func(s *mock_post_order_ready.MockOrderService)
+
+	for _, tc := range []struct {
+		name         string
+		orderID      string
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name:    "success",
+			orderID: orderID.String(),
+			mockBehavior: func(s *mock_post_order_ready.MockOrderService) {
+				s.EXPECT().MarkReady(gomock.Any(), orderID).Return(nil).Times(1)
+			},
+			wantStatus: http.StatusAccepted,
+			wantBody:   "",
+		},
+		{
+			name:    "no order found",
+			orderID: orderID.String(),
+			mockBehavior: func(s *mock_post_order_ready.MockOrderService) {
+				s.EXPECT().MarkReady(gomock.Any(), orderID).Return(service.ErrNoOrderFound).Times(1)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   service.ErrNoOrderFound.Error(),
+		},
+		{
+			name:    "order not assembling",
+			orderID: orderID.String(),
+			mockBehavior: func(s *mock_post_order_ready.MockOrderService) {
+				s.EXPECT().MarkReady(gomock.Any(), orderID).Return(service.ErrOrderNotAssembling).Times(1)
+			},
+			wantStatus: http.StatusConflict,
+			wantBody:   service.ErrOrderNotAssembling.Error(),
+		},
+		{
+			name:    "products not stored",
+			orderID: orderID.String(),
+			mockBehavior: func(s *mock_post_order_ready.MockOrderService) {
+				s.EXPECT().MarkReady(gomock.Any(), orderID).Return(service.ErrProductsNotStored).Times(1)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   service.ErrProductsNotStored.Error(),
+		},
+		{
+			name:    "code not sent",
+			orderID: orderID.String(),
+			mockBehavior: func(s *mock_post_order_ready.MockOrderService) {
+				s.EXPECT().MarkReady(gomock.Any(), orderID).Return(fmt.Errorf("%w: %w", service.ErrPickupCodeNotSent, arbitraryErr)).Times(1)
+			},
+			wantStatus: http.StatusFailedDependency,
+			wantBody:   service.ErrPickupCodeNotSent.Error(),
+		},
+		{
+			name:    "internal error",
+			orderID: orderID.String(),
+			mockBehavior: func(s *mock_post_order_ready.MockOrderService) {
+				s.EXPECT().MarkReady(gomock.Any(), orderID).Return(arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+		{
+			name:         "invalid order id provided",
+			orderID:      "123",
+			mockBehavior: func(s *mock_post_order_ready.MockOrderService) {},
+			wantStatus:   http.StatusBadRequest,
+			wantBody:     "invalid UUID length: 3",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctx.SetParamNames("orderId")
+			ctx.SetParamValues(tc.orderID)
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_post_order_ready.NewMockOrderService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := post_order_ready.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
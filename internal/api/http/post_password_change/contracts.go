@@ -0,0 +1,13 @@
+package post_password_change
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type UserService interface {
+	ChangePassword(ctx context.Context, userID uuid.UUID, oldPassword string, newPassword string) error
+}
@@ -0,0 +1,115 @@
+package post_password_change_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_password_change"
+	mock_post_password_change "github.com/4udiwe/avito-pvz/internal/api/http/post_password_change/mocks"
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/service/user"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		userID       = uuid.New()
+		claims       = &auth.TokenClaims{UserID: userID, Role: entity.RoleEmployee}
+		request      = post_password_change.Request{
+			OldPassword: "OldPassword1",
+			NewPassword: "NewPassword1",
+		}
+	)
+
+	type MockBehavior func(s *mock_post_password_change.MockUserService)
+
+	for _, tc := range []struct {
+		name         string
+		request      post_password_change.Request
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name:    "success",
+			request: request,
+			mockBehavior: func(s *mock_post_password_change.MockUserService) {
+				s.EXPECT().ChangePassword(gomock.Any(), userID, request.OldPassword, request.NewPassword).Return(nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:         "weak password",
+			request:      post_password_change.Request{OldPassword: request.OldPassword, NewPassword: "password"},
+			mockBehavior: func(s *mock_post_password_change.MockUserService) {},
+			wantStatus:   http.StatusBadRequest,
+			wantBody:     "field new_password must be 8 to 72 characters long and contain an uppercase letter, a lowercase letter and a digit",
+		},
+		{
+			name:    "wrong old password",
+			request: request,
+			mockBehavior: func(s *mock_post_password_change.MockUserService) {
+				s.EXPECT().ChangePassword(gomock.Any(), userID, request.OldPassword, request.NewPassword).Return(user.ErrInvalidCredentials).Times(1)
+			},
+			wantStatus: http.StatusForbidden,
+			wantBody:   user.ErrInvalidCredentials.Error(),
+		},
+		{
+			name:    "internal error",
+			request: request,
+			mockBehavior: func(s *mock_post_password_change.MockUserService) {
+				s.EXPECT().ChangePassword(gomock.Any(), userID, request.OldPassword, request.NewPassword).Return(arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+
+			requestBody, _ := json.Marshal(tc.request)
+
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctx.Set(middleware.USER_CLAIMS_KEY, claims)
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_post_password_change.NewMockUserService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := post_password_change.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package post_password_reset
+
+import (
+	"context"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type UserService interface {
+	ResetPassword(ctx context.Context, token string, newPassword string) error
+}
@@ -0,0 +1,107 @@
+package post_password_reset_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_password_reset"
+	mock_post_password_reset "github.com/4udiwe/avito-pvz/internal/api/http/post_password_reset/mocks"
+	"github.com/4udiwe/avito-pvz/internal/service/user"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		request      = post_password_reset.Request{
+			Token:       "reset-token",
+			NewPassword: "NewPassword1",
+		}
+	)
+
+	type MockBehavior func(s *mock_post_password_reset.MockUserService)
+
+	for _, tc := range []struct {
+		name         string
+		request      post_password_reset.Request
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name:    "success",
+			request: request,
+			mockBehavior: func(s *mock_post_password_reset.MockUserService) {
+				s.EXPECT().ResetPassword(gomock.Any(), request.Token, request.NewPassword).Return(nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:         "missing token",
+			request:      post_password_reset.Request{NewPassword: request.NewPassword},
+			mockBehavior: func(s *mock_post_password_reset.MockUserService) {},
+			wantStatus:   http.StatusBadRequest,
+			wantBody:     "field token is required",
+		},
+		{
+			name:    "invalid token",
+			request: request,
+			mockBehavior: func(s *mock_post_password_reset.MockUserService) {
+				s.EXPECT().ResetPassword(gomock.Any(), request.Token, request.NewPassword).Return(user.ErrInvalidResetToken).Times(1)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   user.ErrInvalidResetToken.Error(),
+		},
+		{
+			name:    "internal error",
+			request: request,
+			mockBehavior: func(s *mock_post_password_reset.MockUserService) {
+				s.EXPECT().ResetPassword(gomock.Any(), request.Token, request.NewPassword).Return(arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+
+			requestBody, _ := json.Marshal(tc.request)
+
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_post_password_reset.NewMockUserService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := post_password_reset.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+			}
+		})
+	}
+}
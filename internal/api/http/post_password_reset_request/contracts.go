@@ -0,0 +1,11 @@
+package post_password_reset_request
+
+import (
+	"context"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type UserService interface {
+	RequestPasswordReset(ctx context.Context, email string) error
+}
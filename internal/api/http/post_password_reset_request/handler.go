@@ -0,0 +1,31 @@
+package post_password_reset_request
+
+import (
+	"net/http"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s UserService
+}
+
+func New(userService UserService) api.Handler {
+	return decorator.NewBindAndValidateDerocator(&handler{s: userService})
+}
+
+type Request struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// Handle answers 202 whether or not the email is registered.
+func (h *handler) Handle(ctx echo.Context, in Request) error {
+	err := h.s.RequestPasswordReset(ctx.Request().Context(), in.Email)
+
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return ctx.NoContent(http.StatusAccepted)
+}
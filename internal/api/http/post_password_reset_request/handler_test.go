@@ -0,0 +1,94 @@
+package post_password_reset_request_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_password_reset_request"
+	mock_post_password_reset_request "github.com/4udiwe/avito-pvz/internal/api/http/post_password_reset_request/mocks"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		email        = "user@mail.com"
+	)
+
+	type MockBehavior func(s *mock_post_password_reset_request.MockUserService)
+
+	for _, tc := range []struct {
+		name         string
+		email        string
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name:  "success",
+			email: email,
+			mockBehavior: func(s *mock_post_password_reset_request.MockUserService) {
+				s.EXPECT().RequestPasswordReset(gomock.Any(), email).Return(nil).Times(1)
+			},
+			wantStatus: http.StatusAccepted,
+		},
+		{
+			name:         "invalid email",
+			email:        "not an email",
+			mockBehavior: func(s *mock_post_password_reset_request.MockUserService) {},
+			wantStatus:   http.StatusBadRequest,
+			wantBody:     "field email must be a valid email address",
+		},
+		{
+			name:  "internal error",
+			email: email,
+			mockBehavior: func(s *mock_post_password_reset_request.MockUserService) {
+				s.EXPECT().RequestPasswordReset(gomock.Any(), email).Return(arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+
+			requestBody, _ := json.Marshal(post_password_reset_request.Request{Email: tc.email})
+
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_post_password_reset_request.NewMockUserService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := post_password_reset_request.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+			}
+		})
+	}
+}
@@ -4,10 +4,11 @@ import (
 	"context"
 
 	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/google/uuid"
 )
 
 //go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
 
 type PointService interface {
-	CreatePoint(ctx context.Context, city string) (entity.Point, error)
+	CreatePoint(ctx context.Context, actorID uuid.UUID, city string) (entity.Point, error)
 }
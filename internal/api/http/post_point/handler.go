@@ -1,11 +1,12 @@
 package post_point
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
 	api "github.com/4udiwe/avito-pvz/internal/api/http"
-	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
 	"github.com/4udiwe/avito-pvz/internal/dto"
 	service "github.com/4udiwe/avito-pvz/internal/service/point"
 	"github.com/labstack/echo/v4"
@@ -15,23 +16,23 @@ type handler struct {
 	s PointService
 }
 
-func New(pointService PointService) api.Handler {
-	return decorator.NewBindAndValidateDerocator(&handler{s: pointService})
+func New(pointService PointService) api.PostPvzHandler {
+	return &handler{s: pointService}
 }
 
-type Request dto.PostPvzJSONRequestBody
+func (h *handler) PostPvz(ctx context.Context, request dto.PostPvzRequestObject) (dto.PostPvzResponseObject, error) {
+	claims, err := middleware.UserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-func (h *handler) Handle(ctx echo.Context, in Request) error {
-	point, err := h.s.CreatePoint(ctx.Request().Context(), string(in.City))
+	point, err := h.s.CreatePoint(ctx, claims.UserID, string(request.Body.City))
 
 	if err != nil {
 		if errors.Is(err, service.ErrNoCityFound) {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
 		}
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return nil, echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
 	}
-	return ctx.JSON(
-		http.StatusCreated,
-		dto.EntityPointToDTO(&point),
-	)
+	return dto.PostPvz201JSONResponse(*dto.EntityPointToDTO(&point)), nil
 }
@@ -10,12 +10,14 @@ import (
 	"testing"
 	"time"
 
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
 	"github.com/4udiwe/avito-pvz/internal/api/http/post_point"
 	mock_post_point "github.com/4udiwe/avito-pvz/internal/api/http/post_point/mocks"
+	"github.com/4udiwe/avito-pvz/internal/auth"
 	"github.com/4udiwe/avito-pvz/internal/dto"
 	"github.com/4udiwe/avito-pvz/internal/entity"
 	service "github.com/4udiwe/avito-pvz/internal/service/point"
-	"github.com/4udiwe/avito-pvz/pkg/validator"
 	"github.com/go-playground/assert/v2"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -25,12 +27,15 @@ import (
 )
 
 func TestHandle(t *testing.T) {
-	var arbitraryErr = errors.New("arbitrary error")
+	var (
+		actorID      = uuid.New()
+		arbitraryErr = errors.New("arbitrary error")
+	)
 
 	id := types.UUID(uuid.New())
 	time := time.Now()
 
-	request := post_point.Request{
+	request := dto.PostPvzJSONRequestBody{
 		City: "Москва",
 	}
 	response := dto.PVZ{
@@ -56,7 +61,7 @@ func TestHandle(t *testing.T) {
 					City:      string(request.City),
 					CreatedAt: *response.RegistrationDate,
 				}
-				s.EXPECT().CreatePoint(gomock.Any(), string(request.City)).Return(e, nil).Times(1)
+				s.EXPECT().CreatePoint(gomock.Any(), actorID, string(request.City)).Return(e, nil).Times(1)
 			},
 			wantStatus: http.StatusCreated,
 			wantBody:   string(responseJSON),
@@ -64,7 +69,7 @@ func TestHandle(t *testing.T) {
 		{
 			name: "no city found",
 			mockBehavior: func(s *mock_post_point.MockPointService) {
-				s.EXPECT().CreatePoint(gomock.Any(), string(request.City)).Return(entity.Point{}, service.ErrNoCityFound).Times(1)
+				s.EXPECT().CreatePoint(gomock.Any(), actorID, string(request.City)).Return(entity.Point{}, service.ErrNoCityFound).Times(1)
 			},
 			wantStatus: http.StatusBadRequest,
 			wantBody:   service.ErrNoCityFound.Error(),
@@ -72,17 +77,16 @@ func TestHandle(t *testing.T) {
 		{
 			name: "internal error",
 			mockBehavior: func(s *mock_post_point.MockPointService) {
-				s.EXPECT().CreatePoint(gomock.Any(), string(request.City)).Return(entity.Point{}, arbitraryErr).Times(1)
+				s.EXPECT().CreatePoint(gomock.Any(), actorID, string(request.City)).Return(entity.Point{}, arbitraryErr).Times(1)
 			},
 			wantStatus: http.StatusInternalServerError,
-			wantBody:   arbitraryErr.Error(),
+			wantBody:   http.StatusText(http.StatusInternalServerError),
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
 			e := echo.New()
-			e.Validator = validator.NewCustomValidator()
 
 			requestBody, _ := json.Marshal(request)
 
@@ -90,14 +94,15 @@ func TestHandle(t *testing.T) {
 			req.Header.Set("Content-Type", "application/json")
 			rec := httptest.NewRecorder()
 			ctx := e.NewContext(req, rec)
+			ctx.SetRequest(req.WithContext(middleware.NewUserContext(req.Context(), &auth.TokenClaims{UserID: actorID, Role: entity.RoleEmployee})))
 
 			ctrl := gomock.NewController(t)
 			MockService := mock_post_point.NewMockPointService(ctrl)
 			tc.mockBehavior(MockService)
 
-			handler := post_point.New(MockService)
+			server := &dto.ServerInterfaceWrapper{Handler: dto.NewStrictHandler(api.StrictServer{PostPvzHandler: post_point.New(MockService)}, nil)}
 
-			err := handler.Handle(ctx)
+			err := server.PostPvz(ctx)
 
 			if tc.wantStatus >= 400 {
 				require.Error(t, err)
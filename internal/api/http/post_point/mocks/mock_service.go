@@ -14,6 +14,7 @@ import (
 	reflect "reflect"
 
 	entity "github.com/4udiwe/avito-pvz/internal/entity"
+	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -42,16 +43,16 @@ func (m *MockPointService) EXPECT() *MockPointServiceMockRecorder {
 }
 
 // CreatePoint mocks base method.
-func (m *MockPointService) CreatePoint(ctx context.Context, city string) (entity.Point, error) {
+func (m *MockPointService) CreatePoint(ctx context.Context, actorID uuid.UUID, city string) (entity.Point, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreatePoint", ctx, city)
+	ret := m.ctrl.Call(m, "CreatePoint", ctx, actorID, city)
 	ret0, _ := ret[0].(entity.Point)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreatePoint indicates an expected call of CreatePoint.
-func (mr *MockPointServiceMockRecorder) CreatePoint(ctx, city any) *gomock.Call {
+func (mr *MockPointServiceMockRecorder) CreatePoint(ctx, actorID, city any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePoint", reflect.TypeOf((*MockPointService)(nil).CreatePoint), ctx, city)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePoint", reflect.TypeOf((*MockPointService)(nil).CreatePoint), ctx, actorID, city)
 }
@@ -12,7 +12,9 @@ import (
 type ProductService interface {
 	AddProduct(
 		ctx context.Context,
+		actorID uuid.UUID,
 		pointID uuid.UUID,
 		productType entity.ProductType,
+		barcode string,
 	) (entity.Product, error)
 }
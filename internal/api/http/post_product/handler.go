@@ -1,44 +1,63 @@
 package post_product
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
 
 	api "github.com/4udiwe/avito-pvz/internal/api/http"
-	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
 	"github.com/4udiwe/avito-pvz/internal/dto"
 	"github.com/4udiwe/avito-pvz/internal/entity"
 	service "github.com/4udiwe/avito-pvz/internal/service/product"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
 	"github.com/labstack/echo/v4"
+	"github.com/samber/lo"
+	"github.com/sirupsen/logrus"
 )
 
 type handler struct {
 	s ProductService
 }
 
-func New(productService ProductService) api.Handler {
-	return decorator.NewBindAndValidateDerocator(&handler{s: productService})
+func New(productService ProductService) api.PostProductsHandler {
+	return &handler{s: productService}
 }
 
-type Request dto.PostProductsJSONBody
+// maxBarcodeLength repeats the maxLength of api/swagger.yaml for when
+// request validation is turned off.
+const maxBarcodeLength = 64
 
-func (h *handler) Handle(ctx echo.Context, in Request) error {
-	product, err := h.s.AddProduct(ctx.Request().Context(), in.PvzId, entity.ProductType(in.Type))
+func (h *handler) PostProducts(ctx context.Context, request dto.PostProductsRequestObject) (dto.PostProductsResponseObject, error) {
+	claims, err := middleware.UserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	in := request.Body
+	barcode := lo.FromPtr(in.Barcode)
+	if len(barcode) > maxBarcodeLength {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("field barcode must be at most %d characters long", maxBarcodeLength))
+	}
+
+	ctx = logger.With(ctx, logrus.Fields{logger.PointID: in.PvzId})
+	product, err := h.s.AddProduct(ctx, claims.UserID, in.PvzId, entity.ProductType(in.Type), barcode)
 
 	if err != nil {
 		if errors.Is(err, service.ErrNoPointFound) {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
 		}
 		if errors.Is(err, service.ErrNoReceptionFound) {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
 		}
 		if errors.Is(err, service.ErrReceptionAlreadyClosed) {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrBarcodeAlreadyExists) {
+			return nil, echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
 		}
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return nil, echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
 	}
-	return ctx.JSON(
-		http.StatusCreated,
-		dto.EntityProductToDTO(&product),
-	)
+	return dto.PostProducts201JSONResponse(*dto.EntityProductToDTO(&product)), nil
 }
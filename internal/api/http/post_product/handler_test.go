@@ -10,12 +10,14 @@ import (
 	"testing"
 	"time"
 
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
 	"github.com/4udiwe/avito-pvz/internal/api/http/post_product"
 	mock_post_product "github.com/4udiwe/avito-pvz/internal/api/http/post_product/mocks"
+	"github.com/4udiwe/avito-pvz/internal/auth"
 	"github.com/4udiwe/avito-pvz/internal/dto"
 	"github.com/4udiwe/avito-pvz/internal/entity"
 	service "github.com/4udiwe/avito-pvz/internal/service/product"
-	"github.com/4udiwe/avito-pvz/pkg/validator"
 	"github.com/go-playground/assert/v2"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -26,6 +28,7 @@ import (
 
 func TestHandle(t *testing.T) {
 	var (
+		actorID      = uuid.New()
 		arbitraryErr = errors.New("arbitrary error")
 		PvzID        = types.UUID(uuid.New())
 		ProductID    = types.UUID(uuid.New())
@@ -33,7 +36,7 @@ func TestHandle(t *testing.T) {
 		ReceptionID  = types.UUID(uuid.New())
 		time         = time.Now()
 
-		request = post_product.Request{
+		request = dto.PostProductsJSONRequestBody{
 			PvzId: PvzID,
 			Type:  dto.PostProductsJSONBodyType(ProductType),
 		}
@@ -64,7 +67,7 @@ func TestHandle(t *testing.T) {
 					ReceptionID: ReceptionID,
 					Type:        ProductType,
 				}
-				s.EXPECT().AddProduct(gomock.Any(), request.PvzId, entity.ProductType(request.Type)).Return(e, nil).Times(1)
+				s.EXPECT().AddProduct(gomock.Any(), actorID, request.PvzId, entity.ProductType(request.Type), "").Return(e, nil).Times(1)
 			},
 			wantStatus: http.StatusCreated,
 			wantBody:   string(responseJSON),
@@ -72,7 +75,7 @@ func TestHandle(t *testing.T) {
 		{
 			name: "no point found",
 			mockBehavior: func(s *mock_post_product.MockProductService) {
-				s.EXPECT().AddProduct(gomock.Any(), request.PvzId, entity.ProductType(request.Type)).Return(entity.Product{}, service.ErrNoPointFound).Times(1)
+				s.EXPECT().AddProduct(gomock.Any(), actorID, request.PvzId, entity.ProductType(request.Type), "").Return(entity.Product{}, service.ErrNoPointFound).Times(1)
 			},
 			wantStatus: http.StatusBadRequest,
 			wantBody:   service.ErrNoPointFound.Error(),
@@ -80,7 +83,7 @@ func TestHandle(t *testing.T) {
 		{
 			name: "no reception found",
 			mockBehavior: func(s *mock_post_product.MockProductService) {
-				s.EXPECT().AddProduct(gomock.Any(), request.PvzId, entity.ProductType(request.Type)).Return(entity.Product{}, service.ErrNoReceptionFound).Times(1)
+				s.EXPECT().AddProduct(gomock.Any(), actorID, request.PvzId, entity.ProductType(request.Type), "").Return(entity.Product{}, service.ErrNoReceptionFound).Times(1)
 			},
 			wantStatus: http.StatusBadRequest,
 			wantBody:   service.ErrNoReceptionFound.Error(),
@@ -88,25 +91,32 @@ func TestHandle(t *testing.T) {
 		{
 			name: "reception already closed",
 			mockBehavior: func(s *mock_post_product.MockProductService) {
-				s.EXPECT().AddProduct(gomock.Any(), request.PvzId, entity.ProductType(request.Type)).Return(entity.Product{}, service.ErrReceptionAlreadyClosed).Times(1)
+				s.EXPECT().AddProduct(gomock.Any(), actorID, request.PvzId, entity.ProductType(request.Type), "").Return(entity.Product{}, service.ErrReceptionAlreadyClosed).Times(1)
 			},
 			wantStatus: http.StatusBadRequest,
 			wantBody:   service.ErrReceptionAlreadyClosed.Error(),
 		},
+		{
+			name: "barcode already exists",
+			mockBehavior: func(s *mock_post_product.MockProductService) {
+				s.EXPECT().AddProduct(gomock.Any(), actorID, request.PvzId, entity.ProductType(request.Type), "").Return(entity.Product{}, service.ErrBarcodeAlreadyExists).Times(1)
+			},
+			wantStatus: http.StatusConflict,
+			wantBody:   service.ErrBarcodeAlreadyExists.Error(),
+		},
 		{
 			name: "internal error",
 			mockBehavior: func(s *mock_post_product.MockProductService) {
-				s.EXPECT().AddProduct(gomock.Any(), request.PvzId, entity.ProductType(request.Type)).Return(entity.Product{}, arbitraryErr).Times(1)
+				s.EXPECT().AddProduct(gomock.Any(), actorID, request.PvzId, entity.ProductType(request.Type), "").Return(entity.Product{}, arbitraryErr).Times(1)
 			},
 			wantStatus: http.StatusInternalServerError,
-			wantBody:   arbitraryErr.Error(),
+			wantBody:   http.StatusText(http.StatusInternalServerError),
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
 			e := echo.New()
-			e.Validator = validator.NewCustomValidator()
 
 			requestBody, _ := json.Marshal(request)
 
@@ -114,14 +124,15 @@ func TestHandle(t *testing.T) {
 			req.Header.Set("Content-Type", "application/json")
 			rec := httptest.NewRecorder()
 			ctx := e.NewContext(req, rec)
+			ctx.SetRequest(req.WithContext(middleware.NewUserContext(req.Context(), &auth.TokenClaims{UserID: actorID, Role: entity.RoleEmployee})))
 
 			ctrl := gomock.NewController(t)
 			MockService := mock_post_product.NewMockProductService(ctrl)
 			tc.mockBehavior(MockService)
 
-			handler := post_product.New(MockService)
+			server := &dto.ServerInterfaceWrapper{Handler: dto.NewStrictHandler(api.StrictServer{PostProductsHandler: post_product.New(MockService)}, nil)}
 
-			err := handler.Handle(ctx)
+			err := server.PostProducts(ctx)
 
 			if tc.wantStatus >= 400 {
 				require.Error(t, err)
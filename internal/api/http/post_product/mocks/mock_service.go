@@ -43,16 +43,16 @@ func (m *MockProductService) EXPECT() *MockProductServiceMockRecorder {
 }
 
 // AddProduct mocks base method.
-func (m *MockProductService) AddProduct(ctx context.Context, pointID uuid.UUID, productType entity.ProductType) (entity.Product, error) {
+func (m *MockProductService) AddProduct(ctx context.Context, actorID, pointID uuid.UUID, productType entity.ProductType, barcode string) (entity.Product, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddProduct", ctx, pointID, productType)
+	ret := m.ctrl.Call(m, "AddProduct", ctx, actorID, pointID, productType, barcode)
 	ret0, _ := ret[0].(entity.Product)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // AddProduct indicates an expected call of AddProduct.
-func (mr *MockProductServiceMockRecorder) AddProduct(ctx, pointID, productType any) *gomock.Call {
+func (mr *MockProductServiceMockRecorder) AddProduct(ctx, actorID, pointID, productType, barcode any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddProduct", reflect.TypeOf((*MockProductService)(nil).AddProduct), ctx, pointID, productType)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddProduct", reflect.TypeOf((*MockProductService)(nil).AddProduct), ctx, actorID, pointID, productType, barcode)
 }
@@ -0,0 +1,14 @@
+package post_product_cell
+
+import (
+	"context"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/google/uuid"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type CellService interface {
+	AssignProduct(ctx context.Context, productID uuid.UUID, cellID *uuid.UUID, actorID uuid.UUID) (entity.StorageCell, error)
+}
@@ -0,0 +1,63 @@
+package post_product_cell
+
+import (
+	"errors"
+	"net/http"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	service "github.com/4udiwe/avito-pvz/internal/service/cell"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s CellService
+}
+
+func New(cellService CellService) api.Handler {
+	return decorator.NewBindAndValidateDerocator(&handler{s: cellService})
+}
+
+// Request places the product into CellID, or into the suggested cell when it is omitted.
+type Request struct {
+	ProductID uuid.UUID  `param:"productId" validate:"required"`
+	CellID    *uuid.UUID `json:"cellId"`
+}
+
+func (h *handler) Handle(ctx echo.Context, in Request) error {
+	claims, err := middleware.GetUserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	cell, err := h.s.AssignProduct(ctx.Request().Context(), in.ProductID, in.CellID, claims.UserID)
+
+	if err != nil {
+		if errors.Is(err, service.ErrNoProductFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrNoCellFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrProductNotPresent) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrCellFull) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrNoFreeCell) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrCellWrongPoint) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrCellIncompatible) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return ctx.JSON(http.StatusOK, dto.EntityCellToDTO(&cell))
+}
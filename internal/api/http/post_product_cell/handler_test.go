@@ -0,0 +1,149 @@
+package post_product_cell_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_product_cell"
+	mock_post_product_cell "github.com/4udiwe/avito-pvz/internal/api/http/post_product_cell/mocks"
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	service "github.com/4udiwe/avito-pvz/internal/service/cell"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		productID    = uuid.New()
+		actorID      = uuid.New()
+		cellID       = uuid.New()
+
+		cell = entity.StorageCell{ID: cellID, PointID: uuid.New(), Rack: "A", Shelf: "1", Bin: "2", Capacity: 5, Occupied: 1}
+	)
+
+	responseJSON, _ := json.Marshal(dto.EntityCellToDTO(&cell))
+
+	type MockBehavior func(s *mock_post_product_cell.MockCellService)
+
+	for _, tc := range []struct {
+		name         string
+		body         string
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name: "success with explicit cell",
+			body: `{"cellId":"` + cellID.String() + `"}`,
+			mockBehavior: func(s *mock_post_product_cell.MockCellService) {
+				s.EXPECT().AssignProduct(gomock.Any(), productID, &cellID, actorID).Return(cell, nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   string(responseJSON),
+		},
+		{
+			name: "success with suggested cell",
+			body: `{}`,
+			mockBehavior: func(s *mock_post_product_cell.MockCellService) {
+				s.EXPECT().AssignProduct(gomock.Any(), productID, nil, actorID).Return(cell, nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   string(responseJSON),
+		},
+		{
+			name: "no product found",
+			body: `{}`,
+			mockBehavior: func(s *mock_post_product_cell.MockCellService) {
+				s.EXPECT().AssignProduct(gomock.Any(), productID, nil, actorID).Return(entity.StorageCell{}, service.ErrNoProductFound).Times(1)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   service.ErrNoProductFound.Error(),
+		},
+		{
+			name: "no free cell",
+			body: `{}`,
+			mockBehavior: func(s *mock_post_product_cell.MockCellService) {
+				s.EXPECT().AssignProduct(gomock.Any(), productID, nil, actorID).Return(entity.StorageCell{}, service.ErrNoFreeCell).Times(1)
+			},
+			wantStatus: http.StatusConflict,
+			wantBody:   service.ErrNoFreeCell.Error(),
+		},
+		{
+			name: "cell full",
+			body: `{"cellId":"` + cellID.String() + `"}`,
+			mockBehavior: func(s *mock_post_product_cell.MockCellService) {
+				s.EXPECT().AssignProduct(gomock.Any(), productID, &cellID, actorID).Return(entity.StorageCell{}, service.ErrCellFull).Times(1)
+			},
+			wantStatus: http.StatusConflict,
+			wantBody:   service.ErrCellFull.Error(),
+		},
+		{
+			name: "incompatible cell",
+			body: `{"cellId":"` + cellID.String() + `"}`,
+			mockBehavior: func(s *mock_post_product_cell.MockCellService) {
+				s.EXPECT().AssignProduct(gomock.Any(), productID, &cellID, actorID).Return(entity.StorageCell{}, service.ErrCellIncompatible).Times(1)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   service.ErrCellIncompatible.Error(),
+		},
+		{
+			name: "internal error",
+			body: `{}`,
+			mockBehavior: func(s *mock_post_product_cell.MockCellService) {
+				s.EXPECT().AssignProduct(gomock.Any(), productID, nil, actorID).Return(entity.StorageCell{}, arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(tc.body)))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctx.SetParamNames("productId")
+			ctx.SetParamValues(productID.String())
+			ctx.Set(middleware.USER_CLAIMS_KEY, &auth.TokenClaims{UserID: actorID, Role: entity.RoleEmployee})
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_post_product_cell.NewMockCellService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := post_product_cell.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, strings.Trim(rec.Body.String(), "\n"))
+			}
+		})
+	}
+}
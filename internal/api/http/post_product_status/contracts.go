@@ -0,0 +1,20 @@
+package post_product_status
+
+import (
+	"context"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/google/uuid"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type ProductService interface {
+	ChangeStatus(
+		ctx context.Context,
+		productID uuid.UUID,
+		to entity.ProductStatus,
+		actorID uuid.UUID,
+		reason string,
+	) (entity.Product, error)
+}
@@ -0,0 +1,110 @@
+package post_product_status
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	service "github.com/4udiwe/avito-pvz/internal/service/product"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s ProductService
+}
+
+// New returns the handler of store, issue, return and write_off.
+func New(productService ProductService) api.ProductStatusHandler {
+	return &handler{s: productService}
+}
+
+// maxReasonLength repeats the maxLength of api/swagger.yaml for when
+// request validation is turned off.
+const maxReasonLength = 1024
+
+func (h *handler) PostProductsProductIdStore(
+	ctx context.Context,
+	request dto.PostProductsProductIdStoreRequestObject,
+) (dto.PostProductsProductIdStoreResponseObject, error) {
+	product, err := h.changeStatus(ctx, request.ProductId, entity.ProductStatusStored, request.Body)
+	if err != nil {
+		return nil, err
+	}
+	return dto.PostProductsProductIdStore200JSONResponse(*product), nil
+}
+
+func (h *handler) PostProductsProductIdIssue(
+	ctx context.Context,
+	request dto.PostProductsProductIdIssueRequestObject,
+) (dto.PostProductsProductIdIssueResponseObject, error) {
+	product, err := h.changeStatus(ctx, request.ProductId, entity.ProductStatusIssued, request.Body)
+	if err != nil {
+		return nil, err
+	}
+	return dto.PostProductsProductIdIssue200JSONResponse(*product), nil
+}
+
+func (h *handler) PostProductsProductIdReturn(
+	ctx context.Context,
+	request dto.PostProductsProductIdReturnRequestObject,
+) (dto.PostProductsProductIdReturnResponseObject, error) {
+	product, err := h.changeStatus(ctx, request.ProductId, entity.ProductStatusReturned, request.Body)
+	if err != nil {
+		return nil, err
+	}
+	return dto.PostProductsProductIdReturn200JSONResponse(*product), nil
+}
+
+func (h *handler) PostProductsProductIdWriteOff(
+	ctx context.Context,
+	request dto.PostProductsProductIdWriteOffRequestObject,
+) (dto.PostProductsProductIdWriteOffResponseObject, error) {
+	product, err := h.changeStatus(ctx, request.ProductId, entity.ProductStatusWrittenOff, request.Body)
+	if err != nil {
+		return nil, err
+	}
+	return dto.PostProductsProductIdWriteOff200JSONResponse(*product), nil
+}
+
+func (h *handler) changeStatus(ctx context.Context, productID uuid.UUID, to entity.ProductStatus, body *dto.ProductStatusChange) (*dto.Product, error) {
+	claims, err := middleware.UserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var reason string
+	if body != nil && body.Reason != nil {
+		reason = *body.Reason
+	}
+	if len(reason) > maxReasonLength {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("field reason must be at most %d characters long", maxReasonLength))
+	}
+
+	product, err := h.s.ChangeStatus(ctx, productID, to, claims.UserID, reason)
+
+	if err != nil {
+		if errors.Is(err, service.ErrNoProductFound) {
+			return nil, echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrNoReceptionFound) {
+			return nil, echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrInvalidTransition) {
+			return nil, echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrReceptionNotClosed) {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrReasonRequired) {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		return nil, echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return dto.EntityProductToDTO(&product), nil
+}
@@ -0,0 +1,197 @@
+package post_product_status_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_product_status"
+	mock_post_product_status "github.com/4udiwe/avito-pvz/internal/api/http/post_product_status/mocks"
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	service "github.com/4udiwe/avito-pvz/internal/service/product"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		productID    = uuid.New()
+		receptionID  = uuid.New()
+		actorID      = uuid.New()
+		reason       = "customer refused"
+		to           = entity.ProductStatusReturned
+		now          = time.Now()
+
+		product = entity.Product{
+			ID:          productID,
+			ReceptionID: receptionID,
+			CreatedAt:   now,
+			Type:        entity.ProductTypeClothes,
+			Status:      to,
+		}
+	)
+
+	responseJSON, _ := json.Marshal(dto.EntityProductToDTO(&product))
+
+	type MockBehavior func(s *mock_post_product_status.MockProductService)
+
+	for _, tc := range []struct {
+		name         string
+		productID    string
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name:      "success",
+			productID: productID.String(),
+			mockBehavior: func(s *mock_post_product_status.MockProductService) {
+				s.EXPECT().ChangeStatus(gomock.Any(), productID, to, actorID, reason).Return(product, nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   string(responseJSON),
+		},
+		{
+			name:      "no product found",
+			productID: productID.String(),
+			mockBehavior: func(s *mock_post_product_status.MockProductService) {
+				s.EXPECT().ChangeStatus(gomock.Any(), productID, to, actorID, reason).Return(entity.Product{}, service.ErrNoProductFound).Times(1)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   service.ErrNoProductFound.Error(),
+		},
+		{
+			name:      "invalid transition",
+			productID: productID.String(),
+			mockBehavior: func(s *mock_post_product_status.MockProductService) {
+				s.EXPECT().ChangeStatus(gomock.Any(), productID, to, actorID, reason).Return(entity.Product{}, service.ErrInvalidTransition).Times(1)
+			},
+			wantStatus: http.StatusConflict,
+			wantBody:   service.ErrInvalidTransition.Error(),
+		},
+		{
+			name:      "reception not closed",
+			productID: productID.String(),
+			mockBehavior: func(s *mock_post_product_status.MockProductService) {
+				s.EXPECT().ChangeStatus(gomock.Any(), productID, to, actorID, reason).Return(entity.Product{}, service.ErrReceptionNotClosed).Times(1)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   service.ErrReceptionNotClosed.Error(),
+		},
+		{
+			name:      "reason required",
+			productID: productID.String(),
+			mockBehavior: func(s *mock_post_product_status.MockProductService) {
+				s.EXPECT().ChangeStatus(gomock.Any(), productID, to, actorID, reason).Return(entity.Product{}, service.ErrReasonRequired).Times(1)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   service.ErrReasonRequired.Error(),
+		},
+		{
+			name:      "internal error",
+			productID: productID.String(),
+			mockBehavior: func(s *mock_post_product_status.MockProductService) {
+				s.EXPECT().ChangeStatus(gomock.Any(), productID, to, actorID, reason).Return(entity.Product{}, arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+		{
+			name:         "invalid product id provided",
+			productID:    "123",
+			mockBehavior: func(s *mock_post_product_status.MockProductService) {},
+			wantStatus:   http.StatusBadRequest,
+			wantBody:     "Invalid format for parameter productId: error unmarshaling '123' text as *uuid.UUID: invalid UUID length: 3",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+
+			requestBody, _ := json.Marshal(map[string]string{"reason": reason})
+
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctx.SetRequest(req.WithContext(middleware.NewUserContext(req.Context(), &auth.TokenClaims{UserID: actorID, Role: entity.RoleEmployee})))
+			ctx.SetParamNames("productId")
+			ctx.SetParamValues(tc.productID)
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_post_product_status.NewMockProductService(ctrl)
+			tc.mockBehavior(MockService)
+
+			server := newServer(MockService)
+
+			err := server.PostProductsProductIdReturn(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, strings.Trim(rec.Body.String(), "\n"))
+			}
+		})
+	}
+}
+
+// TestStatuses checks that each operation moves the product into its own
+// status, with or without a reason.
+func TestStatuses(t *testing.T) {
+	productID := uuid.New()
+	actorID := uuid.New()
+
+	for _, tc := range []struct {
+		status entity.ProductStatus
+		call   func(server *dto.ServerInterfaceWrapper, c echo.Context) error
+	}{
+		{entity.ProductStatusStored, (*dto.ServerInterfaceWrapper).PostProductsProductIdStore},
+		{entity.ProductStatusIssued, (*dto.ServerInterfaceWrapper).PostProductsProductIdIssue},
+		{entity.ProductStatusReturned, (*dto.ServerInterfaceWrapper).PostProductsProductIdReturn},
+		{entity.ProductStatusWrittenOff, (*dto.ServerInterfaceWrapper).PostProductsProductIdWriteOff},
+	} {
+		t.Run(string(tc.status), func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+			ctx.SetRequest(req.WithContext(middleware.NewUserContext(req.Context(), &auth.TokenClaims{UserID: actorID, Role: entity.RoleEmployee})))
+			ctx.SetParamNames("productId")
+			ctx.SetParamValues(productID.String())
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_post_product_status.NewMockProductService(ctrl)
+			MockService.EXPECT().ChangeStatus(gomock.Any(), productID, tc.status, actorID, "").
+				Return(entity.Product{ID: productID, Status: tc.status}, nil).Times(1)
+
+			require.NoError(t, tc.call(newServer(MockService), ctx))
+			assert.Equal(t, http.StatusOK, rec.Code)
+		})
+	}
+}
+
+func newServer(s post_product_status.ProductService) *dto.ServerInterfaceWrapper {
+	return &dto.ServerInterfaceWrapper{Handler: dto.NewStrictHandler(api.StrictServer{ProductStatusHandler: post_product_status.New(s)}, nil)}
+}
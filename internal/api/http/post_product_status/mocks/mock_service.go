@@ -0,0 +1,58 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: contracts.go
+//
+// Generated by this command:
+//
+//	mockgen -source=contracts.go -destination=mocks/mock_service.go
+//
+
+// Package mock_post_product_status is a generated GoMock package.
+package mock_post_product_status
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/4udiwe/avito-pvz/internal/entity"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProductService is a mock of ProductService interface.
+type MockProductService struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockProductServiceMockRecorder is the mock recorder for MockProductService.
+type MockProductServiceMockRecorder struct {
+	mock *MockProductService
+}
+
+// NewMockProductService creates a new mock instance.
+func NewMockProductService(ctrl *gomock.Controller) *MockProductService {
+	mock := &MockProductService{ctrl: ctrl}
+	mock.recorder = &MockProductServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductService) EXPECT() *MockProductServiceMockRecorder {
+	return m.recorder
+}
+
+// ChangeStatus mocks base method.
+func (m *MockProductService) ChangeStatus(ctx context.Context, productID uuid.UUID, to entity.ProductStatus, actorID uuid.UUID, reason string) (entity.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeStatus", ctx, productID, to, actorID, reason)
+	ret0, _ := ret[0].(entity.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeStatus indicates an expected call of ChangeStatus.
+func (mr *MockProductServiceMockRecorder) ChangeStatus(ctx, productID, to, actorID, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeStatus", reflect.TypeOf((*MockProductService)(nil).ChangeStatus), ctx, productID, to, actorID, reason)
+}
@@ -10,5 +10,5 @@ import (
 //go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
 
 type ReceptionService interface {
-	OpenReception(ctx context.Context, pointID uuid.UUID) (entity.Reception, error)
+	OpenReception(ctx context.Context, actorID uuid.UUID, pointID uuid.UUID, kind entity.ReceptionKind) (entity.Reception, error)
 }
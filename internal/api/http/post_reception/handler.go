@@ -1,40 +1,50 @@
 package post_reception
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
 	api "github.com/4udiwe/avito-pvz/internal/api/http"
-	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
 	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
 	service "github.com/4udiwe/avito-pvz/internal/service/reception"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
 	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
 )
 
 type handler struct {
 	s ReceptionService
 }
 
-func New(receptionService ReceptionService) api.Handler {
-	return decorator.NewBindAndValidateDerocator(&handler{s: receptionService})
+func New(receptionService ReceptionService) api.PostReceptionsHandler {
+	return &handler{s: receptionService}
 }
 
-type Request dto.PostReceptionsJSONBody
+func (h *handler) PostReceptions(ctx context.Context, request dto.PostReceptionsRequestObject) (dto.PostReceptionsResponseObject, error) {
+	claims, err := middleware.UserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	kind := entity.ReceptionKindRegular
+	if request.Body.Kind != nil {
+		kind = entity.ReceptionKind(*request.Body.Kind)
+	}
 
-func (h *handler) Handle(ctx echo.Context, in Request) error {
-	reception, err := h.s.OpenReception(ctx.Request().Context(), in.PvzId)
+	ctx = logger.With(ctx, logrus.Fields{logger.PointID: request.Body.PvzId})
+	reception, err := h.s.OpenReception(ctx, claims.UserID, request.Body.PvzId, kind)
 
 	if err != nil {
 		if errors.Is(err, service.ErrNoPointFound) {
-			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+			return nil, echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
 		}
 		if errors.Is(err, service.ErrLastReceptionNotClosed) {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
 		}
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return nil, echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
 	}
-	return ctx.JSON(
-		http.StatusCreated,
-		dto.EntityReceptionToDTO(&reception),
-	)
+	return dto.PostReceptions201JSONResponse(*dto.EntityReceptionToDTO(&reception)), nil
 }
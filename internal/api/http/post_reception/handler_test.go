@@ -10,12 +10,14 @@ import (
 	"testing"
 	"time"
 
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
 	"github.com/4udiwe/avito-pvz/internal/api/http/post_reception"
 	mock_post_reception "github.com/4udiwe/avito-pvz/internal/api/http/post_reception/mocks"
+	"github.com/4udiwe/avito-pvz/internal/auth"
 	"github.com/4udiwe/avito-pvz/internal/dto"
 	"github.com/4udiwe/avito-pvz/internal/entity"
 	service "github.com/4udiwe/avito-pvz/internal/service/reception"
-	"github.com/4udiwe/avito-pvz/pkg/validator"
 	"github.com/go-playground/assert/v2"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -26,6 +28,7 @@ import (
 
 func TestHandle(t *testing.T) {
 	var (
+		actorID         = uuid.New()
 		arbitraryErr    = errors.New("arbitrary error")
 		pointID         = types.UUID(uuid.New())
 		receptionID     = types.UUID(uuid.New())
@@ -33,7 +36,7 @@ func TestHandle(t *testing.T) {
 		time            = time.Now()
 	)
 
-	request := post_reception.Request{
+	request := dto.PostReceptionsJSONRequestBody{
 		PvzId: pointID,
 	}
 	response := dto.Reception{
@@ -61,7 +64,7 @@ func TestHandle(t *testing.T) {
 					CreatedAt: time,
 					Status:    receptionStatus,
 				}
-				s.EXPECT().OpenReception(gomock.Any(), pointID).Return(e, nil).Times(1)
+				s.EXPECT().OpenReception(gomock.Any(), actorID, pointID, entity.ReceptionKindRegular).Return(e, nil).Times(1)
 			},
 			wantStatus: http.StatusCreated,
 			wantBody:   string(responseJSON),
@@ -69,7 +72,7 @@ func TestHandle(t *testing.T) {
 		{
 			name: "no point found",
 			mockBehavior: func(s *mock_post_reception.MockReceptionService) {
-				s.EXPECT().OpenReception(gomock.Any(), pointID).Return(entity.Reception{}, service.ErrNoPointFound).Times(1)
+				s.EXPECT().OpenReception(gomock.Any(), actorID, pointID, entity.ReceptionKindRegular).Return(entity.Reception{}, service.ErrNoPointFound).Times(1)
 			},
 			wantStatus: http.StatusNotFound,
 			wantBody:   service.ErrNoPointFound.Error(),
@@ -77,7 +80,7 @@ func TestHandle(t *testing.T) {
 		{
 			name: "last reception not closed",
 			mockBehavior: func(s *mock_post_reception.MockReceptionService) {
-				s.EXPECT().OpenReception(gomock.Any(), pointID).Return(entity.Reception{}, service.ErrLastReceptionNotClosed).Times(1)
+				s.EXPECT().OpenReception(gomock.Any(), actorID, pointID, entity.ReceptionKindRegular).Return(entity.Reception{}, service.ErrLastReceptionNotClosed).Times(1)
 			},
 			wantStatus: http.StatusBadRequest,
 			wantBody:   service.ErrLastReceptionNotClosed.Error(),
@@ -85,17 +88,16 @@ func TestHandle(t *testing.T) {
 		{
 			name: "internal error",
 			mockBehavior: func(s *mock_post_reception.MockReceptionService) {
-				s.EXPECT().OpenReception(gomock.Any(), pointID).Return(entity.Reception{}, arbitraryErr).Times(1)
+				s.EXPECT().OpenReception(gomock.Any(), actorID, pointID, entity.ReceptionKindRegular).Return(entity.Reception{}, arbitraryErr).Times(1)
 			},
 			wantStatus: http.StatusInternalServerError,
-			wantBody:   arbitraryErr.Error(),
+			wantBody:   http.StatusText(http.StatusInternalServerError),
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
 			e := echo.New()
-			e.Validator = validator.NewCustomValidator()
 
 			requestBody, _ := json.Marshal(request)
 
@@ -103,14 +105,15 @@ func TestHandle(t *testing.T) {
 			req.Header.Set("Content-Type", "application/json")
 			rec := httptest.NewRecorder()
 			ctx := e.NewContext(req, rec)
+			ctx.SetRequest(req.WithContext(middleware.NewUserContext(req.Context(), &auth.TokenClaims{UserID: actorID, Role: entity.RoleEmployee})))
 
 			ctrl := gomock.NewController(t)
 			MockService := mock_post_reception.NewMockReceptionService(ctrl)
 			tc.mockBehavior(MockService)
 
-			handler := post_reception.New(MockService)
+			server := &dto.ServerInterfaceWrapper{Handler: dto.NewStrictHandler(api.StrictServer{PostReceptionsHandler: post_reception.New(MockService)}, nil)}
 
-			err := handler.Handle(ctx)
+			err := server.PostReceptions(ctx)
 
 			if tc.wantStatus >= 400 {
 				require.Error(t, err)
@@ -43,16 +43,16 @@ func (m *MockReceptionService) EXPECT() *MockReceptionServiceMockRecorder {
 }
 
 // OpenReception mocks base method.
-func (m *MockReceptionService) OpenReception(ctx context.Context, pointID uuid.UUID) (entity.Reception, error) {
+func (m *MockReceptionService) OpenReception(ctx context.Context, actorID, pointID uuid.UUID, kind entity.ReceptionKind) (entity.Reception, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "OpenReception", ctx, pointID)
+	ret := m.ctrl.Call(m, "OpenReception", ctx, actorID, pointID, kind)
 	ret0, _ := ret[0].(entity.Reception)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // OpenReception indicates an expected call of OpenReception.
-func (mr *MockReceptionServiceMockRecorder) OpenReception(ctx, pointID any) *gomock.Call {
+func (mr *MockReceptionServiceMockRecorder) OpenReception(ctx, actorID, pointID, kind any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenReception", reflect.TypeOf((*MockReceptionService)(nil).OpenReception), ctx, pointID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenReception", reflect.TypeOf((*MockReceptionService)(nil).OpenReception), ctx, actorID, pointID, kind)
 }
@@ -4,10 +4,11 @@ import (
 	"context"
 
 	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/entity"
 )
 
 //go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
 
 type UserService interface {
-	RefreshTokens(ctx context.Context, refreshToken string) (*auth.Tokens, error)
+	RefreshTokens(ctx context.Context, refreshToken string, client entity.ClientInfo) (*auth.Tokens, error)
 }
@@ -6,6 +6,7 @@ import (
 
 	api "github.com/4udiwe/avito-pvz/internal/api/http"
 	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/entity"
 	"github.com/4udiwe/avito-pvz/internal/service/user"
 	"github.com/labstack/echo/v4"
 )
@@ -23,13 +24,17 @@ type Request struct {
 }
 
 func (h *handler) Handle(ctx echo.Context, in Request) error {
-	tokens, err := h.s.RefreshTokens(ctx.Request().Context(), in.RefreshToken)
+	tokens, err := h.s.RefreshTokens(
+		ctx.Request().Context(),
+		in.RefreshToken,
+		entity.ClientInfo{UserAgent: ctx.Request().UserAgent(), IP: ctx.RealIP()},
+	)
 
 	if err != nil {
-		if errors.Is(err, user.ErrInvalidRefreshToken) {
-			return echo.NewHTTPError(http.StatusForbidden, err.Error())
+		if errors.Is(err, user.ErrInvalidRefreshToken) || errors.Is(err, user.ErrUserDisabled) || errors.Is(err, user.ErrMFARequired) {
+			return echo.NewHTTPError(http.StatusForbidden, err.Error()).SetInternal(err)
 		}
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
 	}
 	return ctx.JSON(http.StatusCreated, tokens)
 }
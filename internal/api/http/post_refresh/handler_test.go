@@ -12,6 +12,7 @@ import (
 	"github.com/4udiwe/avito-pvz/internal/api/http/post_refresh"
 	mock_post_refresh "github.com/4udiwe/avito-pvz/internal/api/http/post_refresh/mocks"
 	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/entity"
 	"github.com/4udiwe/avito-pvz/internal/service/user"
 	"github.com/4udiwe/avito-pvz/pkg/validator"
 	"github.com/go-playground/assert/v2"
@@ -22,6 +23,7 @@ import (
 
 func TestHandle(t *testing.T) {
 	var (
+		client          = entity.ClientInfo{UserAgent: "scanner/1.0", IP: "10.0.0.1"}
 		arbitraryErr    = errors.New("arbitrary error")
 		OldRefreshToken = "old_refresh"
 		RefreshToken    = "refresh"
@@ -50,7 +52,7 @@ func TestHandle(t *testing.T) {
 		{
 			name: "success",
 			mockBehavior: func(s *mock_post_refresh.MockUserService) {
-				s.EXPECT().RefreshTokens(gomock.Any(), request.RefreshToken).Return(&out, nil).Times(1)
+				s.EXPECT().RefreshTokens(gomock.Any(), request.RefreshToken, client).Return(&out, nil).Times(1)
 			},
 			wantStatus: http.StatusCreated,
 			wantBody:   string(responseJSON),
@@ -58,18 +60,34 @@ func TestHandle(t *testing.T) {
 		{
 			name: "invalid refresh token",
 			mockBehavior: func(s *mock_post_refresh.MockUserService) {
-				s.EXPECT().RefreshTokens(gomock.Any(), request.RefreshToken).Return(&auth.Tokens{}, user.ErrInvalidRefreshToken).Times(1)
+				s.EXPECT().RefreshTokens(gomock.Any(), request.RefreshToken, client).Return(&auth.Tokens{}, user.ErrInvalidRefreshToken).Times(1)
 			},
 			wantStatus: http.StatusForbidden,
 			wantBody:   user.ErrInvalidRefreshToken.Error(),
 		},
+		{
+			name: "disabled user",
+			mockBehavior: func(s *mock_post_refresh.MockUserService) {
+				s.EXPECT().RefreshTokens(gomock.Any(), request.RefreshToken, client).Return(&auth.Tokens{}, user.ErrUserDisabled).Times(1)
+			},
+			wantStatus: http.StatusForbidden,
+			wantBody:   user.ErrUserDisabled.Error(),
+		},
+		{
+			name: "second factor required",
+			mockBehavior: func(s *mock_post_refresh.MockUserService) {
+				s.EXPECT().RefreshTokens(gomock.Any(), request.RefreshToken, client).Return(nil, user.ErrMFARequired).Times(1)
+			},
+			wantStatus: http.StatusForbidden,
+			wantBody:   user.ErrMFARequired.Error(),
+		},
 		{
 			name: "internal error",
 			mockBehavior: func(s *mock_post_refresh.MockUserService) {
-				s.EXPECT().RefreshTokens(gomock.Any(), request.RefreshToken).Return(&auth.Tokens{}, arbitraryErr).Times(1)
+				s.EXPECT().RefreshTokens(gomock.Any(), request.RefreshToken, client).Return(&auth.Tokens{}, arbitraryErr).Times(1)
 			},
 			wantStatus: http.StatusInternalServerError,
-			wantBody:   arbitraryErr.Error(),
+			wantBody:   http.StatusText(http.StatusInternalServerError),
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
@@ -82,6 +100,8 @@ func TestHandle(t *testing.T) {
 
 			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(requestBody))
 			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("User-Agent", client.UserAgent)
+			req.Header.Set("X-Real-Ip", client.IP)
 			rec := httptest.NewRecorder()
 			ctx := e.NewContext(req, rec)
 
@@ -14,6 +14,7 @@ import (
 	reflect "reflect"
 
 	auth "github.com/4udiwe/avito-pvz/internal/auth"
+	entity "github.com/4udiwe/avito-pvz/internal/entity"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -42,16 +43,16 @@ func (m *MockUserService) EXPECT() *MockUserServiceMockRecorder {
 }
 
 // RefreshTokens mocks base method.
-func (m *MockUserService) RefreshTokens(ctx context.Context, refreshToken string) (*auth.Tokens, error) {
+func (m *MockUserService) RefreshTokens(ctx context.Context, refreshToken string, client entity.ClientInfo) (*auth.Tokens, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RefreshTokens", ctx, refreshToken)
+	ret := m.ctrl.Call(m, "RefreshTokens", ctx, refreshToken, client)
 	ret0, _ := ret[0].(*auth.Tokens)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // RefreshTokens indicates an expected call of RefreshTokens.
-func (mr *MockUserServiceMockRecorder) RefreshTokens(ctx, refreshToken any) *gomock.Call {
+func (mr *MockUserServiceMockRecorder) RefreshTokens(ctx, refreshToken, client any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshTokens", reflect.TypeOf((*MockUserService)(nil).RefreshTokens), ctx, refreshToken)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshTokens", reflect.TypeOf((*MockUserService)(nil).RefreshTokens), ctx, refreshToken, client)
 }
@@ -1,35 +1,51 @@
 package post_register
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
 	api "github.com/4udiwe/avito-pvz/internal/api/http"
-	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
 	"github.com/4udiwe/avito-pvz/internal/dto"
 	"github.com/4udiwe/avito-pvz/internal/entity"
 	"github.com/4udiwe/avito-pvz/internal/service/user"
 	"github.com/labstack/echo/v4"
+	"github.com/samber/lo"
 )
 
 type handler struct {
 	s UserService
 }
 
-func New(userService UserService) api.Handler {
-	return decorator.NewBindAndValidateDerocator(&handler{s: userService})
+func New(userService UserService) api.PostRegisterHandler {
+	return &handler{s: userService}
 }
 
-type Request dto.PostRegisterJSONBody
+func (h *handler) PostRegister(ctx context.Context, request dto.PostRegisterRequestObject) (dto.PostRegisterResponseObject, error) {
+	in := request.Body
+	if in.Role != nil && lo.FromPtr(in.InvitationCode) == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "field role requires an invitation")
+	}
 
-func (h *handler) Handle(ctx echo.Context, in Request) error {
-	tokens, err := h.s.Register(ctx.Request().Context(), string(in.Email), in.Password, entity.UserRole(in.Role))
+	created, err := h.s.Register(
+		ctx,
+		string(in.Email),
+		in.Password,
+		entity.UserRole(lo.FromPtr(in.Role)),
+		lo.FromPtr(in.InvitationCode),
+	)
 
 	if err != nil {
 		if errors.Is(err, user.ErrUserAlreadyExists) {
-			return echo.NewHTTPError(http.StatusConflict, err.Error())
+			return nil, echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, user.ErrRegistrationClosed) {
+			return nil, echo.NewHTTPError(http.StatusForbidden, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, user.ErrInvalidInvitation) || errors.Is(err, user.ErrUnknownRole) {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
 		}
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return nil, echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
 	}
-	return ctx.JSON(http.StatusCreated, tokens)
+	return dto.PostRegister201JSONResponse(*dto.EntityNewUserToDTO(&created)), nil
 }
@@ -9,14 +9,14 @@ import (
 	"strings"
 	"testing"
 
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
 	"github.com/4udiwe/avito-pvz/internal/api/http/post_register"
 	mock_post_register "github.com/4udiwe/avito-pvz/internal/api/http/post_register/mocks"
-	"github.com/4udiwe/avito-pvz/internal/auth"
 	"github.com/4udiwe/avito-pvz/internal/dto"
 	"github.com/4udiwe/avito-pvz/internal/entity"
 	"github.com/4udiwe/avito-pvz/internal/service/user"
-	"github.com/4udiwe/avito-pvz/pkg/validator"
 	"github.com/go-playground/assert/v2"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/oapi-codegen/runtime/types"
 	"github.com/stretchr/testify/require"
@@ -28,28 +28,30 @@ func TestHandle(t *testing.T) {
 		arbitraryErr = errors.New("arbitrary error")
 		Email        = "example@gmail.gom"
 		Password     = "12345678"
-		Role         = entity.RoleModerator
-		RefreshToken = "refresh"
-		AccessToken  = "access"
-		ttl          = 100
+		Role         = dto.PostRegisterJSONBodyRole(entity.RoleModerator)
+		Code         = "invitation-code"
+		userID       = uuid.New()
 	)
 
-	request := post_register.Request{
-		Email:    types.Email(Email),
-		Password: Password,
-		Role:     dto.PostRegisterJSONBodyRole(Role),
+	request := dto.PostRegisterJSONRequestBody{
+		Email:          types.Email(Email),
+		Password:       Password,
+		Role:           &Role,
+		InvitationCode: &Code,
 	}
-	out := auth.Tokens{
-		RefreshToken: RefreshToken,
-		AccessToken:  AccessToken,
-		ExpiresIn:    int64(ttl),
+	out := entity.User{
+		ID:           userID,
+		Email:        Email,
+		PasswordHash: "hash",
+		Role:         entity.RoleModerator,
 	}
-	responseJSON, _ := json.Marshal(out)
+	responseJSON := `{"email":"` + Email + `","id":"` + userID.String() + `","role":"moderator"}`
 
 	type MockBehavior func(s *mock_post_register.MockUserService)
 
 	for _, tc := range []struct {
 		name         string
+		request      dto.PostRegisterJSONRequestBody
 		mockBehavior MockBehavior
 		wantStatus   int
 		wantBody     string
@@ -57,35 +59,61 @@ func TestHandle(t *testing.T) {
 		{
 			name: "success",
 			mockBehavior: func(s *mock_post_register.MockUserService) {
-				s.EXPECT().Register(gomock.Any(), string(request.Email), request.Password, entity.UserRole(request.Role)).Return(&out, nil).Times(1)
+				s.EXPECT().Register(gomock.Any(), string(request.Email), request.Password, entity.UserRole(Role), Code).Return(out, nil).Times(1)
 			},
 			wantStatus: http.StatusCreated,
-			wantBody:   string(responseJSON),
+			wantBody:   responseJSON,
 		},
 		{
 			name: "user already exists",
 			mockBehavior: func(s *mock_post_register.MockUserService) {
-				s.EXPECT().Register(gomock.Any(), string(request.Email), request.Password, entity.UserRole(request.Role)).Return(nil, user.ErrUserAlreadyExists).Times(1)
+				s.EXPECT().Register(gomock.Any(), string(request.Email), request.Password, entity.UserRole(Role), Code).Return(entity.User{}, user.ErrUserAlreadyExists).Times(1)
 			},
 			wantStatus: http.StatusConflict,
 			wantBody:   user.ErrUserAlreadyExists.Error(),
 		},
+		{
+			name: "registration closed",
+			mockBehavior: func(s *mock_post_register.MockUserService) {
+				s.EXPECT().Register(gomock.Any(), string(request.Email), request.Password, entity.UserRole(Role), Code).Return(entity.User{}, user.ErrRegistrationClosed).Times(1)
+			},
+			wantStatus: http.StatusForbidden,
+			wantBody:   user.ErrRegistrationClosed.Error(),
+		},
+		{
+			name: "invalid invitation",
+			mockBehavior: func(s *mock_post_register.MockUserService) {
+				s.EXPECT().Register(gomock.Any(), string(request.Email), request.Password, entity.UserRole(Role), Code).Return(entity.User{}, user.ErrInvalidInvitation).Times(1)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   user.ErrInvalidInvitation.Error(),
+		},
+		{
+			name:         "role without invitation",
+			request:      dto.PostRegisterJSONRequestBody{Email: request.Email, Password: Password, Role: &Role},
+			mockBehavior: func(s *mock_post_register.MockUserService) {},
+			wantStatus:   http.StatusBadRequest,
+			wantBody:     "field role requires an invitation",
+		},
 		{
 			name: "internal error",
 			mockBehavior: func(s *mock_post_register.MockUserService) {
-				s.EXPECT().Register(gomock.Any(), string(request.Email), request.Password, entity.UserRole(request.Role)).Return(nil, arbitraryErr).Times(1)
+				s.EXPECT().Register(gomock.Any(), string(request.Email), request.Password, entity.UserRole(Role), Code).Return(entity.User{}, arbitraryErr).Times(1)
 			},
 			wantStatus: http.StatusInternalServerError,
-			wantBody:   arbitraryErr.Error(),
+			wantBody:   http.StatusText(http.StatusInternalServerError),
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
 			e := echo.New()
-			e.Validator = validator.NewCustomValidator()
 
-			requestBody, _ := json.Marshal(request)
+			body := request
+			if tc.request.Email != "" {
+				body = tc.request
+			}
+			requestBody, _ := json.Marshal(body)
 
 			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(requestBody))
 			req.Header.Set("Content-Type", "application/json")
@@ -96,9 +124,9 @@ func TestHandle(t *testing.T) {
 			MockService := mock_post_register.NewMockUserService(ctrl)
 			tc.mockBehavior(MockService)
 
-			handler := post_register.New(MockService)
+			server := &dto.ServerInterfaceWrapper{Handler: dto.NewStrictHandler(api.StrictServer{PostRegisterHandler: post_register.New(MockService)}, nil)}
 
-			err := handler.Handle(ctx)
+			err := server.PostRegister(ctx)
 
 			if tc.wantStatus >= 400 {
 				require.Error(t, err)
@@ -13,7 +13,6 @@ import (
 	context "context"
 	reflect "reflect"
 
-	auth "github.com/4udiwe/avito-pvz/internal/auth"
 	entity "github.com/4udiwe/avito-pvz/internal/entity"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -43,16 +42,16 @@ func (m *MockUserService) EXPECT() *MockUserServiceMockRecorder {
 }
 
 // Register mocks base method.
-func (m *MockUserService) Register(ctx context.Context, email, password string, role entity.UserRole) (*auth.Tokens, error) {
+func (m *MockUserService) Register(ctx context.Context, email, password string, role entity.UserRole, invitationCode string) (entity.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Register", ctx, email, password, role)
-	ret0, _ := ret[0].(*auth.Tokens)
+	ret := m.ctrl.Call(m, "Register", ctx, email, password, role, invitationCode)
+	ret0, _ := ret[0].(entity.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Register indicates an expected call of Register.
-func (mr *MockUserServiceMockRecorder) Register(ctx, email, password, role any) *gomock.Call {
+func (mr *MockUserServiceMockRecorder) Register(ctx, email, password, role, invitationCode any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockUserService)(nil).Register), ctx, email, password, role)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockUserService)(nil).Register), ctx, email, password, role, invitationCode)
 }
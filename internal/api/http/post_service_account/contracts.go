@@ -0,0 +1,14 @@
+package post_service_account
+
+import (
+	"context"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/google/uuid"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type ServiceAccountService interface {
+	CreateServiceAccount(ctx context.Context, actorID uuid.UUID, name string, description string) (entity.ServiceAccount, error)
+}
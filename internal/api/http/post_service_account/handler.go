@@ -0,0 +1,43 @@
+package post_service_account
+
+import (
+	"errors"
+	"net/http"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/service/service_account"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s ServiceAccountService
+}
+
+func New(serviceAccountService ServiceAccountService) api.Handler {
+	return decorator.NewBindAndValidateDerocator(&handler{s: serviceAccountService})
+}
+
+type Request struct {
+	Name        string `json:"name" validate:"required,max=128"`
+	Description string `json:"description" validate:"max=512"`
+}
+
+func (h *handler) Handle(ctx echo.Context, in Request) error {
+	claims, err := middleware.GetUserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	account, err := h.s.CreateServiceAccount(ctx.Request().Context(), claims.UserID, in.Name, in.Description)
+
+	if err != nil {
+		if errors.Is(err, service_account.ErrServiceAccountAlreadyExists) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return ctx.JSON(http.StatusCreated, dto.EntityServiceAccountToDTO(&account))
+}
@@ -0,0 +1,122 @@
+package post_service_account_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_service_account"
+	mock_post_service_account "github.com/4udiwe/avito-pvz/internal/api/http/post_service_account/mocks"
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/service/service_account"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		moderatorID  = uuid.New()
+		now          = time.Now().UTC().Truncate(time.Second)
+		out          = entity.ServiceAccount{
+			ID:          uuid.New(),
+			Name:        "erp",
+			Description: "ERP integration",
+			CreatedBy:   moderatorID,
+			CreatedAt:   now,
+		}
+		body = `{"name":"erp","description":"ERP integration"}`
+	)
+
+	responseJSON, _ := json.Marshal(dto.EntityServiceAccountToDTO(&out))
+
+	type MockBehavior func(s *mock_post_service_account.MockServiceAccountService)
+
+	for _, tc := range []struct {
+		name         string
+		body         string
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name: "success",
+			body: body,
+			mockBehavior: func(s *mock_post_service_account.MockServiceAccountService) {
+				s.EXPECT().CreateServiceAccount(gomock.Any(), moderatorID, "erp", "ERP integration").Return(out, nil).Times(1)
+			},
+			wantStatus: http.StatusCreated,
+			wantBody:   string(responseJSON),
+		},
+		{
+			name:         "missing name",
+			body:         `{"description":"ERP integration"}`,
+			mockBehavior: func(s *mock_post_service_account.MockServiceAccountService) {},
+			wantStatus:   http.StatusBadRequest,
+			wantBody:     "field name is required",
+		},
+		{
+			name: "already exists",
+			body: body,
+			mockBehavior: func(s *mock_post_service_account.MockServiceAccountService) {
+				s.EXPECT().CreateServiceAccount(gomock.Any(), moderatorID, "erp", "ERP integration").Return(entity.ServiceAccount{}, service_account.ErrServiceAccountAlreadyExists).Times(1)
+			},
+			wantStatus: http.StatusConflict,
+			wantBody:   service_account.ErrServiceAccountAlreadyExists.Error(),
+		},
+		{
+			name: "internal error",
+			body: body,
+			mockBehavior: func(s *mock_post_service_account.MockServiceAccountService) {
+				s.EXPECT().CreateServiceAccount(gomock.Any(), moderatorID, "erp", "ERP integration").Return(entity.ServiceAccount{}, arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctx.Set(middleware.USER_CLAIMS_KEY, &auth.TokenClaims{UserID: moderatorID, Role: entity.RoleModerator})
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_post_service_account.NewMockServiceAccountService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := post_service_account.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, strings.Trim(rec.Body.String(), "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package post_transfer
+
+import (
+	"context"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/google/uuid"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type TransferService interface {
+	CreateTransfer(ctx context.Context, sourcePointID uuid.UUID, destinationPointID uuid.UUID, productIDs []uuid.UUID, actorID uuid.UUID) (entity.Transfer, error)
+}
@@ -0,0 +1,51 @@
+package post_transfer
+
+import (
+	"errors"
+	"net/http"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	service "github.com/4udiwe/avito-pvz/internal/service/transfer"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s TransferService
+}
+
+func New(transferService TransferService) api.Handler {
+	return decorator.NewBindAndValidateDerocator(&handler{s: transferService})
+}
+
+type Request struct {
+	SourcePvzId      uuid.UUID   `json:"sourcePvzId" validate:"required"`
+	DestinationPvzId uuid.UUID   `json:"destinationPvzId" validate:"required"`
+	ProductIds       []uuid.UUID `json:"productIds" validate:"required,min=1"`
+}
+
+func (h *handler) Handle(ctx echo.Context, in Request) error {
+	claims, err := middleware.GetUserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	transfer, err := h.s.CreateTransfer(ctx.Request().Context(), in.SourcePvzId, in.DestinationPvzId, in.ProductIds, claims.UserID)
+
+	if err != nil {
+		if errors.Is(err, service.ErrNoPointFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrSamePoint) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrProductsUnavailable) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return ctx.JSON(http.StatusCreated, dto.EntityTransferToDTO(&transfer))
+}
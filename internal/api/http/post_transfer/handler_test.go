@@ -0,0 +1,148 @@
+package post_transfer_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_transfer"
+	mock_post_transfer "github.com/4udiwe/avito-pvz/internal/api/http/post_transfer/mocks"
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	service "github.com/4udiwe/avito-pvz/internal/service/transfer"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		actorID      = uuid.New()
+		sourceID     = uuid.New()
+		destID       = uuid.New()
+		productIDs   = []uuid.UUID{uuid.New()}
+
+		transfer = entity.Transfer{
+			ID:                 uuid.New(),
+			SourcePointID:      sourceID,
+			DestinationPointID: destID,
+			Status:             entity.TransferStatusCreated,
+			CreatedBy:          actorID,
+			Products:           []entity.TransferProduct{{ProductID: productIDs[0]}},
+			CreatedAt:          time.Now(),
+		}
+	)
+
+	responseJSON, _ := json.Marshal(dto.EntityTransferToDTO(&transfer))
+
+	type MockBehavior func(s *mock_post_transfer.MockTransferService)
+
+	for _, tc := range []struct {
+		name         string
+		request      post_transfer.Request
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name:    "success",
+			request: post_transfer.Request{SourcePvzId: sourceID, DestinationPvzId: destID, ProductIds: productIDs},
+			mockBehavior: func(s *mock_post_transfer.MockTransferService) {
+				s.EXPECT().CreateTransfer(gomock.Any(), sourceID, destID, productIDs, actorID).Return(transfer, nil).Times(1)
+			},
+			wantStatus: http.StatusCreated,
+			wantBody:   string(responseJSON),
+		},
+		{
+			name:    "no point found",
+			request: post_transfer.Request{SourcePvzId: sourceID, DestinationPvzId: destID, ProductIds: productIDs},
+			mockBehavior: func(s *mock_post_transfer.MockTransferService) {
+				s.EXPECT().CreateTransfer(gomock.Any(), sourceID, destID, productIDs, actorID).Return(entity.Transfer{}, service.ErrNoPointFound).Times(1)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   service.ErrNoPointFound.Error(),
+		},
+		{
+			name:    "same point",
+			request: post_transfer.Request{SourcePvzId: sourceID, DestinationPvzId: sourceID, ProductIds: productIDs},
+			mockBehavior: func(s *mock_post_transfer.MockTransferService) {
+				s.EXPECT().CreateTransfer(gomock.Any(), sourceID, sourceID, productIDs, actorID).Return(entity.Transfer{}, service.ErrSamePoint).Times(1)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   service.ErrSamePoint.Error(),
+		},
+		{
+			name:    "products unavailable",
+			request: post_transfer.Request{SourcePvzId: sourceID, DestinationPvzId: destID, ProductIds: productIDs},
+			mockBehavior: func(s *mock_post_transfer.MockTransferService) {
+				s.EXPECT().CreateTransfer(gomock.Any(), sourceID, destID, productIDs, actorID).Return(entity.Transfer{}, service.ErrProductsUnavailable).Times(1)
+			},
+			wantStatus: http.StatusConflict,
+			wantBody:   service.ErrProductsUnavailable.Error(),
+		},
+		{
+			name:    "internal error",
+			request: post_transfer.Request{SourcePvzId: sourceID, DestinationPvzId: destID, ProductIds: productIDs},
+			mockBehavior: func(s *mock_post_transfer.MockTransferService) {
+				s.EXPECT().CreateTransfer(gomock.Any(), sourceID, destID, productIDs, actorID).Return(entity.Transfer{}, arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+		{
+			name:         "no products",
+			request:      post_transfer.Request{SourcePvzId: sourceID, DestinationPvzId: destID},
+			mockBehavior: func(s *mock_post_transfer.MockTransferService) {},
+			wantStatus:   http.StatusBadRequest,
+			wantBody:     "field productIds is required",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+
+			requestBody, _ := json.Marshal(tc.request)
+
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctx.Set(middleware.USER_CLAIMS_KEY, &auth.TokenClaims{UserID: actorID, Role: entity.RoleEmployee})
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_post_transfer.NewMockTransferService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := post_transfer.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, strings.Trim(rec.Body.String(), "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package post_transfer_accept
+
+import (
+	"context"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/google/uuid"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type TransferService interface {
+	Accept(ctx context.Context, transferID uuid.UUID, scannedIDs []uuid.UUID, actorID uuid.UUID) (entity.Transfer, error)
+}
@@ -0,0 +1,145 @@
+package post_transfer_accept_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_transfer_accept"
+	mock_post_transfer_accept "github.com/4udiwe/avito-pvz/internal/api/http/post_transfer_accept/mocks"
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	service "github.com/4udiwe/avito-pvz/internal/service/transfer"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		actorID      = uuid.New()
+		transferID   = uuid.New()
+		receptionID  = uuid.New()
+		scannedIDs   = []uuid.UUID{uuid.New()}
+
+		transfer = entity.Transfer{
+			ID:                 transferID,
+			SourcePointID:      uuid.New(),
+			DestinationPointID: uuid.New(),
+			Status:             entity.TransferStatusAccepted,
+			ReceptionID:        &receptionID,
+			Products:           []entity.TransferProduct{{ProductID: scannedIDs[0], Accepted: true}},
+			CreatedAt:          time.Now(),
+		}
+	)
+
+	responseJSON, _ := json.Marshal(dto.EntityTransferToDTO(&transfer))
+
+	type MockBehavior func(s *mock_post_transfer_accept.MockTransferService)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name: "success",
+			mockBehavior: func(s *mock_post_transfer_accept.MockTransferService) {
+				s.EXPECT().Accept(gomock.Any(), transferID, scannedIDs, actorID).Return(transfer, nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   string(responseJSON),
+		},
+		{
+			name: "no transfer found",
+			mockBehavior: func(s *mock_post_transfer_accept.MockTransferService) {
+				s.EXPECT().Accept(gomock.Any(), transferID, scannedIDs, actorID).Return(entity.Transfer{}, service.ErrNoTransferFound).Times(1)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   service.ErrNoTransferFound.Error(),
+		},
+		{
+			name: "not dispatched",
+			mockBehavior: func(s *mock_post_transfer_accept.MockTransferService) {
+				s.EXPECT().Accept(gomock.Any(), transferID, scannedIDs, actorID).Return(entity.Transfer{}, service.ErrTransferNotDispatched).Times(1)
+			},
+			wantStatus: http.StatusConflict,
+			wantBody:   service.ErrTransferNotDispatched.Error(),
+		},
+		{
+			name: "no transfer reception",
+			mockBehavior: func(s *mock_post_transfer_accept.MockTransferService) {
+				s.EXPECT().Accept(gomock.Any(), transferID, scannedIDs, actorID).Return(entity.Transfer{}, service.ErrNoTransferReception).Times(1)
+			},
+			wantStatus: http.StatusConflict,
+			wantBody:   service.ErrNoTransferReception.Error(),
+		},
+		{
+			name: "unknown product",
+			mockBehavior: func(s *mock_post_transfer_accept.MockTransferService) {
+				s.EXPECT().Accept(gomock.Any(), transferID, scannedIDs, actorID).Return(entity.Transfer{}, service.ErrUnknownProduct).Times(1)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   service.ErrUnknownProduct.Error(),
+		},
+		{
+			name: "internal error",
+			mockBehavior: func(s *mock_post_transfer_accept.MockTransferService) {
+				s.EXPECT().Accept(gomock.Any(), transferID, scannedIDs, actorID).Return(entity.Transfer{}, arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+
+			requestBody, _ := json.Marshal(map[string][]uuid.UUID{"productIds": scannedIDs})
+
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctx.SetParamNames("transferId")
+			ctx.SetParamValues(transferID.String())
+			ctx.Set(middleware.USER_CLAIMS_KEY, &auth.TokenClaims{UserID: actorID, Role: entity.RoleEmployee})
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_post_transfer_accept.NewMockTransferService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := post_transfer_accept.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, strings.Trim(rec.Body.String(), "\n"))
+			}
+		})
+	}
+}
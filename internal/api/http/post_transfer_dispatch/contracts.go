@@ -0,0 +1,13 @@
+package post_transfer_dispatch
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type TransferService interface {
+	Dispatch(ctx context.Context, transferID uuid.UUID, actorID uuid.UUID) error
+}
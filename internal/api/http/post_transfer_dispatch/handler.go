@@ -0,0 +1,48 @@
+package post_transfer_dispatch
+
+import (
+	"errors"
+	"net/http"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	service "github.com/4udiwe/avito-pvz/internal/service/transfer"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s TransferService
+}
+
+func New(transferService TransferService) api.Handler {
+	return decorator.NewBindAndValidateDerocator(&handler{s: transferService})
+}
+
+type Request struct {
+	TransferID uuid.UUID `param:"transferId" validate:"required"`
+}
+
+func (h *handler) Handle(ctx echo.Context, in Request) error {
+	claims, err := middleware.GetUserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = h.s.Dispatch(ctx.Request().Context(), in.TransferID, claims.UserID)
+
+	if err != nil {
+		if errors.Is(err, service.ErrNoTransferFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrTransferNotCreated) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrProductsUnavailable) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return ctx.NoContent(http.StatusOK)
+}
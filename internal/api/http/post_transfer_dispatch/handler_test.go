@@ -0,0 +1,115 @@
+package post_transfer_dispatch_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_transfer_dispatch"
+	mock_post_transfer_dispatch "github.com/4udiwe/avito-pvz/internal/api/http/post_transfer_dispatch/mocks"
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	service "github.com/4udiwe/avito-pvz/internal/service/transfer"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		transferID   = uuid.New()
+		actorID      = uuid.New()
+		arbitraryErr = errors.New("arbitrary error")
+	)
+
+	type MockBehavior func(s *mock_post_transfer_dispatch.MockTransferService)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name: "success",
+			mockBehavior: func(s *mock_post_transfer_dispatch.MockTransferService) {
+				s.EXPECT().Dispatch(gomock.Any(), transferID, actorID).Return(nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   "",
+		},
+		{
+			name: "no transfer found",
+			mockBehavior: func(s *mock_post_transfer_dispatch.MockTransferService) {
+				s.EXPECT().Dispatch(gomock.Any(), transferID, actorID).Return(service.ErrNoTransferFound).Times(1)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   service.ErrNoTransferFound.Error(),
+		},
+		{
+			name: "already dispatched",
+			mockBehavior: func(s *mock_post_transfer_dispatch.MockTransferService) {
+				s.EXPECT().Dispatch(gomock.Any(), transferID, actorID).Return(service.ErrTransferNotCreated).Times(1)
+			},
+			wantStatus: http.StatusConflict,
+			wantBody:   service.ErrTransferNotCreated.Error(),
+		},
+		{
+			name: "products unavailable",
+			mockBehavior: func(s *mock_post_transfer_dispatch.MockTransferService) {
+				s.EXPECT().Dispatch(gomock.Any(), transferID, actorID).Return(service.ErrProductsUnavailable).Times(1)
+			},
+			wantStatus: http.StatusConflict,
+			wantBody:   service.ErrProductsUnavailable.Error(),
+		},
+		{
+			name: "internal error",
+			mockBehavior: func(s *mock_post_transfer_dispatch.MockTransferService) {
+				s.EXPECT().Dispatch(gomock.Any(), transferID, actorID).Return(arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctx.SetParamNames("transferId")
+			ctx.SetParamValues(transferID.String())
+			ctx.Set(middleware.USER_CLAIMS_KEY, &auth.TokenClaims{UserID: actorID, Role: entity.RoleEmployee})
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_post_transfer_dispatch.NewMockTransferService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := post_transfer_dispatch.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
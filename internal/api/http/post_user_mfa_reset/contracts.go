@@ -0,0 +1,13 @@
+package post_user_mfa_reset
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type UserService interface {
+	ResetMFA(ctx context.Context, actorID uuid.UUID, userID uuid.UUID) error
+}
@@ -0,0 +1,112 @@
+package post_user_mfa_reset_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_user_mfa_reset"
+	mock_post_user_mfa_reset "github.com/4udiwe/avito-pvz/internal/api/http/post_user_mfa_reset/mocks"
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/service/user"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		userID       = uuid.New()
+		moderatorID  = uuid.New()
+	)
+
+	type MockBehavior func(s *mock_post_user_mfa_reset.MockUserService)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name: "success",
+			mockBehavior: func(s *mock_post_user_mfa_reset.MockUserService) {
+				s.EXPECT().ResetMFA(gomock.Any(), moderatorID, userID).Return(nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "no user found",
+			mockBehavior: func(s *mock_post_user_mfa_reset.MockUserService) {
+				s.EXPECT().ResetMFA(gomock.Any(), moderatorID, userID).Return(user.ErrNoUserFound).Times(1)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   user.ErrNoUserFound.Error(),
+		},
+		{
+			name: "mfa not enabled",
+			mockBehavior: func(s *mock_post_user_mfa_reset.MockUserService) {
+				s.EXPECT().ResetMFA(gomock.Any(), moderatorID, userID).Return(user.ErrNoMFAFound).Times(1)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   user.ErrNoMFAFound.Error(),
+		},
+		{
+			name: "self",
+			mockBehavior: func(s *mock_post_user_mfa_reset.MockUserService) {
+				s.EXPECT().ResetMFA(gomock.Any(), moderatorID, userID).Return(user.ErrCannotModifySelf).Times(1)
+			},
+			wantStatus: http.StatusConflict,
+			wantBody:   user.ErrCannotModifySelf.Error(),
+		},
+		{
+			name: "internal error",
+			mockBehavior: func(s *mock_post_user_mfa_reset.MockUserService) {
+				s.EXPECT().ResetMFA(gomock.Any(), moderatorID, userID).Return(arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctx.SetParamNames("userId")
+			ctx.SetParamValues(userID.String())
+			ctx.Set(middleware.USER_CLAIMS_KEY, &auth.TokenClaims{UserID: moderatorID, Role: entity.RoleModerator})
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_post_user_mfa_reset.NewMockUserService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := post_user_mfa_reset.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package post_user_role
+
+import (
+	"context"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/google/uuid"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type UserService interface {
+	ChangeRole(ctx context.Context, actorID uuid.UUID, userID uuid.UUID, role entity.UserRole) (entity.User, error)
+}
@@ -0,0 +1,50 @@
+package post_user_role
+
+import (
+	"errors"
+	"net/http"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/service/user"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s UserService
+}
+
+func New(userService UserService) api.Handler {
+	return decorator.NewBindAndValidateDerocator(&handler{s: userService})
+}
+
+type Request struct {
+	UserID uuid.UUID `param:"userId" validate:"required"`
+	Role   string    `json:"role" validate:"required,max=32"`
+}
+
+func (h *handler) Handle(ctx echo.Context, in Request) error {
+	claims, err := middleware.GetUserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	u, err := h.s.ChangeRole(ctx.Request().Context(), claims.UserID, in.UserID, entity.UserRole(in.Role))
+
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrNoUserFound):
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		case errors.Is(err, user.ErrUnknownRole):
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		case errors.Is(err, user.ErrCannotModifySelf):
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return ctx.JSON(http.StatusOK, dto.EntityUserToDTO(&u))
+}
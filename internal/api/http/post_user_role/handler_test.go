@@ -0,0 +1,134 @@
+package post_user_role_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_user_role"
+	mock_post_user_role "github.com/4udiwe/avito-pvz/internal/api/http/post_user_role/mocks"
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/service/user"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		moderatorID  = uuid.New()
+		userID       = uuid.New()
+		out          = entity.User{ID: userID, Email: "a@mail.com", Role: entity.RoleModerator}
+	)
+
+	responseJSON, _ := json.Marshal(dto.EntityUserToDTO(&out))
+
+	type MockBehavior func(s *mock_post_user_role.MockUserService)
+
+	for _, tc := range []struct {
+		name         string
+		body         string
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name: "success",
+			body: `{"role":"moderator"}`,
+			mockBehavior: func(s *mock_post_user_role.MockUserService) {
+				s.EXPECT().ChangeRole(gomock.Any(), moderatorID, userID, entity.RoleModerator).Return(out, nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   string(responseJSON),
+		},
+		{
+			name:         "missing role",
+			body:         `{}`,
+			mockBehavior: func(s *mock_post_user_role.MockUserService) {},
+			wantStatus:   http.StatusBadRequest,
+			wantBody:     "field role is required",
+		},
+		{
+			name: "unknown role",
+			body: `{"role":"admin"}`,
+			mockBehavior: func(s *mock_post_user_role.MockUserService) {
+				s.EXPECT().ChangeRole(gomock.Any(), moderatorID, userID, entity.UserRole("admin")).Return(entity.User{}, user.ErrUnknownRole).Times(1)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   user.ErrUnknownRole.Error(),
+		},
+		{
+			name: "own role",
+			body: `{"role":"moderator"}`,
+			mockBehavior: func(s *mock_post_user_role.MockUserService) {
+				s.EXPECT().ChangeRole(gomock.Any(), moderatorID, userID, entity.RoleModerator).Return(entity.User{}, user.ErrCannotModifySelf).Times(1)
+			},
+			wantStatus: http.StatusConflict,
+			wantBody:   user.ErrCannotModifySelf.Error(),
+		},
+		{
+			name: "no user found",
+			body: `{"role":"moderator"}`,
+			mockBehavior: func(s *mock_post_user_role.MockUserService) {
+				s.EXPECT().ChangeRole(gomock.Any(), moderatorID, userID, entity.RoleModerator).Return(entity.User{}, user.ErrNoUserFound).Times(1)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   user.ErrNoUserFound.Error(),
+		},
+		{
+			name: "internal error",
+			body: `{"role":"moderator"}`,
+			mockBehavior: func(s *mock_post_user_role.MockUserService) {
+				s.EXPECT().ChangeRole(gomock.Any(), moderatorID, userID, entity.RoleModerator).Return(entity.User{}, arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctx.SetParamNames("userId")
+			ctx.SetParamValues(userID.String())
+			ctx.Set(middleware.USER_CLAIMS_KEY, &auth.TokenClaims{UserID: moderatorID, Role: entity.RoleModerator})
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_post_user_role.NewMockUserService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := post_user_role.New(MockService)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, strings.Trim(rec.Body.String(), "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package post_user_status
+
+import (
+	"context"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/google/uuid"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type UserService interface {
+	SetDisabled(ctx context.Context, actorID uuid.UUID, userID uuid.UUID, disabled bool) (entity.User, error)
+}
@@ -0,0 +1,48 @@
+package post_user_status
+
+import (
+	"errors"
+	"net/http"
+
+	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/decorator"
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/service/user"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// handler serves both POST /users/{userId}/disable and /enable.
+type handler struct {
+	s        UserService
+	disabled bool
+}
+
+func New(userService UserService, disabled bool) api.Handler {
+	return decorator.NewBindAndValidateDerocator(&handler{s: userService, disabled: disabled})
+}
+
+type Request struct {
+	UserID uuid.UUID `param:"userId" validate:"required"`
+}
+
+func (h *handler) Handle(ctx echo.Context, in Request) error {
+	claims, err := middleware.GetUserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	u, err := h.s.SetDisabled(ctx.Request().Context(), claims.UserID, in.UserID, h.disabled)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrNoUserFound):
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		case errors.Is(err, user.ErrCannotModifySelf):
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return ctx.JSON(http.StatusOK, dto.EntityUserToDTO(&u))
+}
@@ -0,0 +1,130 @@
+package post_user_status_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_user_status"
+	mock_post_user_status "github.com/4udiwe/avito-pvz/internal/api/http/post_user_status/mocks"
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/service/user"
+	"github.com/4udiwe/avito-pvz/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandle(t *testing.T) {
+	var (
+		arbitraryErr = errors.New("arbitrary error")
+		moderatorID  = uuid.New()
+		userID       = uuid.New()
+		disabledAt   = time.Now().UTC().Truncate(time.Second)
+		disabledUser = entity.User{ID: userID, Email: "a@mail.com", Role: entity.RoleEmployee, DisabledAt: &disabledAt}
+		enabledUser  = entity.User{ID: userID, Email: "a@mail.com", Role: entity.RoleEmployee}
+	)
+
+	disabledJSON, _ := json.Marshal(dto.EntityUserToDTO(&disabledUser))
+	enabledJSON, _ := json.Marshal(dto.EntityUserToDTO(&enabledUser))
+
+	type MockBehavior func(s *mock_post_user_status.MockUserService)
+
+	for _, tc := range []struct {
+		name         string
+		disable      bool
+		mockBehavior MockBehavior
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name:    "disable",
+			disable: true,
+			mockBehavior: func(s *mock_post_user_status.MockUserService) {
+				s.EXPECT().SetDisabled(gomock.Any(), moderatorID, userID, true).Return(disabledUser, nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   string(disabledJSON),
+		},
+		{
+			name:    "enable",
+			disable: false,
+			mockBehavior: func(s *mock_post_user_status.MockUserService) {
+				s.EXPECT().SetDisabled(gomock.Any(), moderatorID, userID, false).Return(enabledUser, nil).Times(1)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   string(enabledJSON),
+		},
+		{
+			name:    "self",
+			disable: true,
+			mockBehavior: func(s *mock_post_user_status.MockUserService) {
+				s.EXPECT().SetDisabled(gomock.Any(), moderatorID, userID, true).Return(entity.User{}, user.ErrCannotModifySelf).Times(1)
+			},
+			wantStatus: http.StatusConflict,
+			wantBody:   user.ErrCannotModifySelf.Error(),
+		},
+		{
+			name:    "no user found",
+			disable: true,
+			mockBehavior: func(s *mock_post_user_status.MockUserService) {
+				s.EXPECT().SetDisabled(gomock.Any(), moderatorID, userID, true).Return(entity.User{}, user.ErrNoUserFound).Times(1)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   user.ErrNoUserFound.Error(),
+		},
+		{
+			name:    "internal error",
+			disable: false,
+			mockBehavior: func(s *mock_post_user_status.MockUserService) {
+				s.EXPECT().SetDisabled(gomock.Any(), moderatorID, userID, false).Return(entity.User{}, arbitraryErr).Times(1)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   http.StatusText(http.StatusInternalServerError),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Validator = validator.NewCustomValidator()
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			ctx.SetParamNames("userId")
+			ctx.SetParamValues(userID.String())
+			ctx.Set(middleware.USER_CLAIMS_KEY, &auth.TokenClaims{UserID: moderatorID, Role: entity.RoleModerator})
+
+			ctrl := gomock.NewController(t)
+			MockService := mock_post_user_status.NewMockUserService(ctrl)
+			tc.mockBehavior(MockService)
+
+			handler := post_user_status.New(MockService, tc.disable)
+
+			err := handler.Handle(ctx)
+
+			if tc.wantStatus >= 400 {
+				require.Error(t, err)
+				httpErr := &echo.HTTPError{}
+				ok := errors.As(err, &httpErr)
+				require.True(t, ok)
+				assert.Equal(t, tc.wantStatus, httpErr.Code)
+				assert.Equal(t, tc.wantBody, httpErr.Message)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+				assert.Equal(t, tc.wantBody, strings.Trim(rec.Body.String(), "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package post_user_unlock
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mock_service.go
+
+type UserService interface {
+	UnlockUser(ctx context.Context, actorID uuid.UUID, userID uuid.UUID) error
+}
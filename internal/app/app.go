@@ -11,17 +11,44 @@ import (
 	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
 	"github.com/4udiwe/avito-pvz/internal/auth"
 	"github.com/4udiwe/avito-pvz/internal/database"
+	"github.com/4udiwe/avito-pvz/internal/dto"
 	"github.com/4udiwe/avito-pvz/internal/metrics"
+	repo_api_key "github.com/4udiwe/avito-pvz/internal/repository/api_key"
+	repo_audit "github.com/4udiwe/avito-pvz/internal/repository/audit"
+	repo_cell "github.com/4udiwe/avito-pvz/internal/repository/cell"
+	repo_idempotency "github.com/4udiwe/avito-pvz/internal/repository/idempotency"
+	repo_identity "github.com/4udiwe/avito-pvz/internal/repository/identity"
+	repo_invitation "github.com/4udiwe/avito-pvz/internal/repository/invitation"
+	repo_login_failure "github.com/4udiwe/avito-pvz/internal/repository/login_failure"
+	repo_mfa "github.com/4udiwe/avito-pvz/internal/repository/mfa"
+	repo_mfa_challenge "github.com/4udiwe/avito-pvz/internal/repository/mfa_challenge"
+	repo_oidc_state "github.com/4udiwe/avito-pvz/internal/repository/oidc_state"
+	repo_order "github.com/4udiwe/avito-pvz/internal/repository/order"
+	repo_password_reset "github.com/4udiwe/avito-pvz/internal/repository/password_reset"
 	repo_point "github.com/4udiwe/avito-pvz/internal/repository/point"
 	repo_product "github.com/4udiwe/avito-pvz/internal/repository/product"
 	repo_reception "github.com/4udiwe/avito-pvz/internal/repository/reception"
+	repo_revoked_token "github.com/4udiwe/avito-pvz/internal/repository/revoked_token"
+	repo_role "github.com/4udiwe/avito-pvz/internal/repository/role"
+	repo_service_account "github.com/4udiwe/avito-pvz/internal/repository/service_account"
+	repo_session "github.com/4udiwe/avito-pvz/internal/repository/session"
+	repo_transfer "github.com/4udiwe/avito-pvz/internal/repository/transfer"
 	repo_user "github.com/4udiwe/avito-pvz/internal/repository/user"
+	"github.com/4udiwe/avito-pvz/internal/service/audit"
+	"github.com/4udiwe/avito-pvz/internal/service/cell"
+	"github.com/4udiwe/avito-pvz/internal/service/order"
 	"github.com/4udiwe/avito-pvz/internal/service/point"
 	"github.com/4udiwe/avito-pvz/internal/service/product"
 	"github.com/4udiwe/avito-pvz/internal/service/reception"
+	"github.com/4udiwe/avito-pvz/internal/service/service_account"
+	"github.com/4udiwe/avito-pvz/internal/service/transfer"
 	"github.com/4udiwe/avito-pvz/internal/service/user"
+	"github.com/4udiwe/avito-pvz/pkg/encryptor"
+	"github.com/4udiwe/avito-pvz/pkg/hashchain"
 	"github.com/4udiwe/avito-pvz/pkg/hasher"
 	"github.com/4udiwe/avito-pvz/pkg/httpserver"
+	"github.com/4udiwe/avito-pvz/pkg/notifier"
+	"github.com/4udiwe/avito-pvz/pkg/oidc"
 	"github.com/4udiwe/avito-pvz/pkg/postgres"
 	"github.com/labstack/echo/v4"
 )
@@ -41,37 +68,117 @@ type App struct {
 	pointRepo     *repo_point.Repository
 	productRepo   *repo_product.Repository
 	receptionRepo *repo_reception.Repository
+	orderRepo     *repo_order.Repository
+	transferRepo  *repo_transfer.Repository
+	cellRepo      *repo_cell.Repository
+	revokedRepo   *repo_revoked_token.Repository
+	sessionRepo   *repo_session.Repository
+	resetRepo     *repo_password_reset.Repository
+	failureRepo   *repo_login_failure.Repository
+	auditRepo     *repo_audit.Repository
+	inviteRepo    *repo_invitation.Repository
+	roleRepo      *repo_role.Repository
+	accountRepo   *repo_service_account.Repository
+	apiKeyRepo    *repo_api_key.Repository
+	oidcStateRepo *repo_oidc_state.Repository
+	identityRepo  *repo_identity.Repository
+	mfaRepo       *repo_mfa.Repository
+	challengeRepo *repo_mfa_challenge.Repository
+	idemRepo      *repo_idempotency.Repository
 
 	// Auth
-	auth   *auth.Auth
-	hasher *hasher.BcryptHasher
+	auth          *auth.Auth
+	hasher        *hasher.BcryptHasher
+	passwords     *hasher.Argon2Hasher
+	revocations   *auth.RevocationList
+	disabledUsers *auth.DisabledUsers
+	permissions   *auth.Permissions
+	oidcProvider  *oidc.Provider
+	encryptor     *encryptor.AESEncryptor
+	auditSigner   *hashchain.Signer
+
+	// Notifications
+	notifier notifier.Notifier
 
 	// Middleware
-	authMW *middleware.AuthMiddleware
+	authMW       *middleware.AuthMiddleware
+	permissionMW *middleware.PermissionMiddleware
+	openAPIMW    *middleware.OpenAPIValidator
+	idempotency  *middleware.Idempotency
 
 	// Handlers
-	postDummyLoginHandler api.Handler
-	postLoginHandler      api.Handler
-	postRegisterHandler   api.Handler
-	postRefreshHandler    api.Handler
-
-	deleteProductHandler  api.Handler
-	getPointsHandler      api.Handler
-	closeReceptionHandler api.Handler
-	postPointHandler      api.Handler
-	postProductHandler    api.Handler
-	postReceptionHandler  api.Handler
+	strictHandler dto.ServerInterface
+
+	postRefreshHandler   api.Handler
+	getJWKSHandler       api.Handler
+	postLogoutHandler    api.Handler
+	getSessionsHandler   api.Handler
+	deleteSessionHandler api.Handler
+
+	postPasswordChangeHandler       api.Handler
+	postPasswordResetRequestHandler api.Handler
+	postPasswordResetHandler        api.Handler
+	postUserUnlockHandler           api.Handler
+	getUsersHandler                 api.Handler
+	getUserHandler                  api.Handler
+	postUserRoleHandler             api.Handler
+	postUserDisableHandler          api.Handler
+	postUserEnableHandler           api.Handler
+	postInvitationHandler           api.Handler
+	getOIDCLoginHandler             api.Handler
+	getOIDCCallbackHandler          api.Handler
+	postMFAVerifyHandler            api.Handler
+	postMFAEnrollByTokenHandler     api.Handler
+	postMFAEnrollHandler            api.Handler
+	postMFAConfirmHandler           api.Handler
+	postUserMFAResetHandler         api.Handler
+
+	postServiceAccountHandler api.Handler
+	getServiceAccountsHandler api.Handler
+	postAPIKeyHandler         api.Handler
+	postAPIKeyRotateHandler   api.Handler
+	deleteAPIKeyHandler       api.Handler
+
+	getAuditHandler       api.Handler
+	getAuditExportHandler api.Handler
+
+	getPointStockHandler api.Handler
+	getCityStockHandler  api.Handler
+
+	postOrderHandler           api.Handler
+	getOrderHandler            api.Handler
+	postOrderReadyHandler      api.Handler
+	postOrderCodeResendHandler api.Handler
+	postOrderIssueHandler      api.Handler
+
+	postTransferHandler         api.Handler
+	getTransferHandler          api.Handler
+	postTransferDispatchHandler api.Handler
+	postTransferAcceptHandler   api.Handler
+
+	postCellHandler          api.Handler
+	getCellsHandler          api.Handler
+	postProductCellHandler   api.Handler
+	getCellSuggestionHandler api.Handler
+	getProductCellHandler    api.Handler
 
 	// Services
 	userService      *user.Service
 	pointService     *point.Service
 	productService   *product.Service
 	receptionService *reception.Service
+	orderService     *order.Service
+	transferService  *transfer.Service
+	cellService      *cell.Service
+	accountService   *service_account.Service
+	auditService     *audit.Service
 
 	// Metrics
 	pointMetrics     *metrics.PointMetrics
 	productMetrics   *metrics.ProductMetrics
 	receptionMetrics *metrics.ReceptionMetrics
+	stockMetrics     *metrics.StockMetrics
+	loginMetrics     *metrics.LoginMetrics
 }
 
 func New(configPath string) *App {
@@ -80,7 +187,7 @@ func New(configPath string) *App {
 		log.Fatalf("app - New - config.New: %v", err)
 	}
 
-	initLogger(cfg.Log.Level)
+	initLogger(cfg.Log)
 
 	return &App{
 		cfg: cfg,
@@ -88,25 +195,54 @@ func New(configPath string) *App {
 }
 
 func (app *App) Start() {
+	// Refuse to start with missing or weak signing keys
+	app.Auth()
+	app.Encryptor()
+	app.PasswordHasher()
+	app.AuditSigner()
+
 	// Postgres
-	log.Info("Connecting to PostgreSQL...")
+	app.connectPostgres()
+	defer app.postgres.Close()
 
-	postgres, err := postgres.New(app.cfg.Postgres.URL, postgres.ConnAttempts(5))
+	// Migrations
+	if err := database.RunMigrations(context.Background(), app.postgres.Pool); err != nil {
+		log.Errorf("app - Start - Migrations failed: %v", err)
+	}
 
-	if err != nil {
-		log.Fatalf("app - Start - Postgres failed:%v", err)
+	// Access token revocations
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := app.RevocationList().Sync(ctx); err != nil {
+		log.Errorf("app - Start - RevocationList.Sync: %v", err)
 	}
-	app.postgres = postgres
+	go app.RevocationList().Run(ctx, app.cfg.Auth.RevocationSyncInterval)
 
-	defer postgres.Close()
+	// Disabled users
+	if err := app.DisabledUsers().Sync(ctx); err != nil {
+		log.Errorf("app - Start - DisabledUsers.Sync: %v", err)
+	}
+	go app.DisabledUsers().Run(ctx, app.cfg.Auth.RevocationSyncInterval)
 
-	// Migrations
-	if err := database.RunMigrations(context.Background(), app.postgres.Pool); err != nil {
-		log.Errorf("app - Start - Migrations failed: %v", err)
+	// Role permissions
+	if err := app.Permissions().Sync(ctx); err != nil {
+		log.Errorf("app - Start - Permissions.Sync: %v", err)
 	}
+	go app.Permissions().Run(ctx, app.cfg.Auth.PermissionSyncInterval)
+
+	// Audit chain sealing and checkpoints
+	go app.AuditService().Run(ctx, app.cfg.Audit.SealInterval, app.cfg.Audit.CheckpointInterval)
+
+	// Stale login failure counters
+	go app.UserService().RunLoginFailureCleanup(ctx, app.cfg.Login.FailureWindow)
+
+	// Expired idempotency keys
+	go app.Idempotency().Run(ctx, app.cfg.Idempotency.CleanupInterval)
 
 	// Prometheus server
 	log.Infof("Starting metrics server...")
+	app.StockMetrics()
 	metricsHandler := echo.New()
 	metrics.ConfigureHandler(metricsHandler)
 	metricsHandler.GET("/health", func(c echo.Context) error {
@@ -145,3 +281,14 @@ func (app *App) Start() {
 
 	log.Info("Shutting down...")
 }
+
+func (app *App) connectPostgres() {
+	log.Info("Connecting to PostgreSQL...")
+
+	postgres, err := postgres.New(app.cfg.Postgres.URL, postgres.ConnAttempts(5))
+
+	if err != nil {
+		log.Fatalf("app - Start - Postgres failed:%v", err)
+	}
+	app.postgres = postgres
+}
@@ -1,19 +1,58 @@
 package app
 
 import (
+	"net/http"
+
+	"github.com/4udiwe/avito-pvz/config"
 	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
 	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/pkg/encryptor"
+	"github.com/4udiwe/avito-pvz/pkg/hashchain"
 	"github.com/4udiwe/avito-pvz/pkg/hasher"
+	"github.com/4udiwe/avito-pvz/pkg/oidc"
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
 )
 
 func (app *App) Auth() *auth.Auth {
 	if app.auth != nil {
 		return app.auth
 	}
-	app.auth = auth.New()
+	a, err := auth.New(auth.Options{
+		Algorithm:     app.cfg.Auth.Algorithm,
+		AccessSecret:  app.cfg.Auth.AccessSecret,
+		RefreshSecret: app.cfg.Auth.RefreshSecret,
+		SigningKeyID:  app.cfg.Auth.SigningKeyID,
+		Keys: lo.Map(app.cfg.Auth.Keys, func(k config.AuthKey, _ int) auth.KeyFile {
+			return auth.KeyFile{ID: k.ID, PrivateKeyPath: k.PrivateKeyFile, PublicKeyPath: k.PublicKeyFile}
+		}),
+	})
+	if err != nil {
+		log.Fatalf("app - Auth - auth.New: %v", err)
+	}
+	app.auth = a
 	return app.auth
 }
 
+// PasswordHasher hashes user passwords. Pickup codes stay on Hasher.
+func (app *App) PasswordHasher() *hasher.Argon2Hasher {
+	if app.passwords != nil {
+		return app.passwords
+	}
+	h, err := hasher.NewArgon2(hasher.Argon2Params{
+		Memory:      app.cfg.Password.Argon2.Memory,
+		Iterations:  app.cfg.Password.Argon2.Iterations,
+		Parallelism: app.cfg.Password.Argon2.Parallelism,
+		SaltLength:  hasher.DefaultArgon2Params.SaltLength,
+		KeyLength:   hasher.DefaultArgon2Params.KeyLength,
+	})
+	if err != nil {
+		log.Fatalf("app - PasswordHasher - hasher.NewArgon2: %v", err)
+	}
+	app.passwords = h
+	return app.passwords
+}
+
 func (app *App) Hasher() *hasher.BcryptHasher {
 	if app.hasher != nil {
 		return app.hasher
@@ -22,10 +61,96 @@ func (app *App) Hasher() *hasher.BcryptHasher {
 	return app.hasher
 }
 
+// Encryptor protects TOTP secrets at rest. A missing or malformed
+// MFA_ENCRYPTION_KEY stops the service.
+func (app *App) Encryptor() *encryptor.AESEncryptor {
+	if app.encryptor != nil {
+		return app.encryptor
+	}
+	e, err := encryptor.New(app.cfg.MFA.EncryptionKey)
+	if err != nil {
+		log.Fatalf("app - Encryptor - encryptor.New: %v", err)
+	}
+	app.encryptor = e
+	return app.encryptor
+}
+
+// AuditSigner signs audit chain checkpoints. A missing or malformed
+// AUDIT_SIGNING_KEY stops the service.
+func (app *App) AuditSigner() *hashchain.Signer {
+	if app.auditSigner != nil {
+		return app.auditSigner
+	}
+	s, err := hashchain.NewSigner(app.cfg.Audit.SigningKey)
+	if err != nil {
+		log.Fatalf("app - AuditSigner - hashchain.NewSigner: %v", err)
+	}
+	app.auditSigner = s
+	return app.auditSigner
+}
+
+func (app *App) RevocationList() *auth.RevocationList {
+	if app.revocations != nil {
+		return app.revocations
+	}
+	app.revocations = auth.NewRevocationList(app.RevokedTokenRepo())
+	return app.revocations
+}
+
+func (app *App) DisabledUsers() *auth.DisabledUsers {
+	if app.disabledUsers != nil {
+		return app.disabledUsers
+	}
+	app.disabledUsers = auth.NewDisabledUsers(app.UserRepo())
+	return app.disabledUsers
+}
+
 func (app *App) AuthMiddleware() *middleware.AuthMiddleware {
 	if app.authMW != nil {
 		return app.authMW
 	}
-	app.authMW = middleware.New(app.Auth())
+	app.authMW = middleware.New(app.Auth(), app.RevocationList(), app.DisabledUsers(), app.ServiceAccountService())
 	return app.authMW
 }
+
+// Idempotency stores keys in the transaction of the handler, which joins it
+// through the request context.
+func (app *App) Idempotency() *middleware.Idempotency {
+	if app.idempotency != nil {
+		return app.idempotency
+	}
+	app.idempotency = middleware.NewIdempotency(app.Postgres(), app.IdempotencyRepo(), app.cfg.Idempotency.TTL)
+	return app.idempotency
+}
+
+func (app *App) Permissions() *auth.Permissions {
+	if app.permissions != nil {
+		return app.permissions
+	}
+	app.permissions = auth.NewPermissions(app.RoleRepo())
+	return app.permissions
+}
+
+func (app *App) PermissionMiddleware() *middleware.PermissionMiddleware {
+	if app.permissionMW != nil {
+		return app.permissionMW
+	}
+	app.permissionMW = middleware.NewPermissionMiddleware(app.Permissions())
+	return app.permissionMW
+}
+
+// OIDCProvider is the corporate identity provider. Discovery happens on
+// the first login, so the service starts while the provider is down.
+func (app *App) OIDCProvider() *oidc.Provider {
+	if app.oidcProvider != nil {
+		return app.oidcProvider
+	}
+	app.oidcProvider = oidc.New(oidc.Config{
+		Issuer:       app.cfg.OIDC.Issuer,
+		ClientID:     app.cfg.OIDC.ClientID,
+		ClientSecret: app.cfg.OIDC.ClientSecret,
+		RedirectURL:  app.cfg.OIDC.RedirectURL,
+		Scopes:       app.cfg.OIDC.Scopes,
+	}, &http.Client{Timeout: app.cfg.OIDC.Timeout})
+	return app.oidcProvider
+}
@@ -0,0 +1,47 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/4udiwe/avito-pvz/internal/database"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/service/user"
+)
+
+// CreateUser creates a user with the given role, which is how the first
+// moderator is set up: registration never grants a role without an
+// invitation. The password is read from CREATE_USER_PASSWORD so that it does
+// not show up in the process list. It returns the process exit code: 0 when
+// the user was created, 1 when the email is taken and 2 on other errors.
+func (app *App) CreateUser(email string, role string) int {
+	password := os.Getenv("CREATE_USER_PASSWORD")
+	if password == "" {
+		fmt.Println("CREATE_USER_PASSWORD is not set")
+		return 2
+	}
+
+	app.connectPostgres()
+	defer app.postgres.Close()
+
+	ctx := context.Background()
+	if err := database.RunMigrations(ctx, app.postgres.Pool); err != nil {
+		fmt.Printf("migrations failed: %v\n", err)
+		return 2
+	}
+
+	created, err := app.UserService().CreateUser(ctx, email, password, entity.UserRole(role))
+	if errors.Is(err, user.ErrUserAlreadyExists) {
+		fmt.Println("user already exists")
+		return 1
+	}
+	if err != nil {
+		fmt.Printf("user could not be created: %v\n", err)
+		return 2
+	}
+
+	fmt.Printf("user %s created with role %s\n", created.ID, created.Role)
+	return 0
+}
@@ -1,9 +1,26 @@
 package app
 
 import (
+	repo_api_key "github.com/4udiwe/avito-pvz/internal/repository/api_key"
+	repo_audit "github.com/4udiwe/avito-pvz/internal/repository/audit"
+	repo_cell "github.com/4udiwe/avito-pvz/internal/repository/cell"
+	repo_idempotency "github.com/4udiwe/avito-pvz/internal/repository/idempotency"
+	repo_identity "github.com/4udiwe/avito-pvz/internal/repository/identity"
+	repo_invitation "github.com/4udiwe/avito-pvz/internal/repository/invitation"
+	repo_login_failure "github.com/4udiwe/avito-pvz/internal/repository/login_failure"
+	repo_mfa "github.com/4udiwe/avito-pvz/internal/repository/mfa"
+	repo_mfa_challenge "github.com/4udiwe/avito-pvz/internal/repository/mfa_challenge"
+	repo_oidc_state "github.com/4udiwe/avito-pvz/internal/repository/oidc_state"
+	repo_order "github.com/4udiwe/avito-pvz/internal/repository/order"
+	repo_password_reset "github.com/4udiwe/avito-pvz/internal/repository/password_reset"
 	repo_point "github.com/4udiwe/avito-pvz/internal/repository/point"
 	repo_product "github.com/4udiwe/avito-pvz/internal/repository/product"
 	repo_reception "github.com/4udiwe/avito-pvz/internal/repository/reception"
+	repo_revoked_token "github.com/4udiwe/avito-pvz/internal/repository/revoked_token"
+	repo_role "github.com/4udiwe/avito-pvz/internal/repository/role"
+	repo_service_account "github.com/4udiwe/avito-pvz/internal/repository/service_account"
+	repo_session "github.com/4udiwe/avito-pvz/internal/repository/session"
+	repo_transfer "github.com/4udiwe/avito-pvz/internal/repository/transfer"
 	repo_user "github.com/4udiwe/avito-pvz/internal/repository/user"
 	"github.com/4udiwe/avito-pvz/pkg/postgres"
 )
@@ -43,3 +60,139 @@ func (app *App) ReceptionRepo() *repo_reception.Repository {
 	app.receptionRepo = repo_reception.New(app.Postgres())
 	return app.receptionRepo
 }
+
+func (app *App) OrderRepo() *repo_order.Repository {
+	if app.orderRepo != nil {
+		return app.orderRepo
+	}
+	app.orderRepo = repo_order.New(app.Postgres())
+	return app.orderRepo
+}
+
+func (app *App) TransferRepo() *repo_transfer.Repository {
+	if app.transferRepo != nil {
+		return app.transferRepo
+	}
+	app.transferRepo = repo_transfer.New(app.Postgres())
+	return app.transferRepo
+}
+
+func (app *App) CellRepo() *repo_cell.Repository {
+	if app.cellRepo != nil {
+		return app.cellRepo
+	}
+	app.cellRepo = repo_cell.New(app.Postgres())
+	return app.cellRepo
+}
+
+func (app *App) RevokedTokenRepo() *repo_revoked_token.Repository {
+	if app.revokedRepo != nil {
+		return app.revokedRepo
+	}
+	app.revokedRepo = repo_revoked_token.New(app.Postgres())
+	return app.revokedRepo
+}
+
+func (app *App) SessionRepo() *repo_session.Repository {
+	if app.sessionRepo != nil {
+		return app.sessionRepo
+	}
+	app.sessionRepo = repo_session.New(app.Postgres())
+	return app.sessionRepo
+}
+
+func (app *App) ResetRepo() *repo_password_reset.Repository {
+	if app.resetRepo != nil {
+		return app.resetRepo
+	}
+	app.resetRepo = repo_password_reset.New(app.Postgres())
+	return app.resetRepo
+}
+
+func (app *App) FailureRepo() *repo_login_failure.Repository {
+	if app.failureRepo != nil {
+		return app.failureRepo
+	}
+	app.failureRepo = repo_login_failure.New(app.Postgres())
+	return app.failureRepo
+}
+
+func (app *App) AuditRepo() *repo_audit.Repository {
+	if app.auditRepo != nil {
+		return app.auditRepo
+	}
+	app.auditRepo = repo_audit.New(app.Postgres())
+	return app.auditRepo
+}
+
+func (app *App) InviteRepo() *repo_invitation.Repository {
+	if app.inviteRepo != nil {
+		return app.inviteRepo
+	}
+	app.inviteRepo = repo_invitation.New(app.Postgres())
+	return app.inviteRepo
+}
+
+func (app *App) RoleRepo() *repo_role.Repository {
+	if app.roleRepo != nil {
+		return app.roleRepo
+	}
+	app.roleRepo = repo_role.New(app.Postgres())
+	return app.roleRepo
+}
+
+func (app *App) ServiceAccountRepo() *repo_service_account.Repository {
+	if app.accountRepo != nil {
+		return app.accountRepo
+	}
+	app.accountRepo = repo_service_account.New(app.Postgres())
+	return app.accountRepo
+}
+
+func (app *App) APIKeyRepo() *repo_api_key.Repository {
+	if app.apiKeyRepo != nil {
+		return app.apiKeyRepo
+	}
+	app.apiKeyRepo = repo_api_key.New(app.Postgres())
+	return app.apiKeyRepo
+}
+
+func (app *App) OIDCStateRepo() *repo_oidc_state.Repository {
+	if app.oidcStateRepo != nil {
+		return app.oidcStateRepo
+	}
+	app.oidcStateRepo = repo_oidc_state.New(app.Postgres())
+	return app.oidcStateRepo
+}
+
+func (app *App) IdentityRepo() *repo_identity.Repository {
+	if app.identityRepo != nil {
+		return app.identityRepo
+	}
+	app.identityRepo = repo_identity.New(app.Postgres())
+	return app.identityRepo
+}
+
+func (app *App) MFARepo() *repo_mfa.Repository {
+	if app.mfaRepo != nil {
+		return app.mfaRepo
+	}
+	app.mfaRepo = repo_mfa.New(app.Postgres())
+	return app.mfaRepo
+}
+
+func (app *App) MFAChallengeRepo() *repo_mfa_challenge.Repository {
+	if app.challengeRepo != nil {
+		return app.challengeRepo
+	}
+	app.challengeRepo = repo_mfa_challenge.New(app.Postgres())
+	return app.challengeRepo
+}
+
+func (app *App) IdempotencyRepo() *repo_idempotency.Repository {
+	if app.idemRepo != nil {
+		return app.idemRepo
+	}
+	app.idemRepo = repo_idempotency.New(app.Postgres())
+	return app.idemRepo
+}
@@ -2,94 +2,442 @@ package app
 
 import (
 	api "github.com/4udiwe/avito-pvz/internal/api/http"
+	"github.com/4udiwe/avito-pvz/internal/api/http/delete_api_key"
 	"github.com/4udiwe/avito-pvz/internal/api/http/delete_product"
+	"github.com/4udiwe/avito-pvz/internal/api/http/delete_session"
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_audit"
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_audit_export"
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_cell_suggestion"
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_cells"
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_city_stock"
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_jwks"
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_oidc_callback"
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_oidc_login"
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_order"
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_point_stock"
 	"github.com/4udiwe/avito-pvz/internal/api/http/get_points"
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_product_cell"
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_product_history"
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_service_accounts"
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_sessions"
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_transfer"
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_user"
+	"github.com/4udiwe/avito-pvz/internal/api/http/get_users"
 	"github.com/4udiwe/avito-pvz/internal/api/http/patch_reception"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_api_key"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_api_key_rotate"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_cell"
 	"github.com/4udiwe/avito-pvz/internal/api/http/post_dummy_login"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_invitation"
 	"github.com/4udiwe/avito-pvz/internal/api/http/post_login"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_logout"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_mfa_confirm"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_mfa_enroll"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_mfa_enroll_by_token"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_mfa_verify"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_order"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_order_code_resend"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_order_issue"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_order_ready"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_password_change"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_password_reset"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_password_reset_request"
 	"github.com/4udiwe/avito-pvz/internal/api/http/post_point"
 	"github.com/4udiwe/avito-pvz/internal/api/http/post_product"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_product_cell"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_product_status"
 	"github.com/4udiwe/avito-pvz/internal/api/http/post_reception"
 	"github.com/4udiwe/avito-pvz/internal/api/http/post_refresh"
 	"github.com/4udiwe/avito-pvz/internal/api/http/post_register"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_service_account"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_transfer"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_transfer_accept"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_transfer_dispatch"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_user_mfa_reset"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_user_role"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_user_status"
+	"github.com/4udiwe/avito-pvz/internal/api/http/post_user_unlock"
+	"github.com/4udiwe/avito-pvz/internal/dto"
 )
 
-func (app *App) DeleteProductHandler() api.Handler {
-	if app.deleteProductHandler != nil {
-		return app.deleteProductHandler
+// StrictHandler serves the operations of api/swagger.yaml through the
+// generated strict server.
+func (app *App) StrictHandler() dto.ServerInterface {
+	if app.strictHandler != nil {
+		return app.strictHandler
 	}
-	app.deleteProductHandler = delete_product.New(app.ProductService())
-	return app.deleteProductHandler
+	app.strictHandler = dto.NewStrictHandler(api.StrictServer{
+		PostDummyLoginHandler:                 post_dummy_login.New(app.UserService()),
+		PostRegisterHandler:                   post_register.New(app.UserService()),
+		PostLoginHandler:                      post_login.New(app.UserService()),
+		GetPvzHandler:                         get_points.New(app.PointService()),
+		PostPvzHandler:                        post_point.New(app.PointService()),
+		PostPvzPvzIdCloseLastReceptionHandler: patch_reception.New(app.ReceptionService()),
+		PostPvzPvzIdDeleteLastProductHandler:  delete_product.New(app.ProductService()),
+		PostReceptionsHandler:                 post_reception.New(app.ReceptionService()),
+		PostProductsHandler:                   post_product.New(app.ProductService()),
+		ProductStatusHandler:                  post_product_status.New(app.ProductService()),
+		GetProductsProductIdHistoryHandler:    get_product_history.New(app.ProductService()),
+	}, nil)
+	return app.strictHandler
 }
 
-func (app *App) GetPointsHandler() api.Handler {
-	if app.getPointsHandler != nil {
-		return app.getPointsHandler
+func (app *App) PostRefreshHandler() api.Handler {
+	if app.postRefreshHandler != nil {
+		return app.postRefreshHandler
 	}
-	app.getPointsHandler = get_points.New(app.PointService())
-	return app.getPointsHandler
+	app.postRefreshHandler = post_refresh.New(app.UserService())
+	return app.postRefreshHandler
 }
 
-func (app *App) CloseReceptionHandler() api.Handler {
-	if app.closeReceptionHandler != nil {
-		return app.closeReceptionHandler
+func (app *App) PostOrderHandler() api.Handler {
+	if app.postOrderHandler != nil {
+		return app.postOrderHandler
 	}
-	app.closeReceptionHandler = patch_reception.New(app.ReceptionService())
-	return app.closeReceptionHandler
+	app.postOrderHandler = post_order.New(app.OrderService())
+	return app.postOrderHandler
 }
 
-func (app *App) PostPointHandler() api.Handler {
-	if app.postPointHandler != nil {
-		return app.postPointHandler
+func (app *App) GetOrderHandler() api.Handler {
+	if app.getOrderHandler != nil {
+		return app.getOrderHandler
 	}
-	app.postPointHandler = post_point.New(app.PointService())
-	return app.postPointHandler
+	app.getOrderHandler = get_order.New(app.OrderService())
+	return app.getOrderHandler
 }
 
-func (app *App) PostProductHandler() api.Handler {
-	if app.postProductHandler != nil {
-		return app.postProductHandler
+func (app *App) PostOrderReadyHandler() api.Handler {
+	if app.postOrderReadyHandler != nil {
+		return app.postOrderReadyHandler
 	}
-	app.postProductHandler = post_product.New(app.ProductService())
-	return app.postProductHandler
+	app.postOrderReadyHandler = post_order_ready.New(app.OrderService())
+	return app.postOrderReadyHandler
 }
 
-func (app *App) PostReceptionHandler() api.Handler {
-	if app.postReceptionHandler != nil {
-		return app.postReceptionHandler
+func (app *App) PostOrderCodeResendHandler() api.Handler {
+	if app.postOrderCodeResendHandler != nil {
+		return app.postOrderCodeResendHandler
 	}
-	app.postReceptionHandler = post_reception.New(app.ReceptionService())
-	return app.postReceptionHandler
+	app.postOrderCodeResendHandler = post_order_code_resend.New(app.OrderService())
+	return app.postOrderCodeResendHandler
 }
 
-func (app *App) PostDummyLoginHandler() api.Handler {
-	if app.postDummyLoginHandler != nil {
-		return app.postDummyLoginHandler
+func (app *App) PostOrderIssueHandler() api.Handler {
+	if app.postOrderIssueHandler != nil {
+		return app.postOrderIssueHandler
 	}
-	app.postDummyLoginHandler = post_dummy_login.New(app.UserService())
-	return app.postDummyLoginHandler
+	app.postOrderIssueHandler = post_order_issue.New(app.OrderService())
+	return app.postOrderIssueHandler
 }
 
-func (app *App) PostLoginHandler() api.Handler {
-	if app.postLoginHandler != nil {
-		return app.postLoginHandler
+func (app *App) PostTransferHandler() api.Handler {
+	if app.postTransferHandler != nil {
+		return app.postTransferHandler
 	}
-	app.postLoginHandler = post_login.New(app.UserService())
-	return app.postLoginHandler
+	app.postTransferHandler = post_transfer.New(app.TransferService())
+	return app.postTransferHandler
 }
 
-func (app *App) PostRegisterHandler() api.Handler {
-	if app.postRegisterHandler != nil {
-		return app.postRegisterHandler
+func (app *App) GetTransferHandler() api.Handler {
+	if app.getTransferHandler != nil {
+		return app.getTransferHandler
 	}
-	app.postRegisterHandler = post_register.New(app.UserService())
-	return app.postRegisterHandler
+	app.getTransferHandler = get_transfer.New(app.TransferService())
+	return app.getTransferHandler
 }
 
-func (app *App) PostRefreshHandler() api.Handler {
-	if app.postRefreshHandler != nil {
-		return app.postRefreshHandler
+func (app *App) PostTransferDispatchHandler() api.Handler {
+	if app.postTransferDispatchHandler != nil {
+		return app.postTransferDispatchHandler
 	}
-	app.postRefreshHandler = post_refresh.New(app.UserService())
-	return app.postRefreshHandler
+	app.postTransferDispatchHandler = post_transfer_dispatch.New(app.TransferService())
+	return app.postTransferDispatchHandler
+}
+
+func (app *App) PostTransferAcceptHandler() api.Handler {
+	if app.postTransferAcceptHandler != nil {
+		return app.postTransferAcceptHandler
+	}
+	app.postTransferAcceptHandler = post_transfer_accept.New(app.TransferService())
+	return app.postTransferAcceptHandler
+}
+
+func (app *App) GetPointStockHandler() api.Handler {
+	if app.getPointStockHandler != nil {
+		return app.getPointStockHandler
+	}
+	app.getPointStockHandler = get_point_stock.New(app.PointService())
+	return app.getPointStockHandler
+}
+
+func (app *App) GetCityStockHandler() api.Handler {
+	if app.getCityStockHandler != nil {
+		return app.getCityStockHandler
+	}
+	app.getCityStockHandler = get_city_stock.New(app.PointService())
+	return app.getCityStockHandler
+}
+
+func (app *App) PostCellHandler() api.Handler {
+	if app.postCellHandler != nil {
+		return app.postCellHandler
+	}
+	app.postCellHandler = post_cell.New(app.CellService())
+	return app.postCellHandler
+}
+
+func (app *App) GetCellsHandler() api.Handler {
+	if app.getCellsHandler != nil {
+		return app.getCellsHandler
+	}
+	app.getCellsHandler = get_cells.New(app.CellService())
+	return app.getCellsHandler
+}
+
+func (app *App) PostProductCellHandler() api.Handler {
+	if app.postProductCellHandler != nil {
+		return app.postProductCellHandler
+	}
+	app.postProductCellHandler = post_product_cell.New(app.CellService())
+	return app.postProductCellHandler
+}
+
+func (app *App) GetCellSuggestionHandler() api.Handler {
+	if app.getCellSuggestionHandler != nil {
+		return app.getCellSuggestionHandler
+	}
+	app.getCellSuggestionHandler = get_cell_suggestion.New(app.CellService())
+	return app.getCellSuggestionHandler
+}
+
+func (app *App) GetProductCellHandler() api.Handler {
+	if app.getProductCellHandler != nil {
+		return app.getProductCellHandler
+	}
+	app.getProductCellHandler = get_product_cell.New(app.CellService())
+	return app.getProductCellHandler
+}
+
+func (app *App) PostLogoutHandler() api.Handler {
+	if app.postLogoutHandler != nil {
+		return app.postLogoutHandler
+	}
+	app.postLogoutHandler = post_logout.New(app.UserService())
+	return app.postLogoutHandler
+}
+
+func (app *App) GetSessionsHandler() api.Handler {
+	if app.getSessionsHandler != nil {
+		return app.getSessionsHandler
+	}
+	app.getSessionsHandler = get_sessions.New(app.UserService())
+	return app.getSessionsHandler
+}
+
+func (app *App) DeleteSessionHandler() api.Handler {
+	if app.deleteSessionHandler != nil {
+		return app.deleteSessionHandler
+	}
+	app.deleteSessionHandler = delete_session.New(app.UserService())
+	return app.deleteSessionHandler
+}
+
+func (app *App) GetJWKSHandler() api.Handler {
+	if app.getJWKSHandler != nil {
+		return app.getJWKSHandler
+	}
+	app.getJWKSHandler = get_jwks.New(app.Auth())
+	return app.getJWKSHandler
+}
+
+func (app *App) PostPasswordChangeHandler() api.Handler {
+	if app.postPasswordChangeHandler != nil {
+		return app.postPasswordChangeHandler
+	}
+	app.postPasswordChangeHandler = post_password_change.New(app.UserService())
+	return app.postPasswordChangeHandler
+}
+
+func (app *App) PostPasswordResetRequestHandler() api.Handler {
+	if app.postPasswordResetRequestHandler != nil {
+		return app.postPasswordResetRequestHandler
+	}
+	app.postPasswordResetRequestHandler = post_password_reset_request.New(app.UserService())
+	return app.postPasswordResetRequestHandler
+}
+
+func (app *App) PostPasswordResetHandler() api.Handler {
+	if app.postPasswordResetHandler != nil {
+		return app.postPasswordResetHandler
+	}
+	app.postPasswordResetHandler = post_password_reset.New(app.UserService())
+	return app.postPasswordResetHandler
+}
+
+func (app *App) PostUserUnlockHandler() api.Handler {
+	if app.postUserUnlockHandler != nil {
+		return app.postUserUnlockHandler
+	}
+	app.postUserUnlockHandler = post_user_unlock.New(app.UserService())
+	return app.postUserUnlockHandler
+}
+
+func (app *App) GetUsersHandler() api.Handler {
+	if app.getUsersHandler != nil {
+		return app.getUsersHandler
+	}
+	app.getUsersHandler = get_users.New(app.UserService())
+	return app.getUsersHandler
+}
+
+func (app *App) GetUserHandler() api.Handler {
+	if app.getUserHandler != nil {
+		return app.getUserHandler
+	}
+	app.getUserHandler = get_user.New(app.UserService())
+	return app.getUserHandler
+}
+
+func (app *App) PostUserRoleHandler() api.Handler {
+	if app.postUserRoleHandler != nil {
+		return app.postUserRoleHandler
+	}
+	app.postUserRoleHandler = post_user_role.New(app.UserService())
+	return app.postUserRoleHandler
+}
+
+func (app *App) PostUserDisableHandler() api.Handler {
+	if app.postUserDisableHandler != nil {
+		return app.postUserDisableHandler
+	}
+	app.postUserDisableHandler = post_user_status.New(app.UserService(), true)
+	return app.postUserDisableHandler
+}
+
+func (app *App) PostUserEnableHandler() api.Handler {
+	if app.postUserEnableHandler != nil {
+		return app.postUserEnableHandler
+	}
+	app.postUserEnableHandler = post_user_status.New(app.UserService(), false)
+	return app.postUserEnableHandler
+}
+
+func (app *App) PostInvitationHandler() api.Handler {
+	if app.postInvitationHandler != nil {
+		return app.postInvitationHandler
+	}
+	app.postInvitationHandler = post_invitation.New(app.UserService())
+	return app.postInvitationHandler
+}
+
+func (app *App) GetOIDCLoginHandler() api.Handler {
+	if app.getOIDCLoginHandler != nil {
+		return app.getOIDCLoginHandler
+	}
+	app.getOIDCLoginHandler = get_oidc_login.New(app.UserService())
+	return app.getOIDCLoginHandler
+}
+
+func (app *App) GetOIDCCallbackHandler() api.Handler {
+	if app.getOIDCCallbackHandler != nil {
+		return app.getOIDCCallbackHandler
+	}
+	app.getOIDCCallbackHandler = get_oidc_callback.New(app.UserService())
+	return app.getOIDCCallbackHandler
+}
+
+func (app *App) PostMFAVerifyHandler() api.Handler {
+	if app.postMFAVerifyHandler != nil {
+		return app.postMFAVerifyHandler
+	}
+	app.postMFAVerifyHandler = post_mfa_verify.New(app.UserService())
+	return app.postMFAVerifyHandler
+}
+
+func (app *App) PostMFAEnrollByTokenHandler() api.Handler {
+	if app.postMFAEnrollByTokenHandler != nil {
+		return app.postMFAEnrollByTokenHandler
+	}
+	app.postMFAEnrollByTokenHandler = post_mfa_enroll_by_token.New(app.UserService())
+	return app.postMFAEnrollByTokenHandler
+}
+
+func (app *App) PostMFAEnrollHandler() api.Handler {
+	if app.postMFAEnrollHandler != nil {
+		return app.postMFAEnrollHandler
+	}
+	app.postMFAEnrollHandler = post_mfa_enroll.New(app.UserService())
+	return app.postMFAEnrollHandler
+}
+
+func (app *App) PostMFAConfirmHandler() api.Handler {
+	if app.postMFAConfirmHandler != nil {
+		return app.postMFAConfirmHandler
+	}
+	app.postMFAConfirmHandler = post_mfa_confirm.New(app.UserService())
+	return app.postMFAConfirmHandler
+}
+
+func (app *App) PostUserMFAResetHandler() api.Handler {
+	if app.postUserMFAResetHandler != nil {
+		return app.postUserMFAResetHandler
+	}
+	app.postUserMFAResetHandler = post_user_mfa_reset.New(app.UserService())
+	return app.postUserMFAResetHandler
+}
+
+func (app *App) PostServiceAccountHandler() api.Handler {
+	if app.postServiceAccountHandler != nil {
+		return app.postServiceAccountHandler
+	}
+	app.postServiceAccountHandler = post_service_account.New(app.ServiceAccountService())
+	return app.postServiceAccountHandler
+}
+
+func (app *App) GetServiceAccountsHandler() api.Handler {
+	if app.getServiceAccountsHandler != nil {
+		return app.getServiceAccountsHandler
+	}
+	app.getServiceAccountsHandler = get_service_accounts.New(app.ServiceAccountService())
+	return app.getServiceAccountsHandler
+}
+
+func (app *App) PostAPIKeyHandler() api.Handler {
+	if app.postAPIKeyHandler != nil {
+		return app.postAPIKeyHandler
+	}
+	app.postAPIKeyHandler = post_api_key.New(app.ServiceAccountService())
+	return app.postAPIKeyHandler
+}
+
+func (app *App) PostAPIKeyRotateHandler() api.Handler {
+	if app.postAPIKeyRotateHandler != nil {
+		return app.postAPIKeyRotateHandler
+	}
+	app.postAPIKeyRotateHandler = post_api_key_rotate.New(app.ServiceAccountService())
+	return app.postAPIKeyRotateHandler
+}
+
+func (app *App) DeleteAPIKeyHandler() api.Handler {
+	if app.deleteAPIKeyHandler != nil {
+		return app.deleteAPIKeyHandler
+	}
+	app.deleteAPIKeyHandler = delete_api_key.New(app.ServiceAccountService())
+	return app.deleteAPIKeyHandler
+}
+
+func (app *App) GetAuditHandler() api.Handler {
+	if app.getAuditHandler != nil {
+		return app.getAuditHandler
+	}
+	app.getAuditHandler = get_audit.New(app.AuditService())
+	return app.getAuditHandler
+}
+
+func (app *App) GetAuditExportHandler() api.Handler {
+	if app.getAuditExportHandler != nil {
+		return app.getAuditExportHandler
+	}
+	app.getAuditExportHandler = get_audit_export.New(app.AuditService())
+	return app.getAuditExportHandler
 }
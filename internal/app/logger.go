@@ -1,20 +1,13 @@
 package app
 
 import (
-	"time"
-
+	"github.com/4udiwe/avito-pvz/config"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
 	log "github.com/sirupsen/logrus"
 )
 
-func initLogger(level string) {
-	logrusLevel, err := log.ParseLevel(level)
-	if err != nil {
-		log.SetLevel(log.DebugLevel)
-	} else {
-		log.SetLevel(logrusLevel)
+func initLogger(cfg config.Log) {
+	if err := logger.Setup(cfg.Level, cfg.Format); err != nil {
+		log.Fatalf("app - initLogger: %v", err)
 	}
-
-	log.SetFormatter(&log.JSONFormatter{
-		TimestampFormat: time.DateTime,
-	})
 }
@@ -27,3 +27,19 @@ func (app *App) ReceptionMetrics() *metrics.ReceptionMetrics {
 	app.receptionMetrics = metrics.NewReceptionMetrics()
 	return app.receptionMetrics
 }
+
+func (app *App) StockMetrics() *metrics.StockMetrics {
+	if app.stockMetrics != nil {
+		return app.stockMetrics
+	}
+	app.stockMetrics = metrics.NewStockMetrics(app.PointRepo())
+	return app.stockMetrics
+}
+
+func (app *App) LoginMetrics() *metrics.LoginMetrics {
+	if app.loginMetrics != nil {
+		return app.loginMetrics
+	}
+	app.loginMetrics = metrics.NewLoginMetrics()
+	return app.loginMetrics
+}
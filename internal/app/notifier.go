@@ -0,0 +1,19 @@
+package app
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	"github.com/4udiwe/avito-pvz/pkg/notifier"
+)
+
+func (app *App) Notifier() notifier.Notifier {
+	if app.notifier != nil {
+		return app.notifier
+	}
+	n, err := notifier.New(app.cfg.Notifier.Type, app.cfg.Notifier.FilePath)
+	if err != nil {
+		log.Fatalf("app - Notifier - notifier.New: %v", err)
+	}
+	app.notifier = n
+	return app.notifier
+}
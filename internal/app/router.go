@@ -4,9 +4,13 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/4udiwe/avito-pvz/internal/api/http/errorhandler"
 	"github.com/4udiwe/avito-pvz/internal/api/http/middleware"
+	"github.com/4udiwe/avito-pvz/internal/dto"
+	"github.com/4udiwe/avito-pvz/internal/entity"
 	"github.com/4udiwe/avito-pvz/pkg/validator"
 	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
 )
 
 func (app *App) EchoHandler() *echo.Echo {
@@ -15,7 +19,20 @@ func (app *App) EchoHandler() *echo.Echo {
 	}
 
 	handler := echo.New()
-	handler.Validator = validator.NewCustomValidator()
+	handler.HTTPErrorHandler = errorhandler.Handle
+	ipExtractor, err := middleware.IPExtractor(app.cfg.HTTP.TrustedProxies)
+	if err != nil {
+		log.Fatalf("app - EchoHandler - IPExtractor: %v", err)
+	}
+	handler.IPExtractor = ipExtractor
+	handler.Validator = validator.NewCustomValidator(validator.WithPasswordPolicy(validator.PasswordPolicy{
+		MinLength:      app.cfg.Password.MinLength,
+		MaxLength:      app.cfg.Password.MaxLength,
+		RequireUpper:   app.cfg.Password.RequireUpper,
+		RequireLower:   app.cfg.Password.RequireLower,
+		RequireDigit:   app.cfg.Password.RequireDigit,
+		RequireSpecial: app.cfg.Password.RequireSpecial,
+	}))
 
 	app.configureRouter(handler)
 
@@ -27,32 +44,201 @@ func (app *App) EchoHandler() *echo.Echo {
 	return app.echoHandler
 }
 
+func (app *App) OpenAPIValidator() *middleware.OpenAPIValidator {
+	if app.openAPIMW != nil {
+		return app.openAPIMW
+	}
+
+	doc, err := dto.GetSwagger()
+	if err != nil {
+		log.Fatalf("app - OpenAPIValidator - GetSwagger: %v", err)
+	}
+	app.openAPIMW, err = middleware.NewOpenAPIValidator(doc, app.cfg.HTTP.OpenAPI.ValidateRequests, app.cfg.HTTP.OpenAPI.ValidateResponses)
+	if err != nil {
+		log.Fatalf("app - OpenAPIValidator: %v", err)
+	}
+	return app.openAPIMW
+}
+
 func (app *App) configureRouter(handler *echo.Echo) {
+	// Request ID first, so every later middleware and the audit log see it
+	handler.Use(middleware.RequestID)
 	// Metrics middleware
 	handler.Use(middleware.MetricsMiddleware)
+	handler.Use(middleware.ClientInfo)
+
+	for _, version := range app.apiVersions() {
+		mount := middleware.APIVersion{Name: version.name}
+		mountVersion(handler, apiPrefix+version.name, mount, version.register)
+	}
+
+	// Clients from before the versioning call v1 at the root
+	if legacy := app.cfg.HTTP.Legacy; legacy.Enabled {
+		mount := middleware.APIVersion{
+			Name:         "v1",
+			Deprecated:   true,
+			DeprecatedAt: legacy.DeprecatedAt,
+			Sunset:       legacy.Sunset,
+			Successor:    apiPrefix + "v1",
+		}
+		mountVersion(handler, "", mount, app.registerV1)
+	}
+
+	handler.GET("/.well-known/jwks.json", app.GetJWKSHandler().Handle)
+	handler.GET("/health", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+}
+
+const apiPrefix = "/api/"
+
+// mountVersion registers the routes of a version under prefix, each behind
+// the mount middleware. The routes are collected on a scratch instance
+// first: a group with middleware also answers every unknown path under its
+// prefix, and those 404s would be served and counted as the version.
+func mountVersion(handler *echo.Echo, prefix string, mount middleware.APIVersion, register func(g *echo.Group)) {
+	scratch := echo.New()
+	scratch.OnAddRouteHandler = func(_ string, route echo.Route, h echo.HandlerFunc, m []echo.MiddlewareFunc) {
+		if route.Method == echo.RouteNotFound {
+			return
+		}
+		handler.Add(route.Method, prefix+route.Path, h, append([]echo.MiddlewareFunc{mount.Middleware}, m...)...)
+	}
+	register(scratch.Group(""))
+}
+
+type apiVersion struct {
+	name     string
+	register func(g *echo.Group)
+}
+
+// apiVersions are mounted under /api/<name>. A new version gets its own
+// register function here; older ones keep serving until they are retired.
+func (app *App) apiVersions() []apiVersion {
+	return []apiVersion{
+		{name: "v1", register: app.registerV1},
+	}
+}
+
+func (app *App) registerV1(handler *echo.Group) {
+	can := app.PermissionMiddleware().Require
+	strict := &dto.ServerInterfaceWrapper{Handler: app.StrictHandler()}
+	idempotent := app.Idempotency().Middleware
+	// The description is checked after authentication, so a caller without
+	// valid credentials gets a 401 whatever it sends
+	validate := app.OpenAPIValidator().Middleware
+
+	if app.cfg.App.DevMode {
+		handler.POST("/dummyLogin", strict.PostDummyLogin, validate)
+	}
+	handler.POST("/register", strict.PostRegister, validate)
+	handler.POST("/login", strict.PostLogin, validate)
+	handler.POST("/login/mfa", app.PostMFAVerifyHandler().Handle, validate)
+	handler.POST("/login/mfa/enroll", app.PostMFAEnrollByTokenHandler().Handle, validate)
+	handler.POST("/refresh", app.PostRefreshHandler().Handle, validate)
+
+	if app.cfg.OIDC.Enabled {
+		oidcGroup := handler.Group("/oidc")
+		{
+			oidcGroup.GET("/login", app.GetOIDCLoginHandler().Handle, validate)
+			oidcGroup.GET("/callback", app.GetOIDCCallbackHandler().Handle, validate)
+		}
+	}
 
-	handler.POST("dummyLogin", app.PostDummyLoginHandler().Handle)
-	handler.POST("register", app.PostRegisterHandler().Handle)
-	handler.POST("login", app.PostLoginHandler().Handle)
-	handler.POST("refresh", app.PostRefreshHandler().Handle)
+	handler.POST("/logout", app.PostLogoutHandler().Handle, app.AuthMiddleware().Middleware, middleware.UsersOnly, validate)
 
-	receptionsGroup := handler.Group("receptions", app.AuthMiddleware().Middleware)
+	passwordGroup := handler.Group("/password")
 	{
-		receptionsGroup.POST("", app.PostReceptionHandler().Handle, middleware.EmployeeOnly)
+		passwordGroup.POST("/change", app.PostPasswordChangeHandler().Handle, app.AuthMiddleware().Middleware, middleware.UsersOnly, validate)
+		passwordGroup.POST("/reset/request", app.PostPasswordResetRequestHandler().Handle, validate)
+		passwordGroup.POST("/reset", app.PostPasswordResetHandler().Handle, validate)
 	}
 
-	productsGroup := handler.Group("products", app.AuthMiddleware().Middleware)
+	mfaGroup := handler.Group("/mfa", app.AuthMiddleware().Middleware, middleware.UsersOnly, validate)
 	{
-		productsGroup.POST("", app.PostProductHandler().Handle, middleware.EmployeeOnly)
+		mfaGroup.POST("/totp", app.PostMFAEnrollHandler().Handle)
+		mfaGroup.POST("/totp/confirm", app.PostMFAConfirmHandler().Handle)
 	}
 
-	pvzGroup := handler.Group("pvz", app.AuthMiddleware().Middleware)
+	usersGroup := handler.Group("/users", app.AuthMiddleware().Middleware, validate)
 	{
-		pvzGroup.POST("/:pvzId/close_last_reception", app.CloseReceptionHandler().Handle, middleware.EmployeeOnly)
-		pvzGroup.POST("/:pvzId/delete_last_product", app.DeleteProductHandler().Handle, middleware.EmployeeOnly)
-		pvzGroup.POST("", app.PostPointHandler().Handle, middleware.ModderatorOnly)
-		pvzGroup.GET("", app.GetPointsHandler().Handle, middleware.EmployeeAndModerator)
+		usersGroup.GET("", app.GetUsersHandler().Handle, can(entity.PermissionUserRead))
+		usersGroup.GET("/:userId", app.GetUserHandler().Handle, can(entity.PermissionUserRead))
+		usersGroup.POST("/:userId/role", app.PostUserRoleHandler().Handle, can(entity.PermissionUserManage))
+		usersGroup.POST("/:userId/disable", app.PostUserDisableHandler().Handle, can(entity.PermissionUserManage))
+		usersGroup.POST("/:userId/enable", app.PostUserEnableHandler().Handle, can(entity.PermissionUserManage))
+		usersGroup.POST("/:userId/unlock", app.PostUserUnlockHandler().Handle, can(entity.PermissionUserManage))
+		usersGroup.POST("/:userId/mfa/reset", app.PostUserMFAResetHandler().Handle, can(entity.PermissionUserManage))
 	}
 
-	handler.GET("/health", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+	invitationsGroup := handler.Group("/invitations", app.AuthMiddleware().Middleware, validate)
+	{
+		invitationsGroup.POST("", app.PostInvitationHandler().Handle, can(entity.PermissionUserInvite))
+	}
+
+	serviceAccountsGroup := handler.Group("/service_accounts", app.AuthMiddleware().Middleware, can(entity.PermissionServiceAccountManage), validate)
+	{
+		serviceAccountsGroup.POST("", app.PostServiceAccountHandler().Handle)
+		serviceAccountsGroup.GET("", app.GetServiceAccountsHandler().Handle)
+		serviceAccountsGroup.POST("/:accountId/keys", app.PostAPIKeyHandler().Handle)
+		serviceAccountsGroup.POST("/:accountId/keys/:keyId/rotate", app.PostAPIKeyRotateHandler().Handle)
+		serviceAccountsGroup.DELETE("/:accountId/keys/:keyId", app.DeleteAPIKeyHandler().Handle)
+	}
+
+	auditGroup := handler.Group("/audit", app.AuthMiddleware().Middleware, can(entity.PermissionAuditRead), validate)
+	{
+		auditGroup.GET("", app.GetAuditHandler().Handle)
+		auditGroup.GET("/export", app.GetAuditExportHandler().Handle)
+	}
+
+	sessionsGroup := handler.Group("/sessions", app.AuthMiddleware().Middleware, middleware.UsersOnly, validate)
+	{
+		sessionsGroup.GET("", app.GetSessionsHandler().Handle)
+		sessionsGroup.DELETE("/:sessionId", app.DeleteSessionHandler().Handle)
+	}
+
+	receptionsGroup := handler.Group("/receptions", app.AuthMiddleware().Middleware, validate)
+	{
+		receptionsGroup.POST("", strict.PostReceptions, can(entity.PermissionReceptionOpen), idempotent)
+	}
+
+	productsGroup := handler.Group("/products", app.AuthMiddleware().Middleware, validate)
+	{
+		productsGroup.POST("", strict.PostProducts, can(entity.PermissionProductAdd), idempotent)
+		productsGroup.POST("/:productId/store", strict.PostProductsProductIdStore, can(entity.PermissionProductMove))
+		productsGroup.POST("/:productId/issue", strict.PostProductsProductIdIssue, can(entity.PermissionProductMove))
+		productsGroup.POST("/:productId/return", strict.PostProductsProductIdReturn, can(entity.PermissionProductMove))
+		productsGroup.POST("/:productId/write_off", strict.PostProductsProductIdWriteOff, can(entity.PermissionProductWriteOff))
+		productsGroup.GET("/:productId/history", strict.GetProductsProductIdHistory, can(entity.PermissionProductRead))
+		productsGroup.GET("/cell", app.GetProductCellHandler().Handle, can(entity.PermissionProductRead))
+		productsGroup.POST("/:productId/cell", app.PostProductCellHandler().Handle, can(entity.PermissionProductPlace))
+		productsGroup.GET("/:productId/cell/suggestion", app.GetCellSuggestionHandler().Handle, can(entity.PermissionProductPlace))
+	}
+
+	ordersGroup := handler.Group("/orders", app.AuthMiddleware().Middleware, validate)
+	{
+		ordersGroup.POST("", app.PostOrderHandler().Handle, can(entity.PermissionOrderManage))
+		ordersGroup.GET("", app.GetOrderHandler().Handle, can(entity.PermissionOrderRead))
+		ordersGroup.POST("/:orderId/ready", app.PostOrderReadyHandler().Handle, can(entity.PermissionOrderManage))
+		ordersGroup.POST("/:orderId/code/resend", app.PostOrderCodeResendHandler().Handle, can(entity.PermissionOrderManage))
+		ordersGroup.POST("/:orderId/issue", app.PostOrderIssueHandler().Handle, can(entity.PermissionOrderManage))
+	}
+
+	transfersGroup := handler.Group("/transfers", app.AuthMiddleware().Middleware, validate)
+	{
+		transfersGroup.POST("", app.PostTransferHandler().Handle, can(entity.PermissionTransferManage))
+		transfersGroup.GET("/:transferId", app.GetTransferHandler().Handle, can(entity.PermissionTransferRead))
+		transfersGroup.POST("/:transferId/dispatch", app.PostTransferDispatchHandler().Handle, can(entity.PermissionTransferManage))
+		transfersGroup.POST("/:transferId/accept", app.PostTransferAcceptHandler().Handle, can(entity.PermissionTransferManage))
+	}
+
+	pvzGroup := handler.Group("/pvz", app.AuthMiddleware().Middleware, validate)
+	{
+		pvzGroup.POST("/:pvzId/close_last_reception", strict.PostPvzPvzIdCloseLastReception, can(entity.PermissionReceptionClose))
+		pvzGroup.POST("/:pvzId/delete_last_product", strict.PostPvzPvzIdDeleteLastProduct, can(entity.PermissionProductDelete))
+		pvzGroup.POST("", strict.PostPvz, can(entity.PermissionPointCreate), idempotent)
+		pvzGroup.GET("", strict.GetPvz, can(entity.PermissionPointRead))
+		pvzGroup.GET("/stock", app.GetCityStockHandler().Handle, can(entity.PermissionPointRead))
+		pvzGroup.GET("/:pvzId/stock", app.GetPointStockHandler().Handle, can(entity.PermissionPointRead))
+		pvzGroup.POST("/:pvzId/cells", app.PostCellHandler().Handle, can(entity.PermissionCellManage))
+		pvzGroup.GET("/:pvzId/cells", app.GetCellsHandler().Handle, can(entity.PermissionPointRead))
+	}
 }
@@ -1,17 +1,25 @@
 package app
 
 import (
+	"github.com/4udiwe/avito-pvz/config"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/service/audit"
+	"github.com/4udiwe/avito-pvz/internal/service/cell"
+	"github.com/4udiwe/avito-pvz/internal/service/order"
 	"github.com/4udiwe/avito-pvz/internal/service/point"
 	"github.com/4udiwe/avito-pvz/internal/service/product"
 	"github.com/4udiwe/avito-pvz/internal/service/reception"
+	"github.com/4udiwe/avito-pvz/internal/service/service_account"
+	"github.com/4udiwe/avito-pvz/internal/service/transfer"
 	"github.com/4udiwe/avito-pvz/internal/service/user"
+	"github.com/samber/lo"
 )
 
 func (app *App) PointService() *point.Service {
 	if app.pointService != nil {
 		return app.pointService
 	}
-	app.pointService = point.New(app.PointRepo(), app.ReceptionRepo(), app.ProductRepo(), app.Postgres(), app.PointMetrics())
+	app.pointService = point.New(app.PointRepo(), app.ReceptionRepo(), app.ProductRepo(), app.AuditRepo(), app.Postgres(), app.PointMetrics())
 	return app.pointService
 }
 
@@ -19,7 +27,7 @@ func (app *App) ProductService() *product.Service {
 	if app.productService != nil {
 		return app.productService
 	}
-	app.productService = product.New(app.ProductRepo(), app.ReceptionRepo(), app.Postgres(), app.ProductMetrics())
+	app.productService = product.New(app.ProductRepo(), app.ReceptionRepo(), app.AuditRepo(), app.Postgres(), app.ProductMetrics())
 	return app.productService
 }
 
@@ -27,7 +35,7 @@ func (app *App) ReceptionService() *reception.Service {
 	if app.receptionService != nil {
 		return app.receptionService
 	}
-	app.receptionService = reception.New(app.ReceptionRepo(), app.Postgres(), app.ReceptionMetrics())
+	app.receptionService = reception.New(app.ReceptionRepo(), app.AuditRepo(), app.Postgres(), app.ReceptionMetrics())
 	return app.receptionService
 }
 
@@ -35,6 +43,120 @@ func (app *App) UserService() *user.Service {
 	if app.userService != nil {
 		return app.userService
 	}
-	app.userService = user.New(app.UserRepo(), app.Postgres(), app.Auth(), app.Hasher())
+	app.userService = user.New(
+		app.UserRepo(),
+		app.SessionRepo(),
+		app.Postgres(),
+		app.Auth(),
+		app.PasswordHasher(),
+		app.RevocationList(),
+		app.ResetRepo(),
+		app.InviteRepo(),
+		app.Notifier(),
+		app.FailureRepo(),
+		app.LoginMetrics(),
+		app.AuditRepo(),
+		app.DisabledUsers(),
+		app.OIDCProvider(),
+		app.OIDCStateRepo(),
+		app.IdentityRepo(),
+		app.MFARepo(),
+		app.MFAChallengeRepo(),
+		app.Encryptor(),
+		user.Policy{
+			ResetTokenTTL:    app.cfg.Password.ResetTokenTTL,
+			InvitationTTL:    app.cfg.Registration.InvitationTTL,
+			OpenRegistration: app.cfg.Registration.Open,
+			Login: user.LoginPolicy{
+				MaxEmailFailures: app.cfg.Login.MaxEmailFailures,
+				MaxIPFailures:    app.cfg.Login.MaxIPFailures,
+				BaseDelay:        app.cfg.Login.BaseDelay,
+				LockDuration:     app.cfg.Login.LockDuration,
+				FailureWindow:    app.cfg.Login.FailureWindow,
+			},
+			OIDC: user.OIDCPolicy{
+				Enabled:   app.cfg.OIDC.Enabled,
+				Issuer:    app.cfg.OIDC.Issuer,
+				RoleClaim: app.cfg.OIDC.RoleClaim,
+				RoleMappings: lo.Map(app.cfg.OIDC.RoleMappings, func(m config.OIDCRoleMapping, _ int) user.OIDCRoleMapping {
+					return user.OIDCRoleMapping{Value: m.Value, Role: entity.UserRole(m.Role)}
+				}),
+				DefaultRole: entity.UserRole(app.cfg.OIDC.DefaultRole),
+				StateTTL:    app.cfg.OIDC.StateTTL,
+				MFAMethods:  lo.Compact(app.cfg.OIDC.MFAMethods),
+			},
+			MFA: user.MFAPolicy{
+				Issuer: app.cfg.MFA.Issuer,
+				// MFA_REQUIRED_ROLES= (empty) reads as a single empty role
+				RequiredRoles: lo.FilterMap(app.cfg.MFA.RequiredRoles, func(r string, _ int) (entity.UserRole, bool) {
+					return entity.UserRole(r), r != ""
+				}),
+				ChallengeTTL:  app.cfg.MFA.ChallengeTTL,
+				MaxAttempts:   app.cfg.MFA.MaxAttempts,
+				RecoveryCodes: app.cfg.MFA.RecoveryCodes,
+			},
+		},
+	)
 	return app.userService
 }
+
+func (app *App) OrderService() *order.Service {
+	if app.orderService != nil {
+		return app.orderService
+	}
+	app.orderService = order.New(
+		app.OrderRepo(),
+		app.ProductRepo(),
+		app.AuditRepo(),
+		app.Postgres(),
+		app.Hasher(),
+		app.Notifier(),
+		order.PickupPolicy{
+			CodeTTL:      app.cfg.Pickup.CodeTTL,
+			MaxAttempts:  app.cfg.Pickup.MaxAttempts,
+			LockDuration: app.cfg.Pickup.LockDuration,
+		},
+	)
+	return app.orderService
+}
+
+func (app *App) TransferService() *transfer.Service {
+	if app.transferService != nil {
+		return app.transferService
+	}
+	app.transferService = transfer.New(app.TransferRepo(), app.ProductRepo(), app.ReceptionRepo(), app.AuditRepo(), app.Postgres())
+	return app.transferService
+}
+
+func (app *App) CellService() *cell.Service {
+	if app.cellService != nil {
+		return app.cellService
+	}
+	app.cellService = cell.New(app.CellRepo(), app.ProductRepo(), app.Postgres())
+	return app.cellService
+}
+
+func (app *App) ServiceAccountService() *service_account.Service {
+	if app.accountService != nil {
+		return app.accountService
+	}
+	app.accountService = service_account.New(
+		app.ServiceAccountRepo(),
+		app.APIKeyRepo(),
+		app.AuditRepo(),
+		app.Postgres(),
+		service_account.Policy{
+			DefaultTTL:    app.cfg.APIKeys.DefaultTTL,
+			RotationGrace: app.cfg.APIKeys.RotationGrace,
+		},
+	)
+	return app.accountService
+}
+
+func (app *App) AuditService() *audit.Service {
+	if app.auditService != nil {
+		return app.auditService
+	}
+	app.auditService = audit.New(app.AuditRepo(), app.AuditSigner(), app.Postgres())
+	return app.auditService
+}
@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// DisabledUserStore lists users whose accounts are disabled.
+type DisabledUserStore interface {
+	GetDisabledIDs(ctx context.Context) ([]uuid.UUID, error)
+}
+
+// DisabledUsers keeps the IDs of disabled users in memory so the auth
+// middleware can reject their access tokens without a database query.
+// Set updates this instance right away; Sync picks up changes made by
+// other instances.
+type DisabledUsers struct {
+	store    DisabledUserStore
+	mu       sync.RWMutex
+	disabled map[uuid.UUID]struct{}
+	// changed holds the time of local Sets, so a Sync whose snapshot was
+	// taken before a Set does not undo it.
+	changed map[uuid.UUID]time.Time
+}
+
+func NewDisabledUsers(store DisabledUserStore) *DisabledUsers {
+	return &DisabledUsers{
+		store:    store,
+		disabled: make(map[uuid.UUID]struct{}),
+		changed:  make(map[uuid.UUID]time.Time),
+	}
+}
+
+func (d *DisabledUsers) Set(userID uuid.UUID, disabled bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if disabled {
+		d.disabled[userID] = struct{}{}
+	} else {
+		delete(d.disabled, userID)
+	}
+	d.changed[userID] = time.Now()
+}
+
+func (d *DisabledUsers) IsDisabled(userID uuid.UUID) bool {
+	d.mu.RLock()
+	_, ok := d.disabled[userID]
+	d.mu.RUnlock()
+
+	return ok
+}
+
+// Sync replaces the set with the store's. Users Set after the store was
+// read keep their local state; the next Sync sees it in the store.
+func (d *DisabledUsers) Sync(ctx context.Context) error {
+	startedAt := time.Now()
+
+	ids, err := d.store.GetDisabledIDs(ctx)
+	if err != nil {
+		return err
+	}
+
+	disabled := make(map[uuid.UUID]struct{}, len(ids))
+	for _, id := range ids {
+		disabled[id] = struct{}{}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for id, changedAt := range d.changed {
+		if !changedAt.After(startedAt) {
+			delete(d.changed, id)
+			continue
+		}
+		if _, ok := d.disabled[id]; ok {
+			disabled[id] = struct{}{}
+		} else {
+			delete(disabled, id)
+		}
+	}
+	d.disabled = disabled
+
+	return nil
+}
+
+// Run syncs the set every interval until ctx is cancelled.
+func (d *DisabledUsers) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.Sync(ctx); err != nil {
+				logrus.Errorf("DisabledUsers - Sync: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type disabledStore struct {
+	ids []uuid.UUID
+	err error
+	// onRead runs after the store is read, like a change racing a sync
+	onRead func()
+}
+
+func (s *disabledStore) GetDisabledIDs(_ context.Context) ([]uuid.UUID, error) {
+	ids := s.ids
+	if s.onRead != nil {
+		s.onRead()
+	}
+	return ids, s.err
+}
+
+func TestDisabledUsers(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("set and clear", func(t *testing.T) {
+		users := auth.NewDisabledUsers(&disabledStore{})
+		userID := uuid.New()
+
+		users.Set(userID, true)
+		assert.True(t, users.IsDisabled(userID))
+		assert.False(t, users.IsDisabled(uuid.New()))
+
+		users.Set(userID, false)
+		assert.False(t, users.IsDisabled(userID))
+	})
+
+	t.Run("sync replaces the set", func(t *testing.T) {
+		enabledElsewhere, disabledElsewhere := uuid.New(), uuid.New()
+		store := &disabledStore{ids: []uuid.UUID{disabledElsewhere}}
+		users := auth.NewDisabledUsers(store)
+		users.Set(enabledElsewhere, true)
+
+		require.NoError(t, users.Sync(ctx))
+
+		assert.False(t, users.IsDisabled(enabledElsewhere))
+		assert.True(t, users.IsDisabled(disabledElsewhere))
+	})
+
+	t.Run("sync keeps sets made after the store was read", func(t *testing.T) {
+		disabledHere, enabledHere := uuid.New(), uuid.New()
+		store := &disabledStore{ids: []uuid.UUID{enabledHere}}
+		users := auth.NewDisabledUsers(store)
+		store.onRead = func() {
+			users.Set(disabledHere, true)
+			users.Set(enabledHere, false)
+		}
+
+		require.NoError(t, users.Sync(ctx))
+
+		assert.True(t, users.IsDisabled(disabledHere))
+		assert.False(t, users.IsDisabled(enabledHere))
+	})
+
+	t.Run("later sync overrides earlier sets", func(t *testing.T) {
+		userID := uuid.New()
+		store := &disabledStore{}
+		users := auth.NewDisabledUsers(store)
+		store.onRead = func() { users.Set(userID, true) }
+		require.NoError(t, users.Sync(ctx))
+
+		// Enabled again by another instance
+		store.onRead = nil
+		require.NoError(t, users.Sync(ctx))
+
+		assert.False(t, users.IsDisabled(userID))
+	})
+
+	t.Run("failed sync keeps the set", func(t *testing.T) {
+		userID := uuid.New()
+		store := &disabledStore{err: errors.New("db down")}
+		users := auth.NewDisabledUsers(store)
+		users.Set(userID, true)
+
+		assert.Error(t, users.Sync(ctx))
+		assert.True(t, users.IsDisabled(userID))
+	})
+}
@@ -2,55 +2,100 @@ package auth
 
 import (
 	"errors"
-	"os"
+	"fmt"
 	"time"
 
 	"github.com/4udiwe/avito-pvz/internal/entity"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 )
 
 var (
-	jwtSecret     = []byte(os.Getenv("JWT_SECRET"))
-	refreshSecret = []byte(os.Getenv("REFRESH_SECRET"))
-
 	ErrInvalidAccessToken  = errors.New("invalid access token")
 	ErrInvalidRefreshToken = errors.New("invalid refresh token")
 	ErrExpiredToken        = errors.New("token has expired")
+	ErrRevokedToken        = errors.New("token has been revoked")
+	ErrDisabledUser        = errors.New("user is disabled")
+)
+
+const (
+	AccessTokenTTL  = 15 * time.Minute
+	RefreshTokenTTL = 7 * 24 * time.Hour
 )
 
-type Auth struct{}
+// Options configures token signing. Access tokens are signed with
+// Algorithm: HS256 uses AccessSecret, RS256 and EdDSA use the key pair
+// SigningKeyID from Keys, while all Keys stay valid for verification.
+// Refresh tokens never leave this service and are always HMAC-signed
+// with RefreshSecret.
+type Options struct {
+	Algorithm     string
+	AccessSecret  string
+	RefreshSecret string
+	SigningKeyID  string
+	Keys          []KeyFile
+}
 
-func New() *Auth {
-	return &Auth{}
+type Auth struct {
+	keys          *keySet
+	refreshSecret []byte
 }
 
-func (a *Auth) GenerateTokens(user entity.User) (*Tokens, error) {
+// New validates key material and refuses missing or weak secrets.
+func New(opts Options) (*Auth, error) {
+	if len(opts.RefreshSecret) < MinSecretLength {
+		return nil, fmt.Errorf("refresh token %w (at least %d bytes)", ErrWeakSecret, MinSecretLength)
+	}
+
+	keys, err := newKeySet(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Auth{
+		keys:          keys,
+		refreshSecret: []byte(opts.RefreshSecret),
+	}, nil
+}
+
+// JWKS returns public keys that verify access tokens. It is empty for HS256.
+func (a *Auth) JWKS() JWKS {
+	return a.keys.jwks
+}
+
+// GenerateTokens issues an access/refresh pair bound to the given session.
+func (a *Auth) GenerateTokens(user entity.User, sessionID uuid.UUID) (*Tokens, error) {
 	// Access token (15 min TTL)
 	accessClaims := TokenClaims{
-		UserID: user.ID,
-		Email:  user.Email,
-		Role:   user.Role,
+		UserID:    user.ID,
+		Email:     user.Email,
+		Role:      user.Role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(15 * time.Minute)),
+			ID:        uuid.NewString(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(AccessTokenTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
-	accessTokenString, err := accessToken.SignedString(jwtSecret)
+	accessTokenString, err := a.keys.sign(accessClaims)
 	if err != nil {
 		return nil, err
 	}
 
 	// Refresh token (7 days)
-	refreshClaims := jwt.RegisteredClaims{
-		Subject:   user.Email,
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(7 * 24 * time.Hour)),
-		IssuedAt:  jwt.NewNumericDate(time.Now()),
+	refreshClaims := RefreshClaims{
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   user.Email,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(RefreshTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
 	}
 
 	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
-	refreshTokenString, err := refreshToken.SignedString(refreshSecret)
+	refreshTokenString, err := refreshToken.SignedString(a.refreshSecret)
 	if err != nil {
 		return nil, err
 	}
@@ -58,14 +103,12 @@ func (a *Auth) GenerateTokens(user entity.User) (*Tokens, error) {
 	return &Tokens{
 		AccessToken:  accessTokenString,
 		RefreshToken: refreshTokenString,
-		ExpiresIn:    15 * 60, // 15 mins
+		ExpiresIn:    int64(AccessTokenTTL.Seconds()),
 	}, nil
 }
 
 func (a *Auth) ValidateAccessToken(tokenString string) (*TokenClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &TokenClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
-	})
+	token, err := a.keys.parse(tokenString, &TokenClaims{})
 
 	if err != nil {
 		return nil, err
@@ -78,18 +121,19 @@ func (a *Auth) ValidateAccessToken(tokenString string) (*TokenClaims, error) {
 	return nil, ErrInvalidAccessToken
 }
 
-func (a *Auth) ValidateRefreshToken(tokenString string) (string, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &jwt.RegisteredClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return refreshSecret, nil
+func (a *Auth) ValidateRefreshToken(tokenString string) (*RefreshClaims, error) {
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	token, err := parser.ParseWithClaims(tokenString, &RefreshClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return a.refreshSecret, nil
 	})
 
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	if claims, ok := token.Claims.(*jwt.RegisteredClaims); ok && token.Valid {
-		return claims.Subject, nil
+	if claims, ok := token.Claims.(*RefreshClaims); ok && token.Valid {
+		return claims, nil
 	}
 
-	return "", ErrInvalidRefreshToken
+	return nil, ErrInvalidRefreshToken
 }
@@ -0,0 +1,249 @@
+package auth_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	accessSecret  = "access-secret-access-secret-0123"
+	refreshSecret = "refresh-secret-refresh-secret-01"
+)
+
+func writePEM(t *testing.T, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+	return path
+}
+
+func edKeyFiles(t *testing.T) (privatePath, publicPath string) {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	require.NoError(t, err)
+
+	return writePEM(t, "key.pem", "PRIVATE KEY", privateDER), writePEM(t, "key.pub.pem", "PUBLIC KEY", publicDER)
+}
+
+func rsaKeyFile(t *testing.T, bits int) string {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	require.NoError(t, err)
+	return writePEM(t, "rsa.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key))
+}
+
+func TestNew(t *testing.T) {
+	edPrivate, edPublic := edKeyFiles(t)
+
+	for _, tc := range []struct {
+		name    string
+		opts    auth.Options
+		wantErr error
+	}{
+		{
+			name: "hmac",
+			opts: auth.Options{Algorithm: auth.AlgorithmHS256, AccessSecret: accessSecret, RefreshSecret: refreshSecret},
+		},
+		{
+			name:    "missing access secret",
+			opts:    auth.Options{Algorithm: auth.AlgorithmHS256, RefreshSecret: refreshSecret},
+			wantErr: auth.ErrWeakSecret,
+		},
+		{
+			name:    "short access secret",
+			opts:    auth.Options{Algorithm: auth.AlgorithmHS256, AccessSecret: "jwt_secret", RefreshSecret: refreshSecret},
+			wantErr: auth.ErrWeakSecret,
+		},
+		{
+			name:    "same access and refresh secret",
+			opts:    auth.Options{Algorithm: auth.AlgorithmHS256, AccessSecret: refreshSecret, RefreshSecret: refreshSecret},
+			wantErr: auth.ErrWeakSecret,
+		},
+		{
+			name:    "missing refresh secret",
+			opts:    auth.Options{Algorithm: auth.AlgorithmHS256, AccessSecret: accessSecret},
+			wantErr: auth.ErrWeakSecret,
+		},
+		{
+			name:    "unknown algorithm",
+			opts:    auth.Options{Algorithm: "none", RefreshSecret: refreshSecret},
+			wantErr: auth.ErrUnknownAlgorithm,
+		},
+		{
+			name: "eddsa",
+			opts: auth.Options{
+				Algorithm:     auth.AlgorithmEdDSA,
+				RefreshSecret: refreshSecret,
+				SigningKeyID:  "new",
+				Keys:          []auth.KeyFile{{ID: "new", PrivateKeyPath: edPrivate}},
+			},
+		},
+		{
+			name: "signing key has only public part",
+			opts: auth.Options{
+				Algorithm:     auth.AlgorithmEdDSA,
+				RefreshSecret: refreshSecret,
+				SigningKeyID:  "old",
+				Keys:          []auth.KeyFile{{ID: "old", PublicKeyPath: edPublic}},
+			},
+			wantErr: auth.ErrNoSigningKey,
+		},
+		{
+			name: "weak rsa key",
+			opts: auth.Options{
+				Algorithm:     auth.AlgorithmRS256,
+				RefreshSecret: refreshSecret,
+				SigningKeyID:  "rsa",
+				Keys:          []auth.KeyFile{{ID: "rsa", PrivateKeyPath: rsaKeyFile(t, 1024)}},
+			},
+			wantErr: auth.ErrWeakKey,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := auth.New(tc.opts)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTokens(t *testing.T) {
+	var (
+		user      = entity.User{ID: uuid.New(), Email: "employee@mail.com", Role: entity.RoleEmployee}
+		sessionID = uuid.New()
+	)
+
+	oldPrivate, _ := edKeyFiles(t)
+	newPrivate, _ := edKeyFiles(t)
+
+	t.Run("rsa round trip with kid", func(t *testing.T) {
+		a, err := auth.New(auth.Options{
+			Algorithm:     auth.AlgorithmRS256,
+			RefreshSecret: refreshSecret,
+			SigningKeyID:  "rsa-1",
+			Keys:          []auth.KeyFile{{ID: "rsa-1", PrivateKeyPath: rsaKeyFile(t, 2048)}},
+		})
+		require.NoError(t, err)
+
+		tokens, err := a.GenerateTokens(user, sessionID)
+		require.NoError(t, err)
+
+		token, _, err := jwt.NewParser().ParseUnverified(tokens.AccessToken, &auth.TokenClaims{})
+		require.NoError(t, err)
+		assert.Equal(t, "rsa-1", token.Header["kid"])
+		assert.Equal(t, "RS256", token.Header["alg"])
+
+		claims, err := a.ValidateAccessToken(tokens.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, claims.UserID)
+		assert.Equal(t, sessionID, claims.SessionID)
+
+		refresh, err := a.ValidateRefreshToken(tokens.RefreshToken)
+		require.NoError(t, err)
+		assert.Equal(t, sessionID, refresh.SessionID)
+
+		jwks := a.JWKS()
+		require.Len(t, jwks.Keys, 1)
+		assert.Equal(t, "RSA", jwks.Keys[0].Kty)
+		assert.Equal(t, "AQAB", jwks.Keys[0].E)
+	})
+
+	t.Run("tokens signed with rotated out key stay valid", func(t *testing.T) {
+		before, err := auth.New(auth.Options{
+			Algorithm:     auth.AlgorithmEdDSA,
+			RefreshSecret: refreshSecret,
+			SigningKeyID:  "old",
+			Keys:          []auth.KeyFile{{ID: "old", PrivateKeyPath: oldPrivate}},
+		})
+		require.NoError(t, err)
+		after, err := auth.New(auth.Options{
+			Algorithm:     auth.AlgorithmEdDSA,
+			RefreshSecret: refreshSecret,
+			SigningKeyID:  "new",
+			Keys: []auth.KeyFile{
+				{ID: "new", PrivateKeyPath: newPrivate},
+				{ID: "old", PrivateKeyPath: oldPrivate},
+			},
+		})
+		require.NoError(t, err)
+
+		tokens, err := before.GenerateTokens(user, sessionID)
+		require.NoError(t, err)
+
+		_, err = after.ValidateAccessToken(tokens.AccessToken)
+		assert.NoError(t, err)
+		assert.Len(t, after.JWKS().Keys, 2)
+
+		newTokens, err := after.GenerateTokens(user, sessionID)
+		require.NoError(t, err)
+		_, err = before.ValidateAccessToken(newTokens.AccessToken)
+		assert.ErrorIs(t, err, auth.ErrUnknownKeyID)
+	})
+
+	t.Run("algorithm confusion is rejected", func(t *testing.T) {
+		a, err := auth.New(auth.Options{
+			Algorithm:     auth.AlgorithmEdDSA,
+			RefreshSecret: refreshSecret,
+			SigningKeyID:  "old",
+			Keys:          []auth.KeyFile{{ID: "old", PrivateKeyPath: oldPrivate}},
+		})
+		require.NoError(t, err)
+
+		// HS256 token keyed with the published public key
+		public := []byte(a.JWKS().Keys[0].X)
+		forged := jwt.NewWithClaims(jwt.SigningMethodHS256, auth.TokenClaims{
+			UserID: user.ID,
+			Role:   entity.RoleModerator,
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			},
+		})
+		forged.Header["kid"] = "old"
+		forgedString, err := forged.SignedString(public)
+		require.NoError(t, err)
+
+		_, err = a.ValidateAccessToken(forgedString)
+		assert.Error(t, err)
+
+		unsigned := jwt.NewWithClaims(jwt.SigningMethodNone, auth.TokenClaims{UserID: user.ID})
+		unsignedString, err := unsigned.SignedString(jwt.UnsafeAllowNoneSignatureType)
+		require.NoError(t, err)
+
+		_, err = a.ValidateAccessToken(unsignedString)
+		assert.Error(t, err)
+	})
+
+	t.Run("access token is not accepted as refresh token", func(t *testing.T) {
+		a, err := auth.New(auth.Options{Algorithm: auth.AlgorithmHS256, AccessSecret: accessSecret, RefreshSecret: refreshSecret})
+		require.NoError(t, err)
+
+		tokens, err := a.GenerateTokens(user, sessionID)
+		require.NoError(t, err)
+		assert.Empty(t, a.JWKS().Keys)
+
+		_, err = a.ValidateRefreshToken(tokens.AccessToken)
+		assert.Error(t, err)
+	})
+}
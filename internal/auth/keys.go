@@ -0,0 +1,229 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+
+	// MinSecretLength is the minimal HMAC secret length in bytes (256 bits).
+	MinSecretLength = 32
+	// MinRSAKeyBits is the minimal RSA modulus size accepted for signing.
+	MinRSAKeyBits = 2048
+
+	defaultKeyID = "default"
+)
+
+var (
+	ErrUnknownAlgorithm = errors.New("unknown signing algorithm")
+	ErrWeakSecret       = errors.New("secret is missing or too short")
+	ErrWeakKey          = errors.New("key is too weak")
+	ErrNoSigningKey     = errors.New("no private key for signing key id")
+	ErrUnknownKeyID     = errors.New("unknown key id")
+)
+
+// KeyFile points to PEM encoded key material of one key pair. Keys that are
+// only used to verify tokens issued before a rotation need just the public part.
+type KeyFile struct {
+	ID             string
+	PrivateKeyPath string
+	PublicKeyPath  string
+}
+
+// keySet holds the key used to sign access tokens and every key that is
+// still accepted when verifying them, indexed by kid.
+type keySet struct {
+	method     jwt.SigningMethod
+	signingKID string
+	signingKey any
+	verifyKeys map[string]any
+	jwks       JWKS
+}
+
+func newKeySet(opts Options) (*keySet, error) {
+	switch opts.Algorithm {
+	case AlgorithmHS256:
+		return newHMACKeySet(opts)
+	case AlgorithmRS256, AlgorithmEdDSA:
+		return newAsymmetricKeySet(opts)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownAlgorithm, opts.Algorithm)
+	}
+}
+
+func newHMACKeySet(opts Options) (*keySet, error) {
+	if len(opts.AccessSecret) < MinSecretLength {
+		return nil, fmt.Errorf("access token %w (at least %d bytes)", ErrWeakSecret, MinSecretLength)
+	}
+	if opts.AccessSecret == opts.RefreshSecret {
+		return nil, fmt.Errorf("access and refresh secrets must differ: %w", ErrWeakSecret)
+	}
+
+	kid := opts.SigningKeyID
+	if kid == "" {
+		kid = defaultKeyID
+	}
+
+	return &keySet{
+		method:     jwt.SigningMethodHS256,
+		signingKID: kid,
+		signingKey: []byte(opts.AccessSecret),
+		verifyKeys: map[string]any{kid: []byte(opts.AccessSecret)},
+		// Symmetric keys are never published
+		jwks: JWKS{Keys: []JWK{}},
+	}, nil
+}
+
+func newAsymmetricKeySet(opts Options) (*keySet, error) {
+	ks := &keySet{
+		verifyKeys: make(map[string]any),
+		jwks:       JWKS{Keys: []JWK{}},
+		signingKID: opts.SigningKeyID,
+	}
+	if opts.Algorithm == AlgorithmRS256 {
+		ks.method = jwt.SigningMethodRS256
+	} else {
+		ks.method = jwt.SigningMethodEdDSA
+	}
+
+	for _, file := range opts.Keys {
+		if file.ID == "" {
+			return nil, fmt.Errorf("key without id: %w", ErrUnknownKeyID)
+		}
+		if _, ok := ks.verifyKeys[file.ID]; ok {
+			return nil, fmt.Errorf("duplicate key id %q", file.ID)
+		}
+
+		private, public, err := loadKeyPair(opts.Algorithm, file)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", file.ID, err)
+		}
+
+		if file.ID == opts.SigningKeyID {
+			ks.signingKey = private
+		}
+		ks.verifyKeys[file.ID] = public
+		ks.jwks.Keys = append(ks.jwks.Keys, newJWK(file.ID, opts.Algorithm, public))
+	}
+
+	if ks.signingKey == nil {
+		return nil, fmt.Errorf("%w %q", ErrNoSigningKey, opts.SigningKeyID)
+	}
+	return ks, nil
+}
+
+func loadKeyPair(algorithm string, file KeyFile) (private crypto.PrivateKey, public crypto.PublicKey, err error) {
+	if file.PrivateKeyPath != "" {
+		pem, err := os.ReadFile(file.PrivateKeyPath)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch algorithm {
+		case AlgorithmRS256:
+			key, err := jwt.ParseRSAPrivateKeyFromPEM(pem)
+			if err != nil {
+				return nil, nil, err
+			}
+			private, public = key, &key.PublicKey
+		case AlgorithmEdDSA:
+			key, err := jwt.ParseEdPrivateKeyFromPEM(pem)
+			if err != nil {
+				return nil, nil, err
+			}
+			edKey, ok := key.(ed25519.PrivateKey)
+			if !ok {
+				return nil, nil, jwt.ErrNotEdPrivateKey
+			}
+			private, public = edKey, edKey.Public()
+		}
+	} else {
+		if file.PublicKeyPath == "" {
+			return nil, nil, errors.New("neither private nor public key file is set")
+		}
+		pem, err := os.ReadFile(file.PublicKeyPath)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch algorithm {
+		case AlgorithmRS256:
+			public, err = jwt.ParseRSAPublicKeyFromPEM(pem)
+		case AlgorithmEdDSA:
+			public, err = jwt.ParseEdPublicKeyFromPEM(pem)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if rsaKey, ok := public.(*rsa.PublicKey); ok && rsaKey.N.BitLen() < MinRSAKeyBits {
+		return nil, nil, fmt.Errorf("%w: RSA key must be at least %d bits", ErrWeakKey, MinRSAKeyBits)
+	}
+	return private, public, nil
+}
+
+func (ks *keySet) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.method, claims)
+	token.Header["kid"] = ks.signingKID
+	return token.SignedString(ks.signingKey)
+}
+
+func (ks *keySet) parse(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	// Only the configured algorithm is accepted, which rules out
+	// "none" and HMAC-with-public-key confusion.
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{ks.method.Alg()}))
+
+	return parser.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := ks.verifyKeys[kid]
+		if !ok {
+			return nil, fmt.Errorf("%w %q", ErrUnknownKeyID, kid)
+		}
+		return key, nil
+	})
+}
+
+// JWK is a public key in JSON Web Key format (RFC 7517).
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+func newJWK(kid string, algorithm string, public crypto.PublicKey) JWK {
+	jwk := JWK{Kid: kid, Use: "sig", Alg: algorithm}
+
+	switch key := public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(key)
+	}
+	return jwk
+}
@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/sirupsen/logrus"
+)
+
+// PermissionStore reads the role to permission mapping.
+type PermissionStore interface {
+	GetPermissions(ctx context.Context) (map[entity.UserRole][]entity.Permission, error)
+}
+
+// Permissions keeps the role to permission mapping in memory. Sync reloads
+// it from the database, so grants changed there take effect on the next
+// sync without a redeploy. Until the first sync no role has permissions.
+type Permissions struct {
+	store PermissionStore
+	mu    sync.RWMutex
+	roles map[entity.UserRole]map[entity.Permission]struct{}
+}
+
+func NewPermissions(store PermissionStore) *Permissions {
+	return &Permissions{
+		store: store,
+		roles: make(map[entity.UserRole]map[entity.Permission]struct{}),
+	}
+}
+
+func (p *Permissions) Allows(role entity.UserRole, permission entity.Permission) bool {
+	p.mu.RLock()
+	_, ok := p.roles[role][permission]
+	p.mu.RUnlock()
+
+	return ok
+}
+
+func (p *Permissions) Sync(ctx context.Context) error {
+	grants, err := p.store.GetPermissions(ctx)
+	if err != nil {
+		return err
+	}
+
+	roles := make(map[entity.UserRole]map[entity.Permission]struct{}, len(grants))
+	for role, permissions := range grants {
+		set := make(map[entity.Permission]struct{}, len(permissions))
+		for _, permission := range permissions {
+			set[permission] = struct{}{}
+		}
+		roles[role] = set
+	}
+
+	p.mu.Lock()
+	p.roles = roles
+	p.mu.Unlock()
+
+	return nil
+}
+
+// Run syncs the mapping every interval until ctx is cancelled.
+func (p *Permissions) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.Sync(ctx); err != nil {
+				logrus.Errorf("Permissions - Sync: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type permissionStore struct {
+	grants map[entity.UserRole][]entity.Permission
+	err    error
+}
+
+func (s *permissionStore) GetPermissions(_ context.Context) (map[entity.UserRole][]entity.Permission, error) {
+	return s.grants, s.err
+}
+
+func TestPermissions(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("nothing allowed before sync", func(t *testing.T) {
+		permissions := auth.NewPermissions(&permissionStore{})
+
+		assert.False(t, permissions.Allows(entity.RoleModerator, entity.PermissionPointCreate))
+	})
+
+	t.Run("sync loads grants", func(t *testing.T) {
+		store := &permissionStore{grants: map[entity.UserRole][]entity.Permission{
+			entity.RoleModerator: {entity.PermissionPointCreate, entity.PermissionPointRead},
+			entity.RoleAuditor:   {},
+		}}
+		permissions := auth.NewPermissions(store)
+
+		require.NoError(t, permissions.Sync(ctx))
+
+		assert.True(t, permissions.Allows(entity.RoleModerator, entity.PermissionPointCreate))
+		assert.False(t, permissions.Allows(entity.RoleModerator, entity.PermissionReceptionOpen))
+		assert.False(t, permissions.Allows(entity.RoleAuditor, entity.PermissionPointRead))
+		assert.False(t, permissions.Allows(entity.RoleEmployee, entity.PermissionPointRead))
+	})
+
+	t.Run("sync applies revoked grants", func(t *testing.T) {
+		store := &permissionStore{grants: map[entity.UserRole][]entity.Permission{
+			entity.RoleEmployee: {entity.PermissionProductWriteOff},
+		}}
+		permissions := auth.NewPermissions(store)
+		require.NoError(t, permissions.Sync(ctx))
+
+		store.grants = map[entity.UserRole][]entity.Permission{entity.RoleEmployee: {}}
+		require.NoError(t, permissions.Sync(ctx))
+
+		assert.False(t, permissions.Allows(entity.RoleEmployee, entity.PermissionProductWriteOff))
+	})
+
+	t.Run("failed sync keeps grants", func(t *testing.T) {
+		store := &permissionStore{grants: map[entity.UserRole][]entity.Permission{
+			entity.RoleEmployee: {entity.PermissionReceptionOpen},
+		}}
+		permissions := auth.NewPermissions(store)
+		require.NoError(t, permissions.Sync(ctx))
+
+		store.err = errors.New("db down")
+		assert.Error(t, permissions.Sync(ctx))
+		assert.True(t, permissions.Allows(entity.RoleEmployee, entity.PermissionReceptionOpen))
+	})
+}
@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// RevocationStore persists revoked access token IDs so that revocations
+// survive restarts and are shared between instances.
+type RevocationStore interface {
+	Create(ctx context.Context, jti string, expiresAt time.Time) error
+	GetActive(ctx context.Context) (map[string]time.Time, error)
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+// RevocationList keeps revoked access token IDs in memory until the tokens
+// expire. Lookups never touch the database; Sync reloads entries revoked by
+// other instances and prunes expired ones from the store.
+type RevocationList struct {
+	store   RevocationStore
+	mu      sync.RWMutex
+	revoked map[string]time.Time
+}
+
+// SessionRevocationID is the entry that revokes every access token issued
+// for a session, for changes such as a new role that must not wait for the
+// tokens to expire.
+func SessionRevocationID(sessionID uuid.UUID) string {
+	return "sid:" + sessionID.String()
+}
+
+func NewRevocationList(store RevocationStore) *RevocationList {
+	return &RevocationList{
+		store:   store,
+		revoked: make(map[string]time.Time),
+	}
+}
+
+func (l *RevocationList) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	if jti == "" || !expiresAt.After(time.Now()) {
+		return nil
+	}
+
+	if err := l.store.Create(ctx, jti, expiresAt); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	l.revoked[jti] = expiresAt
+	l.mu.Unlock()
+
+	return nil
+}
+
+func (l *RevocationList) IsRevoked(jti string) bool {
+	l.mu.RLock()
+	expiresAt, ok := l.revoked[jti]
+	l.mu.RUnlock()
+
+	return ok && expiresAt.After(time.Now())
+}
+
+func (l *RevocationList) Sync(ctx context.Context) error {
+	if _, err := l.store.DeleteExpired(ctx); err != nil {
+		return err
+	}
+
+	active, err := l.store.GetActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for jti, expiresAt := range l.revoked {
+		if !expiresAt.After(now) {
+			delete(l.revoked, jti)
+		}
+	}
+	for jti, expiresAt := range active {
+		l.revoked[jti] = expiresAt
+	}
+
+	return nil
+}
+
+// Run syncs the list every interval until ctx is cancelled.
+func (l *RevocationList) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := l.Sync(ctx); err != nil {
+				logrus.Errorf("RevocationList - Sync: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryStore struct {
+	tokens    map[string]time.Time
+	createErr error
+}
+
+func (s *memoryStore) Create(_ context.Context, jti string, expiresAt time.Time) error {
+	if s.createErr != nil {
+		return s.createErr
+	}
+	s.tokens[jti] = expiresAt
+	return nil
+}
+
+func (s *memoryStore) GetActive(_ context.Context) (map[string]time.Time, error) {
+	active := make(map[string]time.Time)
+	for jti, expiresAt := range s.tokens {
+		if expiresAt.After(time.Now()) {
+			active[jti] = expiresAt
+		}
+	}
+	return active, nil
+}
+
+func (s *memoryStore) DeleteExpired(_ context.Context) (int64, error) {
+	var deleted int64
+	for jti, expiresAt := range s.tokens {
+		if !expiresAt.After(time.Now()) {
+			delete(s.tokens, jti)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func TestRevocationList(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("revoked token is rejected until expiry", func(t *testing.T) {
+		store := &memoryStore{tokens: map[string]time.Time{}}
+		list := auth.NewRevocationList(store)
+
+		require.NoError(t, list.Revoke(ctx, "jti-1", time.Now().Add(time.Minute)))
+
+		assert.True(t, list.IsRevoked("jti-1"))
+		assert.False(t, list.IsRevoked("jti-2"))
+		assert.Contains(t, store.tokens, "jti-1")
+	})
+
+	t.Run("already expired token is not stored", func(t *testing.T) {
+		store := &memoryStore{tokens: map[string]time.Time{}}
+		list := auth.NewRevocationList(store)
+
+		require.NoError(t, list.Revoke(ctx, "jti-1", time.Now().Add(-time.Minute)))
+
+		assert.False(t, list.IsRevoked("jti-1"))
+		assert.Empty(t, store.tokens)
+	})
+
+	t.Run("store error is returned and token is not cached", func(t *testing.T) {
+		storeErr := errors.New("store error")
+		list := auth.NewRevocationList(&memoryStore{tokens: map[string]time.Time{}, createErr: storeErr})
+
+		assert.ErrorIs(t, list.Revoke(ctx, "jti-1", time.Now().Add(time.Minute)), storeErr)
+		assert.False(t, list.IsRevoked("jti-1"))
+	})
+
+	t.Run("sync loads revocations from other instances and prunes expired", func(t *testing.T) {
+		store := &memoryStore{tokens: map[string]time.Time{
+			"remote":  time.Now().Add(time.Minute),
+			"expired": time.Now().Add(-time.Minute),
+		}}
+		list := auth.NewRevocationList(store)
+
+		require.NoError(t, list.Sync(ctx))
+
+		assert.True(t, list.IsRevoked("remote"))
+		assert.False(t, list.IsRevoked("expired"))
+		assert.NotContains(t, store.tokens, "expired")
+	})
+}
@@ -1,7 +1,11 @@
 package auth
 
 import (
+	"fmt"
+	"log/slog"
+
 	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/pkg/redact"
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
 )
@@ -12,9 +16,39 @@ type Tokens struct {
 	ExpiresIn    int64  `json:"expires_in"`
 }
 
+// String masks both tokens, so tokens printed with %v or %+v are safe to
+// log.
+func (t Tokens) String() string {
+	return fmt.Sprintf("{AccessToken:%s RefreshToken:%s ExpiresIn:%d}", redact.Mask, redact.Mask, t.ExpiresIn)
+}
+
+// LogValue is String for log/slog.
+func (t Tokens) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("access_token", redact.Mask),
+		slog.String("refresh_token", redact.Mask),
+		slog.Int64("expires_in", t.ExpiresIn),
+	)
+}
+
+// TokenClaims identify the caller. For requests made with an API key
+// UserID is the service account, APIKeyID is set and Scopes replace the
+// permissions of Role; these fields never appear in a JWT.
 type TokenClaims struct {
-	UserID uuid.UUID       `json:"user_id"`
-	Email  string          `json:"email"`
-	Role   entity.UserRole `json:"role"`
+	UserID    uuid.UUID           `json:"user_id"`
+	Email     string              `json:"email"`
+	Role      entity.UserRole     `json:"role"`
+	SessionID uuid.UUID           `json:"sid"`
+	APIKeyID  uuid.UUID           `json:"-"`
+	Scopes    []entity.Permission `json:"-"`
+	jwt.RegisteredClaims
+}
+
+func (c *TokenClaims) IsServiceAccount() bool {
+	return c.APIKeyID != uuid.Nil
+}
+
+type RefreshClaims struct {
+	SessionID uuid.UUID `json:"sid"`
 	jwt.RegisteredClaims
 }
@@ -0,0 +1,30 @@
+package auth_test
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokensRedacted(t *testing.T) {
+	tokens := &auth.Tokens{AccessToken: "access-secret", RefreshToken: "refresh-secret", ExpiresIn: 900}
+
+	for _, formatted := range []string{
+		fmt.Sprintf("%v", tokens),
+		fmt.Sprintf("%+v", *tokens),
+	} {
+		assert.NotContains(t, formatted, "access-secret")
+		assert.NotContains(t, formatted, "refresh-secret")
+		assert.Contains(t, formatted, "ExpiresIn:900")
+	}
+
+	var out bytes.Buffer
+	slog.New(slog.NewJSONHandler(&out, nil)).Info("issued", "tokens", tokens)
+	assert.NotContains(t, out.String(), "access-secret")
+	assert.NotContains(t, out.String(), "refresh-secret")
+	assert.Contains(t, out.String(), `"expires_in":900`)
+}
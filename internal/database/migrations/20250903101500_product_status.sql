@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE product_status AS ENUM(
+    'received',
+    'stored',
+    'issued',
+    'returned',
+    'written_off'
+);
+
+ALTER TABLE products ADD COLUMN status product_status DEFAULT 'received' NOT NULL;
+
+CREATE TABLE product_history(
+    id UUID DEFAULT gen_random_uuid() NOT NULL,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    from_status product_status,
+    to_status product_status NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    actor_id UUID NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_product_history_product_id_created_at ON product_history(product_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS product_history;
+
+ALTER TABLE products DROP COLUMN IF EXISTS status;
+DROP TYPE IF EXISTS product_status;
+-- +goose StatementEnd
@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE order_status AS ENUM(
+    'assembling',
+    'ready',
+    'issued'
+);
+
+CREATE TABLE orders(
+    id UUID DEFAULT gen_random_uuid() NOT NULL,
+    point_id UUID NOT NULL REFERENCES points(id),
+    number VARCHAR(64) UNIQUE NOT NULL,
+    customer_contact VARCHAR(256) NOT NULL,
+    status order_status DEFAULT 'assembling' NOT NULL,
+    pickup_code_hash CHAR(60),
+    code_expires_at TIMESTAMPTZ,
+    failed_attempts INTEGER DEFAULT 0 NOT NULL,
+    locked_until TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    issued_at TIMESTAMPTZ,
+
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE order_products(
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    product_id UUID UNIQUE NOT NULL REFERENCES products(id),
+
+    PRIMARY KEY (order_id, product_id)
+);
+
+CREATE INDEX idx_orders_point_id_status ON orders(point_id, status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS order_products;
+DROP TABLE IF EXISTS orders;
+DROP TYPE IF EXISTS order_status;
+-- +goose StatementEnd
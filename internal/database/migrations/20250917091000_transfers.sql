@@ -0,0 +1,63 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TYPE product_status ADD VALUE 'in_transit';
+
+-- Current location of a product. Set from its reception and moved by accepted transfers.
+ALTER TABLE products ADD COLUMN point_id UUID REFERENCES points(id);
+UPDATE products p SET point_id = r.point_id FROM receptions r WHERE r.id = p.reception_id;
+ALTER TABLE products ALTER COLUMN point_id SET NOT NULL;
+
+CREATE INDEX idx_products_point_id_status ON products(point_id, status);
+
+CREATE TYPE reception_kind AS ENUM(
+    'regular',
+    'transfer'
+);
+
+ALTER TABLE receptions ADD COLUMN kind reception_kind DEFAULT 'regular' NOT NULL;
+
+CREATE TYPE transfer_status AS ENUM(
+    'created',
+    'dispatched',
+    'accepted'
+);
+
+CREATE TABLE transfers(
+    id UUID DEFAULT gen_random_uuid() NOT NULL,
+    source_point_id UUID NOT NULL REFERENCES points(id),
+    destination_point_id UUID NOT NULL REFERENCES points(id),
+    status transfer_status DEFAULT 'created' NOT NULL,
+    reception_id UUID REFERENCES receptions(id),
+    created_by UUID NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    dispatched_at TIMESTAMPTZ,
+    accepted_at TIMESTAMPTZ,
+
+    PRIMARY KEY (id),
+    CHECK (source_point_id <> destination_point_id)
+);
+
+CREATE TABLE transfer_products(
+    transfer_id UUID NOT NULL REFERENCES transfers(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products(id),
+    accepted BOOLEAN DEFAULT FALSE NOT NULL,
+    missing BOOLEAN DEFAULT FALSE NOT NULL,
+
+    PRIMARY KEY (transfer_id, product_id)
+);
+
+CREATE INDEX idx_transfers_destination_point_id_status ON transfers(destination_point_id, status);
+CREATE INDEX idx_transfer_products_product_id ON transfer_products(product_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS transfer_products;
+DROP TABLE IF EXISTS transfers;
+DROP TYPE IF EXISTS transfer_status;
+
+ALTER TABLE receptions DROP COLUMN IF EXISTS kind;
+DROP TYPE IF EXISTS reception_kind;
+
+ALTER TABLE products DROP COLUMN IF EXISTS point_id;
+-- +goose StatementEnd
@@ -0,0 +1,55 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Products physically present at a point (received or stored), per type.
+-- Maintained incrementally by trigger on products.
+CREATE TABLE point_stock(
+    point_id UUID NOT NULL REFERENCES points(id),
+    type product_type NOT NULL,
+    amount INTEGER DEFAULT 0 NOT NULL CHECK (amount >= 0),
+
+    PRIMARY KEY (point_id, type)
+);
+
+CREATE FUNCTION point_stock_apply(p_point_id UUID, p_type product_type, p_status product_status, p_delta INTEGER)
+RETURNS VOID AS $$
+BEGIN
+    IF p_status NOT IN ('received', 'stored') THEN
+        RETURN;
+    END IF;
+
+    INSERT INTO point_stock(point_id, type, amount)
+    VALUES (p_point_id, p_type, p_delta)
+    ON CONFLICT (point_id, type) DO UPDATE SET amount = point_stock.amount + EXCLUDED.amount;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION point_stock_track() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        PERFORM point_stock_apply(OLD.point_id, OLD.type, OLD.status, -1);
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        PERFORM point_stock_apply(NEW.point_id, NEW.type, NEW.status, 1);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_products_point_stock
+AFTER INSERT OR DELETE OR UPDATE OF status, point_id, type ON products
+FOR EACH ROW EXECUTE FUNCTION point_stock_track();
+
+INSERT INTO point_stock(point_id, type, amount)
+SELECT point_id, type, COUNT(*)
+FROM products
+WHERE status IN ('received', 'stored')
+GROUP BY point_id, type;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS trg_products_point_stock ON products;
+DROP FUNCTION IF EXISTS point_stock_track();
+DROP FUNCTION IF EXISTS point_stock_apply(UUID, product_type, product_status, INTEGER);
+DROP TABLE IF EXISTS point_stock;
+-- +goose StatementEnd
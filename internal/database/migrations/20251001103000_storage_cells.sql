@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE storage_cells(
+    id UUID DEFAULT gen_random_uuid() NOT NULL,
+    point_id UUID NOT NULL REFERENCES points(id),
+    rack VARCHAR(16) NOT NULL,
+    shelf VARCHAR(16) NOT NULL,
+    bin VARCHAR(16) NOT NULL,
+    capacity INTEGER NOT NULL CHECK (capacity > 0),
+    -- NULL means the cell accepts products of any type.
+    product_type product_type,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+
+    PRIMARY KEY (id),
+    UNIQUE (point_id, rack, shelf, bin)
+);
+
+ALTER TABLE products ADD COLUMN barcode VARCHAR(64) UNIQUE;
+ALTER TABLE products ADD COLUMN cell_id UUID REFERENCES storage_cells(id);
+
+CREATE INDEX idx_products_cell_id_status ON products(cell_id, status);
+
+ALTER TABLE product_history ADD COLUMN from_cell_id UUID REFERENCES storage_cells(id);
+ALTER TABLE product_history ADD COLUMN to_cell_id UUID REFERENCES storage_cells(id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE product_history DROP COLUMN IF EXISTS to_cell_id;
+ALTER TABLE product_history DROP COLUMN IF EXISTS from_cell_id;
+
+DROP INDEX IF EXISTS idx_products_cell_id_status;
+ALTER TABLE products DROP COLUMN IF EXISTS cell_id;
+ALTER TABLE products DROP COLUMN IF EXISTS barcode;
+
+DROP TABLE IF EXISTS storage_cells;
+-- +goose StatementEnd
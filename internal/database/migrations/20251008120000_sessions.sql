@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE sessions(
+    id UUID NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    refresh_token_hash CHAR(64) NOT NULL,
+    user_agent VARCHAR(512) DEFAULT '' NOT NULL,
+    ip VARCHAR(64) DEFAULT '' NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    last_used_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_sessions_user_id ON sessions(user_id);
+
+ALTER TABLE users DROP COLUMN refresh_token;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN refresh_token VARCHAR(256);
+
+DROP TABLE IF EXISTS sessions;
+-- +goose StatementEnd
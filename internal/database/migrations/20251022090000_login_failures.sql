@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE login_failures(
+    scope VARCHAR(16) NOT NULL,
+    key VARCHAR(320) NOT NULL,
+    failures INT DEFAULT 0 NOT NULL,
+    last_failure_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    locked_until TIMESTAMPTZ,
+
+    PRIMARY KEY (scope, key)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS login_failures;
+-- +goose StatementEnd
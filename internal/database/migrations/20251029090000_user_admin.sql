@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN disabled_at TIMESTAMPTZ;
+
+CREATE INDEX idx_users_disabled ON users(id) WHERE disabled_at IS NOT NULL;
+
+CREATE TABLE audit_log(
+    id UUID DEFAULT gen_random_uuid() NOT NULL,
+    actor_id UUID NOT NULL,
+    action VARCHAR(64) NOT NULL,
+    target_type VARCHAR(32) NOT NULL,
+    target_id UUID NOT NULL,
+    before JSONB,
+    after JSONB,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_audit_log_target ON audit_log(target_type, target_id);
+CREATE INDEX idx_audit_log_created_at ON audit_log(created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS audit_log;
+
+DROP INDEX IF EXISTS idx_users_disabled;
+
+ALTER TABLE users DROP COLUMN disabled_at;
+-- +goose StatementEnd
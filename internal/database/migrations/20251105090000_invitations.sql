@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE invitations(
+    id UUID DEFAULT gen_random_uuid() NOT NULL,
+    email VARCHAR(256) NOT NULL,
+    role user_role NOT NULL,
+    code_hash CHAR(64) NOT NULL UNIQUE,
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    used_by UUID REFERENCES users(id),
+
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_invitations_email ON invitations(email);
+
+CREATE TABLE invitation_points(
+    invitation_id UUID NOT NULL REFERENCES invitations(id) ON DELETE CASCADE,
+    point_id UUID NOT NULL REFERENCES points(id),
+
+    PRIMARY KEY (invitation_id, point_id)
+);
+
+CREATE TABLE user_points(
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    point_id UUID NOT NULL REFERENCES points(id),
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+
+    PRIMARY KEY (user_id, point_id)
+);
+
+CREATE INDEX idx_user_points_point_id ON user_points(point_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_points;
+DROP TABLE IF EXISTS invitation_points;
+DROP TABLE IF EXISTS invitations;
+-- +goose StatementEnd
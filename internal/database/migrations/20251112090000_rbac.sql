@@ -0,0 +1,108 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE roles(
+    name VARCHAR(32) NOT NULL,
+    description VARCHAR(256) DEFAULT '' NOT NULL,
+
+    PRIMARY KEY (name)
+);
+
+INSERT INTO roles(name, description) VALUES
+    ('employee', 'Сотрудник ПВЗ'),
+    ('senior_employee', 'Старший сотрудник ПВЗ'),
+    ('moderator', 'Модератор'),
+    ('regional_manager', 'Региональный менеджер'),
+    ('auditor', 'Аудитор, только чтение');
+
+CREATE TABLE permissions(
+    name VARCHAR(64) NOT NULL,
+    description VARCHAR(256) DEFAULT '' NOT NULL,
+
+    PRIMARY KEY (name)
+);
+
+INSERT INTO permissions(name, description) VALUES
+    ('point:create', 'Создание ПВЗ'),
+    ('point:read', 'Просмотр ПВЗ, остатков и ячеек'),
+    ('cell:manage', 'Создание ячеек хранения'),
+    ('reception:open', 'Открытие приемки'),
+    ('reception:close', 'Закрытие приемки'),
+    ('product:add', 'Добавление товара в приемку'),
+    ('product:delete', 'Удаление последнего товара приемки'),
+    ('product:move', 'Хранение, выдача и возврат товара'),
+    ('product:place', 'Размещение товара в ячейке'),
+    ('product:write_off', 'Списание товара'),
+    ('product:read', 'Просмотр истории и ячейки товара'),
+    ('order:manage', 'Создание и выдача заказов'),
+    ('order:read', 'Просмотр заказов'),
+    ('transfer:manage', 'Создание, отправка и приемка перемещений'),
+    ('transfer:read', 'Просмотр перемещений'),
+    ('user:read', 'Просмотр пользователей'),
+    ('user:manage', 'Смена роли, блокировка и разблокировка пользователей'),
+    ('user:invite', 'Приглашение пользователей');
+
+CREATE TABLE role_permissions(
+    role VARCHAR(32) NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+    permission VARCHAR(64) NOT NULL REFERENCES permissions(name) ON DELETE CASCADE,
+
+    PRIMARY KEY (role, permission)
+);
+
+INSERT INTO role_permissions(role, permission)
+SELECT 'employee', unnest(ARRAY[
+    'point:read', 'reception:open', 'reception:close',
+    'product:add', 'product:delete', 'product:move', 'product:place', 'product:read',
+    'order:manage', 'order:read', 'transfer:manage', 'transfer:read'
+]);
+
+INSERT INTO role_permissions(role, permission)
+SELECT 'senior_employee', permission FROM role_permissions WHERE role = 'employee'
+UNION ALL SELECT 'senior_employee', 'product:write_off';
+
+INSERT INTO role_permissions(role, permission)
+SELECT 'moderator', unnest(ARRAY[
+    'point:create', 'point:read', 'cell:manage',
+    'product:write_off', 'product:read', 'order:read', 'transfer:read',
+    'user:read', 'user:manage', 'user:invite'
+]);
+
+INSERT INTO role_permissions(role, permission)
+SELECT 'regional_manager', unnest(ARRAY[
+    'point:read', 'cell:manage', 'product:write_off', 'product:read',
+    'order:read', 'transfer:read', 'user:read'
+]);
+
+INSERT INTO role_permissions(role, permission)
+SELECT 'auditor', unnest(ARRAY[
+    'point:read', 'product:read', 'order:read', 'transfer:read', 'user:read'
+]);
+
+ALTER TABLE users ALTER COLUMN role TYPE VARCHAR(32) USING role::text;
+ALTER TABLE users ADD CONSTRAINT fk_users_role FOREIGN KEY (role) REFERENCES roles(name);
+
+ALTER TABLE invitations ALTER COLUMN role TYPE VARCHAR(32) USING role::text;
+ALTER TABLE invitations ADD CONSTRAINT fk_invitations_role FOREIGN KEY (role) REFERENCES roles(name);
+
+DROP TYPE user_role;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE TYPE user_role AS ENUM(
+    'employee',
+    'moderator'
+);
+
+UPDATE users SET role = 'employee' WHERE role NOT IN ('employee', 'moderator');
+DELETE FROM invitations WHERE role NOT IN ('employee', 'moderator');
+
+ALTER TABLE invitations DROP CONSTRAINT fk_invitations_role;
+ALTER TABLE invitations ALTER COLUMN role TYPE user_role USING role::user_role;
+
+ALTER TABLE users DROP CONSTRAINT fk_users_role;
+ALTER TABLE users ALTER COLUMN role TYPE user_role USING role::user_role;
+
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS permissions;
+DROP TABLE IF EXISTS roles;
+-- +goose StatementEnd
@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE service_accounts(
+    id UUID DEFAULT gen_random_uuid() NOT NULL,
+    name VARCHAR(128) NOT NULL UNIQUE,
+    description VARCHAR(512) DEFAULT '' NOT NULL,
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE api_keys(
+    id UUID DEFAULT gen_random_uuid() NOT NULL,
+    service_account_id UUID NOT NULL REFERENCES service_accounts(id) ON DELETE CASCADE,
+    prefix VARCHAR(16) NOT NULL,
+    key_hash CHAR(64) NOT NULL UNIQUE,
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    last_used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_api_keys_service_account_id ON api_keys(service_account_id);
+
+CREATE TABLE api_key_scopes(
+    api_key_id UUID NOT NULL REFERENCES api_keys(id) ON DELETE CASCADE,
+    permission VARCHAR(64) NOT NULL REFERENCES permissions(name) ON DELETE CASCADE,
+
+    PRIMARY KEY (api_key_id, permission)
+);
+
+INSERT INTO permissions(name, description) VALUES
+    ('service_account:manage', 'Управление сервисными аккаунтами и API-ключами');
+
+INSERT INTO role_permissions(role, permission) VALUES
+    ('moderator', 'service_account:manage');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions WHERE name = 'service_account:manage';
+
+DROP TABLE IF EXISTS api_key_scopes;
+DROP TABLE IF EXISTS api_keys;
+DROP TABLE IF EXISTS service_accounts;
+-- +goose StatementEnd
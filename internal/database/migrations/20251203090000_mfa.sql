@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE user_mfa(
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    secret_encrypted TEXT,
+    pending_secret_encrypted TEXT,
+    last_used_step BIGINT DEFAULT 0 NOT NULL,
+    confirmed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+
+    PRIMARY KEY (user_id)
+);
+
+CREATE TABLE mfa_recovery_codes(
+    id UUID DEFAULT gen_random_uuid() NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash CHAR(64) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    used_at TIMESTAMPTZ,
+
+    PRIMARY KEY (id),
+    UNIQUE (user_id, code_hash)
+);
+
+CREATE TABLE mfa_challenges(
+    id UUID DEFAULT gen_random_uuid() NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    attempts INT DEFAULT 0 NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_mfa_challenges_expires_at ON mfa_challenges(expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS mfa_challenges;
+DROP TABLE IF EXISTS mfa_recovery_codes;
+DROP TABLE IF EXISTS user_mfa;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- argon2id hashes in the PHC format are longer than the 60 characters of bcrypt
+ALTER TABLE users ALTER COLUMN password_hash TYPE VARCHAR(255);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Fails while argon2id hashes are stored: such users must reset their password first
+ALTER TABLE users ALTER COLUMN password_hash TYPE CHAR(60);
+-- +goose StatementEnd
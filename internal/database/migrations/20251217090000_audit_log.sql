@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE audit_log ADD COLUMN request_id VARCHAR(128);
+
+CREATE INDEX idx_audit_log_actor ON audit_log(actor_id, created_at);
+CREATE INDEX idx_audit_log_action ON audit_log(action, created_at);
+CREATE INDEX idx_audit_log_request ON audit_log(request_id);
+
+INSERT INTO permissions(name, description) VALUES
+    ('audit:read', 'Просмотр и выгрузка журнала аудита');
+
+INSERT INTO role_permissions(role, permission) VALUES
+    ('moderator', 'audit:read'),
+    ('auditor', 'audit:read');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions WHERE name = 'audit:read';
+
+DROP INDEX IF EXISTS idx_audit_log_request;
+DROP INDEX IF EXISTS idx_audit_log_action;
+DROP INDEX IF EXISTS idx_audit_log_actor;
+
+ALTER TABLE audit_log DROP COLUMN request_id;
+-- +goose StatementEnd
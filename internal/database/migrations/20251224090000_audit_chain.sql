@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Records written before this migration stay outside the chain.
+ALTER TABLE audit_log
+    ADD COLUMN seq BIGINT,
+    ADD COLUMN prev_hash CHAR(64),
+    ADD COLUMN hash CHAR(64);
+
+CREATE UNIQUE INDEX idx_audit_log_seq ON audit_log(seq);
+
+CREATE TABLE audit_checkpoints (
+    seq BIGINT PRIMARY KEY,
+    hash CHAR(64) NOT NULL,
+    signature TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS audit_checkpoints;
+
+DROP INDEX IF EXISTS idx_audit_log_seq;
+
+ALTER TABLE audit_log
+    DROP COLUMN hash,
+    DROP COLUMN prev_hash,
+    DROP COLUMN seq;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Sessions started before this migration did not pass a second factor, so
+-- the ones of roles in mfa.required_roles end at their next refresh
+ALTER TABLE sessions ADD COLUMN mfa_verified BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE sessions DROP COLUMN mfa_verified;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Records are written unsealed and chained by the audit service after the
+-- commit. Records that existed before are either chained already or stay
+-- outside the chain, so they count as sealed.
+ALTER TABLE audit_log ADD COLUMN sealed BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE audit_log ALTER COLUMN sealed SET DEFAULT FALSE;
+
+CREATE INDEX idx_audit_log_unsealed ON audit_log(created_at, id) WHERE NOT sealed;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_audit_log_unsealed;
+
+ALTER TABLE audit_log DROP COLUMN sealed;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Users provisioned by the identity provider have no local password and
+-- sign in only there.
+ALTER TABLE users ALTER COLUMN password_hash DROP NOT NULL;
+
+-- They were created in the same transaction as their identity, so both
+-- rows got the same NOW(); users linked by email keep their password.
+UPDATE users u SET password_hash = NULL
+FROM user_identities i
+WHERE i.user_id = u.id AND i.created_at = u.created_at;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- '!' matches no password
+UPDATE users SET password_hash = '!' WHERE password_hash IS NULL;
+
+ALTER TABLE users ALTER COLUMN password_hash SET NOT NULL;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Replacing an enabled second factor now needs a current code; secrets
+-- started without one must not be confirmed.
+UPDATE user_mfa SET pending_secret_encrypted = NULL
+WHERE confirmed_at IS NOT NULL AND pending_secret_encrypted IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- Cleared pending secrets cannot be restored; the users start over.
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A record is sealed exactly when it has a chain position. A separate flag
+-- could be set without one and keep an edited record out of the chain.
+-- Records written before the chain lose their exemption and are chained
+-- by the next seal.
+DROP INDEX IF EXISTS idx_audit_log_unsealed;
+
+ALTER TABLE audit_log DROP COLUMN sealed;
+
+CREATE INDEX idx_audit_log_unsealed ON audit_log(created_at, id) WHERE seq IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_audit_log_unsealed;
+
+ALTER TABLE audit_log ADD COLUMN sealed BOOLEAN NOT NULL DEFAULT FALSE;
+UPDATE audit_log SET sealed = TRUE WHERE seq IS NOT NULL;
+
+CREATE INDEX idx_audit_log_unsealed ON audit_log(created_at, id) WHERE NOT sealed;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+type AuditRecord struct {
+	Id         openapi_types.UUID `json:"id"`
+	ActorId    openapi_types.UUID `json:"actorId"`
+	Action     string             `json:"action"`
+	TargetType string             `json:"targetType"`
+	TargetId   openapi_types.UUID `json:"targetId"`
+	Before     json.RawMessage    `json:"before,omitempty"`
+	After      json.RawMessage    `json:"after,omitempty"`
+	RequestId  string             `json:"requestId,omitempty"`
+	CreatedAt  time.Time          `json:"createdAt"`
+}
+
+func EntityAuditRecordToDTO(e *entity.AuditRecord) *AuditRecord {
+	return &AuditRecord{
+		Id:         openapi_types.UUID(e.ID),
+		ActorId:    openapi_types.UUID(e.ActorID),
+		Action:     string(e.Action),
+		TargetType: string(e.TargetType),
+		TargetId:   openapi_types.UUID(e.TargetID),
+		Before:     e.Before,
+		After:      e.After,
+		RequestId:  e.RequestID,
+		CreatedAt:  e.CreatedAt,
+	}
+}
@@ -0,0 +1,30 @@
+package dto
+
+import (
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+type StorageCell struct {
+	Id          openapi_types.UUID  `json:"id"`
+	PvzId       openapi_types.UUID  `json:"pvzId"`
+	Rack        string              `json:"rack"`
+	Shelf       string              `json:"shelf"`
+	Bin         string              `json:"bin"`
+	Capacity    int                 `json:"capacity"`
+	Occupied    int                 `json:"occupied"`
+	ProductType *entity.ProductType `json:"productType,omitempty"`
+}
+
+func EntityCellToDTO(e *entity.StorageCell) *StorageCell {
+	return &StorageCell{
+		Id:          openapi_types.UUID(e.ID),
+		PvzId:       openapi_types.UUID(e.PointID),
+		Rack:        e.Rack,
+		Shelf:       e.Shelf,
+		Bin:         e.Bin,
+		Capacity:    e.Capacity,
+		Occupied:    e.Occupied,
+		ProductType: e.ProductType,
+	}
+}
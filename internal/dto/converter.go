@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"github.com/4udiwe/avito-pvz/internal/auth"
 	"github.com/4udiwe/avito-pvz/internal/entity"
 	openapi_types "github.com/oapi-codegen/runtime/types"
 )
@@ -19,21 +20,71 @@ func EntityPointToDTO(e *entity.Point) *PVZ {
 func EntityReceptionToDTO(e *entity.Reception) *Reception {
 	id := openapi_types.UUID(e.ID)
 	pointID := openapi_types.UUID(e.PointID)
-	return &Reception{
+	reception := &Reception{
 		Id:       &id,
 		PvzId:    pointID,
 		DateTime: e.CreatedAt,
 		Status:   ReceptionStatus(e.Status),
 	}
+	if e.Kind != "" {
+		kind := ReceptionKind(e.Kind)
+		reception.Kind = &kind
+	}
+	return reception
 }
 
 func EntityProductToDTO(e *entity.Product) *Product {
 	id := openapi_types.UUID(e.ID)
 	receptionID := openapi_types.UUID(e.ReceptionID)
-	return &Product{
+	product := &Product{
 		Id:          &id,
 		ReceptionId: receptionID,
 		DateTime:    &e.CreatedAt,
 		Type:        ProductType(e.Type),
 	}
+	if e.Status != "" {
+		status := ProductStatus(e.Status)
+		product.Status = &status
+	}
+	product.Barcode = e.Barcode
+	product.CellId = e.CellID
+	return product
+}
+
+func EntityProductHistoryToDTO(e *entity.ProductHistory) *ProductHistoryRecord {
+	record := &ProductHistoryRecord{
+		Id:         openapi_types.UUID(e.ID),
+		ToStatus:   ProductStatus(e.ToStatus),
+		ActorId:    openapi_types.UUID(e.ActorID),
+		FromCellId: e.FromCellID,
+		ToCellId:   e.ToCellID,
+		DateTime:   e.CreatedAt,
+	}
+	if e.FromStatus != nil {
+		from := ProductStatus(*e.FromStatus)
+		record.FromStatus = &from
+	}
+	if e.Reason != "" {
+		record.Reason = &e.Reason
+	}
+	return record
+}
+
+// EntityNewUserToDTO is the User of api/swagger.yaml, returned on
+// registration; moderators see the UserAccount.
+func EntityNewUserToDTO(e *entity.User) *User {
+	id := openapi_types.UUID(e.ID)
+	return &User{
+		Id:    &id,
+		Email: openapi_types.Email(e.Email),
+		Role:  UserRole(e.Role),
+	}
+}
+
+func TokensToDTO(t *auth.Tokens) *TokenPair {
+	return &TokenPair{
+		AccessToken:  t.AccessToken,
+		RefreshToken: t.RefreshToken,
+		ExpiresIn:    t.ExpiresIn,
+	}
 }
@@ -4,8 +4,22 @@
 package dto
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
 	"time"
 
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+	"github.com/oapi-codegen/runtime"
+	strictecho "github.com/oapi-codegen/runtime/strictmiddleware/echo"
 	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
@@ -27,6 +41,22 @@ const (
 	ProductTypeЭлектроника ProductType = "электроника"
 )
 
+// Defines values for ProductStatus.
+const (
+	InTransit  ProductStatus = "in_transit"
+	Issued     ProductStatus = "issued"
+	Received   ProductStatus = "received"
+	Returned   ProductStatus = "returned"
+	Stored     ProductStatus = "stored"
+	WrittenOff ProductStatus = "written_off"
+)
+
+// Defines values for ReceptionKind.
+const (
+	ReceptionKindRegular  ReceptionKind = "regular"
+	ReceptionKindTransfer ReceptionKind = "transfer"
+)
+
 // Defines values for ReceptionStatus.
 const (
 	Close      ReceptionStatus = "close"
@@ -35,14 +65,20 @@ const (
 
 // Defines values for UserRole.
 const (
-	UserRoleEmployee  UserRole = "employee"
-	UserRoleModerator UserRole = "moderator"
+	UserRoleAuditor         UserRole = "auditor"
+	UserRoleEmployee        UserRole = "employee"
+	UserRoleModerator       UserRole = "moderator"
+	UserRoleRegionalManager UserRole = "regional_manager"
+	UserRoleSeniorEmployee  UserRole = "senior_employee"
 )
 
 // Defines values for PostDummyLoginJSONBodyRole.
 const (
-	PostDummyLoginJSONBodyRoleEmployee  PostDummyLoginJSONBodyRole = "employee"
-	PostDummyLoginJSONBodyRoleModerator PostDummyLoginJSONBodyRole = "moderator"
+	PostDummyLoginJSONBodyRoleAuditor         PostDummyLoginJSONBodyRole = "auditor"
+	PostDummyLoginJSONBodyRoleEmployee        PostDummyLoginJSONBodyRole = "employee"
+	PostDummyLoginJSONBodyRoleModerator       PostDummyLoginJSONBodyRole = "moderator"
+	PostDummyLoginJSONBodyRoleRegionalManager PostDummyLoginJSONBodyRole = "regional_manager"
+	PostDummyLoginJSONBodyRoleSeniorEmployee  PostDummyLoginJSONBodyRole = "senior_employee"
 )
 
 // Defines values for PostProductsJSONBodyType.
@@ -52,17 +88,35 @@ const (
 	PostProductsJSONBodyTypeЭлектроника PostProductsJSONBodyType = "электроника"
 )
 
+// Defines values for PostReceptionsJSONBodyKind.
+const (
+	PostReceptionsJSONBodyKindRegular  PostReceptionsJSONBodyKind = "regular"
+	PostReceptionsJSONBodyKindTransfer PostReceptionsJSONBodyKind = "transfer"
+)
+
 // Defines values for PostRegisterJSONBodyRole.
 const (
-	Employee  PostRegisterJSONBodyRole = "employee"
-	Moderator PostRegisterJSONBodyRole = "moderator"
+	Auditor         PostRegisterJSONBodyRole = "auditor"
+	Employee        PostRegisterJSONBodyRole = "employee"
+	Moderator       PostRegisterJSONBodyRole = "moderator"
+	RegionalManager PostRegisterJSONBodyRole = "regional_manager"
+	SeniorEmployee  PostRegisterJSONBodyRole = "senior_employee"
 )
 
 // Error defines model for Error.
 type Error struct {
+	// Code Стабильный машинно-читаемый код ошибки, например `point_not_found`
+	Code    string `json:"code"`
 	Message string `json:"message"`
 }
 
+// MFAPending Вход требует второго фактора, `mfa_token` обменивается на токены в POST /login/mfa
+type MFAPending struct {
+	EnrollmentRequired bool      `json:"enrollment_required"`
+	ExpiresAt          time.Time `json:"expires_at"`
+	MfaToken           string    `json:"mfa_token"`
+}
+
 // PVZ defines model for PVZ.
 type PVZ struct {
 	City             PVZCity             `json:"city"`
@@ -73,31 +127,80 @@ type PVZ struct {
 // PVZCity defines model for PVZ.City.
 type PVZCity string
 
+// PVZWithReceptions defines model for PVZWithReceptions.
+type PVZWithReceptions struct {
+	Pvz        PVZ                     `json:"pvz"`
+	Receptions []ReceptionWithProducts `json:"receptions"`
+}
+
 // Product defines model for Product.
 type Product struct {
+	Barcode     *string             `json:"barcode,omitempty"`
+	CellId      *openapi_types.UUID `json:"cellId,omitempty"`
 	DateTime    *time.Time          `json:"dateTime,omitempty"`
 	Id          *openapi_types.UUID `json:"id,omitempty"`
 	ReceptionId openapi_types.UUID  `json:"receptionId"`
+	Status      *ProductStatus      `json:"status,omitempty"`
 	Type        ProductType         `json:"type"`
 }
 
 // ProductType defines model for Product.Type.
 type ProductType string
 
+// ProductHistoryRecord Переход товара между статусами или ячейками
+type ProductHistoryRecord struct {
+	ActorId    openapi_types.UUID  `json:"actorId"`
+	DateTime   time.Time           `json:"dateTime"`
+	FromCellId *openapi_types.UUID `json:"fromCellId,omitempty"`
+	FromStatus *ProductStatus      `json:"fromStatus,omitempty"`
+	Id         openapi_types.UUID  `json:"id"`
+	Reason     *string             `json:"reason,omitempty"`
+	ToCellId   *openapi_types.UUID `json:"toCellId,omitempty"`
+	ToStatus   ProductStatus       `json:"toStatus"`
+}
+
+// ProductStatus defines model for ProductStatus.
+type ProductStatus string
+
+// ProductStatusChange defines model for ProductStatusChange.
+type ProductStatusChange struct {
+	// Reason Причина перехода, обязательна для возврата и списания
+	Reason *string `json:"reason,omitempty"`
+}
+
 // Reception defines model for Reception.
 type Reception struct {
 	DateTime time.Time           `json:"dateTime"`
 	Id       *openapi_types.UUID `json:"id,omitempty"`
+	Kind     *ReceptionKind      `json:"kind,omitempty"`
 	PvzId    openapi_types.UUID  `json:"pvzId"`
 	Status   ReceptionStatus     `json:"status"`
 }
 
+// ReceptionKind defines model for Reception.Kind.
+type ReceptionKind string
+
 // ReceptionStatus defines model for Reception.Status.
 type ReceptionStatus string
 
+// ReceptionWithProducts defines model for ReceptionWithProducts.
+type ReceptionWithProducts struct {
+	Products  []Product `json:"products"`
+	Reception Reception `json:"reception"`
+}
+
 // Token defines model for Token.
 type Token = string
 
+// TokenPair defines model for TokenPair.
+type TokenPair struct {
+	AccessToken string `json:"access_token"`
+
+	// ExpiresIn Время жизни access-токена в секундах
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
+
 // User defines model for User.
 type User struct {
 	Email openapi_types.Email `json:"email"`
@@ -108,6 +211,12 @@ type User struct {
 // UserRole defines model for User.Role.
 type UserRole string
 
+// IdempotencyKey defines model for IdempotencyKey.
+type IdempotencyKey = string
+
+// ProductId defines model for ProductId.
+type ProductId = openapi_types.UUID
+
 // PostDummyLoginJSONBody defines parameters for PostDummyLogin.
 type PostDummyLoginJSONBody struct {
 	Role PostDummyLoginJSONBodyRole `json:"role"`
@@ -124,8 +233,15 @@ type PostLoginJSONBody struct {
 
 // PostProductsJSONBody defines parameters for PostProducts.
 type PostProductsJSONBody struct {
-	PvzId openapi_types.UUID       `json:"pvzId"`
-	Type  PostProductsJSONBodyType `json:"type"`
+	Barcode *string                  `json:"barcode,omitempty"`
+	PvzId   openapi_types.UUID       `json:"pvzId"`
+	Type    PostProductsJSONBodyType `json:"type"`
+}
+
+// PostProductsParams defines parameters for PostProducts.
+type PostProductsParams struct {
+	// IdempotencyKey Ключ идемпотентности. Повтор запроса с тем же ключом возвращает сохраненный ответ первого запроса (с заголовком Idempotent-Replayed), не выполняя его повторно. Ключ с другим запросом дает 422.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
 }
 
 // PostProductsJSONBodyType defines parameters for PostProducts.
@@ -146,16 +262,37 @@ type GetPvzParams struct {
 	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
 }
 
+// PostPvzParams defines parameters for PostPvz.
+type PostPvzParams struct {
+	// IdempotencyKey Ключ идемпотентности. Повтор запроса с тем же ключом возвращает сохраненный ответ первого запроса (с заголовком Idempotent-Replayed), не выполняя его повторно. Ключ с другим запросом дает 422.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
 // PostReceptionsJSONBody defines parameters for PostReceptions.
 type PostReceptionsJSONBody struct {
-	PvzId openapi_types.UUID `json:"pvzId"`
+	Kind  *PostReceptionsJSONBodyKind `json:"kind,omitempty"`
+	PvzId openapi_types.UUID          `json:"pvzId"`
+}
+
+// PostReceptionsParams defines parameters for PostReceptions.
+type PostReceptionsParams struct {
+	// IdempotencyKey Ключ идемпотентности. Повтор запроса с тем же ключом возвращает сохраненный ответ первого запроса (с заголовком Idempotent-Replayed), не выполняя его повторно. Ключ с другим запросом дает 422.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
 }
 
+// PostReceptionsJSONBodyKind defines parameters for PostReceptions.
+type PostReceptionsJSONBodyKind string
+
 // PostRegisterJSONBody defines parameters for PostRegister.
 type PostRegisterJSONBody struct {
-	Email    openapi_types.Email      `json:"email"`
-	Password string                   `json:"password"`
-	Role     PostRegisterJSONBodyRole `json:"role"`
+	Email openapi_types.Email `json:"email"`
+
+	// InvitationCode Код приглашения, обязателен при закрытой регистрации
+	InvitationCode *string `json:"invitationCode,omitempty"`
+	Password       string  `json:"password"`
+
+	// Role Роль берется из приглашения; без приглашения поле запрещено, а открытая регистрация всегда создает сотрудника (employee)
+	Role *PostRegisterJSONBodyRole `json:"role,omitempty"`
 }
 
 // PostRegisterJSONBodyRole defines parameters for PostRegister.
@@ -170,6 +307,18 @@ type PostLoginJSONRequestBody PostLoginJSONBody
 // PostProductsJSONRequestBody defines body for PostProducts for application/json ContentType.
 type PostProductsJSONRequestBody PostProductsJSONBody
 
+// PostProductsProductIdIssueJSONRequestBody defines body for PostProductsProductIdIssue for application/json ContentType.
+type PostProductsProductIdIssueJSONRequestBody = ProductStatusChange
+
+// PostProductsProductIdReturnJSONRequestBody defines body for PostProductsProductIdReturn for application/json ContentType.
+type PostProductsProductIdReturnJSONRequestBody = ProductStatusChange
+
+// PostProductsProductIdStoreJSONRequestBody defines body for PostProductsProductIdStore for application/json ContentType.
+type PostProductsProductIdStoreJSONRequestBody = ProductStatusChange
+
+// PostProductsProductIdWriteOffJSONRequestBody defines body for PostProductsProductIdWriteOff for application/json ContentType.
+type PostProductsProductIdWriteOffJSONRequestBody = ProductStatusChange
+
 // PostPvzJSONRequestBody defines body for PostPvz for application/json ContentType.
 type PostPvzJSONRequestBody = PVZ
 
@@ -178,3 +327,1697 @@ type PostReceptionsJSONRequestBody PostReceptionsJSONBody
 
 // PostRegisterJSONRequestBody defines body for PostRegister for application/json ContentType.
 type PostRegisterJSONRequestBody PostRegisterJSONBody
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// Получение тестового токена
+	// (POST /dummyLogin)
+	PostDummyLogin(ctx echo.Context) error
+	// Авторизация пользователя
+	// (POST /login)
+	PostLogin(ctx echo.Context) error
+	// Добавление товара в текущую приемку (только для сотрудников ПВЗ)
+	// (POST /products)
+	PostProducts(ctx echo.Context, params PostProductsParams) error
+	// История статусов и ячеек товара, начиная с приемки
+	// (GET /products/{productId}/history)
+	GetProductsProductIdHistory(ctx echo.Context, productId ProductId) error
+	// Выдача товара получателю
+	// (POST /products/{productId}/issue)
+	PostProductsProductIdIssue(ctx echo.Context, productId ProductId) error
+	// Возврат товара (нужна причина)
+	// (POST /products/{productId}/return)
+	PostProductsProductIdReturn(ctx echo.Context, productId ProductId) error
+	// Размещение принятого товара на хранение
+	// (POST /products/{productId}/store)
+	PostProductsProductIdStore(ctx echo.Context, productId ProductId) error
+	// Списание товара (нужна причина)
+	// (POST /products/{productId}/write_off)
+	PostProductsProductIdWriteOff(ctx echo.Context, productId ProductId) error
+	// Получение списка ПВЗ с фильтрацией по дате приемки и пагинацией
+	// (GET /pvz)
+	GetPvz(ctx echo.Context, params GetPvzParams) error
+	// Создание ПВЗ (только для модераторов)
+	// (POST /pvz)
+	PostPvz(ctx echo.Context, params PostPvzParams) error
+	// Закрытие последней открытой приемки товаров в рамках ПВЗ
+	// (POST /pvz/{pvzId}/close_last_reception)
+	PostPvzPvzIdCloseLastReception(ctx echo.Context, pvzId openapi_types.UUID) error
+	// Удаление последнего добавленного товара из текущей приемки (LIFO, только для сотрудников ПВЗ)
+	// (POST /pvz/{pvzId}/delete_last_product)
+	PostPvzPvzIdDeleteLastProduct(ctx echo.Context, pvzId openapi_types.UUID) error
+	// Создание новой приемки товаров (только для сотрудников ПВЗ)
+	// (POST /receptions)
+	PostReceptions(ctx echo.Context, params PostReceptionsParams) error
+	// Регистрация пользователя
+	// (POST /register)
+	PostRegister(ctx echo.Context) error
+}
+
+// ServerInterfaceWrapper converts echo contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler ServerInterface
+}
+
+// PostDummyLogin converts echo context to params.
+func (w *ServerInterfaceWrapper) PostDummyLogin(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostDummyLogin(ctx)
+	return err
+}
+
+// PostLogin converts echo context to params.
+func (w *ServerInterfaceWrapper) PostLogin(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostLogin(ctx)
+	return err
+}
+
+// PostProducts converts echo context to params.
+func (w *ServerInterfaceWrapper) PostProducts(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostProductsParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKey
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for Idempotency-Key, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Idempotency-Key", valueList[0], &IdempotencyKey, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter Idempotency-Key: %s", err))
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostProducts(ctx, params)
+	return err
+}
+
+// GetProductsProductIdHistory converts echo context to params.
+func (w *ServerInterfaceWrapper) GetProductsProductIdHistory(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "productId" -------------
+	var productId ProductId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "productId", ctx.Param("productId"), &productId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter productId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetProductsProductIdHistory(ctx, productId)
+	return err
+}
+
+// PostProductsProductIdIssue converts echo context to params.
+func (w *ServerInterfaceWrapper) PostProductsProductIdIssue(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "productId" -------------
+	var productId ProductId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "productId", ctx.Param("productId"), &productId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter productId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostProductsProductIdIssue(ctx, productId)
+	return err
+}
+
+// PostProductsProductIdReturn converts echo context to params.
+func (w *ServerInterfaceWrapper) PostProductsProductIdReturn(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "productId" -------------
+	var productId ProductId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "productId", ctx.Param("productId"), &productId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter productId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostProductsProductIdReturn(ctx, productId)
+	return err
+}
+
+// PostProductsProductIdStore converts echo context to params.
+func (w *ServerInterfaceWrapper) PostProductsProductIdStore(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "productId" -------------
+	var productId ProductId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "productId", ctx.Param("productId"), &productId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter productId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostProductsProductIdStore(ctx, productId)
+	return err
+}
+
+// PostProductsProductIdWriteOff converts echo context to params.
+func (w *ServerInterfaceWrapper) PostProductsProductIdWriteOff(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "productId" -------------
+	var productId ProductId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "productId", ctx.Param("productId"), &productId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter productId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostProductsProductIdWriteOff(ctx, productId)
+	return err
+}
+
+// GetPvz converts echo context to params.
+func (w *ServerInterfaceWrapper) GetPvz(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetPvzParams
+	// ------------- Optional query parameter "startDate" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "startDate", ctx.QueryParams(), &params.StartDate)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter startDate: %s", err))
+	}
+
+	// ------------- Optional query parameter "endDate" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "endDate", ctx.QueryParams(), &params.EndDate)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter endDate: %s", err))
+	}
+
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetPvz(ctx, params)
+	return err
+}
+
+// PostPvz converts echo context to params.
+func (w *ServerInterfaceWrapper) PostPvz(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostPvzParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKey
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for Idempotency-Key, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Idempotency-Key", valueList[0], &IdempotencyKey, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter Idempotency-Key: %s", err))
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostPvz(ctx, params)
+	return err
+}
+
+// PostPvzPvzIdCloseLastReception converts echo context to params.
+func (w *ServerInterfaceWrapper) PostPvzPvzIdCloseLastReception(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "pvzId" -------------
+	var pvzId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "pvzId", ctx.Param("pvzId"), &pvzId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter pvzId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostPvzPvzIdCloseLastReception(ctx, pvzId)
+	return err
+}
+
+// PostPvzPvzIdDeleteLastProduct converts echo context to params.
+func (w *ServerInterfaceWrapper) PostPvzPvzIdDeleteLastProduct(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "pvzId" -------------
+	var pvzId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "pvzId", ctx.Param("pvzId"), &pvzId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter pvzId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostPvzPvzIdDeleteLastProduct(ctx, pvzId)
+	return err
+}
+
+// PostReceptions converts echo context to params.
+func (w *ServerInterfaceWrapper) PostReceptions(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostReceptionsParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKey
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for Idempotency-Key, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Idempotency-Key", valueList[0], &IdempotencyKey, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter Idempotency-Key: %s", err))
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostReceptions(ctx, params)
+	return err
+}
+
+// PostRegister converts echo context to params.
+func (w *ServerInterfaceWrapper) PostRegister(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostRegister(ctx)
+	return err
+}
+
+// This is a simple interface which specifies echo.Route addition functions which
+// are present on both echo.Echo and echo.Group, since we want to allow using
+// either of them for path registration
+type EchoRouter interface {
+	CONNECT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	DELETE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	GET(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	HEAD(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	OPTIONS(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	PATCH(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	POST(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	PUT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	TRACE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+}
+
+// RegisterHandlers adds each server route to the EchoRouter.
+func RegisterHandlers(router EchoRouter, si ServerInterface) {
+	RegisterHandlersWithBaseURL(router, si, "")
+}
+
+// Registers handlers, and prepends BaseURL to the paths, so that the paths
+// can be served under a prefix.
+func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL string) {
+
+	wrapper := ServerInterfaceWrapper{
+		Handler: si,
+	}
+
+	router.POST(baseURL+"/dummyLogin", wrapper.PostDummyLogin)
+	router.POST(baseURL+"/login", wrapper.PostLogin)
+	router.POST(baseURL+"/products", wrapper.PostProducts)
+	router.GET(baseURL+"/products/:productId/history", wrapper.GetProductsProductIdHistory)
+	router.POST(baseURL+"/products/:productId/issue", wrapper.PostProductsProductIdIssue)
+	router.POST(baseURL+"/products/:productId/return", wrapper.PostProductsProductIdReturn)
+	router.POST(baseURL+"/products/:productId/store", wrapper.PostProductsProductIdStore)
+	router.POST(baseURL+"/products/:productId/write_off", wrapper.PostProductsProductIdWriteOff)
+	router.GET(baseURL+"/pvz", wrapper.GetPvz)
+	router.POST(baseURL+"/pvz", wrapper.PostPvz)
+	router.POST(baseURL+"/pvz/:pvzId/close_last_reception", wrapper.PostPvzPvzIdCloseLastReception)
+	router.POST(baseURL+"/pvz/:pvzId/delete_last_product", wrapper.PostPvzPvzIdDeleteLastProduct)
+	router.POST(baseURL+"/receptions", wrapper.PostReceptions)
+	router.POST(baseURL+"/register", wrapper.PostRegister)
+
+}
+
+type PostDummyLoginRequestObject struct {
+	Body *PostDummyLoginJSONRequestBody
+}
+
+type PostDummyLoginResponseObject interface {
+	VisitPostDummyLoginResponse(w http.ResponseWriter) error
+}
+
+type PostDummyLogin200JSONResponse Token
+
+func (response PostDummyLogin200JSONResponse) VisitPostDummyLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostDummyLogin400JSONResponse Error
+
+func (response PostDummyLogin400JSONResponse) VisitPostDummyLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostDummyLogindefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response PostDummyLogindefaultJSONResponse) VisitPostDummyLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type PostLoginRequestObject struct {
+	Body *PostLoginJSONRequestBody
+}
+
+type PostLoginResponseObject interface {
+	VisitPostLoginResponse(w http.ResponseWriter) error
+}
+
+type PostLogin200JSONResponse TokenPair
+
+func (response PostLogin200JSONResponse) VisitPostLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostLogin202JSONResponse MFAPending
+
+func (response PostLogin202JSONResponse) VisitPostLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(202)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostLogin401JSONResponse Error
+
+func (response PostLogin401JSONResponse) VisitPostLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostLogindefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response PostLogindefaultJSONResponse) VisitPostLoginResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type PostProductsRequestObject struct {
+	Params PostProductsParams
+	Body   *PostProductsJSONRequestBody
+}
+
+type PostProductsResponseObject interface {
+	VisitPostProductsResponse(w http.ResponseWriter) error
+}
+
+type PostProducts201JSONResponse Product
+
+func (response PostProducts201JSONResponse) VisitPostProductsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostProducts400JSONResponse Error
+
+func (response PostProducts400JSONResponse) VisitPostProductsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostProducts403JSONResponse Error
+
+func (response PostProducts403JSONResponse) VisitPostProductsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostProducts422JSONResponse Error
+
+func (response PostProducts422JSONResponse) VisitPostProductsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(422)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostProductsdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response PostProductsdefaultJSONResponse) VisitPostProductsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetProductsProductIdHistoryRequestObject struct {
+	ProductId ProductId `json:"productId"`
+}
+
+type GetProductsProductIdHistoryResponseObject interface {
+	VisitGetProductsProductIdHistoryResponse(w http.ResponseWriter) error
+}
+
+type GetProductsProductIdHistory200JSONResponse []ProductHistoryRecord
+
+func (response GetProductsProductIdHistory200JSONResponse) VisitGetProductsProductIdHistoryResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetProductsProductIdHistory403JSONResponse Error
+
+func (response GetProductsProductIdHistory403JSONResponse) VisitGetProductsProductIdHistoryResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetProductsProductIdHistorydefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response GetProductsProductIdHistorydefaultJSONResponse) VisitGetProductsProductIdHistoryResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type PostProductsProductIdIssueRequestObject struct {
+	ProductId ProductId `json:"productId"`
+	Body      *PostProductsProductIdIssueJSONRequestBody
+}
+
+type PostProductsProductIdIssueResponseObject interface {
+	VisitPostProductsProductIdIssueResponse(w http.ResponseWriter) error
+}
+
+type PostProductsProductIdIssue200JSONResponse Product
+
+func (response PostProductsProductIdIssue200JSONResponse) VisitPostProductsProductIdIssueResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostProductsProductIdIssue400JSONResponse Error
+
+func (response PostProductsProductIdIssue400JSONResponse) VisitPostProductsProductIdIssueResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostProductsProductIdIssue403JSONResponse Error
+
+func (response PostProductsProductIdIssue403JSONResponse) VisitPostProductsProductIdIssueResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostProductsProductIdIssue404JSONResponse Error
+
+func (response PostProductsProductIdIssue404JSONResponse) VisitPostProductsProductIdIssueResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostProductsProductIdIssue409JSONResponse Error
+
+func (response PostProductsProductIdIssue409JSONResponse) VisitPostProductsProductIdIssueResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostProductsProductIdIssuedefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response PostProductsProductIdIssuedefaultJSONResponse) VisitPostProductsProductIdIssueResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type PostProductsProductIdReturnRequestObject struct {
+	ProductId ProductId `json:"productId"`
+	Body      *PostProductsProductIdReturnJSONRequestBody
+}
+
+type PostProductsProductIdReturnResponseObject interface {
+	VisitPostProductsProductIdReturnResponse(w http.ResponseWriter) error
+}
+
+type PostProductsProductIdReturn200JSONResponse Product
+
+func (response PostProductsProductIdReturn200JSONResponse) VisitPostProductsProductIdReturnResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostProductsProductIdReturn400JSONResponse Error
+
+func (response PostProductsProductIdReturn400JSONResponse) VisitPostProductsProductIdReturnResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostProductsProductIdReturn403JSONResponse Error
+
+func (response PostProductsProductIdReturn403JSONResponse) VisitPostProductsProductIdReturnResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostProductsProductIdReturn404JSONResponse Error
+
+func (response PostProductsProductIdReturn404JSONResponse) VisitPostProductsProductIdReturnResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostProductsProductIdReturn409JSONResponse Error
+
+func (response PostProductsProductIdReturn409JSONResponse) VisitPostProductsProductIdReturnResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostProductsProductIdReturndefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response PostProductsProductIdReturndefaultJSONResponse) VisitPostProductsProductIdReturnResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type PostProductsProductIdStoreRequestObject struct {
+	ProductId ProductId `json:"productId"`
+	Body      *PostProductsProductIdStoreJSONRequestBody
+}
+
+type PostProductsProductIdStoreResponseObject interface {
+	VisitPostProductsProductIdStoreResponse(w http.ResponseWriter) error
+}
+
+type PostProductsProductIdStore200JSONResponse Product
+
+func (response PostProductsProductIdStore200JSONResponse) VisitPostProductsProductIdStoreResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostProductsProductIdStore400JSONResponse Error
+
+func (response PostProductsProductIdStore400JSONResponse) VisitPostProductsProductIdStoreResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostProductsProductIdStore403JSONResponse Error
+
+func (response PostProductsProductIdStore403JSONResponse) VisitPostProductsProductIdStoreResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostProductsProductIdStore404JSONResponse Error
+
+func (response PostProductsProductIdStore404JSONResponse) VisitPostProductsProductIdStoreResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostProductsProductIdStore409JSONResponse Error
+
+func (response PostProductsProductIdStore409JSONResponse) VisitPostProductsProductIdStoreResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostProductsProductIdStoredefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response PostProductsProductIdStoredefaultJSONResponse) VisitPostProductsProductIdStoreResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type PostProductsProductIdWriteOffRequestObject struct {
+	ProductId ProductId `json:"productId"`
+	Body      *PostProductsProductIdWriteOffJSONRequestBody
+}
+
+type PostProductsProductIdWriteOffResponseObject interface {
+	VisitPostProductsProductIdWriteOffResponse(w http.ResponseWriter) error
+}
+
+type PostProductsProductIdWriteOff200JSONResponse Product
+
+func (response PostProductsProductIdWriteOff200JSONResponse) VisitPostProductsProductIdWriteOffResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostProductsProductIdWriteOff400JSONResponse Error
+
+func (response PostProductsProductIdWriteOff400JSONResponse) VisitPostProductsProductIdWriteOffResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostProductsProductIdWriteOff403JSONResponse Error
+
+func (response PostProductsProductIdWriteOff403JSONResponse) VisitPostProductsProductIdWriteOffResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostProductsProductIdWriteOff404JSONResponse Error
+
+func (response PostProductsProductIdWriteOff404JSONResponse) VisitPostProductsProductIdWriteOffResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostProductsProductIdWriteOff409JSONResponse Error
+
+func (response PostProductsProductIdWriteOff409JSONResponse) VisitPostProductsProductIdWriteOffResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostProductsProductIdWriteOffdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response PostProductsProductIdWriteOffdefaultJSONResponse) VisitPostProductsProductIdWriteOffResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetPvzRequestObject struct {
+	Params GetPvzParams
+}
+
+type GetPvzResponseObject interface {
+	VisitGetPvzResponse(w http.ResponseWriter) error
+}
+
+type GetPvz200JSONResponse []PVZWithReceptions
+
+func (response GetPvz200JSONResponse) VisitGetPvzResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetPvzdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response GetPvzdefaultJSONResponse) VisitGetPvzResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type PostPvzRequestObject struct {
+	Params PostPvzParams
+	Body   *PostPvzJSONRequestBody
+}
+
+type PostPvzResponseObject interface {
+	VisitPostPvzResponse(w http.ResponseWriter) error
+}
+
+type PostPvz201JSONResponse PVZ
+
+func (response PostPvz201JSONResponse) VisitPostPvzResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostPvz400JSONResponse Error
+
+func (response PostPvz400JSONResponse) VisitPostPvzResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostPvz403JSONResponse Error
+
+func (response PostPvz403JSONResponse) VisitPostPvzResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostPvz422JSONResponse Error
+
+func (response PostPvz422JSONResponse) VisitPostPvzResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(422)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostPvzdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response PostPvzdefaultJSONResponse) VisitPostPvzResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type PostPvzPvzIdCloseLastReceptionRequestObject struct {
+	PvzId openapi_types.UUID `json:"pvzId"`
+}
+
+type PostPvzPvzIdCloseLastReceptionResponseObject interface {
+	VisitPostPvzPvzIdCloseLastReceptionResponse(w http.ResponseWriter) error
+}
+
+type PostPvzPvzIdCloseLastReception200JSONResponse Reception
+
+func (response PostPvzPvzIdCloseLastReception200JSONResponse) VisitPostPvzPvzIdCloseLastReceptionResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostPvzPvzIdCloseLastReception400JSONResponse Error
+
+func (response PostPvzPvzIdCloseLastReception400JSONResponse) VisitPostPvzPvzIdCloseLastReceptionResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostPvzPvzIdCloseLastReception403JSONResponse Error
+
+func (response PostPvzPvzIdCloseLastReception403JSONResponse) VisitPostPvzPvzIdCloseLastReceptionResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostPvzPvzIdCloseLastReceptiondefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response PostPvzPvzIdCloseLastReceptiondefaultJSONResponse) VisitPostPvzPvzIdCloseLastReceptionResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type PostPvzPvzIdDeleteLastProductRequestObject struct {
+	PvzId openapi_types.UUID `json:"pvzId"`
+}
+
+type PostPvzPvzIdDeleteLastProductResponseObject interface {
+	VisitPostPvzPvzIdDeleteLastProductResponse(w http.ResponseWriter) error
+}
+
+type PostPvzPvzIdDeleteLastProduct200Response struct {
+}
+
+func (response PostPvzPvzIdDeleteLastProduct200Response) VisitPostPvzPvzIdDeleteLastProductResponse(w http.ResponseWriter) error {
+	w.WriteHeader(200)
+	return nil
+}
+
+type PostPvzPvzIdDeleteLastProduct400JSONResponse Error
+
+func (response PostPvzPvzIdDeleteLastProduct400JSONResponse) VisitPostPvzPvzIdDeleteLastProductResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostPvzPvzIdDeleteLastProduct403JSONResponse Error
+
+func (response PostPvzPvzIdDeleteLastProduct403JSONResponse) VisitPostPvzPvzIdDeleteLastProductResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostPvzPvzIdDeleteLastProductdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response PostPvzPvzIdDeleteLastProductdefaultJSONResponse) VisitPostPvzPvzIdDeleteLastProductResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type PostReceptionsRequestObject struct {
+	Params PostReceptionsParams
+	Body   *PostReceptionsJSONRequestBody
+}
+
+type PostReceptionsResponseObject interface {
+	VisitPostReceptionsResponse(w http.ResponseWriter) error
+}
+
+type PostReceptions201JSONResponse Reception
+
+func (response PostReceptions201JSONResponse) VisitPostReceptionsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostReceptions400JSONResponse Error
+
+func (response PostReceptions400JSONResponse) VisitPostReceptionsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostReceptions403JSONResponse Error
+
+func (response PostReceptions403JSONResponse) VisitPostReceptionsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostReceptions422JSONResponse Error
+
+func (response PostReceptions422JSONResponse) VisitPostReceptionsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(422)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostReceptionsdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response PostReceptionsdefaultJSONResponse) VisitPostReceptionsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type PostRegisterRequestObject struct {
+	Body *PostRegisterJSONRequestBody
+}
+
+type PostRegisterResponseObject interface {
+	VisitPostRegisterResponse(w http.ResponseWriter) error
+}
+
+type PostRegister201JSONResponse User
+
+func (response PostRegister201JSONResponse) VisitPostRegisterResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRegister400JSONResponse Error
+
+func (response PostRegister400JSONResponse) VisitPostRegisterResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRegisterdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response PostRegisterdefaultJSONResponse) VisitPostRegisterResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+// StrictServerInterface represents all server handlers.
+type StrictServerInterface interface {
+	// Получение тестового токена
+	// (POST /dummyLogin)
+	PostDummyLogin(ctx context.Context, request PostDummyLoginRequestObject) (PostDummyLoginResponseObject, error)
+	// Авторизация пользователя
+	// (POST /login)
+	PostLogin(ctx context.Context, request PostLoginRequestObject) (PostLoginResponseObject, error)
+	// Добавление товара в текущую приемку (только для сотрудников ПВЗ)
+	// (POST /products)
+	PostProducts(ctx context.Context, request PostProductsRequestObject) (PostProductsResponseObject, error)
+	// История статусов и ячеек товара, начиная с приемки
+	// (GET /products/{productId}/history)
+	GetProductsProductIdHistory(ctx context.Context, request GetProductsProductIdHistoryRequestObject) (GetProductsProductIdHistoryResponseObject, error)
+	// Выдача товара получателю
+	// (POST /products/{productId}/issue)
+	PostProductsProductIdIssue(ctx context.Context, request PostProductsProductIdIssueRequestObject) (PostProductsProductIdIssueResponseObject, error)
+	// Возврат товара (нужна причина)
+	// (POST /products/{productId}/return)
+	PostProductsProductIdReturn(ctx context.Context, request PostProductsProductIdReturnRequestObject) (PostProductsProductIdReturnResponseObject, error)
+	// Размещение принятого товара на хранение
+	// (POST /products/{productId}/store)
+	PostProductsProductIdStore(ctx context.Context, request PostProductsProductIdStoreRequestObject) (PostProductsProductIdStoreResponseObject, error)
+	// Списание товара (нужна причина)
+	// (POST /products/{productId}/write_off)
+	PostProductsProductIdWriteOff(ctx context.Context, request PostProductsProductIdWriteOffRequestObject) (PostProductsProductIdWriteOffResponseObject, error)
+	// Получение списка ПВЗ с фильтрацией по дате приемки и пагинацией
+	// (GET /pvz)
+	GetPvz(ctx context.Context, request GetPvzRequestObject) (GetPvzResponseObject, error)
+	// Создание ПВЗ (только для модераторов)
+	// (POST /pvz)
+	PostPvz(ctx context.Context, request PostPvzRequestObject) (PostPvzResponseObject, error)
+	// Закрытие последней открытой приемки товаров в рамках ПВЗ
+	// (POST /pvz/{pvzId}/close_last_reception)
+	PostPvzPvzIdCloseLastReception(ctx context.Context, request PostPvzPvzIdCloseLastReceptionRequestObject) (PostPvzPvzIdCloseLastReceptionResponseObject, error)
+	// Удаление последнего добавленного товара из текущей приемки (LIFO, только для сотрудников ПВЗ)
+	// (POST /pvz/{pvzId}/delete_last_product)
+	PostPvzPvzIdDeleteLastProduct(ctx context.Context, request PostPvzPvzIdDeleteLastProductRequestObject) (PostPvzPvzIdDeleteLastProductResponseObject, error)
+	// Создание новой приемки товаров (только для сотрудников ПВЗ)
+	// (POST /receptions)
+	PostReceptions(ctx context.Context, request PostReceptionsRequestObject) (PostReceptionsResponseObject, error)
+	// Регистрация пользователя
+	// (POST /register)
+	PostRegister(ctx context.Context, request PostRegisterRequestObject) (PostRegisterResponseObject, error)
+}
+
+type StrictHandlerFunc = strictecho.StrictEchoHandlerFunc
+type StrictMiddlewareFunc = strictecho.StrictEchoMiddlewareFunc
+
+func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares}
+}
+
+type strictHandler struct {
+	ssi         StrictServerInterface
+	middlewares []StrictMiddlewareFunc
+}
+
+// PostDummyLogin operation middleware
+func (sh *strictHandler) PostDummyLogin(ctx echo.Context) error {
+	var request PostDummyLoginRequestObject
+
+	var body PostDummyLoginJSONRequestBody
+	if err := ctx.Bind(&body); err != nil {
+		return err
+	}
+	request.Body = &body
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.PostDummyLogin(ctx.Request().Context(), request.(PostDummyLoginRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostDummyLogin")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(PostDummyLoginResponseObject); ok {
+		return validResponse.VisitPostDummyLoginResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// PostLogin operation middleware
+func (sh *strictHandler) PostLogin(ctx echo.Context) error {
+	var request PostLoginRequestObject
+
+	var body PostLoginJSONRequestBody
+	if err := ctx.Bind(&body); err != nil {
+		return err
+	}
+	request.Body = &body
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.PostLogin(ctx.Request().Context(), request.(PostLoginRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostLogin")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(PostLoginResponseObject); ok {
+		return validResponse.VisitPostLoginResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// PostProducts operation middleware
+func (sh *strictHandler) PostProducts(ctx echo.Context, params PostProductsParams) error {
+	var request PostProductsRequestObject
+
+	request.Params = params
+
+	var body PostProductsJSONRequestBody
+	if err := ctx.Bind(&body); err != nil {
+		return err
+	}
+	request.Body = &body
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.PostProducts(ctx.Request().Context(), request.(PostProductsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostProducts")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(PostProductsResponseObject); ok {
+		return validResponse.VisitPostProductsResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// GetProductsProductIdHistory operation middleware
+func (sh *strictHandler) GetProductsProductIdHistory(ctx echo.Context, productId ProductId) error {
+	var request GetProductsProductIdHistoryRequestObject
+
+	request.ProductId = productId
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.GetProductsProductIdHistory(ctx.Request().Context(), request.(GetProductsProductIdHistoryRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetProductsProductIdHistory")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(GetProductsProductIdHistoryResponseObject); ok {
+		return validResponse.VisitGetProductsProductIdHistoryResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// PostProductsProductIdIssue operation middleware
+func (sh *strictHandler) PostProductsProductIdIssue(ctx echo.Context, productId ProductId) error {
+	var request PostProductsProductIdIssueRequestObject
+
+	request.ProductId = productId
+
+	var body PostProductsProductIdIssueJSONRequestBody
+	if err := ctx.Bind(&body); err != nil {
+		return err
+	}
+	request.Body = &body
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.PostProductsProductIdIssue(ctx.Request().Context(), request.(PostProductsProductIdIssueRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostProductsProductIdIssue")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(PostProductsProductIdIssueResponseObject); ok {
+		return validResponse.VisitPostProductsProductIdIssueResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// PostProductsProductIdReturn operation middleware
+func (sh *strictHandler) PostProductsProductIdReturn(ctx echo.Context, productId ProductId) error {
+	var request PostProductsProductIdReturnRequestObject
+
+	request.ProductId = productId
+
+	var body PostProductsProductIdReturnJSONRequestBody
+	if err := ctx.Bind(&body); err != nil {
+		return err
+	}
+	request.Body = &body
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.PostProductsProductIdReturn(ctx.Request().Context(), request.(PostProductsProductIdReturnRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostProductsProductIdReturn")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(PostProductsProductIdReturnResponseObject); ok {
+		return validResponse.VisitPostProductsProductIdReturnResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// PostProductsProductIdStore operation middleware
+func (sh *strictHandler) PostProductsProductIdStore(ctx echo.Context, productId ProductId) error {
+	var request PostProductsProductIdStoreRequestObject
+
+	request.ProductId = productId
+
+	var body PostProductsProductIdStoreJSONRequestBody
+	if err := ctx.Bind(&body); err != nil {
+		return err
+	}
+	request.Body = &body
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.PostProductsProductIdStore(ctx.Request().Context(), request.(PostProductsProductIdStoreRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostProductsProductIdStore")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(PostProductsProductIdStoreResponseObject); ok {
+		return validResponse.VisitPostProductsProductIdStoreResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// PostProductsProductIdWriteOff operation middleware
+func (sh *strictHandler) PostProductsProductIdWriteOff(ctx echo.Context, productId ProductId) error {
+	var request PostProductsProductIdWriteOffRequestObject
+
+	request.ProductId = productId
+
+	var body PostProductsProductIdWriteOffJSONRequestBody
+	if err := ctx.Bind(&body); err != nil {
+		return err
+	}
+	request.Body = &body
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.PostProductsProductIdWriteOff(ctx.Request().Context(), request.(PostProductsProductIdWriteOffRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostProductsProductIdWriteOff")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(PostProductsProductIdWriteOffResponseObject); ok {
+		return validResponse.VisitPostProductsProductIdWriteOffResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// GetPvz operation middleware
+func (sh *strictHandler) GetPvz(ctx echo.Context, params GetPvzParams) error {
+	var request GetPvzRequestObject
+
+	request.Params = params
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.GetPvz(ctx.Request().Context(), request.(GetPvzRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetPvz")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(GetPvzResponseObject); ok {
+		return validResponse.VisitGetPvzResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// PostPvz operation middleware
+func (sh *strictHandler) PostPvz(ctx echo.Context, params PostPvzParams) error {
+	var request PostPvzRequestObject
+
+	request.Params = params
+
+	var body PostPvzJSONRequestBody
+	if err := ctx.Bind(&body); err != nil {
+		return err
+	}
+	request.Body = &body
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.PostPvz(ctx.Request().Context(), request.(PostPvzRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostPvz")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(PostPvzResponseObject); ok {
+		return validResponse.VisitPostPvzResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// PostPvzPvzIdCloseLastReception operation middleware
+func (sh *strictHandler) PostPvzPvzIdCloseLastReception(ctx echo.Context, pvzId openapi_types.UUID) error {
+	var request PostPvzPvzIdCloseLastReceptionRequestObject
+
+	request.PvzId = pvzId
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.PostPvzPvzIdCloseLastReception(ctx.Request().Context(), request.(PostPvzPvzIdCloseLastReceptionRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostPvzPvzIdCloseLastReception")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(PostPvzPvzIdCloseLastReceptionResponseObject); ok {
+		return validResponse.VisitPostPvzPvzIdCloseLastReceptionResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// PostPvzPvzIdDeleteLastProduct operation middleware
+func (sh *strictHandler) PostPvzPvzIdDeleteLastProduct(ctx echo.Context, pvzId openapi_types.UUID) error {
+	var request PostPvzPvzIdDeleteLastProductRequestObject
+
+	request.PvzId = pvzId
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.PostPvzPvzIdDeleteLastProduct(ctx.Request().Context(), request.(PostPvzPvzIdDeleteLastProductRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostPvzPvzIdDeleteLastProduct")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(PostPvzPvzIdDeleteLastProductResponseObject); ok {
+		return validResponse.VisitPostPvzPvzIdDeleteLastProductResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// PostReceptions operation middleware
+func (sh *strictHandler) PostReceptions(ctx echo.Context, params PostReceptionsParams) error {
+	var request PostReceptionsRequestObject
+
+	request.Params = params
+
+	var body PostReceptionsJSONRequestBody
+	if err := ctx.Bind(&body); err != nil {
+		return err
+	}
+	request.Body = &body
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.PostReceptions(ctx.Request().Context(), request.(PostReceptionsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostReceptions")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(PostReceptionsResponseObject); ok {
+		return validResponse.VisitPostReceptionsResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// PostRegister operation middleware
+func (sh *strictHandler) PostRegister(ctx echo.Context) error {
+	var request PostRegisterRequestObject
+
+	var body PostRegisterJSONRequestBody
+	if err := ctx.Bind(&body); err != nil {
+		return err
+	}
+	request.Body = &body
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.PostRegister(ctx.Request().Context(), request.(PostRegisterRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostRegister")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(PostRegisterResponseObject); ok {
+		return validResponse.VisitPostRegisterResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// Base64 encoded, gzipped, json marshaled Swagger object
+var swaggerSpec = []string{
+
+	"H4sIAAAAAAAC/+xcbW8bx/H/Kof7/184wNmUFSdA1Vep07RuXUSw3QRIKsgXcildwnvI3lIJYxCQyCRO",
+	"IDcqggApgqaJmy9AU7yYpkTqK8x+o2Jm75F3pEhJlmmUr2ze087MzvzmYWf0QC+7tuc6zBG+vvZA90xu",
+	"2kwwTr9uVZjtuYI55cafWQOvVJhf5pYnLNfR13T4AY7kN/KhBn3oQQDHcAIj2YIAhrIFQxjJPdmC/jUN",
+	"foIRdGULRnJXg6fQgRO5i7eho8k9jV451uBXCDQYqG/CCK90YQRPoSt3oSO/hg4EsqXJPRjJL/ASDHEp",
+	"GMp9eKbRyl16Ak4gkLv08iGMxhe8IvfUpUMYwRESBgNaLWZXXL3DvJrZYJVXDA0X0aAr95E5OIKhPJAH",
+	"GgTq0ycJY8jwNS2WCa7Sk7uyDYfQh2O1ZEQFMdcLGbqxunrtb45u6BYKdZuZFcZ1Q3dMm+lr6U24irtg",
+	"6H55m9kmbodtfnqbOVtiW19bfe01Q7ctJ/p93dBFw8MP+IJbzpbebBr6Oncr9bK4VcGXaTXPFNvJWl58",
+	"39A5+7hucVbR1wSvs/SqVZfbptDX9Hrdquj5dZrRw6REv+fc5fgfs1KxUHHM2jp3PcaFxXx9rWrWfGbo",
+	"XuoSKmWFFajbY9mCDjyBPhzJR9G2H0NHfgV91AMYXZUPoU9PBXCs7uPe9lA78KEnMIA+7anaiz4co6po",
+	"9z3XcsSm44rNqlt3KvfzbBm6zXzf3CLC8qJNxPV+/KChGNmIv+V+8CErC/zWX956Y505FXw7z+e38gsi",
+	"WrbkLgTwRLaVWkeKptRafg4dGISXOoZ2366am8L9iDn3NRjBE2QNhtCHrtIzuYd6O0STa8EIBnhX7mvQ",
+	"1dbfvntPK9XcLcsp2VVTH98O5nC3VrOZIzYTNmMhfOC6NWY6yBX71LM48zdNkdGTiinYVWHZrFCqEdUz",
+	"yDV+NLOUUUhgkdTX33mPUC6rbJZoKC7rNq4C/yITHaDcdEOHxwQ0A9m6Cj+hHAlbnsi23IVDvP8DdMi2",
+	"h/JRatGEQasyg9Ego1uWL7iJSvCmKdisEhyTEXEzgfd3LbF9h5UZKZqfl4S38xn+8/+cVfU1/f9KiW8o",
+	"hQZdQgnSiumvWILZ/mlvxgsjFSEQ+XozptTk3Gzk2EGSMssVsqa+lmfoA5NHUJKTd5nVardm2xqU/j0U",
+	"/sxKPfOeh3zNSIgvTFE/VdKhOO6qh2MJJxou/w5HEKBKE5ggRgyUro/Ij/8KvegnYk+3ULHHNoruZjma",
+	"slV/tHzh8sYdVnZ5pQABfyIjC2IcJC/dQZzTCNZ+hZ5saxRedGRLttGxwzH0MRI5gr4mD+RDCOAZDNT1",
+	"HKSZZeHy57b9Ve7aN2fXL3z87tm2dmZFM33XKbQD4c5BqXDPROeYrqhvR58y4s1IiXqK7iQURPqMSmft",
+	"MPwAqhX9x/L9OlNxjKhzh/77CbeEYM6mW63iE86m4KbjW6IQtjOr3dw2HeX5s2qUyHVcgTG4wGCEHC6c",
+	"pPUZnTWZ1gH6DQp+KZ7BB3twhF46Hfe28Hofw94T6JOiD6EvD3QjHf5dX1m9UWSiOSHGMJxn5rnB3EeW",
+	"U8nu11a9ZnJ8FDegynjhDng7n82Ni9ESlrPpcXeLMx8VrFxzfXY6hsUCiNaOv7wxTZIZh5Z3qqk7MznK",
+	"8FN515hC15mdbY7H5BNGQloRe/cmxGThnXXT4nlmzXKZ+f7EeC4J2yynMO7dpbj9AFPBPjxFRdfUJ68m",
+	"MSuaQxfNAT1YG4ZoUPIL3UjUxHLE6zcSPbEcwbYYVwKscuZvzxpwZtgZfzvDTJEE/+qzAhEx27RqGa1W",
+	"V84RRbi1jHtntldzGwzV2GeO5fLN1BXbrTBuCpfrKuTEfGzTNh1zi5JOs16x8OapthIRTYvnuUejZOU6",
+	"t0TjLupkGI8xkzP+Rl1sJ7/eitj707v3ouSW0gq6m/C7LYSnkkvLqbpF2WGY9PflXoSjsk1ZXge6FPAQ",
+	"bmIx4lv4HiE1TAGxcIFZ4rN0oDGCLq5tiRoRY5Y/Yk5F8xnfscooxx3GfbXw9Wsr11ZwH1yPOaZn6Wv6",
+	"q3TJoOyaGC9V6rbduI0ZFv70XJ+iVdQLM4r/9HXXF28mzyl5M1/8zq00VFbsYHEC/2t6Xs0q06ulD0MP",
+	"lCToYz7qRajHJLVojhcW6ILvuY6vqF1dWZmL12koqCCMFh3TlV/ImwbyK4QT1IlOnFz3ySl/SS62aeg3",
+	"LpAeVQopoudHCKh2tRtVNVLFIgpBWdWs18QlUPLvuE7SUVZct22TN1RQPoIj2abIGnOGgAp3ci+0mrAq",
+	"kcJp+oCqK0zX+otV+DkQ1jN9/5MwBZkN7+I3FkK5yQufT8FXV1YvjKZUVauIqP+ky1mqHJWUtJ5lClrK",
+	"9K5fuukFmlJw2Qp/UpVW1ZiDhbHEfxRtpipGYybxNPRiKrM4UGaYjkMnW2IcxxqZs4D3i5lIHimNnRU0",
+	"Ny7KmlM1nFS+8/qNcyUMl1gRUUSdDS0uTv/jpKLILKOgB5UdC8dxvLQYDjAq6sBQlcEVSGBde0i4kQnj",
+	"+ormVy+B5u/UARcGmQm9gfw6ltzq6iVQMdsZHIbCdLzWl3vjOAHDUw+rXjDyhZkE4VA6h3h/o7mRAcbv",
+	"svobBSmp6mFXnTYOZFt+Ldvym4z2yLZ2RbZC6QxgFOcReOTYIvn0QnAYQTfMJF7J4mvpQXyK1ixtqzon",
+	"imKLFUDuH1iMuPHZXFgbnRuB4w+E4HuOuGOeOkW2lJuv5zeNqcVduZ/bINLgXXkAPQwkNSqDBdFp1uKY",
+	"98tgD/9U0TkVIw+y9XJS4LhSHsAgsw/qjDQqYNK7OZydqPVUe50tzoiV9ha9c16dP1vAMXMlOywDN0Pn",
+	"/Zwi+xl9NbYkUHC6GF7ayOpHZ8ysqYviKfnuXbkfFrVjt47uaRCdo0In/FSkfgvk0lduXAIVqU0msQ2h",
+	"A8+glxDxm0sgInsEh4lGynOG7S/ZA7ixHX9JIfNbZVbyYY6hk7j+EaVW30wBQXXuNCcK3lEvLWFwDhjM",
+	"tIgFSzhcwuESDi8QDtMn0VmWrsCQssq8hk5LieiEfk5YvEvvLFFxdlTEPck0yfaj1GkJjEtgXALj+YHx",
+	"Z1LD44hsKjWFmo0t4q30eVhKu3NmGUzBSmxYYtSuNB9evovvvU1dTkvInBEy0/1VS6RcIuUSKS8KKR+n",
+	"GxchmDeI3PlsavmcGsTHUC5nZJTOR12WeFTaixore9BHqZKl4MFfJ5rD+bjOeCMZjfGFyQX15ReOwkxt",
+	"0C+YmxrRYdbDM5PDnMpFEfMjHvDQFAwZqvJNffml3J+wtmduZReOlfA6jSBZdt1ODyDF/XcTJHFEe64a",
+	"SbpoA+ow9jg8w6JCNTnODHkQTCCvZtmWmEDfCjXNKgJfXTmF2ss5RcmNZcxyhBKZ1AgG4SnUywIGBU1E",
+	"keNFF6eYoaHAz9Wcl9pzbHHAhn6qwkX2EowdSaiePprsU8cW4UsomynRUxGCXF63w6nTNpfcKPDOe4U7",
+	"Hu0LlQMW6Nhhedz/P3zc/zjRRpV/KS0tPMOHY9XLE85UqKazbhJklB5Qp06zRMMCmzXTx2HC9LDENARZ",
+	"x3dv4pu3TV/cSTfZZ4GlaOo2nDU4+8TtxnPMntKjBAWwkElQsunIgnUPjeVSoYEUULxsLpjN+r5PiU5V",
+	"P5Apit16VN4Ih/KjZ/K9WmMN99Sds0sTewOc6YhDm3ETrbAaE6GNeqnhz1Mt9E16EU00Kgq8UAOdXIxo",
+	"E6odLdR51kztd2PNeuMbHM9lxOwljfZLu5vN7n5JC6/I7g6V18u2wg0LC5K5IkRuP6/cvvXW24Z21ra4",
+	"7KT4ZAtNJUAL03oczS3GSpGaXHxOs4z58fcFaB6eJwRIZwgLFwJQjUE+InDKen5qn08zsswrlnlFklcM",
+	"wwmn0wKYK2eHSfzrH4yfBpLhUy9gVMpydixBX71Z/Nd5flBlbSWfQziiv8sTOvj8wDveCR/OBuEoZDQA",
+	"VKGo4keFnP6cA1zJYOwYoT+rLdLgSViQD2eR0BcWk/9benbi7bA1LEonUvYLI0NDN5sOg6mbtoBBNQ61",
+	"Rzd6WSSN/+xVVos62pVodPOVlEu61Pnfc87DXZyTomnr4spV0VTUowWtZS3EfNnPxdo5eb4MX2d8JwrY",
+	"6rymr+kl07NKO9f1grL/L+E5F5pBV34VhrGyTT4msjUSk9xD06ejUDSNLsW1qP7Hcl8LV6BgNywKy/3Q",
+	"kMf/wltHu1t3fCZ0IyJPb240/zsAuPxX7wFPAAA=",
+}
+
+// GetSwagger returns the content of the embedded swagger specification file
+// or error if failed to decode
+func decodeSpec() ([]byte, error) {
+	zipped, err := base64.StdEncoding.DecodeString(strings.Join(swaggerSpec, ""))
+	if err != nil {
+		return nil, fmt.Errorf("error base64 decoding spec: %w", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(zipped))
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %w", err)
+	}
+	var buf bytes.Buffer
+	_, err = buf.ReadFrom(zr)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+var rawSpec = decodeSpecCached()
+
+// a naive cached of a decoded swagger spec
+func decodeSpecCached() func() ([]byte, error) {
+	data, err := decodeSpec()
+	return func() ([]byte, error) {
+		return data, err
+	}
+}
+
+// Constructs a synthetic filesystem for resolving external references when loading openapi specifications.
+func PathToRawSpec(pathToFile string) map[string]func() ([]byte, error) {
+	res := make(map[string]func() ([]byte, error))
+	if len(pathToFile) > 0 {
+		res[pathToFile] = rawSpec
+	}
+
+	return res
+}
+
+// GetSwagger returns the Swagger specification corresponding to the generated code
+// in this file. The external references of Swagger specification are resolved.
+// The logic of resolving external references is tightly connected to "import-mapping" feature.
+// Externally referenced files must be embedded in the corresponding golang packages.
+// Urls can be supported but this task was out of the scope.
+func GetSwagger() (swagger *openapi3.T, err error) {
+	resolvePath := PathToRawSpec("")
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = func(loader *openapi3.Loader, url *url.URL) ([]byte, error) {
+		pathToFile := url.String()
+		pathToFile = path.Clean(pathToFile)
+		getSpec, ok := resolvePath[pathToFile]
+		if !ok {
+			err1 := fmt.Errorf("path not found: %s", pathToFile)
+			return nil, err1
+		}
+		return getSpec()
+	}
+	var specData []byte
+	specData, err = rawSpec()
+	if err != nil {
+		return
+	}
+	swagger, err = loader.LoadFromData(specData)
+	if err != nil {
+		return
+	}
+	return
+}
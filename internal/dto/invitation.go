@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"github.com/samber/lo"
+)
+
+// Invitation is returned once, when it is created: Code is not stored.
+type Invitation struct {
+	Id        openapi_types.UUID   `json:"id"`
+	Email     string               `json:"email"`
+	Role      UserRole             `json:"role"`
+	PvzIds    []openapi_types.UUID `json:"pvzIds"`
+	Code      string               `json:"code"`
+	CreatedAt time.Time            `json:"createdAt"`
+	ExpiresAt time.Time            `json:"expiresAt"`
+}
+
+func EntityInvitationToDTO(e *entity.Invitation, code string) *Invitation {
+	return &Invitation{
+		Id:        openapi_types.UUID(e.ID),
+		Email:     e.Email,
+		Role:      UserRole(e.Role),
+		PvzIds:    lo.Ternary(e.PointIDs == nil, []openapi_types.UUID{}, e.PointIDs),
+		Code:      code,
+		CreatedAt: e.CreatedAt,
+		ExpiresAt: e.ExpiresAt,
+	}
+}
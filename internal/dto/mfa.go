@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"encoding/base64"
+
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+)
+
+// MFATokens carries RecoveryCodes only when the login confirmed an
+// enrolment: the codes are not stored and cannot be shown again.
+type MFATokens struct {
+	*auth.Tokens
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+type MFAEnrollment struct {
+	Secret     string `json:"secret"`
+	OtpauthUri string `json:"otpauthUri"`
+	QrCode     string `json:"qrCode"`
+}
+
+type RecoveryCodes struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// EntityMFAEnrollmentToDTO inlines the QR code as a data URI.
+func EntityMFAEnrollmentToDTO(e *entity.MFAEnrollment) *MFAEnrollment {
+	return &MFAEnrollment{
+		Secret:     e.Secret,
+		OtpauthUri: e.URI,
+		QrCode:     "data:image/png;base64," + base64.StdEncoding.EncodeToString(e.QRCode),
+	}
+}
@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+type Order struct {
+	Id              openapi_types.UUID   `json:"id"`
+	PvzId           openapi_types.UUID   `json:"pvzId"`
+	Number          string               `json:"number"`
+	CustomerContact string               `json:"customerContact"`
+	Status          entity.OrderStatus   `json:"status"`
+	ProductIds      []openapi_types.UUID `json:"productIds"`
+	DateTime        time.Time            `json:"dateTime"`
+	IssuedAt        *time.Time           `json:"issuedAt,omitempty"`
+}
+
+func EntityOrderToDTO(e *entity.Order) *Order {
+	productIDs := make([]openapi_types.UUID, 0, len(e.ProductIDs))
+	for _, id := range e.ProductIDs {
+		productIDs = append(productIDs, openapi_types.UUID(id))
+	}
+	return &Order{
+		Id:              openapi_types.UUID(e.ID),
+		PvzId:           openapi_types.UUID(e.PointID),
+		Number:          e.Number,
+		CustomerContact: e.CustomerContact,
+		Status:          e.Status,
+		ProductIds:      productIDs,
+		DateTime:        e.CreatedAt,
+		IssuedAt:        e.IssuedAt,
+	}
+}
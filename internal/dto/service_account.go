@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"github.com/samber/lo"
+)
+
+type ServiceAccount struct {
+	Id          openapi_types.UUID `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	CreatedBy   openapi_types.UUID `json:"createdBy"`
+	CreatedAt   time.Time          `json:"createdAt"`
+	Keys        []APIKey           `json:"keys"`
+}
+
+// APIKey carries Key only when the key is created or rotated: it is not
+// stored and cannot be shown again.
+type APIKey struct {
+	Id         openapi_types.UUID `json:"id"`
+	Prefix     string             `json:"prefix"`
+	Key        string             `json:"key,omitempty"`
+	Scopes     []string           `json:"scopes"`
+	CreatedAt  time.Time          `json:"createdAt"`
+	ExpiresAt  time.Time          `json:"expiresAt"`
+	LastUsedAt *time.Time         `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time         `json:"revokedAt,omitempty"`
+}
+
+func EntityServiceAccountToDTO(e *entity.ServiceAccount) *ServiceAccount {
+	return &ServiceAccount{
+		Id:          openapi_types.UUID(e.ID),
+		Name:        e.Name,
+		Description: e.Description,
+		CreatedBy:   openapi_types.UUID(e.CreatedBy),
+		CreatedAt:   e.CreatedAt,
+		Keys: lo.Map(e.Keys, func(k entity.APIKey, _ int) APIKey {
+			return *EntityAPIKeyToDTO(&k, "")
+		}),
+	}
+}
+
+func EntityAPIKeyToDTO(e *entity.APIKey, key string) *APIKey {
+	return &APIKey{
+		Id:     openapi_types.UUID(e.ID),
+		Prefix: e.Prefix,
+		Key:    key,
+		Scopes: lo.Map(e.Scopes, func(p entity.Permission, _ int) string {
+			return string(p)
+		}),
+		CreatedAt:  e.CreatedAt,
+		ExpiresAt:  e.ExpiresAt,
+		LastUsedAt: e.LastUsedAt,
+		RevokedAt:  e.RevokedAt,
+	}
+}
@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+type Session struct {
+	Id         openapi_types.UUID `json:"id"`
+	UserAgent  string             `json:"userAgent"`
+	Ip         string             `json:"ip"`
+	CreatedAt  time.Time          `json:"createdAt"`
+	LastUsedAt time.Time          `json:"lastUsedAt"`
+	ExpiresAt  time.Time          `json:"expiresAt"`
+	Current    bool               `json:"current"`
+}
+
+// EntitySessionToDTO marks the session the request was made from as current.
+func EntitySessionToDTO(e *entity.Session, currentID uuid.UUID) *Session {
+	return &Session{
+		Id:         openapi_types.UUID(e.ID),
+		UserAgent:  e.UserAgent,
+		Ip:         e.IP,
+		CreatedAt:  e.CreatedAt,
+		LastUsedAt: e.LastUsedAt,
+		ExpiresAt:  e.ExpiresAt,
+		Current:    e.ID == currentID,
+	}
+}
@@ -0,0 +1,18 @@
+package dto
+
+import (
+	"github.com/4udiwe/avito-pvz/internal/entity"
+)
+
+type StockItem struct {
+	Type   entity.ProductType `json:"type"`
+	Amount int                `json:"amount"`
+}
+
+func EntityStockToDTO(stock []entity.Stock) []StockItem {
+	items := make([]StockItem, 0, len(stock))
+	for _, s := range stock {
+		items = append(items, StockItem{Type: s.Type, Amount: s.Amount})
+	}
+	return items
+}
@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+type Transfer struct {
+	Id               openapi_types.UUID    `json:"id"`
+	SourcePvzId      openapi_types.UUID    `json:"sourcePvzId"`
+	DestinationPvzId openapi_types.UUID    `json:"destinationPvzId"`
+	Status           entity.TransferStatus `json:"status"`
+	ReceptionId      *openapi_types.UUID   `json:"receptionId,omitempty"`
+	Products         []TransferProduct     `json:"products"`
+	DateTime         time.Time             `json:"dateTime"`
+	DispatchedAt     *time.Time            `json:"dispatchedAt,omitempty"`
+	AcceptedAt       *time.Time            `json:"acceptedAt,omitempty"`
+}
+
+type TransferProduct struct {
+	ProductId openapi_types.UUID `json:"productId"`
+	Accepted  bool               `json:"accepted"`
+	Missing   bool               `json:"missing"`
+}
+
+func EntityTransferToDTO(e *entity.Transfer) *Transfer {
+	products := make([]TransferProduct, 0, len(e.Products))
+	for _, p := range e.Products {
+		products = append(products, TransferProduct{
+			ProductId: openapi_types.UUID(p.ProductID),
+			Accepted:  p.Accepted,
+			Missing:   p.Missing,
+		})
+	}
+	return &Transfer{
+		Id:               openapi_types.UUID(e.ID),
+		SourcePvzId:      openapi_types.UUID(e.SourcePointID),
+		DestinationPvzId: openapi_types.UUID(e.DestinationPointID),
+		Status:           e.Status,
+		ReceptionId:      e.ReceptionID,
+		Products:         products,
+		DateTime:         e.CreatedAt,
+		DispatchedAt:     e.DispatchedAt,
+		AcceptedAt:       e.AcceptedAt,
+	}
+}
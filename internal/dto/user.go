@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// UserAccount is a user as seen by moderators.
+type UserAccount struct {
+	Id         openapi_types.UUID `json:"id"`
+	Email      string             `json:"email"`
+	Role       UserRole           `json:"role"`
+	Disabled   bool               `json:"disabled"`
+	DisabledAt *time.Time         `json:"disabledAt,omitempty"`
+	CreatedAt  time.Time          `json:"createdAt"`
+	UpdatedAt  time.Time          `json:"updatedAt"`
+}
+
+func EntityUserToDTO(e *entity.User) *UserAccount {
+	return &UserAccount{
+		Id:         openapi_types.UUID(e.ID),
+		Email:      e.Email,
+		Role:       UserRole(e.Role),
+		Disabled:   e.Disabled(),
+		DisabledAt: e.DisabledAt,
+		CreatedAt:  e.CreatedAt,
+		UpdatedAt:  e.UpdatedAt,
+	}
+}
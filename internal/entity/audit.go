@@ -0,0 +1,164 @@
+package entity
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditAction string
+
+const (
+	AuditActionUserRoleChanged AuditAction = "user.role_changed"
+	AuditActionUserDisabled    AuditAction = "user.disabled"
+	AuditActionUserEnabled     AuditAction = "user.enabled"
+	AuditActionUserUnlocked    AuditAction = "user.unlocked"
+	AuditActionMFAEnabled      AuditAction = "user.mfa_enabled"
+	AuditActionMFAReset        AuditAction = "user.mfa_reset"
+	AuditActionUserRegistered  AuditAction = "user.registered"
+	AuditActionPasswordChanged AuditAction = "user.password_changed"
+	AuditActionPasswordReset   AuditAction = "user.password_reset"
+	AuditActionSessionRevoked  AuditAction = "session.revoked"
+
+	AuditActionPointCreated AuditAction = "point.created"
+
+	AuditActionReceptionOpened AuditAction = "reception.opened"
+	AuditActionReceptionClosed AuditAction = "reception.closed"
+
+	AuditActionProductAdded         AuditAction = "product.added"
+	AuditActionProductDeleted       AuditAction = "product.deleted"
+	AuditActionProductStatusChanged AuditAction = "product.status_changed"
+
+	AuditActionInvitationCreated AuditAction = "invitation.created"
+
+	AuditActionServiceAccountCreated AuditAction = "service_account.created"
+	AuditActionAPIKeyCreated         AuditAction = "api_key.created"
+	AuditActionAPIKeyRotated         AuditAction = "api_key.rotated"
+	AuditActionAPIKeyRevoked         AuditAction = "api_key.revoked"
+)
+
+type AuditTarget string
+
+const (
+	AuditTargetUser       AuditTarget = "user"
+	AuditTargetSession    AuditTarget = "session"
+	AuditTargetInvitation AuditTarget = "invitation"
+	AuditTargetPoint      AuditTarget = "point"
+	AuditTargetReception  AuditTarget = "reception"
+	AuditTargetProduct    AuditTarget = "product"
+
+	AuditTargetServiceAccount AuditTarget = "service_account"
+	AuditTargetAPIKey         AuditTarget = "api_key"
+)
+
+// AuditRecord is a change made by ActorID. Before and After hold JSON
+// snapshots of the target, either may be empty. RequestID links the record
+// to the request that made the change. Records form a hash chain: Seq is
+// the position in it and Hash covers the record and PrevHash.
+type AuditRecord struct {
+	ID         uuid.UUID       `db:"id"`
+	ActorID    uuid.UUID       `db:"actor_id"`
+	Action     AuditAction     `db:"action"`
+	TargetType AuditTarget     `db:"target_type"`
+	TargetID   uuid.UUID       `db:"target_id"`
+	Before     json.RawMessage `db:"before"`
+	After      json.RawMessage `db:"after"`
+	RequestID  string          `db:"request_id"`
+	CreatedAt  time.Time       `db:"created_at"`
+	Seq        int64           `db:"seq"`
+	PrevHash   string          `db:"prev_hash"`
+	Hash       string          `db:"hash"`
+}
+
+// ChainPayload is the canonical form of the record that its hash covers.
+// Snapshots are re-encoded, so the payload does not depend on how the
+// database formats JSON.
+func (r AuditRecord) ChainPayload() ([]byte, error) {
+	before, err := canonicalJSON(r.Before)
+	if err != nil {
+		return nil, err
+	}
+	after, err := canonicalJSON(r.After)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(struct {
+		Seq        int64           `json:"seq"`
+		ID         uuid.UUID       `json:"id"`
+		ActorID    uuid.UUID       `json:"actor_id"`
+		Action     AuditAction     `json:"action"`
+		TargetType AuditTarget     `json:"target_type"`
+		TargetID   uuid.UUID       `json:"target_id"`
+		Before     json.RawMessage `json:"before"`
+		After      json.RawMessage `json:"after"`
+		RequestID  string          `json:"request_id"`
+		CreatedAt  string          `json:"created_at"`
+	}{
+		Seq:        r.Seq,
+		ID:         r.ID,
+		ActorID:    r.ActorID,
+		Action:     r.Action,
+		TargetType: r.TargetType,
+		TargetID:   r.TargetID,
+		Before:     before,
+		After:      after,
+		RequestID:  r.RequestID,
+		CreatedAt:  r.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+}
+
+// canonicalJSON sorts object keys and drops insignificant whitespace.
+func canonicalJSON(raw json.RawMessage) (json.RawMessage, error) {
+	if len(raw) == 0 {
+		return json.RawMessage("null"), nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var v any
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// AuditCheckpoint is a signed copy of the chain head at Seq. A record
+// edited or deleted up to Seq no longer matches the signed Hash.
+type AuditCheckpoint struct {
+	Seq       int64     `db:"seq"`
+	Hash      string    `db:"hash"`
+	Signature string    `db:"signature"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// AuditChainReport is the result of walking the audit chain. Break is the
+// first broken link, nil when the chain is intact.
+type AuditChainReport struct {
+	Records     int64
+	Checkpoints int
+	Break       *AuditChainBreak
+}
+
+type AuditChainBreak struct {
+	Seq      int64
+	RecordID uuid.UUID
+	Reason   string
+}
+
+// AuditFilter selects audit records, newest first. Nil fields are not
+// filtered on; From is inclusive and To exclusive.
+type AuditFilter struct {
+	ActorID    *uuid.UUID
+	Action     *AuditAction
+	TargetType *AuditTarget
+	TargetID   *uuid.UUID
+	RequestID  string
+	From       *time.Time
+	To         *time.Time
+	Page       int
+	Limit      int
+}
@@ -0,0 +1,21 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyKey is a key sent by a client in the Idempotency-Key header
+// together with the response to replay when the request is retried. Keys
+// are scoped to their owner, the authenticated user or service account.
+type IdempotencyKey struct {
+	OwnerID     uuid.UUID `db:"owner_id"`
+	Key         string    `db:"key"`
+	RequestHash string    `db:"request_hash"`
+	Status      int       `db:"status"`
+	ContentType string    `db:"content_type"`
+	Body        []byte    `db:"body"`
+	CreatedAt   time.Time `db:"created_at"`
+	ExpiresAt   time.Time `db:"expires_at"`
+}
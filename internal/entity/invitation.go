@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Invitation lets a new user register with the given role. The code is sent
+// to the invitee once; only its hash is stored.
+type Invitation struct {
+	ID        uuid.UUID   `db:"id"`
+	Email     string      `db:"email"`
+	Role      UserRole    `db:"role"`
+	PointIDs  []uuid.UUID `db:"-"`
+	CodeHash  string      `db:"code_hash"`
+	CreatedBy uuid.UUID   `db:"created_by"`
+	CreatedAt time.Time   `db:"created_at"`
+	ExpiresAt time.Time   `db:"expires_at"`
+	UsedAt    *time.Time  `db:"used_at"`
+	UsedBy    *uuid.UUID  `db:"used_by"`
+}
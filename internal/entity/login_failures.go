@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// LoginScope is what failed logins are counted by.
+type LoginScope string
+
+const (
+	LoginScopeEmail LoginScope = "email"
+	LoginScopeIP    LoginScope = "ip"
+)
+
+// LoginFailures is the failed login counter of a single email or IP.
+type LoginFailures struct {
+	Scope         LoginScope `db:"scope"`
+	Key           string     `db:"key"`
+	Failures      int        `db:"failures"`
+	LastFailureAt time.Time  `db:"last_failure_at"`
+	LockedUntil   *time.Time `db:"locked_until"`
+}
@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserMFA is the TOTP second factor of a user. Secrets are stored
+// encrypted. PendingSecret belongs to an enrolment that has not been
+// confirmed with a code yet and does not replace Secret until it is.
+type UserMFA struct {
+	UserID        uuid.UUID  `db:"user_id"`
+	Secret        string     `db:"secret_encrypted"`
+	PendingSecret string     `db:"pending_secret_encrypted"`
+	LastUsedStep  int64      `db:"last_used_step"`
+	ConfirmedAt   *time.Time `db:"confirmed_at"`
+	CreatedAt     time.Time  `db:"created_at"`
+}
+
+func (m UserMFA) Enabled() bool {
+	return m.ConfirmedAt != nil && m.Secret != ""
+}
+
+// MFAChallenge is a login that passed the password check and waits for
+// the second factor. Only the hash of the MFA pending token is stored.
+type MFAChallenge struct {
+	ID        uuid.UUID `db:"id"`
+	TokenHash string    `db:"token_hash"`
+	UserID    uuid.UUID `db:"user_id"`
+	Attempts  int       `db:"attempts"`
+	CreatedAt time.Time `db:"created_at"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
+
+// MFAEnrollment is shown once, when a user starts enrolling: the secret,
+// the otpauth URI and the same URI as a PNG QR code.
+type MFAEnrollment struct {
+	Secret string
+	URI    string
+	QRCode []byte
+}
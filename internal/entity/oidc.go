@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OIDCLoginState is a login started at the identity provider and not yet
+// completed. Only the hash of the state is stored; the nonce and the PKCE
+// code verifier are useless without the authorization code.
+type OIDCLoginState struct {
+	StateHash    string    `db:"state_hash"`
+	Nonce        string    `db:"nonce"`
+	CodeVerifier string    `db:"code_verifier"`
+	CreatedAt    time.Time `db:"created_at"`
+	ExpiresAt    time.Time `db:"expires_at"`
+}
+
+// UserIdentity links a user to an account at an external identity provider.
+type UserIdentity struct {
+	Issuer    string    `db:"issuer"`
+	Subject   string    `db:"subject"`
+	UserID    uuid.UUID `db:"user_id"`
+	CreatedAt time.Time `db:"created_at"`
+}
@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OrderStatus string
+
+const (
+	OrderStatusAssembling OrderStatus = "assembling"
+	OrderStatusReady      OrderStatus = "ready"
+	OrderStatusIssued     OrderStatus = "issued"
+)
+
+type Order struct {
+	ID              uuid.UUID   `db:"id"`
+	PointID         uuid.UUID   `db:"point_id"`
+	Number          string      `db:"number"`
+	CustomerContact string      `db:"customer_contact"`
+	Status          OrderStatus `db:"status"`
+	PickupCodeHash  string      `db:"pickup_code_hash"`
+	CodeExpiresAt   *time.Time  `db:"code_expires_at"`
+	FailedAttempts  int         `db:"failed_attempts"`
+	LockedUntil     *time.Time  `db:"locked_until"`
+	CreatedAt       time.Time   `db:"created_at"`
+	IssuedAt        *time.Time  `db:"issued_at"`
+	ProductIDs      []uuid.UUID
+}
@@ -0,0 +1,35 @@
+package entity
+
+// Permission allows an action. Roles are granted permissions in the
+// role_permissions table.
+type Permission string
+
+const (
+	PermissionPointCreate Permission = "point:create"
+	PermissionPointRead   Permission = "point:read"
+	PermissionCellManage  Permission = "cell:manage"
+
+	PermissionReceptionOpen  Permission = "reception:open"
+	PermissionReceptionClose Permission = "reception:close"
+
+	PermissionProductAdd      Permission = "product:add"
+	PermissionProductDelete   Permission = "product:delete"
+	PermissionProductMove     Permission = "product:move"
+	PermissionProductPlace    Permission = "product:place"
+	PermissionProductWriteOff Permission = "product:write_off"
+	PermissionProductRead     Permission = "product:read"
+
+	PermissionOrderManage Permission = "order:manage"
+	PermissionOrderRead   Permission = "order:read"
+
+	PermissionTransferManage Permission = "transfer:manage"
+	PermissionTransferRead   Permission = "transfer:read"
+
+	PermissionUserRead   Permission = "user:read"
+	PermissionUserManage Permission = "user:manage"
+	PermissionUserInvite Permission = "user:invite"
+
+	PermissionServiceAccountManage Permission = "service_account:manage"
+
+	PermissionAuditRead Permission = "audit:read"
+)
@@ -12,6 +12,16 @@ type Point struct {
 	City      string    `db:"city"`
 }
 
+// PointFilter selects a page of points, oldest first. With StartDate or
+// EndDate set, only points with receptions in that period are listed, and
+// only with those receptions. Both ends are inclusive.
+type PointFilter struct {
+	StartDate *time.Time
+	EndDate   *time.Time
+	Page      int
+	Limit     int
+}
+
 type PointFullInfo struct {
 	Point      Point
 	Receptions []ReceptionWithProducts
@@ -14,9 +14,59 @@ const (
 	ProductTypeShoes       ProductType = "обувь"
 )
 
+type ProductStatus string
+
+const (
+	ProductStatusReceived   ProductStatus = "received"
+	ProductStatusStored     ProductStatus = "stored"
+	ProductStatusIssued     ProductStatus = "issued"
+	ProductStatusReturned   ProductStatus = "returned"
+	ProductStatusWrittenOff ProductStatus = "written_off"
+	ProductStatusInTransit  ProductStatus = "in_transit"
+)
+
+// productTransitions describes the product lifecycle:
+// received -> stored -> issued | returned | written_off,
+// with stored -> in_transit -> received when a product is moved to another point.
+var productTransitions = map[ProductStatus][]ProductStatus{
+	ProductStatusReceived:  {ProductStatusStored},
+	ProductStatusStored:    {ProductStatusIssued, ProductStatusReturned, ProductStatusWrittenOff, ProductStatusInTransit},
+	ProductStatusInTransit: {ProductStatusReceived, ProductStatusWrittenOff},
+}
+
+func (s ProductStatus) CanTransitionTo(to ProductStatus) bool {
+	for _, allowed := range productTransitions[s] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// RequiresReason reports whether moving a product into this status must be justified.
+func (s ProductStatus) RequiresReason() bool {
+	return s == ProductStatusReturned || s == ProductStatusWrittenOff
+}
+
 type Product struct {
-	ID          uuid.UUID   `db:"id"`
-	ReceptionID uuid.UUID   `db:"reception_id"`
-	CreatedAt   time.Time   `db:"created_at"`
-	Type        ProductType `db:"type"`
+	ID          uuid.UUID     `db:"id"`
+	ReceptionID uuid.UUID     `db:"reception_id"`
+	CreatedAt   time.Time     `db:"created_at"`
+	Type        ProductType   `db:"type"`
+	Status      ProductStatus `db:"status"`
+	PointID     uuid.UUID     `db:"point_id"`
+	Barcode     *string       `db:"barcode"`
+	CellID      *uuid.UUID    `db:"cell_id"`
+}
+
+type ProductHistory struct {
+	ID         uuid.UUID      `db:"id"`
+	ProductID  uuid.UUID      `db:"product_id"`
+	FromStatus *ProductStatus `db:"from_status"`
+	ToStatus   ProductStatus  `db:"to_status"`
+	Reason     string         `db:"reason"`
+	ActorID    uuid.UUID      `db:"actor_id"`
+	FromCellID *uuid.UUID     `db:"from_cell_id"`
+	ToCellID   *uuid.UUID     `db:"to_cell_id"`
+	CreatedAt  time.Time      `db:"created_at"`
 }
@@ -13,9 +13,17 @@ const (
 	ReceptionStatusClosed     ReceptionStatus = "close"
 )
 
+type ReceptionKind string
+
+const (
+	ReceptionKindRegular  ReceptionKind = "regular"
+	ReceptionKindTransfer ReceptionKind = "transfer"
+)
+
 type Reception struct {
 	ID        uuid.UUID       `db:"id"`
 	PointID   uuid.UUID       `db:"point_id"`
 	CreatedAt time.Time       `db:"created_at"`
 	Status    ReceptionStatus `db:"status"`
+	Kind      ReceptionKind   `db:"kind"`
 }
@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ServiceAccount is a non-human principal used by partner systems. It acts
+// through API keys, and its ID is recorded wherever a user ID would be.
+type ServiceAccount struct {
+	ID          uuid.UUID `db:"id"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	CreatedBy   uuid.UUID `db:"created_by"`
+	CreatedAt   time.Time `db:"created_at"`
+	Keys        []APIKey  `db:"-"`
+}
+
+// APIKey authenticates a service account. Scopes are the permissions the key
+// grants. Only the hash of the key is stored; Prefix identifies the key in
+// listings.
+type APIKey struct {
+	ID               uuid.UUID    `db:"id"`
+	ServiceAccountID uuid.UUID    `db:"service_account_id"`
+	Prefix           string       `db:"prefix"`
+	KeyHash          string       `db:"key_hash"`
+	Scopes           []Permission `db:"-"`
+	CreatedBy        uuid.UUID    `db:"created_by"`
+	CreatedAt        time.Time    `db:"created_at"`
+	ExpiresAt        time.Time    `db:"expires_at"`
+	LastUsedAt       *time.Time   `db:"last_used_at"`
+	RevokedAt        *time.Time   `db:"revoked_at"`
+}
+
+// Active tells whether the key can be used at the given time.
+func (k APIKey) Active(at time.Time) bool {
+	return k.RevokedAt == nil && k.ExpiresAt.After(at)
+}
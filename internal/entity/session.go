@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session is a single signed-in device. The refresh token is rotated on
+// every use, and only the hash of the latest one is kept, so a session is
+// also the family of all refresh tokens issued to that device. MFAVerified
+// tells that the login passed a second factor.
+type Session struct {
+	ID               uuid.UUID  `db:"id"`
+	UserID           uuid.UUID  `db:"user_id"`
+	RefreshTokenHash string     `db:"refresh_token_hash"`
+	UserAgent        string     `db:"user_agent"`
+	IP               string     `db:"ip"`
+	CreatedAt        time.Time  `db:"created_at"`
+	LastUsedAt       time.Time  `db:"last_used_at"`
+	ExpiresAt        time.Time  `db:"expires_at"`
+	RevokedAt        *time.Time `db:"revoked_at"`
+	MFAVerified      bool       `db:"mfa_verified"`
+}
+
+// ClientInfo describes the device a request came from.
+type ClientInfo struct {
+	UserAgent string
+	IP        string
+}
@@ -0,0 +1,11 @@
+package entity
+
+import "github.com/google/uuid"
+
+// Stock is the amount of products of one type physically present at a point.
+// PointID is empty for aggregates over several points.
+type Stock struct {
+	PointID uuid.UUID   `db:"point_id"`
+	Type    ProductType `db:"type"`
+	Amount  int         `db:"amount"`
+}
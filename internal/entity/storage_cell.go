@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StorageCell is a rack/shelf/bin place inside a point. ProductType restricts
+// the cell to one product type, nil accepts any type. Occupied is the number
+// of products present in the cell.
+type StorageCell struct {
+	ID          uuid.UUID    `db:"id"`
+	PointID     uuid.UUID    `db:"point_id"`
+	Rack        string       `db:"rack"`
+	Shelf       string       `db:"shelf"`
+	Bin         string       `db:"bin"`
+	Capacity    int          `db:"capacity"`
+	ProductType *ProductType `db:"product_type"`
+	Occupied    int          `db:"occupied"`
+	CreatedAt   time.Time    `db:"created_at"`
+}
+
+// Accepts reports whether a product of the given type may be placed into the cell.
+func (c StorageCell) Accepts(productType ProductType) bool {
+	return c.ProductType == nil || *c.ProductType == productType
+}
+
+func (c StorageCell) IsFull() bool {
+	return c.Occupied >= c.Capacity
+}
@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TransferStatus string
+
+const (
+	TransferStatusCreated    TransferStatus = "created"
+	TransferStatusDispatched TransferStatus = "dispatched"
+	TransferStatusAccepted   TransferStatus = "accepted"
+)
+
+type Transfer struct {
+	ID                 uuid.UUID      `db:"id"`
+	SourcePointID      uuid.UUID      `db:"source_point_id"`
+	DestinationPointID uuid.UUID      `db:"destination_point_id"`
+	Status             TransferStatus `db:"status"`
+	ReceptionID        *uuid.UUID     `db:"reception_id"`
+	CreatedBy          uuid.UUID      `db:"created_by"`
+	CreatedAt          time.Time      `db:"created_at"`
+	DispatchedAt       *time.Time     `db:"dispatched_at"`
+	AcceptedAt         *time.Time     `db:"accepted_at"`
+	Products           []TransferProduct
+}
+
+type TransferProduct struct {
+	ProductID uuid.UUID `db:"product_id"`
+	Accepted  bool      `db:"accepted"`
+	Missing   bool      `db:"missing"`
+}
@@ -1,24 +1,68 @@
 package entity
 
 import (
+	"fmt"
+	"log/slog"
 	"time"
 
+	"github.com/4udiwe/avito-pvz/pkg/redact"
 	"github.com/google/uuid"
 )
 
+// UserRole names a row of the roles table. The constants are the roles
+// created by migrations; more can be added in the database.
 type UserRole string
 
 const (
-	RoleModerator UserRole = "moderator"
-	RoleEmployee  UserRole = "employee"
+	RoleModerator       UserRole = "moderator"
+	RoleEmployee        UserRole = "employee"
+	RoleSeniorEmployee  UserRole = "senior_employee"
+	RoleRegionalManager UserRole = "regional_manager"
+	RoleAuditor         UserRole = "auditor"
 )
 
 type User struct {
-	ID           uuid.UUID `db:"id"`
-	Email        string    `db:"email"`
-	PasswordHash string    `db:"password_hash"`
-	Role         UserRole  `db:"role"`
-	RefreshToken string    `db:"refresh_token"`
-	CreatedAt    time.Time `db:"created_at"`
-	UpdatedAt    time.Time `db:"updated_at"`
+	ID           uuid.UUID  `db:"id"`
+	Email        string     `db:"email"`
+	PasswordHash string     `db:"password_hash"`
+	Role         UserRole   `db:"role"`
+	CreatedAt    time.Time  `db:"created_at"`
+	UpdatedAt    time.Time  `db:"updated_at"`
+	DisabledAt   *time.Time `db:"disabled_at"`
+}
+
+func (u User) Disabled() bool {
+	return u.DisabledAt != nil
+}
+
+// HasPassword tells whether the user can sign in with a password. Users
+// provisioned by the identity provider have none and sign in only there.
+func (u User) HasPassword() bool {
+	return u.PasswordHash != ""
+}
+
+// String leaves out the password hash and masks the email, so a user
+// printed with %v or %+v is safe to log.
+func (u User) String() string {
+	return fmt.Sprintf("{ID:%s Email:%s Role:%s Disabled:%t}", u.ID, redact.Email(u.Email), u.Role, u.Disabled())
+}
+
+// LogValue is String for log/slog.
+func (u User) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("id", u.ID.String()),
+		slog.String("email", redact.Email(u.Email)),
+		slog.String("role", string(u.Role)),
+		slog.Bool("disabled", u.Disabled()),
+	)
+}
+
+// UserFilter selects users for the moderator list. Query matches a part of
+// the email; nil fields are not filtered on.
+type UserFilter struct {
+	Query    string
+	Role     *UserRole
+	Disabled *bool
+	Page     int
+	Limit    int
 }
@@ -0,0 +1,34 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+type LoginMetrics struct {
+	failures *prometheus.CounterVec
+	lockouts *prometheus.CounterVec
+}
+
+func NewLoginMetrics() *LoginMetrics {
+	m := &LoginMetrics{
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "login_failures_total",
+			Help: "Amount of failed login attempts",
+		}, []string{"reason"}),
+		lockouts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "login_lockouts_total",
+			Help: "Amount of login lockouts by email or IP",
+		}, []string{"scope"}),
+	}
+
+	prometheus.MustRegister(m.failures)
+	prometheus.MustRegister(m.lockouts)
+
+	return m
+}
+
+func (m *LoginMetrics) FailureInc(reason string) {
+	m.failures.WithLabelValues(reason).Inc()
+}
+
+func (m *LoginMetrics) LockoutInc(scope string) {
+	m.lockouts.WithLabelValues(scope).Inc()
+}
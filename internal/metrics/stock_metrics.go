@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+const stockScrapeTimeout = 5 * time.Second
+
+type StockSource interface {
+	GetAllStock(ctx context.Context) ([]entity.Stock, error)
+}
+
+// StockMetrics exposes current stock per point and product type.
+// Values are read from the stock counters on every scrape.
+type StockMetrics struct {
+	source StockSource
+	desc   *prometheus.Desc
+}
+
+func NewStockMetrics(source StockSource) *StockMetrics {
+	m := &StockMetrics{
+		source: source,
+		desc: prometheus.NewDesc(
+			"point_stock_products",
+			"Amount of products physically present at a point",
+			[]string{"point_id", "type"},
+			nil,
+		),
+	}
+
+	prometheus.MustRegister(m)
+
+	return m
+}
+
+func (m *StockMetrics) Describe(ch chan<- *prometheus.Desc) {
+	ch <- m.desc
+}
+
+func (m *StockMetrics) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), stockScrapeTimeout)
+	defer cancel()
+
+	stock, err := m.source.GetAllStock(ctx)
+	if err != nil {
+		logrus.Errorf("Failed to collect stock metrics: %v", err)
+		return
+	}
+
+	for _, s := range stock {
+		ch <- prometheus.MustNewConstMetric(m.desc, prometheus.GaugeValue, float64(s.Amount), s.PointID.String(), string(s.Type))
+	}
+}
@@ -0,0 +1,235 @@
+package repo_api_key
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/repository"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/4udiwe/avito-pvz/pkg/postgres"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/samber/lo"
+)
+
+type Repository struct {
+	*postgres.Postgres
+}
+
+func New(pg *postgres.Postgres) *Repository {
+	return &Repository{pg}
+}
+
+// Create stores the key with its scopes. Must be called within a transaction.
+func (r *Repository) Create(ctx context.Context, key entity.APIKey) (entity.APIKey, error) {
+	logger.FromContext(ctx).Infof("Creating api key %s for service account %s", key.Prefix, key.ServiceAccountID)
+
+	query, args, _ := r.Builder.
+		Insert("api_keys").
+		Columns("service_account_id", "prefix", "key_hash", "created_by", "expires_at").
+		Values(key.ServiceAccountID, key.Prefix, key.KeyHash, key.CreatedBy, key.ExpiresAt).
+		Suffix("RETURNING id, created_at").
+		ToSql()
+
+	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.ForeignKeyViolation {
+			logger.FromContext(ctx).Warnf("No service account found: %s", key.ServiceAccountID)
+			return entity.APIKey{}, repository.ErrNoServiceAccountFound
+		}
+		logger.FromContext(ctx).Errorf("Failed to create api key for service account %s: %v", key.ServiceAccountID, err)
+		return entity.APIKey{}, fmt.Errorf("APIKeyRepository.Create - Scan: %w", err)
+	}
+
+	query = `
+        INSERT INTO api_key_scopes(api_key_id, permission)
+        SELECT $1, UNNEST($2::text[])
+    `
+	scopes := lo.Map(key.Scopes, func(p entity.Permission, _ int) string { return string(p) })
+	if _, err = r.GetTxManager(ctx).Exec(ctx, query, key.ID, scopes); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.ForeignKeyViolation {
+			logger.FromContext(ctx).Warnf("No permission found among %v", key.Scopes)
+			return entity.APIKey{}, repository.ErrNoPermissionFound
+		}
+		logger.FromContext(ctx).Errorf("Failed to add scopes to api key %s: %v", key.ID, err)
+		return entity.APIKey{}, fmt.Errorf("APIKeyRepository.Create - Exec: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Api key created: %s", key.ID)
+	return key, nil
+}
+
+func (r *Repository) GetByIDForUpdate(ctx context.Context, keyID uuid.UUID) (entity.APIKey, error) {
+	logger.FromContext(ctx).Infof("Fetching api key for update: %s", keyID)
+
+	key, err := r.getOne(ctx, squirrel.Eq{"k.id": keyID}, "FOR UPDATE OF k")
+	if err != nil {
+		if errors.Is(err, repository.ErrNoAPIKeyFound) {
+			logger.FromContext(ctx).Warnf("No api key found: %s", keyID)
+		} else {
+			logger.FromContext(ctx).Errorf("Failed to fetch api key %s: %v", keyID, err)
+		}
+		return entity.APIKey{}, err
+	}
+
+	logger.FromContext(ctx).Infof("Fetched api key %s", keyID)
+	return key, nil
+}
+
+func (r *Repository) GetByHash(ctx context.Context, keyHash string) (entity.APIKey, error) {
+	logger.FromContext(ctx).Info("Fetching api key by hash")
+
+	key, err := r.getOne(ctx, squirrel.Eq{"k.key_hash": keyHash}, "")
+	if err != nil {
+		if errors.Is(err, repository.ErrNoAPIKeyFound) {
+			logger.FromContext(ctx).Warn("No api key found by hash")
+		} else {
+			logger.FromContext(ctx).Errorf("Failed to fetch api key by hash: %v", err)
+		}
+		return entity.APIKey{}, err
+	}
+
+	logger.FromContext(ctx).Infof("Fetched api key %s", key.ID)
+	return key, nil
+}
+
+// GetByServiceAccounts returns the keys of the given accounts, newest first.
+func (r *Repository) GetByServiceAccounts(ctx context.Context, accountIDs []uuid.UUID) ([]entity.APIKey, error) {
+	logger.FromContext(ctx).Infof("Fetching api keys of %d service accounts", len(accountIDs))
+
+	query, args, _ := r.selectKeys().
+		Where("k.service_account_id = ANY(?)", accountIDs).
+		OrderBy("k.created_at DESC").
+		ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to fetch api keys: %v", err)
+		return nil, fmt.Errorf("APIKeyRepository.GetByServiceAccounts - Query: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []entity.APIKey
+	for rows.Next() {
+		key, err := scanKey(rows)
+		if err != nil {
+			logger.FromContext(ctx).Errorf("Failed to scan api key row: %v", err)
+			return nil, fmt.Errorf("APIKeyRepository.GetByServiceAccounts - Scan: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		logger.FromContext(ctx).Errorf("Rows error after fetching api keys: %v", err)
+		return nil, fmt.Errorf("APIKeyRepository.GetByServiceAccounts - rows.Err: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Fetched %d api keys", len(keys))
+	return keys, nil
+}
+
+func (r *Repository) SetExpiresAt(ctx context.Context, keyID uuid.UUID, expiresAt time.Time) error {
+	logger.FromContext(ctx).Infof("Setting expiry of api key %s to %s", keyID, expiresAt)
+
+	return r.update(ctx, "SetExpiresAt", keyID, "expires_at", expiresAt)
+}
+
+func (r *Repository) Revoke(ctx context.Context, keyID uuid.UUID) error {
+	logger.FromContext(ctx).Infof("Revoking api key %s", keyID)
+
+	return r.update(ctx, "Revoke", keyID, "revoked_at", time.Now())
+}
+
+// TouchLastUsed records a use of the key. The row is written only if the
+// previous use is older than since, so busy keys do not cause a write on
+// every request.
+func (r *Repository) TouchLastUsed(ctx context.Context, keyID uuid.UUID, at time.Time, since time.Time) error {
+	query, args, _ := r.Builder.
+		Update("api_keys").
+		Set("last_used_at", at).
+		Where("id = ?", keyID).
+		Where(squirrel.Or{squirrel.Eq{"last_used_at": nil}, squirrel.Lt{"last_used_at": since}}).
+		ToSql()
+
+	if _, err := r.GetTxManager(ctx).Exec(ctx, query, args...); err != nil {
+		logger.FromContext(ctx).Errorf("Failed to update last use of api key %s: %v", keyID, err)
+		return fmt.Errorf("APIKeyRepository.TouchLastUsed - Exec: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) update(ctx context.Context, method string, keyID uuid.UUID, column string, value time.Time) error {
+	query, args, _ := r.Builder.
+		Update("api_keys").
+		Set(column, value).
+		Where("id = ?", keyID).
+		ToSql()
+
+	result, err := r.GetTxManager(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to update api key %s: %v", keyID, err)
+		return fmt.Errorf("APIKeyRepository.%s - Exec: %w", method, err)
+	}
+	if result.RowsAffected() == 0 {
+		logger.FromContext(ctx).Warnf("No api key found: %s", keyID)
+		return repository.ErrNoAPIKeyFound
+	}
+
+	logger.FromContext(ctx).Infof("Api key %s updated", keyID)
+	return nil
+}
+
+func (r *Repository) selectKeys() squirrel.SelectBuilder {
+	return r.Builder.
+		Select(
+			"k.id", "k.service_account_id", "k.prefix", "k.key_hash", "k.created_by", "k.created_at",
+			"k.expires_at", "k.last_used_at", "k.revoked_at",
+			"ARRAY(SELECT s.permission FROM api_key_scopes s WHERE s.api_key_id = k.id ORDER BY s.permission)",
+		).
+		From("api_keys k")
+}
+
+func (r *Repository) getOne(ctx context.Context, where squirrel.Eq, suffix string) (entity.APIKey, error) {
+	query, args, _ := r.selectKeys().Where(where).Suffix(suffix).ToSql()
+
+	key, err := scanKey(r.GetTxManager(ctx).QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.APIKey{}, repository.ErrNoAPIKeyFound
+		}
+		return entity.APIKey{}, fmt.Errorf("APIKeyRepository - Scan: %w", err)
+	}
+	return key, nil
+}
+
+func scanKey(row pgx.Row) (entity.APIKey, error) {
+	var (
+		key    entity.APIKey
+		scopes []string
+	)
+	err := row.Scan(
+		&key.ID,
+		&key.ServiceAccountID,
+		&key.Prefix,
+		&key.KeyHash,
+		&key.CreatedBy,
+		&key.CreatedAt,
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
+		&scopes,
+	)
+	if err != nil {
+		return entity.APIKey{}, err
+	}
+
+	key.Scopes = lo.Map(scopes, func(s string, _ int) entity.Permission { return entity.Permission(s) })
+	return key, nil
+}
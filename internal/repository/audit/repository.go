@@ -0,0 +1,338 @@
+package repo_audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/pkg/hashchain"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/4udiwe/avito-pvz/pkg/postgres"
+	"github.com/4udiwe/avito-pvz/pkg/requestid"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+var auditColumns = []string{
+	"id", "actor_id", "action", "target_type", "target_id", "before", "after", "COALESCE(request_id, '')", "created_at",
+	"COALESCE(seq, 0)", "COALESCE(prev_hash, '')", "COALESCE(hash, '')",
+}
+
+type Repository struct {
+	*postgres.Postgres
+}
+
+func New(pg *postgres.Postgres) *Repository {
+	return &Repository{pg}
+}
+
+// auditChainLock serializes the sealers of the audit chain across instances.
+// Writers of records never take it.
+const auditChainLock int64 = 0x61756469
+
+// Create writes the record in the caller's transaction, so it is stored
+// only together with the change it describes. Without a RequestID the one
+// of the current request is used. The record is written unsealed: it joins
+// the hash chain when the audit service seals it after the commit, so
+// business transactions do not wait for each other on the chain.
+func (r *Repository) Create(ctx context.Context, record entity.AuditRecord) error {
+	logger.FromContext(ctx).Infof("Writing audit record %s on %s %s by %s", record.Action, record.TargetType, record.TargetID, record.ActorID)
+
+	if record.RequestID == "" {
+		record.RequestID = requestid.FromContext(ctx)
+	}
+
+	query, args, _ := r.Builder.
+		Insert("audit_log").
+		Columns("id", "actor_id", "action", "target_type", "target_id", "before", "after", "request_id", "created_at").
+		Values(
+			uuid.New(), record.ActorID, record.Action, record.TargetType, record.TargetID,
+			nullJSON(record.Before), nullJSON(record.After), nullString(record.RequestID),
+			time.Now().UTC().Truncate(time.Microsecond),
+		).
+		ToSql()
+
+	if _, err := r.GetTxManager(ctx).Exec(ctx, query, args...); err != nil {
+		logger.FromContext(ctx).Errorf("Failed to write audit record %s: %v", record.Action, err)
+		return fmt.Errorf("AuditRepository.Create - Exec: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Audit record %s written", record.Action)
+	return nil
+}
+
+// LockChain makes the caller the only sealer of the chain until its
+// transaction ends.
+func (r *Repository) LockChain(ctx context.Context) error {
+	if _, err := r.GetTxManager(ctx).Exec(ctx, "SELECT pg_advisory_xact_lock($1)", auditChainLock); err != nil {
+		logger.FromContext(ctx).Errorf("Failed to lock audit chain: %v", err)
+		return fmt.Errorf("AuditRepository.LockChain - Exec: %w", err)
+	}
+	return nil
+}
+
+// ListUnsealed returns up to limit committed records that are not in the
+// chain yet, oldest first.
+func (r *Repository) ListUnsealed(ctx context.Context, limit int) ([]entity.AuditRecord, error) {
+	query, args, _ := r.Builder.
+		Select(auditColumns...).
+		From("audit_log").
+		Where("seq IS NULL").
+		OrderBy("created_at", "id").
+		Limit(uint64(limit)).
+		ToSql()
+
+	var records []entity.AuditRecord
+	err := r.query(ctx, query, args, func(record entity.AuditRecord) error {
+		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to fetch unsealed audit records: %v", err)
+		return nil, fmt.Errorf("AuditRepository.ListUnsealed - %w", err)
+	}
+	return records, nil
+}
+
+// Seal stores the chain position and hashes of a record.
+func (r *Repository) Seal(ctx context.Context, record entity.AuditRecord) error {
+	query, args, _ := r.Builder.
+		Update("audit_log").
+		Set("seq", record.Seq).
+		Set("prev_hash", record.PrevHash).
+		Set("hash", record.Hash).
+		Where("id = ?", record.ID).
+		Where("seq IS NULL").
+		ToSql()
+
+	tag, err := r.GetTxManager(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to seal audit record %s: %v", record.ID, err)
+		return fmt.Errorf("AuditRepository.Seal - Exec: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("AuditRepository.Seal - record %s is already sealed", record.ID)
+	}
+	return nil
+}
+
+// GetChainHead returns the position and hash of the last chained record,
+// or zero and the genesis hash for an empty chain.
+func (r *Repository) GetChainHead(ctx context.Context) (entity.AuditCheckpoint, error) {
+	query, args, _ := r.Builder.
+		Select("seq", "hash").
+		From("audit_log").
+		Where("seq IS NOT NULL").
+		OrderBy("seq DESC").
+		Limit(1).
+		ToSql()
+
+	head := entity.AuditCheckpoint{Hash: hashchain.Genesis}
+	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(&head.Seq, &head.Hash)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		logger.FromContext(ctx).Errorf("Failed to fetch audit chain head: %v", err)
+		return entity.AuditCheckpoint{}, fmt.Errorf("AuditRepository.GetChainHead - Scan: %w", err)
+	}
+	return head, nil
+}
+
+func (r *Repository) List(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditRecord, error) {
+	logger.FromContext(ctx).Infof("Fetching audit records: %+v", filter)
+
+	query, args, _ := applyFilter(r.Builder.Select(auditColumns...).From("audit_log"), filter).
+		OrderBy("created_at DESC", "id DESC").
+		Limit(uint64(filter.Limit)).
+		Offset(uint64((filter.Page - 1) * filter.Limit)).
+		ToSql()
+
+	var records []entity.AuditRecord
+	err := r.query(ctx, query, args, func(record entity.AuditRecord) error {
+		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to fetch audit records: %v", err)
+		return nil, fmt.Errorf("AuditRepository.List - %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Fetched %d audit records", len(records))
+	return records, nil
+}
+
+// ForEach streams every matching record, oldest first, from a single
+// query: an export neither holds the whole log in memory nor skips or
+// repeats records written meanwhile. Page and Limit are ignored.
+func (r *Repository) ForEach(ctx context.Context, filter entity.AuditFilter, fn func(entity.AuditRecord) error) error {
+	logger.FromContext(ctx).Infof("Exporting audit records: %+v", filter)
+
+	query, args, _ := applyFilter(r.Builder.Select(auditColumns...).From("audit_log"), filter).
+		OrderBy("created_at", "id").
+		ToSql()
+
+	if err := r.query(ctx, query, args, fn); err != nil {
+		logger.FromContext(ctx).Errorf("Failed to export audit records: %v", err)
+		return fmt.Errorf("AuditRepository.ForEach - %w", err)
+	}
+
+	logger.FromContext(ctx).Info("Audit records exported")
+	return nil
+}
+
+// ForEachInChain streams the chained records in chain order.
+func (r *Repository) ForEachInChain(ctx context.Context, fn func(entity.AuditRecord) error) error {
+	logger.FromContext(ctx).Info("Walking audit chain")
+
+	query, args, _ := r.Builder.
+		Select(auditColumns...).
+		From("audit_log").
+		Where("seq IS NOT NULL").
+		OrderBy("seq").
+		ToSql()
+
+	if err := r.query(ctx, query, args, fn); err != nil {
+		logger.FromContext(ctx).Errorf("Failed to walk audit chain: %v", err)
+		return fmt.Errorf("AuditRepository.ForEachInChain - %w", err)
+	}
+	return nil
+}
+
+// CreateCheckpoint stores a signed chain head. A checkpoint of the same
+// head written by another instance is kept.
+func (r *Repository) CreateCheckpoint(ctx context.Context, checkpoint entity.AuditCheckpoint) error {
+	logger.FromContext(ctx).Infof("Writing audit checkpoint at %d", checkpoint.Seq)
+
+	query, args, _ := r.Builder.
+		Insert("audit_checkpoints").
+		Columns("seq", "hash", "signature").
+		Values(checkpoint.Seq, checkpoint.Hash, checkpoint.Signature).
+		Suffix("ON CONFLICT (seq) DO NOTHING").
+		ToSql()
+
+	if _, err := r.GetTxManager(ctx).Exec(ctx, query, args...); err != nil {
+		logger.FromContext(ctx).Errorf("Failed to write audit checkpoint at %d: %v", checkpoint.Seq, err)
+		return fmt.Errorf("AuditRepository.CreateCheckpoint - Exec: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Audit checkpoint at %d written", checkpoint.Seq)
+	return nil
+}
+
+func (r *Repository) ListCheckpoints(ctx context.Context) ([]entity.AuditCheckpoint, error) {
+	logger.FromContext(ctx).Info("Fetching audit checkpoints")
+
+	query, args, _ := r.Builder.
+		Select("seq", "hash", "signature", "created_at").
+		From("audit_checkpoints").
+		OrderBy("seq").
+		ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to fetch audit checkpoints: %v", err)
+		return nil, fmt.Errorf("AuditRepository.ListCheckpoints - Query: %w", err)
+	}
+	defer rows.Close()
+
+	var checkpoints []entity.AuditCheckpoint
+	for rows.Next() {
+		var checkpoint entity.AuditCheckpoint
+		if err := rows.Scan(&checkpoint.Seq, &checkpoint.Hash, &checkpoint.Signature, &checkpoint.CreatedAt); err != nil {
+			logger.FromContext(ctx).Errorf("Failed to scan audit checkpoint: %v", err)
+			return nil, fmt.Errorf("AuditRepository.ListCheckpoints - Scan: %w", err)
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("AuditRepository.ListCheckpoints - rows.Err: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Fetched %d audit checkpoints", len(checkpoints))
+	return checkpoints, nil
+}
+
+func (r *Repository) query(ctx context.Context, query string, args []any, fn func(entity.AuditRecord) error) error {
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("Query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		record, err := scanRecord(rows)
+		if err != nil {
+			return fmt.Errorf("Scan: %w", err)
+		}
+		if err = fn(record); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows.Err: %w", err)
+	}
+	return nil
+}
+
+func applyFilter(builder squirrel.SelectBuilder, filter entity.AuditFilter) squirrel.SelectBuilder {
+	if filter.ActorID != nil {
+		builder = builder.Where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.Action != nil {
+		builder = builder.Where("action = ?", *filter.Action)
+	}
+	if filter.TargetType != nil {
+		builder = builder.Where("target_type = ?", *filter.TargetType)
+	}
+	if filter.TargetID != nil {
+		builder = builder.Where("target_id = ?", *filter.TargetID)
+	}
+	if filter.RequestID != "" {
+		builder = builder.Where("request_id = ?", filter.RequestID)
+	}
+	if filter.From != nil {
+		builder = builder.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		builder = builder.Where("created_at < ?", *filter.To)
+	}
+	return builder
+}
+
+func scanRecord(row pgx.Row) (entity.AuditRecord, error) {
+	var (
+		record        entity.AuditRecord
+		before, after []byte
+	)
+	err := row.Scan(
+		&record.ID,
+		&record.ActorID,
+		&record.Action,
+		&record.TargetType,
+		&record.TargetID,
+		&before,
+		&after,
+		&record.RequestID,
+		&record.CreatedAt,
+		&record.Seq,
+		&record.PrevHash,
+		&record.Hash,
+	)
+	record.Before, record.After = before, after
+	return record, err
+}
+
+func nullJSON(b []byte) any {
+	if len(b) == 0 {
+		return nil
+	}
+	return string(b)
+}
+
+func nullString(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
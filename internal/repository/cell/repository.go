@@ -0,0 +1,227 @@
+package repo_cell
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/repository"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/4udiwe/avito-pvz/pkg/postgres"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// occupiedExpr counts products physically present in the cell.
+const occupiedExpr = `(
+    SELECT COUNT(*) FROM products p
+    WHERE p.cell_id = c.id AND p.status IN ('received', 'stored')
+)`
+
+var cellColumns = []string{
+	"c.id",
+	"c.point_id",
+	"c.rack",
+	"c.shelf",
+	"c.bin",
+	"c.capacity",
+	"c.product_type",
+	occupiedExpr + " AS occupied",
+	"c.created_at",
+}
+
+type Repository struct {
+	*postgres.Postgres
+}
+
+func New(postgres *postgres.Postgres) *Repository {
+	return &Repository{postgres}
+}
+
+func (r *Repository) Create(ctx context.Context, cell entity.StorageCell) (entity.StorageCell, error) {
+	logger.FromContext(ctx).Infof("Attempting to create storage cell %s/%s/%s for point: %s", cell.Rack, cell.Shelf, cell.Bin, cell.PointID)
+
+	query, args, _ := r.Builder.
+		Insert("storage_cells").
+		Columns("point_id", "rack", "shelf", "bin", "capacity", "product_type").
+		Values(cell.PointID, cell.Rack, cell.Shelf, cell.Bin, cell.Capacity, cell.ProductType).
+		Suffix("RETURNING id, created_at").
+		ToSql()
+
+	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(&cell.ID, &cell.CreatedAt)
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case pgerrcode.UniqueViolation:
+				logger.FromContext(ctx).Warnf("Storage cell %s/%s/%s already exists at point %s", cell.Rack, cell.Shelf, cell.Bin, cell.PointID)
+				return entity.StorageCell{}, repository.ErrCellAlreadyExists
+			case pgerrcode.ForeignKeyViolation:
+				logger.FromContext(ctx).Warnf("No point found: %s", cell.PointID)
+				return entity.StorageCell{}, repository.ErrNoPointFound
+			}
+		}
+		logger.FromContext(ctx).Errorf("Failed to create storage cell: %v", err)
+		return entity.StorageCell{}, fmt.Errorf("CellRepository.Create - Scan: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Storage cell created: %+v", cell)
+	return cell, nil
+}
+
+func (r *Repository) GetAllByPoint(ctx context.Context, pointID uuid.UUID) ([]entity.StorageCell, error) {
+	logger.FromContext(ctx).Infof("Fetching storage cells for point: %s", pointID)
+
+	query, args, _ := r.Builder.
+		Select(cellColumns...).
+		From("storage_cells c").
+		Where("c.point_id = ?", pointID).
+		OrderBy("c.rack", "c.shelf", "c.bin").
+		ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to fetch storage cells for point %s: %v", pointID, err)
+		return nil, fmt.Errorf("CellRepository.GetAllByPoint - Query: %w", err)
+	}
+	defer rows.Close()
+
+	var cells []entity.StorageCell
+	for rows.Next() {
+		var cell entity.StorageCell
+		if err = scanCell(rows, &cell); err != nil {
+			logger.FromContext(ctx).Errorf("Failed to scan storage cell row: %v", err)
+			return nil, fmt.Errorf("CellRepository.GetAllByPoint - rows.Scan: %w", err)
+		}
+		cells = append(cells, cell)
+	}
+
+	if err = rows.Err(); err != nil {
+		logger.FromContext(ctx).Errorf("Rows error after fetching storage cells: %v", err)
+		return nil, fmt.Errorf("CellRepository.GetAllByPoint - rows.Err: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Fetched %d storage cells for point %s", len(cells), pointID)
+	return cells, nil
+}
+
+func (r *Repository) GetByID(ctx context.Context, cellID uuid.UUID) (entity.StorageCell, error) {
+	logger.FromContext(ctx).Infof("Fetching storage cell: %s", cellID)
+
+	query, args, _ := r.Builder.
+		Select(cellColumns...).
+		From("storage_cells c").
+		Where("c.id = ?", cellID).
+		ToSql()
+
+	return r.getOne(ctx, "GetByID", query, args...)
+}
+
+func (r *Repository) GetByIDForUpdate(ctx context.Context, cellID uuid.UUID) (entity.StorageCell, error) {
+	logger.FromContext(ctx).Infof("Fetching storage cell for update: %s", cellID)
+
+	query, args, _ := r.Builder.
+		Select(cellColumns...).
+		From("storage_cells c").
+		Where("c.id = ?", cellID).
+		Suffix("FOR UPDATE").
+		ToSql()
+
+	return r.getOne(ctx, "GetByIDForUpdate", query, args...)
+}
+
+// CountOccupied counts products physically present in the cell. Called
+// after the cell is locked, it sees placements committed while waiting
+// for the lock, which the locking statement's own snapshot misses.
+func (r *Repository) CountOccupied(ctx context.Context, cellID uuid.UUID) (int, error) {
+	logger.FromContext(ctx).Infof("Counting products in storage cell: %s", cellID)
+
+	query, args, _ := r.Builder.
+		Select("COUNT(*)").
+		From("products").
+		Where(squirrel.Eq{"cell_id": cellID}).
+		Where("status IN ('received', 'stored')").
+		ToSql()
+
+	var occupied int
+	if err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(&occupied); err != nil {
+		logger.FromContext(ctx).Errorf("Failed to count products in storage cell: %v", err)
+		return 0, fmt.Errorf("CellRepository.CountOccupied - Scan: %w", err)
+	}
+
+	return occupied, nil
+}
+
+// Suggest returns the emptiest cell of the point that accepts the product
+// type and still has free space, without locking it.
+func (r *Repository) Suggest(ctx context.Context, pointID uuid.UUID, productType entity.ProductType) (entity.StorageCell, error) {
+	logger.FromContext(ctx).Infof("Suggesting storage cell for %s at point: %s", productType, pointID)
+
+	query, args, _ := r.suggestQuery(pointID, productType).ToSql()
+
+	return r.getOne(ctx, "Suggest", query, args...)
+}
+
+// SuggestForUpdate is Suggest that locks the cell until the end of the
+// transaction of ctx.
+func (r *Repository) SuggestForUpdate(ctx context.Context, pointID uuid.UUID, productType entity.ProductType) (entity.StorageCell, error) {
+	logger.FromContext(ctx).Infof("Suggesting storage cell for update for %s at point: %s", productType, pointID)
+
+	query, args, _ := r.suggestQuery(pointID, productType).Suffix("FOR UPDATE").ToSql()
+
+	return r.getOne(ctx, "SuggestForUpdate", query, args...)
+}
+
+func (r *Repository) suggestQuery(pointID uuid.UUID, productType entity.ProductType) squirrel.SelectBuilder {
+	return r.Builder.
+		Select(cellColumns...).
+		From("storage_cells c").
+		Where(squirrel.Eq{"c.point_id": pointID}).
+		Where(squirrel.Or{
+			squirrel.Eq{"c.product_type": nil},
+			squirrel.Eq{"c.product_type": productType},
+		}).
+		Where(occupiedExpr+" < c.capacity").
+		OrderBy(
+			occupiedExpr+"::float / c.capacity ASC",
+			"c.capacity DESC",
+			"c.rack", "c.shelf", "c.bin",
+		).
+		Limit(1)
+}
+
+func (r *Repository) getOne(ctx context.Context, op string, query string, args ...any) (entity.StorageCell, error) {
+	var cell entity.StorageCell
+	err := scanCell(r.GetTxManager(ctx).QueryRow(ctx, query, args...), &cell)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.FromContext(ctx).Warn("No storage cell found")
+			return entity.StorageCell{}, repository.ErrNoCellFound
+		}
+		logger.FromContext(ctx).Errorf("Failed to fetch storage cell: %v", err)
+		return entity.StorageCell{}, fmt.Errorf("CellRepository.%s - Scan: %w", op, err)
+	}
+
+	logger.FromContext(ctx).Infof("Fetched storage cell: %+v", cell)
+	return cell, nil
+}
+
+func scanCell(row pgx.Row, cell *entity.StorageCell) error {
+	return row.Scan(
+		&cell.ID,
+		&cell.PointID,
+		&cell.Rack,
+		&cell.Shelf,
+		&cell.Bin,
+		&cell.Capacity,
+		&cell.ProductType,
+		&cell.Occupied,
+		&cell.CreatedAt,
+	)
+}
@@ -8,9 +8,39 @@ var (
 
 	ErrUserAlreadyExists = errors.New("user already exists")
 	ErrNoUserFound       = errors.New("no user found")
+	ErrNoRoleFound       = errors.New("no role found")
+
+	ErrNoSessionFound = errors.New("no session found")
+
+	ErrNoResetTokenFound = errors.New("no password reset token found")
+
+	ErrNoInvitationFound = errors.New("no invitation found")
+
+	ErrNoOIDCStateFound = errors.New("no oidc login state found")
+	ErrNoIdentityFound  = errors.New("no user identity found")
+
+	ErrNoMFAFound          = errors.New("no mfa found")
+	ErrNoMFAChallengeFound = errors.New("no mfa challenge found")
+	ErrNoRecoveryCodeFound = errors.New("no recovery code found")
+
+	ErrNoServiceAccountFound       = errors.New("no service account found")
+	ErrServiceAccountAlreadyExists = errors.New("service account already exists")
+	ErrNoAPIKeyFound               = errors.New("no api key found")
+	ErrNoPermissionFound           = errors.New("no permission found")
 
 	ErrLastReceptionNotClosed = errors.New("last reception not closed")
 	ErrNoReceptionFound       = errors.New("no reception found")
 
-	ErrNoProductFound = errors.New("no product found")
+	ErrNoProductFound       = errors.New("no product found")
+	ErrBarcodeAlreadyExists = errors.New("barcode already exists")
+
+	ErrNoOrderFound          = errors.New("no order found")
+	ErrOrderAlreadyExists    = errors.New("order already exists")
+	ErrProductsUnavailable   = errors.New("products unavailable")
+	ErrProductAlreadyInOrder = errors.New("product already in order")
+
+	ErrNoTransferFound = errors.New("no transfer found")
+
+	ErrNoCellFound       = errors.New("no storage cell found")
+	ErrCellAlreadyExists = errors.New("storage cell already exists")
 )
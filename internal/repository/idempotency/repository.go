@@ -0,0 +1,122 @@
+package repo_idempotency
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/4udiwe/avito-pvz/pkg/postgres"
+)
+
+type Repository struct {
+	*postgres.Postgres
+}
+
+func New(pg *postgres.Postgres) *Repository {
+	return &Repository{pg}
+}
+
+// Acquire takes the key in the transaction of ctx. A new or expired key is
+// stored without a response and false is returned; a key seen before
+// returns its stored response. A request holding the same key in another
+// transaction makes Acquire wait until it commits or rolls back.
+func (r *Repository) Acquire(ctx context.Context, key entity.IdempotencyKey) (entity.IdempotencyKey, bool, error) {
+	logger.FromContext(ctx).Infof("Acquiring idempotency key %s of %s", key.Key, key.OwnerID)
+
+	query, args, _ := r.Builder.
+		Delete("idempotency_keys").
+		Where("owner_id = ?", key.OwnerID).
+		Where("key = ?", key.Key).
+		Where("expires_at <= NOW()").
+		ToSql()
+
+	if _, err := r.GetTxManager(ctx).Exec(ctx, query, args...); err != nil {
+		logger.FromContext(ctx).Errorf("Failed to drop expired idempotency key %s of %s: %v", key.Key, key.OwnerID, err)
+		return entity.IdempotencyKey{}, false, fmt.Errorf("IdempotencyRepository.Acquire - Delete: %w", err)
+	}
+
+	query, args, _ = r.Builder.
+		Insert("idempotency_keys").
+		Columns("owner_id", "key", "request_hash", "expires_at").
+		Values(key.OwnerID, key.Key, key.RequestHash, key.ExpiresAt).
+		Suffix("ON CONFLICT (owner_id, key) DO NOTHING").
+		ToSql()
+
+	result, err := r.GetTxManager(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to store idempotency key %s of %s: %v", key.Key, key.OwnerID, err)
+		return entity.IdempotencyKey{}, false, fmt.Errorf("IdempotencyRepository.Acquire - Insert: %w", err)
+	}
+	if result.RowsAffected() == 1 {
+		logger.FromContext(ctx).Infof("Idempotency key %s of %s is new", key.Key, key.OwnerID)
+		return key, false, nil
+	}
+
+	query, args, _ = r.Builder.
+		Select("owner_id", "key", "request_hash", "status", "content_type", "body", "created_at", "expires_at").
+		From("idempotency_keys").
+		Where("owner_id = ?", key.OwnerID).
+		Where("key = ?", key.Key).
+		Suffix("FOR UPDATE").
+		ToSql()
+
+	var stored entity.IdempotencyKey
+	err = r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(
+		&stored.OwnerID,
+		&stored.Key,
+		&stored.RequestHash,
+		&stored.Status,
+		&stored.ContentType,
+		&stored.Body,
+		&stored.CreatedAt,
+		&stored.ExpiresAt,
+	)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to fetch idempotency key %s of %s: %v", key.Key, key.OwnerID, err)
+		return entity.IdempotencyKey{}, false, fmt.Errorf("IdempotencyRepository.Acquire - Scan: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Idempotency key %s of %s was used before", key.Key, key.OwnerID)
+	return stored, true, nil
+}
+
+// SaveResponse stores the response of a key taken by Acquire.
+func (r *Repository) SaveResponse(ctx context.Context, key entity.IdempotencyKey) error {
+	logger.FromContext(ctx).Infof("Saving response %d of idempotency key %s of %s", key.Status, key.Key, key.OwnerID)
+
+	query, args, _ := r.Builder.
+		Update("idempotency_keys").
+		Set("status", key.Status).
+		Set("content_type", key.ContentType).
+		Set("body", key.Body).
+		Where("owner_id = ?", key.OwnerID).
+		Where("key = ?", key.Key).
+		ToSql()
+
+	if _, err := r.GetTxManager(ctx).Exec(ctx, query, args...); err != nil {
+		logger.FromContext(ctx).Errorf("Failed to save response of idempotency key %s of %s: %v", key.Key, key.OwnerID, err)
+		return fmt.Errorf("IdempotencyRepository.SaveResponse - Exec: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Response of idempotency key %s of %s saved", key.Key, key.OwnerID)
+	return nil
+}
+
+func (r *Repository) DeleteExpired(ctx context.Context) (int64, error) {
+	logger.FromContext(ctx).Info("Pruning expired idempotency keys")
+
+	query, args, _ := r.Builder.
+		Delete("idempotency_keys").
+		Where("expires_at <= NOW()").
+		ToSql()
+
+	result, err := r.GetTxManager(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to prune idempotency keys: %v", err)
+		return 0, fmt.Errorf("IdempotencyRepository.DeleteExpired - Exec: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Pruned %d expired idempotency keys", result.RowsAffected())
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,69 @@
+package repo_identity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/repository"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/4udiwe/avito-pvz/pkg/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+type Repository struct {
+	*postgres.Postgres
+}
+
+func New(pg *postgres.Postgres) *Repository {
+	return &Repository{pg}
+}
+
+func (r *Repository) Create(ctx context.Context, identity entity.UserIdentity) error {
+	logger.FromContext(ctx).Infof("Linking identity %s at %s to user %s", identity.Subject, identity.Issuer, identity.UserID)
+
+	query, args, _ := r.Builder.
+		Insert("user_identities").
+		Columns("issuer", "subject", "user_id").
+		Values(identity.Issuer, identity.Subject, identity.UserID).
+		ToSql()
+
+	if _, err := r.GetTxManager(ctx).Exec(ctx, query, args...); err != nil {
+		logger.FromContext(ctx).Errorf("Failed to link identity %s at %s: %v", identity.Subject, identity.Issuer, err)
+		return fmt.Errorf("IdentityRepository.Create - Exec: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Identity %s at %s linked", identity.Subject, identity.Issuer)
+	return nil
+}
+
+func (r *Repository) Get(ctx context.Context, issuer string, subject string) (entity.UserIdentity, error) {
+	logger.FromContext(ctx).Infof("Fetching identity %s at %s", subject, issuer)
+
+	query, args, _ := r.Builder.
+		Select("issuer", "subject", "user_id", "created_at").
+		From("user_identities").
+		Where("issuer = ?", issuer).
+		Where("subject = ?", subject).
+		ToSql()
+
+	var identity entity.UserIdentity
+	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(
+		&identity.Issuer,
+		&identity.Subject,
+		&identity.UserID,
+		&identity.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.FromContext(ctx).Infof("No identity %s at %s", subject, issuer)
+			return entity.UserIdentity{}, repository.ErrNoIdentityFound
+		}
+		logger.FromContext(ctx).Errorf("Failed to fetch identity %s at %s: %v", subject, issuer, err)
+		return entity.UserIdentity{}, fmt.Errorf("IdentityRepository.Get - Scan: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Fetched identity %s at %s of user %s", subject, issuer, identity.UserID)
+	return identity, nil
+}
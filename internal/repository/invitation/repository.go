@@ -0,0 +1,154 @@
+package repo_invitation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/repository"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/4udiwe/avito-pvz/pkg/postgres"
+	"github.com/google/uuid"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type Repository struct {
+	*postgres.Postgres
+}
+
+func New(pg *postgres.Postgres) *Repository {
+	return &Repository{pg}
+}
+
+// Create stores the invitation together with its point assignments.
+// Must be called within a transaction when points are given.
+func (r *Repository) Create(ctx context.Context, invitation entity.Invitation) (entity.Invitation, error) {
+	logger.FromContext(ctx).Infof("Creating invitation for %s with role %s", invitation.Email, invitation.Role)
+
+	query, args, _ := r.Builder.
+		Insert("invitations").
+		Columns("email", "role", "code_hash", "created_by", "expires_at").
+		Values(invitation.Email, invitation.Role, invitation.CodeHash, invitation.CreatedBy, invitation.ExpiresAt).
+		Suffix("RETURNING id, created_at").
+		ToSql()
+
+	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(&invitation.ID, &invitation.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.ForeignKeyViolation && pgErr.ConstraintName == "fk_invitations_role" {
+			logger.FromContext(ctx).Warnf("No role found: %s", invitation.Role)
+			return entity.Invitation{}, repository.ErrNoRoleFound
+		}
+		logger.FromContext(ctx).Errorf("Failed to create invitation for %s: %v", invitation.Email, err)
+		return entity.Invitation{}, fmt.Errorf("InvitationRepository.Create - Scan: %w", err)
+	}
+
+	if len(invitation.PointIDs) > 0 {
+		query = `
+            INSERT INTO invitation_points(invitation_id, point_id)
+            SELECT $1, UNNEST($2::uuid[])
+        `
+		if _, err = r.GetTxManager(ctx).Exec(ctx, query, invitation.ID, invitation.PointIDs); err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.ForeignKeyViolation {
+				logger.FromContext(ctx).Warnf("No point found among %v", invitation.PointIDs)
+				return entity.Invitation{}, repository.ErrNoPointFound
+			}
+			logger.FromContext(ctx).Errorf("Failed to assign points to invitation %s: %v", invitation.ID, err)
+			return entity.Invitation{}, fmt.Errorf("InvitationRepository.Create - Exec: %w", err)
+		}
+	}
+
+	logger.FromContext(ctx).Infof("Invitation created: %s", invitation.ID)
+	return invitation, nil
+}
+
+func (r *Repository) GetByHashForUpdate(ctx context.Context, codeHash string) (entity.Invitation, error) {
+	logger.FromContext(ctx).Info("Fetching invitation for update")
+
+	query, args, _ := r.Builder.
+		Select("id", "email", "role", "code_hash", "created_by", "created_at", "expires_at", "used_at", "used_by").
+		From("invitations").
+		Where("code_hash = ?", codeHash).
+		Suffix("FOR UPDATE").
+		ToSql()
+
+	var invitation entity.Invitation
+	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(
+		&invitation.ID,
+		&invitation.Email,
+		&invitation.Role,
+		&invitation.CodeHash,
+		&invitation.CreatedBy,
+		&invitation.CreatedAt,
+		&invitation.ExpiresAt,
+		&invitation.UsedAt,
+		&invitation.UsedBy,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.FromContext(ctx).Warn("No invitation found")
+			return entity.Invitation{}, repository.ErrNoInvitationFound
+		}
+		logger.FromContext(ctx).Errorf("Failed to fetch invitation: %v", err)
+		return entity.Invitation{}, fmt.Errorf("InvitationRepository.GetByHashForUpdate - Scan: %w", err)
+	}
+
+	query, args, _ = r.Builder.
+		Select("point_id").
+		From("invitation_points").
+		Where("invitation_id = ?", invitation.ID).
+		OrderBy("point_id").
+		ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to fetch points of invitation %s: %v", invitation.ID, err)
+		return entity.Invitation{}, fmt.Errorf("InvitationRepository.GetByHashForUpdate - Query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var pointID uuid.UUID
+		if err = rows.Scan(&pointID); err != nil {
+			logger.FromContext(ctx).Errorf("Failed to scan point of invitation %s: %v", invitation.ID, err)
+			return entity.Invitation{}, fmt.Errorf("InvitationRepository.GetByHashForUpdate - rows.Scan: %w", err)
+		}
+		invitation.PointIDs = append(invitation.PointIDs, pointID)
+	}
+	if err = rows.Err(); err != nil {
+		return entity.Invitation{}, fmt.Errorf("InvitationRepository.GetByHashForUpdate - rows.Err: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Fetched invitation %s for %s", invitation.ID, invitation.Email)
+	return invitation, nil
+}
+
+func (r *Repository) MarkUsed(ctx context.Context, invitationID uuid.UUID, userID uuid.UUID) error {
+	logger.FromContext(ctx).Infof("Marking invitation %s used by user %s", invitationID, userID)
+
+	query, args, _ := r.Builder.
+		Update("invitations").
+		Set("used_at", time.Now()).
+		Set("used_by", userID).
+		Where("id = ?", invitationID).
+		Where("used_at IS NULL").
+		ToSql()
+
+	result, err := r.GetTxManager(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to mark invitation %s used: %v", invitationID, err)
+		return fmt.Errorf("InvitationRepository.MarkUsed - Exec: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		logger.FromContext(ctx).Warnf("No unused invitation found: %s", invitationID)
+		return repository.ErrNoInvitationFound
+	}
+
+	logger.FromContext(ctx).Infof("Invitation %s used", invitationID)
+	return nil
+}
@@ -0,0 +1,137 @@
+package repo_login_failure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/4udiwe/avito-pvz/pkg/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+type Repository struct {
+	*postgres.Postgres
+}
+
+func New(pg *postgres.Postgres) *Repository {
+	return &Repository{pg}
+}
+
+// GetForUpdate returns the counter of the key. A key without failures
+// gives a zero counter, not an error.
+func (r *Repository) GetForUpdate(ctx context.Context, scope entity.LoginScope, key string) (entity.LoginFailures, error) {
+	logger.FromContext(ctx).Infof("Fetching login failures for %s %s", scope, key)
+
+	query, args, _ := r.Builder.
+		Select("failures", "last_failure_at", "locked_until").
+		From("login_failures").
+		Where("scope = ?", scope).
+		Where("key = ?", key).
+		Suffix("FOR UPDATE").
+		ToSql()
+
+	out := entity.LoginFailures{Scope: scope, Key: key}
+	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(
+		&out.Failures,
+		&out.LastFailureAt,
+		&out.LockedUntil,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return out, nil
+		}
+		logger.FromContext(ctx).Errorf("Failed to fetch login failures for %s %s: %v", scope, key, err)
+		return entity.LoginFailures{}, fmt.Errorf("LoginFailureRepository.GetForUpdate - Scan: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Fetched %d login failures for %s %s", out.Failures, scope, key)
+	return out, nil
+}
+
+// RecordFailure increments the counter and returns the new value. Failures
+// older than window are forgotten.
+func (r *Repository) RecordFailure(ctx context.Context, scope entity.LoginScope, key string, window time.Duration) (int, error) {
+	logger.FromContext(ctx).Infof("Recording login failure for %s %s", scope, key)
+
+	now := time.Now()
+	query, args, _ := r.Builder.
+		Insert("login_failures").
+		Columns("scope", "key", "failures", "last_failure_at").
+		Values(scope, key, 1, now).
+		Suffix(`ON CONFLICT (scope, key) DO UPDATE SET
+			failures = CASE WHEN login_failures.last_failure_at < ? THEN 1 ELSE login_failures.failures + 1 END,
+			last_failure_at = EXCLUDED.last_failure_at
+			RETURNING failures`, now.Add(-window)).
+		ToSql()
+
+	var failures int
+	if err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(&failures); err != nil {
+		logger.FromContext(ctx).Errorf("Failed to record login failure for %s %s: %v", scope, key, err)
+		return 0, fmt.Errorf("LoginFailureRepository.RecordFailure - Scan: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Login failures for %s %s: %d", scope, key, failures)
+	return failures, nil
+}
+
+func (r *Repository) Lock(ctx context.Context, scope entity.LoginScope, key string, until time.Time) error {
+	logger.FromContext(ctx).Infof("Locking logins for %s %s until %s", scope, key, until)
+
+	query, args, _ := r.Builder.
+		Update("login_failures").
+		Set("locked_until", until).
+		Where("scope = ?", scope).
+		Where("key = ?", key).
+		ToSql()
+
+	if _, err := r.GetTxManager(ctx).Exec(ctx, query, args...); err != nil {
+		logger.FromContext(ctx).Errorf("Failed to lock logins for %s %s: %v", scope, key, err)
+		return fmt.Errorf("LoginFailureRepository.Lock - Exec: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Logins for %s %s locked", scope, key)
+	return nil
+}
+
+func (r *Repository) Reset(ctx context.Context, scope entity.LoginScope, key string) error {
+	logger.FromContext(ctx).Infof("Resetting login failures for %s %s", scope, key)
+
+	query, args, _ := r.Builder.
+		Delete("login_failures").
+		Where("scope = ?", scope).
+		Where("key = ?", key).
+		ToSql()
+
+	if _, err := r.GetTxManager(ctx).Exec(ctx, query, args...); err != nil {
+		logger.FromContext(ctx).Errorf("Failed to reset login failures for %s %s: %v", scope, key, err)
+		return fmt.Errorf("LoginFailureRepository.Reset - Exec: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Login failures for %s %s reset", scope, key)
+	return nil
+}
+
+// DeleteStale removes counters whose last failure is before the given time
+// and that are not locked, so the table does not keep every address and
+// email that ever failed.
+func (r *Repository) DeleteStale(ctx context.Context, before time.Time) (int64, error) {
+	logger.FromContext(ctx).Infof("Deleting login failures older than %s", before)
+
+	query, args, _ := r.Builder.
+		Delete("login_failures").
+		Where("last_failure_at < ?", before).
+		Where("(locked_until IS NULL OR locked_until < NOW())").
+		ToSql()
+
+	tag, err := r.GetTxManager(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to delete stale login failures: %v", err)
+		return 0, fmt.Errorf("LoginFailureRepository.DeleteStale - Exec: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Deleted %d stale login failures", tag.RowsAffected())
+	return tag.RowsAffected(), nil
+}
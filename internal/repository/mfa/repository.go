@@ -0,0 +1,226 @@
+package repo_mfa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/repository"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/4udiwe/avito-pvz/pkg/postgres"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type Repository struct {
+	*postgres.Postgres
+}
+
+func New(pg *postgres.Postgres) *Repository {
+	return &Repository{pg}
+}
+
+func (r *Repository) Get(ctx context.Context, userID uuid.UUID) (entity.UserMFA, error) {
+	return r.get(ctx, userID, "")
+}
+
+func (r *Repository) GetForUpdate(ctx context.Context, userID uuid.UUID) (entity.UserMFA, error) {
+	return r.get(ctx, userID, "FOR UPDATE")
+}
+
+func (r *Repository) get(ctx context.Context, userID uuid.UUID, suffix string) (entity.UserMFA, error) {
+	logger.FromContext(ctx).Infof("Fetching mfa of user %s", userID)
+
+	query, args, _ := r.Builder.
+		Select(
+			"user_id",
+			"COALESCE(secret_encrypted, '')",
+			"COALESCE(pending_secret_encrypted, '')",
+			"last_used_step",
+			"confirmed_at",
+			"created_at",
+		).
+		From("user_mfa").
+		Where("user_id = ?", userID).
+		Suffix(suffix).
+		ToSql()
+
+	var mfa entity.UserMFA
+	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(
+		&mfa.UserID,
+		&mfa.Secret,
+		&mfa.PendingSecret,
+		&mfa.LastUsedStep,
+		&mfa.ConfirmedAt,
+		&mfa.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.FromContext(ctx).Infof("No mfa found for user %s", userID)
+			return entity.UserMFA{}, repository.ErrNoMFAFound
+		}
+		logger.FromContext(ctx).Errorf("Failed to fetch mfa of user %s: %v", userID, err)
+		return entity.UserMFA{}, fmt.Errorf("MFARepository.Get - Scan: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Fetched mfa of user %s", userID)
+	return mfa, nil
+}
+
+// SetPendingSecret starts an enrolment, replacing an unconfirmed one. An
+// enabled secret stays in use until the enrolment is confirmed.
+func (r *Repository) SetPendingSecret(ctx context.Context, userID uuid.UUID, secret string) error {
+	logger.FromContext(ctx).Infof("Setting pending mfa secret of user %s", userID)
+
+	query, args, _ := r.Builder.
+		Insert("user_mfa").
+		Columns("user_id", "pending_secret_encrypted").
+		Values(userID, secret).
+		Suffix("ON CONFLICT (user_id) DO UPDATE SET pending_secret_encrypted = EXCLUDED.pending_secret_encrypted").
+		ToSql()
+
+	if _, err := r.GetTxManager(ctx).Exec(ctx, query, args...); err != nil {
+		logger.FromContext(ctx).Errorf("Failed to set pending mfa secret of user %s: %v", userID, err)
+		return fmt.Errorf("MFARepository.SetPendingSecret - Exec: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Pending mfa secret of user %s set", userID)
+	return nil
+}
+
+// Confirm makes the pending secret the active one. step is the time step
+// of the code that confirmed it, so that code cannot be used again.
+func (r *Repository) Confirm(ctx context.Context, userID uuid.UUID, step int64) error {
+	logger.FromContext(ctx).Infof("Confirming mfa of user %s", userID)
+
+	query, args, _ := r.Builder.
+		Update("user_mfa").
+		Set("secret_encrypted", squirrel.Expr("pending_secret_encrypted")).
+		Set("pending_secret_encrypted", nil).
+		Set("last_used_step", step).
+		Set("confirmed_at", time.Now()).
+		Where("user_id = ?", userID).
+		Where("pending_secret_encrypted IS NOT NULL").
+		ToSql()
+
+	result, err := r.GetTxManager(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to confirm mfa of user %s: %v", userID, err)
+		return fmt.Errorf("MFARepository.Confirm - Exec: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		logger.FromContext(ctx).Warnf("No pending mfa found for user %s", userID)
+		return repository.ErrNoMFAFound
+	}
+
+	logger.FromContext(ctx).Infof("Mfa of user %s confirmed", userID)
+	return nil
+}
+
+func (r *Repository) SetLastUsedStep(ctx context.Context, userID uuid.UUID, step int64) error {
+	logger.FromContext(ctx).Infof("Setting last used mfa step of user %s", userID)
+
+	query, args, _ := r.Builder.
+		Update("user_mfa").
+		Set("last_used_step", step).
+		Where("user_id = ?", userID).
+		ToSql()
+
+	if _, err := r.GetTxManager(ctx).Exec(ctx, query, args...); err != nil {
+		logger.FromContext(ctx).Errorf("Failed to set last used mfa step of user %s: %v", userID, err)
+		return fmt.Errorf("MFARepository.SetLastUsedStep - Exec: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Last used mfa step of user %s set", userID)
+	return nil
+}
+
+// Delete removes the second factor and the recovery codes of the user.
+func (r *Repository) Delete(ctx context.Context, userID uuid.UUID) error {
+	logger.FromContext(ctx).Infof("Deleting mfa of user %s", userID)
+
+	query, args, _ := r.Builder.
+		Delete("mfa_recovery_codes").
+		Where("user_id = ?", userID).
+		ToSql()
+
+	if _, err := r.GetTxManager(ctx).Exec(ctx, query, args...); err != nil {
+		logger.FromContext(ctx).Errorf("Failed to delete recovery codes of user %s: %v", userID, err)
+		return fmt.Errorf("MFARepository.Delete - Exec: %w", err)
+	}
+
+	query, args, _ = r.Builder.
+		Delete("user_mfa").
+		Where("user_id = ?", userID).
+		ToSql()
+
+	result, err := r.GetTxManager(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to delete mfa of user %s: %v", userID, err)
+		return fmt.Errorf("MFARepository.Delete - Exec: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		logger.FromContext(ctx).Warnf("No mfa found for user %s", userID)
+		return repository.ErrNoMFAFound
+	}
+
+	logger.FromContext(ctx).Infof("Mfa of user %s deleted", userID)
+	return nil
+}
+
+// ReplaceRecoveryCodes deletes the user's recovery codes and stores the
+// given hashes instead.
+func (r *Repository) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes []string) error {
+	logger.FromContext(ctx).Infof("Replacing recovery codes of user %s", userID)
+
+	query, args, _ := r.Builder.
+		Delete("mfa_recovery_codes").
+		Where("user_id = ?", userID).
+		ToSql()
+
+	if _, err := r.GetTxManager(ctx).Exec(ctx, query, args...); err != nil {
+		logger.FromContext(ctx).Errorf("Failed to delete recovery codes of user %s: %v", userID, err)
+		return fmt.Errorf("MFARepository.ReplaceRecoveryCodes - Exec: %w", err)
+	}
+
+	insert := `
+        INSERT INTO mfa_recovery_codes(user_id, code_hash)
+        SELECT $1, UNNEST($2::text[])
+    `
+	if _, err := r.GetTxManager(ctx).Exec(ctx, insert, userID, codeHashes); err != nil {
+		logger.FromContext(ctx).Errorf("Failed to create recovery codes of user %s: %v", userID, err)
+		return fmt.Errorf("MFARepository.ReplaceRecoveryCodes - Exec: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Created %d recovery codes of user %s", len(codeHashes), userID)
+	return nil
+}
+
+// UseRecoveryCode marks an unused code of the user as used.
+func (r *Repository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) error {
+	logger.FromContext(ctx).Infof("Using recovery code of user %s", userID)
+
+	query, args, _ := r.Builder.
+		Update("mfa_recovery_codes").
+		Set("used_at", time.Now()).
+		Where("user_id = ?", userID).
+		Where("code_hash = ?", codeHash).
+		Where("used_at IS NULL").
+		ToSql()
+
+	result, err := r.GetTxManager(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to use recovery code of user %s: %v", userID, err)
+		return fmt.Errorf("MFARepository.UseRecoveryCode - Exec: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		logger.FromContext(ctx).Warnf("No unused recovery code found for user %s", userID)
+		return repository.ErrNoRecoveryCodeFound
+	}
+
+	logger.FromContext(ctx).Infof("Recovery code of user %s used", userID)
+	return nil
+}
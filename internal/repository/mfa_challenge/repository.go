@@ -0,0 +1,121 @@
+package repo_mfa_challenge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/repository"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/4udiwe/avito-pvz/pkg/postgres"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type Repository struct {
+	*postgres.Postgres
+}
+
+func New(pg *postgres.Postgres) *Repository {
+	return &Repository{pg}
+}
+
+// Create stores a challenge and deletes expired ones.
+func (r *Repository) Create(ctx context.Context, challenge entity.MFAChallenge) (entity.MFAChallenge, error) {
+	logger.FromContext(ctx).Infof("Creating mfa challenge for user %s", challenge.UserID)
+
+	query, args, _ := r.Builder.
+		Delete("mfa_challenges").
+		Where("expires_at < NOW()").
+		ToSql()
+
+	if _, err := r.GetTxManager(ctx).Exec(ctx, query, args...); err != nil {
+		logger.FromContext(ctx).Errorf("Failed to delete expired mfa challenges: %v", err)
+		return entity.MFAChallenge{}, fmt.Errorf("MFAChallengeRepository.Create - Exec: %w", err)
+	}
+
+	query, args, _ = r.Builder.
+		Insert("mfa_challenges").
+		Columns("token_hash", "user_id", "expires_at").
+		Values(challenge.TokenHash, challenge.UserID, challenge.ExpiresAt).
+		Suffix("RETURNING id, created_at").
+		ToSql()
+
+	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(&challenge.ID, &challenge.CreatedAt)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to create mfa challenge for user %s: %v", challenge.UserID, err)
+		return entity.MFAChallenge{}, fmt.Errorf("MFAChallengeRepository.Create - Scan: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Mfa challenge created: %s", challenge.ID)
+	return challenge, nil
+}
+
+func (r *Repository) GetByHashForUpdate(ctx context.Context, tokenHash string) (entity.MFAChallenge, error) {
+	logger.FromContext(ctx).Info("Fetching mfa challenge for update")
+
+	query, args, _ := r.Builder.
+		Select("id", "token_hash", "user_id", "attempts", "created_at", "expires_at").
+		From("mfa_challenges").
+		Where("token_hash = ?", tokenHash).
+		Suffix("FOR UPDATE").
+		ToSql()
+
+	var challenge entity.MFAChallenge
+	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(
+		&challenge.ID,
+		&challenge.TokenHash,
+		&challenge.UserID,
+		&challenge.Attempts,
+		&challenge.CreatedAt,
+		&challenge.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.FromContext(ctx).Warn("No mfa challenge found")
+			return entity.MFAChallenge{}, repository.ErrNoMFAChallengeFound
+		}
+		logger.FromContext(ctx).Errorf("Failed to fetch mfa challenge: %v", err)
+		return entity.MFAChallenge{}, fmt.Errorf("MFAChallengeRepository.GetByHashForUpdate - Scan: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Fetched mfa challenge %s of user %s", challenge.ID, challenge.UserID)
+	return challenge, nil
+}
+
+func (r *Repository) IncrementAttempts(ctx context.Context, challengeID uuid.UUID) error {
+	logger.FromContext(ctx).Infof("Incrementing attempts of mfa challenge %s", challengeID)
+
+	query, args, _ := r.Builder.
+		Update("mfa_challenges").
+		Set("attempts", squirrel.Expr("attempts + 1")).
+		Where("id = ?", challengeID).
+		ToSql()
+
+	if _, err := r.GetTxManager(ctx).Exec(ctx, query, args...); err != nil {
+		logger.FromContext(ctx).Errorf("Failed to increment attempts of mfa challenge %s: %v", challengeID, err)
+		return fmt.Errorf("MFAChallengeRepository.IncrementAttempts - Exec: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Attempts of mfa challenge %s incremented", challengeID)
+	return nil
+}
+
+func (r *Repository) Delete(ctx context.Context, challengeID uuid.UUID) error {
+	logger.FromContext(ctx).Infof("Deleting mfa challenge %s", challengeID)
+
+	query, args, _ := r.Builder.
+		Delete("mfa_challenges").
+		Where("id = ?", challengeID).
+		ToSql()
+
+	if _, err := r.GetTxManager(ctx).Exec(ctx, query, args...); err != nil {
+		logger.FromContext(ctx).Errorf("Failed to delete mfa challenge %s: %v", challengeID, err)
+		return fmt.Errorf("MFAChallengeRepository.Delete - Exec: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Mfa challenge %s deleted", challengeID)
+	return nil
+}
@@ -0,0 +1,82 @@
+package repo_oidc_state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/repository"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/4udiwe/avito-pvz/pkg/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+type Repository struct {
+	*postgres.Postgres
+}
+
+func New(pg *postgres.Postgres) *Repository {
+	return &Repository{pg}
+}
+
+// Create stores a started login and deletes expired ones.
+func (r *Repository) Create(ctx context.Context, state entity.OIDCLoginState) error {
+	logger.FromContext(ctx).Info("Creating oidc login state")
+
+	query, args, _ := r.Builder.
+		Delete("oidc_login_states").
+		Where("expires_at < NOW()").
+		ToSql()
+
+	if _, err := r.GetTxManager(ctx).Exec(ctx, query, args...); err != nil {
+		logger.FromContext(ctx).Errorf("Failed to delete expired oidc login states: %v", err)
+		return fmt.Errorf("OIDCStateRepository.Create - Exec: %w", err)
+	}
+
+	query, args, _ = r.Builder.
+		Insert("oidc_login_states").
+		Columns("state_hash", "nonce", "code_verifier", "expires_at").
+		Values(state.StateHash, state.Nonce, state.CodeVerifier, state.ExpiresAt).
+		ToSql()
+
+	if _, err := r.GetTxManager(ctx).Exec(ctx, query, args...); err != nil {
+		logger.FromContext(ctx).Errorf("Failed to create oidc login state: %v", err)
+		return fmt.Errorf("OIDCStateRepository.Create - Exec: %w", err)
+	}
+
+	logger.FromContext(ctx).Info("Oidc login state created")
+	return nil
+}
+
+// Take deletes the state and returns it, so each state completes at most
+// one login.
+func (r *Repository) Take(ctx context.Context, stateHash string) (entity.OIDCLoginState, error) {
+	logger.FromContext(ctx).Info("Taking oidc login state")
+
+	query, args, _ := r.Builder.
+		Delete("oidc_login_states").
+		Where("state_hash = ?", stateHash).
+		Suffix("RETURNING state_hash, nonce, code_verifier, created_at, expires_at").
+		ToSql()
+
+	var state entity.OIDCLoginState
+	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(
+		&state.StateHash,
+		&state.Nonce,
+		&state.CodeVerifier,
+		&state.CreatedAt,
+		&state.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.FromContext(ctx).Warn("No oidc login state found")
+			return entity.OIDCLoginState{}, repository.ErrNoOIDCStateFound
+		}
+		logger.FromContext(ctx).Errorf("Failed to take oidc login state: %v", err)
+		return entity.OIDCLoginState{}, fmt.Errorf("OIDCStateRepository.Take - Scan: %w", err)
+	}
+
+	logger.FromContext(ctx).Info("Oidc login state taken")
+	return state, nil
+}
@@ -0,0 +1,245 @@
+package repo_order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/repository"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/4udiwe/avito-pvz/pkg/postgres"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type Repository struct {
+	*postgres.Postgres
+}
+
+func New(postgres *postgres.Postgres) *Repository {
+	return &Repository{postgres}
+}
+
+var orderColumns = []string{
+	"id",
+	"point_id",
+	"number",
+	"customer_contact",
+	"status",
+	"COALESCE(pickup_code_hash, '')",
+	"code_expires_at",
+	"failed_attempts",
+	"locked_until",
+	"created_at",
+	"issued_at",
+}
+
+func scanOrder(row pgx.Row, order *entity.Order) error {
+	return row.Scan(
+		&order.ID,
+		&order.PointID,
+		&order.Number,
+		&order.CustomerContact,
+		&order.Status,
+		&order.PickupCodeHash,
+		&order.CodeExpiresAt,
+		&order.FailedAttempts,
+		&order.LockedUntil,
+		&order.CreatedAt,
+		&order.IssuedAt,
+	)
+}
+
+func (r *Repository) Create(ctx context.Context, order entity.Order) (entity.Order, error) {
+	logger.FromContext(ctx).Infof("Attempting to create order %s for point: %s", order.Number, order.PointID)
+
+	query, args, _ := r.Builder.
+		Insert("orders").
+		Columns("point_id", "number", "customer_contact").
+		Values(order.PointID, order.Number, order.CustomerContact).
+		Suffix("RETURNING id, status, created_at").
+		ToSql()
+
+	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(&order.ID, &order.Status, &order.CreatedAt)
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			if pgErr.Code == pgerrcode.UniqueViolation {
+				logger.FromContext(ctx).Warnf("Order already exists: %s", order.Number)
+				return entity.Order{}, repository.ErrOrderAlreadyExists
+			}
+			if pgErr.Code == pgerrcode.ForeignKeyViolation {
+				logger.FromContext(ctx).Warnf("No point found: %s", order.PointID)
+				return entity.Order{}, repository.ErrNoPointFound
+			}
+		}
+		logger.FromContext(ctx).Errorf("Failed to create order %s: %v", order.Number, err)
+		return entity.Order{}, fmt.Errorf("OrderRepository.Create - Scan: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Order created: %s", order.ID)
+	return order, nil
+}
+
+// AttachProducts links products to the order. Only products that are physically
+// at the order's point and not yet handed out can be attached.
+func (r *Repository) AttachProducts(ctx context.Context, orderID uuid.UUID, pointID uuid.UUID, productIDs []uuid.UUID) error {
+	logger.FromContext(ctx).Infof("Attaching %d products to order %s", len(productIDs), orderID)
+
+	query := `
+        INSERT INTO order_products(order_id, product_id)
+        SELECT $1, p.id
+        FROM products p
+        WHERE p.id = ANY($2) AND p.point_id = $3 AND p.status IN ('received', 'stored')
+    `
+	result, err := r.GetTxManager(ctx).Exec(ctx, query, orderID, productIDs, pointID)
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			logger.FromContext(ctx).Warnf("Some products are already in another order: %v", productIDs)
+			return repository.ErrProductAlreadyInOrder
+		}
+		logger.FromContext(ctx).Errorf("Failed to attach products to order %s: %v", orderID, err)
+		return fmt.Errorf("OrderRepository.AttachProducts - Exec: %w", err)
+	}
+
+	if int(result.RowsAffected()) != len(productIDs) {
+		logger.FromContext(ctx).Warnf("Only %d of %d products are available for order %s", result.RowsAffected(), len(productIDs), orderID)
+		return repository.ErrProductsUnavailable
+	}
+
+	logger.FromContext(ctx).Infof("Attached %d products to order %s", len(productIDs), orderID)
+	return nil
+}
+
+func (r *Repository) GetByIDForUpdate(ctx context.Context, orderID uuid.UUID) (entity.Order, error) {
+	logger.FromContext(ctx).Infof("Fetching order for update: %s", orderID)
+
+	query, args, _ := r.Builder.
+		Select(orderColumns...).
+		From("orders").
+		Where("id = ?", orderID).
+		Suffix("FOR UPDATE").
+		ToSql()
+
+	return r.getOrder(ctx, query, args...)
+}
+
+func (r *Repository) GetByNumber(ctx context.Context, number string) (entity.Order, error) {
+	logger.FromContext(ctx).Infof("Fetching order by number: %s", number)
+
+	query, args, _ := r.Builder.
+		Select(orderColumns...).
+		From("orders").
+		Where("number = ?", number).
+		ToSql()
+
+	return r.getOrder(ctx, query, args...)
+}
+
+func (r *Repository) getOrder(ctx context.Context, query string, args ...any) (entity.Order, error) {
+	var order entity.Order
+	if err := scanOrder(r.GetTxManager(ctx).QueryRow(ctx, query, args...), &order); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.FromContext(ctx).Warn("No order found")
+			return entity.Order{}, repository.ErrNoOrderFound
+		}
+		logger.FromContext(ctx).Errorf("Failed to fetch order: %v", err)
+		return entity.Order{}, fmt.Errorf("OrderRepository.getOrder - Scan: %w", err)
+	}
+
+	query, args, _ = r.Builder.
+		Select("product_id").
+		From("order_products").
+		Where("order_id = ?", order.ID).
+		OrderBy("product_id").
+		ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to fetch products of order %s: %v", order.ID, err)
+		return entity.Order{}, fmt.Errorf("OrderRepository.getOrder - Query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID uuid.UUID
+		if err := rows.Scan(&productID); err != nil {
+			logger.FromContext(ctx).Errorf("Failed to scan order product row: %v", err)
+			return entity.Order{}, fmt.Errorf("OrderRepository.getOrder - rows.Scan: %w", err)
+		}
+		order.ProductIDs = append(order.ProductIDs, productID)
+	}
+	if err := rows.Err(); err != nil {
+		logger.FromContext(ctx).Errorf("Rows error after fetching order products: %v", err)
+		return entity.Order{}, fmt.Errorf("OrderRepository.getOrder - rows.Err: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Fetched order %s with %d products", order.ID, len(order.ProductIDs))
+	return order, nil
+}
+
+func (r *Repository) SetPickupCode(ctx context.Context, orderID uuid.UUID, codeHash string, expiresAt time.Time) error {
+	logger.FromContext(ctx).Infof("Setting pickup code for order: %s", orderID)
+
+	query, args, _ := r.Builder.
+		Update("orders").
+		Set("status", entity.OrderStatusReady).
+		Set("pickup_code_hash", codeHash).
+		Set("code_expires_at", expiresAt).
+		Set("failed_attempts", 0).
+		Set("locked_until", nil).
+		Where("id = ?", orderID).
+		ToSql()
+
+	return r.exec(ctx, "SetPickupCode", orderID, query, args...)
+}
+
+func (r *Repository) UpdateAttempts(ctx context.Context, orderID uuid.UUID, failedAttempts int, lockedUntil *time.Time) error {
+	logger.FromContext(ctx).Infof("Updating failed pickup attempts for order %s: %d", orderID, failedAttempts)
+
+	query, args, _ := r.Builder.
+		Update("orders").
+		Set("failed_attempts", failedAttempts).
+		Set("locked_until", lockedUntil).
+		Where("id = ?", orderID).
+		ToSql()
+
+	return r.exec(ctx, "UpdateAttempts", orderID, query, args...)
+}
+
+func (r *Repository) MarkIssued(ctx context.Context, orderID uuid.UUID) error {
+	logger.FromContext(ctx).Infof("Marking order as issued: %s", orderID)
+
+	query, args, _ := r.Builder.
+		Update("orders").
+		Set("status", entity.OrderStatusIssued).
+		Set("pickup_code_hash", nil).
+		Set("issued_at", squirrel.Expr("NOW()")).
+		Where("id = ?", orderID).
+		ToSql()
+
+	return r.exec(ctx, "MarkIssued", orderID, query, args...)
+}
+
+func (r *Repository) exec(ctx context.Context, op string, orderID uuid.UUID, query string, args ...any) error {
+	result, err := r.GetTxManager(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to update order %s: %v", orderID, err)
+		return fmt.Errorf("OrderRepository.%s - Exec: %w", op, err)
+	}
+	if result.RowsAffected() == 0 {
+		logger.FromContext(ctx).Warnf("No order found to update: %s", orderID)
+		return repository.ErrNoOrderFound
+	}
+
+	logger.FromContext(ctx).Infof("Order %s updated", orderID)
+	return nil
+}
@@ -0,0 +1,120 @@
+package repo_password_reset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/repository"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/4udiwe/avito-pvz/pkg/postgres"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type Repository struct {
+	*postgres.Postgres
+}
+
+func New(pg *postgres.Postgres) *Repository {
+	return &Repository{pg}
+}
+
+func (r *Repository) Create(ctx context.Context, token entity.PasswordResetToken) (entity.PasswordResetToken, error) {
+	logger.FromContext(ctx).Infof("Creating password reset token for user %s", token.UserID)
+
+	query, args, _ := r.Builder.
+		Insert("password_reset_tokens").
+		Columns("user_id", "token_hash", "expires_at").
+		Values(token.UserID, token.TokenHash, token.ExpiresAt).
+		Suffix("RETURNING id, created_at").
+		ToSql()
+
+	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(&token.ID, &token.CreatedAt)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to create password reset token for user %s: %v", token.UserID, err)
+		return entity.PasswordResetToken{}, fmt.Errorf("PasswordResetRepository.Create - Scan: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Password reset token created: %s", token.ID)
+	return token, nil
+}
+
+// InvalidateByUser marks all unused tokens of the user as used, so only the
+// latest requested token is valid.
+func (r *Repository) InvalidateByUser(ctx context.Context, userID uuid.UUID) error {
+	logger.FromContext(ctx).Infof("Invalidating password reset tokens of user %s", userID)
+
+	query, args, _ := r.Builder.
+		Update("password_reset_tokens").
+		Set("used_at", time.Now()).
+		Where("user_id = ?", userID).
+		Where("used_at IS NULL").
+		ToSql()
+
+	if _, err := r.GetTxManager(ctx).Exec(ctx, query, args...); err != nil {
+		logger.FromContext(ctx).Errorf("Failed to invalidate password reset tokens of user %s: %v", userID, err)
+		return fmt.Errorf("PasswordResetRepository.InvalidateByUser - Exec: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Password reset tokens of user %s invalidated", userID)
+	return nil
+}
+
+func (r *Repository) GetByHashForUpdate(ctx context.Context, tokenHash string) (entity.PasswordResetToken, error) {
+	logger.FromContext(ctx).Info("Fetching password reset token for update")
+
+	query, args, _ := r.Builder.
+		Select("id", "user_id", "token_hash", "created_at", "expires_at", "used_at").
+		From("password_reset_tokens").
+		Where("token_hash = ?", tokenHash).
+		Suffix("FOR UPDATE").
+		ToSql()
+
+	var token entity.PasswordResetToken
+	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.TokenHash,
+		&token.CreatedAt,
+		&token.ExpiresAt,
+		&token.UsedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.FromContext(ctx).Warn("No password reset token found")
+			return entity.PasswordResetToken{}, repository.ErrNoResetTokenFound
+		}
+		logger.FromContext(ctx).Errorf("Failed to fetch password reset token: %v", err)
+		return entity.PasswordResetToken{}, fmt.Errorf("PasswordResetRepository.GetByHashForUpdate - Scan: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Fetched password reset token %s of user %s", token.ID, token.UserID)
+	return token, nil
+}
+
+func (r *Repository) MarkUsed(ctx context.Context, tokenID uuid.UUID) error {
+	logger.FromContext(ctx).Infof("Marking password reset token used: %s", tokenID)
+
+	query, args, _ := r.Builder.
+		Update("password_reset_tokens").
+		Set("used_at", time.Now()).
+		Where("id = ?", tokenID).
+		Where("used_at IS NULL").
+		ToSql()
+
+	result, err := r.GetTxManager(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to mark password reset token %s used: %v", tokenID, err)
+		return fmt.Errorf("PasswordResetRepository.MarkUsed - Exec: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		logger.FromContext(ctx).Warnf("No unused password reset token found: %s", tokenID)
+		return repository.ErrNoResetTokenFound
+	}
+
+	logger.FromContext(ctx).Infof("Password reset token %s used", tokenID)
+	return nil
+}
@@ -7,9 +7,11 @@ import (
 
 	"github.com/4udiwe/avito-pvz/internal/entity"
 	repo "github.com/4udiwe/avito-pvz/internal/repository"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
 	"github.com/4udiwe/avito-pvz/pkg/postgres"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
-	"github.com/sirupsen/logrus"
 )
 
 type Repository struct {
@@ -21,7 +23,7 @@ func New(postgres *postgres.Postgres) *Repository {
 }
 
 func (r *Repository) Create(ctx context.Context, city string) (entity.Point, error) {
-	logrus.Infof("Attempting to create point for city: %s", city)
+	logger.FromContext(ctx).Infof("Attempting to create point for city: %s", city)
 
 	cityQuery := r.Builder.
 		Select("id").
@@ -43,19 +45,19 @@ func (r *Repository) Create(ctx context.Context, city string) (entity.Point, err
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			logrus.Warnf("No city found with name: %s", city)
+			logger.FromContext(ctx).Warnf("No city found with name: %s", city)
 			return entity.Point{}, repo.ErrNoCityFound
 		}
-		logrus.Errorf("Failed to create point for city %s: %v", city, err)
+		logger.FromContext(ctx).Errorf("Failed to create point for city %s: %v", city, err)
 		return entity.Point{}, fmt.Errorf("PointRepository.Create - QueryRow: %w", err)
 	}
 
-	logrus.Infof("Point created: %+v", point)
+	logger.FromContext(ctx).Infof("Point created: %+v", point)
 	return point, nil
 }
 
 func (r *Repository) GetAll(ctx context.Context) ([]entity.Point, error) {
-	logrus.Info("Fetching all points")
+	logger.FromContext(ctx).Info("Fetching all points")
 
 	sql, args, _ := r.Builder.
 		Select("points.id, created_at, cities.name AS city").
@@ -65,7 +67,7 @@ func (r *Repository) GetAll(ctx context.Context) ([]entity.Point, error) {
 
 	rows, err := r.GetTxManager(ctx).Query(ctx, sql, args...)
 	if err != nil {
-		logrus.Errorf("Failed to fetch points: %v", err)
+		logger.FromContext(ctx).Errorf("Failed to fetch points: %v", err)
 		return nil, fmt.Errorf("PointRepository.GetAll - Query: %w", err)
 	}
 	defer rows.Close()
@@ -74,7 +76,7 @@ func (r *Repository) GetAll(ctx context.Context) ([]entity.Point, error) {
 	for rows.Next() {
 		var point entity.Point
 		if err = rows.Scan(&point.ID, &point.CreatedAt, &point.City); err != nil {
-			logrus.Errorf("Failed to scan point row: %v", err)
+			logger.FromContext(ctx).Errorf("Failed to scan point row: %v", err)
 			return nil, fmt.Errorf("PointRepository.GetAll - rows.Scan: %w", err)
 		}
 
@@ -82,10 +84,187 @@ func (r *Repository) GetAll(ctx context.Context) ([]entity.Point, error) {
 	}
 
 	if err = rows.Err(); err != nil {
-		logrus.Errorf("Rows error after fetching points: %v", err)
+		logger.FromContext(ctx).Errorf("Rows error after fetching points: %v", err)
 		return nil, fmt.Errorf("PointRepository.GetAll - rows.Err: %w", err)
 	}
 
-	logrus.Infof("Fetched %d points", len(points))
+	logger.FromContext(ctx).Infof("Fetched %d points", len(points))
 	return points, nil
 }
+
+// List returns a page of points; see entity.PointFilter.
+func (r *Repository) List(ctx context.Context, filter entity.PointFilter) ([]entity.Point, error) {
+	logger.FromContext(ctx).Infof("Fetching points: %+v", filter)
+
+	builder := r.Builder.
+		Select("points.id, points.created_at, cities.name AS city").
+		From("points").
+		InnerJoin("cities ON cities.id = points.city_id")
+
+	if filter.StartDate != nil || filter.EndDate != nil {
+		// The subquery keeps ? placeholders: the outer query numbers them
+		receptions := squirrel.Select("1").From("receptions").Where("receptions.point_id = points.id")
+		if filter.StartDate != nil {
+			receptions = receptions.Where("receptions.created_at >= ?", *filter.StartDate)
+		}
+		if filter.EndDate != nil {
+			receptions = receptions.Where("receptions.created_at <= ?", *filter.EndDate)
+		}
+		builder = builder.Where(squirrel.Expr("EXISTS (?)", receptions))
+	}
+
+	sql, args, err := builder.
+		OrderBy("points.created_at ASC", "points.id ASC").
+		Limit(uint64(filter.Limit)).
+		Offset(uint64((filter.Page - 1) * filter.Limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("PointRepository.List - ToSql: %w", err)
+	}
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to fetch points: %v", err)
+		return nil, fmt.Errorf("PointRepository.List - Query: %w", err)
+	}
+	defer rows.Close()
+
+	var points []entity.Point
+	for rows.Next() {
+		var point entity.Point
+		if err = rows.Scan(&point.ID, &point.CreatedAt, &point.City); err != nil {
+			logger.FromContext(ctx).Errorf("Failed to scan point row: %v", err)
+			return nil, fmt.Errorf("PointRepository.List - rows.Scan: %w", err)
+		}
+		points = append(points, point)
+	}
+
+	if err = rows.Err(); err != nil {
+		logger.FromContext(ctx).Errorf("Rows error after fetching points: %v", err)
+		return nil, fmt.Errorf("PointRepository.List - rows.Err: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Fetched %d points", len(points))
+	return points, nil
+}
+
+// GetStock returns stock of the point for every product type, including zero amounts.
+func (r *Repository) GetStock(ctx context.Context, pointID uuid.UUID) ([]entity.Stock, error) {
+	logger.FromContext(ctx).Infof("Fetching stock for point: %s", pointID)
+
+	query := `
+        SELECT t.type, COALESCE(s.amount, 0)
+        FROM points p
+        CROSS JOIN unnest(enum_range(NULL::product_type)) AS t(type)
+        LEFT JOIN point_stock s ON s.point_id = p.id AND s.type = t.type
+        WHERE p.id = $1
+        ORDER BY t.type
+    `
+	stock, err := r.queryStock(ctx, "GetStock", query, pointID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(stock) == 0 {
+		logger.FromContext(ctx).Warnf("No point found: %s", pointID)
+		return nil, repo.ErrNoPointFound
+	}
+
+	for i := range stock {
+		stock[i].PointID = pointID
+	}
+
+	logger.FromContext(ctx).Infof("Fetched stock for point %s", pointID)
+	return stock, nil
+}
+
+// GetStockByCity returns stock summed over all points of the city for every product type.
+func (r *Repository) GetStockByCity(ctx context.Context, city string) ([]entity.Stock, error) {
+	logger.FromContext(ctx).Infof("Fetching stock for city: %s", city)
+
+	query := `
+        SELECT t.type, COALESCE(SUM(s.amount), 0)
+        FROM cities c
+        CROSS JOIN unnest(enum_range(NULL::product_type)) AS t(type)
+        LEFT JOIN points p ON p.city_id = c.id
+        LEFT JOIN point_stock s ON s.point_id = p.id AND s.type = t.type
+        WHERE c.name = $1
+        GROUP BY t.type
+        ORDER BY t.type
+    `
+	stock, err := r.queryStock(ctx, "GetStockByCity", query, city)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(stock) == 0 {
+		logger.FromContext(ctx).Warnf("No city found with name: %s", city)
+		return nil, repo.ErrNoCityFound
+	}
+
+	logger.FromContext(ctx).Infof("Fetched stock for city %s", city)
+	return stock, nil
+}
+
+// GetAllStock returns non-empty stock counters of all points.
+func (r *Repository) GetAllStock(ctx context.Context) ([]entity.Stock, error) {
+	logger.FromContext(ctx).Info("Fetching stock for all points")
+
+	query, args, _ := r.Builder.
+		Select("point_id", "type", "amount").
+		From("point_stock").
+		Where("amount > 0").
+		ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to fetch stock: %v", err)
+		return nil, fmt.Errorf("PointRepository.GetAllStock - Query: %w", err)
+	}
+	defer rows.Close()
+
+	var stock []entity.Stock
+	for rows.Next() {
+		var s entity.Stock
+		if err = rows.Scan(&s.PointID, &s.Type, &s.Amount); err != nil {
+			logger.FromContext(ctx).Errorf("Failed to scan stock row: %v", err)
+			return nil, fmt.Errorf("PointRepository.GetAllStock - rows.Scan: %w", err)
+		}
+		stock = append(stock, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		logger.FromContext(ctx).Errorf("Rows error after fetching stock: %v", err)
+		return nil, fmt.Errorf("PointRepository.GetAllStock - rows.Err: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Fetched %d stock counters", len(stock))
+	return stock, nil
+}
+
+// queryStock scans (type, amount) rows.
+func (r *Repository) queryStock(ctx context.Context, op string, query string, args ...any) ([]entity.Stock, error) {
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to fetch stock: %v", err)
+		return nil, fmt.Errorf("PointRepository.%s - Query: %w", op, err)
+	}
+	defer rows.Close()
+
+	var stock []entity.Stock
+	for rows.Next() {
+		var s entity.Stock
+		if err = rows.Scan(&s.Type, &s.Amount); err != nil {
+			logger.FromContext(ctx).Errorf("Failed to scan stock row: %v", err)
+			return nil, fmt.Errorf("PointRepository.%s - rows.Scan: %w", op, err)
+		}
+		stock = append(stock, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		logger.FromContext(ctx).Errorf("Rows error after fetching stock: %v", err)
+		return nil, fmt.Errorf("PointRepository.%s - rows.Err: %w", op, err)
+	}
+
+	return stock, nil
+}
@@ -4,16 +4,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/4udiwe/avito-pvz/internal/entity"
 	"github.com/4udiwe/avito-pvz/internal/repository"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
 	"github.com/4udiwe/avito-pvz/pkg/postgres"
 	"github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
+	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5"
-	"github.com/sirupsen/logrus"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
+var productColumns = []string{"id", "reception_id", "type", "created_at", "status", "point_id", "barcode", "cell_id"}
+
 type Repository struct {
 	*postgres.Postgres
 }
@@ -22,8 +27,8 @@ func New(postgres *postgres.Postgres) *Repository {
 	return &Repository{postgres}
 }
 
-func (r *Repository) Create(ctx context.Context, pointID uuid.UUID, productType entity.ProductType) (entity.Product, error) {
-	logrus.Infof("Attempting to create product of type %s for point: %s", productType, pointID)
+func (r *Repository) Create(ctx context.Context, pointID uuid.UUID, productType entity.ProductType, barcode string) (entity.Product, error) {
+	logger.FromContext(ctx).Infof("Attempting to create product of type %s for point: %s", productType, pointID)
 
 	query, args, _ := r.Builder.
 		Select("id").
@@ -37,37 +42,49 @@ func (r *Repository) Create(ctx context.Context, pointID uuid.UUID, productType
 	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(&receptionID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			logrus.Warnf("No reception found for point: %s", pointID)
+			logger.FromContext(ctx).Warnf("No reception found for point: %s", pointID)
 			return entity.Product{}, repository.ErrNoReceptionFound
 		}
-		logrus.Errorf("Failed to find reception for point %s: %v", pointID, err)
+		logger.FromContext(ctx).Errorf("Failed to find reception for point %s: %v", pointID, err)
 		return entity.Product{}, fmt.Errorf("ProductRepository.Create - find reception: %w", err)
 	}
 
-	query, args, _ = r.Builder.
-		Insert("products").
-		Columns("reception_id", "type").
-		Values(receptionID, productType).
-		Suffix("RETURNING id, created_at").
-		ToSql()
-
 	product := entity.Product{
 		ReceptionID: receptionID,
 		Type:        productType,
+		PointID:     pointID,
+	}
+	if barcode != "" {
+		product.Barcode = &barcode
 	}
-	err = r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(&product.ID, &product.CreatedAt)
+
+	query, args, _ = r.Builder.
+		Insert("products").
+		Columns("reception_id", "type", "point_id", "barcode").
+		Values(receptionID, productType, pointID, product.Barcode).
+		Suffix("RETURNING id, created_at, status").
+		ToSql()
+
+	err = r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(&product.ID, &product.CreatedAt, &product.Status)
 
 	if err != nil {
-		logrus.Errorf("Failed to create product for reception %s: %v", receptionID, err)
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			logger.FromContext(ctx).Warnf("Product with barcode %s already exists", barcode)
+			return entity.Product{}, repository.ErrBarcodeAlreadyExists
+		}
+		logger.FromContext(ctx).Errorf("Failed to create product for reception %s: %v", receptionID, err)
 		return entity.Product{}, fmt.Errorf("ProductRepository.Create - Scan: %w", err)
 	}
 
-	logrus.Infof("Product created: %+v", product)
+	logger.FromContext(ctx).Infof("Product created: %+v", product)
 	return product, nil
 }
 
-func (r *Repository) DeleteLastFromReception(ctx context.Context, pointID uuid.UUID) error {
-	logrus.Infof("Deleting last product from reception for point: %s", pointID)
+// DeleteLastFromReception deletes the newest product of the point and
+// returns it.
+func (r *Repository) DeleteLastFromReception(ctx context.Context, pointID uuid.UUID) (entity.Product, error) {
+	logger.FromContext(ctx).Infof("Deleting last product from reception for point: %s", pointID)
 
 	query, args, _ := r.Builder.
 		Delete("products").
@@ -78,28 +95,29 @@ func (r *Repository) DeleteLastFromReception(ctx context.Context, pointID uuid.U
 			"ORDER BY p.created_at DESC, p.id DESC "+
 			"LIMIT 1"+
 			")", pointID).
+		Suffix("RETURNING " + strings.Join(productColumns, ", ")).
 		ToSql()
 
-	result, err := r.GetTxManager(ctx).Exec(ctx, query, args...)
-
+	var product entity.Product
+	err := scanProduct(r.GetTxManager(ctx).QueryRow(ctx, query, args...), &product)
 	if err != nil {
-		logrus.Errorf("Failed to delete last product from reception for point %s: %v", pointID, err)
-		return fmt.Errorf("ProductRepository.Delete - Exec: %w", err)
-	}
-	if result.RowsAffected() == 0 {
-		logrus.Warnf("No product found to delete for point: %s", pointID)
-		return repository.ErrNoProductFound
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.FromContext(ctx).Warnf("No product found to delete for point: %s", pointID)
+			return entity.Product{}, repository.ErrNoProductFound
+		}
+		logger.FromContext(ctx).Errorf("Failed to delete last product from reception for point %s: %v", pointID, err)
+		return entity.Product{}, fmt.Errorf("ProductRepository.Delete - Scan: %w", err)
 	}
 
-	logrus.Infof("Deleted last product from reception for point: %s", pointID)
-	return nil
+	logger.FromContext(ctx).Infof("Deleted last product %s from reception for point: %s", product.ID, pointID)
+	return product, nil
 }
 
 func (r *Repository) GetAllByReception(ctx context.Context, receptionID uuid.UUID) ([]entity.Product, error) {
-	logrus.Infof("Fetching all products for reception: %s", receptionID)
+	logger.FromContext(ctx).Infof("Fetching all products for reception: %s", receptionID)
 
 	query, args, _ := r.Builder.
-		Select("id", "reception_id", "type", "created_at").
+		Select(productColumns...).
 		From("products").
 		Where("reception_id = ?", receptionID).
 		OrderBy("created_at ASC").
@@ -107,7 +125,7 @@ func (r *Repository) GetAllByReception(ctx context.Context, receptionID uuid.UUI
 
 	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
 	if err != nil {
-		logrus.Errorf("Failed to fetch products for reception %s: %v", receptionID, err)
+		logger.FromContext(ctx).Errorf("Failed to fetch products for reception %s: %v", receptionID, err)
 		return nil, fmt.Errorf("ProductRepository.GetAllByReception - Query: %w", err)
 	}
 	defer rows.Close()
@@ -115,17 +133,194 @@ func (r *Repository) GetAllByReception(ctx context.Context, receptionID uuid.UUI
 	var products []entity.Product
 	for rows.Next() {
 		var product entity.Product
-		if err := rows.Scan(&product.ID, &product.ReceptionID, &product.Type, &product.CreatedAt); err != nil {
-			logrus.Errorf("Failed to scan product row: %v", err)
+		if err := scanProduct(rows, &product); err != nil {
+			logger.FromContext(ctx).Errorf("Failed to scan product row: %v", err)
 			return nil, fmt.Errorf("ProductRepository.GetAllByReception - Scan: %w", err)
 		}
 		products = append(products, product)
 	}
 	if err := rows.Err(); err != nil {
-		logrus.Errorf("Rows error after fetching products: %v", err)
+		logger.FromContext(ctx).Errorf("Rows error after fetching products: %v", err)
 		return nil, fmt.Errorf("ProductRepository.GetAllByReception - rows.Err: %w", err)
 	}
 
-	logrus.Infof("Fetched %d products for reception %s", len(products), receptionID)
+	logger.FromContext(ctx).Infof("Fetched %d products for reception %s", len(products), receptionID)
 	return products, nil
 }
+
+func (r *Repository) GetByID(ctx context.Context, productID uuid.UUID) (entity.Product, error) {
+	logger.FromContext(ctx).Infof("Fetching product: %s", productID)
+	return r.getOne(ctx, "GetByID", squirrel.Eq{"id": productID}, "")
+}
+
+func (r *Repository) GetByIDForUpdate(ctx context.Context, productID uuid.UUID) (entity.Product, error) {
+	logger.FromContext(ctx).Infof("Fetching product for update: %s", productID)
+	return r.getOne(ctx, "GetByIDForUpdate", squirrel.Eq{"id": productID}, "FOR UPDATE")
+}
+
+func (r *Repository) GetByBarcode(ctx context.Context, barcode string) (entity.Product, error) {
+	logger.FromContext(ctx).Infof("Fetching product by barcode: %s", barcode)
+	return r.getOne(ctx, "GetByBarcode", squirrel.Eq{"barcode": barcode}, "")
+}
+
+func (r *Repository) getOne(ctx context.Context, op string, where squirrel.Eq, suffix string) (entity.Product, error) {
+	query, args, _ := r.Builder.
+		Select(productColumns...).
+		From("products").
+		Where(where).
+		Suffix(suffix).
+		ToSql()
+
+	var product entity.Product
+	err := scanProduct(r.GetTxManager(ctx).QueryRow(ctx, query, args...), &product)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.FromContext(ctx).Warnf("No product found: %v", where)
+			return entity.Product{}, repository.ErrNoProductFound
+		}
+		logger.FromContext(ctx).Errorf("Failed to fetch product %v: %v", where, err)
+		return entity.Product{}, fmt.Errorf("ProductRepository.%s - Scan: %w", op, err)
+	}
+
+	logger.FromContext(ctx).Infof("Fetched product: %+v", product)
+	return product, nil
+}
+
+func scanProduct(row pgx.Row, product *entity.Product) error {
+	return row.Scan(
+		&product.ID,
+		&product.ReceptionID,
+		&product.Type,
+		&product.CreatedAt,
+		&product.Status,
+		&product.PointID,
+		&product.Barcode,
+		&product.CellID,
+	)
+}
+
+func (r *Repository) UpdateStatus(ctx context.Context, productID uuid.UUID, status entity.ProductStatus) error {
+	logger.FromContext(ctx).Infof("Updating status of product %s to %s", productID, status)
+
+	query, args, _ := r.Builder.
+		Update("products").
+		Set("status", status).
+		Where("id = ?", productID).
+		ToSql()
+
+	result, err := r.GetTxManager(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to update status of product %s: %v", productID, err)
+		return fmt.Errorf("ProductRepository.UpdateStatus - Exec: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		logger.FromContext(ctx).Warnf("No product found to update: %s", productID)
+		return repository.ErrNoProductFound
+	}
+
+	logger.FromContext(ctx).Infof("Updated status of product %s to %s", productID, status)
+	return nil
+}
+
+func (r *Repository) UpdateLocation(ctx context.Context, productID uuid.UUID, pointID uuid.UUID) error {
+	logger.FromContext(ctx).Infof("Moving product %s to point %s", productID, pointID)
+
+	query, args, _ := r.Builder.
+		Update("products").
+		Set("point_id", pointID).
+		Set("cell_id", nil).
+		Where("id = ?", productID).
+		ToSql()
+
+	result, err := r.GetTxManager(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to move product %s: %v", productID, err)
+		return fmt.Errorf("ProductRepository.UpdateLocation - Exec: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		logger.FromContext(ctx).Warnf("No product found to move: %s", productID)
+		return repository.ErrNoProductFound
+	}
+
+	logger.FromContext(ctx).Infof("Moved product %s to point %s", productID, pointID)
+	return nil
+}
+
+func (r *Repository) UpdateCell(ctx context.Context, productID uuid.UUID, cellID uuid.UUID) error {
+	logger.FromContext(ctx).Infof("Placing product %s into cell %s", productID, cellID)
+
+	query, args, _ := r.Builder.
+		Update("products").
+		Set("cell_id", cellID).
+		Where("id = ?", productID).
+		ToSql()
+
+	result, err := r.GetTxManager(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to place product %s: %v", productID, err)
+		return fmt.Errorf("ProductRepository.UpdateCell - Exec: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		logger.FromContext(ctx).Warnf("No product found to place: %s", productID)
+		return repository.ErrNoProductFound
+	}
+
+	logger.FromContext(ctx).Infof("Placed product %s into cell %s", productID, cellID)
+	return nil
+}
+
+func (r *Repository) CreateHistory(ctx context.Context, history entity.ProductHistory) (entity.ProductHistory, error) {
+	logger.FromContext(ctx).Infof("Recording history for product %s: %v -> %s", history.ProductID, history.FromStatus, history.ToStatus)
+
+	query, args, _ := r.Builder.
+		Insert("product_history").
+		Columns("product_id", "from_status", "to_status", "reason", "actor_id", "from_cell_id", "to_cell_id").
+		Values(history.ProductID, history.FromStatus, history.ToStatus, history.Reason, history.ActorID, history.FromCellID, history.ToCellID).
+		Suffix("RETURNING id, created_at").
+		ToSql()
+
+	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(&history.ID, &history.CreatedAt)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to record history for product %s: %v", history.ProductID, err)
+		return entity.ProductHistory{}, fmt.Errorf("ProductRepository.CreateHistory - Scan: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("History recorded: %+v", history)
+	return history, nil
+}
+
+func (r *Repository) GetHistory(ctx context.Context, productID uuid.UUID) ([]entity.ProductHistory, error) {
+	logger.FromContext(ctx).Infof("Fetching history for product: %s", productID)
+
+	query, args, _ := r.Builder.
+		Select("id", "product_id", "from_status", "to_status", "reason", "actor_id", "from_cell_id", "to_cell_id", "created_at").
+		From("product_history").
+		Where("product_id = ?", productID).
+		OrderBy("created_at ASC").
+		ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to fetch history for product %s: %v", productID, err)
+		return nil, fmt.Errorf("ProductRepository.GetHistory - Query: %w", err)
+	}
+	defer rows.Close()
+
+	var history []entity.ProductHistory
+	for rows.Next() {
+		var h entity.ProductHistory
+		if err := rows.Scan(&h.ID, &h.ProductID, &h.FromStatus, &h.ToStatus, &h.Reason, &h.ActorID, &h.FromCellID, &h.ToCellID, &h.CreatedAt); err != nil {
+			logger.FromContext(ctx).Errorf("Failed to scan product history row: %v", err)
+			return nil, fmt.Errorf("ProductRepository.GetHistory - Scan: %w", err)
+		}
+		history = append(history, h)
+	}
+	if err := rows.Err(); err != nil {
+		logger.FromContext(ctx).Errorf("Rows error after fetching product history: %v", err)
+		return nil, fmt.Errorf("ProductRepository.GetHistory - rows.Err: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Fetched %d history records for product %s", len(history), productID)
+	return history, nil
+}
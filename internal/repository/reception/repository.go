@@ -4,13 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/4udiwe/avito-pvz/internal/entity"
 	"github.com/4udiwe/avito-pvz/internal/repository"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
 	"github.com/4udiwe/avito-pvz/pkg/postgres"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
-	"github.com/sirupsen/logrus"
 )
 
 type Repository struct {
@@ -21,17 +22,17 @@ func New(postgres *postgres.Postgres) *Repository {
 	return &Repository{postgres}
 }
 
-func (r *Repository) Open(ctx context.Context, pointID uuid.UUID) (entity.Reception, error) {
-	logrus.Infof("Opening reception for point: %s", pointID)
+func (r *Repository) Open(ctx context.Context, pointID uuid.UUID, kind entity.ReceptionKind) (entity.Reception, error) {
+	logger.FromContext(ctx).Infof("Opening %s reception for point: %s", kind, pointID)
 
 	query, args, _ := r.Builder.
 		Insert("receptions").
-		Columns("point_id").
-		Values(pointID).
+		Columns("point_id", "kind").
+		Values(pointID, kind).
 		Suffix("RETURNING id, created_at, status").
 		ToSql()
 
-	reception := entity.Reception{PointID: pointID}
+	reception := entity.Reception{PointID: pointID, Kind: kind}
 	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(
 		&reception.ID,
 		&reception.CreatedAt,
@@ -39,18 +40,18 @@ func (r *Repository) Open(ctx context.Context, pointID uuid.UUID) (entity.Recept
 	)
 
 	if err != nil {
-		logrus.Errorf("Failed to open reception for point %s: %v", pointID, err)
+		logger.FromContext(ctx).Errorf("Failed to open reception for point %s: %v", pointID, err)
 		return entity.Reception{}, fmt.Errorf("ReceptionRepository.Open - create.Scan: %w", err)
 	}
 
-	logrus.Infof("Reception opened: %+v", reception)
+	logger.FromContext(ctx).Infof("Reception opened: %+v", reception)
 	return reception, nil
 }
 
 func (r *Repository) GetLastReceptionStatus(ctx context.Context, pointID uuid.UUID) (entity.ReceptionStatus, error) {
 	// TODO: ckech if there is a point with id pointID
 
-	logrus.Infof("Fetching last reception status for point: %s", pointID)
+	logger.FromContext(ctx).Infof("Fetching last reception status for point: %s", pointID)
 
 	query, args, _ := r.Builder.
 		Select("status").
@@ -65,40 +66,45 @@ func (r *Repository) GetLastReceptionStatus(ctx context.Context, pointID uuid.UU
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			logrus.Infof("No receptions found for point: %s", pointID)
+			logger.FromContext(ctx).Infof("No receptions found for point: %s", pointID)
 			return entity.ReceptionStatusClosed, nil
 		}
-		logrus.Errorf("Failed to fetch last reception status for point %s: %v", pointID, err)
+		logger.FromContext(ctx).Errorf("Failed to fetch last reception status for point %s: %v", pointID, err)
 		return "", fmt.Errorf("ReceptionRepository.GetLastReceptionStatus - lastStatus.Scan: %w", err)
 	}
 
-	logrus.Infof("Fetched last reception status for point %s: %s", pointID, lastReceptionStatus)
+	logger.FromContext(ctx).Infof("Fetched last reception status for point %s: %s", pointID, lastReceptionStatus)
 	return lastReceptionStatus, nil
 }
 
 func (r *Repository) GetLastReceptionProductsAmount(ctx context.Context, pointID uuid.UUID) (int, error) {
-	logrus.Infof("Fetching last reception products amount for point: %s", pointID)
+	logger.FromContext(ctx).Infof("Fetching last reception products amount for point: %s", pointID)
 
+	// Transfer receptions hold no products of their own, only accepted transfer items
 	query := `
-        SELECT COUNT(p.id)
-        FROM products p
-        JOIN (
+        WITH r AS (
             SELECT id FROM receptions WHERE point_id = $1 ORDER BY created_at DESC LIMIT 1
-        ) r ON p.reception_id = r.id
+        )
+        SELECT
+            (SELECT COUNT(p.id) FROM products p JOIN r ON p.reception_id = r.id) +
+            (SELECT COUNT(tp.product_id) FROM transfer_products tp
+                JOIN transfers t ON t.id = tp.transfer_id
+                JOIN r ON t.reception_id = r.id
+                WHERE tp.accepted)
     `
 	var productCount int
 	err := r.GetTxManager(ctx).QueryRow(ctx, query, pointID).Scan(&productCount)
 	if err != nil {
-		logrus.Errorf("Failed to fetch product count for last reception of point %s: %v", pointID, err)
+		logger.FromContext(ctx).Errorf("Failed to fetch product count for last reception of point %s: %v", pointID, err)
 		return 0, fmt.Errorf("ReceptionRepository.GetLastReceptionProductsAmount - productCount.Scan: %w", err)
 	}
 
-	logrus.Infof("Fetched product count for last reception of point %s: %d", pointID, productCount)
+	logger.FromContext(ctx).Infof("Fetched product count for last reception of point %s: %d", pointID, productCount)
 	return productCount, nil
 }
 
-func (r *Repository) CloseLastReception(ctx context.Context, pointID uuid.UUID) error {
-	logrus.Infof("Closing last reception for point: %s", pointID)
+func (r *Repository) CloseLastReception(ctx context.Context, pointID uuid.UUID) (entity.Reception, error) {
+	logger.FromContext(ctx).Infof("Closing last reception for point: %s", pointID)
 
 	query, args, _ := r.Builder.
 		Update("receptions").
@@ -106,35 +112,52 @@ func (r *Repository) CloseLastReception(ctx context.Context, pointID uuid.UUID)
 		Where("point_id = ? AND created_at = ("+
 			"SELECT MAX(created_at) FROM receptions WHERE point_id = ?"+
 			")", pointID, pointID).
+		Suffix("RETURNING id, point_id, created_at, status, kind").
 		ToSql()
 
-	result, err := r.GetTxManager(ctx).Exec(ctx, query, args...)
+	var reception entity.Reception
+	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(
+		&reception.ID,
+		&reception.PointID,
+		&reception.CreatedAt,
+		&reception.Status,
+		&reception.Kind,
+	)
 	if err != nil {
-		logrus.Errorf("Failed to close last reception for point %s: %v", pointID, err)
-		return fmt.Errorf("ReceptionRepository.CloseLastReception - Exec: %w", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.FromContext(ctx).Warnf("No reception found to close for point: %s", pointID)
+			return entity.Reception{}, repository.ErrNoReceptionFound
+		}
+		logger.FromContext(ctx).Errorf("Failed to close last reception for point %s: %v", pointID, err)
+		return entity.Reception{}, fmt.Errorf("ReceptionRepository.CloseLastReception - Scan: %w", err)
 	}
 
-	if rowsAffected := result.RowsAffected(); rowsAffected == 0 {
-		logrus.Warnf("No reception found to close for point: %s", pointID)
-		return repository.ErrNoReceptionFound
-	}
-	logrus.Infof("Closed last reception for point: %s", pointID)
-	return nil
+	logger.FromContext(ctx).Infof("Closed last reception for point: %s", pointID)
+	return reception, nil
 }
 
-func (r *Repository) GetAllByPoint(ctx context.Context, pointID uuid.UUID) ([]entity.Reception, error) {
-	logrus.Infof("Fetching all receptions for point: %s", pointID)
+// GetAllByPoint returns the receptions of the point created between start
+// and end inclusive; nil ends are open.
+func (r *Repository) GetAllByPoint(ctx context.Context, pointID uuid.UUID, start, end *time.Time) ([]entity.Reception, error) {
+	logger.FromContext(ctx).Infof("Fetching all receptions for point: %s", pointID)
 
-	query, args, _ := r.Builder.
-		Select("id", "point_id", "created_at", "status").
+	builder := r.Builder.
+		Select("id", "point_id", "created_at", "status", "kind").
 		From("receptions").
-		Where("point_id = ?", pointID).
+		Where("point_id = ?", pointID)
+	if start != nil {
+		builder = builder.Where("created_at >= ?", *start)
+	}
+	if end != nil {
+		builder = builder.Where("created_at <= ?", *end)
+	}
+	query, args, _ := builder.
 		OrderBy("created_at ASC").
 		ToSql()
 
 	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
 	if err != nil {
-		logrus.Errorf("Failed to fetch receptions for point %s: %v", pointID, err)
+		logger.FromContext(ctx).Errorf("Failed to fetch receptions for point %s: %v", pointID, err)
 		return nil, fmt.Errorf("ReceptionRepository.GetAllByPoint - Query: %w", err)
 	}
 	defer rows.Close()
@@ -142,21 +165,54 @@ func (r *Repository) GetAllByPoint(ctx context.Context, pointID uuid.UUID) ([]en
 	var receptions []entity.Reception
 	for rows.Next() {
 		var reception entity.Reception
-		if err := rows.Scan(&reception.ID, &reception.PointID, &reception.CreatedAt, &reception.Status); err != nil {
-			logrus.Errorf("Failed to scan reception row: %v", err)
+		if err := rows.Scan(&reception.ID, &reception.PointID, &reception.CreatedAt, &reception.Status, &reception.Kind); err != nil {
+			logger.FromContext(ctx).Errorf("Failed to scan reception row: %v", err)
 			return nil, fmt.Errorf("ReceptionRepository.GetAllByPoint - Scan: %w", err)
 		}
 		receptions = append(receptions, reception)
 	}
 	if err := rows.Err(); err != nil {
-		logrus.Errorf("Rows error after fetching receptions: %v", err)
+		logger.FromContext(ctx).Errorf("Rows error after fetching receptions: %v", err)
 		return nil, fmt.Errorf("ReceptionRepository.GetAllByPoint - rows.Err: %w", err)
 	}
 
-	logrus.Infof("Fetched %d receptions for point %s", len(receptions), pointID)
+	logger.FromContext(ctx).Infof("Fetched %d receptions for point %s", len(receptions), pointID)
 	return receptions, nil
 }
 
+func (r *Repository) GetLastReception(ctx context.Context, pointID uuid.UUID) (entity.Reception, error) {
+	logger.FromContext(ctx).Infof("Fetching last reception for point: %s", pointID)
+
+	query, args, _ := r.Builder.
+		Select("id", "point_id", "created_at", "status", "kind").
+		From("receptions").
+		Where("point_id = ?", pointID).
+		OrderBy("created_at DESC").
+		Limit(1).
+		ToSql()
+
+	var reception entity.Reception
+	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(
+		&reception.ID,
+		&reception.PointID,
+		&reception.CreatedAt,
+		&reception.Status,
+		&reception.Kind,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.FromContext(ctx).Warnf("No receptions found for point: %s", pointID)
+			return entity.Reception{}, repository.ErrNoReceptionFound
+		}
+		logger.FromContext(ctx).Errorf("Failed to fetch last reception for point %s: %v", pointID, err)
+		return entity.Reception{}, fmt.Errorf("ReceptionRepository.GetLastReception - Scan: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Fetched last reception for point %s: %+v", pointID, reception)
+	return reception, nil
+}
+
 func (r *Repository) CheckIfPointExists(ctx context.Context, pointID uuid.UUID) (bool, error) {
 	query, args, _ := r.Builder.
 		Select("1").
@@ -177,3 +233,28 @@ func (r *Repository) CheckIfPointExists(ctx context.Context, pointID uuid.UUID)
 
 	return true, nil
 }
+
+func (r *Repository) GetStatusByID(ctx context.Context, receptionID uuid.UUID) (entity.ReceptionStatus, error) {
+	logger.FromContext(ctx).Infof("Fetching status of reception: %s", receptionID)
+
+	query, args, _ := r.Builder.
+		Select("status").
+		From("receptions").
+		Where("id = ?", receptionID).
+		ToSql()
+
+	var status entity.ReceptionStatus
+	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(&status)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.FromContext(ctx).Warnf("No reception found: %s", receptionID)
+			return "", repository.ErrNoReceptionFound
+		}
+		logger.FromContext(ctx).Errorf("Failed to fetch status of reception %s: %v", receptionID, err)
+		return "", fmt.Errorf("ReceptionRepository.GetStatusByID - Scan: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Fetched status of reception %s: %s", receptionID, status)
+	return status, nil
+}
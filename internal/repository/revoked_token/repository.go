@@ -0,0 +1,92 @@
+package repo_revoked_token
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/4udiwe/avito-pvz/pkg/postgres"
+)
+
+type Repository struct {
+	*postgres.Postgres
+}
+
+func New(pg *postgres.Postgres) *Repository {
+	return &Repository{pg}
+}
+
+func (r *Repository) Create(ctx context.Context, jti string, expiresAt time.Time) error {
+	logger.FromContext(ctx).Infof("Revoking token %s until %s", jti, expiresAt)
+
+	query, args, _ := r.Builder.
+		Insert("revoked_tokens").
+		Columns("jti", "expires_at").
+		Values(jti, expiresAt).
+		Suffix("ON CONFLICT (jti) DO NOTHING").
+		ToSql()
+
+	if _, err := r.GetTxManager(ctx).Exec(ctx, query, args...); err != nil {
+		logger.FromContext(ctx).Errorf("Failed to revoke token %s: %v", jti, err)
+		return fmt.Errorf("RevokedTokenRepository.Create - Exec: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Token revoked: %s", jti)
+	return nil
+}
+
+func (r *Repository) GetActive(ctx context.Context) (map[string]time.Time, error) {
+	logger.FromContext(ctx).Info("Fetching active revoked tokens")
+
+	query, args, _ := r.Builder.
+		Select("jti", "expires_at").
+		From("revoked_tokens").
+		Where("expires_at > NOW()").
+		ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to fetch revoked tokens: %v", err)
+		return nil, fmt.Errorf("RevokedTokenRepository.GetActive - Query: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := make(map[string]time.Time)
+	for rows.Next() {
+		var (
+			jti       string
+			expiresAt time.Time
+		)
+		if err := rows.Scan(&jti, &expiresAt); err != nil {
+			logger.FromContext(ctx).Errorf("Failed to scan revoked token row: %v", err)
+			return nil, fmt.Errorf("RevokedTokenRepository.GetActive - Scan: %w", err)
+		}
+		tokens[jti] = expiresAt
+	}
+	if err := rows.Err(); err != nil {
+		logger.FromContext(ctx).Errorf("Rows error after fetching revoked tokens: %v", err)
+		return nil, fmt.Errorf("RevokedTokenRepository.GetActive - rows.Err: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Fetched %d active revoked tokens", len(tokens))
+	return tokens, nil
+}
+
+func (r *Repository) DeleteExpired(ctx context.Context) (int64, error) {
+	logger.FromContext(ctx).Info("Pruning expired revoked tokens")
+
+	query, args, _ := r.Builder.
+		Delete("revoked_tokens").
+		Where("expires_at <= NOW()").
+		ToSql()
+
+	result, err := r.GetTxManager(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to prune revoked tokens: %v", err)
+		return 0, fmt.Errorf("RevokedTokenRepository.DeleteExpired - Exec: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Pruned %d expired revoked tokens", result.RowsAffected())
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,61 @@
+package repo_role
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/4udiwe/avito-pvz/pkg/postgres"
+)
+
+type Repository struct {
+	*postgres.Postgres
+}
+
+func New(pg *postgres.Postgres) *Repository {
+	return &Repository{pg}
+}
+
+// GetPermissions returns the permissions granted to each role. Roles
+// without permissions are included with an empty list.
+func (r *Repository) GetPermissions(ctx context.Context) (map[entity.UserRole][]entity.Permission, error) {
+	logger.FromContext(ctx).Info("Fetching role permissions")
+
+	query, args, _ := r.Builder.
+		Select("r.name", "rp.permission").
+		From("roles r").
+		LeftJoin("role_permissions rp ON rp.role = r.name").
+		ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to fetch role permissions: %v", err)
+		return nil, fmt.Errorf("RoleRepository.GetPermissions - Query: %w", err)
+	}
+	defer rows.Close()
+
+	permissions := make(map[entity.UserRole][]entity.Permission)
+	for rows.Next() {
+		var (
+			role       entity.UserRole
+			permission *entity.Permission
+		)
+		if err := rows.Scan(&role, &permission); err != nil {
+			logger.FromContext(ctx).Errorf("Failed to scan role permission row: %v", err)
+			return nil, fmt.Errorf("RoleRepository.GetPermissions - Scan: %w", err)
+		}
+		if permission == nil {
+			permissions[role] = []entity.Permission{}
+			continue
+		}
+		permissions[role] = append(permissions[role], *permission)
+	}
+	if err := rows.Err(); err != nil {
+		logger.FromContext(ctx).Errorf("Rows error after fetching role permissions: %v", err)
+		return nil, fmt.Errorf("RoleRepository.GetPermissions - rows.Err: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Fetched permissions of %d roles", len(permissions))
+	return permissions, nil
+}
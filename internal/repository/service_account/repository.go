@@ -0,0 +1,113 @@
+package repo_service_account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/repository"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/4udiwe/avito-pvz/pkg/postgres"
+	"github.com/google/uuid"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type Repository struct {
+	*postgres.Postgres
+}
+
+func New(pg *postgres.Postgres) *Repository {
+	return &Repository{pg}
+}
+
+func (r *Repository) Create(ctx context.Context, account entity.ServiceAccount) (entity.ServiceAccount, error) {
+	logger.FromContext(ctx).Infof("Creating service account %s", account.Name)
+
+	query, args, _ := r.Builder.
+		Insert("service_accounts").
+		Columns("name", "description", "created_by").
+		Values(account.Name, account.Description, account.CreatedBy).
+		Suffix("RETURNING id, created_at").
+		ToSql()
+
+	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(&account.ID, &account.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			logger.FromContext(ctx).Warnf("Service account already exists: %s", account.Name)
+			return entity.ServiceAccount{}, repository.ErrServiceAccountAlreadyExists
+		}
+		logger.FromContext(ctx).Errorf("Failed to create service account %s: %v", account.Name, err)
+		return entity.ServiceAccount{}, fmt.Errorf("ServiceAccountRepository.Create - Scan: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Service account created: %s", account.ID)
+	return account, nil
+}
+
+func (r *Repository) GetByID(ctx context.Context, accountID uuid.UUID) (entity.ServiceAccount, error) {
+	logger.FromContext(ctx).Infof("Fetching service account: %s", accountID)
+
+	query, args, _ := r.Builder.
+		Select("id", "name", "description", "created_by", "created_at").
+		From("service_accounts").
+		Where("id = ?", accountID).
+		ToSql()
+
+	var account entity.ServiceAccount
+	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(
+		&account.ID,
+		&account.Name,
+		&account.Description,
+		&account.CreatedBy,
+		&account.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.FromContext(ctx).Warnf("No service account found: %s", accountID)
+			return entity.ServiceAccount{}, repository.ErrNoServiceAccountFound
+		}
+		logger.FromContext(ctx).Errorf("Failed to fetch service account %s: %v", accountID, err)
+		return entity.ServiceAccount{}, fmt.Errorf("ServiceAccountRepository.GetByID - Scan: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Fetched service account %s", accountID)
+	return account, nil
+}
+
+func (r *Repository) GetAll(ctx context.Context) ([]entity.ServiceAccount, error) {
+	logger.FromContext(ctx).Info("Fetching service accounts")
+
+	query, args, _ := r.Builder.
+		Select("id", "name", "description", "created_by", "created_at").
+		From("service_accounts").
+		OrderBy("name").
+		ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to fetch service accounts: %v", err)
+		return nil, fmt.Errorf("ServiceAccountRepository.GetAll - Query: %w", err)
+	}
+	defer rows.Close()
+
+	var accounts []entity.ServiceAccount
+	for rows.Next() {
+		var account entity.ServiceAccount
+		if err := rows.Scan(&account.ID, &account.Name, &account.Description, &account.CreatedBy, &account.CreatedAt); err != nil {
+			logger.FromContext(ctx).Errorf("Failed to scan service account row: %v", err)
+			return nil, fmt.Errorf("ServiceAccountRepository.GetAll - Scan: %w", err)
+		}
+		accounts = append(accounts, account)
+	}
+	if err := rows.Err(); err != nil {
+		logger.FromContext(ctx).Errorf("Rows error after fetching service accounts: %v", err)
+		return nil, fmt.Errorf("ServiceAccountRepository.GetAll - rows.Err: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Fetched %d service accounts", len(accounts))
+	return accounts, nil
+}
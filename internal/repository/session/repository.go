@@ -0,0 +1,199 @@
+package repo_session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/repository"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/4udiwe/avito-pvz/pkg/postgres"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+var sessionColumns = []string{
+	"id", "user_id", "refresh_token_hash", "user_agent", "ip",
+	"created_at", "last_used_at", "expires_at", "revoked_at", "mfa_verified",
+}
+
+type Repository struct {
+	*postgres.Postgres
+}
+
+func New(pg *postgres.Postgres) *Repository {
+	return &Repository{pg}
+}
+
+func (r *Repository) Create(ctx context.Context, session entity.Session) (entity.Session, error) {
+	logger.FromContext(ctx).Infof("Creating session %s for user %s", session.ID, session.UserID)
+
+	query, args, _ := r.Builder.
+		Insert("sessions").
+		Columns("id", "user_id", "refresh_token_hash", "user_agent", "ip", "expires_at", "mfa_verified").
+		Values(session.ID, session.UserID, session.RefreshTokenHash, session.UserAgent, session.IP, session.ExpiresAt, session.MFAVerified).
+		Suffix("RETURNING created_at, last_used_at").
+		ToSql()
+
+	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(&session.CreatedAt, &session.LastUsedAt)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to create session for user %s: %v", session.UserID, err)
+		return entity.Session{}, fmt.Errorf("SessionRepository.Create - Scan: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Session created: %s", session.ID)
+	return session, nil
+}
+
+func (r *Repository) GetByIDForUpdate(ctx context.Context, sessionID uuid.UUID) (entity.Session, error) {
+	logger.FromContext(ctx).Infof("Fetching session for update: %s", sessionID)
+
+	query, args, _ := r.Builder.
+		Select(sessionColumns...).
+		From("sessions").
+		Where("id = ?", sessionID).
+		Suffix("FOR UPDATE").
+		ToSql()
+
+	var session entity.Session
+	err := scanSession(r.GetTxManager(ctx).QueryRow(ctx, query, args...), &session)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.FromContext(ctx).Warnf("No session found: %s", sessionID)
+			return entity.Session{}, repository.ErrNoSessionFound
+		}
+		logger.FromContext(ctx).Errorf("Failed to fetch session %s: %v", sessionID, err)
+		return entity.Session{}, fmt.Errorf("SessionRepository.GetByIDForUpdate - Scan: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Fetched session %s of user %s", session.ID, session.UserID)
+	return session, nil
+}
+
+func (r *Repository) GetActiveByUser(ctx context.Context, userID uuid.UUID) ([]entity.Session, error) {
+	logger.FromContext(ctx).Infof("Fetching active sessions of user: %s", userID)
+
+	query, args, _ := r.Builder.
+		Select(sessionColumns...).
+		From("sessions").
+		Where("user_id = ?", userID).
+		Where("revoked_at IS NULL").
+		Where("expires_at > NOW()").
+		OrderBy("last_used_at DESC").
+		ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to fetch sessions of user %s: %v", userID, err)
+		return nil, fmt.Errorf("SessionRepository.GetActiveByUser - Query: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []entity.Session
+	for rows.Next() {
+		var session entity.Session
+		if err := scanSession(rows, &session); err != nil {
+			logger.FromContext(ctx).Errorf("Failed to scan session row: %v", err)
+			return nil, fmt.Errorf("SessionRepository.GetActiveByUser - Scan: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+	if err := rows.Err(); err != nil {
+		logger.FromContext(ctx).Errorf("Rows error after fetching sessions: %v", err)
+		return nil, fmt.Errorf("SessionRepository.GetActiveByUser - rows.Err: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Fetched %d active sessions of user %s", len(sessions), userID)
+	return sessions, nil
+}
+
+func (r *Repository) Rotate(ctx context.Context, session entity.Session) error {
+	logger.FromContext(ctx).Infof("Rotating refresh token of session %s", session.ID)
+
+	query, args, _ := r.Builder.
+		Update("sessions").
+		Set("refresh_token_hash", session.RefreshTokenHash).
+		Set("user_agent", session.UserAgent).
+		Set("ip", session.IP).
+		Set("expires_at", session.ExpiresAt).
+		Set("last_used_at", time.Now()).
+		Where("id = ?", session.ID).
+		Where("revoked_at IS NULL").
+		ToSql()
+
+	result, err := r.GetTxManager(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to rotate session %s: %v", session.ID, err)
+		return fmt.Errorf("SessionRepository.Rotate - Exec: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		logger.FromContext(ctx).Warnf("No active session found to rotate: %s", session.ID)
+		return repository.ErrNoSessionFound
+	}
+
+	logger.FromContext(ctx).Infof("Rotated refresh token of session %s", session.ID)
+	return nil
+}
+
+func (r *Repository) Revoke(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) error {
+	logger.FromContext(ctx).Infof("Revoking session %s of user %s", sessionID, userID)
+
+	query, args, _ := r.Builder.
+		Update("sessions").
+		Set("revoked_at", time.Now()).
+		Where("id = ?", sessionID).
+		Where("user_id = ?", userID).
+		Where("revoked_at IS NULL").
+		ToSql()
+
+	result, err := r.GetTxManager(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to revoke session %s: %v", sessionID, err)
+		return fmt.Errorf("SessionRepository.Revoke - Exec: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		logger.FromContext(ctx).Warnf("No active session found to revoke: %s", sessionID)
+		return repository.ErrNoSessionFound
+	}
+
+	logger.FromContext(ctx).Infof("Revoked session %s", sessionID)
+	return nil
+}
+
+// RevokeAllByUser signs the user out of every device.
+func (r *Repository) RevokeAllByUser(ctx context.Context, userID uuid.UUID) error {
+	logger.FromContext(ctx).Infof("Revoking all sessions of user %s", userID)
+
+	query, args, _ := r.Builder.
+		Update("sessions").
+		Set("revoked_at", time.Now()).
+		Where("user_id = ?", userID).
+		Where("revoked_at IS NULL").
+		ToSql()
+
+	result, err := r.GetTxManager(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to revoke sessions of user %s: %v", userID, err)
+		return fmt.Errorf("SessionRepository.RevokeAllByUser - Exec: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Revoked %d sessions of user %s", result.RowsAffected(), userID)
+	return nil
+}
+
+func scanSession(row pgx.Row, session *entity.Session) error {
+	return row.Scan(
+		&session.ID,
+		&session.UserID,
+		&session.RefreshTokenHash,
+		&session.UserAgent,
+		&session.IP,
+		&session.CreatedAt,
+		&session.LastUsedAt,
+		&session.ExpiresAt,
+		&session.RevokedAt,
+		&session.MFAVerified,
+	)
+}
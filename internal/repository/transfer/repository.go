@@ -0,0 +1,218 @@
+package repo_transfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/repository"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/4udiwe/avito-pvz/pkg/postgres"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type Repository struct {
+	*postgres.Postgres
+}
+
+func New(postgres *postgres.Postgres) *Repository {
+	return &Repository{postgres}
+}
+
+func (r *Repository) Create(ctx context.Context, transfer entity.Transfer) (entity.Transfer, error) {
+	logger.FromContext(ctx).Infof("Attempting to create transfer from %s to %s", transfer.SourcePointID, transfer.DestinationPointID)
+
+	query, args, _ := r.Builder.
+		Insert("transfers").
+		Columns("source_point_id", "destination_point_id", "created_by").
+		Values(transfer.SourcePointID, transfer.DestinationPointID, transfer.CreatedBy).
+		Suffix("RETURNING id, status, created_at").
+		ToSql()
+
+	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(&transfer.ID, &transfer.Status, &transfer.CreatedAt)
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.ForeignKeyViolation {
+			logger.FromContext(ctx).Warnf("No point found for transfer from %s to %s", transfer.SourcePointID, transfer.DestinationPointID)
+			return entity.Transfer{}, repository.ErrNoPointFound
+		}
+		logger.FromContext(ctx).Errorf("Failed to create transfer: %v", err)
+		return entity.Transfer{}, fmt.Errorf("TransferRepository.Create - Scan: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Transfer created: %s", transfer.ID)
+	return transfer, nil
+}
+
+// AttachProducts links stored products of the source point that are not part
+// of another unfinished transfer.
+func (r *Repository) AttachProducts(ctx context.Context, transferID uuid.UUID, pointID uuid.UUID, productIDs []uuid.UUID) error {
+	logger.FromContext(ctx).Infof("Attaching %d products to transfer %s", len(productIDs), transferID)
+
+	query := `
+        INSERT INTO transfer_products(transfer_id, product_id)
+        SELECT $1, p.id
+        FROM products p
+        WHERE p.id = ANY($2) AND p.point_id = $3 AND p.status = 'stored'
+            AND NOT EXISTS (
+                SELECT 1 FROM transfer_products tp
+                JOIN transfers t ON t.id = tp.transfer_id
+                WHERE tp.product_id = p.id AND t.status <> 'accepted'
+            )
+    `
+	result, err := r.GetTxManager(ctx).Exec(ctx, query, transferID, productIDs, pointID)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to attach products to transfer %s: %v", transferID, err)
+		return fmt.Errorf("TransferRepository.AttachProducts - Exec: %w", err)
+	}
+
+	if int(result.RowsAffected()) != len(productIDs) {
+		logger.FromContext(ctx).Warnf("Only %d of %d products are available for transfer %s", result.RowsAffected(), len(productIDs), transferID)
+		return repository.ErrProductsUnavailable
+	}
+
+	logger.FromContext(ctx).Infof("Attached %d products to transfer %s", len(productIDs), transferID)
+	return nil
+}
+
+func (r *Repository) GetByID(ctx context.Context, transferID uuid.UUID) (entity.Transfer, error) {
+	logger.FromContext(ctx).Infof("Fetching transfer: %s", transferID)
+	return r.get(ctx, transferID, "")
+}
+
+func (r *Repository) GetByIDForUpdate(ctx context.Context, transferID uuid.UUID) (entity.Transfer, error) {
+	logger.FromContext(ctx).Infof("Fetching transfer for update: %s", transferID)
+	return r.get(ctx, transferID, "FOR UPDATE")
+}
+
+func (r *Repository) get(ctx context.Context, transferID uuid.UUID, suffix string) (entity.Transfer, error) {
+	query, args, _ := r.Builder.
+		Select(
+			"id",
+			"source_point_id",
+			"destination_point_id",
+			"status",
+			"reception_id",
+			"created_by",
+			"created_at",
+			"dispatched_at",
+			"accepted_at",
+		).
+		From("transfers").
+		Where("id = ?", transferID).
+		Suffix(suffix).
+		ToSql()
+
+	var transfer entity.Transfer
+	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(
+		&transfer.ID,
+		&transfer.SourcePointID,
+		&transfer.DestinationPointID,
+		&transfer.Status,
+		&transfer.ReceptionID,
+		&transfer.CreatedBy,
+		&transfer.CreatedAt,
+		&transfer.DispatchedAt,
+		&transfer.AcceptedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.FromContext(ctx).Warnf("No transfer found: %s", transferID)
+			return entity.Transfer{}, repository.ErrNoTransferFound
+		}
+		logger.FromContext(ctx).Errorf("Failed to fetch transfer %s: %v", transferID, err)
+		return entity.Transfer{}, fmt.Errorf("TransferRepository.get - Scan: %w", err)
+	}
+
+	query, args, _ = r.Builder.
+		Select("product_id", "accepted", "missing").
+		From("transfer_products").
+		Where("transfer_id = ?", transferID).
+		OrderBy("product_id").
+		ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to fetch products of transfer %s: %v", transferID, err)
+		return entity.Transfer{}, fmt.Errorf("TransferRepository.get - Query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p entity.TransferProduct
+		if err := rows.Scan(&p.ProductID, &p.Accepted, &p.Missing); err != nil {
+			logger.FromContext(ctx).Errorf("Failed to scan transfer product row: %v", err)
+			return entity.Transfer{}, fmt.Errorf("TransferRepository.get - rows.Scan: %w", err)
+		}
+		transfer.Products = append(transfer.Products, p)
+	}
+	if err := rows.Err(); err != nil {
+		logger.FromContext(ctx).Errorf("Rows error after fetching transfer products: %v", err)
+		return entity.Transfer{}, fmt.Errorf("TransferRepository.get - rows.Err: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Fetched transfer %s with %d products", transferID, len(transfer.Products))
+	return transfer, nil
+}
+
+func (r *Repository) MarkDispatched(ctx context.Context, transferID uuid.UUID) error {
+	logger.FromContext(ctx).Infof("Marking transfer as dispatched: %s", transferID)
+
+	query, args, _ := r.Builder.
+		Update("transfers").
+		Set("status", entity.TransferStatusDispatched).
+		Set("dispatched_at", squirrel.Expr("NOW()")).
+		Where("id = ?", transferID).
+		ToSql()
+
+	return r.exec(ctx, "MarkDispatched", transferID, query, args...)
+}
+
+func (r *Repository) MarkAccepted(ctx context.Context, transferID uuid.UUID, receptionID uuid.UUID) error {
+	logger.FromContext(ctx).Infof("Marking transfer %s as accepted by reception %s", transferID, receptionID)
+
+	query, args, _ := r.Builder.
+		Update("transfers").
+		Set("status", entity.TransferStatusAccepted).
+		Set("reception_id", receptionID).
+		Set("accepted_at", squirrel.Expr("NOW()")).
+		Where("id = ?", transferID).
+		ToSql()
+
+	return r.exec(ctx, "MarkAccepted", transferID, query, args...)
+}
+
+func (r *Repository) UpdateProduct(ctx context.Context, transferID uuid.UUID, product entity.TransferProduct) error {
+	logger.FromContext(ctx).Infof("Updating product %s of transfer %s: %+v", product.ProductID, transferID, product)
+
+	query, args, _ := r.Builder.
+		Update("transfer_products").
+		Set("accepted", product.Accepted).
+		Set("missing", product.Missing).
+		Where("transfer_id = ? AND product_id = ?", transferID, product.ProductID).
+		ToSql()
+
+	return r.exec(ctx, "UpdateProduct", transferID, query, args...)
+}
+
+func (r *Repository) exec(ctx context.Context, op string, transferID uuid.UUID, query string, args ...any) error {
+	result, err := r.GetTxManager(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to update transfer %s: %v", transferID, err)
+		return fmt.Errorf("TransferRepository.%s - Exec: %w", op, err)
+	}
+	if result.RowsAffected() == 0 {
+		logger.FromContext(ctx).Warnf("No transfer found to update: %s", transferID)
+		return repository.ErrNoTransferFound
+	}
+
+	logger.FromContext(ctx).Infof("Transfer %s updated", transferID)
+	return nil
+}
@@ -4,18 +4,24 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/4udiwe/avito-pvz/internal/entity"
 	"github.com/4udiwe/avito-pvz/internal/repository"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
 	"github.com/4udiwe/avito-pvz/pkg/postgres"
+	"github.com/4udiwe/avito-pvz/pkg/redact"
+	"github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
-	"github.com/sirupsen/logrus"
 )
 
+// likeEscaper escapes LIKE wildcards in user input.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 type Repository struct {
 	*postgres.Postgres
 }
@@ -25,88 +31,299 @@ func New(pg *postgres.Postgres) *Repository {
 }
 
 func (r *Repository) Create(ctx context.Context, user entity.User) (entity.User, error) {
-	logrus.Infof("Attempting to create user: %s", user.Email)
+	logger.FromContext(ctx).Infof("Attempting to create user: %s", redact.Email(user.Email))
 
 	query, args, _ := r.Builder.Insert("users").
-		Columns("email", "password_hash", "role", "refresh_token").
-		Values(user.Email, user.PasswordHash, user.Role, user.RefreshToken).
-		Suffix("RETURNING id").
+		Columns("email", "password_hash", "role").
+		Values(user.Email, squirrel.Expr("NULLIF(?, '')", user.PasswordHash), user.Role).
+		Suffix("RETURNING id, created_at, updated_at").
 		ToSql()
 
-	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(&user.ID)
+	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
 
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if ok := errors.As(err, &pgErr); ok {
 			if pgErr.Code == pgerrcode.UniqueViolation {
-				logrus.Warnf("User already exists: %s", user.Email)
+				logger.FromContext(ctx).Warnf("User already exists: %s", redact.Email(user.Email))
 				return entity.User{}, repository.ErrUserAlreadyExists
 			}
+			if pgErr.Code == pgerrcode.ForeignKeyViolation && pgErr.ConstraintName == "fk_users_role" {
+				logger.FromContext(ctx).Warnf("No role found: %s", user.Role)
+				return entity.User{}, repository.ErrNoRoleFound
+			}
 		}
-		logrus.Errorf("Failed to create user %s: %v", user.Email, err)
+		logger.FromContext(ctx).Errorf("Failed to create user %s: %v", redact.Email(user.Email), err)
 		return entity.User{}, err
 	}
 
-	logrus.Infof("User created: %+v", user)
+	logger.FromContext(ctx).Infof("User created: %s", user.ID)
 	return user, nil
 }
 
-func (r *Repository) UpdateRefreshToken(ctx context.Context, userID uuid.UUID, refreshToken string) (entity.User, error) {
-	logrus.Infof("Updating refresh token for user %d", userID)
+func (r *Repository) GetByEmail(ctx context.Context, email string) (entity.User, error) {
+	logger.FromContext(ctx).Infof("Fetching user by email: %s", redact.Email(email))
 
-	query, args, _ := r.Builder.Update("users").
-		Set("refresh_token", refreshToken).
-		Set("updated_at", time.Now()).
-		Where("id = ?", userID).
-		Suffix("RETURNING id, email, role, created_at, updated_at").
+	query, args, _ := r.Builder.
+		Select("id", "COALESCE(password_hash, '')", "role", "created_at", "updated_at", "disabled_at").
+		From("users").
+		Where("email = ?", email).
 		ToSql()
 
-	var user entity.User
+	user := entity.User{Email: email}
 	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(
 		&user.ID,
-		&user.Email,
+		&user.PasswordHash,
 		&user.Role,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.DisabledAt,
 	)
 
 	if err != nil {
-		logrus.Errorf("Failed to update refresh token for user %d: %v", userID, err)
-		return entity.User{}, err
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.FromContext(ctx).Warnf("No user found with email: %s", redact.Email(email))
+			return entity.User{}, repository.ErrNoUserFound
+		}
+		logger.FromContext(ctx).Errorf("Failed to fetch user %s: %v", redact.Email(email), err)
+		return entity.User{}, fmt.Errorf("UserRepository.GetByEmail - Scan: %w", err)
 	}
 
-	logrus.Infof("Refresh token updated for user %d", userID)
+	logger.FromContext(ctx).Infof("Fetched user: %s", user.ID)
 	return user, nil
 }
 
-func (r *Repository) GetByEmail(ctx context.Context, email string) (entity.User, error) {
-	logrus.Infof("Fetching user by email: %s", email)
+func (r *Repository) GetByID(ctx context.Context, userID uuid.UUID) (entity.User, error) {
+	logger.FromContext(ctx).Infof("Fetching user: %s", userID)
 
-	query, args, _ := r.Builder.
-		Select("id", "password_hash", "role", "refresh_token", "created_at", "updated_at").
+	query, args, _ := r.byIDQuery(userID).ToSql()
+
+	return r.getByID(ctx, "GetByID", userID, query, args...)
+}
+
+// GetByIDForUpdate is GetByID that locks the user until the end of the
+// transaction of ctx.
+func (r *Repository) GetByIDForUpdate(ctx context.Context, userID uuid.UUID) (entity.User, error) {
+	logger.FromContext(ctx).Infof("Fetching user for update: %s", userID)
+
+	query, args, _ := r.byIDQuery(userID).Suffix("FOR UPDATE").ToSql()
+
+	return r.getByID(ctx, "GetByIDForUpdate", userID, query, args...)
+}
+
+func (r *Repository) byIDQuery(userID uuid.UUID) squirrel.SelectBuilder {
+	return r.Builder.
+		Select("email", "COALESCE(password_hash, '')", "role", "created_at", "updated_at", "disabled_at").
 		From("users").
-		Where("email = ?", email).
-		ToSql()
+		Where("id = ?", userID)
+}
 
-	user := entity.User{Email: email}
+func (r *Repository) getByID(ctx context.Context, op string, userID uuid.UUID, query string, args ...any) (entity.User, error) {
+	user := entity.User{ID: userID}
 	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(
-		&user.ID,
+		&user.Email,
 		&user.PasswordHash,
 		&user.Role,
-		&user.RefreshToken,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.DisabledAt,
 	)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			logrus.Warnf("No user found with email: %s", email)
+			logger.FromContext(ctx).Warnf("No user found with id: %s", userID)
 			return entity.User{}, repository.ErrNoUserFound
 		}
-		logrus.Errorf("Failed to fetch user %s: %v", email, err)
-		return entity.User{}, fmt.Errorf("UserRepository.GetByEmail - Scan: %w", err)
+		logger.FromContext(ctx).Errorf("Failed to fetch user %s: %v", userID, err)
+		return entity.User{}, fmt.Errorf("UserRepository.%s - Scan: %w", op, err)
 	}
 
-	logrus.Infof("Fetched user: %+v", user)
+	logger.FromContext(ctx).Infof("Fetched user: %s", user.ID)
 	return user, nil
 }
+
+func (r *Repository) UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error {
+	logger.FromContext(ctx).Infof("Updating password of user: %s", userID)
+
+	query, args, _ := r.Builder.
+		Update("users").
+		Set("password_hash", passwordHash).
+		Set("updated_at", time.Now()).
+		Where("id = ?", userID).
+		ToSql()
+
+	result, err := r.GetTxManager(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to update password of user %s: %v", userID, err)
+		return fmt.Errorf("UserRepository.UpdatePassword - Exec: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		logger.FromContext(ctx).Warnf("No user found with id: %s", userID)
+		return repository.ErrNoUserFound
+	}
+
+	logger.FromContext(ctx).Infof("Password of user %s updated", userID)
+	return nil
+}
+
+func (r *Repository) List(ctx context.Context, filter entity.UserFilter) ([]entity.User, error) {
+	logger.FromContext(ctx).Infof("Fetching users: %+v", filter)
+
+	builder := r.Builder.
+		Select("id", "email", "role", "created_at", "updated_at", "disabled_at").
+		From("users").
+		OrderBy("created_at DESC", "id").
+		Limit(uint64(filter.Limit)).
+		Offset(uint64((filter.Page - 1) * filter.Limit))
+
+	if filter.Query != "" {
+		builder = builder.Where("email ILIKE ?", "%"+likeEscaper.Replace(filter.Query)+"%")
+	}
+	if filter.Role != nil {
+		builder = builder.Where("role = ?", *filter.Role)
+	}
+	if filter.Disabled != nil {
+		if *filter.Disabled {
+			builder = builder.Where("disabled_at IS NOT NULL")
+		} else {
+			builder = builder.Where("disabled_at IS NULL")
+		}
+	}
+
+	query, args, _ := builder.ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to fetch users: %v", err)
+		return nil, fmt.Errorf("UserRepository.List - Query: %w", err)
+	}
+	defer rows.Close()
+
+	var users []entity.User
+	for rows.Next() {
+		var user entity.User
+		if err := rows.Scan(&user.ID, &user.Email, &user.Role, &user.CreatedAt, &user.UpdatedAt, &user.DisabledAt); err != nil {
+			logger.FromContext(ctx).Errorf("Failed to scan user row: %v", err)
+			return nil, fmt.Errorf("UserRepository.List - Scan: %w", err)
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		logger.FromContext(ctx).Errorf("Rows error after fetching users: %v", err)
+		return nil, fmt.Errorf("UserRepository.List - rows.Err: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Fetched %d users", len(users))
+	return users, nil
+}
+
+func (r *Repository) UpdateRole(ctx context.Context, userID uuid.UUID, role entity.UserRole) error {
+	logger.FromContext(ctx).Infof("Updating role of user %s to %s", userID, role)
+
+	query, args, _ := r.Builder.
+		Update("users").
+		Set("role", role).
+		Set("updated_at", time.Now()).
+		Where("id = ?", userID).
+		ToSql()
+
+	result, err := r.GetTxManager(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.ForeignKeyViolation {
+			logger.FromContext(ctx).Warnf("No role found: %s", role)
+			return repository.ErrNoRoleFound
+		}
+		logger.FromContext(ctx).Errorf("Failed to update role of user %s: %v", userID, err)
+		return fmt.Errorf("UserRepository.UpdateRole - Exec: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		logger.FromContext(ctx).Warnf("No user found with id: %s", userID)
+		return repository.ErrNoUserFound
+	}
+
+	logger.FromContext(ctx).Infof("Role of user %s updated", userID)
+	return nil
+}
+
+// SetDisabledAt disables the user, or enables it when disabledAt is nil.
+func (r *Repository) SetDisabledAt(ctx context.Context, userID uuid.UUID, disabledAt *time.Time) error {
+	logger.FromContext(ctx).Infof("Setting disabled_at of user %s to %v", userID, disabledAt)
+
+	query, args, _ := r.Builder.
+		Update("users").
+		Set("disabled_at", disabledAt).
+		Set("updated_at", time.Now()).
+		Where("id = ?", userID).
+		ToSql()
+
+	result, err := r.GetTxManager(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to set disabled_at of user %s: %v", userID, err)
+		return fmt.Errorf("UserRepository.SetDisabledAt - Exec: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		logger.FromContext(ctx).Warnf("No user found with id: %s", userID)
+		return repository.ErrNoUserFound
+	}
+
+	logger.FromContext(ctx).Infof("disabled_at of user %s updated", userID)
+	return nil
+}
+
+func (r *Repository) GetDisabledIDs(ctx context.Context) ([]uuid.UUID, error) {
+	logger.FromContext(ctx).Info("Fetching disabled users")
+
+	query, args, _ := r.Builder.
+		Select("id").
+		From("users").
+		Where("disabled_at IS NOT NULL").
+		ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to fetch disabled users: %v", err)
+		return nil, fmt.Errorf("UserRepository.GetDisabledIDs - Query: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			logger.FromContext(ctx).Errorf("Failed to scan disabled user row: %v", err)
+			return nil, fmt.Errorf("UserRepository.GetDisabledIDs - Scan: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		logger.FromContext(ctx).Errorf("Rows error after fetching disabled users: %v", err)
+		return nil, fmt.Errorf("UserRepository.GetDisabledIDs - rows.Err: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Fetched %d disabled users", len(ids))
+	return ids, nil
+}
+
+// AssignPoints links the user to the pickup points they work at.
+func (r *Repository) AssignPoints(ctx context.Context, userID uuid.UUID, pointIDs []uuid.UUID) error {
+	logger.FromContext(ctx).Infof("Assigning %d points to user %s", len(pointIDs), userID)
+
+	query := `
+        INSERT INTO user_points(user_id, point_id)
+        SELECT $1, UNNEST($2::uuid[])
+        ON CONFLICT DO NOTHING
+    `
+	if _, err := r.GetTxManager(ctx).Exec(ctx, query, userID, pointIDs); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.ForeignKeyViolation {
+			logger.FromContext(ctx).Warnf("No point found among %v", pointIDs)
+			return repository.ErrNoPointFound
+		}
+		logger.FromContext(ctx).Errorf("Failed to assign points to user %s: %v", userID, err)
+		return fmt.Errorf("UserRepository.AssignPoints - Exec: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("Assigned points to user %s", userID)
+	return nil
+}
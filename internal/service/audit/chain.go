@@ -0,0 +1,217 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/pkg/hashchain"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+)
+
+// errChainBroken stops the walk at the first broken link.
+var errChainBroken = errors.New("audit chain broken")
+
+// sealBatchSize is how many records one sealing transaction chains.
+const sealBatchSize = 500
+
+// Seal appends the committed records that are not chained yet to the hash
+// chain, oldest first, and returns how many it chained. Until then a record
+// can be edited unnoticed, so the seal interval bounds that window.
+// Instances seal one at a time.
+func (s *Service) Seal(ctx context.Context) (int, error) {
+	var total int
+	for {
+		var sealed int
+		err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := s.auditRepository.LockChain(ctx); err != nil {
+				return err
+			}
+
+			head, err := s.auditRepository.GetChainHead(ctx)
+			if err != nil {
+				return err
+			}
+
+			records, err := s.auditRepository.ListUnsealed(ctx, sealBatchSize)
+			if err != nil {
+				return err
+			}
+
+			for _, record := range records {
+				record.Seq = head.Seq + 1
+				record.PrevHash = head.Hash
+
+				payload, err := record.ChainPayload()
+				if err != nil {
+					return fmt.Errorf("audit record %s cannot be encoded: %w", record.ID, err)
+				}
+				record.Hash = hashchain.Link(record.PrevHash, payload)
+
+				if err := s.auditRepository.Seal(ctx, record); err != nil {
+					return err
+				}
+				head = entity.AuditCheckpoint{Seq: record.Seq, Hash: record.Hash}
+			}
+			sealed = len(records)
+			return nil
+		})
+		if err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to seal audit records: %v", err)
+			return total, err
+		}
+
+		total += sealed
+		if sealed < sealBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// Checkpoint seals the pending records and signs the chain head. Records
+// written up to it can no longer be edited, even together with every later
+// hash, without the signing key.
+func (s *Service) Checkpoint(ctx context.Context) error {
+	if _, err := s.Seal(ctx); err != nil {
+		return err
+	}
+
+	head, err := s.auditRepository.GetChainHead(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to fetch audit chain head: %v", err)
+		return err
+	}
+	if head.Seq == 0 {
+		return nil
+	}
+
+	head.Signature = s.signer.Sign(head.Seq, head.Hash)
+	if err = s.auditRepository.CreateCheckpoint(ctx, head); err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to write audit checkpoint: %v", err)
+		return err
+	}
+	return nil
+}
+
+// Run seals new records every sealInterval and writes a checkpoint every
+// checkpointInterval until ctx is cancelled.
+func (s *Service) Run(ctx context.Context, sealInterval time.Duration, checkpointInterval time.Duration) {
+	seal := time.NewTicker(sealInterval)
+	defer seal.Stop()
+	checkpoint := time.NewTicker(checkpointInterval)
+	defer checkpoint.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-seal.C:
+			if _, err := s.Seal(ctx); err != nil {
+				logger.FromContext(ctx).Errorf("Audit - Seal: %v", err)
+			}
+		case <-checkpoint.C:
+			if err := s.Checkpoint(ctx); err != nil {
+				logger.FromContext(ctx).Errorf("Audit - Checkpoint: %v", err)
+			}
+		}
+	}
+}
+
+// VerifyChain walks the chain from the first record, recomputing every
+// hash and checking it against the signed checkpoints. It reports the
+// first broken link; an error means the chain could not be read. A record
+// left out of the chain for longer than the sealer needs, two
+// sealIntervals, counts as a break too: until sealed it can be edited
+// unnoticed.
+func (s *Service) VerifyChain(ctx context.Context, sealInterval time.Duration) (entity.AuditChainReport, error) {
+	logger.FromContext(ctx).Info("Service: Verifying audit chain")
+
+	checkpoints, err := s.auditRepository.ListCheckpoints(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to fetch audit checkpoints: %v", err)
+		return entity.AuditChainReport{}, err
+	}
+
+	signed := make(map[int64]entity.AuditCheckpoint, len(checkpoints))
+	for _, checkpoint := range checkpoints {
+		signed[checkpoint.Seq] = checkpoint
+	}
+
+	report := entity.AuditChainReport{Checkpoints: len(checkpoints)}
+	prev := hashchain.Genesis
+
+	err = s.auditRepository.ForEachInChain(ctx, func(record entity.AuditRecord) error {
+		if reason := checkLink(record, report.Records+1, prev, signed, s.signer); reason != "" {
+			report.Break = &entity.AuditChainBreak{Seq: report.Records + 1, RecordID: record.ID, Reason: reason}
+			return errChainBroken
+		}
+		report.Records++
+		prev = record.Hash
+		return nil
+	})
+	if err != nil && !errors.Is(err, errChainBroken) {
+		logger.FromContext(ctx).Errorf("Service: Failed to walk audit chain: %v", err)
+		return entity.AuditChainReport{}, err
+	}
+
+	// Records deleted from the end leave checkpoints past it
+	if report.Break == nil && len(checkpoints) > 0 && checkpoints[len(checkpoints)-1].Seq > report.Records {
+		report.Break = &entity.AuditChainBreak{
+			Seq:    report.Records + 1,
+			Reason: fmt.Sprintf("chain ends before checkpoint %d", checkpoints[len(checkpoints)-1].Seq),
+		}
+	}
+
+	if report.Break == nil {
+		unsealed, err := s.auditRepository.ListUnsealed(ctx, 1)
+		if err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to fetch unsealed audit records: %v", err)
+			return entity.AuditChainReport{}, err
+		}
+		if len(unsealed) > 0 && unsealed[0].CreatedAt.Before(time.Now().Add(-2*sealInterval)) {
+			report.Break = &entity.AuditChainBreak{
+				Seq:      report.Records + 1,
+				RecordID: unsealed[0].ID,
+				Reason:   fmt.Sprintf("record written at %s is not sealed", unsealed[0].CreatedAt.Format(time.RFC3339)),
+			}
+		}
+	}
+
+	if report.Break != nil {
+		logger.FromContext(ctx).Warnf("Service: Audit chain broken at %d: %s", report.Break.Seq, report.Break.Reason)
+	} else {
+		logger.FromContext(ctx).Infof("Service: Audit chain intact, %d records", report.Records)
+	}
+	return report, nil
+}
+
+// checkLink returns why record cannot follow prev at position seq, or an
+// empty string.
+func checkLink(record entity.AuditRecord, seq int64, prev string, signed map[int64]entity.AuditCheckpoint, signer Signer) string {
+	if record.Seq != seq {
+		return fmt.Sprintf("record %d is missing, next is %d", seq, record.Seq)
+	}
+	if record.PrevHash != prev {
+		return "previous hash does not match the previous record"
+	}
+
+	payload, err := record.ChainPayload()
+	if err != nil {
+		return fmt.Sprintf("record cannot be encoded: %v", err)
+	}
+	if hashchain.Link(prev, payload) != record.Hash {
+		return "record does not match its hash"
+	}
+
+	if checkpoint, ok := signed[seq]; ok {
+		if checkpoint.Hash != record.Hash {
+			return "hash does not match the signed checkpoint"
+		}
+		if !signer.Verify(checkpoint.Seq, checkpoint.Hash, checkpoint.Signature) {
+			return "checkpoint signature is invalid"
+		}
+	}
+	return ""
+}
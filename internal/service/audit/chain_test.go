@@ -0,0 +1,449 @@
+package audit_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	mock_transactor "github.com/4udiwe/avito-pvz/internal/mocks"
+	service "github.com/4udiwe/avito-pvz/internal/service/audit"
+	"github.com/4udiwe/avito-pvz/internal/service/audit/mocks"
+	"github.com/4udiwe/avito-pvz/pkg/hashchain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newSigner(t *testing.T, key string) *hashchain.Signer {
+	t.Helper()
+	s, err := hashchain.NewSigner(base64.StdEncoding.EncodeToString([]byte(strings.Repeat(key, 32))))
+	require.NoError(t, err)
+	return s
+}
+
+// chain links n reception records as the repository writes them
+func chain(t *testing.T, n int) []entity.AuditRecord {
+	t.Helper()
+
+	records := make([]entity.AuditRecord, n)
+	prev := hashchain.Genesis
+	for i := range records {
+		records[i] = entity.AuditRecord{
+			ID:         uuid.New(),
+			ActorID:    uuid.New(),
+			Action:     entity.AuditActionReceptionOpened,
+			TargetType: entity.AuditTargetReception,
+			TargetID:   uuid.New(),
+			After:      json.RawMessage(`{"status":"in_progress","kind":"inbound"}`),
+			RequestID:  "req",
+			CreatedAt:  time.Date(2025, 12, 24, 9, 0, i, 0, time.UTC),
+			Seq:        int64(i + 1),
+			PrevHash:   prev,
+		}
+		payload, err := records[i].ChainPayload()
+		require.NoError(t, err)
+		records[i].Hash = hashchain.Link(prev, payload)
+		prev = records[i].Hash
+	}
+	return records
+}
+
+func TestChainPayloadCanonical(t *testing.T) {
+	record := chain(t, 1)[0]
+	want, err := record.ChainPayload()
+	require.NoError(t, err)
+
+	// Postgres returns jsonb with its own key order and spacing
+	record.After = json.RawMessage(`{"kind": "inbound", "status": "in_progress"}`)
+	got, err := record.ChainPayload()
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+
+	record.CreatedAt = record.CreatedAt.In(time.FixedZone("MSK", 3*60*60))
+	got, err = record.ChainPayload()
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+}
+
+// withinTx runs the transaction body of the sealer
+func withinTx(ctx context.Context, tx *mock_transactor.MockTransactor) {
+	tx.EXPECT().WithinTransaction(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+}
+
+// unsealed strips what the sealer fills in from chained records
+func unsealed(records []entity.AuditRecord) []entity.AuditRecord {
+	out := make([]entity.AuditRecord, len(records))
+	for i, record := range records {
+		record.Seq, record.PrevHash, record.Hash = 0, "", ""
+		out[i] = record
+	}
+	return out
+}
+
+func TestSeal(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		arbitraryErr = errors.New("arbitrary error")
+		records      = chain(t, 3)
+		genesis      = entity.AuditCheckpoint{Hash: hashchain.Genesis}
+	)
+
+	type MockBehavior func(r *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		want         int
+		wantErr      error
+	}{
+		{
+			name: "empty chain",
+			mockBehavior: func(r *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				r.EXPECT().LockChain(ctx).Return(nil).Times(1)
+				r.EXPECT().GetChainHead(ctx).Return(genesis, nil).Times(1)
+				r.EXPECT().ListUnsealed(ctx, gomock.Any()).Return(unsealed(records), nil).Times(1)
+				gomock.InOrder(
+					r.EXPECT().Seal(ctx, records[0]).Return(nil),
+					r.EXPECT().Seal(ctx, records[1]).Return(nil),
+					r.EXPECT().Seal(ctx, records[2]).Return(nil),
+				)
+			},
+			want: 3,
+		},
+		{
+			name: "appends to head",
+			mockBehavior: func(r *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				r.EXPECT().LockChain(ctx).Return(nil).Times(1)
+				r.EXPECT().GetChainHead(ctx).Return(entity.AuditCheckpoint{Seq: 1, Hash: records[0].Hash}, nil).Times(1)
+				r.EXPECT().ListUnsealed(ctx, gomock.Any()).Return(unsealed(records[1:]), nil).Times(1)
+				gomock.InOrder(
+					r.EXPECT().Seal(ctx, records[1]).Return(nil),
+					r.EXPECT().Seal(ctx, records[2]).Return(nil),
+				)
+			},
+			want: 2,
+		},
+		{
+			name: "nothing to seal",
+			mockBehavior: func(r *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				r.EXPECT().LockChain(ctx).Return(nil).Times(1)
+				r.EXPECT().GetChainHead(ctx).Return(genesis, nil).Times(1)
+				r.EXPECT().ListUnsealed(ctx, gomock.Any()).Return(nil, nil).Times(1)
+			},
+			want: 0,
+		},
+		{
+			name: "lock error",
+			mockBehavior: func(r *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				r.EXPECT().LockChain(ctx).Return(arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+		{
+			name: "seal error",
+			mockBehavior: func(r *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				r.EXPECT().LockChain(ctx).Return(nil).Times(1)
+				r.EXPECT().GetChainHead(ctx).Return(genesis, nil).Times(1)
+				r.EXPECT().ListUnsealed(ctx, gomock.Any()).Return(unsealed(records), nil).Times(1)
+				r.EXPECT().Seal(ctx, records[0]).Return(arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			MockAuditRepository := mocks.NewMockAuditRepository(ctrl)
+			MockTransactor := mock_transactor.NewMockTransactor(ctrl)
+			tc.mockBehavior(MockAuditRepository, MockTransactor)
+
+			s := service.New(MockAuditRepository, nil, MockTransactor)
+
+			got, err := s.Seal(ctx)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestCheckpoint(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		arbitraryErr = errors.New("arbitrary error")
+		signer       = newSigner(t, "k")
+		head         = entity.AuditCheckpoint{Seq: 3, Hash: hashchain.Link(hashchain.Genesis, []byte("3"))}
+	)
+
+	// nothingToSeal expects a sealing pass that finds no new records
+	nothingToSeal := func(r *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor, head entity.AuditCheckpoint) {
+		withinTx(ctx, tx)
+		r.EXPECT().LockChain(ctx).Return(nil).Times(1)
+		r.EXPECT().GetChainHead(ctx).Return(head, nil).Times(1)
+		r.EXPECT().ListUnsealed(ctx, gomock.Any()).Return(nil, nil).Times(1)
+	}
+
+	type MockBehavior func(r *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		wantErr      error
+	}{
+		{
+			name: "success",
+			mockBehavior: func(r *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor) {
+				nothingToSeal(r, tx, head)
+				r.EXPECT().GetChainHead(ctx).Return(head, nil).Times(1)
+				r.EXPECT().CreateCheckpoint(ctx, gomock.Any()).DoAndReturn(
+					func(_ context.Context, checkpoint entity.AuditCheckpoint) error {
+						assert.Equal(t, head.Seq, checkpoint.Seq)
+						assert.Equal(t, head.Hash, checkpoint.Hash)
+						assert.True(t, signer.Verify(checkpoint.Seq, checkpoint.Hash, checkpoint.Signature))
+						return nil
+					}).Times(1)
+			},
+		},
+		{
+			name: "empty chain",
+			mockBehavior: func(r *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor) {
+				nothingToSeal(r, tx, entity.AuditCheckpoint{Hash: hashchain.Genesis})
+				r.EXPECT().GetChainHead(ctx).Return(entity.AuditCheckpoint{Hash: hashchain.Genesis}, nil).Times(1)
+			},
+		},
+		{
+			name: "seal error",
+			mockBehavior: func(r *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				r.EXPECT().LockChain(ctx).Return(arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+		{
+			name: "head error",
+			mockBehavior: func(r *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor) {
+				nothingToSeal(r, tx, head)
+				r.EXPECT().GetChainHead(ctx).Return(entity.AuditCheckpoint{}, arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+		{
+			name: "create error",
+			mockBehavior: func(r *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor) {
+				nothingToSeal(r, tx, head)
+				r.EXPECT().GetChainHead(ctx).Return(head, nil).Times(1)
+				r.EXPECT().CreateCheckpoint(ctx, gomock.Any()).Return(arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			MockAuditRepository := mocks.NewMockAuditRepository(ctrl)
+			MockTransactor := mock_transactor.NewMockTransactor(ctrl)
+			tc.mockBehavior(MockAuditRepository, MockTransactor)
+
+			s := service.New(MockAuditRepository, signer, MockTransactor)
+
+			err := s.Checkpoint(ctx)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestVerifyChain(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		arbitraryErr = errors.New("arbitrary error")
+		signer       = newSigner(t, "k")
+		otherSigner  = newSigner(t, "o")
+		records      = chain(t, 4)
+		sealInterval = 5 * time.Second
+	)
+
+	// pending is a record written after the chain, not sealed yet
+	pending := func(writtenAgo time.Duration) []entity.AuditRecord {
+		record := unsealed(chain(t, 1))[0]
+		record.CreatedAt = time.Now().Add(-writtenAgo)
+		return []entity.AuditRecord{record}
+	}
+	stale := pending(time.Hour)
+
+	checkpoint := func(s *hashchain.Signer, record entity.AuditRecord) entity.AuditCheckpoint {
+		return entity.AuditCheckpoint{Seq: record.Seq, Hash: record.Hash, Signature: s.Sign(record.Seq, record.Hash)}
+	}
+
+	// rehash rebuilds every hash after records were changed, as someone
+	// with write access to the database but without the key would
+	rehash := func(records []entity.AuditRecord) []entity.AuditRecord {
+		prev := hashchain.Genesis
+		for i := range records {
+			records[i].PrevHash = prev
+			payload, err := records[i].ChainPayload()
+			require.NoError(t, err)
+			records[i].Hash = hashchain.Link(prev, payload)
+			prev = records[i].Hash
+		}
+		return records
+	}
+
+	modify := func(i int, change func(*entity.AuditRecord)) []entity.AuditRecord {
+		changed := append([]entity.AuditRecord(nil), records...)
+		change(&changed[i])
+		return changed
+	}
+
+	for _, tc := range []struct {
+		name        string
+		records     []entity.AuditRecord
+		checkpoints []entity.AuditCheckpoint
+		unsealed    []entity.AuditRecord
+		listErr     error
+		walkErr     error
+		unsealedErr error
+		want        entity.AuditChainReport
+		wantErr     error
+	}{
+		{
+			name:        "intact",
+			records:     records,
+			checkpoints: []entity.AuditCheckpoint{checkpoint(signer, records[1]), checkpoint(signer, records[3])},
+			want:        entity.AuditChainReport{Records: 4, Checkpoints: 2},
+		},
+		{
+			name: "empty",
+			want: entity.AuditChainReport{},
+		},
+		{
+			name:     "record waiting for the sealer",
+			records:  records,
+			unsealed: pending(time.Second),
+			want:     entity.AuditChainReport{Records: 4},
+		},
+		{
+			// e.g. a record whose chain position was cleared, or written
+			// while the sealer was down
+			name:     "record kept out of the chain",
+			records:  records,
+			unsealed: stale,
+			want: entity.AuditChainReport{Records: 4, Break: &entity.AuditChainBreak{
+				Seq: 5, RecordID: stale[0].ID, Reason: "record written at " + stale[0].CreatedAt.Format(time.RFC3339) + " is not sealed",
+			}},
+		},
+		{
+			name: "record edited",
+			records: modify(2, func(r *entity.AuditRecord) {
+				r.After = json.RawMessage(`{"status":"close","kind":"inbound"}`)
+			}),
+			want: entity.AuditChainReport{Records: 2, Break: &entity.AuditChainBreak{
+				Seq: 3, RecordID: records[2].ID, Reason: "record does not match its hash",
+			}},
+		},
+		{
+			name:    "record deleted",
+			records: append(append([]entity.AuditRecord(nil), records[:1]...), records[2:]...),
+			want: entity.AuditChainReport{Records: 1, Break: &entity.AuditChainBreak{
+				Seq: 2, RecordID: records[2].ID, Reason: "record 2 is missing, next is 3",
+			}},
+		},
+		{
+			name: "previous hash replaced",
+			records: modify(1, func(r *entity.AuditRecord) {
+				r.PrevHash = hashchain.Genesis
+			}),
+			want: entity.AuditChainReport{Records: 1, Break: &entity.AuditChainBreak{
+				Seq: 2, RecordID: records[1].ID, Reason: "previous hash does not match the previous record",
+			}},
+		},
+		{
+			name: "chain rehashed after edit",
+			records: rehash(modify(0, func(r *entity.AuditRecord) {
+				r.ActorID = uuid.New()
+			})),
+			checkpoints: []entity.AuditCheckpoint{checkpoint(signer, records[1])},
+			want: entity.AuditChainReport{Records: 1, Checkpoints: 1, Break: &entity.AuditChainBreak{
+				Seq: 2, RecordID: records[1].ID, Reason: "hash does not match the signed checkpoint",
+			}},
+		},
+		{
+			name:        "forged checkpoint",
+			records:     records,
+			checkpoints: []entity.AuditCheckpoint{checkpoint(otherSigner, records[2])},
+			want: entity.AuditChainReport{Records: 2, Checkpoints: 1, Break: &entity.AuditChainBreak{
+				Seq: 3, RecordID: records[2].ID, Reason: "checkpoint signature is invalid",
+			}},
+		},
+		{
+			name:        "tail deleted",
+			records:     records[:2],
+			checkpoints: []entity.AuditCheckpoint{checkpoint(signer, records[3])},
+			want: entity.AuditChainReport{Records: 2, Checkpoints: 1, Break: &entity.AuditChainBreak{
+				Seq: 3, Reason: "chain ends before checkpoint 4",
+			}},
+		},
+		{
+			name:    "checkpoints error",
+			listErr: arbitraryErr,
+			wantErr: arbitraryErr,
+		},
+		{
+			name:    "walk error",
+			walkErr: arbitraryErr,
+			wantErr: arbitraryErr,
+		},
+		{
+			name:        "unsealed records error",
+			records:     records,
+			unsealedErr: arbitraryErr,
+			wantErr:     arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			MockAuditRepository := mocks.NewMockAuditRepository(ctrl)
+			MockAuditRepository.EXPECT().ListCheckpoints(ctx).Return(tc.checkpoints, tc.listErr).Times(1)
+			if tc.listErr == nil {
+				MockAuditRepository.EXPECT().ForEachInChain(ctx, gomock.Any()).DoAndReturn(
+					func(_ context.Context, fn func(entity.AuditRecord) error) error {
+						if tc.walkErr != nil {
+							return tc.walkErr
+						}
+						for _, r := range tc.records {
+							if err := fn(r); err != nil {
+								return err
+							}
+						}
+						return nil
+					}).Times(1)
+			}
+
+			if tc.listErr == nil && tc.walkErr == nil && (tc.want.Break == nil || tc.unsealed != nil) {
+				MockAuditRepository.EXPECT().ListUnsealed(ctx, 1).Return(tc.unsealed, tc.unsealedErr).Times(1)
+			}
+
+			s := service.New(MockAuditRepository, signer, nil)
+
+			report, err := s.VerifyChain(ctx, sealInterval)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.want, report)
+		})
+	}
+}
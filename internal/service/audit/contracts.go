@@ -0,0 +1,26 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mocks.go -package=mocks
+
+type AuditRepository interface {
+	List(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditRecord, error)
+	ForEach(ctx context.Context, filter entity.AuditFilter, fn func(entity.AuditRecord) error) error
+	GetChainHead(ctx context.Context) (entity.AuditCheckpoint, error)
+	LockChain(ctx context.Context) error
+	ListUnsealed(ctx context.Context, limit int) ([]entity.AuditRecord, error)
+	Seal(ctx context.Context, record entity.AuditRecord) error
+	ForEachInChain(ctx context.Context, fn func(entity.AuditRecord) error) error
+	CreateCheckpoint(ctx context.Context, checkpoint entity.AuditCheckpoint) error
+	ListCheckpoints(ctx context.Context) ([]entity.AuditCheckpoint, error)
+}
+
+type Signer interface {
+	Sign(seq int64, hash string) string
+	Verify(seq int64, hash, signature string) bool
+}
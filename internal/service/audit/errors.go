@@ -0,0 +1,5 @@
+package audit
+
+import "errors"
+
+var ErrInvalidPeriod = errors.New("period start must be before its end")
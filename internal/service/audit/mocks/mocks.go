@@ -0,0 +1,224 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: contracts.go
+//
+// Generated by this command:
+//
+//	mockgen -source=contracts.go -destination=mocks/mocks.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/4udiwe/avito-pvz/internal/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAuditRepository is a mock of AuditRepository interface.
+type MockAuditRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAuditRepositoryMockRecorder is the mock recorder for MockAuditRepository.
+type MockAuditRepositoryMockRecorder struct {
+	mock *MockAuditRepository
+}
+
+// NewMockAuditRepository creates a new mock instance.
+func NewMockAuditRepository(ctrl *gomock.Controller) *MockAuditRepository {
+	mock := &MockAuditRepository{ctrl: ctrl}
+	mock.recorder = &MockAuditRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditRepository) EXPECT() *MockAuditRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateCheckpoint mocks base method.
+func (m *MockAuditRepository) CreateCheckpoint(ctx context.Context, checkpoint entity.AuditCheckpoint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCheckpoint", ctx, checkpoint)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCheckpoint indicates an expected call of CreateCheckpoint.
+func (mr *MockAuditRepositoryMockRecorder) CreateCheckpoint(ctx, checkpoint any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCheckpoint", reflect.TypeOf((*MockAuditRepository)(nil).CreateCheckpoint), ctx, checkpoint)
+}
+
+// ForEach mocks base method.
+func (m *MockAuditRepository) ForEach(ctx context.Context, filter entity.AuditFilter, fn func(entity.AuditRecord) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForEach", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForEach indicates an expected call of ForEach.
+func (mr *MockAuditRepositoryMockRecorder) ForEach(ctx, filter, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForEach", reflect.TypeOf((*MockAuditRepository)(nil).ForEach), ctx, filter, fn)
+}
+
+// ForEachInChain mocks base method.
+func (m *MockAuditRepository) ForEachInChain(ctx context.Context, fn func(entity.AuditRecord) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForEachInChain", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForEachInChain indicates an expected call of ForEachInChain.
+func (mr *MockAuditRepositoryMockRecorder) ForEachInChain(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForEachInChain", reflect.TypeOf((*MockAuditRepository)(nil).ForEachInChain), ctx, fn)
+}
+
+// GetChainHead mocks base method.
+func (m *MockAuditRepository) GetChainHead(ctx context.Context) (entity.AuditCheckpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChainHead", ctx)
+	ret0, _ := ret[0].(entity.AuditCheckpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChainHead indicates an expected call of GetChainHead.
+func (mr *MockAuditRepositoryMockRecorder) GetChainHead(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChainHead", reflect.TypeOf((*MockAuditRepository)(nil).GetChainHead), ctx)
+}
+
+// List mocks base method.
+func (m *MockAuditRepository) List(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].([]entity.AuditRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAuditRepositoryMockRecorder) List(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAuditRepository)(nil).List), ctx, filter)
+}
+
+// ListCheckpoints mocks base method.
+func (m *MockAuditRepository) ListCheckpoints(ctx context.Context) ([]entity.AuditCheckpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCheckpoints", ctx)
+	ret0, _ := ret[0].([]entity.AuditCheckpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCheckpoints indicates an expected call of ListCheckpoints.
+func (mr *MockAuditRepositoryMockRecorder) ListCheckpoints(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCheckpoints", reflect.TypeOf((*MockAuditRepository)(nil).ListCheckpoints), ctx)
+}
+
+// ListUnsealed mocks base method.
+func (m *MockAuditRepository) ListUnsealed(ctx context.Context, limit int) ([]entity.AuditRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnsealed", ctx, limit)
+	ret0, _ := ret[0].([]entity.AuditRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnsealed indicates an expected call of ListUnsealed.
+func (mr *MockAuditRepositoryMockRecorder) ListUnsealed(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnsealed", reflect.TypeOf((*MockAuditRepository)(nil).ListUnsealed), ctx, limit)
+}
+
+// LockChain mocks base method.
+func (m *MockAuditRepository) LockChain(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockChain", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockChain indicates an expected call of LockChain.
+func (mr *MockAuditRepositoryMockRecorder) LockChain(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockChain", reflect.TypeOf((*MockAuditRepository)(nil).LockChain), ctx)
+}
+
+// Seal mocks base method.
+func (m *MockAuditRepository) Seal(ctx context.Context, record entity.AuditRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Seal", ctx, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Seal indicates an expected call of Seal.
+func (mr *MockAuditRepositoryMockRecorder) Seal(ctx, record any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Seal", reflect.TypeOf((*MockAuditRepository)(nil).Seal), ctx, record)
+}
+
+// MockSigner is a mock of Signer interface.
+type MockSigner struct {
+	ctrl     *gomock.Controller
+	recorder *MockSignerMockRecorder
+	isgomock struct{}
+}
+
+// MockSignerMockRecorder is the mock recorder for MockSigner.
+type MockSignerMockRecorder struct {
+	mock *MockSigner
+}
+
+// NewMockSigner creates a new mock instance.
+func NewMockSigner(ctrl *gomock.Controller) *MockSigner {
+	mock := &MockSigner{ctrl: ctrl}
+	mock.recorder = &MockSignerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSigner) EXPECT() *MockSignerMockRecorder {
+	return m.recorder
+}
+
+// Sign mocks base method.
+func (m *MockSigner) Sign(seq int64, hash string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sign", seq, hash)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Sign indicates an expected call of Sign.
+func (mr *MockSignerMockRecorder) Sign(seq, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sign", reflect.TypeOf((*MockSigner)(nil).Sign), seq, hash)
+}
+
+// Verify mocks base method.
+func (m *MockSigner) Verify(seq int64, hash, signature string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", seq, hash, signature)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockSignerMockRecorder) Verify(seq, hash, signature any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockSigner)(nil).Verify), seq, hash, signature)
+}
@@ -0,0 +1,83 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/4udiwe/avito-pvz/pkg/transactor"
+)
+
+const (
+	defaultRecordsLimit = 20
+	maxRecordsLimit     = 100
+)
+
+// Service reads the audit log and guards its hash chain. Records are
+// written by the services making the changes, within their own
+// transactions, and chained by Seal afterwards.
+type Service struct {
+	auditRepository AuditRepository
+	signer          Signer
+	txManager       transactor.Transactor
+}
+
+func New(a AuditRepository, signer Signer, txManager transactor.Transactor) *Service {
+	return &Service{auditRepository: a, signer: signer, txManager: txManager}
+}
+
+func (s *Service) List(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditRecord, error) {
+	logger.FromContext(ctx).Infof("Service: Fetching audit records: %+v", filter)
+
+	if err := checkPeriod(ctx, filter); err != nil {
+		return nil, err
+	}
+
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 {
+		filter.Limit = defaultRecordsLimit
+	}
+	filter.Limit = min(filter.Limit, maxRecordsLimit)
+
+	records, err := s.auditRepository.List(ctx, filter)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to fetch audit records: %v", err)
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Infof("Service: Fetched %d audit records", len(records))
+	return records, nil
+}
+
+// Export passes every matching record to fn, oldest first, without paging.
+// It stops at the first error returned by fn.
+func (s *Service) Export(ctx context.Context, filter entity.AuditFilter, fn func(entity.AuditRecord) error) error {
+	logger.FromContext(ctx).Infof("Service: Exporting audit records: %+v", filter)
+
+	if err := checkPeriod(ctx, filter); err != nil {
+		return err
+	}
+
+	var exported int
+	err := s.auditRepository.ForEach(ctx, filter, func(record entity.AuditRecord) error {
+		exported++
+		return fn(record)
+	})
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to export audit records: %v", err)
+		return err
+	}
+
+	logger.FromContext(ctx).Infof("Service: Exported %d audit records", exported)
+	return nil
+}
+
+func checkPeriod(ctx context.Context, filter entity.AuditFilter) error {
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		logger.FromContext(ctx).Warnf("Service: Invalid audit period %s - %s", filter.From, filter.To)
+		return ErrInvalidPeriod
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package audit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	service "github.com/4udiwe/avito-pvz/internal/service/audit"
+	"github.com/4udiwe/avito-pvz/internal/service/audit/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestList(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		arbitraryErr = errors.New("arbitrary error")
+		actorID      = uuid.New()
+		from         = time.Now().Add(-time.Hour)
+		to           = time.Now()
+		records      = []entity.AuditRecord{{ID: uuid.New(), ActorID: actorID, Action: entity.AuditActionPointCreated}}
+	)
+
+	type MockBehavior func(r *mocks.MockAuditRepository)
+
+	for _, tc := range []struct {
+		name         string
+		filter       entity.AuditFilter
+		mockBehavior MockBehavior
+		want         []entity.AuditRecord
+		wantErr      error
+	}{
+		{
+			name:   "defaults applied",
+			filter: entity.AuditFilter{ActorID: &actorID},
+			mockBehavior: func(r *mocks.MockAuditRepository) {
+				r.EXPECT().List(ctx, entity.AuditFilter{ActorID: &actorID, Page: 1, Limit: 20}).Return(records, nil).Times(1)
+			},
+			want: records,
+		},
+		{
+			name:   "limit capped",
+			filter: entity.AuditFilter{From: &from, To: &to, Page: 2, Limit: 1000},
+			mockBehavior: func(r *mocks.MockAuditRepository) {
+				r.EXPECT().List(ctx, entity.AuditFilter{From: &from, To: &to, Page: 2, Limit: 100}).Return(records, nil).Times(1)
+			},
+			want: records,
+		},
+		{
+			name:         "invalid period",
+			filter:       entity.AuditFilter{From: &to, To: &from},
+			mockBehavior: func(r *mocks.MockAuditRepository) {},
+			wantErr:      service.ErrInvalidPeriod,
+		},
+		{
+			name:   "repository error",
+			filter: entity.AuditFilter{Page: 1, Limit: 10},
+			mockBehavior: func(r *mocks.MockAuditRepository) {
+				r.EXPECT().List(ctx, entity.AuditFilter{Page: 1, Limit: 10}).Return(nil, arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			MockAuditRepository := mocks.NewMockAuditRepository(ctrl)
+			tc.mockBehavior(MockAuditRepository)
+
+			s := service.New(MockAuditRepository, nil, nil)
+
+			out, err := s.List(ctx, tc.filter)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.want, out)
+		})
+	}
+}
+
+func TestExport(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		arbitraryErr = errors.New("arbitrary error")
+		from         = time.Now().Add(-time.Hour)
+		to           = time.Now()
+		records      = []entity.AuditRecord{
+			{ID: uuid.New(), Action: entity.AuditActionReceptionOpened},
+			{ID: uuid.New(), Action: entity.AuditActionReceptionClosed},
+		}
+	)
+
+	// stream feeds the records to the callback as the repository does
+	stream := func(_ context.Context, _ entity.AuditFilter, fn func(entity.AuditRecord) error) error {
+		for _, r := range records {
+			if err := fn(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	type MockBehavior func(r *mocks.MockAuditRepository)
+
+	for _, tc := range []struct {
+		name         string
+		filter       entity.AuditFilter
+		fnErr        error
+		mockBehavior MockBehavior
+		want         []entity.AuditRecord
+		wantErr      error
+	}{
+		{
+			name:   "success",
+			filter: entity.AuditFilter{From: &from, To: &to},
+			mockBehavior: func(r *mocks.MockAuditRepository) {
+				r.EXPECT().ForEach(ctx, entity.AuditFilter{From: &from, To: &to}, gomock.Any()).DoAndReturn(stream).Times(1)
+			},
+			want: records,
+		},
+		{
+			name:   "callback error stops export",
+			fnErr:  arbitraryErr,
+			filter: entity.AuditFilter{},
+			mockBehavior: func(r *mocks.MockAuditRepository) {
+				r.EXPECT().ForEach(ctx, entity.AuditFilter{}, gomock.Any()).DoAndReturn(stream).Times(1)
+			},
+			want:    records[:1],
+			wantErr: arbitraryErr,
+		},
+		{
+			name:         "invalid period",
+			filter:       entity.AuditFilter{From: &to, To: &to},
+			mockBehavior: func(r *mocks.MockAuditRepository) {},
+			wantErr:      service.ErrInvalidPeriod,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			MockAuditRepository := mocks.NewMockAuditRepository(ctrl)
+			tc.mockBehavior(MockAuditRepository)
+
+			s := service.New(MockAuditRepository, nil, nil)
+
+			var out []entity.AuditRecord
+			err := s.Export(ctx, tc.filter, func(r entity.AuditRecord) error {
+				out = append(out, r)
+				return tc.fnErr
+			})
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.want, out)
+		})
+	}
+}
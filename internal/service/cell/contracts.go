@@ -0,0 +1,28 @@
+package cell
+
+import (
+	"context"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/google/uuid"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mocks.go -package=mocks
+
+type CellRepository interface {
+	Create(ctx context.Context, cell entity.StorageCell) (entity.StorageCell, error)
+	GetAllByPoint(ctx context.Context, pointID uuid.UUID) ([]entity.StorageCell, error)
+	GetByID(ctx context.Context, cellID uuid.UUID) (entity.StorageCell, error)
+	GetByIDForUpdate(ctx context.Context, cellID uuid.UUID) (entity.StorageCell, error)
+	Suggest(ctx context.Context, pointID uuid.UUID, productType entity.ProductType) (entity.StorageCell, error)
+	SuggestForUpdate(ctx context.Context, pointID uuid.UUID, productType entity.ProductType) (entity.StorageCell, error)
+	CountOccupied(ctx context.Context, cellID uuid.UUID) (int, error)
+}
+
+type ProductRepository interface {
+	GetByID(ctx context.Context, productID uuid.UUID) (entity.Product, error)
+	GetByIDForUpdate(ctx context.Context, productID uuid.UUID) (entity.Product, error)
+	GetByBarcode(ctx context.Context, barcode string) (entity.Product, error)
+	UpdateCell(ctx context.Context, productID uuid.UUID, cellID uuid.UUID) error
+	CreateHistory(ctx context.Context, history entity.ProductHistory) (entity.ProductHistory, error)
+}
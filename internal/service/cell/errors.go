@@ -0,0 +1,16 @@
+package cell
+
+import "errors"
+
+var (
+	ErrNoPointFound      = errors.New("no point found")
+	ErrNoCellFound       = errors.New("no storage cell found")
+	ErrNoProductFound    = errors.New("no product found")
+	ErrCellAlreadyExists = errors.New("storage cell already exists")
+	ErrProductNotPresent = errors.New("product is not physically at the point")
+	ErrCellWrongPoint    = errors.New("storage cell belongs to another point")
+	ErrCellIncompatible  = errors.New("storage cell does not accept this product type")
+	ErrCellFull          = errors.New("storage cell is full")
+	ErrNoFreeCell        = errors.New("no free compatible storage cell")
+	ErrProductNotPlaced  = errors.New("product is not placed into a storage cell")
+)
@@ -0,0 +1,246 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: contracts.go
+//
+// Generated by this command:
+//
+//	mockgen -source=contracts.go -destination=mocks/mocks.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/4udiwe/avito-pvz/internal/entity"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCellRepository is a mock of CellRepository interface.
+type MockCellRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCellRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockCellRepositoryMockRecorder is the mock recorder for MockCellRepository.
+type MockCellRepositoryMockRecorder struct {
+	mock *MockCellRepository
+}
+
+// NewMockCellRepository creates a new mock instance.
+func NewMockCellRepository(ctrl *gomock.Controller) *MockCellRepository {
+	mock := &MockCellRepository{ctrl: ctrl}
+	mock.recorder = &MockCellRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCellRepository) EXPECT() *MockCellRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountOccupied mocks base method.
+func (m *MockCellRepository) CountOccupied(ctx context.Context, cellID uuid.UUID) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountOccupied", ctx, cellID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountOccupied indicates an expected call of CountOccupied.
+func (mr *MockCellRepositoryMockRecorder) CountOccupied(ctx, cellID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOccupied", reflect.TypeOf((*MockCellRepository)(nil).CountOccupied), ctx, cellID)
+}
+
+// Create mocks base method.
+func (m *MockCellRepository) Create(ctx context.Context, cell entity.StorageCell) (entity.StorageCell, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, cell)
+	ret0, _ := ret[0].(entity.StorageCell)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCellRepositoryMockRecorder) Create(ctx, cell any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCellRepository)(nil).Create), ctx, cell)
+}
+
+// GetAllByPoint mocks base method.
+func (m *MockCellRepository) GetAllByPoint(ctx context.Context, pointID uuid.UUID) ([]entity.StorageCell, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllByPoint", ctx, pointID)
+	ret0, _ := ret[0].([]entity.StorageCell)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllByPoint indicates an expected call of GetAllByPoint.
+func (mr *MockCellRepositoryMockRecorder) GetAllByPoint(ctx, pointID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByPoint", reflect.TypeOf((*MockCellRepository)(nil).GetAllByPoint), ctx, pointID)
+}
+
+// GetByID mocks base method.
+func (m *MockCellRepository) GetByID(ctx context.Context, cellID uuid.UUID) (entity.StorageCell, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, cellID)
+	ret0, _ := ret[0].(entity.StorageCell)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockCellRepositoryMockRecorder) GetByID(ctx, cellID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockCellRepository)(nil).GetByID), ctx, cellID)
+}
+
+// GetByIDForUpdate mocks base method.
+func (m *MockCellRepository) GetByIDForUpdate(ctx context.Context, cellID uuid.UUID) (entity.StorageCell, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDForUpdate", ctx, cellID)
+	ret0, _ := ret[0].(entity.StorageCell)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDForUpdate indicates an expected call of GetByIDForUpdate.
+func (mr *MockCellRepositoryMockRecorder) GetByIDForUpdate(ctx, cellID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDForUpdate", reflect.TypeOf((*MockCellRepository)(nil).GetByIDForUpdate), ctx, cellID)
+}
+
+// Suggest mocks base method.
+func (m *MockCellRepository) Suggest(ctx context.Context, pointID uuid.UUID, productType entity.ProductType) (entity.StorageCell, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Suggest", ctx, pointID, productType)
+	ret0, _ := ret[0].(entity.StorageCell)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Suggest indicates an expected call of Suggest.
+func (mr *MockCellRepositoryMockRecorder) Suggest(ctx, pointID, productType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Suggest", reflect.TypeOf((*MockCellRepository)(nil).Suggest), ctx, pointID, productType)
+}
+
+// SuggestForUpdate mocks base method.
+func (m *MockCellRepository) SuggestForUpdate(ctx context.Context, pointID uuid.UUID, productType entity.ProductType) (entity.StorageCell, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SuggestForUpdate", ctx, pointID, productType)
+	ret0, _ := ret[0].(entity.StorageCell)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SuggestForUpdate indicates an expected call of SuggestForUpdate.
+func (mr *MockCellRepositoryMockRecorder) SuggestForUpdate(ctx, pointID, productType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuggestForUpdate", reflect.TypeOf((*MockCellRepository)(nil).SuggestForUpdate), ctx, pointID, productType)
+}
+
+// MockProductRepository is a mock of ProductRepository interface.
+type MockProductRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockProductRepositoryMockRecorder is the mock recorder for MockProductRepository.
+type MockProductRepositoryMockRecorder struct {
+	mock *MockProductRepository
+}
+
+// NewMockProductRepository creates a new mock instance.
+func NewMockProductRepository(ctrl *gomock.Controller) *MockProductRepository {
+	mock := &MockProductRepository{ctrl: ctrl}
+	mock.recorder = &MockProductRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductRepository) EXPECT() *MockProductRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateHistory mocks base method.
+func (m *MockProductRepository) CreateHistory(ctx context.Context, history entity.ProductHistory) (entity.ProductHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHistory", ctx, history)
+	ret0, _ := ret[0].(entity.ProductHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHistory indicates an expected call of CreateHistory.
+func (mr *MockProductRepositoryMockRecorder) CreateHistory(ctx, history any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHistory", reflect.TypeOf((*MockProductRepository)(nil).CreateHistory), ctx, history)
+}
+
+// GetByBarcode mocks base method.
+func (m *MockProductRepository) GetByBarcode(ctx context.Context, barcode string) (entity.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByBarcode", ctx, barcode)
+	ret0, _ := ret[0].(entity.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByBarcode indicates an expected call of GetByBarcode.
+func (mr *MockProductRepositoryMockRecorder) GetByBarcode(ctx, barcode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByBarcode", reflect.TypeOf((*MockProductRepository)(nil).GetByBarcode), ctx, barcode)
+}
+
+// GetByID mocks base method.
+func (m *MockProductRepository) GetByID(ctx context.Context, productID uuid.UUID) (entity.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, productID)
+	ret0, _ := ret[0].(entity.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockProductRepositoryMockRecorder) GetByID(ctx, productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockProductRepository)(nil).GetByID), ctx, productID)
+}
+
+// GetByIDForUpdate mocks base method.
+func (m *MockProductRepository) GetByIDForUpdate(ctx context.Context, productID uuid.UUID) (entity.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDForUpdate", ctx, productID)
+	ret0, _ := ret[0].(entity.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDForUpdate indicates an expected call of GetByIDForUpdate.
+func (mr *MockProductRepositoryMockRecorder) GetByIDForUpdate(ctx, productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDForUpdate", reflect.TypeOf((*MockProductRepository)(nil).GetByIDForUpdate), ctx, productID)
+}
+
+// UpdateCell mocks base method.
+func (m *MockProductRepository) UpdateCell(ctx context.Context, productID, cellID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCell", ctx, productID, cellID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCell indicates an expected call of UpdateCell.
+func (mr *MockProductRepositoryMockRecorder) UpdateCell(ctx, productID, cellID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCell", reflect.TypeOf((*MockProductRepository)(nil).UpdateCell), ctx, productID, cellID)
+}
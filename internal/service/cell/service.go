@@ -0,0 +1,261 @@
+package cell
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/repository"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/4udiwe/avito-pvz/pkg/transactor"
+	"github.com/google/uuid"
+)
+
+// suggestAttempts bounds how many suggested cells AssignProduct locks
+// before giving up when each turns out filled concurrently.
+const suggestAttempts = 3
+
+type Service struct {
+	cellRepository    CellRepository
+	productRepository ProductRepository
+	txManager         transactor.Transactor
+}
+
+func New(c CellRepository, p ProductRepository, tx transactor.Transactor) *Service {
+	return &Service{
+		cellRepository:    c,
+		productRepository: p,
+		txManager:         tx,
+	}
+}
+
+func (s *Service) CreateCell(ctx context.Context, cell entity.StorageCell) (entity.StorageCell, error) {
+	logger.FromContext(ctx).Infof("Service: Creating storage cell %s/%s/%s for point: %s", cell.Rack, cell.Shelf, cell.Bin, cell.PointID)
+
+	out, err := s.cellRepository.Create(ctx, cell)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to create storage cell: %v", err)
+		switch {
+		case errors.Is(err, repository.ErrNoPointFound):
+			return entity.StorageCell{}, ErrNoPointFound
+		case errors.Is(err, repository.ErrCellAlreadyExists):
+			return entity.StorageCell{}, ErrCellAlreadyExists
+		}
+		return entity.StorageCell{}, err
+	}
+
+	logger.FromContext(ctx).Infof("Service: Storage cell created: %s", out.ID)
+	return out, nil
+}
+
+func (s *Service) GetCells(ctx context.Context, pointID uuid.UUID) ([]entity.StorageCell, error) {
+	logger.FromContext(ctx).Infof("Service: Fetching storage cells for point: %s", pointID)
+
+	cells, err := s.cellRepository.GetAllByPoint(ctx, pointID)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to fetch storage cells for point %s: %v", pointID, err)
+		return nil, err
+	}
+
+	return cells, nil
+}
+
+// SuggestCell returns the emptiest cell of the product's point that accepts
+// its type. Nothing is reserved; AssignProduct locks the cell it places into.
+func (s *Service) SuggestCell(ctx context.Context, productID uuid.UUID) (entity.StorageCell, error) {
+	logger.FromContext(ctx).Infof("Service: Suggesting storage cell for product: %s", productID)
+
+	product, err := s.productRepository.GetByID(ctx, productID)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to get product %s: %v", productID, err)
+		return entity.StorageCell{}, mapError(err)
+	}
+
+	cell, err := s.cellRepository.Suggest(ctx, product.PointID, product.Type)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to suggest storage cell for product %s: %v", productID, err)
+		if errors.Is(err, repository.ErrNoCellFound) {
+			return entity.StorageCell{}, ErrNoFreeCell
+		}
+		return entity.StorageCell{}, err
+	}
+
+	return cell, nil
+}
+
+// AssignProduct places a product into the given cell, or into the suggested
+// one when cellID is nil. Moves between cells are recorded in product history.
+func (s *Service) AssignProduct(ctx context.Context, productID uuid.UUID, cellID *uuid.UUID, actorID uuid.UUID) (entity.StorageCell, error) {
+	logger.FromContext(ctx).Infof("Service: Assigning product %s to storage cell %v by %s", productID, cellID, actorID)
+
+	var out entity.StorageCell
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		product, err := s.productRepository.GetByIDForUpdate(ctx, productID)
+		if err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to get product %s: %v", productID, err)
+			return err
+		}
+
+		if product.Status != entity.ProductStatusReceived && product.Status != entity.ProductStatusStored {
+			logger.FromContext(ctx).Warnf("Service: Product %s is %s and cannot be placed", productID, product.Status)
+			return ErrProductNotPresent
+		}
+
+		var cell entity.StorageCell
+		if cellID == nil {
+			cell, err = s.suggestForUpdate(ctx, product)
+		} else {
+			cell, err = s.lockCell(ctx, *cellID)
+		}
+		if err != nil {
+			return err
+		}
+
+		if product.CellID != nil && *product.CellID == cell.ID {
+			out = cell
+			return nil
+		}
+
+		if cell.PointID != product.PointID {
+			logger.FromContext(ctx).Warnf("Service: Storage cell %s is not at point %s", cell.ID, product.PointID)
+			return ErrCellWrongPoint
+		}
+		if !cell.Accepts(product.Type) {
+			logger.FromContext(ctx).Warnf("Service: Storage cell %s does not accept %s", cell.ID, product.Type)
+			return ErrCellIncompatible
+		}
+		if cell.IsFull() {
+			logger.FromContext(ctx).Warnf("Service: Storage cell %s is full", cell.ID)
+			return ErrCellFull
+		}
+
+		if err = s.productRepository.UpdateCell(ctx, productID, cell.ID); err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to place product %s: %v", productID, err)
+			return err
+		}
+
+		status := product.Status
+		_, err = s.productRepository.CreateHistory(ctx, entity.ProductHistory{
+			ProductID:  productID,
+			FromStatus: &status,
+			ToStatus:   status,
+			Reason:     fmt.Sprintf("placed into cell %s-%s-%s", cell.Rack, cell.Shelf, cell.Bin),
+			ActorID:    actorID,
+			FromCellID: product.CellID,
+			ToCellID:   &cell.ID,
+		})
+		if err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to write history for product %s: %v", productID, err)
+			return err
+		}
+
+		cell.Occupied++
+		out = cell
+		return nil
+	})
+
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to assign product %s: %v", productID, err)
+		return entity.StorageCell{}, mapError(err)
+	}
+
+	logger.FromContext(ctx).Infof("Service: Product %s placed into storage cell %s", productID, out.ID)
+	return out, nil
+}
+
+// LocateProduct finds a product by ID or, when productID is nil, by barcode
+// and returns the cell it is placed in.
+func (s *Service) LocateProduct(ctx context.Context, productID *uuid.UUID, barcode string) (entity.Product, entity.StorageCell, error) {
+	logger.FromContext(ctx).Infof("Service: Locating product %v / %s", productID, barcode)
+
+	var (
+		product entity.Product
+		err     error
+	)
+	if productID != nil {
+		product, err = s.productRepository.GetByID(ctx, *productID)
+	} else {
+		product, err = s.productRepository.GetByBarcode(ctx, barcode)
+	}
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to get product: %v", err)
+		return entity.Product{}, entity.StorageCell{}, mapError(err)
+	}
+
+	if product.CellID == nil {
+		logger.FromContext(ctx).Warnf("Service: Product %s is not placed", product.ID)
+		return entity.Product{}, entity.StorageCell{}, ErrProductNotPlaced
+	}
+
+	cell, err := s.cellRepository.GetByID(ctx, *product.CellID)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to get storage cell %s: %v", *product.CellID, err)
+		return entity.Product{}, entity.StorageCell{}, mapError(err)
+	}
+
+	return product, cell, nil
+}
+
+func mapError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrNoProductFound):
+		return ErrNoProductFound
+	case errors.Is(err, repository.ErrNoCellFound):
+		return ErrNoCellFound
+	}
+	return err
+}
+
+// lockCell locks the cell and recounts its occupancy: the count read
+// together with the lock comes from the statement snapshot and misses
+// placements committed while the lock was awaited.
+func (s *Service) lockCell(ctx context.Context, cellID uuid.UUID) (entity.StorageCell, error) {
+	cell, err := s.cellRepository.GetByIDForUpdate(ctx, cellID)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to get storage cell %s: %v", cellID, err)
+		return entity.StorageCell{}, err
+	}
+
+	if err = s.recountOccupied(ctx, &cell); err != nil {
+		return entity.StorageCell{}, err
+	}
+	return cell, nil
+}
+
+// suggestForUpdate locks the suggested cell for the product. A suggested
+// cell that turns out full once locked was filled concurrently; the next
+// suggestion sees that placement and picks another cell.
+func (s *Service) suggestForUpdate(ctx context.Context, product entity.Product) (entity.StorageCell, error) {
+	for range suggestAttempts {
+		cell, err := s.cellRepository.SuggestForUpdate(ctx, product.PointID, product.Type)
+		if errors.Is(err, repository.ErrNoCellFound) {
+			logger.FromContext(ctx).Warnf("Service: No free storage cell for product %s", product.ID)
+			return entity.StorageCell{}, ErrNoFreeCell
+		}
+		if err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to suggest storage cell: %v", err)
+			return entity.StorageCell{}, err
+		}
+
+		if err = s.recountOccupied(ctx, &cell); err != nil {
+			return entity.StorageCell{}, err
+		}
+		if !cell.IsFull() || (product.CellID != nil && *product.CellID == cell.ID) {
+			return cell, nil
+		}
+		logger.FromContext(ctx).Warnf("Service: Suggested storage cell %s was filled concurrently", cell.ID)
+	}
+
+	return entity.StorageCell{}, ErrNoFreeCell
+}
+
+func (s *Service) recountOccupied(ctx context.Context, cell *entity.StorageCell) error {
+	occupied, err := s.cellRepository.CountOccupied(ctx, cell.ID)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to count products in storage cell %s: %v", cell.ID, err)
+		return err
+	}
+	cell.Occupied = occupied
+	return nil
+}
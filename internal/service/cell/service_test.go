@@ -0,0 +1,451 @@
+package cell_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	mock_transactor "github.com/4udiwe/avito-pvz/internal/mocks"
+	"github.com/4udiwe/avito-pvz/internal/repository"
+	service "github.com/4udiwe/avito-pvz/internal/service/cell"
+	"github.com/4udiwe/avito-pvz/internal/service/cell/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type MockBehavior func(
+	c *mocks.MockCellRepository,
+	p *mocks.MockProductRepository,
+	tx *mock_transactor.MockTransactor,
+)
+
+func withinTx(ctx context.Context, tx *mock_transactor.MockTransactor) {
+	tx.EXPECT().WithinTransaction(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+}
+
+func newService(t *testing.T, mockBehavior MockBehavior) *service.Service {
+	ctrl := gomock.NewController(t)
+
+	MockCellRepository := mocks.NewMockCellRepository(ctrl)
+	MockProductRepository := mocks.NewMockProductRepository(ctrl)
+	MockTransactor := mock_transactor.NewMockTransactor(ctrl)
+
+	mockBehavior(MockCellRepository, MockProductRepository, MockTransactor)
+
+	return service.New(MockCellRepository, MockProductRepository, MockTransactor)
+}
+
+func TestCreateCell(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		arbitraryErr = errors.New("arbitrary error")
+		shoes        = entity.ProductTypeShoes
+
+		cell    = entity.StorageCell{PointID: uuid.New(), Rack: "A", Shelf: "1", Bin: "3", Capacity: 10, ProductType: &shoes}
+		created = entity.StorageCell{ID: uuid.New(), PointID: cell.PointID, Rack: "A", Shelf: "1", Bin: "3", Capacity: 10, ProductType: &shoes}
+	)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		want         entity.StorageCell
+		wantErr      error
+	}{
+		{
+			name: "success",
+			mockBehavior: func(c *mocks.MockCellRepository, p *mocks.MockProductRepository, tx *mock_transactor.MockTransactor) {
+				c.EXPECT().Create(ctx, cell).Return(created, nil)
+			},
+			want: created,
+		},
+		{
+			name: "no point",
+			mockBehavior: func(c *mocks.MockCellRepository, p *mocks.MockProductRepository, tx *mock_transactor.MockTransactor) {
+				c.EXPECT().Create(ctx, cell).Return(entity.StorageCell{}, repository.ErrNoPointFound)
+			},
+			wantErr: service.ErrNoPointFound,
+		},
+		{
+			name: "already exists",
+			mockBehavior: func(c *mocks.MockCellRepository, p *mocks.MockProductRepository, tx *mock_transactor.MockTransactor) {
+				c.EXPECT().Create(ctx, cell).Return(entity.StorageCell{}, repository.ErrCellAlreadyExists)
+			},
+			wantErr: service.ErrCellAlreadyExists,
+		},
+		{
+			name: "arbitrary error",
+			mockBehavior: func(c *mocks.MockCellRepository, p *mocks.MockProductRepository, tx *mock_transactor.MockTransactor) {
+				c.EXPECT().Create(ctx, cell).Return(entity.StorageCell{}, arbitraryErr)
+			},
+			wantErr: arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := newService(t, tc.mockBehavior)
+			out, err := s.CreateCell(ctx, cell)
+
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.want, out)
+		})
+	}
+}
+
+func TestAssignProduct(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		pointID      = uuid.New()
+		productID    = uuid.New()
+		actorID      = uuid.New()
+		cellID       = uuid.New()
+		oldCellID    = uuid.New()
+		arbitraryErr = errors.New("arbitrary error")
+		clothes      = entity.ProductTypeClothes
+
+		product = entity.Product{ID: productID, PointID: pointID, Type: entity.ProductTypeShoes, Status: entity.ProductStatusStored, CellID: &oldCellID}
+		cell    = entity.StorageCell{ID: cellID, PointID: pointID, Rack: "A", Shelf: "1", Bin: "2", Capacity: 2, Occupied: 1}
+		placed  = entity.StorageCell{ID: cellID, PointID: pointID, Rack: "A", Shelf: "1", Bin: "2", Capacity: 2, Occupied: 2}
+	)
+
+	stored := entity.ProductStatusStored
+	history := entity.ProductHistory{
+		ProductID:  productID,
+		FromStatus: &stored,
+		ToStatus:   stored,
+		Reason:     "placed into cell A-1-2",
+		ActorID:    actorID,
+		FromCellID: &oldCellID,
+		ToCellID:   &cellID,
+	}
+
+	for _, tc := range []struct {
+		name         string
+		cellID       *uuid.UUID
+		mockBehavior MockBehavior
+		want         entity.StorageCell
+		wantErr      error
+	}{
+		{
+			name:   "success with explicit cell",
+			cellID: &cellID,
+			mockBehavior: func(c *mocks.MockCellRepository, p *mocks.MockProductRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				p.EXPECT().GetByIDForUpdate(ctx, productID).Return(product, nil)
+				c.EXPECT().GetByIDForUpdate(ctx, cellID).Return(cell, nil)
+				c.EXPECT().CountOccupied(ctx, cellID).Return(1, nil)
+				p.EXPECT().UpdateCell(ctx, productID, cellID).Return(nil)
+				p.EXPECT().CreateHistory(ctx, history).Return(history, nil)
+			},
+			want: placed,
+		},
+		{
+			name: "success with suggested cell",
+			mockBehavior: func(c *mocks.MockCellRepository, p *mocks.MockProductRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				p.EXPECT().GetByIDForUpdate(ctx, productID).Return(product, nil)
+				c.EXPECT().SuggestForUpdate(ctx, pointID, entity.ProductTypeShoes).Return(cell, nil)
+				c.EXPECT().CountOccupied(ctx, cellID).Return(1, nil)
+				p.EXPECT().UpdateCell(ctx, productID, cellID).Return(nil)
+				p.EXPECT().CreateHistory(ctx, history).Return(history, nil)
+			},
+			want: placed,
+		},
+		{
+			name:   "already in cell",
+			cellID: &oldCellID,
+			mockBehavior: func(c *mocks.MockCellRepository, p *mocks.MockProductRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				p.EXPECT().GetByIDForUpdate(ctx, productID).Return(product, nil)
+				c.EXPECT().GetByIDForUpdate(ctx, oldCellID).Return(entity.StorageCell{ID: oldCellID, PointID: pointID}, nil)
+				c.EXPECT().CountOccupied(ctx, oldCellID).Return(0, nil)
+			},
+			want: entity.StorageCell{ID: oldCellID, PointID: pointID},
+		},
+		{
+			name: "no free cell",
+			mockBehavior: func(c *mocks.MockCellRepository, p *mocks.MockProductRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				p.EXPECT().GetByIDForUpdate(ctx, productID).Return(product, nil)
+				c.EXPECT().SuggestForUpdate(ctx, pointID, entity.ProductTypeShoes).Return(entity.StorageCell{}, repository.ErrNoCellFound)
+			},
+			wantErr: service.ErrNoFreeCell,
+		},
+		{
+			name:   "no cell",
+			cellID: &cellID,
+			mockBehavior: func(c *mocks.MockCellRepository, p *mocks.MockProductRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				p.EXPECT().GetByIDForUpdate(ctx, productID).Return(product, nil)
+				c.EXPECT().GetByIDForUpdate(ctx, cellID).Return(entity.StorageCell{}, repository.ErrNoCellFound)
+			},
+			wantErr: service.ErrNoCellFound,
+		},
+		{
+			name:   "no product",
+			cellID: &cellID,
+			mockBehavior: func(c *mocks.MockCellRepository, p *mocks.MockProductRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				p.EXPECT().GetByIDForUpdate(ctx, productID).Return(entity.Product{}, repository.ErrNoProductFound)
+			},
+			wantErr: service.ErrNoProductFound,
+		},
+		{
+			name:   "product issued",
+			cellID: &cellID,
+			mockBehavior: func(c *mocks.MockCellRepository, p *mocks.MockProductRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				issued := product
+				issued.Status = entity.ProductStatusIssued
+				p.EXPECT().GetByIDForUpdate(ctx, productID).Return(issued, nil)
+			},
+			wantErr: service.ErrProductNotPresent,
+		},
+		{
+			name:   "cell at another point",
+			cellID: &cellID,
+			mockBehavior: func(c *mocks.MockCellRepository, p *mocks.MockProductRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				p.EXPECT().GetByIDForUpdate(ctx, productID).Return(product, nil)
+				other := cell
+				other.PointID = uuid.New()
+				c.EXPECT().GetByIDForUpdate(ctx, cellID).Return(other, nil)
+				c.EXPECT().CountOccupied(ctx, cellID).Return(1, nil)
+			},
+			wantErr: service.ErrCellWrongPoint,
+		},
+		{
+			name:   "incompatible cell",
+			cellID: &cellID,
+			mockBehavior: func(c *mocks.MockCellRepository, p *mocks.MockProductRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				p.EXPECT().GetByIDForUpdate(ctx, productID).Return(product, nil)
+				typed := cell
+				typed.ProductType = &clothes
+				c.EXPECT().GetByIDForUpdate(ctx, cellID).Return(typed, nil)
+				c.EXPECT().CountOccupied(ctx, cellID).Return(1, nil)
+			},
+			wantErr: service.ErrCellIncompatible,
+		},
+		{
+			name:   "cell full",
+			cellID: &cellID,
+			mockBehavior: func(c *mocks.MockCellRepository, p *mocks.MockProductRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				p.EXPECT().GetByIDForUpdate(ctx, productID).Return(product, nil)
+				c.EXPECT().GetByIDForUpdate(ctx, cellID).Return(placed, nil)
+				c.EXPECT().CountOccupied(ctx, cellID).Return(2, nil)
+			},
+			wantErr: service.ErrCellFull,
+		},
+		{
+			name:   "cell filled while waiting for lock",
+			cellID: &cellID,
+			mockBehavior: func(c *mocks.MockCellRepository, p *mocks.MockProductRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				p.EXPECT().GetByIDForUpdate(ctx, productID).Return(product, nil)
+				c.EXPECT().GetByIDForUpdate(ctx, cellID).Return(cell, nil)
+				c.EXPECT().CountOccupied(ctx, cellID).Return(2, nil)
+			},
+			wantErr: service.ErrCellFull,
+		},
+		{
+			name: "suggested cell filled while waiting for lock",
+			mockBehavior: func(c *mocks.MockCellRepository, p *mocks.MockProductRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				p.EXPECT().GetByIDForUpdate(ctx, productID).Return(product, nil)
+				filled := cell
+				filled.ID = uuid.New()
+				gomock.InOrder(
+					c.EXPECT().SuggestForUpdate(ctx, pointID, entity.ProductTypeShoes).Return(filled, nil),
+					c.EXPECT().CountOccupied(ctx, filled.ID).Return(2, nil),
+					c.EXPECT().SuggestForUpdate(ctx, pointID, entity.ProductTypeShoes).Return(cell, nil),
+					c.EXPECT().CountOccupied(ctx, cellID).Return(1, nil),
+				)
+				p.EXPECT().UpdateCell(ctx, productID, cellID).Return(nil)
+				p.EXPECT().CreateHistory(ctx, history).Return(history, nil)
+			},
+			want: placed,
+		},
+		{
+			name: "suggested cells keep filling",
+			mockBehavior: func(c *mocks.MockCellRepository, p *mocks.MockProductRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				p.EXPECT().GetByIDForUpdate(ctx, productID).Return(product, nil)
+				c.EXPECT().SuggestForUpdate(ctx, pointID, entity.ProductTypeShoes).Return(cell, nil).Times(3)
+				c.EXPECT().CountOccupied(ctx, cellID).Return(2, nil).Times(3)
+			},
+			wantErr: service.ErrNoFreeCell,
+		},
+		{
+			name:   "count error",
+			cellID: &cellID,
+			mockBehavior: func(c *mocks.MockCellRepository, p *mocks.MockProductRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				p.EXPECT().GetByIDForUpdate(ctx, productID).Return(product, nil)
+				c.EXPECT().GetByIDForUpdate(ctx, cellID).Return(cell, nil)
+				c.EXPECT().CountOccupied(ctx, cellID).Return(0, arbitraryErr)
+			},
+			wantErr: arbitraryErr,
+		},
+		{
+			name:   "arbitrary error",
+			cellID: &cellID,
+			mockBehavior: func(c *mocks.MockCellRepository, p *mocks.MockProductRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				p.EXPECT().GetByIDForUpdate(ctx, productID).Return(product, nil)
+				c.EXPECT().GetByIDForUpdate(ctx, cellID).Return(cell, nil)
+				c.EXPECT().CountOccupied(ctx, cellID).Return(1, nil)
+				p.EXPECT().UpdateCell(ctx, productID, cellID).Return(arbitraryErr)
+			},
+			wantErr: arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := newService(t, tc.mockBehavior)
+			out, err := s.AssignProduct(ctx, productID, tc.cellID, actorID)
+
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.want, out)
+		})
+	}
+}
+
+func TestLocateProduct(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		productID    = uuid.New()
+		cellID       = uuid.New()
+		barcode      = "4600000000017"
+		arbitraryErr = errors.New("arbitrary error")
+
+		product = entity.Product{ID: productID, Barcode: &barcode, CellID: &cellID}
+		cell    = entity.StorageCell{ID: cellID, Rack: "B", Shelf: "2", Bin: "1"}
+	)
+
+	for _, tc := range []struct {
+		name         string
+		productID    *uuid.UUID
+		barcode      string
+		mockBehavior MockBehavior
+		wantProduct  entity.Product
+		wantCell     entity.StorageCell
+		wantErr      error
+	}{
+		{
+			name:      "by id",
+			productID: &productID,
+			mockBehavior: func(c *mocks.MockCellRepository, p *mocks.MockProductRepository, tx *mock_transactor.MockTransactor) {
+				p.EXPECT().GetByID(ctx, productID).Return(product, nil)
+				c.EXPECT().GetByID(ctx, cellID).Return(cell, nil)
+			},
+			wantProduct: product,
+			wantCell:    cell,
+		},
+		{
+			name:    "by barcode",
+			barcode: barcode,
+			mockBehavior: func(c *mocks.MockCellRepository, p *mocks.MockProductRepository, tx *mock_transactor.MockTransactor) {
+				p.EXPECT().GetByBarcode(ctx, barcode).Return(product, nil)
+				c.EXPECT().GetByID(ctx, cellID).Return(cell, nil)
+			},
+			wantProduct: product,
+			wantCell:    cell,
+		},
+		{
+			name:    "no product",
+			barcode: barcode,
+			mockBehavior: func(c *mocks.MockCellRepository, p *mocks.MockProductRepository, tx *mock_transactor.MockTransactor) {
+				p.EXPECT().GetByBarcode(ctx, barcode).Return(entity.Product{}, repository.ErrNoProductFound)
+			},
+			wantErr: service.ErrNoProductFound,
+		},
+		{
+			name:      "not placed",
+			productID: &productID,
+			mockBehavior: func(c *mocks.MockCellRepository, p *mocks.MockProductRepository, tx *mock_transactor.MockTransactor) {
+				p.EXPECT().GetByID(ctx, productID).Return(entity.Product{ID: productID}, nil)
+			},
+			wantErr: service.ErrProductNotPlaced,
+		},
+		{
+			name:      "arbitrary error",
+			productID: &productID,
+			mockBehavior: func(c *mocks.MockCellRepository, p *mocks.MockProductRepository, tx *mock_transactor.MockTransactor) {
+				p.EXPECT().GetByID(ctx, productID).Return(product, nil)
+				c.EXPECT().GetByID(ctx, cellID).Return(entity.StorageCell{}, arbitraryErr)
+			},
+			wantErr: arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := newService(t, tc.mockBehavior)
+			outProduct, outCell, err := s.LocateProduct(ctx, tc.productID, tc.barcode)
+
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.wantProduct, outProduct)
+			assert.Equal(t, tc.wantCell, outCell)
+		})
+	}
+}
+
+func TestSuggestCell(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		pointID   = uuid.New()
+		productID = uuid.New()
+
+		product = entity.Product{ID: productID, PointID: pointID, Type: entity.ProductTypeElectronics}
+		cell    = entity.StorageCell{ID: uuid.New(), PointID: pointID, Capacity: 5, Occupied: 1}
+	)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		want         entity.StorageCell
+		wantErr      error
+	}{
+		{
+			name: "success",
+			mockBehavior: func(c *mocks.MockCellRepository, p *mocks.MockProductRepository, tx *mock_transactor.MockTransactor) {
+				p.EXPECT().GetByID(ctx, productID).Return(product, nil)
+				c.EXPECT().Suggest(ctx, pointID, entity.ProductTypeElectronics).Return(cell, nil)
+			},
+			want: cell,
+		},
+		{
+			name: "no free cell",
+			mockBehavior: func(c *mocks.MockCellRepository, p *mocks.MockProductRepository, tx *mock_transactor.MockTransactor) {
+				p.EXPECT().GetByID(ctx, productID).Return(product, nil)
+				c.EXPECT().Suggest(ctx, pointID, entity.ProductTypeElectronics).Return(entity.StorageCell{}, repository.ErrNoCellFound)
+			},
+			wantErr: service.ErrNoFreeCell,
+		},
+		{
+			name: "no product",
+			mockBehavior: func(c *mocks.MockCellRepository, p *mocks.MockProductRepository, tx *mock_transactor.MockTransactor) {
+				p.EXPECT().GetByID(ctx, productID).Return(entity.Product{}, repository.ErrNoProductFound)
+			},
+			wantErr: service.ErrNoProductFound,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := newService(t, tc.mockBehavior)
+			out, err := s.SuggestCell(ctx, productID)
+
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.want, out)
+		})
+	}
+}
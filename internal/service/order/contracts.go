@@ -0,0 +1,41 @@
+package order
+
+import (
+	"context"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/pkg/notifier"
+	"github.com/google/uuid"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mocks.go -package=mocks
+
+type OrderRepository interface {
+	Create(ctx context.Context, order entity.Order) (entity.Order, error)
+	AttachProducts(ctx context.Context, orderID uuid.UUID, pointID uuid.UUID, productIDs []uuid.UUID) error
+	GetByIDForUpdate(ctx context.Context, orderID uuid.UUID) (entity.Order, error)
+	GetByNumber(ctx context.Context, number string) (entity.Order, error)
+	SetPickupCode(ctx context.Context, orderID uuid.UUID, codeHash string, expiresAt time.Time) error
+	UpdateAttempts(ctx context.Context, orderID uuid.UUID, failedAttempts int, lockedUntil *time.Time) error
+	MarkIssued(ctx context.Context, orderID uuid.UUID) error
+}
+
+type ProductRepository interface {
+	GetByIDForUpdate(ctx context.Context, productID uuid.UUID) (entity.Product, error)
+	UpdateStatus(ctx context.Context, productID uuid.UUID, status entity.ProductStatus) error
+	CreateHistory(ctx context.Context, history entity.ProductHistory) (entity.ProductHistory, error)
+}
+
+type AuditRepository interface {
+	Create(ctx context.Context, record entity.AuditRecord) error
+}
+
+type Hasher interface {
+	HashPassword(password string) (string, error)
+	CheckPasswordHash(password, hash string) bool
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, msg notifier.Message) error
+}
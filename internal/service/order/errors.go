@@ -0,0 +1,18 @@
+package order
+
+import "errors"
+
+var (
+	ErrNoOrderFound          = errors.New("no order found")
+	ErrNoPointFound          = errors.New("no point found")
+	ErrOrderAlreadyExists    = errors.New("order already exists")
+	ErrProductsUnavailable   = errors.New("some products are not available at this point")
+	ErrProductAlreadyInOrder = errors.New("product already belongs to another order")
+	ErrProductsNotStored     = errors.New("all order products must be stored")
+	ErrOrderNotAssembling    = errors.New("order is not being assembled")
+	ErrOrderNotReady         = errors.New("order is not ready for pickup")
+	ErrInvalidPickupCode     = errors.New("invalid pickup code")
+	ErrPickupCodeExpired     = errors.New("pickup code expired")
+	ErrTooManyAttempts       = errors.New("too many wrong pickup code attempts")
+	ErrPickupCodeNotSent     = errors.New("order is ready, but the pickup code could not be sent; resend it")
+)
@@ -0,0 +1,422 @@
+package order
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/repository"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/4udiwe/avito-pvz/pkg/notifier"
+	"github.com/4udiwe/avito-pvz/pkg/transactor"
+	"github.com/google/uuid"
+)
+
+// pickupCodeSpace gives six-digit pickup codes.
+const pickupCodeSpace = 1_000_000
+
+// PickupPolicy controls pickup code lifetime and wrong-code rate limiting.
+type PickupPolicy struct {
+	CodeTTL      time.Duration
+	MaxAttempts  int
+	LockDuration time.Duration
+}
+
+// productSnapshot matches the product service records, so status changes
+// look the same in the audit log whichever way they were made.
+type productSnapshot struct {
+	ReceptionID uuid.UUID            `json:"reception_id"`
+	Type        entity.ProductType   `json:"type"`
+	Status      entity.ProductStatus `json:"status"`
+	Barcode     *string              `json:"barcode,omitempty"`
+	Reason      string               `json:"reason,omitempty"`
+}
+
+type Service struct {
+	orderRepository   OrderRepository
+	productRepository ProductRepository
+	auditRepository   AuditRepository
+	txManager         transactor.Transactor
+	hasher            Hasher
+	notifier          Notifier
+	policy            PickupPolicy
+}
+
+func New(
+	o OrderRepository,
+	p ProductRepository,
+	a AuditRepository,
+	tx transactor.Transactor,
+	h Hasher,
+	n Notifier,
+	policy PickupPolicy,
+) *Service {
+	return &Service{
+		orderRepository:   o,
+		productRepository: p,
+		auditRepository:   a,
+		txManager:         tx,
+		hasher:            h,
+		notifier:          n,
+		policy:            policy,
+	}
+}
+
+func (s *Service) CreateOrder(
+	ctx context.Context,
+	pointID uuid.UUID,
+	number string,
+	customerContact string,
+	productIDs []uuid.UUID,
+) (entity.Order, error) {
+	logger.FromContext(ctx).Infof("Service: Creating order %s with %d products for point: %s", number, len(productIDs), pointID)
+
+	var out entity.Order
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		order, err := s.orderRepository.Create(ctx, entity.Order{
+			PointID:         pointID,
+			Number:          number,
+			CustomerContact: customerContact,
+		})
+		if err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to create order %s: %v", number, err)
+			return err
+		}
+
+		if err = s.orderRepository.AttachProducts(ctx, order.ID, pointID, productIDs); err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to attach products to order %s: %v", number, err)
+			return err
+		}
+
+		order.ProductIDs = productIDs
+		out = order
+		return nil
+	})
+
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrOrderAlreadyExists):
+			return entity.Order{}, ErrOrderAlreadyExists
+		case errors.Is(err, repository.ErrNoPointFound):
+			return entity.Order{}, ErrNoPointFound
+		case errors.Is(err, repository.ErrProductsUnavailable):
+			return entity.Order{}, ErrProductsUnavailable
+		case errors.Is(err, repository.ErrProductAlreadyInOrder):
+			return entity.Order{}, ErrProductAlreadyInOrder
+		}
+		return entity.Order{}, err
+	}
+
+	logger.FromContext(ctx).Infof("Service: Order created: %s", out.ID)
+	return out, nil
+}
+
+func (s *Service) GetOrderByNumber(ctx context.Context, number string) (entity.Order, error) {
+	logger.FromContext(ctx).Infof("Service: Fetching order by number: %s", number)
+
+	order, err := s.orderRepository.GetByNumber(ctx, number)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to fetch order %s: %v", number, err)
+		if errors.Is(err, repository.ErrNoOrderFound) {
+			return entity.Order{}, ErrNoOrderFound
+		}
+		return entity.Order{}, err
+	}
+
+	logger.FromContext(ctx).Infof("Service: Fetched order: %s", order.ID)
+	return order, nil
+}
+
+// MarkReady generates a pickup code for an assembled order and sends it to
+// the customer after the code is committed. If sending fails the order stays
+// ready and ErrPickupCodeNotSent is returned; ResendPickupCode delivers a new
+// code.
+func (s *Service) MarkReady(ctx context.Context, orderID uuid.UUID) error {
+	logger.FromContext(ctx).Infof("Service: Marking order ready for pickup: %s", orderID)
+
+	var (
+		order entity.Order
+		code  string
+	)
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.orderRepository.GetByIDForUpdate(ctx, orderID)
+		if err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to get order %s: %v", orderID, err)
+			return err
+		}
+
+		if order.Status != entity.OrderStatusAssembling {
+			logger.FromContext(ctx).Warnf("Service: Order %s is %s, not assembling", orderID, order.Status)
+			return ErrOrderNotAssembling
+		}
+
+		for _, productID := range order.ProductIDs {
+			product, err := s.productRepository.GetByIDForUpdate(ctx, productID)
+			if err != nil {
+				logger.FromContext(ctx).Errorf("Service: Failed to get product %s: %v", productID, err)
+				return err
+			}
+			if product.Status != entity.ProductStatusStored {
+				logger.FromContext(ctx).Warnf("Service: Product %s of order %s is %s", productID, orderID, product.Status)
+				return ErrProductsNotStored
+			}
+		}
+
+		code, err = s.setPickupCode(ctx, orderID)
+		return err
+	})
+
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to mark order %s ready: %v", orderID, err)
+		if errors.Is(err, repository.ErrNoOrderFound) {
+			return ErrNoOrderFound
+		}
+		return err
+	}
+
+	if err = s.sendPickupCode(ctx, order, code); err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx).Infof("Service: Order %s is ready for pickup", orderID)
+	return nil
+}
+
+// ResendPickupCode replaces the pickup code of a ready order and sends the
+// new one, e.g. when the first one was not delivered. The old code stops
+// working. A locked order keeps its lock.
+func (s *Service) ResendPickupCode(ctx context.Context, orderID uuid.UUID) error {
+	logger.FromContext(ctx).Infof("Service: Resending pickup code of order: %s", orderID)
+
+	var (
+		order entity.Order
+		code  string
+	)
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.orderRepository.GetByIDForUpdate(ctx, orderID)
+		if err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to get order %s: %v", orderID, err)
+			return err
+		}
+
+		if order.Status != entity.OrderStatusReady {
+			logger.FromContext(ctx).Warnf("Service: Order %s is %s, not ready", orderID, order.Status)
+			return ErrOrderNotReady
+		}
+
+		if order.LockedUntil != nil && time.Now().Before(*order.LockedUntil) {
+			logger.FromContext(ctx).Warnf("Service: Order %s is locked until %s", orderID, order.LockedUntil)
+			return ErrTooManyAttempts
+		}
+
+		code, err = s.setPickupCode(ctx, orderID)
+		return err
+	})
+
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to resend pickup code of order %s: %v", orderID, err)
+		if errors.Is(err, repository.ErrNoOrderFound) {
+			return ErrNoOrderFound
+		}
+		return err
+	}
+
+	if err = s.sendPickupCode(ctx, order, code); err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx).Infof("Service: Pickup code of order %s resent", orderID)
+	return nil
+}
+
+// setPickupCode stores the hash of a new pickup code and returns the code.
+func (s *Service) setPickupCode(ctx context.Context, orderID uuid.UUID) (string, error) {
+	code, err := generatePickupCode()
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to generate pickup code: %v", err)
+		return "", err
+	}
+
+	hash, err := s.hasher.HashPassword(code)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to hash pickup code: %v", err)
+		return "", err
+	}
+
+	expiresAt := time.Now().Add(s.policy.CodeTTL)
+	if err = s.orderRepository.SetPickupCode(ctx, orderID, hash, expiresAt); err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to set pickup code for order %s: %v", orderID, err)
+		return "", err
+	}
+	return code, nil
+}
+
+// sendPickupCode is called only once the code is committed, and the order
+// row is not locked while the notifier works.
+func (s *Service) sendPickupCode(ctx context.Context, order entity.Order, code string) error {
+	err := s.notifier.Notify(ctx, notifier.Message{
+		Recipient: order.CustomerContact,
+		Subject:   fmt.Sprintf("Order %s is ready for pickup", order.Number),
+		Body:      fmt.Sprintf("Your pickup code for order %s: %s", order.Number, code),
+	})
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to send pickup code of order %s: %v", order.ID, err)
+		return fmt.Errorf("%w: %w", ErrPickupCodeNotSent, err)
+	}
+	return nil
+}
+
+// IssueOrder checks the pickup code and hands all order products to the customer.
+// Wrong codes are counted per order; after MaxAttempts the order is locked for LockDuration.
+func (s *Service) IssueOrder(ctx context.Context, orderID uuid.UUID, code string, actorID uuid.UUID) error {
+	logger.FromContext(ctx).Infof("Service: Issuing order %s by %s", orderID, actorID)
+
+	var codeErr error
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		order, err := s.orderRepository.GetByIDForUpdate(ctx, orderID)
+		if err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to get order %s: %v", orderID, err)
+			return err
+		}
+
+		if order.Status != entity.OrderStatusReady {
+			logger.FromContext(ctx).Warnf("Service: Order %s is %s, not ready", orderID, order.Status)
+			return ErrOrderNotReady
+		}
+
+		now := time.Now()
+		if order.LockedUntil != nil && now.Before(*order.LockedUntil) {
+			logger.FromContext(ctx).Warnf("Service: Order %s is locked until %s", orderID, order.LockedUntil)
+			return ErrTooManyAttempts
+		}
+
+		if order.CodeExpiresAt != nil && now.After(*order.CodeExpiresAt) {
+			logger.FromContext(ctx).Warnf("Service: Pickup code for order %s expired", orderID)
+			return ErrPickupCodeExpired
+		}
+
+		if !s.hasher.CheckPasswordHash(code, order.PickupCodeHash) {
+			// The attempt counter must survive, so the transaction is committed and the error is returned afterwards
+			codeErr = ErrInvalidPickupCode
+			attempts := order.FailedAttempts + 1
+			var lockedUntil *time.Time
+			if attempts >= s.policy.MaxAttempts {
+				logger.FromContext(ctx).Warnf("Service: Too many wrong pickup codes for order %s, locking", orderID)
+				until := now.Add(s.policy.LockDuration)
+				lockedUntil = &until
+				attempts = 0
+				codeErr = ErrTooManyAttempts
+			}
+			return s.orderRepository.UpdateAttempts(ctx, orderID, attempts, lockedUntil)
+		}
+
+		for _, productID := range order.ProductIDs {
+			if err := s.issueProduct(ctx, productID, order.Number, actorID); err != nil {
+				return err
+			}
+		}
+
+		return s.orderRepository.MarkIssued(ctx, orderID)
+	})
+
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to issue order %s: %v", orderID, err)
+		if errors.Is(err, repository.ErrNoOrderFound) {
+			return ErrNoOrderFound
+		}
+		return err
+	}
+
+	if codeErr != nil {
+		logger.FromContext(ctx).Warnf("Service: Wrong pickup code for order %s", orderID)
+		return codeErr
+	}
+
+	logger.FromContext(ctx).Infof("Service: Order %s issued", orderID)
+	return nil
+}
+
+func (s *Service) issueProduct(ctx context.Context, productID uuid.UUID, orderNumber string, actorID uuid.UUID) error {
+	product, err := s.productRepository.GetByIDForUpdate(ctx, productID)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to get product %s: %v", productID, err)
+		return err
+	}
+
+	if !product.Status.CanTransitionTo(entity.ProductStatusIssued) {
+		logger.FromContext(ctx).Warnf("Service: Product %s cannot be issued from %s", productID, product.Status)
+		return ErrProductsNotStored
+	}
+
+	if err = s.productRepository.UpdateStatus(ctx, productID, entity.ProductStatusIssued); err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to update status of product %s: %v", productID, err)
+		return err
+	}
+
+	reason := "order " + orderNumber
+	from := product.Status
+	_, err = s.productRepository.CreateHistory(ctx, entity.ProductHistory{
+		ProductID:  productID,
+		FromStatus: &from,
+		ToStatus:   entity.ProductStatusIssued,
+		Reason:     reason,
+		ActorID:    actorID,
+	})
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to record history for product %s: %v", productID, err)
+		return err
+	}
+
+	before := productSnapshotOf(product)
+	product.Status = entity.ProductStatusIssued
+	after := productSnapshotOf(product)
+	after.Reason = reason
+	return s.audit(ctx, actorID, productID, before, after)
+}
+
+// audit writes a product status change within the caller's transaction.
+func (s *Service) audit(ctx context.Context, actorID uuid.UUID, productID uuid.UUID, before productSnapshot, after productSnapshot) error {
+	record := entity.AuditRecord{
+		ActorID:    actorID,
+		Action:     entity.AuditActionProductStatusChanged,
+		TargetType: entity.AuditTargetProduct,
+		TargetID:   productID,
+	}
+
+	var err error
+	if record.Before, err = json.Marshal(before); err != nil {
+		return err
+	}
+	if record.After, err = json.Marshal(after); err != nil {
+		return err
+	}
+
+	if err = s.auditRepository.Create(ctx, record); err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to write audit record: %v", err)
+	}
+	return err
+}
+
+func productSnapshotOf(p entity.Product) productSnapshot {
+	return productSnapshot{
+		ReceptionID: p.ReceptionID,
+		Type:        p.Type,
+		Status:      p.Status,
+		Barcode:     p.Barcode,
+	}
+}
+
+func generatePickupCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(pickupCodeSpace))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
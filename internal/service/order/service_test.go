@@ -0,0 +1,547 @@
+package order_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	mock_transactor "github.com/4udiwe/avito-pvz/internal/mocks"
+	"github.com/4udiwe/avito-pvz/internal/repository"
+	service "github.com/4udiwe/avito-pvz/internal/service/order"
+	"github.com/4udiwe/avito-pvz/internal/service/order/mocks"
+	"github.com/4udiwe/avito-pvz/pkg/notifier"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var policy = service.PickupPolicy{
+	CodeTTL:      time.Hour,
+	MaxAttempts:  3,
+	LockDuration: 15 * time.Minute,
+}
+
+type MockBehavior func(
+	o *mocks.MockOrderRepository,
+	p *mocks.MockProductRepository,
+	a *mocks.MockAuditRepository,
+	tx *mock_transactor.MockTransactor,
+	h *mocks.MockHasher,
+	n *mocks.MockNotifier,
+)
+
+func withinTx(ctx context.Context, tx *mock_transactor.MockTransactor) {
+	tx.EXPECT().WithinTransaction(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+}
+
+func newService(t *testing.T, mockBehavior MockBehavior) *service.Service {
+	ctrl := gomock.NewController(t)
+
+	MockOrderRepository := mocks.NewMockOrderRepository(ctrl)
+	MockProductRepository := mocks.NewMockProductRepository(ctrl)
+	MockAuditRepository := mocks.NewMockAuditRepository(ctrl)
+	MockTransactor := mock_transactor.NewMockTransactor(ctrl)
+	MockHasher := mocks.NewMockHasher(ctrl)
+	MockNotifier := mocks.NewMockNotifier(ctrl)
+
+	mockBehavior(MockOrderRepository, MockProductRepository, MockAuditRepository, MockTransactor, MockHasher, MockNotifier)
+
+	return service.New(MockOrderRepository, MockProductRepository, MockAuditRepository, MockTransactor, MockHasher, MockNotifier, policy)
+}
+
+func TestCreateOrder(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		pointID      = uuid.New()
+		orderID      = uuid.New()
+		number       = "ORD-1"
+		contact      = "customer@example.com"
+		productIDs   = []uuid.UUID{uuid.New(), uuid.New()}
+		arbitraryErr = errors.New("arbitrary error")
+
+		toCreate = entity.Order{PointID: pointID, Number: number, CustomerContact: contact}
+		created  = entity.Order{ID: orderID, PointID: pointID, Number: number, CustomerContact: contact, Status: entity.OrderStatusAssembling}
+	)
+
+	withProducts := created
+	withProducts.ProductIDs = productIDs
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		want         entity.Order
+		wantErr      error
+	}{
+		{
+			name: "success",
+			mockBehavior: func(o *mocks.MockOrderRepository, p *mocks.MockProductRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor, h *mocks.MockHasher, n *mocks.MockNotifier) {
+				withinTx(ctx, tx)
+				o.EXPECT().Create(ctx, toCreate).Return(created, nil).Times(1)
+				o.EXPECT().AttachProducts(ctx, orderID, pointID, productIDs).Return(nil).Times(1)
+			},
+			want:    withProducts,
+			wantErr: nil,
+		},
+		{
+			name: "order already exists",
+			mockBehavior: func(o *mocks.MockOrderRepository, p *mocks.MockProductRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor, h *mocks.MockHasher, n *mocks.MockNotifier) {
+				withinTx(ctx, tx)
+				o.EXPECT().Create(ctx, toCreate).Return(entity.Order{}, repository.ErrOrderAlreadyExists).Times(1)
+			},
+			want:    entity.Order{},
+			wantErr: service.ErrOrderAlreadyExists,
+		},
+		{
+			name: "no point found",
+			mockBehavior: func(o *mocks.MockOrderRepository, p *mocks.MockProductRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor, h *mocks.MockHasher, n *mocks.MockNotifier) {
+				withinTx(ctx, tx)
+				o.EXPECT().Create(ctx, toCreate).Return(entity.Order{}, repository.ErrNoPointFound).Times(1)
+			},
+			want:    entity.Order{},
+			wantErr: service.ErrNoPointFound,
+		},
+		{
+			name: "products unavailable",
+			mockBehavior: func(o *mocks.MockOrderRepository, p *mocks.MockProductRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor, h *mocks.MockHasher, n *mocks.MockNotifier) {
+				withinTx(ctx, tx)
+				o.EXPECT().Create(ctx, toCreate).Return(created, nil).Times(1)
+				o.EXPECT().AttachProducts(ctx, orderID, pointID, productIDs).Return(repository.ErrProductsUnavailable).Times(1)
+			},
+			want:    entity.Order{},
+			wantErr: service.ErrProductsUnavailable,
+		},
+		{
+			name: "product already in order",
+			mockBehavior: func(o *mocks.MockOrderRepository, p *mocks.MockProductRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor, h *mocks.MockHasher, n *mocks.MockNotifier) {
+				withinTx(ctx, tx)
+				o.EXPECT().Create(ctx, toCreate).Return(created, nil).Times(1)
+				o.EXPECT().AttachProducts(ctx, orderID, pointID, productIDs).Return(repository.ErrProductAlreadyInOrder).Times(1)
+			},
+			want:    entity.Order{},
+			wantErr: service.ErrProductAlreadyInOrder,
+		},
+		{
+			name: "arbitrary error",
+			mockBehavior: func(o *mocks.MockOrderRepository, p *mocks.MockProductRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor, h *mocks.MockHasher, n *mocks.MockNotifier) {
+				withinTx(ctx, tx)
+				o.EXPECT().Create(ctx, toCreate).Return(entity.Order{}, arbitraryErr).Times(1)
+			},
+			want:    entity.Order{},
+			wantErr: arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := newService(t, tc.mockBehavior)
+
+			out, err := s.CreateOrder(ctx, pointID, number, contact, productIDs)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.want, out)
+		})
+	}
+}
+
+func TestMarkReady(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		orderID      = uuid.New()
+		productID    = uuid.New()
+		arbitraryErr = errors.New("arbitrary error")
+
+		order = entity.Order{
+			ID:              orderID,
+			Number:          "ORD-1",
+			CustomerContact: "customer@example.com",
+			Status:          entity.OrderStatusAssembling,
+			ProductIDs:      []uuid.UUID{productID},
+		}
+		stored   = entity.Product{ID: productID, Status: entity.ProductStatusStored}
+		received = entity.Product{ID: productID, Status: entity.ProductStatusReceived}
+	)
+
+	ready := order
+	ready.Status = entity.OrderStatusReady
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		wantErr      error
+	}{
+		{
+			name: "success",
+			mockBehavior: func(o *mocks.MockOrderRepository, p *mocks.MockProductRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor, h *mocks.MockHasher, n *mocks.MockNotifier) {
+				withinTx(ctx, tx)
+				o.EXPECT().GetByIDForUpdate(ctx, orderID).Return(order, nil).Times(1)
+				p.EXPECT().GetByIDForUpdate(ctx, productID).Return(stored, nil).Times(1)
+				h.EXPECT().HashPassword(gomock.Any()).Return("hash", nil).Times(1)
+				o.EXPECT().SetPickupCode(ctx, orderID, "hash", gomock.Any()).Return(nil).Times(1)
+				n.EXPECT().Notify(ctx, gomock.Any()).Return(nil).Times(1)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "no order found",
+			mockBehavior: func(o *mocks.MockOrderRepository, p *mocks.MockProductRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor, h *mocks.MockHasher, n *mocks.MockNotifier) {
+				withinTx(ctx, tx)
+				o.EXPECT().GetByIDForUpdate(ctx, orderID).Return(entity.Order{}, repository.ErrNoOrderFound).Times(1)
+			},
+			wantErr: service.ErrNoOrderFound,
+		},
+		{
+			name: "order not assembling",
+			mockBehavior: func(o *mocks.MockOrderRepository, p *mocks.MockProductRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor, h *mocks.MockHasher, n *mocks.MockNotifier) {
+				withinTx(ctx, tx)
+				o.EXPECT().GetByIDForUpdate(ctx, orderID).Return(ready, nil).Times(1)
+			},
+			wantErr: service.ErrOrderNotAssembling,
+		},
+		{
+			name: "product not stored",
+			mockBehavior: func(o *mocks.MockOrderRepository, p *mocks.MockProductRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor, h *mocks.MockHasher, n *mocks.MockNotifier) {
+				withinTx(ctx, tx)
+				o.EXPECT().GetByIDForUpdate(ctx, orderID).Return(order, nil).Times(1)
+				p.EXPECT().GetByIDForUpdate(ctx, productID).Return(received, nil).Times(1)
+			},
+			wantErr: service.ErrProductsNotStored,
+		},
+		{
+			name: "notifier error",
+			mockBehavior: func(o *mocks.MockOrderRepository, p *mocks.MockProductRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor, h *mocks.MockHasher, n *mocks.MockNotifier) {
+				withinTx(ctx, tx)
+				o.EXPECT().GetByIDForUpdate(ctx, orderID).Return(order, nil).Times(1)
+				p.EXPECT().GetByIDForUpdate(ctx, productID).Return(stored, nil).Times(1)
+				h.EXPECT().HashPassword(gomock.Any()).Return("hash", nil).Times(1)
+				o.EXPECT().SetPickupCode(ctx, orderID, "hash", gomock.Any()).Return(nil).Times(1)
+				n.EXPECT().Notify(ctx, gomock.Any()).Return(arbitraryErr).Times(1)
+			},
+			// The order is ready, the code can be resent
+			wantErr: service.ErrPickupCodeNotSent,
+		},
+		{
+			name: "notified after commit",
+			mockBehavior: func(o *mocks.MockOrderRepository, p *mocks.MockProductRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor, h *mocks.MockHasher, n *mocks.MockNotifier) {
+				committed := false
+				tx.EXPECT().WithinTransaction(ctx, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						err := fn(ctx)
+						committed = err == nil
+						return err
+					})
+				o.EXPECT().GetByIDForUpdate(ctx, orderID).Return(order, nil).Times(1)
+				p.EXPECT().GetByIDForUpdate(ctx, productID).Return(stored, nil).Times(1)
+				h.EXPECT().HashPassword(gomock.Any()).Return("hash", nil).Times(1)
+				o.EXPECT().SetPickupCode(ctx, orderID, "hash", gomock.Any()).Return(nil).Times(1)
+				n.EXPECT().Notify(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, _ notifier.Message) error {
+					if !committed {
+						return errors.New("code sent before it was committed")
+					}
+					return nil
+				}).Times(1)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "commit error",
+			mockBehavior: func(o *mocks.MockOrderRepository, p *mocks.MockProductRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor, h *mocks.MockHasher, n *mocks.MockNotifier) {
+				tx.EXPECT().WithinTransaction(ctx, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						if err := fn(ctx); err != nil {
+							return err
+						}
+						return arbitraryErr
+					})
+				o.EXPECT().GetByIDForUpdate(ctx, orderID).Return(order, nil).Times(1)
+				p.EXPECT().GetByIDForUpdate(ctx, productID).Return(stored, nil).Times(1)
+				h.EXPECT().HashPassword(gomock.Any()).Return("hash", nil).Times(1)
+				o.EXPECT().SetPickupCode(ctx, orderID, "hash", gomock.Any()).Return(nil).Times(1)
+				// Notify must not be called
+			},
+			wantErr: arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := newService(t, tc.mockBehavior)
+
+			err := s.MarkReady(ctx, orderID)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestResendPickupCode(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		orderID      = uuid.New()
+		arbitraryErr = errors.New("arbitrary error")
+		future       = time.Now().Add(time.Hour)
+		past         = time.Now().Add(-time.Hour)
+
+		ready = entity.Order{
+			ID:              orderID,
+			Number:          "ORD-1",
+			CustomerContact: "customer@example.com",
+			Status:          entity.OrderStatusReady,
+			PickupCodeHash:  "old hash",
+		}
+	)
+
+	locked := ready
+	locked.LockedUntil = &future
+	unlocked := ready
+	unlocked.LockedUntil = &past
+	assembling := ready
+	assembling.Status = entity.OrderStatusAssembling
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		wantErr      error
+	}{
+		{
+			name: "success",
+			mockBehavior: func(o *mocks.MockOrderRepository, p *mocks.MockProductRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor, h *mocks.MockHasher, n *mocks.MockNotifier) {
+				withinTx(ctx, tx)
+				o.EXPECT().GetByIDForUpdate(ctx, orderID).Return(ready, nil).Times(1)
+				h.EXPECT().HashPassword(gomock.Any()).Return("new hash", nil).Times(1)
+				o.EXPECT().SetPickupCode(ctx, orderID, "new hash", gomock.Any()).Return(nil).Times(1)
+				n.EXPECT().Notify(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, msg notifier.Message) error {
+					assert.Equal(t, ready.CustomerContact, msg.Recipient)
+					return nil
+				}).Times(1)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "expired lock",
+			mockBehavior: func(o *mocks.MockOrderRepository, p *mocks.MockProductRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor, h *mocks.MockHasher, n *mocks.MockNotifier) {
+				withinTx(ctx, tx)
+				o.EXPECT().GetByIDForUpdate(ctx, orderID).Return(unlocked, nil).Times(1)
+				h.EXPECT().HashPassword(gomock.Any()).Return("new hash", nil).Times(1)
+				o.EXPECT().SetPickupCode(ctx, orderID, "new hash", gomock.Any()).Return(nil).Times(1)
+				n.EXPECT().Notify(ctx, gomock.Any()).Return(nil).Times(1)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "no order found",
+			mockBehavior: func(o *mocks.MockOrderRepository, p *mocks.MockProductRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor, h *mocks.MockHasher, n *mocks.MockNotifier) {
+				withinTx(ctx, tx)
+				o.EXPECT().GetByIDForUpdate(ctx, orderID).Return(entity.Order{}, repository.ErrNoOrderFound).Times(1)
+			},
+			wantErr: service.ErrNoOrderFound,
+		},
+		{
+			name: "order not ready",
+			mockBehavior: func(o *mocks.MockOrderRepository, p *mocks.MockProductRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor, h *mocks.MockHasher, n *mocks.MockNotifier) {
+				withinTx(ctx, tx)
+				o.EXPECT().GetByIDForUpdate(ctx, orderID).Return(assembling, nil).Times(1)
+			},
+			wantErr: service.ErrOrderNotReady,
+		},
+		{
+			name: "locked order keeps its lock",
+			mockBehavior: func(o *mocks.MockOrderRepository, p *mocks.MockProductRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor, h *mocks.MockHasher, n *mocks.MockNotifier) {
+				withinTx(ctx, tx)
+				o.EXPECT().GetByIDForUpdate(ctx, orderID).Return(locked, nil).Times(1)
+			},
+			wantErr: service.ErrTooManyAttempts,
+		},
+		{
+			name: "notifier error",
+			mockBehavior: func(o *mocks.MockOrderRepository, p *mocks.MockProductRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor, h *mocks.MockHasher, n *mocks.MockNotifier) {
+				withinTx(ctx, tx)
+				o.EXPECT().GetByIDForUpdate(ctx, orderID).Return(ready, nil).Times(1)
+				h.EXPECT().HashPassword(gomock.Any()).Return("new hash", nil).Times(1)
+				o.EXPECT().SetPickupCode(ctx, orderID, "new hash", gomock.Any()).Return(nil).Times(1)
+				n.EXPECT().Notify(ctx, gomock.Any()).Return(arbitraryErr).Times(1)
+			},
+			wantErr: service.ErrPickupCodeNotSent,
+		},
+		{
+			name: "repository error",
+			mockBehavior: func(o *mocks.MockOrderRepository, p *mocks.MockProductRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor, h *mocks.MockHasher, n *mocks.MockNotifier) {
+				withinTx(ctx, tx)
+				o.EXPECT().GetByIDForUpdate(ctx, orderID).Return(ready, nil).Times(1)
+				h.EXPECT().HashPassword(gomock.Any()).Return("new hash", nil).Times(1)
+				o.EXPECT().SetPickupCode(ctx, orderID, "new hash", gomock.Any()).Return(arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := newService(t, tc.mockBehavior)
+
+			err := s.ResendPickupCode(ctx, orderID)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestIssueOrder(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		orderID      = uuid.New()
+		productID    = uuid.New()
+		actorID      = uuid.New()
+		code         = "123456"
+		codeHash     = "hash"
+		arbitraryErr = errors.New("arbitrary error")
+		future       = time.Now().Add(time.Hour)
+		past         = time.Now().Add(-time.Hour)
+
+		order = entity.Order{
+			ID:             orderID,
+			Number:         "ORD-1",
+			Status:         entity.OrderStatusReady,
+			PickupCodeHash: codeHash,
+			CodeExpiresAt:  &future,
+			ProductIDs:     []uuid.UUID{productID},
+		}
+		receptionID = uuid.New()
+		stored      = entity.Product{ID: productID, ReceptionID: receptionID, Type: entity.ProductTypeShoes, Status: entity.ProductStatusStored}
+	)
+
+	issued := entity.AuditRecord{
+		ActorID:    actorID,
+		Action:     entity.AuditActionProductStatusChanged,
+		TargetType: entity.AuditTargetProduct,
+		TargetID:   productID,
+		Before:     []byte(`{"reception_id":"` + receptionID.String() + `","type":"обувь","status":"stored"}`),
+		After:      []byte(`{"reception_id":"` + receptionID.String() + `","type":"обувь","status":"issued","reason":"order ORD-1"}`),
+	}
+
+	assembling := order
+	assembling.Status = entity.OrderStatusAssembling
+
+	locked := order
+	locked.LockedUntil = &future
+
+	expired := order
+	expired.CodeExpiresAt = &past
+
+	lastAttempt := order
+	lastAttempt.FailedAttempts = policy.MaxAttempts - 1
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		wantErr      error
+	}{
+		{
+			name: "success",
+			mockBehavior: func(o *mocks.MockOrderRepository, p *mocks.MockProductRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor, h *mocks.MockHasher, n *mocks.MockNotifier) {
+				withinTx(ctx, tx)
+				o.EXPECT().GetByIDForUpdate(ctx, orderID).Return(order, nil).Times(1)
+				h.EXPECT().CheckPasswordHash(code, codeHash).Return(true).Times(1)
+				p.EXPECT().GetByIDForUpdate(ctx, productID).Return(stored, nil).Times(1)
+				p.EXPECT().UpdateStatus(ctx, productID, entity.ProductStatusIssued).Return(nil).Times(1)
+				from := entity.ProductStatusStored
+				p.EXPECT().CreateHistory(ctx, entity.ProductHistory{
+					ProductID:  productID,
+					FromStatus: &from,
+					ToStatus:   entity.ProductStatusIssued,
+					Reason:     "order ORD-1",
+					ActorID:    actorID,
+				}).Return(entity.ProductHistory{}, nil).Times(1)
+				a.EXPECT().Create(ctx, issued).Return(nil).Times(1)
+				o.EXPECT().MarkIssued(ctx, orderID).Return(nil).Times(1)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "audit error",
+			mockBehavior: func(o *mocks.MockOrderRepository, p *mocks.MockProductRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor, h *mocks.MockHasher, n *mocks.MockNotifier) {
+				withinTx(ctx, tx)
+				o.EXPECT().GetByIDForUpdate(ctx, orderID).Return(order, nil).Times(1)
+				h.EXPECT().CheckPasswordHash(code, codeHash).Return(true).Times(1)
+				p.EXPECT().GetByIDForUpdate(ctx, productID).Return(stored, nil).Times(1)
+				p.EXPECT().UpdateStatus(ctx, productID, entity.ProductStatusIssued).Return(nil).Times(1)
+				p.EXPECT().CreateHistory(ctx, gomock.Any()).Return(entity.ProductHistory{}, nil).Times(1)
+				a.EXPECT().Create(ctx, issued).Return(arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+		{
+			name: "no order found",
+			mockBehavior: func(o *mocks.MockOrderRepository, p *mocks.MockProductRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor, h *mocks.MockHasher, n *mocks.MockNotifier) {
+				withinTx(ctx, tx)
+				o.EXPECT().GetByIDForUpdate(ctx, orderID).Return(entity.Order{}, repository.ErrNoOrderFound).Times(1)
+			},
+			wantErr: service.ErrNoOrderFound,
+		},
+		{
+			name: "order not ready",
+			mockBehavior: func(o *mocks.MockOrderRepository, p *mocks.MockProductRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor, h *mocks.MockHasher, n *mocks.MockNotifier) {
+				withinTx(ctx, tx)
+				o.EXPECT().GetByIDForUpdate(ctx, orderID).Return(assembling, nil).Times(1)
+			},
+			wantErr: service.ErrOrderNotReady,
+		},
+		{
+			name: "order locked",
+			mockBehavior: func(o *mocks.MockOrderRepository, p *mocks.MockProductRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor, h *mocks.MockHasher, n *mocks.MockNotifier) {
+				withinTx(ctx, tx)
+				o.EXPECT().GetByIDForUpdate(ctx, orderID).Return(locked, nil).Times(1)
+			},
+			wantErr: service.ErrTooManyAttempts,
+		},
+		{
+			name: "code expired",
+			mockBehavior: func(o *mocks.MockOrderRepository, p *mocks.MockProductRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor, h *mocks.MockHasher, n *mocks.MockNotifier) {
+				withinTx(ctx, tx)
+				o.EXPECT().GetByIDForUpdate(ctx, orderID).Return(expired, nil).Times(1)
+			},
+			wantErr: service.ErrPickupCodeExpired,
+		},
+		{
+			name: "wrong code",
+			mockBehavior: func(o *mocks.MockOrderRepository, p *mocks.MockProductRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor, h *mocks.MockHasher, n *mocks.MockNotifier) {
+				withinTx(ctx, tx)
+				o.EXPECT().GetByIDForUpdate(ctx, orderID).Return(order, nil).Times(1)
+				h.EXPECT().CheckPasswordHash(code, codeHash).Return(false).Times(1)
+				o.EXPECT().UpdateAttempts(ctx, orderID, 1, nil).Return(nil).Times(1)
+			},
+			wantErr: service.ErrInvalidPickupCode,
+		},
+		{
+			name: "wrong code locks order",
+			mockBehavior: func(o *mocks.MockOrderRepository, p *mocks.MockProductRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor, h *mocks.MockHasher, n *mocks.MockNotifier) {
+				withinTx(ctx, tx)
+				o.EXPECT().GetByIDForUpdate(ctx, orderID).Return(lastAttempt, nil).Times(1)
+				h.EXPECT().CheckPasswordHash(code, codeHash).Return(false).Times(1)
+				o.EXPECT().UpdateAttempts(ctx, orderID, 0, gomock.Not(gomock.Nil())).Return(nil).Times(1)
+			},
+			wantErr: service.ErrTooManyAttempts,
+		},
+		{
+			name: "issuing product error",
+			mockBehavior: func(o *mocks.MockOrderRepository, p *mocks.MockProductRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor, h *mocks.MockHasher, n *mocks.MockNotifier) {
+				withinTx(ctx, tx)
+				o.EXPECT().GetByIDForUpdate(ctx, orderID).Return(order, nil).Times(1)
+				h.EXPECT().CheckPasswordHash(code, codeHash).Return(true).Times(1)
+				p.EXPECT().GetByIDForUpdate(ctx, productID).Return(stored, nil).Times(1)
+				p.EXPECT().UpdateStatus(ctx, productID, entity.ProductStatusIssued).Return(arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := newService(t, tc.mockBehavior)
+
+			err := s.IssueOrder(ctx, orderID, code, actorID)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
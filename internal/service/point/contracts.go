@@ -2,6 +2,7 @@ package point
 
 import (
 	"context"
+	"time"
 
 	"github.com/4udiwe/avito-pvz/internal/entity"
 	"github.com/google/uuid"
@@ -12,16 +13,23 @@ import (
 type PointRepository interface {
 	Create(ctx context.Context, city string) (entity.Point, error)
 	GetAll(ctx context.Context) ([]entity.Point, error)
+	List(ctx context.Context, filter entity.PointFilter) ([]entity.Point, error)
+	GetStock(ctx context.Context, pointID uuid.UUID) ([]entity.Stock, error)
+	GetStockByCity(ctx context.Context, city string) ([]entity.Stock, error)
 }
 
 type ReceptionRepository interface {
-	GetAllByPoint(ctx context.Context, pointID uuid.UUID) ([]entity.Reception, error)
+	GetAllByPoint(ctx context.Context, pointID uuid.UUID, start, end *time.Time) ([]entity.Reception, error)
 }
 
 type ProductRepository interface {
 	GetAllByReception(ctx context.Context, receptionID uuid.UUID) ([]entity.Product, error)
 }
 
+type AuditRepository interface {
+	Create(ctx context.Context, record entity.AuditRecord) error
+}
+
 type Metrics interface {
 	Inc()
 	ErrInc()
@@ -3,5 +3,6 @@ package point
 import "errors"
 
 var (
-	ErrNoCityFound = errors.New("no city found")
+	ErrNoCityFound  = errors.New("no city found")
+	ErrNoPointFound = errors.New("no point found")
 )
@@ -12,6 +12,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	entity "github.com/4udiwe/avito-pvz/internal/entity"
 	uuid "github.com/google/uuid"
@@ -72,6 +73,51 @@ func (mr *MockPointRepositoryMockRecorder) GetAll(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockPointRepository)(nil).GetAll), ctx)
 }
 
+// GetStock mocks base method.
+func (m *MockPointRepository) GetStock(ctx context.Context, pointID uuid.UUID) ([]entity.Stock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStock", ctx, pointID)
+	ret0, _ := ret[0].([]entity.Stock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStock indicates an expected call of GetStock.
+func (mr *MockPointRepositoryMockRecorder) GetStock(ctx, pointID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStock", reflect.TypeOf((*MockPointRepository)(nil).GetStock), ctx, pointID)
+}
+
+// GetStockByCity mocks base method.
+func (m *MockPointRepository) GetStockByCity(ctx context.Context, city string) ([]entity.Stock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStockByCity", ctx, city)
+	ret0, _ := ret[0].([]entity.Stock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStockByCity indicates an expected call of GetStockByCity.
+func (mr *MockPointRepositoryMockRecorder) GetStockByCity(ctx, city any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStockByCity", reflect.TypeOf((*MockPointRepository)(nil).GetStockByCity), ctx, city)
+}
+
+// List mocks base method.
+func (m *MockPointRepository) List(ctx context.Context, filter entity.PointFilter) ([]entity.Point, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].([]entity.Point)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockPointRepositoryMockRecorder) List(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPointRepository)(nil).List), ctx, filter)
+}
+
 // MockReceptionRepository is a mock of ReceptionRepository interface.
 type MockReceptionRepository struct {
 	ctrl     *gomock.Controller
@@ -97,18 +143,18 @@ func (m *MockReceptionRepository) EXPECT() *MockReceptionRepositoryMockRecorder
 }
 
 // GetAllByPoint mocks base method.
-func (m *MockReceptionRepository) GetAllByPoint(ctx context.Context, pointID uuid.UUID) ([]entity.Reception, error) {
+func (m *MockReceptionRepository) GetAllByPoint(ctx context.Context, pointID uuid.UUID, start, end *time.Time) ([]entity.Reception, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllByPoint", ctx, pointID)
+	ret := m.ctrl.Call(m, "GetAllByPoint", ctx, pointID, start, end)
 	ret0, _ := ret[0].([]entity.Reception)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllByPoint indicates an expected call of GetAllByPoint.
-func (mr *MockReceptionRepositoryMockRecorder) GetAllByPoint(ctx, pointID any) *gomock.Call {
+func (mr *MockReceptionRepositoryMockRecorder) GetAllByPoint(ctx, pointID, start, end any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByPoint", reflect.TypeOf((*MockReceptionRepository)(nil).GetAllByPoint), ctx, pointID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByPoint", reflect.TypeOf((*MockReceptionRepository)(nil).GetAllByPoint), ctx, pointID, start, end)
 }
 
 // MockProductRepository is a mock of ProductRepository interface.
@@ -150,6 +196,44 @@ func (mr *MockProductRepositoryMockRecorder) GetAllByReception(ctx, receptionID
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByReception", reflect.TypeOf((*MockProductRepository)(nil).GetAllByReception), ctx, receptionID)
 }
 
+// MockAuditRepository is a mock of AuditRepository interface.
+type MockAuditRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAuditRepositoryMockRecorder is the mock recorder for MockAuditRepository.
+type MockAuditRepositoryMockRecorder struct {
+	mock *MockAuditRepository
+}
+
+// NewMockAuditRepository creates a new mock instance.
+func NewMockAuditRepository(ctrl *gomock.Controller) *MockAuditRepository {
+	mock := &MockAuditRepository{ctrl: ctrl}
+	mock.recorder = &MockAuditRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditRepository) EXPECT() *MockAuditRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAuditRepository) Create(ctx context.Context, record entity.AuditRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAuditRepositoryMockRecorder) Create(ctx, record any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAuditRepository)(nil).Create), ctx, record)
+}
+
 // MockMetrics is a mock of Metrics interface.
 type MockMetrics struct {
 	ctrl     *gomock.Controller
@@ -2,18 +2,30 @@ package point
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 
 	"github.com/4udiwe/avito-pvz/internal/entity"
 	"github.com/4udiwe/avito-pvz/internal/repository"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
 	"github.com/4udiwe/avito-pvz/pkg/transactor"
-	"github.com/sirupsen/logrus"
+	"github.com/google/uuid"
 )
 
+const (
+	defaultPointsLimit = 10
+	maxPointsLimit     = 30
+)
+
+type pointSnapshot struct {
+	City string `json:"city"`
+}
+
 type Service struct {
 	pointRepository     PointRepository
 	receptionRepository ReceptionRepository
 	productRepository   ProductRepository
+	auditRepository     AuditRepository
 	txManager           transactor.Transactor
 	metrics             Metrics
 }
@@ -21,6 +33,7 @@ type Service struct {
 func New(pointRepo PointRepository,
 	receptionRepo ReceptionRepository,
 	productRepo ProductRepository,
+	auditRepo AuditRepository,
 	txManager transactor.Transactor,
 	metrics Metrics,
 ) *Service {
@@ -28,57 +41,95 @@ func New(pointRepo PointRepository,
 		pointRepository:     pointRepo,
 		receptionRepository: receptionRepo,
 		productRepository:   productRepo,
+		auditRepository:     auditRepo,
 		txManager:           txManager,
 		metrics:             metrics,
 	}
 }
 
-func (s *Service) CreatePoint(ctx context.Context, city string) (entity.Point, error) {
-	logrus.Infof("Service: Creating point for city: %s", city)
-	point, err := s.pointRepository.Create(ctx, city)
+// CreatePoint creates a point and records it in the audit log in the same
+// transaction.
+func (s *Service) CreatePoint(ctx context.Context, actorID uuid.UUID, city string) (entity.Point, error) {
+	logger.FromContext(ctx).Infof("Service: Creating point for city: %s", city)
+
+	var point entity.Point
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		point, err = s.pointRepository.Create(ctx, city)
+		if err != nil {
+			return err
+		}
+
+		after, err := json.Marshal(pointSnapshot{City: point.City})
+		if err != nil {
+			return err
+		}
+
+		err = s.auditRepository.Create(ctx, entity.AuditRecord{
+			ActorID:    actorID,
+			Action:     entity.AuditActionPointCreated,
+			TargetType: entity.AuditTargetPoint,
+			TargetID:   point.ID,
+			After:      after,
+		})
+		if err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to write audit record: %v", err)
+		}
+		return err
+	})
 
 	if err != nil {
 		if errors.Is(err, repository.ErrNoCityFound) {
-			logrus.Warnf("Service: No city found: %s", city)
+			logger.FromContext(ctx).Warnf("Service: No city found: %s", city)
 			return entity.Point{}, ErrNoCityFound
 		}
-		logrus.Errorf("Service: Failed to create point for city %s: %v", city, err)
+		logger.FromContext(ctx).Errorf("Service: Failed to create point for city %s: %v", city, err)
 		s.metrics.ErrInc()
 		return entity.Point{}, err
 	}
 
-	logrus.Infof("Service: Point created: %+v", point)
+	logger.FromContext(ctx).Infof("Service: Point created: %+v", point)
 	s.metrics.Inc()
 	return point, nil
 }
 
 func (s *Service) GetAllPoints(ctx context.Context) ([]entity.Point, error) {
-	logrus.Info("Service: Fetching all points")
+	logger.FromContext(ctx).Info("Service: Fetching all points")
 	points, err := s.pointRepository.GetAll(ctx)
 
 	if err != nil {
-		logrus.Errorf("Service: Failed to fetch all points: %v", err)
+		logger.FromContext(ctx).Errorf("Service: Failed to fetch all points: %v", err)
 		return nil, err
 	}
 
-	logrus.Infof("Service: Fetched %d points", len(points))
+	logger.FromContext(ctx).Infof("Service: Fetched %d points", len(points))
 	return points, nil
 }
 
-func (s *Service) GetAllPointsFullInfo(ctx context.Context) ([]entity.PointFullInfo, error) {
-	logrus.Info("Service: Fetching full info for all points")
+// GetPointsFullInfo returns a page of points with their receptions and
+// products; see entity.PointFilter.
+func (s *Service) GetPointsFullInfo(ctx context.Context, filter entity.PointFilter) ([]entity.PointFullInfo, error) {
+	logger.FromContext(ctx).Infof("Service: Fetching full info for points: %+v", filter)
 
-	points, err := s.pointRepository.GetAll(ctx)
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 {
+		filter.Limit = defaultPointsLimit
+	}
+	filter.Limit = min(filter.Limit, maxPointsLimit)
+
+	points, err := s.pointRepository.List(ctx, filter)
 	if err != nil {
-		logrus.Errorf("Service: Failed to fetch points: %v", err)
+		logger.FromContext(ctx).Errorf("Service: Failed to fetch points: %v", err)
 		return nil, err
 	}
 
 	var result []entity.PointFullInfo
 	for _, point := range points {
-		receptions, err := s.receptionRepository.GetAllByPoint(ctx, point.ID)
+		receptions, err := s.receptionRepository.GetAllByPoint(ctx, point.ID, filter.StartDate, filter.EndDate)
 		if err != nil {
-			logrus.Errorf("Service: Failed to fetch receptions for point %v: %v", point.ID, err)
+			logger.FromContext(ctx).Errorf("Service: Failed to fetch receptions for point %v: %v", point.ID, err)
 			return nil, err
 		}
 
@@ -86,7 +137,7 @@ func (s *Service) GetAllPointsFullInfo(ctx context.Context) ([]entity.PointFullI
 		for _, reception := range receptions {
 			products, err := s.productRepository.GetAllByReception(ctx, reception.ID)
 			if err != nil {
-				logrus.Errorf("Service: Failed to fetch products for reception %v: %v", reception.ID, err)
+				logger.FromContext(ctx).Errorf("Service: Failed to fetch products for reception %v: %v", reception.ID, err)
 				return nil, err
 			}
 			receptionsWithProducts = append(receptionsWithProducts, entity.ReceptionWithProducts{
@@ -101,6 +152,38 @@ func (s *Service) GetAllPointsFullInfo(ctx context.Context) ([]entity.PointFullI
 		})
 	}
 
-	logrus.Infof("Service: Fetched full info for %d points", len(result))
+	logger.FromContext(ctx).Infof("Service: Fetched full info for %d points", len(result))
 	return result, nil
 }
+
+func (s *Service) GetStock(ctx context.Context, pointID uuid.UUID) ([]entity.Stock, error) {
+	logger.FromContext(ctx).Infof("Service: Fetching stock for point: %s", pointID)
+	stock, err := s.pointRepository.GetStock(ctx, pointID)
+
+	if err != nil {
+		if errors.Is(err, repository.ErrNoPointFound) {
+			logger.FromContext(ctx).Warnf("Service: No point found: %s", pointID)
+			return nil, ErrNoPointFound
+		}
+		logger.FromContext(ctx).Errorf("Service: Failed to fetch stock for point %s: %v", pointID, err)
+		return nil, err
+	}
+
+	return stock, nil
+}
+
+func (s *Service) GetCityStock(ctx context.Context, city string) ([]entity.Stock, error) {
+	logger.FromContext(ctx).Infof("Service: Fetching stock for city: %s", city)
+	stock, err := s.pointRepository.GetStockByCity(ctx, city)
+
+	if err != nil {
+		if errors.Is(err, repository.ErrNoCityFound) {
+			logger.FromContext(ctx).Warnf("Service: No city found: %s", city)
+			return nil, ErrNoCityFound
+		}
+		logger.FromContext(ctx).Errorf("Service: Failed to fetch stock for city %s: %v", city, err)
+		return nil, err
+	}
+
+	return stock, nil
+}
@@ -18,18 +18,26 @@ import (
 
 func TestCreatePoint(t *testing.T) {
 	var (
-		ctx   = context.Background()
-		city  = "Москва"
-		point = entity.Point{
+		ctx     = context.Background()
+		actorID = uuid.New()
+		city    = "Москва"
+		point   = entity.Point{
 			ID:        uuid.Max,
 			City:      city,
 			CreatedAt: time.Now(),
 		}
+		record = entity.AuditRecord{
+			ActorID:    actorID,
+			Action:     entity.AuditActionPointCreated,
+			TargetType: entity.AuditTargetPoint,
+			TargetID:   uuid.Max,
+			After:      []byte(`{"city":"Москва"}`),
+		}
 		emptyPoint   = entity.Point{}
 		arbitraryErr = errors.New("arbitrary error")
 	)
 
-	type MockBehavior func(r *mocks.MockPointRepository, m *mocks.MockMetrics)
+	type MockBehavior func(r *mocks.MockPointRepository, a *mocks.MockAuditRepository, m *mocks.MockMetrics)
 
 	for _, tc := range []struct {
 		name         string
@@ -39,8 +47,9 @@ func TestCreatePoint(t *testing.T) {
 	}{
 		{
 			name: "success",
-			mockBehavior: func(r *mocks.MockPointRepository, m *mocks.MockMetrics) {
+			mockBehavior: func(r *mocks.MockPointRepository, a *mocks.MockAuditRepository, m *mocks.MockMetrics) {
 				r.EXPECT().Create(ctx, city).Return(point, nil).Times(1)
+				a.EXPECT().Create(ctx, record).Return(nil).Times(1)
 				m.EXPECT().Inc().Times(1)
 			},
 			want:    point,
@@ -48,7 +57,7 @@ func TestCreatePoint(t *testing.T) {
 		},
 		{
 			name: "no city found",
-			mockBehavior: func(r *mocks.MockPointRepository, m *mocks.MockMetrics) {
+			mockBehavior: func(r *mocks.MockPointRepository, a *mocks.MockAuditRepository, m *mocks.MockMetrics) {
 				r.EXPECT().Create(ctx, city).Return(emptyPoint, repository.ErrNoCityFound).Times(1)
 			},
 			want:    emptyPoint,
@@ -56,13 +65,23 @@ func TestCreatePoint(t *testing.T) {
 		},
 		{
 			name: "arbitrary error",
-			mockBehavior: func(r *mocks.MockPointRepository, m *mocks.MockMetrics) {
+			mockBehavior: func(r *mocks.MockPointRepository, a *mocks.MockAuditRepository, m *mocks.MockMetrics) {
 				r.EXPECT().Create(ctx, city).Return(emptyPoint, arbitraryErr).Times(1)
 				m.EXPECT().ErrInc().Times(1)
 			},
 			want:    emptyPoint,
 			wantErr: arbitraryErr,
 		},
+		{
+			name: "audit error",
+			mockBehavior: func(r *mocks.MockPointRepository, a *mocks.MockAuditRepository, m *mocks.MockMetrics) {
+				r.EXPECT().Create(ctx, city).Return(point, nil).Times(1)
+				a.EXPECT().Create(ctx, record).Return(arbitraryErr).Times(1)
+				m.EXPECT().ErrInc().Times(1)
+			},
+			want:    emptyPoint,
+			wantErr: arbitraryErr,
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
@@ -71,14 +90,20 @@ func TestCreatePoint(t *testing.T) {
 			MockPointRepository := mocks.NewMockPointRepository(ctrl)
 			MockReceptionRepository := mocks.NewMockReceptionRepository(ctrl)
 			MockProductRepository := mocks.NewMockProductRepository(ctrl)
+			MockAuditRepository := mocks.NewMockAuditRepository(ctrl)
 			MockTransactor := mock_transactor.NewMockTransactor(ctrl)
 			MockMetrics := mocks.NewMockMetrics(ctrl)
 
-			tc.mockBehavior(MockPointRepository, MockMetrics)
+			MockTransactor.EXPECT().WithinTransaction(ctx, gomock.Any()).DoAndReturn(
+				func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				},
+			).Times(1)
+			tc.mockBehavior(MockPointRepository, MockAuditRepository, MockMetrics)
 
-			s := service.New(MockPointRepository, MockReceptionRepository, MockProductRepository, MockTransactor, MockMetrics)
+			s := service.New(MockPointRepository, MockReceptionRepository, MockProductRepository, MockAuditRepository, MockTransactor, MockMetrics)
 
-			out, err := s.CreatePoint(ctx, city)
+			out, err := s.CreatePoint(ctx, actorID, city)
 			assert.ErrorIs(t, err, tc.wantErr)
 			assert.Equal(t, tc.want, out)
 		})
@@ -141,7 +166,7 @@ func TestGetAllPoints(t *testing.T) {
 
 			tc.mockBehavior(MockPointRepository)
 
-			s := service.New(MockPointRepository, MockReceptionRepository, MockProductRepository, MockTransactor, MockMetrics)
+			s := service.New(MockPointRepository, MockReceptionRepository, MockProductRepository, nil, MockTransactor, MockMetrics)
 
 			out, err := s.GetAllPoints(ctx)
 			assert.ErrorIs(t, err, tc.wantErr)
@@ -150,10 +175,13 @@ func TestGetAllPoints(t *testing.T) {
 	}
 }
 
-func TestGetAllPointsFullInfo(t *testing.T) {
+func TestGetPointsFullInfo(t *testing.T) {
 	var (
 		ctx          = context.Background()
 		arbitraryErr = errors.New("arbitrary error")
+		startDate    = time.Now().Add(-time.Hour)
+		endDate      = time.Now()
+		filter       = entity.PointFilter{StartDate: &startDate, EndDate: &endDate, Page: 2, Limit: 5}
 	)
 
 	pointID1 := uuid.New()
@@ -212,19 +240,21 @@ func TestGetAllPointsFullInfo(t *testing.T) {
 
 	for _, tc := range []struct {
 		name         string
+		filter       entity.PointFilter
 		mockBehavior MockBehavior
 		want         []entity.PointFullInfo
 		wantErr      error
 	}{
 		{
-			name: "success",
+			name:   "success",
+			filter: filter,
 			mockBehavior: MockBehavior{
 				pointMock: func(r *mocks.MockPointRepository) {
-					r.EXPECT().GetAll(ctx).Return(points, nil).Times(1)
+					r.EXPECT().List(ctx, filter).Return(points, nil).Times(1)
 				},
 				receptionMock: func(r *mocks.MockReceptionRepository) {
-					r.EXPECT().GetAllByPoint(ctx, pointID1).Return(receptionsPoint1, nil).Times(1)
-					r.EXPECT().GetAllByPoint(ctx, pointID2).Return(receptionsPoint2, nil).Times(1)
+					r.EXPECT().GetAllByPoint(ctx, pointID1, &startDate, &endDate).Return(receptionsPoint1, nil).Times(1)
+					r.EXPECT().GetAllByPoint(ctx, pointID2, &startDate, &endDate).Return(receptionsPoint2, nil).Times(1)
 				},
 				productMock: func(r *mocks.MockProductRepository) {
 					r.EXPECT().GetAllByReception(ctx, receptionID1).Return(productsReception1, nil).Times(1)
@@ -235,10 +265,11 @@ func TestGetAllPointsFullInfo(t *testing.T) {
 			wantErr: nil,
 		},
 		{
-			name: "failed to get points",
+			name:   "failed to get points",
+			filter: filter,
 			mockBehavior: MockBehavior{
 				pointMock: func(r *mocks.MockPointRepository) {
-					r.EXPECT().GetAll(ctx).Return(nil, arbitraryErr).Times(1)
+					r.EXPECT().List(ctx, filter).Return(nil, arbitraryErr).Times(1)
 				},
 				receptionMock: func(r *mocks.MockReceptionRepository) {},
 				productMock:   func(r *mocks.MockProductRepository) {},
@@ -247,13 +278,14 @@ func TestGetAllPointsFullInfo(t *testing.T) {
 			wantErr: arbitraryErr,
 		},
 		{
-			name: "failed to get receptions for point",
+			name:   "failed to get receptions for point",
+			filter: filter,
 			mockBehavior: MockBehavior{
 				pointMock: func(r *mocks.MockPointRepository) {
-					r.EXPECT().GetAll(ctx).Return(points, nil).Times(1)
+					r.EXPECT().List(ctx, filter).Return(points, nil).Times(1)
 				},
 				receptionMock: func(r *mocks.MockReceptionRepository) {
-					r.EXPECT().GetAllByPoint(ctx, pointID1).Return(nil, arbitraryErr).Times(1)
+					r.EXPECT().GetAllByPoint(ctx, pointID1, &startDate, &endDate).Return(nil, arbitraryErr).Times(1)
 				},
 				productMock: func(r *mocks.MockProductRepository) {},
 			},
@@ -261,13 +293,14 @@ func TestGetAllPointsFullInfo(t *testing.T) {
 			wantErr: arbitraryErr,
 		},
 		{
-			name: "failed to get products for reception",
+			name:   "failed to get products for reception",
+			filter: filter,
 			mockBehavior: MockBehavior{
 				pointMock: func(r *mocks.MockPointRepository) {
-					r.EXPECT().GetAll(ctx).Return(points, nil).Times(1)
+					r.EXPECT().List(ctx, filter).Return(points, nil).Times(1)
 				},
 				receptionMock: func(r *mocks.MockReceptionRepository) {
-					r.EXPECT().GetAllByPoint(ctx, pointID1).Return(receptionsPoint1, nil).Times(1)
+					r.EXPECT().GetAllByPoint(ctx, pointID1, &startDate, &endDate).Return(receptionsPoint1, nil).Times(1)
 				},
 				productMock: func(r *mocks.MockProductRepository) {
 					r.EXPECT().GetAllByReception(ctx, receptionID1).Return(nil, arbitraryErr).Times(1)
@@ -277,10 +310,11 @@ func TestGetAllPointsFullInfo(t *testing.T) {
 			wantErr: arbitraryErr,
 		},
 		{
-			name: "no points found",
+			name:   "no points found",
+			filter: filter,
 			mockBehavior: MockBehavior{
 				pointMock: func(r *mocks.MockPointRepository) {
-					r.EXPECT().GetAll(ctx).Return([]entity.Point{}, arbitraryErr).Times(1)
+					r.EXPECT().List(ctx, filter).Return([]entity.Point{}, arbitraryErr).Times(1)
 				},
 				receptionMock: func(r *mocks.MockReceptionRepository) {},
 				productMock:   func(r *mocks.MockProductRepository) {},
@@ -288,6 +322,32 @@ func TestGetAllPointsFullInfo(t *testing.T) {
 			want:    nil,
 			wantErr: arbitraryErr,
 		},
+		{
+			name:   "default page and limit",
+			filter: entity.PointFilter{},
+			mockBehavior: MockBehavior{
+				pointMock: func(r *mocks.MockPointRepository) {
+					r.EXPECT().List(ctx, entity.PointFilter{Page: 1, Limit: 10}).Return(nil, nil).Times(1)
+				},
+				receptionMock: func(r *mocks.MockReceptionRepository) {},
+				productMock:   func(r *mocks.MockProductRepository) {},
+			},
+			want:    nil,
+			wantErr: nil,
+		},
+		{
+			name:   "limit capped",
+			filter: entity.PointFilter{Page: 3, Limit: 100},
+			mockBehavior: MockBehavior{
+				pointMock: func(r *mocks.MockPointRepository) {
+					r.EXPECT().List(ctx, entity.PointFilter{Page: 3, Limit: 30}).Return(nil, nil).Times(1)
+				},
+				receptionMock: func(r *mocks.MockReceptionRepository) {},
+				productMock:   func(r *mocks.MockProductRepository) {},
+			},
+			want:    nil,
+			wantErr: nil,
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
@@ -303,11 +363,127 @@ func TestGetAllPointsFullInfo(t *testing.T) {
 			tc.mockBehavior.receptionMock(MockReceptionRepository)
 			tc.mockBehavior.productMock(MockProductRepository)
 
-			s := service.New(MockPointRepository, MockReceptionRepository, MockProductRepository, MockTransactor, MockMetrics)
+			s := service.New(MockPointRepository, MockReceptionRepository, MockProductRepository, nil, MockTransactor, MockMetrics)
 
-			result, err := s.GetAllPointsFullInfo(ctx)
+			result, err := s.GetPointsFullInfo(ctx, tc.filter)
 			assert.ErrorIs(t, err, tc.wantErr)
 			assert.Equal(t, tc.want, result)
 		})
 	}
 }
+
+func TestGetStock(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		pointID      = uuid.New()
+		arbitraryErr = errors.New("arbitrary error")
+		stock        = []entity.Stock{
+			{PointID: pointID, Type: entity.ProductTypeElectronics, Amount: 2},
+			{PointID: pointID, Type: entity.ProductTypeClothes, Amount: 0},
+			{PointID: pointID, Type: entity.ProductTypeShoes, Amount: 1},
+		}
+	)
+
+	type MockBehavior func(r *mocks.MockPointRepository)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		want         []entity.Stock
+		wantErr      error
+	}{
+		{
+			name: "success",
+			mockBehavior: func(r *mocks.MockPointRepository) {
+				r.EXPECT().GetStock(ctx, pointID).Return(stock, nil).Times(1)
+			},
+			want: stock,
+		},
+		{
+			name: "no point found",
+			mockBehavior: func(r *mocks.MockPointRepository) {
+				r.EXPECT().GetStock(ctx, pointID).Return(nil, repository.ErrNoPointFound).Times(1)
+			},
+			wantErr: service.ErrNoPointFound,
+		},
+		{
+			name: "arbitrary error",
+			mockBehavior: func(r *mocks.MockPointRepository) {
+				r.EXPECT().GetStock(ctx, pointID).Return(nil, arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			MockPointRepository := mocks.NewMockPointRepository(ctrl)
+			tc.mockBehavior(MockPointRepository)
+
+			s := service.New(MockPointRepository, nil, nil, nil, nil, nil)
+
+			out, err := s.GetStock(ctx, pointID)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.want, out)
+		})
+	}
+}
+
+func TestGetCityStock(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		city         = "Казань"
+		arbitraryErr = errors.New("arbitrary error")
+		stock        = []entity.Stock{
+			{Type: entity.ProductTypeElectronics, Amount: 5},
+			{Type: entity.ProductTypeClothes, Amount: 3},
+			{Type: entity.ProductTypeShoes, Amount: 0},
+		}
+	)
+
+	type MockBehavior func(r *mocks.MockPointRepository)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		want         []entity.Stock
+		wantErr      error
+	}{
+		{
+			name: "success",
+			mockBehavior: func(r *mocks.MockPointRepository) {
+				r.EXPECT().GetStockByCity(ctx, city).Return(stock, nil).Times(1)
+			},
+			want: stock,
+		},
+		{
+			name: "no city found",
+			mockBehavior: func(r *mocks.MockPointRepository) {
+				r.EXPECT().GetStockByCity(ctx, city).Return(nil, repository.ErrNoCityFound).Times(1)
+			},
+			wantErr: service.ErrNoCityFound,
+		},
+		{
+			name: "arbitrary error",
+			mockBehavior: func(r *mocks.MockPointRepository) {
+				r.EXPECT().GetStockByCity(ctx, city).Return(nil, arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			MockPointRepository := mocks.NewMockPointRepository(ctrl)
+			tc.mockBehavior(MockPointRepository)
+
+			s := service.New(MockPointRepository, nil, nil, nil, nil, nil)
+
+			out, err := s.GetCityStock(ctx, city)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.want, out)
+		})
+	}
+}
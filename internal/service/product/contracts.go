@@ -10,12 +10,21 @@ import (
 //go:generate go tool mockgen -source=contracts.go -destination=mocks/repo_mock.go -package=mocks
 
 type ProductsRepository interface {
-	Create(ctx context.Context, pointID uuid.UUID, productType entity.ProductType) (entity.Product, error)
-	DeleteLastFromReception(ctx context.Context, pointID uuid.UUID) error
+	Create(ctx context.Context, pointID uuid.UUID, productType entity.ProductType, barcode string) (entity.Product, error)
+	DeleteLastFromReception(ctx context.Context, pointID uuid.UUID) (entity.Product, error)
+	GetByIDForUpdate(ctx context.Context, productID uuid.UUID) (entity.Product, error)
+	UpdateStatus(ctx context.Context, productID uuid.UUID, status entity.ProductStatus) error
+	CreateHistory(ctx context.Context, history entity.ProductHistory) (entity.ProductHistory, error)
+	GetHistory(ctx context.Context, productID uuid.UUID) ([]entity.ProductHistory, error)
 }
 
 type ReceptionRepository interface {
 	GetLastReceptionStatus(ctx context.Context, pointID uuid.UUID) (entity.ReceptionStatus, error)
+	GetStatusByID(ctx context.Context, receptionID uuid.UUID) (entity.ReceptionStatus, error)
+}
+
+type AuditRepository interface {
+	Create(ctx context.Context, record entity.AuditRecord) error
 }
 
 type Metrics interface {
@@ -6,4 +6,9 @@ var (
 	ErrReceptionAlreadyClosed = errors.New("reception already closed")
 	ErrNoPointFound           = errors.New("no point found")
 	ErrNoReceptionFound       = errors.New("no reception found")
+	ErrNoProductFound         = errors.New("no product found")
+	ErrReceptionNotClosed     = errors.New("reception not closed")
+	ErrInvalidTransition      = errors.New("invalid product status transition")
+	ErrReasonRequired         = errors.New("reason is required for this status")
+	ErrBarcodeAlreadyExists   = errors.New("product with this barcode already exists")
 )
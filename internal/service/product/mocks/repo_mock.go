@@ -43,26 +43,42 @@ func (m *MockProductsRepository) EXPECT() *MockProductsRepositoryMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockProductsRepository) Create(ctx context.Context, pointID uuid.UUID, productType entity.ProductType) (entity.Product, error) {
+func (m *MockProductsRepository) Create(ctx context.Context, pointID uuid.UUID, productType entity.ProductType, barcode string) (entity.Product, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, pointID, productType)
+	ret := m.ctrl.Call(m, "Create", ctx, pointID, productType, barcode)
 	ret0, _ := ret[0].(entity.Product)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockProductsRepositoryMockRecorder) Create(ctx, pointID, productType any) *gomock.Call {
+func (mr *MockProductsRepositoryMockRecorder) Create(ctx, pointID, productType, barcode any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockProductsRepository)(nil).Create), ctx, pointID, productType)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockProductsRepository)(nil).Create), ctx, pointID, productType, barcode)
+}
+
+// CreateHistory mocks base method.
+func (m *MockProductsRepository) CreateHistory(ctx context.Context, history entity.ProductHistory) (entity.ProductHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHistory", ctx, history)
+	ret0, _ := ret[0].(entity.ProductHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHistory indicates an expected call of CreateHistory.
+func (mr *MockProductsRepositoryMockRecorder) CreateHistory(ctx, history any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHistory", reflect.TypeOf((*MockProductsRepository)(nil).CreateHistory), ctx, history)
 }
 
 // DeleteLastFromReception mocks base method.
-func (m *MockProductsRepository) DeleteLastFromReception(ctx context.Context, pointID uuid.UUID) error {
+func (m *MockProductsRepository) DeleteLastFromReception(ctx context.Context, pointID uuid.UUID) (entity.Product, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "DeleteLastFromReception", ctx, pointID)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(entity.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // DeleteLastFromReception indicates an expected call of DeleteLastFromReception.
@@ -71,6 +87,50 @@ func (mr *MockProductsRepositoryMockRecorder) DeleteLastFromReception(ctx, point
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLastFromReception", reflect.TypeOf((*MockProductsRepository)(nil).DeleteLastFromReception), ctx, pointID)
 }
 
+// GetByIDForUpdate mocks base method.
+func (m *MockProductsRepository) GetByIDForUpdate(ctx context.Context, productID uuid.UUID) (entity.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDForUpdate", ctx, productID)
+	ret0, _ := ret[0].(entity.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDForUpdate indicates an expected call of GetByIDForUpdate.
+func (mr *MockProductsRepositoryMockRecorder) GetByIDForUpdate(ctx, productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDForUpdate", reflect.TypeOf((*MockProductsRepository)(nil).GetByIDForUpdate), ctx, productID)
+}
+
+// GetHistory mocks base method.
+func (m *MockProductsRepository) GetHistory(ctx context.Context, productID uuid.UUID) ([]entity.ProductHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", ctx, productID)
+	ret0, _ := ret[0].([]entity.ProductHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockProductsRepositoryMockRecorder) GetHistory(ctx, productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockProductsRepository)(nil).GetHistory), ctx, productID)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockProductsRepository) UpdateStatus(ctx context.Context, productID uuid.UUID, status entity.ProductStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, productID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockProductsRepositoryMockRecorder) UpdateStatus(ctx, productID, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockProductsRepository)(nil).UpdateStatus), ctx, productID, status)
+}
+
 // MockReceptionRepository is a mock of ReceptionRepository interface.
 type MockReceptionRepository struct {
 	ctrl     *gomock.Controller
@@ -110,6 +170,59 @@ func (mr *MockReceptionRepositoryMockRecorder) GetLastReceptionStatus(ctx, point
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastReceptionStatus", reflect.TypeOf((*MockReceptionRepository)(nil).GetLastReceptionStatus), ctx, pointID)
 }
 
+// GetStatusByID mocks base method.
+func (m *MockReceptionRepository) GetStatusByID(ctx context.Context, receptionID uuid.UUID) (entity.ReceptionStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatusByID", ctx, receptionID)
+	ret0, _ := ret[0].(entity.ReceptionStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatusByID indicates an expected call of GetStatusByID.
+func (mr *MockReceptionRepositoryMockRecorder) GetStatusByID(ctx, receptionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatusByID", reflect.TypeOf((*MockReceptionRepository)(nil).GetStatusByID), ctx, receptionID)
+}
+
+// MockAuditRepository is a mock of AuditRepository interface.
+type MockAuditRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAuditRepositoryMockRecorder is the mock recorder for MockAuditRepository.
+type MockAuditRepositoryMockRecorder struct {
+	mock *MockAuditRepository
+}
+
+// NewMockAuditRepository creates a new mock instance.
+func NewMockAuditRepository(ctrl *gomock.Controller) *MockAuditRepository {
+	mock := &MockAuditRepository{ctrl: ctrl}
+	mock.recorder = &MockAuditRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditRepository) EXPECT() *MockAuditRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAuditRepository) Create(ctx context.Context, record entity.AuditRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAuditRepositoryMockRecorder) Create(ctx, record any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAuditRepository)(nil).Create), ctx, record)
+}
+
 // MockMetrics is a mock of Metrics interface.
 type MockMetrics struct {
 	ctrl     *gomock.Controller
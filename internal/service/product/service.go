@@ -2,26 +2,37 @@ package product
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 
 	"github.com/4udiwe/avito-pvz/internal/entity"
 	"github.com/4udiwe/avito-pvz/internal/repository"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
 	"github.com/4udiwe/avito-pvz/pkg/transactor"
 	"github.com/google/uuid"
-	"github.com/sirupsen/logrus"
 )
 
+type productSnapshot struct {
+	ReceptionID uuid.UUID            `json:"reception_id"`
+	Type        entity.ProductType   `json:"type"`
+	Status      entity.ProductStatus `json:"status"`
+	Barcode     *string              `json:"barcode,omitempty"`
+	Reason      string               `json:"reason,omitempty"`
+}
+
 type Service struct {
 	productRepository   ProductsRepository
 	receptionRepository ReceptionRepository
+	auditRepository     AuditRepository
 	txManager           transactor.Transactor
 	metrics             Metrics
 }
 
-func New(p ProductsRepository, r ReceptionRepository, tx transactor.Transactor, m Metrics) *Service {
+func New(p ProductsRepository, r ReceptionRepository, a AuditRepository, tx transactor.Transactor, m Metrics) *Service {
 	return &Service{
 		productRepository:   p,
 		receptionRepository: r,
+		auditRepository:     a,
 		txManager:           tx,
 		metrics:             m,
 	}
@@ -29,71 +40,96 @@ func New(p ProductsRepository, r ReceptionRepository, tx transactor.Transactor,
 
 func (s *Service) AddProduct(
 	ctx context.Context,
+	actorID uuid.UUID,
 	pointID uuid.UUID,
 	productType entity.ProductType,
+	barcode string,
 ) (entity.Product, error) {
-	logrus.Infof("Service: Adding product of type %s to point: %s", productType, pointID)
+	logger.FromContext(ctx).Infof("Service: Adding product of type %s to point: %s", productType, pointID)
 	var out entity.Product
 	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
 		// Status check
 		status, err := s.receptionRepository.GetLastReceptionStatus(ctx, pointID)
 
 		if err != nil {
-			logrus.Errorf("Service: Failed to get last reception status for point %s: %v", pointID, err)
+			logger.FromContext(ctx).Errorf("Service: Failed to get last reception status for point %s: %v", pointID, err)
 			return err
 		}
 
 		if status != entity.ReceptionStatusInProgress {
-			logrus.Warnf("Service: Reception already closed for point: %s", pointID)
+			logger.FromContext(ctx).Warnf("Service: Reception already closed for point: %s", pointID)
 			return ErrReceptionAlreadyClosed
 		}
 
 		// Create
-		out, err = s.productRepository.Create(ctx, pointID, productType)
-		return err
+		out, err = s.productRepository.Create(ctx, pointID, productType, barcode)
+		if err != nil {
+			return err
+		}
+
+		// History starts at the status the product is received in
+		_, err = s.productRepository.CreateHistory(ctx, entity.ProductHistory{
+			ProductID: out.ID,
+			ToStatus:  out.Status,
+			ActorID:   actorID,
+		})
+		if err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to record history for product %s: %v", out.ID, err)
+			return err
+		}
+
+		return s.audit(ctx, actorID, entity.AuditActionProductAdded, out.ID, nil, productSnapshotOf(out))
 	})
 
 	if err != nil {
-		logrus.Errorf("Service: Failed to add product to point %s: %v", pointID, err)
+		logger.FromContext(ctx).Errorf("Service: Failed to add product to point %s: %v", pointID, err)
 		if errors.Is(err, repository.ErrNoPointFound) {
 			return entity.Product{}, ErrNoPointFound
 		}
 		if errors.Is(err, repository.ErrNoReceptionFound) {
 			return entity.Product{}, ErrNoReceptionFound
 		}
+		if errors.Is(err, repository.ErrBarcodeAlreadyExists) {
+			return entity.Product{}, ErrBarcodeAlreadyExists
+		}
 		if !errors.Is(err, ErrReceptionAlreadyClosed) {
 			s.metrics.ErrInc()
 		}
 		return entity.Product{}, err
 	}
 
-	logrus.Infof("Service: Product added: %+v", out)
+	logger.FromContext(ctx).Infof("Service: Product added: %+v", out)
 	s.metrics.Inc()
 	return out, nil
 }
 
-func (s *Service) DeleteLastProductFromReception(ctx context.Context, pointID uuid.UUID) error {
-	logrus.Infof("Service: Deleting last product from reception for point: %s", pointID)
+func (s *Service) DeleteLastProductFromReception(ctx context.Context, actorID uuid.UUID, pointID uuid.UUID) error {
+	logger.FromContext(ctx).Infof("Service: Deleting last product from reception for point: %s", pointID)
 	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
 		// Status check
 		status, err := s.receptionRepository.GetLastReceptionStatus(ctx, pointID)
 
 		if err != nil {
-			logrus.Errorf("Service: Failed to get last reception status for point %s: %v", pointID, err)
+			logger.FromContext(ctx).Errorf("Service: Failed to get last reception status for point %s: %v", pointID, err)
 			return err
 		}
 
 		if status != entity.ReceptionStatusInProgress {
-			logrus.Warnf("Service: Reception already closed for point: %s", pointID)
+			logger.FromContext(ctx).Warnf("Service: Reception already closed for point: %s", pointID)
 			return ErrReceptionAlreadyClosed
 		}
 
 		// Delete
-		return s.productRepository.DeleteLastFromReception(ctx, pointID)
+		product, err := s.productRepository.DeleteLastFromReception(ctx, pointID)
+		if err != nil {
+			return err
+		}
+
+		return s.audit(ctx, actorID, entity.AuditActionProductDeleted, product.ID, productSnapshotOf(product), nil)
 	})
 
 	if err != nil {
-		logrus.Errorf("Service: Failed to delete last product from reception for point %s: %v", pointID, err)
+		logger.FromContext(ctx).Errorf("Service: Failed to delete last product from reception for point %s: %v", pointID, err)
 		if errors.Is(err, repository.ErrNoPointFound) {
 			return ErrNoPointFound
 		}
@@ -103,6 +139,140 @@ func (s *Service) DeleteLastProductFromReception(ctx context.Context, pointID uu
 		return err
 	}
 
-	logrus.Infof("Service: Deleted last product from reception for point: %s", pointID)
+	logger.FromContext(ctx).Infof("Service: Deleted last product from reception for point: %s", pointID)
 	return nil
 }
+
+func (s *Service) ChangeStatus(
+	ctx context.Context,
+	productID uuid.UUID,
+	to entity.ProductStatus,
+	actorID uuid.UUID,
+	reason string,
+) (entity.Product, error) {
+	logger.FromContext(ctx).Infof("Service: Changing status of product %s to %s by %s", productID, to, actorID)
+
+	if to.RequiresReason() && reason == "" {
+		logger.FromContext(ctx).Warnf("Service: No reason given for moving product %s to %s", productID, to)
+		return entity.Product{}, ErrReasonRequired
+	}
+
+	var out entity.Product
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		product, err := s.productRepository.GetByIDForUpdate(ctx, productID)
+		if err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to get product %s: %v", productID, err)
+			return err
+		}
+
+		if !product.Status.CanTransitionTo(to) {
+			logger.FromContext(ctx).Warnf("Service: Product %s cannot move from %s to %s", productID, product.Status, to)
+			return ErrInvalidTransition
+		}
+
+		// Products of an open reception may still be deleted, so they stay put until it is closed
+		if product.Status == entity.ProductStatusReceived {
+			status, err := s.receptionRepository.GetStatusByID(ctx, product.ReceptionID)
+			if err != nil {
+				logger.FromContext(ctx).Errorf("Service: Failed to get status of reception %s: %v", product.ReceptionID, err)
+				return err
+			}
+			if status != entity.ReceptionStatusClosed {
+				logger.FromContext(ctx).Warnf("Service: Reception %s of product %s is not closed", product.ReceptionID, productID)
+				return ErrReceptionNotClosed
+			}
+		}
+
+		if err = s.productRepository.UpdateStatus(ctx, productID, to); err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to update status of product %s: %v", productID, err)
+			return err
+		}
+
+		from := product.Status
+		_, err = s.productRepository.CreateHistory(ctx, entity.ProductHistory{
+			ProductID:  productID,
+			FromStatus: &from,
+			ToStatus:   to,
+			Reason:     reason,
+			ActorID:    actorID,
+		})
+		if err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to record history for product %s: %v", productID, err)
+			return err
+		}
+
+		before := productSnapshotOf(product)
+		product.Status = to
+		after := productSnapshotOf(product)
+		after.Reason = reason
+		if err = s.audit(ctx, actorID, entity.AuditActionProductStatusChanged, productID, before, after); err != nil {
+			return err
+		}
+
+		out = product
+		return nil
+	})
+
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to change status of product %s: %v", productID, err)
+		if errors.Is(err, repository.ErrNoProductFound) {
+			return entity.Product{}, ErrNoProductFound
+		}
+		if errors.Is(err, repository.ErrNoReceptionFound) {
+			return entity.Product{}, ErrNoReceptionFound
+		}
+		return entity.Product{}, err
+	}
+
+	logger.FromContext(ctx).Infof("Service: Product %s moved to %s", productID, to)
+	return out, nil
+}
+
+// audit writes a record for a product. A nil after marks a deleted product.
+func (s *Service) audit(ctx context.Context, actorID uuid.UUID, action entity.AuditAction, productID uuid.UUID, before any, after any) error {
+	record := entity.AuditRecord{
+		ActorID:    actorID,
+		Action:     action,
+		TargetType: entity.AuditTargetProduct,
+		TargetID:   productID,
+	}
+
+	var err error
+	if before != nil {
+		if record.Before, err = json.Marshal(before); err != nil {
+			return err
+		}
+	}
+	if after != nil {
+		if record.After, err = json.Marshal(after); err != nil {
+			return err
+		}
+	}
+
+	if err = s.auditRepository.Create(ctx, record); err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to write audit record: %v", err)
+	}
+	return err
+}
+
+func productSnapshotOf(p entity.Product) productSnapshot {
+	return productSnapshot{
+		ReceptionID: p.ReceptionID,
+		Type:        p.Type,
+		Status:      p.Status,
+		Barcode:     p.Barcode,
+	}
+}
+
+func (s *Service) GetHistory(ctx context.Context, productID uuid.UUID) ([]entity.ProductHistory, error) {
+	logger.FromContext(ctx).Infof("Service: Fetching history for product: %s", productID)
+
+	history, err := s.productRepository.GetHistory(ctx, productID)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to fetch history for product %s: %v", productID, err)
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Infof("Service: Fetched %d history records for product %s", len(history), productID)
+	return history, nil
+}
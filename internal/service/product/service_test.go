@@ -18,8 +18,10 @@ import (
 func TestAddProduct(t *testing.T) {
 	var (
 		ctx             = context.Background()
+		actorID         = uuid.New()
 		pointID         = uuid.New()
 		productType     = entity.ProductTypeElectronics
+		barcode         = "4600000000017"
 		lastReceptionID = uuid.New()
 		arbitraryErr    = errors.New("arbitraryErr")
 
@@ -30,11 +32,28 @@ func TestAddProduct(t *testing.T) {
 		ID:          uuid.Max,
 		ReceptionID: lastReceptionID,
 		Type:        productType,
+		Status:      entity.ProductStatusReceived,
+		Barcode:     &barcode,
+	}
+
+	record := entity.AuditRecord{
+		ActorID:    actorID,
+		Action:     entity.AuditActionProductAdded,
+		TargetType: entity.AuditTargetProduct,
+		TargetID:   uuid.Max,
+		After:      []byte(`{"reception_id":"` + lastReceptionID.String() + `","type":"` + string(productType) + `","status":"received","barcode":"4600000000017"}`),
+	}
+
+	received := entity.ProductHistory{
+		ProductID: uuid.Max,
+		ToStatus:  entity.ProductStatusReceived,
+		ActorID:   actorID,
 	}
 
 	type MockBehavior func(
 		productRepo *mocks.MockProductsRepository,
 		receptionRepo *mocks.MockReceptionRepository,
+		auditRepo *mocks.MockAuditRepository,
 		t *mock_transactor.MockTransactor,
 		m *mocks.MockMetrics,
 	)
@@ -47,7 +66,7 @@ func TestAddProduct(t *testing.T) {
 	}{
 		{
 			name: "success",
-			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, t *mock_transactor.MockTransactor, m *mocks.MockMetrics) {
+			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, auditRepo *mocks.MockAuditRepository, t *mock_transactor.MockTransactor, m *mocks.MockMetrics) {
 				t.EXPECT().WithinTransaction(ctx, gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 						return fn(ctx)
@@ -55,7 +74,9 @@ func TestAddProduct(t *testing.T) {
 
 				receptionRepo.EXPECT().GetLastReceptionStatus(ctx, pointID).Return(entity.ReceptionStatusInProgress, nil).Times(1)
 
-				productRepo.EXPECT().Create(ctx, pointID, productType).Return(productOut, nil).Times(1)
+				productRepo.EXPECT().Create(ctx, pointID, productType, barcode).Return(productOut, nil).Times(1)
+				productRepo.EXPECT().CreateHistory(ctx, received).Return(received, nil).Times(1)
+				auditRepo.EXPECT().Create(ctx, record).Return(nil).Times(1)
 
 				m.EXPECT().Inc().Times(1)
 			},
@@ -64,7 +85,7 @@ func TestAddProduct(t *testing.T) {
 		},
 		{
 			name: "reception already closed",
-			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, t *mock_transactor.MockTransactor, m *mocks.MockMetrics) {
+			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, auditRepo *mocks.MockAuditRepository, t *mock_transactor.MockTransactor, m *mocks.MockMetrics) {
 				t.EXPECT().WithinTransaction(ctx, gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 						return fn(ctx)
@@ -77,7 +98,7 @@ func TestAddProduct(t *testing.T) {
 		},
 		{
 			name: "fetching reception status error",
-			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, t *mock_transactor.MockTransactor, m *mocks.MockMetrics) {
+			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, auditRepo *mocks.MockAuditRepository, t *mock_transactor.MockTransactor, m *mocks.MockMetrics) {
 				t.EXPECT().WithinTransaction(ctx, gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 						return fn(ctx)
@@ -91,7 +112,7 @@ func TestAddProduct(t *testing.T) {
 		},
 		{
 			name: "creating error no reception found",
-			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, t *mock_transactor.MockTransactor, m *mocks.MockMetrics) {
+			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, auditRepo *mocks.MockAuditRepository, t *mock_transactor.MockTransactor, m *mocks.MockMetrics) {
 				t.EXPECT().WithinTransaction(ctx, gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 						return fn(ctx)
@@ -99,14 +120,14 @@ func TestAddProduct(t *testing.T) {
 
 				receptionRepo.EXPECT().GetLastReceptionStatus(ctx, pointID).Return(entity.ReceptionStatusInProgress, nil).Times(1)
 
-				productRepo.EXPECT().Create(ctx, pointID, productType).Return(entity.Product{}, repository.ErrNoReceptionFound).Times(1)
+				productRepo.EXPECT().Create(ctx, pointID, productType, barcode).Return(entity.Product{}, repository.ErrNoReceptionFound).Times(1)
 			},
 			want:    entity.Product{},
 			wantErr: service.ErrNoReceptionFound,
 		},
 		{
 			name: "creating error no point found",
-			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, t *mock_transactor.MockTransactor, m *mocks.MockMetrics) {
+			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, auditRepo *mocks.MockAuditRepository, t *mock_transactor.MockTransactor, m *mocks.MockMetrics) {
 				t.EXPECT().WithinTransaction(ctx, gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 						return fn(ctx)
@@ -114,14 +135,49 @@ func TestAddProduct(t *testing.T) {
 
 				receptionRepo.EXPECT().GetLastReceptionStatus(ctx, pointID).Return(entity.ReceptionStatusInProgress, nil).Times(1)
 
-				productRepo.EXPECT().Create(ctx, pointID, productType).Return(entity.Product{}, repository.ErrNoPointFound).Times(1)
+				productRepo.EXPECT().Create(ctx, pointID, productType, barcode).Return(entity.Product{}, repository.ErrNoPointFound).Times(1)
 			},
 			want:    entity.Product{},
 			wantErr: service.ErrNoPointFound,
 		},
 		{
 			name: "creating arbitrary error",
-			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, t *mock_transactor.MockTransactor, m *mocks.MockMetrics) {
+			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, auditRepo *mocks.MockAuditRepository, t *mock_transactor.MockTransactor, m *mocks.MockMetrics) {
+				t.EXPECT().WithinTransaction(ctx, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+
+				receptionRepo.EXPECT().GetLastReceptionStatus(ctx, pointID).Return(entity.ReceptionStatusInProgress, nil).Times(1)
+
+				productRepo.EXPECT().Create(ctx, pointID, productType, barcode).Return(entity.Product{}, arbitraryErr).Times(1)
+
+				m.EXPECT().ErrInc().Times(1)
+			},
+			want:    entity.Product{},
+			wantErr: arbitraryErr,
+		},
+		{
+			name: "history error",
+			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, auditRepo *mocks.MockAuditRepository, t *mock_transactor.MockTransactor, m *mocks.MockMetrics) {
+				t.EXPECT().WithinTransaction(ctx, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+
+				receptionRepo.EXPECT().GetLastReceptionStatus(ctx, pointID).Return(entity.ReceptionStatusInProgress, nil).Times(1)
+
+				productRepo.EXPECT().Create(ctx, pointID, productType, barcode).Return(productOut, nil).Times(1)
+				productRepo.EXPECT().CreateHistory(ctx, received).Return(entity.ProductHistory{}, arbitraryErr).Times(1)
+
+				m.EXPECT().ErrInc().Times(1)
+			},
+			want:    entity.Product{},
+			wantErr: arbitraryErr,
+		},
+		{
+			name: "audit error",
+			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, auditRepo *mocks.MockAuditRepository, t *mock_transactor.MockTransactor, m *mocks.MockMetrics) {
 				t.EXPECT().WithinTransaction(ctx, gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 						return fn(ctx)
@@ -129,7 +185,9 @@ func TestAddProduct(t *testing.T) {
 
 				receptionRepo.EXPECT().GetLastReceptionStatus(ctx, pointID).Return(entity.ReceptionStatusInProgress, nil).Times(1)
 
-				productRepo.EXPECT().Create(ctx, pointID, productType).Return(entity.Product{}, arbitraryErr).Times(1)
+				productRepo.EXPECT().Create(ctx, pointID, productType, barcode).Return(productOut, nil).Times(1)
+				productRepo.EXPECT().CreateHistory(ctx, received).Return(received, nil).Times(1)
+				auditRepo.EXPECT().Create(ctx, record).Return(arbitraryErr).Times(1)
 
 				m.EXPECT().ErrInc().Times(1)
 			},
@@ -143,14 +201,15 @@ func TestAddProduct(t *testing.T) {
 
 			MockReceptionRepository := mocks.NewMockReceptionRepository(ctrl)
 			MockProductRepository := mocks.NewMockProductsRepository(ctrl)
+			MockAuditRepository := mocks.NewMockAuditRepository(ctrl)
 			MockTransactor := mock_transactor.NewMockTransactor(ctrl)
 			MockMetrics := mocks.NewMockMetrics(ctrl)
 
-			tc.mockBehavior(MockProductRepository, MockReceptionRepository, MockTransactor, MockMetrics)
+			tc.mockBehavior(MockProductRepository, MockReceptionRepository, MockAuditRepository, MockTransactor, MockMetrics)
 
-			s := service.New(MockProductRepository, MockReceptionRepository, MockTransactor, MockMetrics)
+			s := service.New(MockProductRepository, MockReceptionRepository, MockAuditRepository, MockTransactor, MockMetrics)
 
-			out, err := s.AddProduct(ctx, pointID, productType)
+			out, err := s.AddProduct(ctx, actorID, pointID, productType, barcode)
 			assert.ErrorIs(t, err, tc.wantErr)
 			assert.Equal(t, tc.want, out)
 		})
@@ -160,15 +219,33 @@ func TestAddProduct(t *testing.T) {
 func TestDeleteLastProductFromReception(t *testing.T) {
 	var (
 		ctx          = context.Background()
+		actorID      = uuid.New()
 		pointID      = uuid.New()
+		receptionID  = uuid.New()
 		arbitraryErr = errors.New("arbitraryErr")
 
 		emptyStatus entity.ReceptionStatus = ""
 	)
 
+	deleted := entity.Product{
+		ID:          uuid.Max,
+		ReceptionID: receptionID,
+		Type:        entity.ProductTypeShoes,
+		Status:      entity.ProductStatusReceived,
+	}
+
+	record := entity.AuditRecord{
+		ActorID:    actorID,
+		Action:     entity.AuditActionProductDeleted,
+		TargetType: entity.AuditTargetProduct,
+		TargetID:   uuid.Max,
+		Before:     []byte(`{"reception_id":"` + receptionID.String() + `","type":"` + string(entity.ProductTypeShoes) + `","status":"received"}`),
+	}
+
 	type MockBehavior func(
 		productRepo *mocks.MockProductsRepository,
 		receptionRepo *mocks.MockReceptionRepository,
+		auditRepo *mocks.MockAuditRepository,
 		t *mock_transactor.MockTransactor,
 	)
 
@@ -179,7 +256,7 @@ func TestDeleteLastProductFromReception(t *testing.T) {
 	}{
 		{
 			name: "success",
-			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, t *mock_transactor.MockTransactor) {
+			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, auditRepo *mocks.MockAuditRepository, t *mock_transactor.MockTransactor) {
 				t.EXPECT().WithinTransaction(ctx, gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 						return fn(ctx)
@@ -187,13 +264,14 @@ func TestDeleteLastProductFromReception(t *testing.T) {
 
 				receptionRepo.EXPECT().GetLastReceptionStatus(ctx, pointID).Return(entity.ReceptionStatusInProgress, nil).Times(1)
 
-				productRepo.EXPECT().DeleteLastFromReception(ctx, pointID).Return(nil).Times(1)
+				productRepo.EXPECT().DeleteLastFromReception(ctx, pointID).Return(deleted, nil).Times(1)
+				auditRepo.EXPECT().Create(ctx, record).Return(nil).Times(1)
 			},
 			wantErr: nil,
 		},
 		{
 			name: "reception already closed",
-			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, t *mock_transactor.MockTransactor) {
+			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, auditRepo *mocks.MockAuditRepository, t *mock_transactor.MockTransactor) {
 				t.EXPECT().WithinTransaction(ctx, gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 						return fn(ctx)
@@ -205,7 +283,7 @@ func TestDeleteLastProductFromReception(t *testing.T) {
 		},
 		{
 			name: "fetching reception status error",
-			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, t *mock_transactor.MockTransactor) {
+			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, auditRepo *mocks.MockAuditRepository, t *mock_transactor.MockTransactor) {
 				t.EXPECT().WithinTransaction(ctx, gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 						return fn(ctx)
@@ -217,7 +295,7 @@ func TestDeleteLastProductFromReception(t *testing.T) {
 		},
 		{
 			name: "deleting error no reception found",
-			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, t *mock_transactor.MockTransactor) {
+			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, auditRepo *mocks.MockAuditRepository, t *mock_transactor.MockTransactor) {
 				t.EXPECT().WithinTransaction(ctx, gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 						return fn(ctx)
@@ -225,13 +303,13 @@ func TestDeleteLastProductFromReception(t *testing.T) {
 
 				receptionRepo.EXPECT().GetLastReceptionStatus(ctx, pointID).Return(entity.ReceptionStatusInProgress, nil).Times(1)
 
-				productRepo.EXPECT().DeleteLastFromReception(ctx, pointID).Return(repository.ErrNoReceptionFound).Times(1)
+				productRepo.EXPECT().DeleteLastFromReception(ctx, pointID).Return(entity.Product{}, repository.ErrNoReceptionFound).Times(1)
 			},
 			wantErr: service.ErrNoReceptionFound,
 		},
 		{
 			name: "deleting error no point found",
-			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, t *mock_transactor.MockTransactor) {
+			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, auditRepo *mocks.MockAuditRepository, t *mock_transactor.MockTransactor) {
 				t.EXPECT().WithinTransaction(ctx, gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 						return fn(ctx)
@@ -239,13 +317,27 @@ func TestDeleteLastProductFromReception(t *testing.T) {
 
 				receptionRepo.EXPECT().GetLastReceptionStatus(ctx, pointID).Return(entity.ReceptionStatusInProgress, nil).Times(1)
 
-				productRepo.EXPECT().DeleteLastFromReception(ctx, pointID).Return(repository.ErrNoPointFound).Times(1)
+				productRepo.EXPECT().DeleteLastFromReception(ctx, pointID).Return(entity.Product{}, repository.ErrNoPointFound).Times(1)
 			},
 			wantErr: service.ErrNoPointFound,
 		},
 		{
 			name: "deleting arbitrary error",
-			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, t *mock_transactor.MockTransactor) {
+			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, auditRepo *mocks.MockAuditRepository, t *mock_transactor.MockTransactor) {
+				t.EXPECT().WithinTransaction(ctx, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+
+				receptionRepo.EXPECT().GetLastReceptionStatus(ctx, pointID).Return(entity.ReceptionStatusInProgress, nil).Times(1)
+
+				productRepo.EXPECT().DeleteLastFromReception(ctx, pointID).Return(entity.Product{}, arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+		{
+			name: "audit error",
+			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, auditRepo *mocks.MockAuditRepository, t *mock_transactor.MockTransactor) {
 				t.EXPECT().WithinTransaction(ctx, gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 						return fn(ctx)
@@ -253,8 +345,206 @@ func TestDeleteLastProductFromReception(t *testing.T) {
 
 				receptionRepo.EXPECT().GetLastReceptionStatus(ctx, pointID).Return(entity.ReceptionStatusInProgress, nil).Times(1)
 
-				productRepo.EXPECT().DeleteLastFromReception(ctx, pointID).Return(arbitraryErr).Times(1)
+				productRepo.EXPECT().DeleteLastFromReception(ctx, pointID).Return(deleted, nil).Times(1)
+				auditRepo.EXPECT().Create(ctx, record).Return(arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			MockReceptionRepository := mocks.NewMockReceptionRepository(ctrl)
+			MockProductRepository := mocks.NewMockProductsRepository(ctrl)
+			MockAuditRepository := mocks.NewMockAuditRepository(ctrl)
+			MockTransactor := mock_transactor.NewMockTransactor(ctrl)
+			MockMetrics := mocks.NewMockMetrics(ctrl)
+
+			tc.mockBehavior(MockProductRepository, MockReceptionRepository, MockAuditRepository, MockTransactor)
+
+			s := service.New(MockProductRepository, MockReceptionRepository, MockAuditRepository, MockTransactor, MockMetrics)
+
+			err := s.DeleteLastProductFromReception(ctx, actorID, pointID)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestChangeStatus(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		productID    = uuid.New()
+		receptionID  = uuid.New()
+		actorID      = uuid.New()
+		reason       = "damaged package"
+		arbitraryErr = errors.New("arbitraryErr")
+	)
+
+	received := entity.Product{
+		ID:          productID,
+		ReceptionID: receptionID,
+		Type:        entity.ProductTypeShoes,
+		Status:      entity.ProductStatusReceived,
+	}
+	stored := received
+	stored.Status = entity.ProductStatusStored
+
+	withinTx := func(t *mock_transactor.MockTransactor) {
+		t.EXPECT().WithinTransaction(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+	}
+
+	type MockBehavior func(
+		productRepo *mocks.MockProductsRepository,
+		receptionRepo *mocks.MockReceptionRepository,
+		auditRepo *mocks.MockAuditRepository,
+		t *mock_transactor.MockTransactor,
+	)
+
+	for _, tc := range []struct {
+		name         string
+		to           entity.ProductStatus
+		reason       string
+		mockBehavior MockBehavior
+		want         entity.Product
+		wantErr      error
+	}{
+		{
+			name: "success store",
+			to:   entity.ProductStatusStored,
+			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, auditRepo *mocks.MockAuditRepository, t *mock_transactor.MockTransactor) {
+				withinTx(t)
+				productRepo.EXPECT().GetByIDForUpdate(ctx, productID).Return(received, nil).Times(1)
+				receptionRepo.EXPECT().GetStatusByID(ctx, receptionID).Return(entity.ReceptionStatusClosed, nil).Times(1)
+				productRepo.EXPECT().UpdateStatus(ctx, productID, entity.ProductStatusStored).Return(nil).Times(1)
+				from := entity.ProductStatusReceived
+				productRepo.EXPECT().CreateHistory(ctx, entity.ProductHistory{
+					ProductID:  productID,
+					FromStatus: &from,
+					ToStatus:   entity.ProductStatusStored,
+					ActorID:    actorID,
+				}).Return(entity.ProductHistory{}, nil).Times(1)
+				auditRepo.EXPECT().Create(ctx, entity.AuditRecord{
+					ActorID:    actorID,
+					Action:     entity.AuditActionProductStatusChanged,
+					TargetType: entity.AuditTargetProduct,
+					TargetID:   productID,
+					Before:     []byte(`{"reception_id":"` + receptionID.String() + `","type":"` + string(entity.ProductTypeShoes) + `","status":"received"}`),
+					After:      []byte(`{"reception_id":"` + receptionID.String() + `","type":"` + string(entity.ProductTypeShoes) + `","status":"stored"}`),
+				}).Return(nil).Times(1)
+			},
+			want:    stored,
+			wantErr: nil,
+		},
+		{
+			name:   "success write off",
+			to:     entity.ProductStatusWrittenOff,
+			reason: reason,
+			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, auditRepo *mocks.MockAuditRepository, t *mock_transactor.MockTransactor) {
+				withinTx(t)
+				productRepo.EXPECT().GetByIDForUpdate(ctx, productID).Return(stored, nil).Times(1)
+				productRepo.EXPECT().UpdateStatus(ctx, productID, entity.ProductStatusWrittenOff).Return(nil).Times(1)
+				from := entity.ProductStatusStored
+				productRepo.EXPECT().CreateHistory(ctx, entity.ProductHistory{
+					ProductID:  productID,
+					FromStatus: &from,
+					ToStatus:   entity.ProductStatusWrittenOff,
+					Reason:     reason,
+					ActorID:    actorID,
+				}).Return(entity.ProductHistory{}, nil).Times(1)
+				auditRepo.EXPECT().Create(ctx, entity.AuditRecord{
+					ActorID:    actorID,
+					Action:     entity.AuditActionProductStatusChanged,
+					TargetType: entity.AuditTargetProduct,
+					TargetID:   productID,
+					Before:     []byte(`{"reception_id":"` + receptionID.String() + `","type":"` + string(entity.ProductTypeShoes) + `","status":"stored"}`),
+					After:      []byte(`{"reception_id":"` + receptionID.String() + `","type":"` + string(entity.ProductTypeShoes) + `","status":"written_off","reason":"damaged package"}`),
+				}).Return(nil).Times(1)
 			},
+			want: entity.Product{
+				ID:          productID,
+				ReceptionID: receptionID,
+				Type:        entity.ProductTypeShoes,
+				Status:      entity.ProductStatusWrittenOff,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "reason required",
+			to:   entity.ProductStatusReturned,
+			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, auditRepo *mocks.MockAuditRepository, t *mock_transactor.MockTransactor) {
+			},
+			want:    entity.Product{},
+			wantErr: service.ErrReasonRequired,
+		},
+		{
+			name: "invalid transition",
+			to:   entity.ProductStatusIssued,
+			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, auditRepo *mocks.MockAuditRepository, t *mock_transactor.MockTransactor) {
+				withinTx(t)
+				productRepo.EXPECT().GetByIDForUpdate(ctx, productID).Return(received, nil).Times(1)
+			},
+			want:    entity.Product{},
+			wantErr: service.ErrInvalidTransition,
+		},
+		{
+			name: "reception not closed",
+			to:   entity.ProductStatusStored,
+			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, auditRepo *mocks.MockAuditRepository, t *mock_transactor.MockTransactor) {
+				withinTx(t)
+				productRepo.EXPECT().GetByIDForUpdate(ctx, productID).Return(received, nil).Times(1)
+				receptionRepo.EXPECT().GetStatusByID(ctx, receptionID).Return(entity.ReceptionStatusInProgress, nil).Times(1)
+			},
+			want:    entity.Product{},
+			wantErr: service.ErrReceptionNotClosed,
+		},
+		{
+			name: "no product found",
+			to:   entity.ProductStatusStored,
+			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, auditRepo *mocks.MockAuditRepository, t *mock_transactor.MockTransactor) {
+				withinTx(t)
+				productRepo.EXPECT().GetByIDForUpdate(ctx, productID).Return(entity.Product{}, repository.ErrNoProductFound).Times(1)
+			},
+			want:    entity.Product{},
+			wantErr: service.ErrNoProductFound,
+		},
+		{
+			name: "updating arbitrary error",
+			to:   entity.ProductStatusIssued,
+			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, auditRepo *mocks.MockAuditRepository, t *mock_transactor.MockTransactor) {
+				withinTx(t)
+				productRepo.EXPECT().GetByIDForUpdate(ctx, productID).Return(stored, nil).Times(1)
+				productRepo.EXPECT().UpdateStatus(ctx, productID, entity.ProductStatusIssued).Return(arbitraryErr).Times(1)
+			},
+			want:    entity.Product{},
+			wantErr: arbitraryErr,
+		},
+		{
+			name: "recording history arbitrary error",
+			to:   entity.ProductStatusIssued,
+			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, auditRepo *mocks.MockAuditRepository, t *mock_transactor.MockTransactor) {
+				withinTx(t)
+				productRepo.EXPECT().GetByIDForUpdate(ctx, productID).Return(stored, nil).Times(1)
+				productRepo.EXPECT().UpdateStatus(ctx, productID, entity.ProductStatusIssued).Return(nil).Times(1)
+				productRepo.EXPECT().CreateHistory(ctx, gomock.Any()).Return(entity.ProductHistory{}, arbitraryErr).Times(1)
+			},
+			want:    entity.Product{},
+			wantErr: arbitraryErr,
+		},
+		{
+			name: "audit error",
+			to:   entity.ProductStatusIssued,
+			mockBehavior: func(productRepo *mocks.MockProductsRepository, receptionRepo *mocks.MockReceptionRepository, auditRepo *mocks.MockAuditRepository, t *mock_transactor.MockTransactor) {
+				withinTx(t)
+				productRepo.EXPECT().GetByIDForUpdate(ctx, productID).Return(stored, nil).Times(1)
+				productRepo.EXPECT().UpdateStatus(ctx, productID, entity.ProductStatusIssued).Return(nil).Times(1)
+				productRepo.EXPECT().CreateHistory(ctx, gomock.Any()).Return(entity.ProductHistory{}, nil).Times(1)
+				auditRepo.EXPECT().Create(ctx, gomock.Any()).Return(arbitraryErr).Times(1)
+			},
+			want:    entity.Product{},
 			wantErr: arbitraryErr,
 		},
 	} {
@@ -264,15 +554,17 @@ func TestDeleteLastProductFromReception(t *testing.T) {
 
 			MockReceptionRepository := mocks.NewMockReceptionRepository(ctrl)
 			MockProductRepository := mocks.NewMockProductsRepository(ctrl)
+			MockAuditRepository := mocks.NewMockAuditRepository(ctrl)
 			MockTransactor := mock_transactor.NewMockTransactor(ctrl)
 			MockMetrics := mocks.NewMockMetrics(ctrl)
 
-			tc.mockBehavior(MockProductRepository, MockReceptionRepository, MockTransactor)
+			tc.mockBehavior(MockProductRepository, MockReceptionRepository, MockAuditRepository, MockTransactor)
 
-			s := service.New(MockProductRepository, MockReceptionRepository, MockTransactor, MockMetrics)
+			s := service.New(MockProductRepository, MockReceptionRepository, MockAuditRepository, MockTransactor, MockMetrics)
 
-			err := s.DeleteLastProductFromReception(ctx, pointID)
+			out, err := s.ChangeStatus(ctx, productID, tc.to, actorID, tc.reason)
 			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.want, out)
 		})
 	}
 }
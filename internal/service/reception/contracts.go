@@ -10,13 +10,17 @@ import (
 //go:generate go tool mockgen -source=contracts.go -destination=mocks/reception_repo_mock.go
 
 type ReceptionRepository interface {
-	Open(ctx context.Context, pointID uuid.UUID) (entity.Reception, error)
+	Open(ctx context.Context, pointID uuid.UUID, kind entity.ReceptionKind) (entity.Reception, error)
 	GetLastReceptionStatus(ctx context.Context, pointID uuid.UUID) (entity.ReceptionStatus, error)
 	GetLastReceptionProductsAmount(ctx context.Context, pointID uuid.UUID) (int, error)
-	CloseLastReception(ctx context.Context, pointID uuid.UUID) error
+	CloseLastReception(ctx context.Context, pointID uuid.UUID) (entity.Reception, error)
 	CheckIfPointExists(ctx context.Context, pointID uuid.UUID) (bool, error)
 }
 
+type AuditRepository interface {
+	Create(ctx context.Context, record entity.AuditRecord) error
+}
+
 type Metrics interface {
 	Inc()
 	ErrInc()
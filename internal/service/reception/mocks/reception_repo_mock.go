@@ -58,11 +58,12 @@ func (mr *MockReceptionRepositoryMockRecorder) CheckIfPointExists(ctx, pointID a
 }
 
 // CloseLastReception mocks base method.
-func (m *MockReceptionRepository) CloseLastReception(ctx context.Context, pointID uuid.UUID) error {
+func (m *MockReceptionRepository) CloseLastReception(ctx context.Context, pointID uuid.UUID) (entity.Reception, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CloseLastReception", ctx, pointID)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(entity.Reception)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // CloseLastReception indicates an expected call of CloseLastReception.
@@ -102,18 +103,56 @@ func (mr *MockReceptionRepositoryMockRecorder) GetLastReceptionStatus(ctx, point
 }
 
 // Open mocks base method.
-func (m *MockReceptionRepository) Open(ctx context.Context, pointID uuid.UUID) (entity.Reception, error) {
+func (m *MockReceptionRepository) Open(ctx context.Context, pointID uuid.UUID, kind entity.ReceptionKind) (entity.Reception, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Open", ctx, pointID)
+	ret := m.ctrl.Call(m, "Open", ctx, pointID, kind)
 	ret0, _ := ret[0].(entity.Reception)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Open indicates an expected call of Open.
-func (mr *MockReceptionRepositoryMockRecorder) Open(ctx, pointID any) *gomock.Call {
+func (mr *MockReceptionRepositoryMockRecorder) Open(ctx, pointID, kind any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockReceptionRepository)(nil).Open), ctx, pointID, kind)
+}
+
+// MockAuditRepository is a mock of AuditRepository interface.
+type MockAuditRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAuditRepositoryMockRecorder is the mock recorder for MockAuditRepository.
+type MockAuditRepositoryMockRecorder struct {
+	mock *MockAuditRepository
+}
+
+// NewMockAuditRepository creates a new mock instance.
+func NewMockAuditRepository(ctrl *gomock.Controller) *MockAuditRepository {
+	mock := &MockAuditRepository{ctrl: ctrl}
+	mock.recorder = &MockAuditRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditRepository) EXPECT() *MockAuditRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAuditRepository) Create(ctx context.Context, record entity.AuditRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAuditRepositoryMockRecorder) Create(ctx, record any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockReceptionRepository)(nil).Open), ctx, pointID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAuditRepository)(nil).Create), ctx, record)
 }
 
 // MockMetrics is a mock of Metrics interface.
@@ -2,41 +2,50 @@ package reception
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 
 	"github.com/4udiwe/avito-pvz/internal/entity"
 	"github.com/4udiwe/avito-pvz/internal/repository"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
 	"github.com/4udiwe/avito-pvz/pkg/transactor"
 	"github.com/google/uuid"
-	"github.com/sirupsen/logrus"
 )
 
+type receptionSnapshot struct {
+	PointID uuid.UUID              `json:"point_id"`
+	Kind    entity.ReceptionKind   `json:"kind"`
+	Status  entity.ReceptionStatus `json:"status"`
+}
+
 type Service struct {
 	receptionRepository ReceptionRepository
+	auditRepository     AuditRepository
 	txManager           transactor.Transactor
 	metrics             Metrics
 }
 
-func New(r ReceptionRepository, tx transactor.Transactor, m Metrics) *Service {
+func New(r ReceptionRepository, a AuditRepository, tx transactor.Transactor, m Metrics) *Service {
 	return &Service{
 		receptionRepository: r,
+		auditRepository:     a,
 		txManager:           tx,
 		metrics:             m,
 	}
 }
 
-func (s *Service) OpenReception(ctx context.Context, pointID uuid.UUID) (entity.Reception, error) {
-	logrus.Infof("Service: Opening reception for point: %s", pointID)
+func (s *Service) OpenReception(ctx context.Context, actorID uuid.UUID, pointID uuid.UUID, kind entity.ReceptionKind) (entity.Reception, error) {
+	logger.FromContext(ctx).Infof("Service: Opening %s reception for point: %s", kind, pointID)
 	var reception entity.Reception
 	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
 		// Point existence check
 		exists, err := s.receptionRepository.CheckIfPointExists(ctx, pointID)
 		if err != nil {
-			logrus.Errorf("Service: Failed to check if point exists for point %s: %v", pointID, err)
+			logger.FromContext(ctx).Errorf("Service: Failed to check if point exists for point %s: %v", pointID, err)
 			return err
 		}
 		if !exists {
-			logrus.Warnf("Service: Point does not exist: %s", pointID)
+			logger.FromContext(ctx).Warnf("Service: Point does not exist: %s", pointID)
 			return ErrNoPointFound
 		}
 
@@ -44,45 +53,49 @@ func (s *Service) OpenReception(ctx context.Context, pointID uuid.UUID) (entity.
 		status, err := s.receptionRepository.GetLastReceptionStatus(ctx, pointID)
 
 		if err != nil {
-			logrus.Errorf("Service: Failed to get last reception status for point %s: %v", pointID, err)
+			logger.FromContext(ctx).Errorf("Service: Failed to get last reception status for point %s: %v", pointID, err)
 			return err
 		}
 
 		if status != entity.ReceptionStatusClosed {
-			logrus.Warnf("Service: Last reception not closed for point: %s", pointID)
+			logger.FromContext(ctx).Warnf("Service: Last reception not closed for point: %s", pointID)
 			return ErrLastReceptionNotClosed
 		}
 
 		// Open
-		reception, err = s.receptionRepository.Open(ctx, pointID)
+		reception, err = s.receptionRepository.Open(ctx, pointID, kind)
+		if err != nil {
+			return err
+		}
 
-		return err
+		return s.audit(ctx, actorID, entity.AuditActionReceptionOpened, reception.ID, nil, receptionSnapshotOf(reception))
 	})
 
 	if err != nil {
-		logrus.Errorf("Service: Failed to open reception for point %s: %v", pointID, err)
+		logger.FromContext(ctx).Errorf("Service: Failed to open reception for point %s: %v", pointID, err)
 		if !errors.Is(err, ErrNoPointFound) && !errors.Is(err, ErrLastReceptionNotClosed) {
 			s.metrics.ErrInc()
 		}
 		return entity.Reception{}, err
 	}
 
-	logrus.Infof("Service: Reception opened: %+v", reception)
+	logger.FromContext(ctx).Infof("Service: Reception opened: %+v", reception)
 	s.metrics.Inc()
 	return reception, nil
 }
 
-func (s *Service) CloseReception(ctx context.Context, pointID uuid.UUID) error {
-	logrus.Infof("Service: Closing reception for point: %s", pointID)
+func (s *Service) CloseReception(ctx context.Context, actorID uuid.UUID, pointID uuid.UUID) (entity.Reception, error) {
+	logger.FromContext(ctx).Infof("Service: Closing reception for point: %s", pointID)
+	var reception entity.Reception
 	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
 		// Point existence check
 		exists, err := s.receptionRepository.CheckIfPointExists(ctx, pointID)
 		if err != nil {
-			logrus.Errorf("Service: Failed to check if point exists for point %s: %v", pointID, err)
+			logger.FromContext(ctx).Errorf("Service: Failed to check if point exists for point %s: %v", pointID, err)
 			return err
 		}
 		if !exists {
-			logrus.Warnf("Service: Point does not exist: %s", pointID)
+			logger.FromContext(ctx).Warnf("Service: Point does not exist: %s", pointID)
 			return ErrNoPointFound
 		}
 
@@ -90,12 +103,12 @@ func (s *Service) CloseReception(ctx context.Context, pointID uuid.UUID) error {
 		status, err := s.receptionRepository.GetLastReceptionStatus(ctx, pointID)
 
 		if err != nil {
-			logrus.Errorf("Service: Failed to get last reception status for point %s: %v", pointID, err)
+			logger.FromContext(ctx).Errorf("Service: Failed to get last reception status for point %s: %v", pointID, err)
 			return err
 		}
 
 		if status == entity.ReceptionStatusClosed {
-			logrus.Warnf("Service: Last reception already closed for point: %s", pointID)
+			logger.FromContext(ctx).Warnf("Service: Last reception already closed for point: %s", pointID)
 			return ErrLastReceptionAlreadyClosed
 		}
 
@@ -103,27 +116,66 @@ func (s *Service) CloseReception(ctx context.Context, pointID uuid.UUID) error {
 		amount, err := s.receptionRepository.GetLastReceptionProductsAmount(ctx, pointID)
 
 		if err != nil {
-			logrus.Errorf("Service: Failed to get products amount for point %s: %v", pointID, err)
+			logger.FromContext(ctx).Errorf("Service: Failed to get products amount for point %s: %v", pointID, err)
 			return err
 		}
 
 		if amount == 0 {
-			logrus.Warnf("Service: Cannot close empty reception for point: %s", pointID)
+			logger.FromContext(ctx).Warnf("Service: Cannot close empty reception for point: %s", pointID)
 			return ErrCannotCloseEmptyReception
 		}
 
 		// Close
-		return s.receptionRepository.CloseLastReception(ctx, pointID)
+		reception, err = s.receptionRepository.CloseLastReception(ctx, pointID)
+		if err != nil {
+			return err
+		}
+
+		before := receptionSnapshotOf(reception)
+		before.Status = status
+		return s.audit(ctx, actorID, entity.AuditActionReceptionClosed, reception.ID, before, receptionSnapshotOf(reception))
 	})
 
 	if err != nil {
 		if errors.Is(err, repository.ErrNoReceptionFound) {
-			return ErrNoReceptionFound
+			return entity.Reception{}, ErrNoReceptionFound
+		}
+		logger.FromContext(ctx).Errorf("Service: Failed to close reception for point %s: %v", pointID, err)
+		return entity.Reception{}, err
+	}
+
+	logger.FromContext(ctx).Infof("Service: Reception closed for point: %s", pointID)
+	return reception, nil
+}
+
+func (s *Service) audit(ctx context.Context, actorID uuid.UUID, action entity.AuditAction, receptionID uuid.UUID, before any, after any) error {
+	record := entity.AuditRecord{
+		ActorID:    actorID,
+		Action:     action,
+		TargetType: entity.AuditTargetReception,
+		TargetID:   receptionID,
+	}
+
+	var err error
+	if before != nil {
+		if record.Before, err = json.Marshal(before); err != nil {
+			return err
 		}
-		logrus.Errorf("Service: Failed to close reception for point %s: %v", pointID, err)
+	}
+	if record.After, err = json.Marshal(after); err != nil {
 		return err
 	}
 
-	logrus.Infof("Service: Reception closed for point: %s", pointID)
-	return nil
+	if err = s.auditRepository.Create(ctx, record); err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to write audit record: %v", err)
+	}
+	return err
+}
+
+func receptionSnapshotOf(r entity.Reception) receptionSnapshot {
+	return receptionSnapshot{
+		PointID: r.PointID,
+		Kind:    r.Kind,
+		Status:  r.Status,
+	}
 }
@@ -18,6 +18,7 @@ import (
 func TestOpenReception(t *testing.T) {
 	var (
 		ctx          = context.Background()
+		actorID      = uuid.New()
 		pointID      = uuid.New()
 		arbitraryErr = errors.New("arbitraryErr")
 
@@ -28,10 +29,20 @@ func TestOpenReception(t *testing.T) {
 		ID:      uuid.New(),
 		PointID: pointID,
 		Status:  entity.ReceptionStatusInProgress,
+		Kind:    entity.ReceptionKindRegular,
+	}
+
+	record := entity.AuditRecord{
+		ActorID:    actorID,
+		Action:     entity.AuditActionReceptionOpened,
+		TargetType: entity.AuditTargetReception,
+		TargetID:   reception.ID,
+		After:      []byte(`{"point_id":"` + pointID.String() + `","kind":"regular","status":"in_progress"}`),
 	}
 
 	type MockBehavior func(
 		r *mock_reception.MockReceptionRepository,
+		a *mock_reception.MockAuditRepository,
 		t *mock_transactor.MockTransactor,
 		m *mock_reception.MockMetrics,
 	)
@@ -44,7 +55,7 @@ func TestOpenReception(t *testing.T) {
 	}{
 		{
 			name: "success",
-			mockBehavior: func(r *mock_reception.MockReceptionRepository, t *mock_transactor.MockTransactor, m *mock_reception.MockMetrics) {
+			mockBehavior: func(r *mock_reception.MockReceptionRepository, a *mock_reception.MockAuditRepository, t *mock_transactor.MockTransactor, m *mock_reception.MockMetrics) {
 				t.EXPECT().WithinTransaction(ctx, gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 						return fn(ctx)
@@ -52,7 +63,8 @@ func TestOpenReception(t *testing.T) {
 
 				r.EXPECT().CheckIfPointExists(ctx, pointID).Return(true, nil).Times(1)
 				r.EXPECT().GetLastReceptionStatus(ctx, pointID).Return(entity.ReceptionStatusClosed, nil).Times(1)
-				r.EXPECT().Open(ctx, pointID).Return(reception, nil).Times(1)
+				r.EXPECT().Open(ctx, pointID, entity.ReceptionKindRegular).Return(reception, nil).Times(1)
+				a.EXPECT().Create(ctx, record).Return(nil).Times(1)
 				m.EXPECT().Inc().Times(1)
 			},
 			want:    reception,
@@ -60,7 +72,7 @@ func TestOpenReception(t *testing.T) {
 		},
 		{
 			name: "failed to get status",
-			mockBehavior: func(r *mock_reception.MockReceptionRepository, t *mock_transactor.MockTransactor, m *mock_reception.MockMetrics) {
+			mockBehavior: func(r *mock_reception.MockReceptionRepository, a *mock_reception.MockAuditRepository, t *mock_transactor.MockTransactor, m *mock_reception.MockMetrics) {
 				t.EXPECT().WithinTransaction(ctx, gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 						return fn(ctx)
@@ -75,7 +87,7 @@ func TestOpenReception(t *testing.T) {
 		},
 		{
 			name: "last reception not closed",
-			mockBehavior: func(r *mock_reception.MockReceptionRepository, t *mock_transactor.MockTransactor, m *mock_reception.MockMetrics) {
+			mockBehavior: func(r *mock_reception.MockReceptionRepository, a *mock_reception.MockAuditRepository, t *mock_transactor.MockTransactor, m *mock_reception.MockMetrics) {
 				t.EXPECT().WithinTransaction(ctx, gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 						return fn(ctx)
@@ -89,7 +101,7 @@ func TestOpenReception(t *testing.T) {
 		},
 		{
 			name: "no point found",
-			mockBehavior: func(r *mock_reception.MockReceptionRepository, t *mock_transactor.MockTransactor, m *mock_reception.MockMetrics) {
+			mockBehavior: func(r *mock_reception.MockReceptionRepository, a *mock_reception.MockAuditRepository, t *mock_transactor.MockTransactor, m *mock_reception.MockMetrics) {
 				t.EXPECT().WithinTransaction(ctx, gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 						return fn(ctx)
@@ -102,7 +114,7 @@ func TestOpenReception(t *testing.T) {
 		},
 		{
 			name: "failed to check point existence",
-			mockBehavior: func(r *mock_reception.MockReceptionRepository, t *mock_transactor.MockTransactor, m *mock_reception.MockMetrics) {
+			mockBehavior: func(r *mock_reception.MockReceptionRepository, a *mock_reception.MockAuditRepository, t *mock_transactor.MockTransactor, m *mock_reception.MockMetrics) {
 				t.EXPECT().WithinTransaction(ctx, gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 						return fn(ctx)
@@ -116,7 +128,7 @@ func TestOpenReception(t *testing.T) {
 		},
 		{
 			name: "failed to open",
-			mockBehavior: func(r *mock_reception.MockReceptionRepository, t *mock_transactor.MockTransactor, m *mock_reception.MockMetrics) {
+			mockBehavior: func(r *mock_reception.MockReceptionRepository, a *mock_reception.MockAuditRepository, t *mock_transactor.MockTransactor, m *mock_reception.MockMetrics) {
 				t.EXPECT().WithinTransaction(ctx, gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 						return fn(ctx)
@@ -124,7 +136,24 @@ func TestOpenReception(t *testing.T) {
 
 				r.EXPECT().CheckIfPointExists(ctx, pointID).Return(true, nil).Times(1)
 				r.EXPECT().GetLastReceptionStatus(ctx, pointID).Return(entity.ReceptionStatusClosed, nil).Times(1)
-				r.EXPECT().Open(ctx, pointID).Return(entity.Reception{}, arbitraryErr).Times(1)
+				r.EXPECT().Open(ctx, pointID, entity.ReceptionKindRegular).Return(entity.Reception{}, arbitraryErr).Times(1)
+				m.EXPECT().ErrInc().Times(1)
+			},
+			want:    entity.Reception{},
+			wantErr: arbitraryErr,
+		},
+		{
+			name: "failed to write audit record",
+			mockBehavior: func(r *mock_reception.MockReceptionRepository, a *mock_reception.MockAuditRepository, t *mock_transactor.MockTransactor, m *mock_reception.MockMetrics) {
+				t.EXPECT().WithinTransaction(ctx, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+
+				r.EXPECT().CheckIfPointExists(ctx, pointID).Return(true, nil).Times(1)
+				r.EXPECT().GetLastReceptionStatus(ctx, pointID).Return(entity.ReceptionStatusClosed, nil).Times(1)
+				r.EXPECT().Open(ctx, pointID, entity.ReceptionKindRegular).Return(reception, nil).Times(1)
+				a.EXPECT().Create(ctx, record).Return(arbitraryErr).Times(1)
 				m.EXPECT().ErrInc().Times(1)
 			},
 			want:    entity.Reception{},
@@ -136,14 +165,15 @@ func TestOpenReception(t *testing.T) {
 			ctrl := gomock.NewController(t)
 
 			MockReceptionRepo := mock_reception.NewMockReceptionRepository(ctrl)
+			MockAuditRepo := mock_reception.NewMockAuditRepository(ctrl)
 			MockTransactor := mock_transactor.NewMockTransactor(ctrl)
 			MockMetrics := mock_reception.NewMockMetrics(ctrl)
 
-			tc.mockBehavior(MockReceptionRepo, MockTransactor, MockMetrics)
+			tc.mockBehavior(MockReceptionRepo, MockAuditRepo, MockTransactor, MockMetrics)
 
-			s := service.New(MockReceptionRepo, MockTransactor, MockMetrics)
+			s := service.New(MockReceptionRepo, MockAuditRepo, MockTransactor, MockMetrics)
 
-			out, err := s.OpenReception(ctx, pointID)
+			out, err := s.OpenReception(ctx, actorID, pointID, entity.ReceptionKindRegular)
 			assert.ErrorIs(t, err, tc.wantErr)
 			assert.Equal(t, tc.want, out)
 		})
@@ -153,6 +183,7 @@ func TestOpenReception(t *testing.T) {
 func TestCloseReception(t *testing.T) {
 	var (
 		ctx                 = context.Background()
+		actorID             = uuid.New()
 		pointID             = uuid.New()
 		arbitraryErr        = errors.New("arbitraryErr")
 		productsAmount      = 3
@@ -161,19 +192,37 @@ func TestCloseReception(t *testing.T) {
 		emptyStatus entity.ReceptionStatus = ""
 	)
 
+	reception := entity.Reception{
+		ID:      uuid.New(),
+		PointID: pointID,
+		Status:  entity.ReceptionStatusClosed,
+		Kind:    entity.ReceptionKindRegular,
+	}
+
+	record := entity.AuditRecord{
+		ActorID:    actorID,
+		Action:     entity.AuditActionReceptionClosed,
+		TargetType: entity.AuditTargetReception,
+		TargetID:   reception.ID,
+		Before:     []byte(`{"point_id":"` + pointID.String() + `","kind":"regular","status":"in_progress"}`),
+		After:      []byte(`{"point_id":"` + pointID.String() + `","kind":"regular","status":"close"}`),
+	}
+
 	type MockBehavior func(
 		r *mock_reception.MockReceptionRepository,
+		a *mock_reception.MockAuditRepository,
 		t *mock_transactor.MockTransactor,
 	)
 
 	for _, tc := range []struct {
 		name         string
 		mockBehavior MockBehavior
+		want         entity.Reception
 		wantErr      error
 	}{
 		{
 			name: "success",
-			mockBehavior: func(r *mock_reception.MockReceptionRepository, t *mock_transactor.MockTransactor) {
+			mockBehavior: func(r *mock_reception.MockReceptionRepository, a *mock_reception.MockAuditRepository, t *mock_transactor.MockTransactor) {
 				t.EXPECT().WithinTransaction(ctx, gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 						return fn(ctx)
@@ -182,13 +231,15 @@ func TestCloseReception(t *testing.T) {
 				r.EXPECT().CheckIfPointExists(ctx, pointID).Return(true, nil).Times(1)
 				r.EXPECT().GetLastReceptionStatus(ctx, pointID).Return(entity.ReceptionStatusInProgress, nil).Times(1)
 				r.EXPECT().GetLastReceptionProductsAmount(ctx, pointID).Return(productsAmount, nil)
-				r.EXPECT().CloseLastReception(ctx, pointID).Return(nil).Times(1)
+				r.EXPECT().CloseLastReception(ctx, pointID).Return(reception, nil).Times(1)
+				a.EXPECT().Create(ctx, record).Return(nil).Times(1)
 			},
+			want:    reception,
 			wantErr: nil,
 		},
 		{
 			name: "failed to get status",
-			mockBehavior: func(r *mock_reception.MockReceptionRepository, t *mock_transactor.MockTransactor) {
+			mockBehavior: func(r *mock_reception.MockReceptionRepository, a *mock_reception.MockAuditRepository, t *mock_transactor.MockTransactor) {
 				t.EXPECT().WithinTransaction(ctx, gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 						return fn(ctx)
@@ -201,7 +252,7 @@ func TestCloseReception(t *testing.T) {
 		},
 		{
 			name: "last reception is closed",
-			mockBehavior: func(r *mock_reception.MockReceptionRepository, t *mock_transactor.MockTransactor) {
+			mockBehavior: func(r *mock_reception.MockReceptionRepository, a *mock_reception.MockAuditRepository, t *mock_transactor.MockTransactor) {
 				t.EXPECT().WithinTransaction(ctx, gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 						return fn(ctx)
@@ -214,7 +265,7 @@ func TestCloseReception(t *testing.T) {
 		},
 		{
 			name: "cannot close empty reception",
-			mockBehavior: func(r *mock_reception.MockReceptionRepository, t *mock_transactor.MockTransactor) {
+			mockBehavior: func(r *mock_reception.MockReceptionRepository, a *mock_reception.MockAuditRepository, t *mock_transactor.MockTransactor) {
 				t.EXPECT().WithinTransaction(ctx, gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 						return fn(ctx)
@@ -228,7 +279,7 @@ func TestCloseReception(t *testing.T) {
 		},
 		{
 			name: "failed to check products amount",
-			mockBehavior: func(r *mock_reception.MockReceptionRepository, t *mock_transactor.MockTransactor) {
+			mockBehavior: func(r *mock_reception.MockReceptionRepository, a *mock_reception.MockAuditRepository, t *mock_transactor.MockTransactor) {
 				t.EXPECT().WithinTransaction(ctx, gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 						return fn(ctx)
@@ -242,7 +293,7 @@ func TestCloseReception(t *testing.T) {
 		},
 		{
 			name: "no point found",
-			mockBehavior: func(r *mock_reception.MockReceptionRepository, t *mock_transactor.MockTransactor) {
+			mockBehavior: func(r *mock_reception.MockReceptionRepository, a *mock_reception.MockAuditRepository, t *mock_transactor.MockTransactor) {
 				t.EXPECT().WithinTransaction(ctx, gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 						return fn(ctx)
@@ -254,7 +305,7 @@ func TestCloseReception(t *testing.T) {
 		},
 		{
 			name: "failed to check point existence",
-			mockBehavior: func(r *mock_reception.MockReceptionRepository, t *mock_transactor.MockTransactor) {
+			mockBehavior: func(r *mock_reception.MockReceptionRepository, a *mock_reception.MockAuditRepository, t *mock_transactor.MockTransactor) {
 				t.EXPECT().WithinTransaction(ctx, gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 						return fn(ctx)
@@ -266,7 +317,7 @@ func TestCloseReception(t *testing.T) {
 		},
 		{
 			name: "failed to close",
-			mockBehavior: func(r *mock_reception.MockReceptionRepository, t *mock_transactor.MockTransactor) {
+			mockBehavior: func(r *mock_reception.MockReceptionRepository, a *mock_reception.MockAuditRepository, t *mock_transactor.MockTransactor) {
 				t.EXPECT().WithinTransaction(ctx, gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 						return fn(ctx)
@@ -275,13 +326,13 @@ func TestCloseReception(t *testing.T) {
 				r.EXPECT().CheckIfPointExists(ctx, pointID).Return(true, nil).Times(1)
 				r.EXPECT().GetLastReceptionStatus(ctx, pointID).Return(entity.ReceptionStatusInProgress, nil).Times(1)
 				r.EXPECT().GetLastReceptionProductsAmount(ctx, pointID).Return(productsAmount, nil)
-				r.EXPECT().CloseLastReception(ctx, pointID).Return(arbitraryErr).Times(1)
+				r.EXPECT().CloseLastReception(ctx, pointID).Return(entity.Reception{}, arbitraryErr).Times(1)
 			},
 			wantErr: arbitraryErr,
 		},
 		{
 			name: "no reception to close found",
-			mockBehavior: func(r *mock_reception.MockReceptionRepository, t *mock_transactor.MockTransactor) {
+			mockBehavior: func(r *mock_reception.MockReceptionRepository, a *mock_reception.MockAuditRepository, t *mock_transactor.MockTransactor) {
 				t.EXPECT().WithinTransaction(ctx, gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 						return fn(ctx)
@@ -290,25 +341,43 @@ func TestCloseReception(t *testing.T) {
 				r.EXPECT().CheckIfPointExists(ctx, pointID).Return(true, nil).Times(1)
 				r.EXPECT().GetLastReceptionStatus(ctx, pointID).Return(entity.ReceptionStatusInProgress, nil).Times(1)
 				r.EXPECT().GetLastReceptionProductsAmount(ctx, pointID).Return(productsAmount, nil)
-				r.EXPECT().CloseLastReception(ctx, pointID).Return(repository.ErrNoReceptionFound).Times(1)
+				r.EXPECT().CloseLastReception(ctx, pointID).Return(entity.Reception{}, repository.ErrNoReceptionFound).Times(1)
 			},
 			wantErr: service.ErrNoReceptionFound,
 		},
+		{
+			name: "failed to write audit record",
+			mockBehavior: func(r *mock_reception.MockReceptionRepository, a *mock_reception.MockAuditRepository, t *mock_transactor.MockTransactor) {
+				t.EXPECT().WithinTransaction(ctx, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+
+				r.EXPECT().CheckIfPointExists(ctx, pointID).Return(true, nil).Times(1)
+				r.EXPECT().GetLastReceptionStatus(ctx, pointID).Return(entity.ReceptionStatusInProgress, nil).Times(1)
+				r.EXPECT().GetLastReceptionProductsAmount(ctx, pointID).Return(productsAmount, nil)
+				r.EXPECT().CloseLastReception(ctx, pointID).Return(reception, nil).Times(1)
+				a.EXPECT().Create(ctx, record).Return(arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 			ctrl := gomock.NewController(t)
 
 			MockReceptionRepo := mock_reception.NewMockReceptionRepository(ctrl)
+			MockAuditRepo := mock_reception.NewMockAuditRepository(ctrl)
 			MockTransactor := mock_transactor.NewMockTransactor(ctrl)
 			MockMetrics := mock_reception.NewMockMetrics(ctrl)
 
-			tc.mockBehavior(MockReceptionRepo, MockTransactor)
+			tc.mockBehavior(MockReceptionRepo, MockAuditRepo, MockTransactor)
 
-			s := service.New(MockReceptionRepo, MockTransactor, MockMetrics)
+			s := service.New(MockReceptionRepo, MockAuditRepo, MockTransactor, MockMetrics)
 
-			err := s.CloseReception(ctx, pointID)
+			out, err := s.CloseReception(ctx, actorID, pointID)
 			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.want, out)
 		})
 	}
 }
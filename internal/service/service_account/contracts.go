@@ -0,0 +1,31 @@
+package service_account
+
+import (
+	"context"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/google/uuid"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mocks.go -package=mocks
+
+type ServiceAccountRepository interface {
+	Create(ctx context.Context, account entity.ServiceAccount) (entity.ServiceAccount, error)
+	GetByID(ctx context.Context, accountID uuid.UUID) (entity.ServiceAccount, error)
+	GetAll(ctx context.Context) ([]entity.ServiceAccount, error)
+}
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key entity.APIKey) (entity.APIKey, error)
+	GetByIDForUpdate(ctx context.Context, keyID uuid.UUID) (entity.APIKey, error)
+	GetByHash(ctx context.Context, keyHash string) (entity.APIKey, error)
+	GetByServiceAccounts(ctx context.Context, accountIDs []uuid.UUID) ([]entity.APIKey, error)
+	SetExpiresAt(ctx context.Context, keyID uuid.UUID, expiresAt time.Time) error
+	Revoke(ctx context.Context, keyID uuid.UUID) error
+	TouchLastUsed(ctx context.Context, keyID uuid.UUID, at time.Time, since time.Time) error
+}
+
+type AuditRepository interface {
+	Create(ctx context.Context, record entity.AuditRecord) error
+}
@@ -0,0 +1,14 @@
+package service_account
+
+import "errors"
+
+var (
+	ErrNoServiceAccountFound       = errors.New("no service account found")
+	ErrServiceAccountAlreadyExists = errors.New("service account already exists")
+	ErrNoAPIKeyFound               = errors.New("no api key found")
+	ErrAPIKeyInactive              = errors.New("api key is revoked or expired")
+	ErrUnknownScope                = errors.New("unknown scope")
+	ErrScopeNotGrantable           = errors.New("scope cannot be granted to an api key")
+	ErrInvalidExpiry               = errors.New("expiry must be in the future")
+	ErrInvalidAPIKey               = errors.New("invalid api key")
+)
@@ -0,0 +1,401 @@
+package service_account
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/repository"
+	"github.com/4udiwe/avito-pvz/pkg/hasher"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/4udiwe/avito-pvz/pkg/transactor"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+const (
+	// keyPrefix marks API keys so they are easy to recognize in leaks.
+	keyPrefix = "pvz_"
+	// keyBytes gives 256 bit API keys.
+	keyBytes = 32
+	// displayPrefixLength is how much of a key is kept to identify it.
+	displayPrefixLength = 12
+	// lastUsedGranularity limits last_used_at writes for busy keys.
+	lastUsedGranularity = time.Minute
+)
+
+// grantableScopes are the permissions an API key may carry: day to day
+// operations of the points. Administration of users and service accounts
+// stays with people, and its records reference the acting user.
+var grantableScopes = map[entity.Permission]struct{}{
+	entity.PermissionPointCreate:     {},
+	entity.PermissionPointRead:       {},
+	entity.PermissionCellManage:      {},
+	entity.PermissionReceptionOpen:   {},
+	entity.PermissionReceptionClose:  {},
+	entity.PermissionProductAdd:      {},
+	entity.PermissionProductDelete:   {},
+	entity.PermissionProductMove:     {},
+	entity.PermissionProductPlace:    {},
+	entity.PermissionProductWriteOff: {},
+	entity.PermissionProductRead:     {},
+	entity.PermissionOrderManage:     {},
+	entity.PermissionOrderRead:       {},
+	entity.PermissionTransferManage:  {},
+	entity.PermissionTransferRead:    {},
+}
+
+func grantable(scope entity.Permission) bool {
+	_, ok := grantableScopes[scope]
+	return ok
+}
+
+// Policy controls API key lifetimes. A key without an explicit expiry lives
+// DefaultTTL; a rotated key keeps working for RotationGrace so the partner
+// can switch over.
+type Policy struct {
+	DefaultTTL    time.Duration
+	RotationGrace time.Duration
+}
+
+// apiKeySnapshot is what the audit log keeps of a key. The key itself is
+// never logged.
+type apiKeySnapshot struct {
+	ServiceAccountID uuid.UUID           `json:"service_account_id"`
+	Prefix           string              `json:"prefix"`
+	Scopes           []entity.Permission `json:"scopes"`
+	ExpiresAt        time.Time           `json:"expires_at"`
+	Revoked          bool                `json:"revoked"`
+}
+
+type Service struct {
+	accountRepository ServiceAccountRepository
+	keyRepository     APIKeyRepository
+	auditRepository   AuditRepository
+	txManager         transactor.Transactor
+	policy            Policy
+}
+
+func New(sa ServiceAccountRepository, k APIKeyRepository, a AuditRepository, tx transactor.Transactor, policy Policy) *Service {
+	return &Service{
+		accountRepository: sa,
+		keyRepository:     k,
+		auditRepository:   a,
+		txManager:         tx,
+		policy:            policy,
+	}
+}
+
+func (s *Service) CreateServiceAccount(ctx context.Context, actorID uuid.UUID, name string, description string) (entity.ServiceAccount, error) {
+	logger.FromContext(ctx).Infof("Service: User %s creates service account %s", actorID, name)
+
+	var out entity.ServiceAccount
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		out, err = s.accountRepository.Create(ctx, entity.ServiceAccount{
+			Name:        name,
+			Description: description,
+			CreatedBy:   actorID,
+		})
+		if err != nil {
+			if errors.Is(err, repository.ErrServiceAccountAlreadyExists) {
+				return ErrServiceAccountAlreadyExists
+			}
+			logger.FromContext(ctx).Errorf("Service: Failed to create service account: %v", err)
+			return err
+		}
+
+		return s.audit(ctx, actorID, entity.AuditActionServiceAccountCreated, entity.AuditTargetServiceAccount, out.ID, nil,
+			map[string]string{"name": out.Name, "description": out.Description})
+	})
+
+	if err != nil {
+		return entity.ServiceAccount{}, err
+	}
+
+	logger.FromContext(ctx).Infof("Service: Service account created: %s", out.ID)
+	return out, nil
+}
+
+// ListServiceAccounts returns all service accounts with their keys.
+func (s *Service) ListServiceAccounts(ctx context.Context) ([]entity.ServiceAccount, error) {
+	logger.FromContext(ctx).Info("Service: Fetching service accounts")
+
+	accounts, err := s.accountRepository.GetAll(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to fetch service accounts: %v", err)
+		return nil, err
+	}
+	if len(accounts) == 0 {
+		return accounts, nil
+	}
+
+	keys, err := s.keyRepository.GetByServiceAccounts(ctx, lo.Map(accounts, func(a entity.ServiceAccount, _ int) uuid.UUID {
+		return a.ID
+	}))
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to fetch api keys: %v", err)
+		return nil, err
+	}
+
+	byAccount := lo.GroupBy(keys, func(k entity.APIKey) uuid.UUID { return k.ServiceAccountID })
+	for i := range accounts {
+		accounts[i].Keys = byAccount[accounts[i].ID]
+	}
+
+	logger.FromContext(ctx).Infof("Service: Fetched %d service accounts", len(accounts))
+	return accounts, nil
+}
+
+// CreateAPIKey issues a key for the service account. The key is returned
+// once; only its hash is stored. Without expiresAt the key lives
+// Policy.DefaultTTL.
+func (s *Service) CreateAPIKey(ctx context.Context, actorID uuid.UUID, accountID uuid.UUID, scopes []entity.Permission, expiresAt *time.Time) (entity.APIKey, string, error) {
+	logger.FromContext(ctx).Infof("Service: User %s creates api key for service account %s", actorID, accountID)
+
+	if denied, ok := lo.Find(scopes, func(scope entity.Permission) bool { return !grantable(scope) }); ok {
+		logger.FromContext(ctx).Warnf("Service: Scope %s cannot be granted to an api key", denied)
+		return entity.APIKey{}, "", ErrScopeNotGrantable
+	}
+
+	expiry := lo.FromPtrOr(expiresAt, time.Now().Add(s.policy.DefaultTTL))
+	if !expiry.After(time.Now()) {
+		return entity.APIKey{}, "", ErrInvalidExpiry
+	}
+
+	var (
+		out    entity.APIKey
+		secret string
+	)
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.getServiceAccount(ctx, accountID); err != nil {
+			return err
+		}
+
+		var err error
+		out, secret, err = s.issueKey(ctx, actorID, accountID, lo.Uniq(scopes), expiry)
+		if err != nil {
+			return err
+		}
+
+		return s.audit(ctx, actorID, entity.AuditActionAPIKeyCreated, entity.AuditTargetAPIKey, out.ID, nil, keySnapshot(out))
+	})
+
+	if err != nil {
+		return entity.APIKey{}, "", err
+	}
+
+	logger.FromContext(ctx).Infof("Service: Api key %s created", out.ID)
+	return out, secret, nil
+}
+
+// RotateAPIKey issues a new key with the same scopes and limits the old one
+// to Policy.RotationGrace.
+func (s *Service) RotateAPIKey(ctx context.Context, actorID uuid.UUID, accountID uuid.UUID, keyID uuid.UUID) (entity.APIKey, string, error) {
+	logger.FromContext(ctx).Infof("Service: User %s rotates api key %s", actorID, keyID)
+
+	var (
+		out    entity.APIKey
+		secret string
+	)
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		old, err := s.getKeyForUpdate(ctx, accountID, keyID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if !old.Active(now) {
+			return ErrAPIKeyInactive
+		}
+
+		out, secret, err = s.issueKey(ctx, actorID, accountID, old.Scopes, now.Add(s.policy.DefaultTTL))
+		if err != nil {
+			return err
+		}
+
+		before := keySnapshot(old)
+		if graceEnd := now.Add(s.policy.RotationGrace); graceEnd.Before(old.ExpiresAt) {
+			if err = s.keyRepository.SetExpiresAt(ctx, old.ID, graceEnd); err != nil {
+				logger.FromContext(ctx).Errorf("Service: Failed to shorten rotated api key: %v", err)
+				return err
+			}
+			old.ExpiresAt = graceEnd
+		}
+
+		return s.audit(ctx, actorID, entity.AuditActionAPIKeyRotated, entity.AuditTargetAPIKey, old.ID, before,
+			map[string]any{"expires_at": old.ExpiresAt, "replaced_by": out.ID})
+	})
+
+	if err != nil {
+		return entity.APIKey{}, "", err
+	}
+
+	logger.FromContext(ctx).Infof("Service: Api key %s rotated to %s", keyID, out.ID)
+	return out, secret, nil
+}
+
+func (s *Service) RevokeAPIKey(ctx context.Context, actorID uuid.UUID, accountID uuid.UUID, keyID uuid.UUID) error {
+	logger.FromContext(ctx).Infof("Service: User %s revokes api key %s", actorID, keyID)
+
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		key, err := s.getKeyForUpdate(ctx, accountID, keyID)
+		if err != nil {
+			return err
+		}
+		if key.RevokedAt != nil {
+			return nil
+		}
+
+		if err = s.keyRepository.Revoke(ctx, keyID); err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to revoke api key: %v", err)
+			return err
+		}
+
+		before := keySnapshot(key)
+		after := before
+		after.Revoked = true
+		return s.audit(ctx, actorID, entity.AuditActionAPIKeyRevoked, entity.AuditTargetAPIKey, keyID, before, after)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx).Infof("Service: Api key %s revoked", keyID)
+	return nil
+}
+
+// AuthenticateAPIKey resolves a key presented by a partner system into
+// claims of its service account.
+func (s *Service) AuthenticateAPIKey(ctx context.Context, secret string) (*auth.TokenClaims, error) {
+	key, err := s.keyRepository.GetByHash(ctx, hasher.HashToken(secret))
+	if err != nil {
+		if errors.Is(err, repository.ErrNoAPIKeyFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		logger.FromContext(ctx).Errorf("Service: Failed to get api key: %v", err)
+		return nil, err
+	}
+
+	now := time.Now()
+	if !key.Active(now) {
+		logger.FromContext(ctx).Warnf("Service: Api key %s is revoked or expired", key.ID)
+		return nil, ErrInvalidAPIKey
+	}
+
+	if err = s.keyRepository.TouchLastUsed(ctx, key.ID, now, now.Add(-lastUsedGranularity)); err != nil {
+		logger.FromContext(ctx).Warnf("Service: Failed to record use of api key %s: %v", key.ID, err)
+	}
+
+	return &auth.TokenClaims{
+		UserID:   key.ServiceAccountID,
+		APIKeyID: key.ID,
+		// Keys issued before the allow-list may hold more
+		Scopes: lo.Filter(key.Scopes, func(scope entity.Permission, _ int) bool { return grantable(scope) }),
+	}, nil
+}
+
+// issueKey generates and stores a key. Must be called within a transaction.
+func (s *Service) issueKey(ctx context.Context, actorID uuid.UUID, accountID uuid.UUID, scopes []entity.Permission, expiresAt time.Time) (entity.APIKey, string, error) {
+	secret, err := generateKey()
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to generate api key: %v", err)
+		return entity.APIKey{}, "", err
+	}
+
+	key, err := s.keyRepository.Create(ctx, entity.APIKey{
+		ServiceAccountID: accountID,
+		Prefix:           secret[:displayPrefixLength],
+		KeyHash:          hasher.HashToken(secret),
+		Scopes:           scopes,
+		CreatedBy:        actorID,
+		ExpiresAt:        expiresAt,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNoPermissionFound):
+			return entity.APIKey{}, "", ErrUnknownScope
+		case errors.Is(err, repository.ErrNoServiceAccountFound):
+			return entity.APIKey{}, "", ErrNoServiceAccountFound
+		}
+		logger.FromContext(ctx).Errorf("Service: Failed to create api key: %v", err)
+		return entity.APIKey{}, "", err
+	}
+	return key, secret, nil
+}
+
+func (s *Service) getServiceAccount(ctx context.Context, accountID uuid.UUID) (entity.ServiceAccount, error) {
+	account, err := s.accountRepository.GetByID(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNoServiceAccountFound) {
+			return entity.ServiceAccount{}, ErrNoServiceAccountFound
+		}
+		logger.FromContext(ctx).Errorf("Service: Failed to get service account: %v", err)
+		return entity.ServiceAccount{}, err
+	}
+	return account, nil
+}
+
+// getKeyForUpdate locks the key and checks that it belongs to the account.
+func (s *Service) getKeyForUpdate(ctx context.Context, accountID uuid.UUID, keyID uuid.UUID) (entity.APIKey, error) {
+	key, err := s.keyRepository.GetByIDForUpdate(ctx, keyID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNoAPIKeyFound) {
+			return entity.APIKey{}, ErrNoAPIKeyFound
+		}
+		logger.FromContext(ctx).Errorf("Service: Failed to get api key: %v", err)
+		return entity.APIKey{}, err
+	}
+	if key.ServiceAccountID != accountID {
+		return entity.APIKey{}, ErrNoAPIKeyFound
+	}
+	return key, nil
+}
+
+func (s *Service) audit(ctx context.Context, actorID uuid.UUID, action entity.AuditAction, target entity.AuditTarget, targetID uuid.UUID, before any, after any) error {
+	record := entity.AuditRecord{
+		ActorID:    actorID,
+		Action:     action,
+		TargetType: target,
+		TargetID:   targetID,
+	}
+
+	var err error
+	if before != nil {
+		if record.Before, err = json.Marshal(before); err != nil {
+			return err
+		}
+	}
+	if record.After, err = json.Marshal(after); err != nil {
+		return err
+	}
+
+	if err = s.auditRepository.Create(ctx, record); err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to write audit record: %v", err)
+	}
+	return err
+}
+
+func keySnapshot(k entity.APIKey) apiKeySnapshot {
+	return apiKeySnapshot{
+		ServiceAccountID: k.ServiceAccountID,
+		Prefix:           k.Prefix,
+		Scopes:           k.Scopes,
+		ExpiresAt:        k.ExpiresAt,
+		Revoked:          k.RevokedAt != nil,
+	}
+}
+
+func generateKey() (string, error) {
+	b := make([]byte, keyBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return keyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,609 @@
+package service_account_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	mock_transactor "github.com/4udiwe/avito-pvz/internal/mocks"
+	"github.com/4udiwe/avito-pvz/internal/repository"
+	service "github.com/4udiwe/avito-pvz/internal/service/service_account"
+	"github.com/4udiwe/avito-pvz/internal/service/service_account/mocks"
+	"github.com/4udiwe/avito-pvz/pkg/hasher"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var policy = service.Policy{
+	DefaultTTL:    90 * 24 * time.Hour,
+	RotationGrace: 24 * time.Hour,
+}
+
+type serviceMocks struct {
+	accounts *mocks.MockServiceAccountRepository
+	keys     *mocks.MockAPIKeyRepository
+	audit    *mocks.MockAuditRepository
+	tx       *mock_transactor.MockTransactor
+}
+
+func newService(ctrl *gomock.Controller) (*service.Service, serviceMocks) {
+	m := serviceMocks{
+		accounts: mocks.NewMockServiceAccountRepository(ctrl),
+		keys:     mocks.NewMockAPIKeyRepository(ctrl),
+		audit:    mocks.NewMockAuditRepository(ctrl),
+		tx:       mock_transactor.NewMockTransactor(ctrl),
+	}
+	return service.New(m.accounts, m.keys, m.audit, m.tx, policy), m
+}
+
+func withinTx(ctx context.Context, tx *mock_transactor.MockTransactor) {
+	tx.EXPECT().WithinTransaction(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+}
+
+// auditRecord matches an audit record of the given action on the key.
+func auditRecord(actorID uuid.UUID, action entity.AuditAction, targetID uuid.UUID, afterContains string) gomock.Matcher {
+	return gomock.Cond(func(r entity.AuditRecord) bool {
+		return r.ActorID == actorID &&
+			r.Action == action &&
+			r.TargetID == targetID &&
+			strings.Contains(string(r.After), afterContains)
+	})
+}
+
+func TestCreateServiceAccount(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		arbitraryErr = errors.New("arbitrary error")
+		actorID      = uuid.New()
+		accountID    = uuid.New()
+		name         = "erp"
+		description  = "ERP integration"
+	)
+
+	toCreate := entity.ServiceAccount{Name: name, Description: description, CreatedBy: actorID}
+	created := entity.ServiceAccount{ID: accountID, Name: name, Description: description, CreatedBy: actorID}
+
+	type MockBehavior func(m serviceMocks)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		want         entity.ServiceAccount
+		wantErr      error
+	}{
+		{
+			name: "success",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.accounts.EXPECT().Create(ctx, toCreate).Return(created, nil).Times(1)
+				m.audit.EXPECT().Create(ctx, auditRecord(actorID, entity.AuditActionServiceAccountCreated, accountID, `"name":"erp"`)).
+					Return(nil).Times(1)
+			},
+			want:    created,
+			wantErr: nil,
+		},
+		{
+			name: "already exists",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.accounts.EXPECT().Create(ctx, toCreate).Return(entity.ServiceAccount{}, repository.ErrServiceAccountAlreadyExists).Times(1)
+			},
+			want:    entity.ServiceAccount{},
+			wantErr: service.ErrServiceAccountAlreadyExists,
+		},
+		{
+			name: "audit error",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.accounts.EXPECT().Create(ctx, toCreate).Return(created, nil).Times(1)
+				m.audit.EXPECT().Create(ctx, gomock.Any()).Return(arbitraryErr).Times(1)
+			},
+			want:    entity.ServiceAccount{},
+			wantErr: arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s, m := newService(ctrl)
+			tc.mockBehavior(m)
+
+			out, err := s.CreateServiceAccount(ctx, actorID, name, description)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.want, out)
+		})
+	}
+}
+
+func TestListServiceAccounts(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		arbitraryErr = errors.New("arbitrary error")
+		first        = entity.ServiceAccount{ID: uuid.New(), Name: "erp"}
+		second       = entity.ServiceAccount{ID: uuid.New(), Name: "courier"}
+		key          = entity.APIKey{ID: uuid.New(), ServiceAccountID: first.ID, Prefix: "pvz_abcdefgh"}
+	)
+
+	withKeys := first
+	withKeys.Keys = []entity.APIKey{key}
+
+	type MockBehavior func(m serviceMocks)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		want         []entity.ServiceAccount
+		wantErr      error
+	}{
+		{
+			name: "success",
+			mockBehavior: func(m serviceMocks) {
+				m.accounts.EXPECT().GetAll(ctx).Return([]entity.ServiceAccount{first, second}, nil).Times(1)
+				m.keys.EXPECT().GetByServiceAccounts(ctx, []uuid.UUID{first.ID, second.ID}).Return([]entity.APIKey{key}, nil).Times(1)
+			},
+			want:    []entity.ServiceAccount{withKeys, second},
+			wantErr: nil,
+		},
+		{
+			name: "no accounts",
+			mockBehavior: func(m serviceMocks) {
+				m.accounts.EXPECT().GetAll(ctx).Return([]entity.ServiceAccount{}, nil).Times(1)
+			},
+			want:    []entity.ServiceAccount{},
+			wantErr: nil,
+		},
+		{
+			name: "keys error",
+			mockBehavior: func(m serviceMocks) {
+				m.accounts.EXPECT().GetAll(ctx).Return([]entity.ServiceAccount{first}, nil).Times(1)
+				m.keys.EXPECT().GetByServiceAccounts(ctx, []uuid.UUID{first.ID}).Return(nil, arbitraryErr).Times(1)
+			},
+			want:    nil,
+			wantErr: arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s, m := newService(ctrl)
+			tc.mockBehavior(m)
+
+			out, err := s.ListServiceAccounts(ctx)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.want, out)
+		})
+	}
+}
+
+func TestCreateAPIKey(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		arbitraryErr = errors.New("arbitrary error")
+		actorID      = uuid.New()
+		accountID    = uuid.New()
+		keyID        = uuid.New()
+		scopes       = []entity.Permission{entity.PermissionPointRead, entity.PermissionPointRead, entity.PermissionProductRead}
+		past         = time.Now().Add(-time.Hour)
+	)
+
+	newKey := gomock.Cond(func(k entity.APIKey) bool {
+		return k.ServiceAccountID == accountID &&
+			k.CreatedBy == actorID &&
+			strings.HasPrefix(k.Prefix, "pvz_") &&
+			len(k.KeyHash) == 64 &&
+			assert.ObjectsAreEqual([]entity.Permission{entity.PermissionPointRead, entity.PermissionProductRead}, k.Scopes) &&
+			time.Until(k.ExpiresAt) > policy.DefaultTTL-time.Minute
+	})
+	created := func(_ context.Context, k entity.APIKey) (entity.APIKey, error) {
+		k.ID = keyID
+		return k, nil
+	}
+
+	type MockBehavior func(m serviceMocks)
+
+	for _, tc := range []struct {
+		name         string
+		expiresAt    *time.Time
+		mockBehavior MockBehavior
+		wantErr      error
+	}{
+		{
+			name: "success",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.accounts.EXPECT().GetByID(ctx, accountID).Return(entity.ServiceAccount{ID: accountID}, nil).Times(1)
+				m.keys.EXPECT().Create(ctx, newKey).DoAndReturn(created).Times(1)
+				m.audit.EXPECT().Create(ctx, auditRecord(actorID, entity.AuditActionAPIKeyCreated, keyID, `"scopes":["point:read","product:read"]`)).
+					Return(nil).Times(1)
+			},
+			wantErr: nil,
+		},
+		{
+			name:         "expiry in the past",
+			expiresAt:    &past,
+			mockBehavior: func(m serviceMocks) {},
+			wantErr:      service.ErrInvalidExpiry,
+		},
+		{
+			name: "no service account",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.accounts.EXPECT().GetByID(ctx, accountID).Return(entity.ServiceAccount{}, repository.ErrNoServiceAccountFound).Times(1)
+			},
+			wantErr: service.ErrNoServiceAccountFound,
+		},
+		{
+			name: "unknown scope",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.accounts.EXPECT().GetByID(ctx, accountID).Return(entity.ServiceAccount{ID: accountID}, nil).Times(1)
+				m.keys.EXPECT().Create(ctx, newKey).Return(entity.APIKey{}, repository.ErrNoPermissionFound).Times(1)
+			},
+			wantErr: service.ErrUnknownScope,
+		},
+		{
+			name: "create error",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.accounts.EXPECT().GetByID(ctx, accountID).Return(entity.ServiceAccount{ID: accountID}, nil).Times(1)
+				m.keys.EXPECT().Create(ctx, newKey).Return(entity.APIKey{}, arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s, m := newService(ctrl)
+			tc.mockBehavior(m)
+
+			out, key, err := s.CreateAPIKey(ctx, actorID, accountID, scopes, tc.expiresAt)
+			assert.ErrorIs(t, err, tc.wantErr)
+			if tc.wantErr == nil {
+				assert.Equal(t, keyID, out.ID)
+				assert.Equal(t, hasher.HashToken(key), out.KeyHash)
+				assert.True(t, strings.HasPrefix(key, out.Prefix))
+			} else {
+				assert.Empty(t, key)
+			}
+		})
+	}
+}
+
+func TestCreateAPIKeyScopeNotGrantable(t *testing.T) {
+	ctx := context.Background()
+
+	for _, scope := range []entity.Permission{
+		entity.PermissionServiceAccountManage,
+		entity.PermissionUserManage,
+		entity.PermissionUserInvite,
+		entity.PermissionUserRead,
+		entity.PermissionAuditRead,
+		"unknown:scope",
+	} {
+		t.Run(string(scope), func(t *testing.T) {
+			t.Parallel()
+			s, _ := newService(gomock.NewController(t))
+
+			out, key, err := s.CreateAPIKey(ctx, uuid.New(), uuid.New(), []entity.Permission{entity.PermissionPointRead, scope}, nil)
+			assert.ErrorIs(t, err, service.ErrScopeNotGrantable)
+			assert.Empty(t, out)
+			assert.Empty(t, key)
+		})
+	}
+}
+
+func TestRotateAPIKey(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		arbitraryErr = errors.New("arbitrary error")
+		actorID      = uuid.New()
+		accountID    = uuid.New()
+		oldID        = uuid.New()
+		newID        = uuid.New()
+		scopes       = []entity.Permission{entity.PermissionOrderRead}
+		revokedAt    = time.Now().Add(-time.Hour)
+		oldKey       = entity.APIKey{
+			ID:               oldID,
+			ServiceAccountID: accountID,
+			Prefix:           "pvz_oldoldol",
+			Scopes:           scopes,
+			ExpiresAt:        time.Now().Add(30 * 24 * time.Hour),
+		}
+	)
+
+	revokedKey := oldKey
+	revokedKey.RevokedAt = &revokedAt
+
+	soonExpiring := oldKey
+	soonExpiring.ExpiresAt = time.Now().Add(time.Hour)
+
+	newKey := gomock.Cond(func(k entity.APIKey) bool {
+		return k.ServiceAccountID == accountID && assert.ObjectsAreEqual(scopes, k.Scopes)
+	})
+	created := func(_ context.Context, k entity.APIKey) (entity.APIKey, error) {
+		k.ID = newID
+		return k, nil
+	}
+	graceEnd := gomock.Cond(func(at time.Time) bool {
+		return at.Sub(time.Now().Add(policy.RotationGrace)).Abs() < time.Minute
+	})
+
+	type MockBehavior func(m serviceMocks)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		wantErr      error
+	}{
+		{
+			name: "success",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.keys.EXPECT().GetByIDForUpdate(ctx, oldID).Return(oldKey, nil).Times(1)
+				m.keys.EXPECT().Create(ctx, newKey).DoAndReturn(created).Times(1)
+				m.keys.EXPECT().SetExpiresAt(ctx, oldID, graceEnd).Return(nil).Times(1)
+				m.audit.EXPECT().Create(ctx, auditRecord(actorID, entity.AuditActionAPIKeyRotated, oldID, newID.String())).
+					Return(nil).Times(1)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "old key expires before grace ends",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.keys.EXPECT().GetByIDForUpdate(ctx, oldID).Return(soonExpiring, nil).Times(1)
+				m.keys.EXPECT().Create(ctx, newKey).DoAndReturn(created).Times(1)
+				m.audit.EXPECT().Create(ctx, gomock.Any()).Return(nil).Times(1)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "no key found",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.keys.EXPECT().GetByIDForUpdate(ctx, oldID).Return(entity.APIKey{}, repository.ErrNoAPIKeyFound).Times(1)
+			},
+			wantErr: service.ErrNoAPIKeyFound,
+		},
+		{
+			name: "key of another account",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				other := oldKey
+				other.ServiceAccountID = uuid.New()
+				m.keys.EXPECT().GetByIDForUpdate(ctx, oldID).Return(other, nil).Times(1)
+			},
+			wantErr: service.ErrNoAPIKeyFound,
+		},
+		{
+			name: "revoked key",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.keys.EXPECT().GetByIDForUpdate(ctx, oldID).Return(revokedKey, nil).Times(1)
+			},
+			wantErr: service.ErrAPIKeyInactive,
+		},
+		{
+			name: "set expiry error",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.keys.EXPECT().GetByIDForUpdate(ctx, oldID).Return(oldKey, nil).Times(1)
+				m.keys.EXPECT().Create(ctx, newKey).DoAndReturn(created).Times(1)
+				m.keys.EXPECT().SetExpiresAt(ctx, oldID, graceEnd).Return(arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s, m := newService(ctrl)
+			tc.mockBehavior(m)
+
+			out, key, err := s.RotateAPIKey(ctx, actorID, accountID, oldID)
+			assert.ErrorIs(t, err, tc.wantErr)
+			if tc.wantErr == nil {
+				assert.Equal(t, newID, out.ID)
+				assert.Equal(t, hasher.HashToken(key), out.KeyHash)
+			} else {
+				assert.Empty(t, key)
+			}
+		})
+	}
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		arbitraryErr = errors.New("arbitrary error")
+		actorID      = uuid.New()
+		accountID    = uuid.New()
+		keyID        = uuid.New()
+		revokedAt    = time.Now()
+		key          = entity.APIKey{ID: keyID, ServiceAccountID: accountID, ExpiresAt: time.Now().Add(time.Hour)}
+	)
+
+	revokedKey := key
+	revokedKey.RevokedAt = &revokedAt
+
+	type MockBehavior func(m serviceMocks)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		wantErr      error
+	}{
+		{
+			name: "success",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.keys.EXPECT().GetByIDForUpdate(ctx, keyID).Return(key, nil).Times(1)
+				m.keys.EXPECT().Revoke(ctx, keyID).Return(nil).Times(1)
+				m.audit.EXPECT().Create(ctx, auditRecord(actorID, entity.AuditActionAPIKeyRevoked, keyID, `"revoked":true`)).
+					Return(nil).Times(1)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "already revoked",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.keys.EXPECT().GetByIDForUpdate(ctx, keyID).Return(revokedKey, nil).Times(1)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "no key found",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.keys.EXPECT().GetByIDForUpdate(ctx, keyID).Return(entity.APIKey{}, repository.ErrNoAPIKeyFound).Times(1)
+			},
+			wantErr: service.ErrNoAPIKeyFound,
+		},
+		{
+			name: "revoke error",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.keys.EXPECT().GetByIDForUpdate(ctx, keyID).Return(key, nil).Times(1)
+				m.keys.EXPECT().Revoke(ctx, keyID).Return(arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s, m := newService(ctrl)
+			tc.mockBehavior(m)
+
+			err := s.RevokeAPIKey(ctx, actorID, accountID, keyID)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		arbitraryErr = errors.New("arbitrary error")
+		secret       = "pvz_secret"
+		accountID    = uuid.New()
+		keyID        = uuid.New()
+		scopes       = []entity.Permission{entity.PermissionPointRead}
+		revokedAt    = time.Now().Add(-time.Minute)
+		key          = entity.APIKey{
+			ID:               keyID,
+			ServiceAccountID: accountID,
+			Scopes:           scopes,
+			ExpiresAt:        time.Now().Add(time.Hour),
+		}
+	)
+
+	expired := key
+	expired.ExpiresAt = time.Now().Add(-time.Minute)
+
+	revoked := key
+	revoked.RevokedAt = &revokedAt
+
+	legacy := key
+	legacy.Scopes = []entity.Permission{entity.PermissionPointRead, entity.PermissionUserManage}
+
+	type MockBehavior func(m serviceMocks)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		wantErr      error
+	}{
+		{
+			name: "success",
+			mockBehavior: func(m serviceMocks) {
+				m.keys.EXPECT().GetByHash(ctx, hasher.HashToken(secret)).Return(key, nil).Times(1)
+				m.keys.EXPECT().TouchLastUsed(ctx, keyID, gomock.Any(), gomock.Any()).Return(nil).Times(1)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "touch error is ignored",
+			mockBehavior: func(m serviceMocks) {
+				m.keys.EXPECT().GetByHash(ctx, hasher.HashToken(secret)).Return(key, nil).Times(1)
+				m.keys.EXPECT().TouchLastUsed(ctx, keyID, gomock.Any(), gomock.Any()).Return(arbitraryErr).Times(1)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "scopes outside the allow-list are dropped",
+			mockBehavior: func(m serviceMocks) {
+				m.keys.EXPECT().GetByHash(ctx, hasher.HashToken(secret)).Return(legacy, nil).Times(1)
+				m.keys.EXPECT().TouchLastUsed(ctx, keyID, gomock.Any(), gomock.Any()).Return(nil).Times(1)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "unknown key",
+			mockBehavior: func(m serviceMocks) {
+				m.keys.EXPECT().GetByHash(ctx, hasher.HashToken(secret)).Return(entity.APIKey{}, repository.ErrNoAPIKeyFound).Times(1)
+			},
+			wantErr: service.ErrInvalidAPIKey,
+		},
+		{
+			name: "expired key",
+			mockBehavior: func(m serviceMocks) {
+				m.keys.EXPECT().GetByHash(ctx, hasher.HashToken(secret)).Return(expired, nil).Times(1)
+			},
+			wantErr: service.ErrInvalidAPIKey,
+		},
+		{
+			name: "revoked key",
+			mockBehavior: func(m serviceMocks) {
+				m.keys.EXPECT().GetByHash(ctx, hasher.HashToken(secret)).Return(revoked, nil).Times(1)
+			},
+			wantErr: service.ErrInvalidAPIKey,
+		},
+		{
+			name: "repository error",
+			mockBehavior: func(m serviceMocks) {
+				m.keys.EXPECT().GetByHash(ctx, hasher.HashToken(secret)).Return(entity.APIKey{}, arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s, m := newService(ctrl)
+			tc.mockBehavior(m)
+
+			claims, err := s.AuthenticateAPIKey(ctx, secret)
+			assert.ErrorIs(t, err, tc.wantErr)
+			if tc.wantErr == nil {
+				assert.Equal(t, accountID, claims.UserID)
+				assert.Equal(t, keyID, claims.APIKeyID)
+				assert.Equal(t, scopes, claims.Scopes)
+				assert.True(t, claims.IsServiceAccount())
+			} else {
+				assert.Nil(t, claims)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package transfer
+
+import (
+	"context"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/google/uuid"
+)
+
+//go:generate go tool mockgen -source=contracts.go -destination=mocks/mocks.go -package=mocks
+
+type TransferRepository interface {
+	Create(ctx context.Context, transfer entity.Transfer) (entity.Transfer, error)
+	AttachProducts(ctx context.Context, transferID uuid.UUID, pointID uuid.UUID, productIDs []uuid.UUID) error
+	GetByID(ctx context.Context, transferID uuid.UUID) (entity.Transfer, error)
+	GetByIDForUpdate(ctx context.Context, transferID uuid.UUID) (entity.Transfer, error)
+	MarkDispatched(ctx context.Context, transferID uuid.UUID) error
+	MarkAccepted(ctx context.Context, transferID uuid.UUID, receptionID uuid.UUID) error
+	UpdateProduct(ctx context.Context, transferID uuid.UUID, product entity.TransferProduct) error
+}
+
+type ProductRepository interface {
+	GetByIDForUpdate(ctx context.Context, productID uuid.UUID) (entity.Product, error)
+	UpdateStatus(ctx context.Context, productID uuid.UUID, status entity.ProductStatus) error
+	UpdateLocation(ctx context.Context, productID uuid.UUID, pointID uuid.UUID) error
+	CreateHistory(ctx context.Context, history entity.ProductHistory) (entity.ProductHistory, error)
+}
+
+type ReceptionRepository interface {
+	GetLastReception(ctx context.Context, pointID uuid.UUID) (entity.Reception, error)
+}
+
+type AuditRepository interface {
+	Create(ctx context.Context, record entity.AuditRecord) error
+}
@@ -0,0 +1,14 @@
+package transfer
+
+import "errors"
+
+var (
+	ErrNoTransferFound       = errors.New("no transfer found")
+	ErrNoPointFound          = errors.New("no point found")
+	ErrSamePoint             = errors.New("source and destination points must differ")
+	ErrProductsUnavailable   = errors.New("some products are not stored at the source point or already in transfer")
+	ErrTransferNotCreated    = errors.New("transfer is already dispatched")
+	ErrTransferNotDispatched = errors.New("transfer is not dispatched")
+	ErrNoTransferReception   = errors.New("no transfer reception in progress at destination point")
+	ErrUnknownProduct        = errors.New("scanned product does not belong to transfer")
+)
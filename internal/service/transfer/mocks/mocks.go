@@ -0,0 +1,303 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: contracts.go
+//
+// Generated by this command:
+//
+//	mockgen -source=contracts.go -destination=mocks/mocks.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/4udiwe/avito-pvz/internal/entity"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTransferRepository is a mock of TransferRepository interface.
+type MockTransferRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransferRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockTransferRepositoryMockRecorder is the mock recorder for MockTransferRepository.
+type MockTransferRepositoryMockRecorder struct {
+	mock *MockTransferRepository
+}
+
+// NewMockTransferRepository creates a new mock instance.
+func NewMockTransferRepository(ctrl *gomock.Controller) *MockTransferRepository {
+	mock := &MockTransferRepository{ctrl: ctrl}
+	mock.recorder = &MockTransferRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransferRepository) EXPECT() *MockTransferRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AttachProducts mocks base method.
+func (m *MockTransferRepository) AttachProducts(ctx context.Context, transferID, pointID uuid.UUID, productIDs []uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachProducts", ctx, transferID, pointID, productIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AttachProducts indicates an expected call of AttachProducts.
+func (mr *MockTransferRepositoryMockRecorder) AttachProducts(ctx, transferID, pointID, productIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachProducts", reflect.TypeOf((*MockTransferRepository)(nil).AttachProducts), ctx, transferID, pointID, productIDs)
+}
+
+// Create mocks base method.
+func (m *MockTransferRepository) Create(ctx context.Context, transfer entity.Transfer) (entity.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, transfer)
+	ret0, _ := ret[0].(entity.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTransferRepositoryMockRecorder) Create(ctx, transfer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTransferRepository)(nil).Create), ctx, transfer)
+}
+
+// GetByID mocks base method.
+func (m *MockTransferRepository) GetByID(ctx context.Context, transferID uuid.UUID) (entity.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, transferID)
+	ret0, _ := ret[0].(entity.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockTransferRepositoryMockRecorder) GetByID(ctx, transferID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTransferRepository)(nil).GetByID), ctx, transferID)
+}
+
+// GetByIDForUpdate mocks base method.
+func (m *MockTransferRepository) GetByIDForUpdate(ctx context.Context, transferID uuid.UUID) (entity.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDForUpdate", ctx, transferID)
+	ret0, _ := ret[0].(entity.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDForUpdate indicates an expected call of GetByIDForUpdate.
+func (mr *MockTransferRepositoryMockRecorder) GetByIDForUpdate(ctx, transferID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDForUpdate", reflect.TypeOf((*MockTransferRepository)(nil).GetByIDForUpdate), ctx, transferID)
+}
+
+// MarkAccepted mocks base method.
+func (m *MockTransferRepository) MarkAccepted(ctx context.Context, transferID, receptionID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAccepted", ctx, transferID, receptionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkAccepted indicates an expected call of MarkAccepted.
+func (mr *MockTransferRepositoryMockRecorder) MarkAccepted(ctx, transferID, receptionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAccepted", reflect.TypeOf((*MockTransferRepository)(nil).MarkAccepted), ctx, transferID, receptionID)
+}
+
+// MarkDispatched mocks base method.
+func (m *MockTransferRepository) MarkDispatched(ctx context.Context, transferID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkDispatched", ctx, transferID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkDispatched indicates an expected call of MarkDispatched.
+func (mr *MockTransferRepositoryMockRecorder) MarkDispatched(ctx, transferID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDispatched", reflect.TypeOf((*MockTransferRepository)(nil).MarkDispatched), ctx, transferID)
+}
+
+// UpdateProduct mocks base method.
+func (m *MockTransferRepository) UpdateProduct(ctx context.Context, transferID uuid.UUID, product entity.TransferProduct) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProduct", ctx, transferID, product)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProduct indicates an expected call of UpdateProduct.
+func (mr *MockTransferRepositoryMockRecorder) UpdateProduct(ctx, transferID, product any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProduct", reflect.TypeOf((*MockTransferRepository)(nil).UpdateProduct), ctx, transferID, product)
+}
+
+// MockProductRepository is a mock of ProductRepository interface.
+type MockProductRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockProductRepositoryMockRecorder is the mock recorder for MockProductRepository.
+type MockProductRepositoryMockRecorder struct {
+	mock *MockProductRepository
+}
+
+// NewMockProductRepository creates a new mock instance.
+func NewMockProductRepository(ctrl *gomock.Controller) *MockProductRepository {
+	mock := &MockProductRepository{ctrl: ctrl}
+	mock.recorder = &MockProductRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductRepository) EXPECT() *MockProductRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateHistory mocks base method.
+func (m *MockProductRepository) CreateHistory(ctx context.Context, history entity.ProductHistory) (entity.ProductHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHistory", ctx, history)
+	ret0, _ := ret[0].(entity.ProductHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHistory indicates an expected call of CreateHistory.
+func (mr *MockProductRepositoryMockRecorder) CreateHistory(ctx, history any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHistory", reflect.TypeOf((*MockProductRepository)(nil).CreateHistory), ctx, history)
+}
+
+// GetByIDForUpdate mocks base method.
+func (m *MockProductRepository) GetByIDForUpdate(ctx context.Context, productID uuid.UUID) (entity.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDForUpdate", ctx, productID)
+	ret0, _ := ret[0].(entity.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDForUpdate indicates an expected call of GetByIDForUpdate.
+func (mr *MockProductRepositoryMockRecorder) GetByIDForUpdate(ctx, productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDForUpdate", reflect.TypeOf((*MockProductRepository)(nil).GetByIDForUpdate), ctx, productID)
+}
+
+// UpdateLocation mocks base method.
+func (m *MockProductRepository) UpdateLocation(ctx context.Context, productID, pointID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLocation", ctx, productID, pointID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLocation indicates an expected call of UpdateLocation.
+func (mr *MockProductRepositoryMockRecorder) UpdateLocation(ctx, productID, pointID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLocation", reflect.TypeOf((*MockProductRepository)(nil).UpdateLocation), ctx, productID, pointID)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockProductRepository) UpdateStatus(ctx context.Context, productID uuid.UUID, status entity.ProductStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, productID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockProductRepositoryMockRecorder) UpdateStatus(ctx, productID, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockProductRepository)(nil).UpdateStatus), ctx, productID, status)
+}
+
+// MockReceptionRepository is a mock of ReceptionRepository interface.
+type MockReceptionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockReceptionRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockReceptionRepositoryMockRecorder is the mock recorder for MockReceptionRepository.
+type MockReceptionRepositoryMockRecorder struct {
+	mock *MockReceptionRepository
+}
+
+// NewMockReceptionRepository creates a new mock instance.
+func NewMockReceptionRepository(ctrl *gomock.Controller) *MockReceptionRepository {
+	mock := &MockReceptionRepository{ctrl: ctrl}
+	mock.recorder = &MockReceptionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReceptionRepository) EXPECT() *MockReceptionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetLastReception mocks base method.
+func (m *MockReceptionRepository) GetLastReception(ctx context.Context, pointID uuid.UUID) (entity.Reception, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastReception", ctx, pointID)
+	ret0, _ := ret[0].(entity.Reception)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastReception indicates an expected call of GetLastReception.
+func (mr *MockReceptionRepositoryMockRecorder) GetLastReception(ctx, pointID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastReception", reflect.TypeOf((*MockReceptionRepository)(nil).GetLastReception), ctx, pointID)
+}
+
+// MockAuditRepository is a mock of AuditRepository interface.
+type MockAuditRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAuditRepositoryMockRecorder is the mock recorder for MockAuditRepository.
+type MockAuditRepositoryMockRecorder struct {
+	mock *MockAuditRepository
+}
+
+// NewMockAuditRepository creates a new mock instance.
+func NewMockAuditRepository(ctrl *gomock.Controller) *MockAuditRepository {
+	mock := &MockAuditRepository{ctrl: ctrl}
+	mock.recorder = &MockAuditRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditRepository) EXPECT() *MockAuditRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAuditRepository) Create(ctx context.Context, record entity.AuditRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAuditRepositoryMockRecorder) Create(ctx, record any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAuditRepository)(nil).Create), ctx, record)
+}
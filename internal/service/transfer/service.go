@@ -0,0 +1,318 @@
+package transfer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/repository"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/4udiwe/avito-pvz/pkg/transactor"
+	"github.com/google/uuid"
+)
+
+// productSnapshot matches the product service records, with the point
+// added because accepting a transfer moves the product.
+type productSnapshot struct {
+	PointID     uuid.UUID            `json:"point_id"`
+	ReceptionID uuid.UUID            `json:"reception_id"`
+	Type        entity.ProductType   `json:"type"`
+	Status      entity.ProductStatus `json:"status"`
+	Barcode     *string              `json:"barcode,omitempty"`
+	Reason      string               `json:"reason,omitempty"`
+}
+
+type Service struct {
+	transferRepository  TransferRepository
+	productRepository   ProductRepository
+	receptionRepository ReceptionRepository
+	auditRepository     AuditRepository
+	txManager           transactor.Transactor
+}
+
+func New(t TransferRepository, p ProductRepository, r ReceptionRepository, a AuditRepository, tx transactor.Transactor) *Service {
+	return &Service{
+		transferRepository:  t,
+		productRepository:   p,
+		receptionRepository: r,
+		auditRepository:     a,
+		txManager:           tx,
+	}
+}
+
+func (s *Service) CreateTransfer(
+	ctx context.Context,
+	sourcePointID uuid.UUID,
+	destinationPointID uuid.UUID,
+	productIDs []uuid.UUID,
+	actorID uuid.UUID,
+) (entity.Transfer, error) {
+	logger.FromContext(ctx).Infof("Service: Creating transfer of %d products from %s to %s", len(productIDs), sourcePointID, destinationPointID)
+
+	if sourcePointID == destinationPointID {
+		return entity.Transfer{}, ErrSamePoint
+	}
+
+	var out entity.Transfer
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		transfer, err := s.transferRepository.Create(ctx, entity.Transfer{
+			SourcePointID:      sourcePointID,
+			DestinationPointID: destinationPointID,
+			CreatedBy:          actorID,
+		})
+		if err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to create transfer: %v", err)
+			return err
+		}
+
+		if err = s.transferRepository.AttachProducts(ctx, transfer.ID, sourcePointID, productIDs); err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to attach products to transfer %s: %v", transfer.ID, err)
+			return err
+		}
+
+		for _, productID := range productIDs {
+			transfer.Products = append(transfer.Products, entity.TransferProduct{ProductID: productID})
+		}
+		out = transfer
+		return nil
+	})
+
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNoPointFound):
+			return entity.Transfer{}, ErrNoPointFound
+		case errors.Is(err, repository.ErrProductsUnavailable):
+			return entity.Transfer{}, ErrProductsUnavailable
+		}
+		return entity.Transfer{}, err
+	}
+
+	logger.FromContext(ctx).Infof("Service: Transfer created: %s", out.ID)
+	return out, nil
+}
+
+func (s *Service) GetTransfer(ctx context.Context, transferID uuid.UUID) (entity.Transfer, error) {
+	logger.FromContext(ctx).Infof("Service: Fetching transfer: %s", transferID)
+
+	transfer, err := s.transferRepository.GetByID(ctx, transferID)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to fetch transfer %s: %v", transferID, err)
+		if errors.Is(err, repository.ErrNoTransferFound) {
+			return entity.Transfer{}, ErrNoTransferFound
+		}
+		return entity.Transfer{}, err
+	}
+
+	return transfer, nil
+}
+
+// Dispatch moves all transfer products to in_transit.
+func (s *Service) Dispatch(ctx context.Context, transferID uuid.UUID, actorID uuid.UUID) error {
+	logger.FromContext(ctx).Infof("Service: Dispatching transfer %s by %s", transferID, actorID)
+
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		transfer, err := s.transferRepository.GetByIDForUpdate(ctx, transferID)
+		if err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to get transfer %s: %v", transferID, err)
+			return err
+		}
+
+		if transfer.Status != entity.TransferStatusCreated {
+			logger.FromContext(ctx).Warnf("Service: Transfer %s is %s, not created", transferID, transfer.Status)
+			return ErrTransferNotCreated
+		}
+
+		for _, p := range transfer.Products {
+			product, err := s.productRepository.GetByIDForUpdate(ctx, p.ProductID)
+			if err != nil {
+				logger.FromContext(ctx).Errorf("Service: Failed to get product %s: %v", p.ProductID, err)
+				return err
+			}
+			if product.Status != entity.ProductStatusStored || product.PointID != transfer.SourcePointID {
+				logger.FromContext(ctx).Warnf("Service: Product %s is no longer stored at source point", p.ProductID)
+				return ErrProductsUnavailable
+			}
+			if err = s.moveProduct(ctx, product, product, entity.ProductStatusInTransit, transferID, actorID); err != nil {
+				return err
+			}
+		}
+
+		return s.transferRepository.MarkDispatched(ctx, transferID)
+	})
+
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to dispatch transfer %s: %v", transferID, err)
+		if errors.Is(err, repository.ErrNoTransferFound) {
+			return ErrNoTransferFound
+		}
+		return err
+	}
+
+	logger.FromContext(ctx).Infof("Service: Transfer %s dispatched", transferID)
+	return nil
+}
+
+// Accept books scanned products into the open transfer reception of the
+// destination point. Products that were not scanned are marked missing and
+// stay in transit.
+func (s *Service) Accept(ctx context.Context, transferID uuid.UUID, scannedIDs []uuid.UUID, actorID uuid.UUID) (entity.Transfer, error) {
+	logger.FromContext(ctx).Infof("Service: Accepting transfer %s with %d scanned products by %s", transferID, len(scannedIDs), actorID)
+
+	var out entity.Transfer
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		transfer, err := s.transferRepository.GetByIDForUpdate(ctx, transferID)
+		if err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to get transfer %s: %v", transferID, err)
+			return err
+		}
+
+		if transfer.Status != entity.TransferStatusDispatched {
+			logger.FromContext(ctx).Warnf("Service: Transfer %s is %s, not dispatched", transferID, transfer.Status)
+			return ErrTransferNotDispatched
+		}
+
+		reception, err := s.receptionRepository.GetLastReception(ctx, transfer.DestinationPointID)
+		if err != nil && !errors.Is(err, repository.ErrNoReceptionFound) {
+			logger.FromContext(ctx).Errorf("Service: Failed to get last reception of point %s: %v", transfer.DestinationPointID, err)
+			return err
+		}
+		if err != nil || reception.Status != entity.ReceptionStatusInProgress || reception.Kind != entity.ReceptionKindTransfer {
+			logger.FromContext(ctx).Warnf("Service: No transfer reception in progress at point %s", transfer.DestinationPointID)
+			return ErrNoTransferReception
+		}
+
+		scanned := make(map[uuid.UUID]bool, len(scannedIDs))
+		for _, id := range scannedIDs {
+			scanned[id] = true
+		}
+
+		for i, p := range transfer.Products {
+			if !scanned[p.ProductID] {
+				p.Missing = true
+				logger.FromContext(ctx).Warnf("Service: Product %s of transfer %s is missing", p.ProductID, transferID)
+			} else {
+				delete(scanned, p.ProductID)
+				p.Accepted = true
+
+				product, err := s.productRepository.GetByIDForUpdate(ctx, p.ProductID)
+				if err != nil {
+					logger.FromContext(ctx).Errorf("Service: Failed to get product %s: %v", p.ProductID, err)
+					return err
+				}
+				if err = s.productRepository.UpdateLocation(ctx, p.ProductID, transfer.DestinationPointID); err != nil {
+					logger.FromContext(ctx).Errorf("Service: Failed to move product %s: %v", p.ProductID, err)
+					return err
+				}
+				// moveProduct records the move in the audit log along with the status
+				before := product
+				product.PointID = transfer.DestinationPointID
+				if err = s.moveProduct(ctx, before, product, entity.ProductStatusReceived, transferID, actorID); err != nil {
+					return err
+				}
+			}
+
+			if err = s.transferRepository.UpdateProduct(ctx, transferID, p); err != nil {
+				logger.FromContext(ctx).Errorf("Service: Failed to update product %s of transfer %s: %v", p.ProductID, transferID, err)
+				return err
+			}
+			transfer.Products[i] = p
+		}
+
+		if len(scanned) > 0 {
+			logger.FromContext(ctx).Warnf("Service: %d scanned products do not belong to transfer %s", len(scanned), transferID)
+			return ErrUnknownProduct
+		}
+
+		if err = s.transferRepository.MarkAccepted(ctx, transferID, reception.ID); err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to mark transfer %s accepted: %v", transferID, err)
+			return err
+		}
+
+		transfer.Status = entity.TransferStatusAccepted
+		transfer.ReceptionID = &reception.ID
+		out = transfer
+		return nil
+	})
+
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to accept transfer %s: %v", transferID, err)
+		if errors.Is(err, repository.ErrNoTransferFound) {
+			return entity.Transfer{}, ErrNoTransferFound
+		}
+		return entity.Transfer{}, err
+	}
+
+	logger.FromContext(ctx).Infof("Service: Transfer %s accepted", transferID)
+	return out, nil
+}
+
+// moveProduct sets the status of a product and records it in the product
+// history and the audit log. moved is the product after changes already
+// made by the caller, e.g. its new point.
+func (s *Service) moveProduct(
+	ctx context.Context,
+	product entity.Product,
+	moved entity.Product,
+	to entity.ProductStatus,
+	transferID uuid.UUID,
+	actorID uuid.UUID,
+) error {
+	if err := s.productRepository.UpdateStatus(ctx, product.ID, to); err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to update status of product %s: %v", product.ID, err)
+		return err
+	}
+
+	reason := fmt.Sprintf("transfer %s", transferID)
+	from := product.Status
+	_, err := s.productRepository.CreateHistory(ctx, entity.ProductHistory{
+		ProductID:  product.ID,
+		FromStatus: &from,
+		ToStatus:   to,
+		Reason:     reason,
+		ActorID:    actorID,
+	})
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to write history for product %s: %v", product.ID, err)
+		return err
+	}
+
+	moved.Status = to
+	after := productSnapshotOf(moved)
+	after.Reason = reason
+	return s.audit(ctx, actorID, product.ID, productSnapshotOf(product), after)
+}
+
+// audit writes a product status change within the caller's transaction.
+func (s *Service) audit(ctx context.Context, actorID uuid.UUID, productID uuid.UUID, before productSnapshot, after productSnapshot) error {
+	record := entity.AuditRecord{
+		ActorID:    actorID,
+		Action:     entity.AuditActionProductStatusChanged,
+		TargetType: entity.AuditTargetProduct,
+		TargetID:   productID,
+	}
+
+	var err error
+	if record.Before, err = json.Marshal(before); err != nil {
+		return err
+	}
+	if record.After, err = json.Marshal(after); err != nil {
+		return err
+	}
+
+	if err = s.auditRepository.Create(ctx, record); err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to write audit record: %v", err)
+	}
+	return err
+}
+
+func productSnapshotOf(p entity.Product) productSnapshot {
+	return productSnapshot{
+		PointID:     p.PointID,
+		ReceptionID: p.ReceptionID,
+		Type:        p.Type,
+		Status:      p.Status,
+		Barcode:     p.Barcode,
+	}
+}
@@ -0,0 +1,406 @@
+package transfer_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	mock_transactor "github.com/4udiwe/avito-pvz/internal/mocks"
+	"github.com/4udiwe/avito-pvz/internal/repository"
+	service "github.com/4udiwe/avito-pvz/internal/service/transfer"
+	"github.com/4udiwe/avito-pvz/internal/service/transfer/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type MockBehavior func(
+	t *mocks.MockTransferRepository,
+	p *mocks.MockProductRepository,
+	r *mocks.MockReceptionRepository,
+	a *mocks.MockAuditRepository,
+	tx *mock_transactor.MockTransactor,
+)
+
+func withinTx(ctx context.Context, tx *mock_transactor.MockTransactor) {
+	tx.EXPECT().WithinTransaction(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+}
+
+func newService(t *testing.T, mockBehavior MockBehavior) *service.Service {
+	ctrl := gomock.NewController(t)
+
+	MockTransferRepository := mocks.NewMockTransferRepository(ctrl)
+	MockProductRepository := mocks.NewMockProductRepository(ctrl)
+	MockReceptionRepository := mocks.NewMockReceptionRepository(ctrl)
+	MockAuditRepository := mocks.NewMockAuditRepository(ctrl)
+	MockTransactor := mock_transactor.NewMockTransactor(ctrl)
+
+	mockBehavior(MockTransferRepository, MockProductRepository, MockReceptionRepository, MockAuditRepository, MockTransactor)
+
+	return service.New(MockTransferRepository, MockProductRepository, MockReceptionRepository, MockAuditRepository, MockTransactor)
+}
+
+func TestCreateTransfer(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		sourceID     = uuid.New()
+		destID       = uuid.New()
+		actorID      = uuid.New()
+		transferID   = uuid.New()
+		productIDs   = []uuid.UUID{uuid.New(), uuid.New()}
+		arbitraryErr = errors.New("arbitrary error")
+
+		toCreate = entity.Transfer{SourcePointID: sourceID, DestinationPointID: destID, CreatedBy: actorID}
+		created  = entity.Transfer{ID: transferID, SourcePointID: sourceID, DestinationPointID: destID, CreatedBy: actorID, Status: entity.TransferStatusCreated}
+	)
+
+	withProducts := created
+	withProducts.Products = []entity.TransferProduct{{ProductID: productIDs[0]}, {ProductID: productIDs[1]}}
+
+	for _, tc := range []struct {
+		name         string
+		destID       uuid.UUID
+		mockBehavior MockBehavior
+		want         entity.Transfer
+		wantErr      error
+	}{
+		{
+			name:   "success",
+			destID: destID,
+			mockBehavior: func(tr *mocks.MockTransferRepository, p *mocks.MockProductRepository, r *mocks.MockReceptionRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				tr.EXPECT().Create(ctx, toCreate).Return(created, nil)
+				tr.EXPECT().AttachProducts(ctx, transferID, sourceID, productIDs).Return(nil)
+			},
+			want: withProducts,
+		},
+		{
+			name:   "same point",
+			destID: sourceID,
+			mockBehavior: func(tr *mocks.MockTransferRepository, p *mocks.MockProductRepository, r *mocks.MockReceptionRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor) {
+			},
+			wantErr: service.ErrSamePoint,
+		},
+		{
+			name:   "no point",
+			destID: destID,
+			mockBehavior: func(tr *mocks.MockTransferRepository, p *mocks.MockProductRepository, r *mocks.MockReceptionRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				tr.EXPECT().Create(ctx, toCreate).Return(entity.Transfer{}, repository.ErrNoPointFound)
+			},
+			wantErr: service.ErrNoPointFound,
+		},
+		{
+			name:   "products unavailable",
+			destID: destID,
+			mockBehavior: func(tr *mocks.MockTransferRepository, p *mocks.MockProductRepository, r *mocks.MockReceptionRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				tr.EXPECT().Create(ctx, toCreate).Return(created, nil)
+				tr.EXPECT().AttachProducts(ctx, transferID, sourceID, productIDs).Return(repository.ErrProductsUnavailable)
+			},
+			wantErr: service.ErrProductsUnavailable,
+		},
+		{
+			name:   "arbitrary error",
+			destID: destID,
+			mockBehavior: func(tr *mocks.MockTransferRepository, p *mocks.MockProductRepository, r *mocks.MockReceptionRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				tr.EXPECT().Create(ctx, toCreate).Return(entity.Transfer{}, arbitraryErr)
+			},
+			wantErr: arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := newService(t, tc.mockBehavior)
+			out, err := s.CreateTransfer(ctx, sourceID, tc.destID, productIDs, actorID)
+
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.want, out)
+		})
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		sourceID     = uuid.New()
+		actorID      = uuid.New()
+		transferID   = uuid.New()
+		productID    = uuid.New()
+		arbitraryErr = errors.New("arbitrary error")
+
+		transfer = entity.Transfer{
+			ID:            transferID,
+			SourcePointID: sourceID,
+			Status:        entity.TransferStatusCreated,
+			Products:      []entity.TransferProduct{{ProductID: productID}},
+		}
+		receptionID = uuid.New()
+		stored      = entity.Product{ID: productID, PointID: sourceID, ReceptionID: receptionID, Type: entity.ProductTypeShoes, Status: entity.ProductStatusStored}
+	)
+
+	shipped := entity.AuditRecord{
+		ActorID:    actorID,
+		Action:     entity.AuditActionProductStatusChanged,
+		TargetType: entity.AuditTargetProduct,
+		TargetID:   productID,
+		Before:     []byte(`{"point_id":"` + sourceID.String() + `","reception_id":"` + receptionID.String() + `","type":"обувь","status":"stored"}`),
+		After:      []byte(`{"point_id":"` + sourceID.String() + `","reception_id":"` + receptionID.String() + `","type":"обувь","status":"in_transit","reason":"transfer ` + transferID.String() + `"}`),
+	}
+
+	dispatched := transfer
+	dispatched.Status = entity.TransferStatusDispatched
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		wantErr      error
+	}{
+		{
+			name: "success",
+			mockBehavior: func(tr *mocks.MockTransferRepository, p *mocks.MockProductRepository, r *mocks.MockReceptionRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				tr.EXPECT().GetByIDForUpdate(ctx, transferID).Return(transfer, nil)
+				p.EXPECT().GetByIDForUpdate(ctx, productID).Return(stored, nil)
+				p.EXPECT().UpdateStatus(ctx, productID, entity.ProductStatusInTransit).Return(nil)
+				p.EXPECT().CreateHistory(ctx, gomock.Any()).Return(entity.ProductHistory{}, nil)
+				a.EXPECT().Create(ctx, shipped).Return(nil)
+				tr.EXPECT().MarkDispatched(ctx, transferID).Return(nil)
+			},
+		},
+		{
+			name: "audit error",
+			mockBehavior: func(tr *mocks.MockTransferRepository, p *mocks.MockProductRepository, r *mocks.MockReceptionRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				tr.EXPECT().GetByIDForUpdate(ctx, transferID).Return(transfer, nil)
+				p.EXPECT().GetByIDForUpdate(ctx, productID).Return(stored, nil)
+				p.EXPECT().UpdateStatus(ctx, productID, entity.ProductStatusInTransit).Return(nil)
+				p.EXPECT().CreateHistory(ctx, gomock.Any()).Return(entity.ProductHistory{}, nil)
+				a.EXPECT().Create(ctx, shipped).Return(arbitraryErr)
+			},
+			wantErr: arbitraryErr,
+		},
+		{
+			name: "no transfer",
+			mockBehavior: func(tr *mocks.MockTransferRepository, p *mocks.MockProductRepository, r *mocks.MockReceptionRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				tr.EXPECT().GetByIDForUpdate(ctx, transferID).Return(entity.Transfer{}, repository.ErrNoTransferFound)
+			},
+			wantErr: service.ErrNoTransferFound,
+		},
+		{
+			name: "already dispatched",
+			mockBehavior: func(tr *mocks.MockTransferRepository, p *mocks.MockProductRepository, r *mocks.MockReceptionRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				tr.EXPECT().GetByIDForUpdate(ctx, transferID).Return(dispatched, nil)
+			},
+			wantErr: service.ErrTransferNotCreated,
+		},
+		{
+			name: "product no longer stored",
+			mockBehavior: func(tr *mocks.MockTransferRepository, p *mocks.MockProductRepository, r *mocks.MockReceptionRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				tr.EXPECT().GetByIDForUpdate(ctx, transferID).Return(transfer, nil)
+				p.EXPECT().GetByIDForUpdate(ctx, productID).Return(entity.Product{ID: productID, PointID: sourceID, Status: entity.ProductStatusIssued}, nil)
+			},
+			wantErr: service.ErrProductsUnavailable,
+		},
+		{
+			name: "arbitrary error",
+			mockBehavior: func(tr *mocks.MockTransferRepository, p *mocks.MockProductRepository, r *mocks.MockReceptionRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				tr.EXPECT().GetByIDForUpdate(ctx, transferID).Return(transfer, nil)
+				p.EXPECT().GetByIDForUpdate(ctx, productID).Return(stored, nil)
+				p.EXPECT().UpdateStatus(ctx, productID, entity.ProductStatusInTransit).Return(arbitraryErr)
+			},
+			wantErr: arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := newService(t, tc.mockBehavior)
+			err := s.Dispatch(ctx, transferID, actorID)
+
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestAccept(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		sourceID     = uuid.New()
+		destID       = uuid.New()
+		actorID      = uuid.New()
+		transferID   = uuid.New()
+		receptionID  = uuid.New()
+		scannedID    = uuid.New()
+		lostID       = uuid.New()
+		arbitraryErr = errors.New("arbitrary error")
+
+		transfer = entity.Transfer{
+			ID:                 transferID,
+			SourcePointID:      sourceID,
+			DestinationPointID: destID,
+			Status:             entity.TransferStatusDispatched,
+			Products:           []entity.TransferProduct{{ProductID: scannedID}, {ProductID: lostID}},
+		}
+		reception = entity.Reception{
+			ID:      receptionID,
+			PointID: destID,
+			Status:  entity.ReceptionStatusInProgress,
+			Kind:    entity.ReceptionKindTransfer,
+		}
+		inTransit = entity.Product{ID: scannedID, PointID: sourceID, ReceptionID: receptionID, Type: entity.ProductTypeShoes, Status: entity.ProductStatusInTransit}
+	)
+
+	received := entity.AuditRecord{
+		ActorID:    actorID,
+		Action:     entity.AuditActionProductStatusChanged,
+		TargetType: entity.AuditTargetProduct,
+		TargetID:   scannedID,
+		Before:     []byte(`{"point_id":"` + sourceID.String() + `","reception_id":"` + receptionID.String() + `","type":"обувь","status":"in_transit"}`),
+		After:      []byte(`{"point_id":"` + destID.String() + `","reception_id":"` + receptionID.String() + `","type":"обувь","status":"received","reason":"transfer ` + transferID.String() + `"}`),
+	}
+
+	accepted := entity.Transfer{
+		ID:                 transferID,
+		SourcePointID:      sourceID,
+		DestinationPointID: destID,
+		Status:             entity.TransferStatusAccepted,
+		ReceptionID:        &receptionID,
+		Products: []entity.TransferProduct{
+			{ProductID: scannedID, Accepted: true},
+			{ProductID: lostID, Missing: true},
+		},
+	}
+
+	// every case gets its own copy because Accept updates products in place
+	load := func() entity.Transfer {
+		tr := transfer
+		tr.Products = append([]entity.TransferProduct(nil), transfer.Products...)
+		return tr
+	}
+
+	for _, tc := range []struct {
+		name         string
+		scanned      []uuid.UUID
+		mockBehavior MockBehavior
+		want         entity.Transfer
+		wantErr      error
+	}{
+		{
+			name:    "success with missing product",
+			scanned: []uuid.UUID{scannedID},
+			mockBehavior: func(tr *mocks.MockTransferRepository, p *mocks.MockProductRepository, r *mocks.MockReceptionRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				tr.EXPECT().GetByIDForUpdate(ctx, transferID).Return(load(), nil)
+				r.EXPECT().GetLastReception(ctx, destID).Return(reception, nil)
+				p.EXPECT().GetByIDForUpdate(ctx, scannedID).Return(inTransit, nil)
+				p.EXPECT().UpdateLocation(ctx, scannedID, destID).Return(nil)
+				p.EXPECT().UpdateStatus(ctx, scannedID, entity.ProductStatusReceived).Return(nil)
+				p.EXPECT().CreateHistory(ctx, gomock.Any()).Return(entity.ProductHistory{}, nil)
+				a.EXPECT().Create(ctx, received).Return(nil)
+				tr.EXPECT().UpdateProduct(ctx, transferID, entity.TransferProduct{ProductID: scannedID, Accepted: true}).Return(nil)
+				tr.EXPECT().UpdateProduct(ctx, transferID, entity.TransferProduct{ProductID: lostID, Missing: true}).Return(nil)
+				tr.EXPECT().MarkAccepted(ctx, transferID, receptionID).Return(nil)
+			},
+			want: accepted,
+		},
+		{
+			name:    "audit error",
+			scanned: []uuid.UUID{scannedID},
+			mockBehavior: func(tr *mocks.MockTransferRepository, p *mocks.MockProductRepository, r *mocks.MockReceptionRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				tr.EXPECT().GetByIDForUpdate(ctx, transferID).Return(load(), nil)
+				r.EXPECT().GetLastReception(ctx, destID).Return(reception, nil)
+				p.EXPECT().GetByIDForUpdate(ctx, scannedID).Return(inTransit, nil)
+				p.EXPECT().UpdateLocation(ctx, scannedID, destID).Return(nil)
+				p.EXPECT().UpdateStatus(ctx, scannedID, entity.ProductStatusReceived).Return(nil)
+				p.EXPECT().CreateHistory(ctx, gomock.Any()).Return(entity.ProductHistory{}, nil)
+				a.EXPECT().Create(ctx, received).Return(arbitraryErr)
+			},
+			wantErr: arbitraryErr,
+		},
+		{
+			name:    "not dispatched",
+			scanned: []uuid.UUID{scannedID},
+			mockBehavior: func(tr *mocks.MockTransferRepository, p *mocks.MockProductRepository, r *mocks.MockReceptionRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				created := load()
+				created.Status = entity.TransferStatusCreated
+				tr.EXPECT().GetByIDForUpdate(ctx, transferID).Return(created, nil)
+			},
+			wantErr: service.ErrTransferNotDispatched,
+		},
+		{
+			name:    "regular reception open",
+			scanned: []uuid.UUID{scannedID},
+			mockBehavior: func(tr *mocks.MockTransferRepository, p *mocks.MockProductRepository, r *mocks.MockReceptionRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				tr.EXPECT().GetByIDForUpdate(ctx, transferID).Return(load(), nil)
+				regular := reception
+				regular.Kind = entity.ReceptionKindRegular
+				r.EXPECT().GetLastReception(ctx, destID).Return(regular, nil)
+			},
+			wantErr: service.ErrNoTransferReception,
+		},
+		{
+			name:    "no reception",
+			scanned: []uuid.UUID{scannedID},
+			mockBehavior: func(tr *mocks.MockTransferRepository, p *mocks.MockProductRepository, r *mocks.MockReceptionRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				tr.EXPECT().GetByIDForUpdate(ctx, transferID).Return(load(), nil)
+				r.EXPECT().GetLastReception(ctx, destID).Return(entity.Reception{}, repository.ErrNoReceptionFound)
+			},
+			wantErr: service.ErrNoTransferReception,
+		},
+		{
+			name:    "unknown scanned product",
+			scanned: []uuid.UUID{uuid.New()},
+			mockBehavior: func(tr *mocks.MockTransferRepository, p *mocks.MockProductRepository, r *mocks.MockReceptionRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				tr.EXPECT().GetByIDForUpdate(ctx, transferID).Return(load(), nil)
+				r.EXPECT().GetLastReception(ctx, destID).Return(reception, nil)
+				tr.EXPECT().UpdateProduct(ctx, transferID, gomock.Any()).Return(nil).Times(2)
+			},
+			wantErr: service.ErrUnknownProduct,
+		},
+		{
+			name:    "no transfer",
+			scanned: []uuid.UUID{scannedID},
+			mockBehavior: func(tr *mocks.MockTransferRepository, p *mocks.MockProductRepository, r *mocks.MockReceptionRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				tr.EXPECT().GetByIDForUpdate(ctx, transferID).Return(entity.Transfer{}, repository.ErrNoTransferFound)
+			},
+			wantErr: service.ErrNoTransferFound,
+		},
+		{
+			name:    "arbitrary error",
+			scanned: []uuid.UUID{scannedID},
+			mockBehavior: func(tr *mocks.MockTransferRepository, p *mocks.MockProductRepository, r *mocks.MockReceptionRepository, a *mocks.MockAuditRepository, tx *mock_transactor.MockTransactor) {
+				withinTx(ctx, tx)
+				tr.EXPECT().GetByIDForUpdate(ctx, transferID).Return(load(), nil)
+				r.EXPECT().GetLastReception(ctx, destID).Return(entity.Reception{}, arbitraryErr)
+			},
+			wantErr: arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := newService(t, tc.mockBehavior)
+			out, err := s.Accept(ctx, transferID, tc.scanned, actorID)
+
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.want, out)
+		})
+	}
+}
@@ -0,0 +1,307 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/repository"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultUsersLimit = 20
+	maxUsersLimit     = 100
+)
+
+// userSnapshot is what the audit log keeps of a user.
+type userSnapshot struct {
+	Role     entity.UserRole `json:"role"`
+	Disabled bool            `json:"disabled"`
+}
+
+func (s *Service) ListUsers(ctx context.Context, filter entity.UserFilter) ([]entity.User, error) {
+	logger.FromContext(ctx).Infof("Service: Fetching users: %+v", filter)
+
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 {
+		filter.Limit = defaultUsersLimit
+	}
+	filter.Limit = min(filter.Limit, maxUsersLimit)
+
+	users, err := s.userRepository.List(ctx, filter)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to fetch users: %v", err)
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Infof("Service: Fetched %d users", len(users))
+	return users, nil
+}
+
+func (s *Service) GetUser(ctx context.Context, userID uuid.UUID) (entity.User, error) {
+	logger.FromContext(ctx).Infof("Service: Fetching user %s", userID)
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	logger.FromContext(ctx).Infof("Service: Fetched user %s", userID)
+	return user, nil
+}
+
+// ChangeRole sets the role of a user. The user's sessions and the access
+// tokens issued for them are revoked, so the new role applies from the
+// next login.
+func (s *Service) ChangeRole(ctx context.Context, actorID uuid.UUID, userID uuid.UUID, role entity.UserRole) (entity.User, error) {
+	logger.FromContext(ctx).Infof("Service: User %s changes role of %s to %s", actorID, userID, role)
+
+	if actorID == userID {
+		return entity.User{}, ErrCannotModifySelf
+	}
+
+	var out entity.User
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		user, err := s.getUserForUpdate(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		out = user
+		if user.Role == role {
+			return nil
+		}
+
+		if err = s.userRepository.UpdateRole(ctx, userID, role); err != nil {
+			if errors.Is(err, repository.ErrNoRoleFound) {
+				return ErrUnknownRole
+			}
+			logger.FromContext(ctx).Errorf("Service: Failed to update role: %v", err)
+			return err
+		}
+		out.Role = role
+
+		if err = s.revokeSessions(ctx, userID); err != nil {
+			return err
+		}
+
+		return s.auditUser(ctx, actorID, entity.AuditActionUserRoleChanged, user, out)
+	})
+
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	logger.FromContext(ctx).Infof("Service: Role of user %s is %s", userID, role)
+	return out, nil
+}
+
+// SetDisabled disables or enables a user. Disabling also revokes all of the
+// user's sessions; access tokens already issued are rejected by the auth
+// middleware.
+func (s *Service) SetDisabled(ctx context.Context, actorID uuid.UUID, userID uuid.UUID, disabled bool) (entity.User, error) {
+	logger.FromContext(ctx).Infof("Service: User %s sets disabled=%t for %s", actorID, disabled, userID)
+
+	if actorID == userID {
+		return entity.User{}, ErrCannotModifySelf
+	}
+
+	var out entity.User
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		user, err := s.getUserForUpdate(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		out = user
+		if user.Disabled() == disabled {
+			return nil
+		}
+
+		action := entity.AuditActionUserEnabled
+		out.DisabledAt = nil
+		if disabled {
+			action = entity.AuditActionUserDisabled
+			now := time.Now()
+			out.DisabledAt = &now
+		}
+
+		if err = s.userRepository.SetDisabledAt(ctx, userID, out.DisabledAt); err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to update user status: %v", err)
+			return err
+		}
+
+		if disabled {
+			if err = s.sessionRepository.RevokeAllByUser(ctx, userID); err != nil {
+				logger.FromContext(ctx).Errorf("Service: Failed to revoke sessions: %v", err)
+				return err
+			}
+		}
+
+		return s.auditUser(ctx, actorID, action, user, out)
+	})
+
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	s.disabledUsers.Set(userID, disabled)
+
+	logger.FromContext(ctx).Infof("Service: User %s disabled=%t", userID, disabled)
+	return out, nil
+}
+
+// UnlockUser clears the failed login counter of the user's email.
+func (s *Service) UnlockUser(ctx context.Context, actorID uuid.UUID, userID uuid.UUID) error {
+	logger.FromContext(ctx).Infof("Service: User %s unlocks %s", actorID, userID)
+
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		user, err := s.getUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		if err = s.failureRepository.Reset(ctx, entity.LoginScopeEmail, normalizeEmail(user.Email)); err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to reset login failures: %v", err)
+			return err
+		}
+
+		return s.auditUser(ctx, actorID, entity.AuditActionUserUnlocked, user, user)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx).Infof("Service: User %s unlocked", userID)
+	return nil
+}
+
+// ResetMFA removes the second factor of a user who lost it, together with
+// the recovery codes. If the role requires a second factor the user
+// enrols again on the next login.
+func (s *Service) ResetMFA(ctx context.Context, actorID uuid.UUID, userID uuid.UUID) error {
+	logger.FromContext(ctx).Infof("Service: User %s resets mfa of %s", actorID, userID)
+
+	if actorID == userID {
+		return ErrCannotModifySelf
+	}
+
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		user, err := s.getUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		if err = s.mfaRepository.Delete(ctx, userID); err != nil {
+			if errors.Is(err, repository.ErrNoMFAFound) {
+				return ErrNoMFAFound
+			}
+			logger.FromContext(ctx).Errorf("Service: Failed to delete mfa: %v", err)
+			return err
+		}
+
+		return s.auditUser(ctx, actorID, entity.AuditActionMFAReset, user, user)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx).Infof("Service: Mfa of user %s reset", userID)
+	return nil
+}
+
+func (s *Service) getUser(ctx context.Context, userID uuid.UUID) (entity.User, error) {
+	user, err := s.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNoUserFound) {
+			return entity.User{}, ErrNoUserFound
+		}
+		logger.FromContext(ctx).Errorf("Service: Failed to get user: %v", err)
+		return entity.User{}, err
+	}
+	return user, nil
+}
+
+// getUserForUpdate is getUser that locks the user until the end of the
+// transaction, so concurrent changes of the user are applied one by one.
+func (s *Service) getUserForUpdate(ctx context.Context, userID uuid.UUID) (entity.User, error) {
+	user, err := s.userRepository.GetByIDForUpdate(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNoUserFound) {
+			return entity.User{}, ErrNoUserFound
+		}
+		logger.FromContext(ctx).Errorf("Service: Failed to get user: %v", err)
+		return entity.User{}, err
+	}
+	return user, nil
+}
+
+// revokeSessions revokes every session of the user together with the
+// access tokens issued for them. Must be called within a transaction.
+func (s *Service) revokeSessions(ctx context.Context, userID uuid.UUID) error {
+	sessions, err := s.sessionRepository.GetActiveByUser(ctx, userID)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to get sessions: %v", err)
+		return err
+	}
+
+	if err = s.sessionRepository.RevokeAllByUser(ctx, userID); err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to revoke sessions: %v", err)
+		return err
+	}
+
+	// Access tokens live for AccessTokenTTL after their last refresh
+	expiresAt := time.Now().Add(auth.AccessTokenTTL)
+	for _, session := range sessions {
+		if err = s.revoker.Revoke(ctx, auth.SessionRevocationID(session.ID), expiresAt); err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to revoke access tokens of session %s: %v", session.ID, err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) auditUser(ctx context.Context, actorID uuid.UUID, action entity.AuditAction, before entity.User, after entity.User) error {
+	return s.audit(ctx, actorID, action, entity.AuditTargetUser, after.ID, snapshotOf(before), snapshotOf(after))
+}
+
+// audit writes a record within the caller's transaction. Nil snapshots are
+// stored as NULL.
+func (s *Service) audit(ctx context.Context, actorID uuid.UUID, action entity.AuditAction, target entity.AuditTarget, targetID uuid.UUID, before any, after any) error {
+	record := entity.AuditRecord{
+		ActorID:    actorID,
+		Action:     action,
+		TargetType: target,
+		TargetID:   targetID,
+	}
+
+	var err error
+	if before != nil {
+		if record.Before, err = json.Marshal(before); err != nil {
+			return err
+		}
+	}
+	if after != nil {
+		if record.After, err = json.Marshal(after); err != nil {
+			return err
+		}
+	}
+
+	if err = s.auditRepository.Create(ctx, record); err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to write audit record: %v", err)
+	}
+	return err
+}
+
+func snapshotOf(user entity.User) userSnapshot {
+	return userSnapshot{Role: user.Role, Disabled: user.Disabled()}
+}
@@ -0,0 +1,492 @@
+package user_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/repository"
+	service "github.com/4udiwe/avito-pvz/internal/service/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// auditRecord matches an audit record on the user with the given snapshots.
+// An empty snapshot matches a record without one.
+func auditRecord(actorID uuid.UUID, action entity.AuditAction, userID uuid.UUID, before string, after string) gomock.Matcher {
+	return gomock.Cond(func(r entity.AuditRecord) bool {
+		return r.ActorID == actorID &&
+			r.Action == action &&
+			r.TargetType == entity.AuditTargetUser &&
+			r.TargetID == userID &&
+			jsonEqual(r.Before, before) &&
+			jsonEqual(r.After, after)
+	})
+}
+
+func jsonEqual(got json.RawMessage, want string) bool {
+	if want == "" {
+		return len(got) == 0
+	}
+	var a, b any
+	if json.Unmarshal(got, &a) != nil || json.Unmarshal([]byte(want), &b) != nil {
+		return false
+	}
+	return assert.ObjectsAreEqual(a, b)
+}
+
+func TestListUsers(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		arbitraryErr = errors.New("arbitrary error")
+		role         = entity.RoleEmployee
+		users        = []entity.User{{ID: uuid.New(), Email: "a@mail.com", Role: role}}
+	)
+
+	type MockBehavior func(m serviceMocks)
+
+	for _, tc := range []struct {
+		name         string
+		filter       entity.UserFilter
+		mockBehavior MockBehavior
+		want         []entity.User
+		wantErr      error
+	}{
+		{
+			name:   "defaults applied",
+			filter: entity.UserFilter{Query: "mail"},
+			mockBehavior: func(m serviceMocks) {
+				m.users.EXPECT().List(ctx, entity.UserFilter{Query: "mail", Page: 1, Limit: 20}).Return(users, nil).Times(1)
+			},
+			want: users,
+		},
+		{
+			name:   "limit capped",
+			filter: entity.UserFilter{Role: &role, Page: 3, Limit: 1000},
+			mockBehavior: func(m serviceMocks) {
+				m.users.EXPECT().List(ctx, entity.UserFilter{Role: &role, Page: 3, Limit: 100}).Return(users, nil).Times(1)
+			},
+			want: users,
+		},
+		{
+			name:   "repository error",
+			filter: entity.UserFilter{Page: 1, Limit: 10},
+			mockBehavior: func(m serviceMocks) {
+				m.users.EXPECT().List(ctx, entity.UserFilter{Page: 1, Limit: 10}).Return(nil, arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s, m := newService(ctrl)
+			tc.mockBehavior(m)
+
+			out, err := s.ListUsers(ctx, tc.filter)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.want, out)
+		})
+	}
+}
+
+func TestGetUser(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		userID = uuid.New()
+		user   = entity.User{ID: userID, Email: "a@mail.com", Role: entity.RoleEmployee}
+	)
+
+	type MockBehavior func(m serviceMocks)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		want         entity.User
+		wantErr      error
+	}{
+		{
+			name: "success",
+			mockBehavior: func(m serviceMocks) {
+				m.users.EXPECT().GetByID(ctx, userID).Return(user, nil).Times(1)
+			},
+			want: user,
+		},
+		{
+			name: "not found",
+			mockBehavior: func(m serviceMocks) {
+				m.users.EXPECT().GetByID(ctx, userID).Return(entity.User{}, repository.ErrNoUserFound).Times(1)
+			},
+			wantErr: service.ErrNoUserFound,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s, m := newService(ctrl)
+			tc.mockBehavior(m)
+
+			out, err := s.GetUser(ctx, userID)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.want, out)
+		})
+	}
+}
+
+func TestChangeRole(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		arbitraryErr = errors.New("arbitrary error")
+		actorID      = uuid.New()
+		userID       = uuid.New()
+		sessionID    = uuid.New()
+		user         = entity.User{ID: userID, Email: "a@mail.com", Role: entity.RoleEmployee}
+		promoted     = entity.User{ID: userID, Email: "a@mail.com", Role: entity.RoleModerator}
+	)
+
+	type MockBehavior func(m serviceMocks)
+
+	for _, tc := range []struct {
+		name         string
+		actorID      uuid.UUID
+		mockBehavior MockBehavior
+		want         entity.User
+		wantErr      error
+	}{
+		{
+			name:    "success",
+			actorID: actorID,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByIDForUpdate(ctx, userID).Return(user, nil).Times(1)
+				m.users.EXPECT().UpdateRole(ctx, userID, entity.RoleModerator).Return(nil).Times(1)
+				m.sessions.EXPECT().GetActiveByUser(ctx, userID).Return([]entity.Session{{ID: sessionID, UserID: userID}}, nil).Times(1)
+				m.sessions.EXPECT().RevokeAllByUser(ctx, userID).Return(nil).Times(1)
+				m.revoker.EXPECT().Revoke(ctx, auth.SessionRevocationID(sessionID), lockedUntil(auth.AccessTokenTTL)).Return(nil).Times(1)
+				m.audit.EXPECT().Create(ctx, auditRecord(actorID, entity.AuditActionUserRoleChanged, userID,
+					`{"role":"employee","disabled":false}`,
+					`{"role":"moderator","disabled":false}`,
+				)).Return(nil).Times(1)
+			},
+			want: promoted,
+		},
+		{
+			name:    "same role is not audited",
+			actorID: actorID,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByIDForUpdate(ctx, userID).Return(promoted, nil).Times(1)
+			},
+			want: promoted,
+		},
+		{
+			name:         "own role",
+			actorID:      userID,
+			mockBehavior: func(m serviceMocks) {},
+			wantErr:      service.ErrCannotModifySelf,
+		},
+		{
+			name:    "user not found",
+			actorID: actorID,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByIDForUpdate(ctx, userID).Return(entity.User{}, repository.ErrNoUserFound).Times(1)
+			},
+			wantErr: service.ErrNoUserFound,
+		},
+		{
+			name:    "unknown role",
+			actorID: actorID,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByIDForUpdate(ctx, userID).Return(user, nil).Times(1)
+				m.users.EXPECT().UpdateRole(ctx, userID, entity.RoleModerator).Return(repository.ErrNoRoleFound).Times(1)
+			},
+			wantErr: service.ErrUnknownRole,
+		},
+		{
+			name:    "audit error",
+			actorID: actorID,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByIDForUpdate(ctx, userID).Return(user, nil).Times(1)
+				m.users.EXPECT().UpdateRole(ctx, userID, entity.RoleModerator).Return(nil).Times(1)
+				m.sessions.EXPECT().GetActiveByUser(ctx, userID).Return(nil, nil).Times(1)
+				m.sessions.EXPECT().RevokeAllByUser(ctx, userID).Return(nil).Times(1)
+				m.audit.EXPECT().Create(ctx, gomock.Any()).Return(arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+		{
+			name:    "revoke sessions error",
+			actorID: actorID,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByIDForUpdate(ctx, userID).Return(user, nil).Times(1)
+				m.users.EXPECT().UpdateRole(ctx, userID, entity.RoleModerator).Return(nil).Times(1)
+				m.sessions.EXPECT().GetActiveByUser(ctx, userID).Return(nil, nil).Times(1)
+				m.sessions.EXPECT().RevokeAllByUser(ctx, userID).Return(arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+		{
+			name:    "revoke access tokens error",
+			actorID: actorID,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByIDForUpdate(ctx, userID).Return(user, nil).Times(1)
+				m.users.EXPECT().UpdateRole(ctx, userID, entity.RoleModerator).Return(nil).Times(1)
+				m.sessions.EXPECT().GetActiveByUser(ctx, userID).Return([]entity.Session{{ID: sessionID, UserID: userID}}, nil).Times(1)
+				m.sessions.EXPECT().RevokeAllByUser(ctx, userID).Return(nil).Times(1)
+				m.revoker.EXPECT().Revoke(ctx, auth.SessionRevocationID(sessionID), gomock.Any()).Return(arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s, m := newService(ctrl)
+			tc.mockBehavior(m)
+
+			out, err := s.ChangeRole(ctx, tc.actorID, userID, entity.RoleModerator)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.want, out)
+		})
+	}
+}
+
+func TestSetDisabled(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		arbitraryErr = errors.New("arbitrary error")
+		actorID      = uuid.New()
+		userID       = uuid.New()
+		disabledAt   = time.Now().Add(-time.Hour)
+		active       = entity.User{ID: userID, Role: entity.RoleEmployee}
+		disabled     = entity.User{ID: userID, Role: entity.RoleEmployee, DisabledAt: &disabledAt}
+		recently     = gomock.Cond(func(t *time.Time) bool { return t != nil && time.Since(*t) < time.Minute })
+	)
+
+	type MockBehavior func(m serviceMocks)
+
+	for _, tc := range []struct {
+		name         string
+		actorID      uuid.UUID
+		disable      bool
+		mockBehavior MockBehavior
+		wantDisabled bool
+		wantErr      error
+	}{
+		{
+			name:    "disable revokes sessions",
+			actorID: actorID,
+			disable: true,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByIDForUpdate(ctx, userID).Return(active, nil).Times(1)
+				m.users.EXPECT().SetDisabledAt(ctx, userID, recently).Return(nil).Times(1)
+				m.sessions.EXPECT().RevokeAllByUser(ctx, userID).Return(nil).Times(1)
+				m.audit.EXPECT().Create(ctx, auditRecord(actorID, entity.AuditActionUserDisabled, userID,
+					`{"role":"employee","disabled":false}`,
+					`{"role":"employee","disabled":true}`,
+				)).Return(nil).Times(1)
+				m.disabled.EXPECT().Set(userID, true).Times(1)
+			},
+			wantDisabled: true,
+		},
+		{
+			name:    "enable",
+			actorID: actorID,
+			disable: false,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByIDForUpdate(ctx, userID).Return(disabled, nil).Times(1)
+				m.users.EXPECT().SetDisabledAt(ctx, userID, (*time.Time)(nil)).Return(nil).Times(1)
+				m.audit.EXPECT().Create(ctx, auditRecord(actorID, entity.AuditActionUserEnabled, userID,
+					`{"role":"employee","disabled":true}`,
+					`{"role":"employee","disabled":false}`,
+				)).Return(nil).Times(1)
+				m.disabled.EXPECT().Set(userID, false).Times(1)
+			},
+			wantDisabled: false,
+		},
+		{
+			name:    "already disabled",
+			actorID: actorID,
+			disable: true,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByIDForUpdate(ctx, userID).Return(disabled, nil).Times(1)
+				m.disabled.EXPECT().Set(userID, true).Times(1)
+			},
+			wantDisabled: true,
+		},
+		{
+			name:         "self",
+			actorID:      userID,
+			disable:      true,
+			mockBehavior: func(m serviceMocks) {},
+			wantErr:      service.ErrCannotModifySelf,
+		},
+		{
+			name:    "revoke sessions error",
+			actorID: actorID,
+			disable: true,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByIDForUpdate(ctx, userID).Return(active, nil).Times(1)
+				m.users.EXPECT().SetDisabledAt(ctx, userID, recently).Return(nil).Times(1)
+				m.sessions.EXPECT().RevokeAllByUser(ctx, userID).Return(arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s, m := newService(ctrl)
+			tc.mockBehavior(m)
+
+			out, err := s.SetDisabled(ctx, tc.actorID, userID, tc.disable)
+			assert.ErrorIs(t, err, tc.wantErr)
+			if tc.wantErr == nil {
+				assert.Equal(t, userID, out.ID)
+				assert.Equal(t, tc.wantDisabled, out.Disabled())
+			}
+		})
+	}
+}
+
+func TestUnlockUser(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		actorID      = uuid.New()
+		userID       = uuid.New()
+		user         = entity.User{ID: userID, Email: "User@Mail.com", Role: entity.RoleEmployee}
+		arbitraryErr = errors.New("arbitrary error")
+	)
+
+	type MockBehavior func(m serviceMocks)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		wantErr      error
+	}{
+		{
+			name: "success",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByID(ctx, userID).Return(user, nil).Times(1)
+				m.failures.EXPECT().Reset(ctx, entity.LoginScopeEmail, "user@mail.com").Return(nil).Times(1)
+				m.audit.EXPECT().Create(ctx, auditRecord(actorID, entity.AuditActionUserUnlocked, userID,
+					`{"role":"employee","disabled":false}`,
+					`{"role":"employee","disabled":false}`,
+				)).Return(nil).Times(1)
+			},
+		},
+		{
+			name: "user not found",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByID(ctx, userID).Return(entity.User{}, repository.ErrNoUserFound).Times(1)
+			},
+			wantErr: service.ErrNoUserFound,
+		},
+		{
+			name: "repository error",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByID(ctx, userID).Return(user, nil).Times(1)
+				m.failures.EXPECT().Reset(ctx, entity.LoginScopeEmail, "user@mail.com").Return(arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s, m := newService(ctrl)
+			tc.mockBehavior(m)
+
+			err := s.UnlockUser(ctx, actorID, userID)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestResetMFA(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		actorID = uuid.New()
+		userID  = uuid.New()
+		user    = entity.User{ID: userID, Email: "user@mail.com", Role: entity.RoleModerator}
+	)
+
+	type MockBehavior func(m serviceMocks)
+
+	for _, tc := range []struct {
+		name         string
+		actorID      uuid.UUID
+		mockBehavior MockBehavior
+		wantErr      error
+	}{
+		{
+			name:    "success",
+			actorID: actorID,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByID(ctx, userID).Return(user, nil).Times(1)
+				m.mfa.EXPECT().Delete(ctx, userID).Return(nil).Times(1)
+				m.audit.EXPECT().Create(ctx, auditRecord(actorID, entity.AuditActionMFAReset, userID,
+					`{"role":"moderator","disabled":false}`,
+					`{"role":"moderator","disabled":false}`,
+				)).Return(nil).Times(1)
+			},
+		},
+		{
+			name:    "mfa not enabled",
+			actorID: actorID,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByID(ctx, userID).Return(user, nil).Times(1)
+				m.mfa.EXPECT().Delete(ctx, userID).Return(repository.ErrNoMFAFound).Times(1)
+			},
+			wantErr: service.ErrNoMFAFound,
+		},
+		{
+			name:         "self",
+			actorID:      userID,
+			mockBehavior: func(m serviceMocks) {},
+			wantErr:      service.ErrCannotModifySelf,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s, m := newService(ctrl)
+			tc.mockBehavior(m)
+
+			err := s.ResetMFA(ctx, tc.actorID, userID)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
@@ -2,9 +2,12 @@ package user
 
 import (
 	"context"
+	"time"
 
 	"github.com/4udiwe/avito-pvz/internal/auth"
 	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/pkg/notifier"
+	"github.com/4udiwe/avito-pvz/pkg/oidc"
 	"github.com/google/uuid"
 )
 
@@ -13,16 +16,113 @@ import (
 type UserRepository interface {
 	Create(ctx context.Context, user entity.User) (entity.User, error)
 	GetByEmail(ctx context.Context, email string) (entity.User, error)
-	UpdateRefreshToken(ctx context.Context, userID uuid.UUID, refreshToken string) (entity.User, error)
+	GetByID(ctx context.Context, userID uuid.UUID) (entity.User, error)
+	GetByIDForUpdate(ctx context.Context, userID uuid.UUID) (entity.User, error)
+	UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error
+	List(ctx context.Context, filter entity.UserFilter) ([]entity.User, error)
+	UpdateRole(ctx context.Context, userID uuid.UUID, role entity.UserRole) error
+	SetDisabledAt(ctx context.Context, userID uuid.UUID, disabledAt *time.Time) error
+	AssignPoints(ctx context.Context, userID uuid.UUID, pointIDs []uuid.UUID) error
+}
+
+type SessionRepository interface {
+	Create(ctx context.Context, session entity.Session) (entity.Session, error)
+	GetByIDForUpdate(ctx context.Context, sessionID uuid.UUID) (entity.Session, error)
+	GetActiveByUser(ctx context.Context, userID uuid.UUID) ([]entity.Session, error)
+	Rotate(ctx context.Context, session entity.Session) error
+	Revoke(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) error
+	RevokeAllByUser(ctx context.Context, userID uuid.UUID) error
+}
+
+type ResetTokenRepository interface {
+	Create(ctx context.Context, token entity.PasswordResetToken) (entity.PasswordResetToken, error)
+	InvalidateByUser(ctx context.Context, userID uuid.UUID) error
+	GetByHashForUpdate(ctx context.Context, tokenHash string) (entity.PasswordResetToken, error)
+	MarkUsed(ctx context.Context, tokenID uuid.UUID) error
+}
+
+type InvitationRepository interface {
+	Create(ctx context.Context, invitation entity.Invitation) (entity.Invitation, error)
+	GetByHashForUpdate(ctx context.Context, codeHash string) (entity.Invitation, error)
+	MarkUsed(ctx context.Context, invitationID uuid.UUID, userID uuid.UUID) error
 }
 
 type Auth interface {
-	GenerateTokens(user entity.User) (*auth.Tokens, error)
+	GenerateTokens(user entity.User, sessionID uuid.UUID) (*auth.Tokens, error)
 	ValidateAccessToken(tokenString string) (*auth.TokenClaims, error)
-	ValidateRefreshToken(tokenString string) (string, error)
+	ValidateRefreshToken(tokenString string) (*auth.RefreshClaims, error)
 }
 
 type Hasher interface {
 	HashPassword(password string) (string, error)
 	CheckPasswordHash(password, hash string) bool
+	NeedsRehash(hash string) bool
+}
+
+type TokenRevoker interface {
+	Revoke(ctx context.Context, jti string, expiresAt time.Time) error
+}
+
+type LoginFailureRepository interface {
+	GetForUpdate(ctx context.Context, scope entity.LoginScope, key string) (entity.LoginFailures, error)
+	RecordFailure(ctx context.Context, scope entity.LoginScope, key string, window time.Duration) (int, error)
+	Lock(ctx context.Context, scope entity.LoginScope, key string, until time.Time) error
+	Reset(ctx context.Context, scope entity.LoginScope, key string) error
+	DeleteStale(ctx context.Context, before time.Time) (int64, error)
+}
+
+type Metrics interface {
+	FailureInc(reason string)
+	LockoutInc(scope string)
+}
+
+type AuditRepository interface {
+	Create(ctx context.Context, record entity.AuditRecord) error
+}
+
+type DisabledUsers interface {
+	Set(userID uuid.UUID, disabled bool)
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, msg notifier.Message) error
+}
+
+type OIDCProvider interface {
+	AuthCodeURL(ctx context.Context, state string, nonce string, codeVerifier string) (string, error)
+	Exchange(ctx context.Context, code string, codeVerifier string) (string, error)
+	Verify(ctx context.Context, rawIDToken string, nonce string) (oidc.IDToken, error)
+}
+
+type OIDCStateRepository interface {
+	Create(ctx context.Context, state entity.OIDCLoginState) error
+	Take(ctx context.Context, stateHash string) (entity.OIDCLoginState, error)
+}
+
+type IdentityRepository interface {
+	Create(ctx context.Context, identity entity.UserIdentity) error
+	Get(ctx context.Context, issuer string, subject string) (entity.UserIdentity, error)
+}
+
+type MFARepository interface {
+	Get(ctx context.Context, userID uuid.UUID) (entity.UserMFA, error)
+	GetForUpdate(ctx context.Context, userID uuid.UUID) (entity.UserMFA, error)
+	SetPendingSecret(ctx context.Context, userID uuid.UUID, secret string) error
+	Confirm(ctx context.Context, userID uuid.UUID, step int64) error
+	SetLastUsedStep(ctx context.Context, userID uuid.UUID, step int64) error
+	Delete(ctx context.Context, userID uuid.UUID) error
+	ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes []string) error
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) error
+}
+
+type MFAChallengeRepository interface {
+	Create(ctx context.Context, challenge entity.MFAChallenge) (entity.MFAChallenge, error)
+	GetByHashForUpdate(ctx context.Context, tokenHash string) (entity.MFAChallenge, error)
+	IncrementAttempts(ctx context.Context, challengeID uuid.UUID) error
+	Delete(ctx context.Context, challengeID uuid.UUID) error
+}
+
+type Encryptor interface {
+	Encrypt(plaintext []byte, associatedData []byte) (string, error)
+	Decrypt(ciphertext string, associatedData []byte) ([]byte, error)
 }
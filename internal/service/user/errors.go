@@ -7,4 +7,23 @@ var (
 	ErrUserAlreadyExists   = errors.New("user already exists")
 	ErrInvalidRefreshToken = errors.New("invalid refresh token")
 	ErrInvalidCredentials  = errors.New("invalid credentials")
+	ErrNoSessionFound      = errors.New("no session found")
+	ErrInvalidResetToken   = errors.New("invalid or expired password reset token")
+	ErrUserDisabled        = errors.New("user is disabled")
+	ErrCannotModifySelf    = errors.New("cannot change own role or status")
+	ErrRegistrationClosed  = errors.New("registration requires an invitation")
+	ErrInvalidInvitation   = errors.New("invalid or expired invitation")
+	ErrNoPointFound        = errors.New("no point found")
+	ErrUnknownRole         = errors.New("unknown role")
+	ErrOIDCDisabled        = errors.New("single sign-on is disabled")
+	ErrInvalidOIDCState    = errors.New("invalid or expired login state")
+	ErrOIDCLoginFailed     = errors.New("identity provider login failed")
+	ErrNoRoleMapped        = errors.New("no role is mapped to the identity provider groups")
+	ErrInvalidMFAToken     = errors.New("invalid or expired mfa token")
+	ErrInvalidMFACode      = errors.New("invalid mfa code")
+	ErrMFANotEnrolled      = errors.New("mfa enrolment required")
+	ErrMFARequired         = errors.New("second factor required, log in again")
+	ErrNoMFAEnrollment     = errors.New("no mfa enrolment in progress")
+	ErrNoMFAFound          = errors.New("mfa is not enabled")
+	ErrMFAEnabled          = errors.New("mfa is already enabled")
 )
@@ -0,0 +1,154 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/repository"
+	"github.com/4udiwe/avito-pvz/pkg/hasher"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/4udiwe/avito-pvz/pkg/notifier"
+	"github.com/4udiwe/avito-pvz/pkg/redact"
+	"github.com/google/uuid"
+)
+
+// invitationSnapshot is what the audit log keeps of an invitation. The code
+// is never logged.
+type invitationSnapshot struct {
+	Email     string          `json:"email"`
+	Role      entity.UserRole `json:"role"`
+	PointIDs  []uuid.UUID     `json:"point_ids,omitempty"`
+	ExpiresAt time.Time       `json:"expires_at"`
+}
+
+// CreateInvitation issues a single-use invitation code and sends it to the
+// invitee. The code is returned once so the moderator can hand it over; only
+// its hash is stored.
+func (s *Service) CreateInvitation(ctx context.Context, actorID uuid.UUID, email string, role entity.UserRole, pointIDs []uuid.UUID) (entity.Invitation, string, error) {
+	logger.FromContext(ctx).Infof("Service: User %s invites %s with role %s", actorID, redact.Email(email), role)
+
+	code, err := generateToken(invitationTokenPrefix)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to generate invitation code: %v", err)
+		return entity.Invitation{}, "", err
+	}
+
+	var invitation entity.Invitation
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.userRepository.GetByEmail(ctx, email); !errors.Is(err, repository.ErrNoUserFound) {
+			if err != nil {
+				logger.FromContext(ctx).Errorf("Service: Failed to get user by email: %v", err)
+				return err
+			}
+			return ErrUserAlreadyExists
+		}
+
+		invitation, err = s.inviteRepository.Create(ctx, entity.Invitation{
+			Email:     email,
+			Role:      role,
+			PointIDs:  pointIDs,
+			CodeHash:  hasher.HashToken(code),
+			CreatedBy: actorID,
+			ExpiresAt: time.Now().Add(s.policy.InvitationTTL),
+		})
+		if err != nil {
+			if errors.Is(err, repository.ErrNoPointFound) {
+				return ErrNoPointFound
+			}
+			if errors.Is(err, repository.ErrNoRoleFound) {
+				return ErrUnknownRole
+			}
+			logger.FromContext(ctx).Errorf("Service: Failed to create invitation: %v", err)
+			return err
+		}
+
+		after, err := json.Marshal(invitationSnapshot{
+			Email:     invitation.Email,
+			Role:      invitation.Role,
+			PointIDs:  invitation.PointIDs,
+			ExpiresAt: invitation.ExpiresAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = s.auditRepository.Create(ctx, entity.AuditRecord{
+			ActorID:    actorID,
+			Action:     entity.AuditActionInvitationCreated,
+			TargetType: entity.AuditTargetInvitation,
+			TargetID:   invitation.ID,
+			After:      after,
+		})
+		if err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to write audit record: %v", err)
+			return err
+		}
+
+		return s.notifier.Notify(ctx, notifier.Message{
+			Recipient: email,
+			Subject:   "Invitation",
+			Body:      fmt.Sprintf("You are invited as %s. Your registration code (valid for %s): %s", role, s.policy.InvitationTTL, code),
+		})
+	})
+
+	if err != nil {
+		return entity.Invitation{}, "", err
+	}
+
+	logger.FromContext(ctx).Infof("Service: Invitation %s created", invitation.ID)
+	return invitation, code, nil
+}
+
+// useInvitation locks the invitation for the code and checks that it can be
+// used by email. Must be called within a transaction.
+func (s *Service) useInvitation(ctx context.Context, email string, code string) (entity.Invitation, error) {
+	invitation, err := s.inviteRepository.GetByHashForUpdate(ctx, hasher.HashToken(code))
+	if err != nil {
+		if errors.Is(err, repository.ErrNoInvitationFound) {
+			return entity.Invitation{}, ErrInvalidInvitation
+		}
+		logger.FromContext(ctx).Errorf("Service: Failed to get invitation: %v", err)
+		return entity.Invitation{}, err
+	}
+
+	if invitation.UsedAt != nil || !invitation.ExpiresAt.After(time.Now()) {
+		logger.FromContext(ctx).Warnf("Service: Invitation %s is used or expired", invitation.ID)
+		return entity.Invitation{}, ErrInvalidInvitation
+	}
+	if !strings.EqualFold(invitation.Email, email) {
+		logger.FromContext(ctx).Warnf("Service: Invitation %s is issued for another email", invitation.ID)
+		return entity.Invitation{}, ErrInvalidInvitation
+	}
+
+	return invitation, nil
+}
+
+// acceptInvitation marks the invitation used by the new user and applies its
+// point assignments. Must be called within a transaction.
+func (s *Service) acceptInvitation(ctx context.Context, invitation entity.Invitation, userID uuid.UUID) error {
+	if err := s.inviteRepository.MarkUsed(ctx, invitation.ID, userID); err != nil {
+		if errors.Is(err, repository.ErrNoInvitationFound) {
+			return ErrInvalidInvitation
+		}
+		logger.FromContext(ctx).Errorf("Service: Failed to mark invitation used: %v", err)
+		return err
+	}
+
+	if len(invitation.PointIDs) == 0 {
+		return nil
+	}
+
+	if err := s.userRepository.AssignPoints(ctx, userID, invitation.PointIDs); err != nil {
+		if errors.Is(err, repository.ErrNoPointFound) {
+			return ErrNoPointFound
+		}
+		logger.FromContext(ctx).Errorf("Service: Failed to assign points: %v", err)
+		return err
+	}
+	return nil
+}
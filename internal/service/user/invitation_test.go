@@ -0,0 +1,324 @@
+package user_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/repository"
+	service "github.com/4udiwe/avito-pvz/internal/service/user"
+	"github.com/4udiwe/avito-pvz/pkg/hasher"
+	"github.com/4udiwe/avito-pvz/pkg/notifier"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreateInvitation(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		arbitraryErr = errors.New("arbitrary error")
+		moderatorID  = uuid.New()
+		invitationID = uuid.New()
+		email        = "new@mail.com"
+		pointIDs     = []uuid.UUID{uuid.New()}
+	)
+
+	newInvitation := gomock.Cond(func(x any) bool {
+		inv, ok := x.(entity.Invitation)
+		return ok &&
+			inv.Email == email &&
+			inv.Role == entity.RoleModerator &&
+			assert.ObjectsAreEqual(pointIDs, inv.PointIDs) &&
+			inv.CreatedBy == moderatorID &&
+			len(inv.CodeHash) == 64 &&
+			time.Until(inv.ExpiresAt) > invitationTTL-time.Minute
+	})
+	created := func(x any) (entity.Invitation, error) {
+		inv := x.(entity.Invitation)
+		inv.ID = invitationID
+		return inv, nil
+	}
+
+	type MockBehavior func(m serviceMocks)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		wantErr      error
+	}{
+		{
+			name: "success",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByEmail(ctx, email).Return(entity.User{}, repository.ErrNoUserFound).Times(1)
+				m.invites.EXPECT().Create(ctx, newInvitation).DoAndReturn(func(_ context.Context, inv entity.Invitation) (entity.Invitation, error) {
+					return created(inv)
+				}).Times(1)
+				m.audit.EXPECT().Create(ctx, gomock.Cond(func(x any) bool {
+					r, ok := x.(entity.AuditRecord)
+					return ok && r.ActorID == moderatorID &&
+						r.Action == entity.AuditActionInvitationCreated &&
+						r.TargetType == entity.AuditTargetInvitation &&
+						r.TargetID == invitationID &&
+						r.Before == nil &&
+						strings.Contains(string(r.After), `"role":"moderator"`)
+				})).Return(nil).Times(1)
+				m.notifier.EXPECT().Notify(ctx, gomock.Cond(func(x any) bool {
+					msg, ok := x.(notifier.Message)
+					return ok && msg.Recipient == email
+				})).Return(nil).Times(1)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "user already exists",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByEmail(ctx, email).Return(entity.User{Email: email}, nil).Times(1)
+			},
+			wantErr: service.ErrUserAlreadyExists,
+		},
+		{
+			name: "get user error",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByEmail(ctx, email).Return(entity.User{}, arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+		{
+			name: "no point found",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByEmail(ctx, email).Return(entity.User{}, repository.ErrNoUserFound).Times(1)
+				m.invites.EXPECT().Create(ctx, newInvitation).Return(entity.Invitation{}, repository.ErrNoPointFound).Times(1)
+			},
+			wantErr: service.ErrNoPointFound,
+		},
+		{
+			name: "unknown role",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByEmail(ctx, email).Return(entity.User{}, repository.ErrNoUserFound).Times(1)
+				m.invites.EXPECT().Create(ctx, newInvitation).Return(entity.Invitation{}, repository.ErrNoRoleFound).Times(1)
+			},
+			wantErr: service.ErrUnknownRole,
+		},
+		{
+			name: "audit error",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByEmail(ctx, email).Return(entity.User{}, repository.ErrNoUserFound).Times(1)
+				m.invites.EXPECT().Create(ctx, newInvitation).DoAndReturn(func(_ context.Context, inv entity.Invitation) (entity.Invitation, error) {
+					return created(inv)
+				}).Times(1)
+				m.audit.EXPECT().Create(ctx, gomock.Any()).Return(arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+		{
+			name: "notify error",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByEmail(ctx, email).Return(entity.User{}, repository.ErrNoUserFound).Times(1)
+				m.invites.EXPECT().Create(ctx, newInvitation).DoAndReturn(func(_ context.Context, inv entity.Invitation) (entity.Invitation, error) {
+					return created(inv)
+				}).Times(1)
+				m.audit.EXPECT().Create(ctx, gomock.Any()).Return(nil).Times(1)
+				m.notifier.EXPECT().Notify(ctx, gomock.Any()).Return(arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s, m := newService(ctrl)
+			tc.mockBehavior(m)
+
+			out, code, err := s.CreateInvitation(ctx, moderatorID, email, entity.RoleModerator, pointIDs)
+			assert.ErrorIs(t, err, tc.wantErr)
+			if tc.wantErr == nil {
+				assert.Equal(t, invitationID, out.ID)
+				assert.Equal(t, hasher.HashToken(code), out.CodeHash)
+				assert.True(t, strings.HasPrefix(code, "pvzi_"), code)
+			} else {
+				assert.Empty(t, code)
+			}
+		})
+	}
+}
+
+func TestRegisterWithInvitation(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		arbitraryErr = errors.New("arbitrary error")
+		email        = "invited@mail.com"
+		password     = "12345678"
+		code         = "invitation-code"
+		userID       = uuid.New()
+		pointIDs     = []uuid.UUID{uuid.New(), uuid.New()}
+		hash         = "hashed_password_123"
+		usedAt       = time.Now().Add(-time.Hour)
+		invitation   = entity.Invitation{
+			ID:        uuid.New(),
+			Email:     "Invited@Mail.com",
+			Role:      entity.RoleModerator,
+			PointIDs:  pointIDs,
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+		closed = policy
+	)
+	closed.OpenRegistration = false
+
+	withInvitation := func(inv entity.Invitation) func(m serviceMocks) {
+		return func(m serviceMocks) {
+			m.invites.EXPECT().GetByHashForUpdate(ctx, hasher.HashToken(code)).Return(inv, nil).Times(1)
+		}
+	}
+	createModerator := func(m serviceMocks) entity.User {
+		m.users.EXPECT().GetByEmail(ctx, email).Return(entity.User{}, repository.ErrNoUserFound).Times(1)
+		m.hasher.EXPECT().HashPassword(password).Return(hash, nil).Times(1)
+		toCreate := entity.User{Email: email, PasswordHash: hash, Role: entity.RoleModerator}
+		created := toCreate
+		created.ID = userID
+		m.users.EXPECT().Create(ctx, toCreate).Return(created, nil).Times(1)
+		return created
+	}
+
+	type MockBehavior func(m serviceMocks)
+
+	for _, tc := range []struct {
+		name         string
+		role         entity.UserRole
+		code         string
+		mockBehavior MockBehavior
+		want         entity.User
+		wantErr      error
+	}{
+		{
+			name:         "registration closed",
+			role:         entity.RoleModerator,
+			code:         "",
+			mockBehavior: func(m serviceMocks) {},
+			want:         entity.User{},
+			wantErr:      service.ErrRegistrationClosed,
+		},
+		{
+			name: "role from invitation",
+			role: entity.RoleEmployee,
+			code: code,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				withInvitation(invitation)(m)
+				createModerator(m)
+				m.invites.EXPECT().MarkUsed(ctx, invitation.ID, userID).Return(nil).Times(1)
+				m.users.EXPECT().AssignPoints(ctx, userID, pointIDs).Return(nil).Times(1)
+				m.audit.EXPECT().Create(ctx, auditRecord(userID, entity.AuditActionUserRegistered, userID,
+					"", `{"role":"moderator","disabled":false}`)).Return(nil).Times(1)
+			},
+			want:    entity.User{ID: userID, Email: email, PasswordHash: hash, Role: entity.RoleModerator},
+			wantErr: nil,
+		},
+		{
+			name: "unknown code",
+			code: code,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.invites.EXPECT().GetByHashForUpdate(ctx, hasher.HashToken(code)).Return(entity.Invitation{}, repository.ErrNoInvitationFound).Times(1)
+			},
+			want:    entity.User{},
+			wantErr: service.ErrInvalidInvitation,
+		},
+		{
+			name: "used invitation",
+			code: code,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				used := invitation
+				used.UsedAt = &usedAt
+				withInvitation(used)(m)
+			},
+			want:    entity.User{},
+			wantErr: service.ErrInvalidInvitation,
+		},
+		{
+			name: "expired invitation",
+			code: code,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				expired := invitation
+				expired.ExpiresAt = time.Now().Add(-time.Minute)
+				withInvitation(expired)(m)
+			},
+			want:    entity.User{},
+			wantErr: service.ErrInvalidInvitation,
+		},
+		{
+			name: "invitation for another email",
+			code: code,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				other := invitation
+				other.Email = "other@mail.com"
+				withInvitation(other)(m)
+			},
+			want:    entity.User{},
+			wantErr: service.ErrInvalidInvitation,
+		},
+		{
+			name: "get invitation error",
+			code: code,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.invites.EXPECT().GetByHashForUpdate(ctx, hasher.HashToken(code)).Return(entity.Invitation{}, arbitraryErr).Times(1)
+			},
+			want:    entity.User{},
+			wantErr: arbitraryErr,
+		},
+		{
+			name: "invitation used concurrently",
+			code: code,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				withInvitation(invitation)(m)
+				createModerator(m)
+				m.invites.EXPECT().MarkUsed(ctx, invitation.ID, userID).Return(repository.ErrNoInvitationFound).Times(1)
+			},
+			want:    entity.User{},
+			wantErr: service.ErrInvalidInvitation,
+		},
+		{
+			name: "assign points error",
+			code: code,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				withInvitation(invitation)(m)
+				createModerator(m)
+				m.invites.EXPECT().MarkUsed(ctx, invitation.ID, userID).Return(nil).Times(1)
+				m.users.EXPECT().AssignPoints(ctx, userID, pointIDs).Return(arbitraryErr).Times(1)
+			},
+			want:    entity.User{},
+			wantErr: arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s, m := newServiceWithPolicy(ctrl, closed)
+			tc.mockBehavior(m)
+
+			out, err := s.Register(ctx, email, password, tc.role, tc.code)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.want, out)
+		})
+	}
+}
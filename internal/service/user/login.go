@@ -0,0 +1,141 @@
+package user
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+)
+
+const (
+	failureReasonInvalidCredentials = "invalid_credentials"
+	failureReasonLocked             = "locked"
+)
+
+// LoginPolicy controls login throttling. After every failure the key is
+// locked for BaseDelay doubled per previous failure; once MaxFailures is
+// reached it is locked for LockDuration. Failures older than FailureWindow
+// are forgotten.
+type LoginPolicy struct {
+	MaxEmailFailures int
+	MaxIPFailures    int
+	BaseDelay        time.Duration
+	LockDuration     time.Duration
+	FailureWindow    time.Duration
+}
+
+// lockFor returns how long to lock a key after its n-th failure, and
+// whether that is a full lockout.
+func (p LoginPolicy) lockFor(failures int, maxFailures int) (time.Duration, bool) {
+	if failures >= maxFailures {
+		return p.LockDuration, true
+	}
+	delay := p.BaseDelay
+	for i := 1; i < failures && delay < p.LockDuration; i++ {
+		delay *= 2
+	}
+	return min(delay, p.LockDuration), false
+}
+
+func (p LoginPolicy) maxFailures(scope entity.LoginScope) int {
+	if scope == entity.LoginScopeIP {
+		return p.MaxIPFailures
+	}
+	return p.MaxEmailFailures
+}
+
+func (s *Service) isLocked(ctx context.Context, keys []loginKey) (bool, error) {
+	now := time.Now()
+	for _, k := range keys {
+		failures, err := s.failureRepository.GetForUpdate(ctx, k.scope, k.key)
+		if err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to get login failures: %v", err)
+			return false, err
+		}
+		if failures.LockedUntil != nil && now.Before(*failures.LockedUntil) {
+			logger.FromContext(ctx).Warnf("Service: Logins for %s %s are locked until %s", k.scope, k.key, failures.LockedUntil)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *Service) recordFailure(ctx context.Context, keys []loginKey) error {
+	now := time.Now()
+	for _, k := range keys {
+		failures, err := s.failureRepository.RecordFailure(ctx, k.scope, k.key, s.policy.Login.FailureWindow)
+		if err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to record login failure: %v", err)
+			return err
+		}
+
+		delay, lockout := s.policy.Login.lockFor(failures, s.policy.Login.maxFailures(k.scope))
+		if lockout {
+			logger.FromContext(ctx).Warnf("Service: Too many login failures for %s %s, locking", k.scope, k.key)
+			s.metrics.LockoutInc(string(k.scope))
+		}
+		if err = s.failureRepository.Lock(ctx, k.scope, k.key, now.Add(delay)); err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to lock logins: %v", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// resetFailures clears the email counter after a completed login. The IP
+// counter is kept: a correct password for one account says nothing about
+// the guesses from the address for others.
+func (s *Service) resetFailures(ctx context.Context, keys []loginKey) error {
+	if err := s.failureRepository.Reset(ctx, keys[0].scope, keys[0].key); err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to reset login failures: %v", err)
+		return err
+	}
+	return nil
+}
+
+// PruneLoginFailures deletes counters that are past the failure window and
+// not locked; they would be restarted from zero anyway.
+func (s *Service) PruneLoginFailures(ctx context.Context) error {
+	if _, err := s.failureRepository.DeleteStale(ctx, time.Now().Add(-s.policy.Login.FailureWindow)); err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to prune login failures: %v", err)
+		return err
+	}
+	return nil
+}
+
+// RunLoginFailureCleanup prunes login failures every interval until ctx is
+// cancelled.
+func (s *Service) RunLoginFailureCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = s.PruneLoginFailures(ctx)
+		}
+	}
+}
+
+type loginKey struct {
+	scope entity.LoginScope
+	key   string
+}
+
+// loginKeys gives the keys failures are counted by, email first. Requests
+// without an IP are counted by email only.
+func loginKeys(email string, client entity.ClientInfo) []loginKey {
+	keys := []loginKey{{scope: entity.LoginScopeEmail, key: normalizeEmail(email)}}
+	if client.IP != "" {
+		keys = append(keys, loginKey{scope: entity.LoginScopeIP, key: client.IP})
+	}
+	return keys
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
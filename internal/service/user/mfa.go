@@ -0,0 +1,509 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/repository"
+	"github.com/4udiwe/avito-pvz/pkg/hasher"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/4udiwe/avito-pvz/pkg/totp"
+	"github.com/google/uuid"
+	"github.com/skip2/go-qrcode"
+)
+
+const (
+	failureReasonInvalidMFACode = "invalid_mfa_code"
+
+	// mfaSkew is how many 30 second periods a code may be off by
+	mfaSkew = 1
+
+	recoveryCodeBytes = 10
+	qrCodeScale       = 6
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// MFAPolicy controls the TOTP second factor. Users with a role in
+// RequiredRoles cannot get tokens with a password alone: they enrol during
+// their next login. Others may enrol at any time.
+type MFAPolicy struct {
+	Issuer        string
+	RequiredRoles []entity.UserRole
+	ChallengeTTL  time.Duration
+	MaxAttempts   int
+	RecoveryCodes int
+}
+
+func (p MFAPolicy) required(role entity.UserRole) bool {
+	return slices.Contains(p.RequiredRoles, role)
+}
+
+// LoginResult is what a password login gives: either Tokens or, when a
+// second factor is needed, MFA.
+type LoginResult struct {
+	Tokens *auth.Tokens
+	MFA    *MFAPending
+}
+
+// MFAPending is a short-lived MFA pending token, exchanged for tokens with
+// VerifyMFA. With EnrollmentRequired the user has no second factor yet and
+// must first enrol with BeginMFAEnrollmentByToken.
+type MFAPending struct {
+	Token              string
+	ExpiresAt          time.Time
+	EnrollmentRequired bool
+}
+
+// login finishes a login that passed the first factor: a password or the
+// identity provider. secondFactor tells that the provider already checked a
+// second factor, see OIDCPolicy.MFAMethods. Must be called within a
+// transaction.
+func (s *Service) login(ctx context.Context, user entity.User, client entity.ClientInfo, secondFactor bool) (*LoginResult, error) {
+	if secondFactor {
+		tokens, err := s.startSession(ctx, user, client, true)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{Tokens: tokens}, nil
+	}
+
+	mfa, err := s.mfaRepository.Get(ctx, user.ID)
+	if err != nil && !errors.Is(err, repository.ErrNoMFAFound) {
+		logger.FromContext(ctx).Errorf("Service: Failed to get mfa: %v", err)
+		return nil, err
+	}
+	enabled := err == nil && mfa.Enabled()
+
+	if !enabled && !s.policy.MFA.required(user.Role) {
+		tokens, err := s.startSession(ctx, user, client, false)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{Tokens: tokens}, nil
+	}
+
+	token, err := generateToken(mfaTokenPrefix)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to generate mfa token: %v", err)
+		return nil, err
+	}
+
+	challenge, err := s.mfaChallenges.Create(ctx, entity.MFAChallenge{
+		TokenHash: hasher.HashToken(token),
+		UserID:    user.ID,
+		ExpiresAt: time.Now().Add(s.policy.MFA.ChallengeTTL),
+	})
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to create mfa challenge: %v", err)
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Infof("Service: User %s must pass the second factor", user.ID)
+	return &LoginResult{MFA: &MFAPending{
+		Token:              token,
+		ExpiresAt:          challenge.ExpiresAt,
+		EnrollmentRequired: !enabled,
+	}}, nil
+}
+
+// VerifyMFA checks a TOTP or recovery code for an MFA pending token and
+// starts a session. If the user was enrolling, the code confirms the
+// enrolment and the new recovery codes are returned as well; they are
+// shown only once. A token allows Policy.MFA.MaxAttempts wrong codes, and
+// every wrong code counts as a failed login of the user, so new tokens do
+// not give new guesses; see LoginPolicy.
+func (s *Service) VerifyMFA(ctx context.Context, mfaToken string, code string, client entity.ClientInfo) (*auth.Tokens, []string, error) {
+	logger.FromContext(ctx).Info("Service: Verifying second factor")
+
+	var (
+		tokens        *auth.Tokens
+		recoveryCodes []string
+		codeErr       error
+	)
+
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		challenge, user, err := s.getChallenge(ctx, mfaToken)
+		if err != nil {
+			return err
+		}
+
+		keys := loginKeys(user.Email, client)
+		locked, err := s.isLocked(ctx, keys)
+		if err != nil {
+			return err
+		}
+		if locked {
+			s.metrics.FailureInc(failureReasonLocked)
+			codeErr = ErrInvalidMFACode
+			return nil
+		}
+
+		mfa, err := s.mfaRepository.GetForUpdate(ctx, user.ID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNoMFAFound) {
+				return ErrMFANotEnrolled
+			}
+			logger.FromContext(ctx).Errorf("Service: Failed to get mfa: %v", err)
+			return err
+		}
+
+		var ok bool
+		switch {
+		case mfa.Enabled():
+			ok, err = s.checkSecondFactor(ctx, mfa, code)
+		case mfa.PendingSecret != "":
+			recoveryCodes, ok, err = s.confirmEnrollment(ctx, user, mfa, code)
+		default:
+			return ErrMFANotEnrolled
+		}
+		if err != nil {
+			return err
+		}
+
+		// Failed attempts must be persisted, so the transaction is
+		// committed and the error is returned afterwards
+		if !ok {
+			logger.FromContext(ctx).Warnf("Service: Invalid mfa code for user %s", user.ID)
+			s.metrics.FailureInc(failureReasonInvalidMFACode)
+			codeErr = ErrInvalidMFACode
+			if err := s.mfaChallenges.IncrementAttempts(ctx, challenge.ID); err != nil {
+				logger.FromContext(ctx).Errorf("Service: Failed to count mfa attempt: %v", err)
+				return err
+			}
+			return s.recordFailure(ctx, keys)
+		}
+
+		if err := s.mfaChallenges.Delete(ctx, challenge.ID); err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to delete mfa challenge: %v", err)
+			return err
+		}
+
+		if err := s.resetFailures(ctx, keys); err != nil {
+			return err
+		}
+
+		tokens, err = s.startSession(ctx, user, client, true)
+		return err
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+	if codeErr != nil {
+		return nil, nil, codeErr
+	}
+
+	logger.FromContext(ctx).Info("Service: Second factor verified")
+	return tokens, recoveryCodes, nil
+}
+
+// BeginMFAEnrollment generates a new secret for the user. It is used for
+// logins only after ConfirmMFAEnrollment, so starting over, for example
+// with a new phone, does not lock the user out. A user who has a second
+// factor must pass it first with code, a TOTP or recovery code; wrong codes
+// count as failed logins, as in VerifyMFA.
+func (s *Service) BeginMFAEnrollment(ctx context.Context, userID uuid.UUID, code string) (entity.MFAEnrollment, error) {
+	logger.FromContext(ctx).Infof("Service: Starting mfa enrolment of user %s", userID)
+
+	var (
+		enrollment entity.MFAEnrollment
+		codeErr    error
+	)
+
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		user, err := s.getUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		mfa, err := s.mfaRepository.GetForUpdate(ctx, userID)
+		if err != nil && !errors.Is(err, repository.ErrNoMFAFound) {
+			logger.FromContext(ctx).Errorf("Service: Failed to get mfa: %v", err)
+			return err
+		}
+
+		if err == nil && mfa.Enabled() {
+			keys := loginKeys(user.Email, entity.ClientInfo{})
+			locked, err := s.isLocked(ctx, keys)
+			if err != nil {
+				return err
+			}
+
+			ok := false
+			if !locked && code != "" {
+				if ok, err = s.checkSecondFactor(ctx, mfa, code); err != nil {
+					return err
+				}
+			}
+			// Failed attempts must be persisted, so the transaction is
+			// committed and the error is returned afterwards
+			if !ok {
+				logger.FromContext(ctx).Warnf("Service: Invalid mfa code to replace the second factor of user %s", userID)
+				s.metrics.FailureInc(failureReasonInvalidMFACode)
+				codeErr = ErrInvalidMFACode
+				if locked {
+					return nil
+				}
+				return s.recordFailure(ctx, keys)
+			}
+		}
+
+		enrollment, err = s.beginEnrollment(ctx, user)
+		return err
+	})
+
+	if err != nil {
+		return entity.MFAEnrollment{}, err
+	}
+	if codeErr != nil {
+		return entity.MFAEnrollment{}, codeErr
+	}
+	return enrollment, nil
+}
+
+// BeginMFAEnrollmentByToken is BeginMFAEnrollment for a user who has only
+// an MFA pending token, because their role requires a second factor they
+// do not have yet. The enrolment is confirmed with VerifyMFA. Users who
+// already have a second factor must log in with it to replace it.
+func (s *Service) BeginMFAEnrollmentByToken(ctx context.Context, mfaToken string) (entity.MFAEnrollment, error) {
+	logger.FromContext(ctx).Info("Service: Starting mfa enrolment during login")
+
+	var enrollment entity.MFAEnrollment
+
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		_, user, err := s.getChallenge(ctx, mfaToken)
+		if err != nil {
+			return err
+		}
+
+		mfa, err := s.mfaRepository.GetForUpdate(ctx, user.ID)
+		if err != nil && !errors.Is(err, repository.ErrNoMFAFound) {
+			logger.FromContext(ctx).Errorf("Service: Failed to get mfa: %v", err)
+			return err
+		}
+		if err == nil && mfa.Enabled() {
+			return ErrMFAEnabled
+		}
+
+		enrollment, err = s.beginEnrollment(ctx, user)
+		return err
+	})
+
+	if err != nil {
+		return entity.MFAEnrollment{}, err
+	}
+	return enrollment, nil
+}
+
+// ConfirmMFAEnrollment enables the secret from BeginMFAEnrollment once
+// the user proves it works, and returns new recovery codes. They replace
+// any previous ones and are shown only once.
+func (s *Service) ConfirmMFAEnrollment(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	logger.FromContext(ctx).Infof("Service: Confirming mfa enrolment of user %s", userID)
+
+	var recoveryCodes []string
+
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		user, err := s.getUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		mfa, err := s.mfaRepository.GetForUpdate(ctx, userID)
+		if err != nil && !errors.Is(err, repository.ErrNoMFAFound) {
+			logger.FromContext(ctx).Errorf("Service: Failed to get mfa: %v", err)
+			return err
+		}
+		if err != nil || mfa.PendingSecret == "" {
+			return ErrNoMFAEnrollment
+		}
+
+		codes, ok, err := s.confirmEnrollment(ctx, user, mfa, code)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrInvalidMFACode
+		}
+		recoveryCodes = codes
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Infof("Service: Mfa of user %s enabled", userID)
+	return recoveryCodes, nil
+}
+
+// getChallenge returns a valid challenge for the token and its user. Must
+// be called within a transaction.
+func (s *Service) getChallenge(ctx context.Context, mfaToken string) (entity.MFAChallenge, entity.User, error) {
+	challenge, err := s.mfaChallenges.GetByHashForUpdate(ctx, hasher.HashToken(mfaToken))
+	if err != nil {
+		if errors.Is(err, repository.ErrNoMFAChallengeFound) {
+			return entity.MFAChallenge{}, entity.User{}, ErrInvalidMFAToken
+		}
+		logger.FromContext(ctx).Errorf("Service: Failed to get mfa challenge: %v", err)
+		return entity.MFAChallenge{}, entity.User{}, err
+	}
+
+	if !challenge.ExpiresAt.After(time.Now()) || challenge.Attempts >= s.policy.MFA.MaxAttempts {
+		logger.FromContext(ctx).Warnf("Service: Mfa challenge %s is expired or used up", challenge.ID)
+		return entity.MFAChallenge{}, entity.User{}, ErrInvalidMFAToken
+	}
+
+	user, err := s.userRepository.GetByID(ctx, challenge.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNoUserFound) {
+			return entity.MFAChallenge{}, entity.User{}, ErrInvalidMFAToken
+		}
+		logger.FromContext(ctx).Errorf("Service: Failed to get user: %v", err)
+		return entity.MFAChallenge{}, entity.User{}, err
+	}
+
+	if user.Disabled() {
+		logger.FromContext(ctx).Warnf("Service: Second factor of disabled user %s", user.ID)
+		return entity.MFAChallenge{}, entity.User{}, ErrUserDisabled
+	}
+
+	return challenge, user, nil
+}
+
+func (s *Service) beginEnrollment(ctx context.Context, user entity.User) (entity.MFAEnrollment, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to generate mfa secret: %v", err)
+		return entity.MFAEnrollment{}, err
+	}
+
+	encrypted, err := s.encryptor.Encrypt([]byte(secret), user.ID[:])
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to encrypt mfa secret: %v", err)
+		return entity.MFAEnrollment{}, err
+	}
+
+	if err = s.mfaRepository.SetPendingSecret(ctx, user.ID, encrypted); err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to store mfa secret: %v", err)
+		return entity.MFAEnrollment{}, err
+	}
+
+	uri := totp.URI(s.policy.MFA.Issuer, user.Email, secret)
+
+	// A negative size sets the pixels per module rather than the image size
+	png, err := qrcode.Encode(uri, qrcode.Medium, -qrCodeScale)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to encode qr code: %v", err)
+		return entity.MFAEnrollment{}, err
+	}
+
+	logger.FromContext(ctx).Infof("Service: Mfa enrolment of user %s started", user.ID)
+	return entity.MFAEnrollment{Secret: secret, URI: uri, QRCode: png}, nil
+}
+
+// confirmEnrollment checks code against the pending secret and, if it
+// matches, enables it. Must be called within a transaction.
+func (s *Service) confirmEnrollment(ctx context.Context, user entity.User, mfa entity.UserMFA, code string) ([]string, bool, error) {
+	secret, err := s.encryptor.Decrypt(mfa.PendingSecret, user.ID[:])
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to decrypt mfa secret: %v", err)
+		return nil, false, err
+	}
+
+	step, ok := totp.Validate(string(secret), code, time.Now(), mfaSkew)
+	if !ok {
+		return nil, false, nil
+	}
+
+	if err = s.mfaRepository.Confirm(ctx, user.ID, step); err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to confirm mfa: %v", err)
+		return nil, false, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes(s.policy.MFA.RecoveryCodes)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to generate recovery codes: %v", err)
+		return nil, false, err
+	}
+	if err = s.mfaRepository.ReplaceRecoveryCodes(ctx, user.ID, hashes); err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to store recovery codes: %v", err)
+		return nil, false, err
+	}
+
+	if err = s.auditUser(ctx, user.ID, entity.AuditActionMFAEnabled, user, user); err != nil {
+		return nil, false, err
+	}
+
+	return codes, true, nil
+}
+
+// checkSecondFactor accepts a TOTP code not older than the last accepted
+// one, or an unused recovery code. Must be called within a transaction.
+func (s *Service) checkSecondFactor(ctx context.Context, mfa entity.UserMFA, code string) (bool, error) {
+	secret, err := s.encryptor.Decrypt(mfa.Secret, mfa.UserID[:])
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to decrypt mfa secret: %v", err)
+		return false, err
+	}
+
+	if step, ok := totp.Validate(string(secret), code, time.Now(), mfaSkew); ok {
+		// A code seen once may have been observed, so it is not accepted again
+		if step <= mfa.LastUsedStep {
+			logger.FromContext(ctx).Warnf("Service: Reused mfa code for user %s", mfa.UserID)
+			return false, nil
+		}
+		if err := s.mfaRepository.SetLastUsedStep(ctx, mfa.UserID, step); err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to store mfa step: %v", err)
+			return false, err
+		}
+		return true, nil
+	}
+
+	err = s.mfaRepository.UseRecoveryCode(ctx, mfa.UserID, hasher.HashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		if errors.Is(err, repository.ErrNoRecoveryCodeFound) {
+			return false, nil
+		}
+		logger.FromContext(ctx).Errorf("Service: Failed to use recovery code: %v", err)
+		return false, err
+	}
+
+	logger.FromContext(ctx).Warnf("Service: User %s logged in with a recovery code", mfa.UserID)
+	return true, nil
+}
+
+// generateRecoveryCodes returns n codes formatted for reading, such as
+// ABCD-EFGH-IJKL-MNOP, and the hashes of their normalized form.
+func generateRecoveryCodes(n int) ([]string, []string, error) {
+	codes := make([]string, 0, n)
+	hashes := make([]string, 0, n)
+	for range n {
+		b := make([]byte, recoveryCodeBytes)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := recoveryCodeEncoding.EncodeToString(b)
+
+		groups := make([]string, 0, len(raw)/4)
+		for i := 0; i < len(raw); i += 4 {
+			groups = append(groups, raw[i:min(i+4, len(raw))])
+		}
+
+		codes = append(codes, strings.Join(groups, "-"))
+		hashes = append(hashes, hasher.HashToken(raw))
+	}
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
@@ -0,0 +1,713 @@
+package user_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/repository"
+	service "github.com/4udiwe/avito-pvz/internal/service/user"
+	"github.com/4udiwe/avito-pvz/pkg/hasher"
+	"github.com/4udiwe/avito-pvz/pkg/totp"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	mfaSecret    = "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+	mfaEncrypted = "encrypted secret"
+	mfaToken     = "mfa pending token"
+)
+
+func mfaPolicy() service.Policy {
+	p := policy
+	p.MFA = service.MFAPolicy{
+		Issuer:        "avito-pvz",
+		RequiredRoles: []entity.UserRole{entity.RoleModerator},
+		ChallengeTTL:  5 * time.Minute,
+		MaxAttempts:   3,
+		RecoveryCodes: 4,
+	}
+	return p
+}
+
+func currentCode(t *testing.T) string {
+	t.Helper()
+	code, err := totp.Code(mfaSecret, time.Now())
+	require.NoError(t, err)
+	return code
+}
+
+// recentStep matches the step of a code accepted just now.
+func recentStep() gomock.Matcher {
+	return gomock.Cond(func(step int64) bool {
+		return step >= totp.Step(time.Now())-1 && step <= totp.Step(time.Now())
+	})
+}
+
+func TestAuthenticateWithMFA(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		email    = "moderator@mail.com"
+		password = "12345678"
+		hash     = "hashed_password_123"
+		client   = entity.ClientInfo{UserAgent: "scanner/1.0", IP: "10.0.0.1"}
+		now      = time.Now()
+	)
+
+	for _, tc := range []struct {
+		name               string
+		role               entity.UserRole
+		mfa                entity.UserMFA
+		mfaErr             error
+		wantEnrollRequired bool
+	}{
+		{
+			name:               "required role without mfa must enrol",
+			role:               entity.RoleModerator,
+			mfaErr:             repository.ErrNoMFAFound,
+			wantEnrollRequired: true,
+		},
+		{
+			name:               "unconfirmed enrolment does not count",
+			role:               entity.RoleModerator,
+			mfa:                entity.UserMFA{PendingSecret: mfaEncrypted},
+			wantEnrollRequired: true,
+		},
+		{
+			name: "optional mfa enabled",
+			role: entity.RoleEmployee,
+			mfa:  entity.UserMFA{Secret: mfaEncrypted, ConfirmedAt: &now},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s, m := newServiceWithPolicy(ctrl, mfaPolicy())
+
+			user := entity.User{ID: uuid.New(), Email: email, PasswordHash: hash, Role: tc.role}
+			withinTx(ctx, m.tx)
+			notLocked(ctx, m, email, client.IP)
+			m.users.EXPECT().GetByEmail(ctx, email).Return(user, nil).Times(1)
+			m.hasher.EXPECT().CheckPasswordHash(password, hash).Return(true).Times(1)
+			m.hasher.EXPECT().NeedsRehash(hash).Return(false).Times(1)
+			m.mfa.EXPECT().Get(ctx, user.ID).Return(tc.mfa, tc.mfaErr).Times(1)
+
+			var stored entity.MFAChallenge
+			m.mfaCh.EXPECT().Create(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, c entity.MFAChallenge) (entity.MFAChallenge, error) {
+					stored = c
+					return c, nil
+				}).Times(1)
+
+			out, err := s.Authenticate(ctx, email, password, client)
+			require.NoError(t, err)
+			require.NotNil(t, out.MFA)
+			assert.Nil(t, out.Tokens)
+			assert.Equal(t, tc.wantEnrollRequired, out.MFA.EnrollmentRequired)
+
+			assert.Equal(t, user.ID, stored.UserID)
+			assert.True(t, strings.HasPrefix(out.MFA.Token, "pvzm_"), out.MFA.Token)
+			assert.Equal(t, hasher.HashToken(out.MFA.Token), stored.TokenHash)
+			assert.NotEqual(t, out.MFA.Token, stored.TokenHash)
+			assert.WithinDuration(t, time.Now().Add(5*time.Minute), out.MFA.ExpiresAt, time.Minute)
+		})
+	}
+}
+
+func TestVerifyMFA(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		arbitraryErr = errors.New("arbitrary error")
+		userID       = uuid.New()
+		user         = entity.User{ID: userID, Email: "moderator@mail.com", Role: entity.RoleModerator}
+		client       = entity.ClientInfo{UserAgent: "scanner/1.0", IP: "10.0.0.1"}
+		tokens       = &auth.Tokens{AccessToken: "access", RefreshToken: "refresh", ExpiresIn: 900}
+		confirmedAt  = time.Now().Add(-24 * time.Hour)
+		challenge    = entity.MFAChallenge{
+			ID:        uuid.New(),
+			TokenHash: hasher.HashToken(mfaToken),
+			UserID:    userID,
+			ExpiresAt: time.Now().Add(time.Minute),
+		}
+		enabled = entity.UserMFA{
+			UserID:       userID,
+			Secret:       mfaEncrypted,
+			ConfirmedAt:  &confirmedAt,
+			LastUsedStep: totp.Step(time.Now()) - 10,
+		}
+	)
+
+	withChallenge := func(m serviceMocks, c entity.MFAChallenge) {
+		m.mfaCh.EXPECT().GetByHashForUpdate(ctx, hasher.HashToken(mfaToken)).Return(c, nil).Times(1)
+	}
+	withUser := func(m serviceMocks, u entity.User) {
+		m.users.EXPECT().GetByID(ctx, userID).Return(u, nil).Times(1)
+	}
+	withSecret := func(m serviceMocks, mfa entity.UserMFA, ciphertext string) {
+		m.mfa.EXPECT().GetForUpdate(ctx, userID).Return(mfa, nil).Times(1)
+		m.crypt.EXPECT().Decrypt(ciphertext, userID[:]).Return([]byte(mfaSecret), nil).Times(1)
+	}
+	expectSession := func(m serviceMocks) {
+		m.mfaCh.EXPECT().Delete(ctx, challenge.ID).Return(nil).Times(1)
+		m.failures.EXPECT().Reset(ctx, entity.LoginScopeEmail, user.Email).Return(nil).Times(1)
+		m.auth.EXPECT().GenerateTokens(user, gomock.Any()).Return(tokens, nil).Times(1)
+		m.sessions.EXPECT().Create(ctx, verifiedSession(userID, tokens.RefreshToken, client)).Return(entity.Session{}, nil).Times(1)
+	}
+	expectFailure := func(m serviceMocks) {
+		m.metrics.EXPECT().FailureInc("invalid_mfa_code").Times(1)
+		m.mfaCh.EXPECT().IncrementAttempts(ctx, challenge.ID).Return(nil).Times(1)
+		m.failures.EXPECT().RecordFailure(ctx, entity.LoginScopeEmail, user.Email, time.Hour).Return(1, nil).Times(1)
+		m.failures.EXPECT().Lock(ctx, entity.LoginScopeEmail, user.Email, lockedUntil(time.Second)).Return(nil).Times(1)
+		m.failures.EXPECT().RecordFailure(ctx, entity.LoginScopeIP, client.IP, time.Hour).Return(1, nil).Times(1)
+		m.failures.EXPECT().Lock(ctx, entity.LoginScopeIP, client.IP, lockedUntil(time.Second)).Return(nil).Times(1)
+	}
+
+	type MockBehavior func(m serviceMocks)
+
+	for _, tc := range []struct {
+		name              string
+		code              func(t *testing.T) string
+		mockBehavior      MockBehavior
+		want              *auth.Tokens
+		wantRecoveryCodes int
+		wantErr           error
+	}{
+		{
+			name: "totp code",
+			code: currentCode,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				withChallenge(m, challenge)
+				withUser(m, user)
+				notLocked(ctx, m, user.Email, client.IP)
+				withSecret(m, enabled, mfaEncrypted)
+				m.mfa.EXPECT().SetLastUsedStep(ctx, userID, recentStep()).Return(nil).Times(1)
+				expectSession(m)
+			},
+			want: tokens,
+		},
+		{
+			name: "reused totp code",
+			code: currentCode,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				withChallenge(m, challenge)
+				withUser(m, user)
+				notLocked(ctx, m, user.Email, client.IP)
+				used := enabled
+				used.LastUsedStep = totp.Step(time.Now())
+				withSecret(m, used, mfaEncrypted)
+				expectFailure(m)
+			},
+			wantErr: service.ErrInvalidMFACode,
+		},
+		{
+			name: "wrong code",
+			code: func(*testing.T) string { return "000000" },
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				withChallenge(m, challenge)
+				withUser(m, user)
+				notLocked(ctx, m, user.Email, client.IP)
+				withSecret(m, enabled, mfaEncrypted)
+				m.mfa.EXPECT().UseRecoveryCode(ctx, userID, hasher.HashToken("000000")).Return(repository.ErrNoRecoveryCodeFound).Times(1)
+				expectFailure(m)
+			},
+			wantErr: service.ErrInvalidMFACode,
+		},
+		{
+			name: "recovery code",
+			code: func(*testing.T) string { return "abcd-efgh-ijkl-mnop" },
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				withChallenge(m, challenge)
+				withUser(m, user)
+				notLocked(ctx, m, user.Email, client.IP)
+				withSecret(m, enabled, mfaEncrypted)
+				m.mfa.EXPECT().UseRecoveryCode(ctx, userID, hasher.HashToken("ABCDEFGHIJKLMNOP")).Return(nil).Times(1)
+				expectSession(m)
+			},
+			want: tokens,
+		},
+		{
+			name: "enrolment confirmed during login",
+			code: currentCode,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				withChallenge(m, challenge)
+				withUser(m, user)
+				notLocked(ctx, m, user.Email, client.IP)
+				withSecret(m, entity.UserMFA{UserID: userID, PendingSecret: "pending"}, "pending")
+				m.mfa.EXPECT().Confirm(ctx, userID, recentStep()).Return(nil).Times(1)
+				m.mfa.EXPECT().ReplaceRecoveryCodes(ctx, userID, gomock.Len(4)).Return(nil).Times(1)
+				m.audit.EXPECT().Create(ctx, auditRecord(userID, entity.AuditActionMFAEnabled, userID,
+					`{"role":"moderator","disabled":false}`,
+					`{"role":"moderator","disabled":false}`,
+				)).Return(nil).Times(1)
+				expectSession(m)
+			},
+			want:              tokens,
+			wantRecoveryCodes: 4,
+		},
+		{
+			name: "wrong code locks the user",
+			code: func(*testing.T) string { return "000000" },
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				withChallenge(m, challenge)
+				withUser(m, user)
+				notLocked(ctx, m, user.Email, client.IP)
+				withSecret(m, enabled, mfaEncrypted)
+				m.mfa.EXPECT().UseRecoveryCode(ctx, userID, hasher.HashToken("000000")).Return(repository.ErrNoRecoveryCodeFound).Times(1)
+				m.metrics.EXPECT().FailureInc("invalid_mfa_code").Times(1)
+				m.mfaCh.EXPECT().IncrementAttempts(ctx, challenge.ID).Return(nil).Times(1)
+				m.failures.EXPECT().RecordFailure(ctx, entity.LoginScopeEmail, user.Email, time.Hour).Return(3, nil).Times(1)
+				m.metrics.EXPECT().LockoutInc("email").Times(1)
+				m.failures.EXPECT().Lock(ctx, entity.LoginScopeEmail, user.Email, lockedUntil(15*time.Minute)).Return(nil).Times(1)
+				m.failures.EXPECT().RecordFailure(ctx, entity.LoginScopeIP, client.IP, time.Hour).Return(3, nil).Times(1)
+				m.failures.EXPECT().Lock(ctx, entity.LoginScopeIP, client.IP, lockedUntil(4*time.Second)).Return(nil).Times(1)
+			},
+			wantErr: service.ErrInvalidMFACode,
+		},
+		{
+			// A correct password does not reset the counter while MFA is
+			// pending, so new challenges do not give more guesses
+			name: "locked user",
+			code: currentCode,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				withChallenge(m, challenge)
+				withUser(m, user)
+				until := time.Now().Add(time.Minute)
+				m.failures.EXPECT().GetForUpdate(ctx, entity.LoginScopeEmail, user.Email).
+					Return(entity.LoginFailures{Scope: entity.LoginScopeEmail, Key: user.Email, LockedUntil: &until}, nil).Times(1)
+				m.metrics.EXPECT().FailureInc("locked").Times(1)
+			},
+			wantErr: service.ErrInvalidMFACode,
+		},
+		{
+			name: "not enrolled",
+			code: currentCode,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				withChallenge(m, challenge)
+				withUser(m, user)
+				notLocked(ctx, m, user.Email, client.IP)
+				m.mfa.EXPECT().GetForUpdate(ctx, userID).Return(entity.UserMFA{}, repository.ErrNoMFAFound).Times(1)
+			},
+			wantErr: service.ErrMFANotEnrolled,
+		},
+		{
+			name: "unknown token",
+			code: currentCode,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.mfaCh.EXPECT().GetByHashForUpdate(ctx, hasher.HashToken(mfaToken)).Return(entity.MFAChallenge{}, repository.ErrNoMFAChallengeFound).Times(1)
+			},
+			wantErr: service.ErrInvalidMFAToken,
+		},
+		{
+			name: "expired token",
+			code: currentCode,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				expired := challenge
+				expired.ExpiresAt = time.Now().Add(-time.Second)
+				withChallenge(m, expired)
+			},
+			wantErr: service.ErrInvalidMFAToken,
+		},
+		{
+			name: "attempts used up",
+			code: currentCode,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				usedUp := challenge
+				usedUp.Attempts = 3
+				withChallenge(m, usedUp)
+			},
+			wantErr: service.ErrInvalidMFAToken,
+		},
+		{
+			name: "disabled user",
+			code: currentCode,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				withChallenge(m, challenge)
+				disabledAt := time.Now()
+				disabled := user
+				disabled.DisabledAt = &disabledAt
+				withUser(m, disabled)
+			},
+			wantErr: service.ErrUserDisabled,
+		},
+		{
+			name: "decrypt error",
+			code: currentCode,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				withChallenge(m, challenge)
+				withUser(m, user)
+				notLocked(ctx, m, user.Email, client.IP)
+				m.mfa.EXPECT().GetForUpdate(ctx, userID).Return(enabled, nil).Times(1)
+				m.crypt.EXPECT().Decrypt(mfaEncrypted, userID[:]).Return(nil, arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s, m := newServiceWithPolicy(ctrl, mfaPolicy())
+			tc.mockBehavior(m)
+
+			out, codes, err := s.VerifyMFA(ctx, mfaToken, tc.code(t), client)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.want, out)
+			assert.Len(t, codes, tc.wantRecoveryCodes)
+		})
+	}
+}
+
+// TestRefreshWithRequiredMFA checks that sessions started without a second
+// factor, e.g. before it became required or before a promotion, cannot be
+// refreshed by users of a required role.
+func TestRefreshWithRequiredMFA(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		refreshToken = "refresh token"
+		userID       = uuid.New()
+		sessionID    = uuid.New()
+		client       = entity.ClientInfo{UserAgent: "scanner/1.0", IP: "10.0.0.1"}
+		tokens       = &auth.Tokens{AccessToken: "access", RefreshToken: "new refresh", ExpiresIn: 900}
+		moderator    = entity.User{ID: userID, Email: "moderator@mail.com", Role: entity.RoleModerator}
+		employee     = entity.User{ID: userID, Email: "employee@mail.com", Role: entity.RoleEmployee}
+	)
+
+	session := entity.Session{
+		ID:               sessionID,
+		UserID:           userID,
+		RefreshTokenHash: hasher.HashToken(refreshToken),
+		ExpiresAt:        time.Now().Add(time.Hour),
+	}
+	verified := session
+	verified.MFAVerified = true
+
+	type MockBehavior func(m serviceMocks)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		want         *auth.Tokens
+		wantErr      error
+	}{
+		{
+			name: "unverified session of required role is revoked",
+			mockBehavior: func(m serviceMocks) {
+				m.sessions.EXPECT().GetByIDForUpdate(ctx, sessionID).Return(session, nil).Times(1)
+				m.users.EXPECT().GetByID(ctx, userID).Return(moderator, nil).Times(1)
+				m.sessions.EXPECT().Revoke(ctx, userID, sessionID).Return(nil).Times(1)
+			},
+			wantErr: service.ErrMFARequired,
+		},
+		{
+			name: "verified session of required role",
+			mockBehavior: func(m serviceMocks) {
+				m.sessions.EXPECT().GetByIDForUpdate(ctx, sessionID).Return(verified, nil).Times(1)
+				m.users.EXPECT().GetByID(ctx, userID).Return(moderator, nil).Times(1)
+				m.auth.EXPECT().GenerateTokens(moderator, sessionID).Return(tokens, nil).Times(1)
+				m.sessions.EXPECT().Rotate(ctx, gomock.Cond(func(s entity.Session) bool {
+					return s.ID == sessionID && s.MFAVerified
+				})).Return(nil).Times(1)
+			},
+			want: tokens,
+		},
+		{
+			name: "unverified session of other role",
+			mockBehavior: func(m serviceMocks) {
+				m.sessions.EXPECT().GetByIDForUpdate(ctx, sessionID).Return(session, nil).Times(1)
+				m.users.EXPECT().GetByID(ctx, userID).Return(employee, nil).Times(1)
+				m.auth.EXPECT().GenerateTokens(employee, sessionID).Return(tokens, nil).Times(1)
+				m.sessions.EXPECT().Rotate(ctx, gomock.Any()).Return(nil).Times(1)
+			},
+			want: tokens,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s, m := newServiceWithPolicy(ctrl, mfaPolicy())
+			m.auth.EXPECT().ValidateRefreshToken(refreshToken).Return(&auth.RefreshClaims{SessionID: sessionID}, nil).Times(1)
+			withinTx(ctx, m.tx)
+			tc.mockBehavior(m)
+
+			out, err := s.RefreshTokens(ctx, refreshToken, client)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.want, out)
+		})
+	}
+}
+
+func TestBeginMFAEnrollment(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		userID = uuid.New()
+		user   = entity.User{ID: userID, Email: "employee@mail.com", Role: entity.RoleEmployee}
+	)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s, m := newServiceWithPolicy(ctrl, mfaPolicy())
+
+	var plaintext []byte
+	withinTx(ctx, m.tx)
+	m.users.EXPECT().GetByID(ctx, userID).Return(user, nil).Times(1)
+	m.mfa.EXPECT().GetForUpdate(ctx, userID).Return(entity.UserMFA{}, repository.ErrNoMFAFound).Times(1)
+	m.crypt.EXPECT().Encrypt(gomock.Any(), userID[:]).
+		DoAndReturn(func(p []byte, _ []byte) (string, error) {
+			plaintext = p
+			return mfaEncrypted, nil
+		}).Times(1)
+	m.mfa.EXPECT().SetPendingSecret(ctx, userID, mfaEncrypted).Return(nil).Times(1)
+
+	out, err := s.BeginMFAEnrollment(ctx, userID, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, string(plaintext), out.Secret)
+	_, err = totp.Code(out.Secret, time.Now())
+	assert.NoError(t, err)
+
+	uri, err := url.Parse(out.URI)
+	require.NoError(t, err)
+	assert.Equal(t, "/avito-pvz:employee@mail.com", uri.Path)
+	assert.Equal(t, out.Secret, uri.Query().Get("secret"))
+
+	assert.Equal(t, []byte("\x89PNG"), out.QRCode[:4])
+}
+
+// TestBeginMFAEnrollmentReplace checks that an enabled second factor is
+// replaced only with a current code, and that wrong codes count towards the
+// login lockout.
+func TestBeginMFAEnrollmentReplace(t *testing.T) {
+	var (
+		ctx         = context.Background()
+		userID      = uuid.New()
+		email       = "employee@mail.com"
+		user        = entity.User{ID: userID, Email: email, Role: entity.RoleEmployee}
+		confirmedAt = time.Now().Add(-24 * time.Hour)
+		enabled     = entity.UserMFA{
+			UserID:       userID,
+			Secret:       mfaEncrypted,
+			ConfirmedAt:  &confirmedAt,
+			LastUsedStep: totp.Step(time.Now()) - 10,
+		}
+	)
+
+	expectFailure := func(m serviceMocks) {
+		m.metrics.EXPECT().FailureInc("invalid_mfa_code").Times(1)
+		m.failures.EXPECT().RecordFailure(ctx, entity.LoginScopeEmail, email, time.Hour).Return(1, nil).Times(1)
+		m.failures.EXPECT().Lock(ctx, entity.LoginScopeEmail, email, lockedUntil(time.Second)).Return(nil).Times(1)
+	}
+	expectUnlocked := func(m serviceMocks) {
+		m.failures.EXPECT().GetForUpdate(ctx, entity.LoginScopeEmail, email).
+			Return(entity.LoginFailures{Scope: entity.LoginScopeEmail, Key: email}, nil).Times(1)
+	}
+
+	type MockBehavior func(m serviceMocks)
+
+	for _, tc := range []struct {
+		name         string
+		code         func(t *testing.T) string
+		mockBehavior MockBehavior
+		wantErr      error
+	}{
+		{
+			name: "current totp code",
+			code: currentCode,
+			mockBehavior: func(m serviceMocks) {
+				expectUnlocked(m)
+				m.crypt.EXPECT().Decrypt(mfaEncrypted, userID[:]).Return([]byte(mfaSecret), nil).Times(1)
+				m.mfa.EXPECT().SetLastUsedStep(ctx, userID, recentStep()).Return(nil).Times(1)
+				m.crypt.EXPECT().Encrypt(gomock.Any(), userID[:]).Return("new secret", nil).Times(1)
+				m.mfa.EXPECT().SetPendingSecret(ctx, userID, "new secret").Return(nil).Times(1)
+			},
+		},
+		{
+			name: "no code",
+			code: func(*testing.T) string { return "" },
+			mockBehavior: func(m serviceMocks) {
+				expectUnlocked(m)
+				expectFailure(m)
+			},
+			wantErr: service.ErrInvalidMFACode,
+		},
+		{
+			name: "wrong code",
+			code: func(*testing.T) string { return "000000" },
+			mockBehavior: func(m serviceMocks) {
+				expectUnlocked(m)
+				m.crypt.EXPECT().Decrypt(mfaEncrypted, userID[:]).Return([]byte(mfaSecret), nil).Times(1)
+				m.mfa.EXPECT().UseRecoveryCode(ctx, userID, hasher.HashToken("000000")).Return(repository.ErrNoRecoveryCodeFound).Times(1)
+				expectFailure(m)
+			},
+			wantErr: service.ErrInvalidMFACode,
+		},
+		{
+			name: "locked user",
+			code: currentCode,
+			mockBehavior: func(m serviceMocks) {
+				until := time.Now().Add(time.Minute)
+				m.failures.EXPECT().GetForUpdate(ctx, entity.LoginScopeEmail, email).
+					Return(entity.LoginFailures{Scope: entity.LoginScopeEmail, Key: email, LockedUntil: &until}, nil).Times(1)
+				m.metrics.EXPECT().FailureInc("invalid_mfa_code").Times(1)
+			},
+			wantErr: service.ErrInvalidMFACode,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s, m := newServiceWithPolicy(ctrl, mfaPolicy())
+			withinTx(ctx, m.tx)
+			m.users.EXPECT().GetByID(ctx, userID).Return(user, nil).Times(1)
+			m.mfa.EXPECT().GetForUpdate(ctx, userID).Return(enabled, nil).Times(1)
+			tc.mockBehavior(m)
+
+			out, err := s.BeginMFAEnrollment(ctx, userID, tc.code(t))
+			assert.ErrorIs(t, err, tc.wantErr)
+			if tc.wantErr == nil {
+				assert.NotEmpty(t, out.Secret)
+			} else {
+				assert.Empty(t, out.Secret)
+			}
+		})
+	}
+}
+
+func TestBeginMFAEnrollmentByToken(t *testing.T) {
+	var (
+		ctx         = context.Background()
+		userID      = uuid.New()
+		user        = entity.User{ID: userID, Email: "moderator@mail.com", Role: entity.RoleModerator}
+		confirmedAt = time.Now()
+		challenge   = entity.MFAChallenge{ID: uuid.New(), UserID: userID, ExpiresAt: time.Now().Add(time.Minute)}
+	)
+
+	for _, tc := range []struct {
+		name    string
+		mfa     entity.UserMFA
+		mfaErr  error
+		wantErr error
+	}{
+		{name: "first enrolment", mfaErr: repository.ErrNoMFAFound},
+		{name: "restarted enrolment", mfa: entity.UserMFA{PendingSecret: "old"}},
+		{name: "already enabled", mfa: entity.UserMFA{Secret: mfaEncrypted, ConfirmedAt: &confirmedAt}, wantErr: service.ErrMFAEnabled},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s, m := newServiceWithPolicy(ctrl, mfaPolicy())
+
+			withinTx(ctx, m.tx)
+			m.mfaCh.EXPECT().GetByHashForUpdate(ctx, hasher.HashToken(mfaToken)).Return(challenge, nil).Times(1)
+			m.users.EXPECT().GetByID(ctx, userID).Return(user, nil).Times(1)
+			m.mfa.EXPECT().GetForUpdate(ctx, userID).Return(tc.mfa, tc.mfaErr).Times(1)
+			if tc.wantErr == nil {
+				m.crypt.EXPECT().Encrypt(gomock.Any(), userID[:]).Return(mfaEncrypted, nil).Times(1)
+				m.mfa.EXPECT().SetPendingSecret(ctx, userID, mfaEncrypted).Return(nil).Times(1)
+			}
+
+			out, err := s.BeginMFAEnrollmentByToken(ctx, mfaToken)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.wantErr == nil, out.Secret != "")
+		})
+	}
+}
+
+func TestConfirmMFAEnrollment(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		userID = uuid.New()
+		user   = entity.User{ID: userID, Email: "employee@mail.com", Role: entity.RoleEmployee}
+	)
+
+	type MockBehavior func(m serviceMocks)
+
+	for _, tc := range []struct {
+		name         string
+		code         func(t *testing.T) string
+		mockBehavior MockBehavior
+		wantCodes    int
+		wantErr      error
+	}{
+		{
+			name: "success",
+			code: currentCode,
+			mockBehavior: func(m serviceMocks) {
+				m.mfa.EXPECT().GetForUpdate(ctx, userID).Return(entity.UserMFA{UserID: userID, PendingSecret: mfaEncrypted}, nil).Times(1)
+				m.crypt.EXPECT().Decrypt(mfaEncrypted, userID[:]).Return([]byte(mfaSecret), nil).Times(1)
+				m.mfa.EXPECT().Confirm(ctx, userID, recentStep()).Return(nil).Times(1)
+				m.mfa.EXPECT().ReplaceRecoveryCodes(ctx, userID, gomock.Len(4)).Return(nil).Times(1)
+				m.audit.EXPECT().Create(ctx, auditRecord(userID, entity.AuditActionMFAEnabled, userID,
+					`{"role":"employee","disabled":false}`,
+					`{"role":"employee","disabled":false}`,
+				)).Return(nil).Times(1)
+			},
+			wantCodes: 4,
+		},
+		{
+			name: "wrong code",
+			code: func(*testing.T) string { return "000000" },
+			mockBehavior: func(m serviceMocks) {
+				m.mfa.EXPECT().GetForUpdate(ctx, userID).Return(entity.UserMFA{UserID: userID, PendingSecret: mfaEncrypted}, nil).Times(1)
+				m.crypt.EXPECT().Decrypt(mfaEncrypted, userID[:]).Return([]byte(mfaSecret), nil).Times(1)
+			},
+			wantErr: service.ErrInvalidMFACode,
+		},
+		{
+			name: "no enrolment started",
+			code: currentCode,
+			mockBehavior: func(m serviceMocks) {
+				m.mfa.EXPECT().GetForUpdate(ctx, userID).Return(entity.UserMFA{}, repository.ErrNoMFAFound).Times(1)
+			},
+			wantErr: service.ErrNoMFAEnrollment,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s, m := newServiceWithPolicy(ctrl, mfaPolicy())
+			withinTx(ctx, m.tx)
+			m.users.EXPECT().GetByID(ctx, userID).Return(user, nil).Times(1)
+			tc.mockBehavior(m)
+
+			codes, err := s.ConfirmMFAEnrollment(ctx, userID, tc.code(t))
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Len(t, codes, tc.wantCodes)
+			for _, code := range codes {
+				assert.Regexp(t, `^[A-Z2-7]{4}-[A-Z2-7]{4}-[A-Z2-7]{4}-[A-Z2-7]{4}$`, code)
+			}
+		})
+	}
+}
@@ -12,9 +12,12 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	auth "github.com/4udiwe/avito-pvz/internal/auth"
 	entity "github.com/4udiwe/avito-pvz/internal/entity"
+	notifier "github.com/4udiwe/avito-pvz/pkg/notifier"
+	oidc "github.com/4udiwe/avito-pvz/pkg/oidc"
 	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -43,6 +46,20 @@ func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AssignPoints mocks base method.
+func (m *MockUserRepository) AssignPoints(ctx context.Context, userID uuid.UUID, pointIDs []uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignPoints", ctx, userID, pointIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignPoints indicates an expected call of AssignPoints.
+func (mr *MockUserRepositoryMockRecorder) AssignPoints(ctx, userID, pointIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignPoints", reflect.TypeOf((*MockUserRepository)(nil).AssignPoints), ctx, userID, pointIDs)
+}
+
 // Create mocks base method.
 func (m *MockUserRepository) Create(ctx context.Context, user entity.User) (entity.User, error) {
 	m.ctrl.T.Helper()
@@ -73,19 +90,352 @@ func (mr *MockUserRepositoryMockRecorder) GetByEmail(ctx, email any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByEmail", reflect.TypeOf((*MockUserRepository)(nil).GetByEmail), ctx, email)
 }
 
-// UpdateRefreshToken mocks base method.
-func (m *MockUserRepository) UpdateRefreshToken(ctx context.Context, userID uuid.UUID, refreshToken string) (entity.User, error) {
+// GetByID mocks base method.
+func (m *MockUserRepository) GetByID(ctx context.Context, userID uuid.UUID) (entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, userID)
+	ret0, _ := ret[0].(entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockUserRepositoryMockRecorder) GetByID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockUserRepository)(nil).GetByID), ctx, userID)
+}
+
+// GetByIDForUpdate mocks base method.
+func (m *MockUserRepository) GetByIDForUpdate(ctx context.Context, userID uuid.UUID) (entity.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateRefreshToken", ctx, userID, refreshToken)
+	ret := m.ctrl.Call(m, "GetByIDForUpdate", ctx, userID)
 	ret0, _ := ret[0].(entity.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateRefreshToken indicates an expected call of UpdateRefreshToken.
-func (mr *MockUserRepositoryMockRecorder) UpdateRefreshToken(ctx, userID, refreshToken any) *gomock.Call {
+// GetByIDForUpdate indicates an expected call of GetByIDForUpdate.
+func (mr *MockUserRepositoryMockRecorder) GetByIDForUpdate(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDForUpdate", reflect.TypeOf((*MockUserRepository)(nil).GetByIDForUpdate), ctx, userID)
+}
+
+// List mocks base method.
+func (m *MockUserRepository) List(ctx context.Context, filter entity.UserFilter) ([]entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].([]entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockUserRepositoryMockRecorder) List(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockUserRepository)(nil).List), ctx, filter)
+}
+
+// SetDisabledAt mocks base method.
+func (m *MockUserRepository) SetDisabledAt(ctx context.Context, userID uuid.UUID, disabledAt *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDisabledAt", ctx, userID, disabledAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDisabledAt indicates an expected call of SetDisabledAt.
+func (mr *MockUserRepositoryMockRecorder) SetDisabledAt(ctx, userID, disabledAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDisabledAt", reflect.TypeOf((*MockUserRepository)(nil).SetDisabledAt), ctx, userID, disabledAt)
+}
+
+// UpdatePassword mocks base method.
+func (m *MockUserRepository) UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePassword", ctx, userID, passwordHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePassword indicates an expected call of UpdatePassword.
+func (mr *MockUserRepositoryMockRecorder) UpdatePassword(ctx, userID, passwordHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockUserRepository)(nil).UpdatePassword), ctx, userID, passwordHash)
+}
+
+// UpdateRole mocks base method.
+func (m *MockUserRepository) UpdateRole(ctx context.Context, userID uuid.UUID, role entity.UserRole) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", ctx, userID, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRole indicates an expected call of UpdateRole.
+func (mr *MockUserRepositoryMockRecorder) UpdateRole(ctx, userID, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockUserRepository)(nil).UpdateRole), ctx, userID, role)
+}
+
+// MockSessionRepository is a mock of SessionRepository interface.
+type MockSessionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockSessionRepositoryMockRecorder is the mock recorder for MockSessionRepository.
+type MockSessionRepositoryMockRecorder struct {
+	mock *MockSessionRepository
+}
+
+// NewMockSessionRepository creates a new mock instance.
+func NewMockSessionRepository(ctrl *gomock.Controller) *MockSessionRepository {
+	mock := &MockSessionRepository{ctrl: ctrl}
+	mock.recorder = &MockSessionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSessionRepository) EXPECT() *MockSessionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockSessionRepository) Create(ctx context.Context, session entity.Session) (entity.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, session)
+	ret0, _ := ret[0].(entity.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSessionRepositoryMockRecorder) Create(ctx, session any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSessionRepository)(nil).Create), ctx, session)
+}
+
+// GetActiveByUser mocks base method.
+func (m *MockSessionRepository) GetActiveByUser(ctx context.Context, userID uuid.UUID) ([]entity.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveByUser", ctx, userID)
+	ret0, _ := ret[0].([]entity.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveByUser indicates an expected call of GetActiveByUser.
+func (mr *MockSessionRepositoryMockRecorder) GetActiveByUser(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveByUser", reflect.TypeOf((*MockSessionRepository)(nil).GetActiveByUser), ctx, userID)
+}
+
+// GetByIDForUpdate mocks base method.
+func (m *MockSessionRepository) GetByIDForUpdate(ctx context.Context, sessionID uuid.UUID) (entity.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDForUpdate", ctx, sessionID)
+	ret0, _ := ret[0].(entity.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDForUpdate indicates an expected call of GetByIDForUpdate.
+func (mr *MockSessionRepositoryMockRecorder) GetByIDForUpdate(ctx, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDForUpdate", reflect.TypeOf((*MockSessionRepository)(nil).GetByIDForUpdate), ctx, sessionID)
+}
+
+// Revoke mocks base method.
+func (m *MockSessionRepository) Revoke(ctx context.Context, userID, sessionID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, userID, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockSessionRepositoryMockRecorder) Revoke(ctx, userID, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockSessionRepository)(nil).Revoke), ctx, userID, sessionID)
+}
+
+// RevokeAllByUser mocks base method.
+func (m *MockSessionRepository) RevokeAllByUser(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAllByUser", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAllByUser indicates an expected call of RevokeAllByUser.
+func (mr *MockSessionRepositoryMockRecorder) RevokeAllByUser(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAllByUser", reflect.TypeOf((*MockSessionRepository)(nil).RevokeAllByUser), ctx, userID)
+}
+
+// Rotate mocks base method.
+func (m *MockSessionRepository) Rotate(ctx context.Context, session entity.Session) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rotate", ctx, session)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rotate indicates an expected call of Rotate.
+func (mr *MockSessionRepositoryMockRecorder) Rotate(ctx, session any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rotate", reflect.TypeOf((*MockSessionRepository)(nil).Rotate), ctx, session)
+}
+
+// MockResetTokenRepository is a mock of ResetTokenRepository interface.
+type MockResetTokenRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockResetTokenRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockResetTokenRepositoryMockRecorder is the mock recorder for MockResetTokenRepository.
+type MockResetTokenRepositoryMockRecorder struct {
+	mock *MockResetTokenRepository
+}
+
+// NewMockResetTokenRepository creates a new mock instance.
+func NewMockResetTokenRepository(ctrl *gomock.Controller) *MockResetTokenRepository {
+	mock := &MockResetTokenRepository{ctrl: ctrl}
+	mock.recorder = &MockResetTokenRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockResetTokenRepository) EXPECT() *MockResetTokenRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockResetTokenRepository) Create(ctx context.Context, token entity.PasswordResetToken) (entity.PasswordResetToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, token)
+	ret0, _ := ret[0].(entity.PasswordResetToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockResetTokenRepositoryMockRecorder) Create(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockResetTokenRepository)(nil).Create), ctx, token)
+}
+
+// GetByHashForUpdate mocks base method.
+func (m *MockResetTokenRepository) GetByHashForUpdate(ctx context.Context, tokenHash string) (entity.PasswordResetToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByHashForUpdate", ctx, tokenHash)
+	ret0, _ := ret[0].(entity.PasswordResetToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByHashForUpdate indicates an expected call of GetByHashForUpdate.
+func (mr *MockResetTokenRepositoryMockRecorder) GetByHashForUpdate(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByHashForUpdate", reflect.TypeOf((*MockResetTokenRepository)(nil).GetByHashForUpdate), ctx, tokenHash)
+}
+
+// InvalidateByUser mocks base method.
+func (m *MockResetTokenRepository) InvalidateByUser(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvalidateByUser", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InvalidateByUser indicates an expected call of InvalidateByUser.
+func (mr *MockResetTokenRepositoryMockRecorder) InvalidateByUser(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateByUser", reflect.TypeOf((*MockResetTokenRepository)(nil).InvalidateByUser), ctx, userID)
+}
+
+// MarkUsed mocks base method.
+func (m *MockResetTokenRepository) MarkUsed(ctx context.Context, tokenID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkUsed", ctx, tokenID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkUsed indicates an expected call of MarkUsed.
+func (mr *MockResetTokenRepositoryMockRecorder) MarkUsed(ctx, tokenID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkUsed", reflect.TypeOf((*MockResetTokenRepository)(nil).MarkUsed), ctx, tokenID)
+}
+
+// MockInvitationRepository is a mock of InvitationRepository interface.
+type MockInvitationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockInvitationRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockInvitationRepositoryMockRecorder is the mock recorder for MockInvitationRepository.
+type MockInvitationRepositoryMockRecorder struct {
+	mock *MockInvitationRepository
+}
+
+// NewMockInvitationRepository creates a new mock instance.
+func NewMockInvitationRepository(ctrl *gomock.Controller) *MockInvitationRepository {
+	mock := &MockInvitationRepository{ctrl: ctrl}
+	mock.recorder = &MockInvitationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInvitationRepository) EXPECT() *MockInvitationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockInvitationRepository) Create(ctx context.Context, invitation entity.Invitation) (entity.Invitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, invitation)
+	ret0, _ := ret[0].(entity.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockInvitationRepositoryMockRecorder) Create(ctx, invitation any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockInvitationRepository)(nil).Create), ctx, invitation)
+}
+
+// GetByHashForUpdate mocks base method.
+func (m *MockInvitationRepository) GetByHashForUpdate(ctx context.Context, codeHash string) (entity.Invitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByHashForUpdate", ctx, codeHash)
+	ret0, _ := ret[0].(entity.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByHashForUpdate indicates an expected call of GetByHashForUpdate.
+func (mr *MockInvitationRepositoryMockRecorder) GetByHashForUpdate(ctx, codeHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByHashForUpdate", reflect.TypeOf((*MockInvitationRepository)(nil).GetByHashForUpdate), ctx, codeHash)
+}
+
+// MarkUsed mocks base method.
+func (m *MockInvitationRepository) MarkUsed(ctx context.Context, invitationID, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkUsed", ctx, invitationID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkUsed indicates an expected call of MarkUsed.
+func (mr *MockInvitationRepositoryMockRecorder) MarkUsed(ctx, invitationID, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRefreshToken", reflect.TypeOf((*MockUserRepository)(nil).UpdateRefreshToken), ctx, userID, refreshToken)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkUsed", reflect.TypeOf((*MockInvitationRepository)(nil).MarkUsed), ctx, invitationID, userID)
 }
 
 // MockAuth is a mock of Auth interface.
@@ -113,18 +463,18 @@ func (m *MockAuth) EXPECT() *MockAuthMockRecorder {
 }
 
 // GenerateTokens mocks base method.
-func (m *MockAuth) GenerateTokens(user entity.User) (*auth.Tokens, error) {
+func (m *MockAuth) GenerateTokens(user entity.User, sessionID uuid.UUID) (*auth.Tokens, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GenerateTokens", user)
+	ret := m.ctrl.Call(m, "GenerateTokens", user, sessionID)
 	ret0, _ := ret[0].(*auth.Tokens)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GenerateTokens indicates an expected call of GenerateTokens.
-func (mr *MockAuthMockRecorder) GenerateTokens(user any) *gomock.Call {
+func (mr *MockAuthMockRecorder) GenerateTokens(user, sessionID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateTokens", reflect.TypeOf((*MockAuth)(nil).GenerateTokens), user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateTokens", reflect.TypeOf((*MockAuth)(nil).GenerateTokens), user, sessionID)
 }
 
 // ValidateAccessToken mocks base method.
@@ -143,10 +493,10 @@ func (mr *MockAuthMockRecorder) ValidateAccessToken(tokenString any) *gomock.Cal
 }
 
 // ValidateRefreshToken mocks base method.
-func (m *MockAuth) ValidateRefreshToken(tokenString string) (string, error) {
+func (m *MockAuth) ValidateRefreshToken(tokenString string) (*auth.RefreshClaims, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ValidateRefreshToken", tokenString)
-	ret0, _ := ret[0].(string)
+	ret0, _ := ret[0].(*auth.RefreshClaims)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -209,3 +559,761 @@ func (mr *MockHasherMockRecorder) HashPassword(password any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashPassword", reflect.TypeOf((*MockHasher)(nil).HashPassword), password)
 }
+
+// NeedsRehash mocks base method.
+func (m *MockHasher) NeedsRehash(hash string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NeedsRehash", hash)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// NeedsRehash indicates an expected call of NeedsRehash.
+func (mr *MockHasherMockRecorder) NeedsRehash(hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NeedsRehash", reflect.TypeOf((*MockHasher)(nil).NeedsRehash), hash)
+}
+
+// MockTokenRevoker is a mock of TokenRevoker interface.
+type MockTokenRevoker struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokenRevokerMockRecorder
+	isgomock struct{}
+}
+
+// MockTokenRevokerMockRecorder is the mock recorder for MockTokenRevoker.
+type MockTokenRevokerMockRecorder struct {
+	mock *MockTokenRevoker
+}
+
+// NewMockTokenRevoker creates a new mock instance.
+func NewMockTokenRevoker(ctrl *gomock.Controller) *MockTokenRevoker {
+	mock := &MockTokenRevoker{ctrl: ctrl}
+	mock.recorder = &MockTokenRevokerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTokenRevoker) EXPECT() *MockTokenRevokerMockRecorder {
+	return m.recorder
+}
+
+// Revoke mocks base method.
+func (m *MockTokenRevoker) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, jti, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockTokenRevokerMockRecorder) Revoke(ctx, jti, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockTokenRevoker)(nil).Revoke), ctx, jti, expiresAt)
+}
+
+// MockLoginFailureRepository is a mock of LoginFailureRepository interface.
+type MockLoginFailureRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginFailureRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockLoginFailureRepositoryMockRecorder is the mock recorder for MockLoginFailureRepository.
+type MockLoginFailureRepositoryMockRecorder struct {
+	mock *MockLoginFailureRepository
+}
+
+// NewMockLoginFailureRepository creates a new mock instance.
+func NewMockLoginFailureRepository(ctrl *gomock.Controller) *MockLoginFailureRepository {
+	mock := &MockLoginFailureRepository{ctrl: ctrl}
+	mock.recorder = &MockLoginFailureRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginFailureRepository) EXPECT() *MockLoginFailureRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteStale mocks base method.
+func (m *MockLoginFailureRepository) DeleteStale(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteStale", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteStale indicates an expected call of DeleteStale.
+func (mr *MockLoginFailureRepositoryMockRecorder) DeleteStale(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStale", reflect.TypeOf((*MockLoginFailureRepository)(nil).DeleteStale), ctx, before)
+}
+
+// GetForUpdate mocks base method.
+func (m *MockLoginFailureRepository) GetForUpdate(ctx context.Context, scope entity.LoginScope, key string) (entity.LoginFailures, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForUpdate", ctx, scope, key)
+	ret0, _ := ret[0].(entity.LoginFailures)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForUpdate indicates an expected call of GetForUpdate.
+func (mr *MockLoginFailureRepositoryMockRecorder) GetForUpdate(ctx, scope, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUpdate", reflect.TypeOf((*MockLoginFailureRepository)(nil).GetForUpdate), ctx, scope, key)
+}
+
+// Lock mocks base method.
+func (m *MockLoginFailureRepository) Lock(ctx context.Context, scope entity.LoginScope, key string, until time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", ctx, scope, key, until)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockLoginFailureRepositoryMockRecorder) Lock(ctx, scope, key, until any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockLoginFailureRepository)(nil).Lock), ctx, scope, key, until)
+}
+
+// RecordFailure mocks base method.
+func (m *MockLoginFailureRepository) RecordFailure(ctx context.Context, scope entity.LoginScope, key string, window time.Duration) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordFailure", ctx, scope, key, window)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordFailure indicates an expected call of RecordFailure.
+func (mr *MockLoginFailureRepositoryMockRecorder) RecordFailure(ctx, scope, key, window any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordFailure", reflect.TypeOf((*MockLoginFailureRepository)(nil).RecordFailure), ctx, scope, key, window)
+}
+
+// Reset mocks base method.
+func (m *MockLoginFailureRepository) Reset(ctx context.Context, scope entity.LoginScope, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reset", ctx, scope, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockLoginFailureRepositoryMockRecorder) Reset(ctx, scope, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockLoginFailureRepository)(nil).Reset), ctx, scope, key)
+}
+
+// MockMetrics is a mock of Metrics interface.
+type MockMetrics struct {
+	ctrl     *gomock.Controller
+	recorder *MockMetricsMockRecorder
+	isgomock struct{}
+}
+
+// MockMetricsMockRecorder is the mock recorder for MockMetrics.
+type MockMetricsMockRecorder struct {
+	mock *MockMetrics
+}
+
+// NewMockMetrics creates a new mock instance.
+func NewMockMetrics(ctrl *gomock.Controller) *MockMetrics {
+	mock := &MockMetrics{ctrl: ctrl}
+	mock.recorder = &MockMetricsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMetrics) EXPECT() *MockMetricsMockRecorder {
+	return m.recorder
+}
+
+// FailureInc mocks base method.
+func (m *MockMetrics) FailureInc(reason string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "FailureInc", reason)
+}
+
+// FailureInc indicates an expected call of FailureInc.
+func (mr *MockMetricsMockRecorder) FailureInc(reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailureInc", reflect.TypeOf((*MockMetrics)(nil).FailureInc), reason)
+}
+
+// LockoutInc mocks base method.
+func (m *MockMetrics) LockoutInc(scope string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "LockoutInc", scope)
+}
+
+// LockoutInc indicates an expected call of LockoutInc.
+func (mr *MockMetricsMockRecorder) LockoutInc(scope any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockoutInc", reflect.TypeOf((*MockMetrics)(nil).LockoutInc), scope)
+}
+
+// MockAuditRepository is a mock of AuditRepository interface.
+type MockAuditRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAuditRepositoryMockRecorder is the mock recorder for MockAuditRepository.
+type MockAuditRepositoryMockRecorder struct {
+	mock *MockAuditRepository
+}
+
+// NewMockAuditRepository creates a new mock instance.
+func NewMockAuditRepository(ctrl *gomock.Controller) *MockAuditRepository {
+	mock := &MockAuditRepository{ctrl: ctrl}
+	mock.recorder = &MockAuditRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditRepository) EXPECT() *MockAuditRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAuditRepository) Create(ctx context.Context, record entity.AuditRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAuditRepositoryMockRecorder) Create(ctx, record any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAuditRepository)(nil).Create), ctx, record)
+}
+
+// MockDisabledUsers is a mock of DisabledUsers interface.
+type MockDisabledUsers struct {
+	ctrl     *gomock.Controller
+	recorder *MockDisabledUsersMockRecorder
+	isgomock struct{}
+}
+
+// MockDisabledUsersMockRecorder is the mock recorder for MockDisabledUsers.
+type MockDisabledUsersMockRecorder struct {
+	mock *MockDisabledUsers
+}
+
+// NewMockDisabledUsers creates a new mock instance.
+func NewMockDisabledUsers(ctrl *gomock.Controller) *MockDisabledUsers {
+	mock := &MockDisabledUsers{ctrl: ctrl}
+	mock.recorder = &MockDisabledUsersMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDisabledUsers) EXPECT() *MockDisabledUsersMockRecorder {
+	return m.recorder
+}
+
+// Set mocks base method.
+func (m *MockDisabledUsers) Set(userID uuid.UUID, disabled bool) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Set", userID, disabled)
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockDisabledUsersMockRecorder) Set(userID, disabled any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockDisabledUsers)(nil).Set), userID, disabled)
+}
+
+// MockNotifier is a mock of Notifier interface.
+type MockNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotifierMockRecorder
+	isgomock struct{}
+}
+
+// MockNotifierMockRecorder is the mock recorder for MockNotifier.
+type MockNotifierMockRecorder struct {
+	mock *MockNotifier
+}
+
+// NewMockNotifier creates a new mock instance.
+func NewMockNotifier(ctrl *gomock.Controller) *MockNotifier {
+	mock := &MockNotifier{ctrl: ctrl}
+	mock.recorder = &MockNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotifier) EXPECT() *MockNotifierMockRecorder {
+	return m.recorder
+}
+
+// Notify mocks base method.
+func (m *MockNotifier) Notify(ctx context.Context, msg notifier.Message) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Notify", ctx, msg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MockNotifierMockRecorder) Notify(ctx, msg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockNotifier)(nil).Notify), ctx, msg)
+}
+
+// MockOIDCProvider is a mock of OIDCProvider interface.
+type MockOIDCProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockOIDCProviderMockRecorder
+	isgomock struct{}
+}
+
+// MockOIDCProviderMockRecorder is the mock recorder for MockOIDCProvider.
+type MockOIDCProviderMockRecorder struct {
+	mock *MockOIDCProvider
+}
+
+// NewMockOIDCProvider creates a new mock instance.
+func NewMockOIDCProvider(ctrl *gomock.Controller) *MockOIDCProvider {
+	mock := &MockOIDCProvider{ctrl: ctrl}
+	mock.recorder = &MockOIDCProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOIDCProvider) EXPECT() *MockOIDCProviderMockRecorder {
+	return m.recorder
+}
+
+// AuthCodeURL mocks base method.
+func (m *MockOIDCProvider) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthCodeURL", ctx, state, nonce, codeVerifier)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthCodeURL indicates an expected call of AuthCodeURL.
+func (mr *MockOIDCProviderMockRecorder) AuthCodeURL(ctx, state, nonce, codeVerifier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthCodeURL", reflect.TypeOf((*MockOIDCProvider)(nil).AuthCodeURL), ctx, state, nonce, codeVerifier)
+}
+
+// Exchange mocks base method.
+func (m *MockOIDCProvider) Exchange(ctx context.Context, code, codeVerifier string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exchange", ctx, code, codeVerifier)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exchange indicates an expected call of Exchange.
+func (mr *MockOIDCProviderMockRecorder) Exchange(ctx, code, codeVerifier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exchange", reflect.TypeOf((*MockOIDCProvider)(nil).Exchange), ctx, code, codeVerifier)
+}
+
+// Verify mocks base method.
+func (m *MockOIDCProvider) Verify(ctx context.Context, rawIDToken, nonce string) (oidc.IDToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", ctx, rawIDToken, nonce)
+	ret0, _ := ret[0].(oidc.IDToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockOIDCProviderMockRecorder) Verify(ctx, rawIDToken, nonce any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockOIDCProvider)(nil).Verify), ctx, rawIDToken, nonce)
+}
+
+// MockOIDCStateRepository is a mock of OIDCStateRepository interface.
+type MockOIDCStateRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOIDCStateRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockOIDCStateRepositoryMockRecorder is the mock recorder for MockOIDCStateRepository.
+type MockOIDCStateRepositoryMockRecorder struct {
+	mock *MockOIDCStateRepository
+}
+
+// NewMockOIDCStateRepository creates a new mock instance.
+func NewMockOIDCStateRepository(ctrl *gomock.Controller) *MockOIDCStateRepository {
+	mock := &MockOIDCStateRepository{ctrl: ctrl}
+	mock.recorder = &MockOIDCStateRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOIDCStateRepository) EXPECT() *MockOIDCStateRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockOIDCStateRepository) Create(ctx context.Context, state entity.OIDCLoginState) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, state)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockOIDCStateRepositoryMockRecorder) Create(ctx, state any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOIDCStateRepository)(nil).Create), ctx, state)
+}
+
+// Take mocks base method.
+func (m *MockOIDCStateRepository) Take(ctx context.Context, stateHash string) (entity.OIDCLoginState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Take", ctx, stateHash)
+	ret0, _ := ret[0].(entity.OIDCLoginState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Take indicates an expected call of Take.
+func (mr *MockOIDCStateRepositoryMockRecorder) Take(ctx, stateHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Take", reflect.TypeOf((*MockOIDCStateRepository)(nil).Take), ctx, stateHash)
+}
+
+// MockIdentityRepository is a mock of IdentityRepository interface.
+type MockIdentityRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdentityRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockIdentityRepositoryMockRecorder is the mock recorder for MockIdentityRepository.
+type MockIdentityRepositoryMockRecorder struct {
+	mock *MockIdentityRepository
+}
+
+// NewMockIdentityRepository creates a new mock instance.
+func NewMockIdentityRepository(ctrl *gomock.Controller) *MockIdentityRepository {
+	mock := &MockIdentityRepository{ctrl: ctrl}
+	mock.recorder = &MockIdentityRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdentityRepository) EXPECT() *MockIdentityRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockIdentityRepository) Create(ctx context.Context, identity entity.UserIdentity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, identity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockIdentityRepositoryMockRecorder) Create(ctx, identity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIdentityRepository)(nil).Create), ctx, identity)
+}
+
+// Get mocks base method.
+func (m *MockIdentityRepository) Get(ctx context.Context, issuer, subject string) (entity.UserIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, issuer, subject)
+	ret0, _ := ret[0].(entity.UserIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockIdentityRepositoryMockRecorder) Get(ctx, issuer, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockIdentityRepository)(nil).Get), ctx, issuer, subject)
+}
+
+// MockMFARepository is a mock of MFARepository interface.
+type MockMFARepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockMFARepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockMFARepositoryMockRecorder is the mock recorder for MockMFARepository.
+type MockMFARepositoryMockRecorder struct {
+	mock *MockMFARepository
+}
+
+// NewMockMFARepository creates a new mock instance.
+func NewMockMFARepository(ctrl *gomock.Controller) *MockMFARepository {
+	mock := &MockMFARepository{ctrl: ctrl}
+	mock.recorder = &MockMFARepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMFARepository) EXPECT() *MockMFARepositoryMockRecorder {
+	return m.recorder
+}
+
+// Confirm mocks base method.
+func (m *MockMFARepository) Confirm(ctx context.Context, userID uuid.UUID, step int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Confirm", ctx, userID, step)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Confirm indicates an expected call of Confirm.
+func (mr *MockMFARepositoryMockRecorder) Confirm(ctx, userID, step any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Confirm", reflect.TypeOf((*MockMFARepository)(nil).Confirm), ctx, userID, step)
+}
+
+// Delete mocks base method.
+func (m *MockMFARepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockMFARepositoryMockRecorder) Delete(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockMFARepository)(nil).Delete), ctx, userID)
+}
+
+// Get mocks base method.
+func (m *MockMFARepository) Get(ctx context.Context, userID uuid.UUID) (entity.UserMFA, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, userID)
+	ret0, _ := ret[0].(entity.UserMFA)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockMFARepositoryMockRecorder) Get(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockMFARepository)(nil).Get), ctx, userID)
+}
+
+// GetForUpdate mocks base method.
+func (m *MockMFARepository) GetForUpdate(ctx context.Context, userID uuid.UUID) (entity.UserMFA, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForUpdate", ctx, userID)
+	ret0, _ := ret[0].(entity.UserMFA)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForUpdate indicates an expected call of GetForUpdate.
+func (mr *MockMFARepositoryMockRecorder) GetForUpdate(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUpdate", reflect.TypeOf((*MockMFARepository)(nil).GetForUpdate), ctx, userID)
+}
+
+// ReplaceRecoveryCodes mocks base method.
+func (m *MockMFARepository) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceRecoveryCodes", ctx, userID, codeHashes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceRecoveryCodes indicates an expected call of ReplaceRecoveryCodes.
+func (mr *MockMFARepositoryMockRecorder) ReplaceRecoveryCodes(ctx, userID, codeHashes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceRecoveryCodes", reflect.TypeOf((*MockMFARepository)(nil).ReplaceRecoveryCodes), ctx, userID, codeHashes)
+}
+
+// SetLastUsedStep mocks base method.
+func (m *MockMFARepository) SetLastUsedStep(ctx context.Context, userID uuid.UUID, step int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLastUsedStep", ctx, userID, step)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLastUsedStep indicates an expected call of SetLastUsedStep.
+func (mr *MockMFARepositoryMockRecorder) SetLastUsedStep(ctx, userID, step any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLastUsedStep", reflect.TypeOf((*MockMFARepository)(nil).SetLastUsedStep), ctx, userID, step)
+}
+
+// SetPendingSecret mocks base method.
+func (m *MockMFARepository) SetPendingSecret(ctx context.Context, userID uuid.UUID, secret string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPendingSecret", ctx, userID, secret)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPendingSecret indicates an expected call of SetPendingSecret.
+func (mr *MockMFARepositoryMockRecorder) SetPendingSecret(ctx, userID, secret any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPendingSecret", reflect.TypeOf((*MockMFARepository)(nil).SetPendingSecret), ctx, userID, secret)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockMFARepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", ctx, userID, codeHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockMFARepositoryMockRecorder) UseRecoveryCode(ctx, userID, codeHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockMFARepository)(nil).UseRecoveryCode), ctx, userID, codeHash)
+}
+
+// MockMFAChallengeRepository is a mock of MFAChallengeRepository interface.
+type MockMFAChallengeRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockMFAChallengeRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockMFAChallengeRepositoryMockRecorder is the mock recorder for MockMFAChallengeRepository.
+type MockMFAChallengeRepositoryMockRecorder struct {
+	mock *MockMFAChallengeRepository
+}
+
+// NewMockMFAChallengeRepository creates a new mock instance.
+func NewMockMFAChallengeRepository(ctrl *gomock.Controller) *MockMFAChallengeRepository {
+	mock := &MockMFAChallengeRepository{ctrl: ctrl}
+	mock.recorder = &MockMFAChallengeRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMFAChallengeRepository) EXPECT() *MockMFAChallengeRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockMFAChallengeRepository) Create(ctx context.Context, challenge entity.MFAChallenge) (entity.MFAChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, challenge)
+	ret0, _ := ret[0].(entity.MFAChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockMFAChallengeRepositoryMockRecorder) Create(ctx, challenge any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockMFAChallengeRepository)(nil).Create), ctx, challenge)
+}
+
+// Delete mocks base method.
+func (m *MockMFAChallengeRepository) Delete(ctx context.Context, challengeID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, challengeID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockMFAChallengeRepositoryMockRecorder) Delete(ctx, challengeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockMFAChallengeRepository)(nil).Delete), ctx, challengeID)
+}
+
+// GetByHashForUpdate mocks base method.
+func (m *MockMFAChallengeRepository) GetByHashForUpdate(ctx context.Context, tokenHash string) (entity.MFAChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByHashForUpdate", ctx, tokenHash)
+	ret0, _ := ret[0].(entity.MFAChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByHashForUpdate indicates an expected call of GetByHashForUpdate.
+func (mr *MockMFAChallengeRepositoryMockRecorder) GetByHashForUpdate(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByHashForUpdate", reflect.TypeOf((*MockMFAChallengeRepository)(nil).GetByHashForUpdate), ctx, tokenHash)
+}
+
+// IncrementAttempts mocks base method.
+func (m *MockMFAChallengeRepository) IncrementAttempts(ctx context.Context, challengeID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementAttempts", ctx, challengeID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrementAttempts indicates an expected call of IncrementAttempts.
+func (mr *MockMFAChallengeRepositoryMockRecorder) IncrementAttempts(ctx, challengeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementAttempts", reflect.TypeOf((*MockMFAChallengeRepository)(nil).IncrementAttempts), ctx, challengeID)
+}
+
+// MockEncryptor is a mock of Encryptor interface.
+type MockEncryptor struct {
+	ctrl     *gomock.Controller
+	recorder *MockEncryptorMockRecorder
+	isgomock struct{}
+}
+
+// MockEncryptorMockRecorder is the mock recorder for MockEncryptor.
+type MockEncryptorMockRecorder struct {
+	mock *MockEncryptor
+}
+
+// NewMockEncryptor creates a new mock instance.
+func NewMockEncryptor(ctrl *gomock.Controller) *MockEncryptor {
+	mock := &MockEncryptor{ctrl: ctrl}
+	mock.recorder = &MockEncryptorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEncryptor) EXPECT() *MockEncryptorMockRecorder {
+	return m.recorder
+}
+
+// Decrypt mocks base method.
+func (m *MockEncryptor) Decrypt(ciphertext string, associatedData []byte) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decrypt", ciphertext, associatedData)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Decrypt indicates an expected call of Decrypt.
+func (mr *MockEncryptorMockRecorder) Decrypt(ciphertext, associatedData any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decrypt", reflect.TypeOf((*MockEncryptor)(nil).Decrypt), ciphertext, associatedData)
+}
+
+// Encrypt mocks base method.
+func (m *MockEncryptor) Encrypt(plaintext, associatedData []byte) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Encrypt", plaintext, associatedData)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Encrypt indicates an expected call of Encrypt.
+func (mr *MockEncryptorMockRecorder) Encrypt(plaintext, associatedData any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Encrypt", reflect.TypeOf((*MockEncryptor)(nil).Encrypt), plaintext, associatedData)
+}
@@ -0,0 +1,266 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/repository"
+	"github.com/4udiwe/avito-pvz/pkg/hasher"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/4udiwe/avito-pvz/pkg/oidc"
+)
+
+// OIDCPolicy controls login with the corporate identity provider. The role
+// is taken from RoleClaim (a string or a list, such as groups): the first
+// of RoleMappings whose Value is present wins, so list the most privileged
+// roles first. Without a match DefaultRole is used; if it is empty the
+// login is refused.
+type OIDCPolicy struct {
+	Enabled      bool
+	Issuer       string
+	RoleClaim    string
+	RoleMappings []OIDCRoleMapping
+	DefaultRole  entity.UserRole
+	StateTTL     time.Duration
+	// MFAMethods are the amr or acr values of an ID token that count as a
+	// second factor done by the provider. Empty means the provider's MFA
+	// is not trusted and MFAPolicy applies as to password logins.
+	MFAMethods []string
+}
+
+type OIDCRoleMapping struct {
+	Value string
+	Role  entity.UserRole
+}
+
+// secondFactor tells whether the provider reports a second factor.
+func (p OIDCPolicy) secondFactor(token oidc.IDToken) bool {
+	methods := slices.Concat(token.StringsClaim("amr"), token.StringsClaim("acr"))
+	return slices.ContainsFunc(methods, func(m string) bool {
+		return slices.Contains(p.MFAMethods, m)
+	})
+}
+
+func (p OIDCPolicy) role(token oidc.IDToken) entity.UserRole {
+	values := token.StringsClaim(p.RoleClaim)
+	for _, m := range p.RoleMappings {
+		if slices.Contains(values, m.Value) {
+			return m.Role
+		}
+	}
+	return p.DefaultRole
+}
+
+// BeginOIDCLogin starts the authorization code flow and returns the URL of
+// the identity provider's login page.
+func (s *Service) BeginOIDCLogin(ctx context.Context) (string, error) {
+	logger.FromContext(ctx).Info("Service: Starting oidc login")
+
+	if !s.policy.OIDC.Enabled {
+		return "", ErrOIDCDisabled
+	}
+
+	var secrets [3]string
+	for i := range secrets {
+		v, err := oidc.RandomString()
+		if err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to generate oidc login state: %v", err)
+			return "", err
+		}
+		secrets[i] = v
+	}
+	state, nonce, verifier := secrets[0], secrets[1], secrets[2]
+
+	url, err := s.oidcProvider.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to build oidc login url: %v", err)
+		return "", err
+	}
+
+	err = s.oidcStates.Create(ctx, entity.OIDCLoginState{
+		StateHash:    hasher.HashToken(state),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(s.policy.OIDC.StateTTL),
+	})
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to store oidc login state: %v", err)
+		return "", err
+	}
+
+	return url, nil
+}
+
+// CompleteOIDCLogin handles the callback of the identity provider: it
+// redeems the code, verifies the ID token and logs the linked user in the
+// way Authenticate does, so users who need a second factor get an MFA
+// pending token unless the provider did one. A user is created on the
+// first login; a local user with the same email is linked only if the
+// provider verified the email. The role is updated from the provider on
+// every login.
+func (s *Service) CompleteOIDCLogin(ctx context.Context, code string, state string, client entity.ClientInfo) (*LoginResult, error) {
+	logger.FromContext(ctx).Info("Service: Completing oidc login")
+
+	if !s.policy.OIDC.Enabled {
+		return nil, ErrOIDCDisabled
+	}
+
+	loginState, err := s.oidcStates.Take(ctx, hasher.HashToken(state))
+	if err != nil {
+		if errors.Is(err, repository.ErrNoOIDCStateFound) {
+			return nil, ErrInvalidOIDCState
+		}
+		logger.FromContext(ctx).Errorf("Service: Failed to get oidc login state: %v", err)
+		return nil, err
+	}
+	if time.Now().After(loginState.ExpiresAt) {
+		logger.FromContext(ctx).Warn("Service: Oidc login state expired")
+		return nil, ErrInvalidOIDCState
+	}
+
+	rawIDToken, err := s.oidcProvider.Exchange(ctx, code, loginState.CodeVerifier)
+	if err != nil {
+		logger.FromContext(ctx).Warnf("Service: Oidc code exchange failed: %v", err)
+		return nil, ErrOIDCLoginFailed
+	}
+
+	token, err := s.oidcProvider.Verify(ctx, rawIDToken, loginState.Nonce)
+	if err != nil {
+		logger.FromContext(ctx).Warnf("Service: Oidc id token rejected: %v", err)
+		return nil, ErrOIDCLoginFailed
+	}
+	if token.Email == "" {
+		logger.FromContext(ctx).Warnf("Service: Oidc id token of %s has no email", token.Subject)
+		return nil, ErrOIDCLoginFailed
+	}
+
+	role := s.policy.OIDC.role(token)
+	if role == "" {
+		logger.FromContext(ctx).Warnf("Service: No role mapped for oidc user %s", token.Subject)
+		return nil, ErrNoRoleMapped
+	}
+
+	var result *LoginResult
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		user, err := s.oidcUser(ctx, token, role)
+		if err != nil {
+			return err
+		}
+
+		if user.Disabled() {
+			logger.FromContext(ctx).Warnf("Service: Oidc login of disabled user %s", user.ID)
+			return ErrUserDisabled
+		}
+
+		result, err = s.login(ctx, user, client, s.policy.OIDC.secondFactor(token))
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Infof("Service: Oidc user %s logged in with role %s", token.Subject, role)
+	return result, nil
+}
+
+// oidcUser finds, links or creates the user of an identity and brings its
+// role in line with the provider. Must be called within a transaction.
+func (s *Service) oidcUser(ctx context.Context, token oidc.IDToken, role entity.UserRole) (entity.User, error) {
+	identity, err := s.identities.Get(ctx, s.policy.OIDC.Issuer, token.Subject)
+	switch {
+	case err == nil:
+		user, err := s.getUser(ctx, identity.UserID)
+		if err != nil {
+			return entity.User{}, err
+		}
+		return s.syncOIDCRole(ctx, user, role)
+
+	case !errors.Is(err, repository.ErrNoIdentityFound):
+		logger.FromContext(ctx).Errorf("Service: Failed to get identity: %v", err)
+		return entity.User{}, err
+	}
+
+	user, err := s.userRepository.GetByEmail(ctx, token.Email)
+	switch {
+	case err == nil:
+		// Linking an unverified email would let anyone who can register it
+		// at the provider take over the local account
+		if !token.EmailVerified {
+			logger.FromContext(ctx).Warnf("Service: Oidc user %s claims unverified email of an existing user", token.Subject)
+			return entity.User{}, ErrUserAlreadyExists
+		}
+		if user, err = s.syncOIDCRole(ctx, user, role); err != nil {
+			return entity.User{}, err
+		}
+
+	case errors.Is(err, repository.ErrNoUserFound):
+		if user, err = s.provisionOIDCUser(ctx, token.Email, role); err != nil {
+			return entity.User{}, err
+		}
+
+	default:
+		logger.FromContext(ctx).Errorf("Service: Failed to get user by email: %v", err)
+		return entity.User{}, err
+	}
+
+	err = s.identities.Create(ctx, entity.UserIdentity{
+		Issuer:  s.policy.OIDC.Issuer,
+		Subject: token.Subject,
+		UserID:  user.ID,
+	})
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to link identity: %v", err)
+		return entity.User{}, err
+	}
+	return user, nil
+}
+
+// provisionOIDCUser creates a user who signs in only through the provider:
+// without a password, password login and reset are refused.
+func (s *Service) provisionOIDCUser(ctx context.Context, email string, role entity.UserRole) (entity.User, error) {
+	user, err := s.userRepository.Create(ctx, entity.User{
+		Email: email,
+		Role:  role,
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrNoRoleFound) {
+			return entity.User{}, ErrUnknownRole
+		}
+		logger.FromContext(ctx).Errorf("Service: Failed to create user: %v", err)
+		return entity.User{}, err
+	}
+
+	if err = s.audit(ctx, user.ID, entity.AuditActionUserRegistered, entity.AuditTargetUser, user.ID, nil, snapshotOf(user)); err != nil {
+		return entity.User{}, err
+	}
+
+	logger.FromContext(ctx).Infof("Service: Provisioned oidc user %s with role %s", user.ID, role)
+	return user, nil
+}
+
+// syncOIDCRole applies the role from the provider. The change is audited
+// with the user as actor.
+func (s *Service) syncOIDCRole(ctx context.Context, user entity.User, role entity.UserRole) (entity.User, error) {
+	if user.Role == role {
+		return user, nil
+	}
+
+	if err := s.userRepository.UpdateRole(ctx, user.ID, role); err != nil {
+		if errors.Is(err, repository.ErrNoRoleFound) {
+			return entity.User{}, ErrUnknownRole
+		}
+		logger.FromContext(ctx).Errorf("Service: Failed to update role: %v", err)
+		return entity.User{}, err
+	}
+
+	updated := user
+	updated.Role = role
+	if err := s.auditUser(ctx, user.ID, entity.AuditActionUserRoleChanged, user, updated); err != nil {
+		return entity.User{}, err
+	}
+	return updated, nil
+}
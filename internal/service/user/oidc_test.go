@@ -0,0 +1,373 @@
+package user_test
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/repository"
+	service "github.com/4udiwe/avito-pvz/internal/service/user"
+	"github.com/4udiwe/avito-pvz/pkg/hasher"
+	"github.com/4udiwe/avito-pvz/pkg/oidc"
+	"github.com/4udiwe/avito-pvz/pkg/oidc/oidctest"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func oidcPolicy(issuer string) service.Policy {
+	p := policy
+	p.OIDC = service.OIDCPolicy{
+		Enabled:   true,
+		Issuer:    issuer,
+		RoleClaim: "groups",
+		RoleMappings: []service.OIDCRoleMapping{
+			{Value: "pvz-moderators", Role: entity.RoleModerator},
+			{Value: "pvz-employees", Role: entity.RoleEmployee},
+		},
+		StateTTL: 10 * time.Minute,
+	}
+	return p
+}
+
+// beginOIDCLogin starts a login and returns the state sent to the provider,
+// the login URL and what was stored for the callback.
+func beginOIDCLogin(t *testing.T, ctx context.Context, s *service.Service, m serviceMocks) (string, string, entity.OIDCLoginState) {
+	t.Helper()
+
+	var stored entity.OIDCLoginState
+	m.states.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, st entity.OIDCLoginState) error {
+		stored = st
+		return nil
+	}).Times(1)
+
+	loginURL, err := s.BeginOIDCLogin(ctx)
+	require.NoError(t, err)
+
+	u, err := url.Parse(loginURL)
+	require.NoError(t, err)
+	state := u.Query().Get("state")
+
+	require.Equal(t, hasher.HashToken(state), stored.StateHash)
+	require.Equal(t, stored.Nonce, u.Query().Get("nonce"))
+	require.Equal(t, oidc.CodeChallenge(stored.CodeVerifier), u.Query().Get("code_challenge"))
+	require.WithinDuration(t, time.Now().Add(10*time.Minute), stored.ExpiresAt, time.Minute)
+
+	return state, loginURL, stored
+}
+
+func TestCompleteOIDCLogin(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		subject = "staff-42"
+		email   = "staff@corp.example"
+		userID  = uuid.New()
+		client  = entity.ClientInfo{UserAgent: "browser/1.0", IP: "10.0.0.1"}
+		tokens  = auth.Tokens{AccessToken: "access token", RefreshToken: "refresh token", ExpiresIn: 100}
+		now     = time.Now()
+	)
+
+	moderatorClaims := map[string]any{
+		"sub":            subject,
+		"email":          email,
+		"email_verified": true,
+		"groups":         []string{"everyone", "pvz-employees", "pvz-moderators"},
+	}
+	unverifiedClaims := map[string]any{
+		"sub":            subject,
+		"email":          email,
+		"email_verified": false,
+		"groups":         []string{"pvz-employees"},
+	}
+	// The provider reports it checked a second factor
+	mfaClaims := map[string]any{
+		"sub":            subject,
+		"email":          email,
+		"email_verified": true,
+		"groups":         []string{"pvz-moderators"},
+		"amr":            []string{"pwd", "otp"},
+	}
+	unmappedClaims := map[string]any{
+		"sub":    subject,
+		"email":  email,
+		"groups": []string{"everyone"},
+	}
+
+	expectSession := func(m serviceMocks, user entity.User) {
+		noMFA(ctx, m, user.ID)
+		m.auth.EXPECT().GenerateTokens(user, gomock.Any()).Return(&tokens, nil).Times(1)
+		m.sessions.EXPECT().Create(ctx, newSession(user.ID, tokens.RefreshToken, client)).
+			DoAndReturn(func(_ context.Context, s entity.Session) (entity.Session, error) {
+				return s, nil
+			}).Times(1)
+	}
+
+	type MockBehavior func(m serviceMocks, issuer string)
+
+	for _, tc := range []struct {
+		name         string
+		claims       map[string]any
+		defaultRole  entity.UserRole
+		mfa          service.MFAPolicy
+		mfaMethods   []string
+		modifyState  func(st *entity.OIDCLoginState)
+		unknownState bool
+		mockBehavior MockBehavior
+		wantMFA      bool
+		wantErr      error
+	}{
+		{
+			name:   "first login provisions user",
+			claims: moderatorClaims,
+			mockBehavior: func(m serviceMocks, issuer string) {
+				withinTx(ctx, m.tx)
+				m.idents.EXPECT().Get(ctx, issuer, subject).Return(entity.UserIdentity{}, repository.ErrNoIdentityFound).Times(1)
+				m.users.EXPECT().GetByEmail(ctx, email).Return(entity.User{}, repository.ErrNoUserFound).Times(1)
+				created := entity.User{ID: userID, Email: email, Role: entity.RoleModerator}
+				m.users.EXPECT().Create(ctx, entity.User{Email: email, Role: entity.RoleModerator}).
+					Return(created, nil).Times(1)
+				m.audit.EXPECT().Create(ctx, auditRecord(userID, entity.AuditActionUserRegistered, userID,
+					"", `{"role":"moderator","disabled":false}`)).Return(nil).Times(1)
+				m.idents.EXPECT().Create(ctx, entity.UserIdentity{Issuer: issuer, Subject: subject, UserID: userID}).Return(nil).Times(1)
+				expectSession(m, created)
+			},
+			wantErr: nil,
+		},
+		{
+			name:        "default role for unmapped groups",
+			claims:      unmappedClaims,
+			defaultRole: entity.RoleAuditor,
+			mockBehavior: func(m serviceMocks, issuer string) {
+				withinTx(ctx, m.tx)
+				m.idents.EXPECT().Get(ctx, issuer, subject).Return(entity.UserIdentity{}, repository.ErrNoIdentityFound).Times(1)
+				m.users.EXPECT().GetByEmail(ctx, email).Return(entity.User{}, repository.ErrNoUserFound).Times(1)
+				created := entity.User{ID: userID, Email: email, Role: entity.RoleAuditor}
+				m.users.EXPECT().Create(ctx, gomock.Cond(func(u entity.User) bool { return u.Role == entity.RoleAuditor })).
+					Return(created, nil).Times(1)
+				m.audit.EXPECT().Create(ctx, auditRecord(userID, entity.AuditActionUserRegistered, userID,
+					"", `{"role":"auditor","disabled":false}`)).Return(nil).Times(1)
+				m.idents.EXPECT().Create(ctx, gomock.Any()).Return(nil).Times(1)
+				expectSession(m, created)
+			},
+			wantErr: nil,
+		},
+		{
+			name:   "returning user gets role from provider",
+			claims: moderatorClaims,
+			mockBehavior: func(m serviceMocks, issuer string) {
+				withinTx(ctx, m.tx)
+				m.idents.EXPECT().Get(ctx, issuer, subject).Return(entity.UserIdentity{Issuer: issuer, Subject: subject, UserID: userID}, nil).Times(1)
+				m.users.EXPECT().GetByID(ctx, userID).Return(entity.User{ID: userID, Email: email, Role: entity.RoleEmployee}, nil).Times(1)
+				m.users.EXPECT().UpdateRole(ctx, userID, entity.RoleModerator).Return(nil).Times(1)
+				m.audit.EXPECT().Create(ctx, gomock.Cond(func(r entity.AuditRecord) bool {
+					return r.ActorID == userID &&
+						r.Action == entity.AuditActionUserRoleChanged &&
+						r.TargetID == userID &&
+						strings.Contains(string(r.Before), `"role":"employee"`) &&
+						strings.Contains(string(r.After), `"role":"moderator"`)
+				})).Return(nil).Times(1)
+				expectSession(m, entity.User{ID: userID, Email: email, Role: entity.RoleModerator})
+			},
+			wantErr: nil,
+		},
+		{
+			name:   "returning user with unchanged role",
+			claims: moderatorClaims,
+			mockBehavior: func(m serviceMocks, issuer string) {
+				withinTx(ctx, m.tx)
+				user := entity.User{ID: userID, Email: email, Role: entity.RoleModerator}
+				m.idents.EXPECT().Get(ctx, issuer, subject).Return(entity.UserIdentity{Issuer: issuer, Subject: subject, UserID: userID}, nil).Times(1)
+				m.users.EXPECT().GetByID(ctx, userID).Return(user, nil).Times(1)
+				expectSession(m, user)
+			},
+			wantErr: nil,
+		},
+		{
+			name:   "moderator must pass required mfa",
+			claims: moderatorClaims,
+			mfa:    mfaPolicy().MFA,
+			mockBehavior: func(m serviceMocks, issuer string) {
+				withinTx(ctx, m.tx)
+				user := entity.User{ID: userID, Email: email, Role: entity.RoleModerator}
+				m.idents.EXPECT().Get(ctx, issuer, subject).Return(entity.UserIdentity{Issuer: issuer, Subject: subject, UserID: userID}, nil).Times(1)
+				m.users.EXPECT().GetByID(ctx, userID).Return(user, nil).Times(1)
+				m.mfa.EXPECT().Get(ctx, userID).Return(entity.UserMFA{}, repository.ErrNoMFAFound).Times(1)
+				m.mfaCh.EXPECT().Create(ctx, gomock.Cond(func(c entity.MFAChallenge) bool {
+					return c.UserID == userID && c.TokenHash != ""
+				})).DoAndReturn(func(_ context.Context, c entity.MFAChallenge) (entity.MFAChallenge, error) {
+					return c, nil
+				}).Times(1)
+			},
+			wantMFA: true,
+		},
+		{
+			name:   "provider mfa is not trusted by default",
+			claims: mfaClaims,
+			mfa:    mfaPolicy().MFA,
+			mockBehavior: func(m serviceMocks, issuer string) {
+				withinTx(ctx, m.tx)
+				user := entity.User{ID: userID, Email: email, Role: entity.RoleModerator}
+				m.idents.EXPECT().Get(ctx, issuer, subject).Return(entity.UserIdentity{Issuer: issuer, Subject: subject, UserID: userID}, nil).Times(1)
+				m.users.EXPECT().GetByID(ctx, userID).Return(user, nil).Times(1)
+				m.mfa.EXPECT().Get(ctx, userID).Return(entity.UserMFA{}, repository.ErrNoMFAFound).Times(1)
+				m.mfaCh.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, c entity.MFAChallenge) (entity.MFAChallenge, error) {
+					return c, nil
+				}).Times(1)
+			},
+			wantMFA: true,
+		},
+		{
+			name:       "trusted provider mfa",
+			claims:     mfaClaims,
+			mfa:        mfaPolicy().MFA,
+			mfaMethods: []string{"mfa", "otp"},
+			mockBehavior: func(m serviceMocks, issuer string) {
+				withinTx(ctx, m.tx)
+				user := entity.User{ID: userID, Email: email, Role: entity.RoleModerator}
+				m.idents.EXPECT().Get(ctx, issuer, subject).Return(entity.UserIdentity{Issuer: issuer, Subject: subject, UserID: userID}, nil).Times(1)
+				m.users.EXPECT().GetByID(ctx, userID).Return(user, nil).Times(1)
+				m.auth.EXPECT().GenerateTokens(user, gomock.Any()).Return(&tokens, nil).Times(1)
+				m.sessions.EXPECT().Create(ctx, verifiedSession(userID, tokens.RefreshToken, client)).
+					DoAndReturn(func(_ context.Context, s entity.Session) (entity.Session, error) {
+						return s, nil
+					}).Times(1)
+			},
+		},
+		{
+			name:   "links local user with verified email",
+			claims: moderatorClaims,
+			mockBehavior: func(m serviceMocks, issuer string) {
+				withinTx(ctx, m.tx)
+				user := entity.User{ID: userID, Email: email, Role: entity.RoleModerator}
+				m.idents.EXPECT().Get(ctx, issuer, subject).Return(entity.UserIdentity{}, repository.ErrNoIdentityFound).Times(1)
+				m.users.EXPECT().GetByEmail(ctx, email).Return(user, nil).Times(1)
+				m.idents.EXPECT().Create(ctx, entity.UserIdentity{Issuer: issuer, Subject: subject, UserID: userID}).Return(nil).Times(1)
+				expectSession(m, user)
+			},
+			wantErr: nil,
+		},
+		{
+			name:   "local user with unverified email",
+			claims: unverifiedClaims,
+			mockBehavior: func(m serviceMocks, issuer string) {
+				withinTx(ctx, m.tx)
+				m.idents.EXPECT().Get(ctx, issuer, subject).Return(entity.UserIdentity{}, repository.ErrNoIdentityFound).Times(1)
+				m.users.EXPECT().GetByEmail(ctx, email).Return(entity.User{ID: userID, Email: email, Role: entity.RoleEmployee}, nil).Times(1)
+			},
+			wantErr: service.ErrUserAlreadyExists,
+		},
+		{
+			name:   "disabled user",
+			claims: moderatorClaims,
+			mockBehavior: func(m serviceMocks, issuer string) {
+				withinTx(ctx, m.tx)
+				m.idents.EXPECT().Get(ctx, issuer, subject).Return(entity.UserIdentity{Issuer: issuer, Subject: subject, UserID: userID}, nil).Times(1)
+				m.users.EXPECT().GetByID(ctx, userID).Return(entity.User{ID: userID, Email: email, Role: entity.RoleModerator, DisabledAt: &now}, nil).Times(1)
+			},
+			wantErr: service.ErrUserDisabled,
+		},
+		{
+			name:         "no role mapped",
+			claims:       unmappedClaims,
+			mockBehavior: func(m serviceMocks, issuer string) {},
+			wantErr:      service.ErrNoRoleMapped,
+		},
+		{
+			name:         "unknown state",
+			claims:       moderatorClaims,
+			unknownState: true,
+			mockBehavior: func(m serviceMocks, issuer string) {},
+			wantErr:      service.ErrInvalidOIDCState,
+		},
+		{
+			name:         "expired state",
+			claims:       moderatorClaims,
+			modifyState:  func(st *entity.OIDCLoginState) { st.ExpiresAt = time.Now().Add(-time.Second) },
+			mockBehavior: func(m serviceMocks, issuer string) {},
+			wantErr:      service.ErrInvalidOIDCState,
+		},
+		{
+			name:         "code verifier mismatch",
+			claims:       moderatorClaims,
+			modifyState:  func(st *entity.OIDCLoginState) { st.CodeVerifier = "another-verifier" },
+			mockBehavior: func(m serviceMocks, issuer string) {},
+			wantErr:      service.ErrOIDCLoginFailed,
+		},
+		{
+			name:         "nonce mismatch",
+			claims:       moderatorClaims,
+			modifyState:  func(st *entity.OIDCLoginState) { st.Nonce = "another-nonce" },
+			mockBehavior: func(m serviceMocks, issuer string) {},
+			wantErr:      service.ErrOIDCLoginFailed,
+		},
+		{
+			name:         "token for another client",
+			claims:       map[string]any{"sub": subject, "email": email, "aud": "another-client", "groups": []string{"pvz-moderators"}},
+			mockBehavior: func(m serviceMocks, issuer string) {},
+			wantErr:      service.ErrOIDCLoginFailed,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			idp := oidctest.NewServer()
+			defer idp.Close()
+
+			p := oidcPolicy(idp.URL)
+			p.OIDC.DefaultRole = tc.defaultRole
+			p.OIDC.MFAMethods = tc.mfaMethods
+			p.MFA = tc.mfa
+			s, m := newServiceWithProvider(ctrl, oidc.New(idp.Config(), nil), p)
+
+			state, loginURL, stored := beginOIDCLogin(t, ctx, s, m)
+			code, err := idp.Login(loginURL, tc.claims)
+			require.NoError(t, err)
+
+			if tc.modifyState != nil {
+				tc.modifyState(&stored)
+			}
+			if tc.unknownState {
+				m.states.EXPECT().Take(ctx, hasher.HashToken(state)).Return(entity.OIDCLoginState{}, repository.ErrNoOIDCStateFound).Times(1)
+			} else {
+				m.states.EXPECT().Take(ctx, hasher.HashToken(state)).Return(stored, nil).Times(1)
+			}
+			tc.mockBehavior(m, idp.URL)
+
+			out, err := s.CompleteOIDCLogin(ctx, code, state, client)
+			assert.ErrorIs(t, err, tc.wantErr)
+			switch {
+			case tc.wantErr != nil:
+				assert.Nil(t, out)
+			case tc.wantMFA:
+				require.NotNil(t, out.MFA)
+				assert.Nil(t, out.Tokens)
+				assert.True(t, out.MFA.EnrollmentRequired)
+				assert.NotEmpty(t, out.MFA.Token)
+			default:
+				require.NotNil(t, out)
+				assert.Equal(t, &tokens, out.Tokens)
+			}
+		})
+	}
+}
+
+func TestOIDCDisabled(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s, _ := newService(ctrl)
+
+	_, err := s.BeginOIDCLogin(ctx)
+	assert.ErrorIs(t, err, service.ErrOIDCDisabled)
+
+	_, err = s.CompleteOIDCLogin(ctx, "code", "state", entity.ClientInfo{})
+	assert.ErrorIs(t, err, service.ErrOIDCDisabled)
+}
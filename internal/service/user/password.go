@@ -0,0 +1,230 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/repository"
+	"github.com/4udiwe/avito-pvz/pkg/hasher"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/4udiwe/avito-pvz/pkg/notifier"
+	"github.com/4udiwe/avito-pvz/pkg/redact"
+	"github.com/google/uuid"
+)
+
+const (
+	// tokenBytes gives 256 bit reset, MFA pending and invitation tokens.
+	tokenBytes = 32
+
+	// Prefixes mark the tokens so they are easy to recognize in leaks, the
+	// way API keys start with pvz_.
+	resetTokenPrefix      = "pvzr_"
+	mfaTokenPrefix        = "pvzm_"
+	invitationTokenPrefix = "pvzi_"
+)
+
+// ChangePassword sets a new password after checking the current one and
+// signs the user out of all devices.
+func (s *Service) ChangePassword(ctx context.Context, userID uuid.UUID, oldPassword string, newPassword string) error {
+	logger.FromContext(ctx).Infof("Service: Changing password of user %s", userID)
+
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		user, err := s.userRepository.GetByID(ctx, userID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNoUserFound) {
+				return ErrNoUserFound
+			}
+			logger.FromContext(ctx).Errorf("Service: Failed to get user: %v", err)
+			return err
+		}
+
+		if !s.hasher.CheckPasswordHash(oldPassword, user.PasswordHash) {
+			logger.FromContext(ctx).Warnf("Service: Invalid current password for user %s", userID)
+			return ErrInvalidCredentials
+		}
+
+		if err = s.setPassword(ctx, userID, newPassword); err != nil {
+			return err
+		}
+
+		return s.audit(ctx, userID, entity.AuditActionPasswordChanged, entity.AuditTargetUser, userID, nil, nil)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx).Infof("Service: Password of user %s changed", userID)
+	return nil
+}
+
+// RequestPasswordReset sends a single-use reset token to the user. Unknown
+// emails and users without a local password are not reported, so the
+// endpoint cannot be used to probe accounts.
+func (s *Service) RequestPasswordReset(ctx context.Context, email string) error {
+	logger.FromContext(ctx).Infof("Service: Password reset requested for %s", redact.Email(email))
+
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		user, err := s.userRepository.GetByEmail(ctx, email)
+		if err != nil {
+			if errors.Is(err, repository.ErrNoUserFound) {
+				logger.FromContext(ctx).Warnf("Service: Password reset for unknown email %s", redact.Email(email))
+				return nil
+			}
+			logger.FromContext(ctx).Errorf("Service: Failed to get user by email: %v", err)
+			return err
+		}
+		// Users of the identity provider would get a way around it
+		if !user.HasPassword() {
+			logger.FromContext(ctx).Warnf("Service: Password reset for user %s without a local password", user.ID)
+			return nil
+		}
+
+		token, err := generateToken(resetTokenPrefix)
+		if err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to generate reset token: %v", err)
+			return err
+		}
+
+		if err = s.resetRepository.InvalidateByUser(ctx, user.ID); err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to invalidate previous reset tokens: %v", err)
+			return err
+		}
+
+		_, err = s.resetRepository.Create(ctx, entity.PasswordResetToken{
+			UserID:    user.ID,
+			TokenHash: hasher.HashToken(token),
+			ExpiresAt: time.Now().Add(s.policy.ResetTokenTTL),
+		})
+		if err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to create reset token: %v", err)
+			return err
+		}
+
+		return s.notifier.Notify(ctx, notifier.Message{
+			Recipient: user.Email,
+			Subject:   "Password reset",
+			Body:      fmt.Sprintf("Your password reset token (valid for %s): %s", s.policy.ResetTokenTTL, token),
+		})
+	})
+
+	if err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx).Infof("Service: Password reset for %s processed", redact.Email(email))
+	return nil
+}
+
+// ResetPassword sets a new password using a reset token and signs the user
+// out of all devices. The token can be used once.
+func (s *Service) ResetPassword(ctx context.Context, token string, newPassword string) error {
+	logger.FromContext(ctx).Info("Service: Resetting password")
+
+	var userID uuid.UUID
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		resetToken, err := s.resetRepository.GetByHashForUpdate(ctx, hasher.HashToken(token))
+		if err != nil {
+			if errors.Is(err, repository.ErrNoResetTokenFound) {
+				return ErrInvalidResetToken
+			}
+			logger.FromContext(ctx).Errorf("Service: Failed to get reset token: %v", err)
+			return err
+		}
+
+		if resetToken.UsedAt != nil || !resetToken.ExpiresAt.After(time.Now()) {
+			logger.FromContext(ctx).Warnf("Service: Reset token %s is used or expired", resetToken.ID)
+			return ErrInvalidResetToken
+		}
+
+		user, err := s.getUser(ctx, resetToken.UserID)
+		if err != nil {
+			return err
+		}
+		if !user.HasPassword() {
+			logger.FromContext(ctx).Warnf("Service: Reset token %s of user %s without a local password", resetToken.ID, user.ID)
+			return ErrInvalidResetToken
+		}
+
+		if err = s.resetRepository.MarkUsed(ctx, resetToken.ID); err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to mark reset token used: %v", err)
+			return err
+		}
+
+		userID = resetToken.UserID
+		if err = s.setPassword(ctx, userID, newPassword); err != nil {
+			return err
+		}
+
+		return s.audit(ctx, userID, entity.AuditActionPasswordReset, entity.AuditTargetUser, userID, nil, nil)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx).Infof("Service: Password of user %s reset", userID)
+	return nil
+}
+
+// setPassword stores the new password hash and revokes every session, so
+// refresh tokens issued with the old password stop working. Must be called
+// within a transaction.
+func (s *Service) setPassword(ctx context.Context, userID uuid.UUID, password string) error {
+	hash, err := s.hasher.HashPassword(password)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to hash password: %v", err)
+		return err
+	}
+
+	if err = s.userRepository.UpdatePassword(ctx, userID, hash); err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to update password: %v", err)
+		if errors.Is(err, repository.ErrNoUserFound) {
+			return ErrNoUserFound
+		}
+		return err
+	}
+
+	if err = s.sessionRepository.RevokeAllByUser(ctx, userID); err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to revoke sessions: %v", err)
+		return err
+	}
+	return nil
+}
+
+// rehashPassword upgrades a hash written with an outdated algorithm or
+// parameters while the plain password is at hand. Sessions are kept, the
+// password did not change. Must be called within a transaction.
+func (s *Service) rehashPassword(ctx context.Context, user entity.User, password string) error {
+	if !s.hasher.NeedsRehash(user.PasswordHash) {
+		return nil
+	}
+
+	hash, err := s.hasher.HashPassword(password)
+	if err != nil {
+		// The old hash still works, so the login goes on
+		logger.FromContext(ctx).Warnf("Service: Failed to rehash password: %v", err)
+		return nil
+	}
+
+	if err = s.userRepository.UpdatePassword(ctx, user.ID, hash); err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to store rehashed password: %v", err)
+		return err
+	}
+
+	logger.FromContext(ctx).Infof("Service: Password hash of user %s upgraded", user.ID)
+	return nil
+}
+
+func generateToken(prefix string) (string, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return prefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
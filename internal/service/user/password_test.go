@@ -0,0 +1,300 @@
+package user_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/repository"
+	service "github.com/4udiwe/avito-pvz/internal/service/user"
+	"github.com/4udiwe/avito-pvz/pkg/hasher"
+	"github.com/4udiwe/avito-pvz/pkg/notifier"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestChangePassword(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		arbitraryErr = errors.New("arbitrary error")
+		userID       = uuid.New()
+		oldPassword  = "OldPassword1"
+		newPassword  = "NewPassword1"
+		user         = entity.User{ID: userID, Email: "user@mail.com", PasswordHash: "old hash"}
+	)
+
+	type MockBehavior func(m serviceMocks)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		wantErr      error
+	}{
+		{
+			name: "success",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByID(ctx, userID).Return(user, nil).Times(1)
+				m.hasher.EXPECT().CheckPasswordHash(oldPassword, user.PasswordHash).Return(true).Times(1)
+				m.hasher.EXPECT().HashPassword(newPassword).Return("new hash", nil).Times(1)
+				m.users.EXPECT().UpdatePassword(ctx, userID, "new hash").Return(nil).Times(1)
+				m.sessions.EXPECT().RevokeAllByUser(ctx, userID).Return(nil).Times(1)
+				m.audit.EXPECT().Create(ctx, auditRecord(userID, entity.AuditActionPasswordChanged, userID, "", "")).Return(nil).Times(1)
+			},
+		},
+		{
+			name: "wrong old password",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByID(ctx, userID).Return(user, nil).Times(1)
+				m.hasher.EXPECT().CheckPasswordHash(oldPassword, user.PasswordHash).Return(false).Times(1)
+			},
+			wantErr: service.ErrInvalidCredentials,
+		},
+		{
+			name: "user not found",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByID(ctx, userID).Return(entity.User{}, repository.ErrNoUserFound).Times(1)
+			},
+			wantErr: service.ErrNoUserFound,
+		},
+		{
+			name: "revoke sessions error",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByID(ctx, userID).Return(user, nil).Times(1)
+				m.hasher.EXPECT().CheckPasswordHash(oldPassword, user.PasswordHash).Return(true).Times(1)
+				m.hasher.EXPECT().HashPassword(newPassword).Return("new hash", nil).Times(1)
+				m.users.EXPECT().UpdatePassword(ctx, userID, "new hash").Return(nil).Times(1)
+				m.sessions.EXPECT().RevokeAllByUser(ctx, userID).Return(arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s, m := newService(ctrl)
+			tc.mockBehavior(m)
+
+			err := s.ChangePassword(ctx, userID, oldPassword, newPassword)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestRequestPasswordReset(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		arbitraryErr = errors.New("arbitrary error")
+		email        = "user@mail.com"
+		user         = entity.User{ID: uuid.New(), Email: email, PasswordHash: "hash"}
+	)
+
+	type MockBehavior func(m serviceMocks)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		wantErr      error
+	}{
+		{
+			name: "unknown email is not reported",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByEmail(ctx, email).Return(entity.User{}, repository.ErrNoUserFound).Times(1)
+			},
+		},
+		{
+			name: "user of the identity provider is not reported",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				idpOnly := user
+				idpOnly.PasswordHash = ""
+				m.users.EXPECT().GetByEmail(ctx, email).Return(idpOnly, nil).Times(1)
+			},
+		},
+		{
+			name: "repository error",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByEmail(ctx, email).Return(user, nil).Times(1)
+				m.resets.EXPECT().InvalidateByUser(ctx, user.ID).Return(nil).Times(1)
+				m.resets.EXPECT().Create(ctx, gomock.Any()).Return(entity.PasswordResetToken{}, arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+		{
+			name: "notifier error",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByEmail(ctx, email).Return(user, nil).Times(1)
+				m.resets.EXPECT().InvalidateByUser(ctx, user.ID).Return(nil).Times(1)
+				m.resets.EXPECT().Create(ctx, gomock.Any()).Return(entity.PasswordResetToken{}, nil).Times(1)
+				m.notifier.EXPECT().Notify(ctx, gomock.Any()).Return(arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s, m := newService(ctrl)
+			tc.mockBehavior(m)
+
+			err := s.RequestPasswordReset(ctx, email)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestRequestPasswordReset_StoresOnlyHash(t *testing.T) {
+	ctx := context.Background()
+	user := entity.User{ID: uuid.New(), Email: "user@mail.com", PasswordHash: "hash"}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s, m := newService(ctrl)
+
+	var stored entity.PasswordResetToken
+	var sent notifier.Message
+	withinTx(ctx, m.tx)
+	m.users.EXPECT().GetByEmail(ctx, user.Email).Return(user, nil).Times(1)
+	m.resets.EXPECT().InvalidateByUser(ctx, user.ID).Return(nil).Times(1)
+	m.resets.EXPECT().Create(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, token entity.PasswordResetToken) (entity.PasswordResetToken, error) {
+			stored = token
+			return token, nil
+		}).Times(1)
+	m.notifier.EXPECT().Notify(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, msg notifier.Message) error {
+			sent = msg
+			return nil
+		}).Times(1)
+
+	err := s.RequestPasswordReset(ctx, user.Email)
+	assert.NoError(t, err)
+
+	token := sent.Body[strings.LastIndex(sent.Body, " ")+1:]
+	assert.Equal(t, user.Email, sent.Recipient)
+	assert.Equal(t, user.ID, stored.UserID)
+	assert.True(t, strings.HasPrefix(token, "pvzr_"), token)
+	assert.Equal(t, hasher.HashToken(token), stored.TokenHash)
+	assert.NotContains(t, stored.TokenHash, token)
+	assert.WithinDuration(t, time.Now().Add(resetTokenTTL), stored.ExpiresAt, time.Minute)
+}
+
+func TestResetPassword(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		arbitraryErr = errors.New("arbitrary error")
+		token        = "reset-token"
+		newPassword  = "NewPassword1"
+		userID       = uuid.New()
+		tokenID      = uuid.New()
+		user         = entity.User{ID: userID, Email: "user@mail.com", PasswordHash: "old hash"}
+		usedAt       = time.Now().Add(-time.Minute)
+		active       = entity.PasswordResetToken{
+			ID:        tokenID,
+			UserID:    userID,
+			TokenHash: hasher.HashToken(token),
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+	)
+
+	type MockBehavior func(m serviceMocks)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		wantErr      error
+	}{
+		{
+			name: "success",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.resets.EXPECT().GetByHashForUpdate(ctx, hasher.HashToken(token)).Return(active, nil).Times(1)
+				m.users.EXPECT().GetByID(ctx, userID).Return(user, nil).Times(1)
+				m.resets.EXPECT().MarkUsed(ctx, tokenID).Return(nil).Times(1)
+				m.hasher.EXPECT().HashPassword(newPassword).Return("new hash", nil).Times(1)
+				m.users.EXPECT().UpdatePassword(ctx, userID, "new hash").Return(nil).Times(1)
+				m.sessions.EXPECT().RevokeAllByUser(ctx, userID).Return(nil).Times(1)
+				m.audit.EXPECT().Create(ctx, auditRecord(userID, entity.AuditActionPasswordReset, userID, "", "")).Return(nil).Times(1)
+			},
+		},
+		{
+			name: "unknown token",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.resets.EXPECT().GetByHashForUpdate(ctx, hasher.HashToken(token)).
+					Return(entity.PasswordResetToken{}, repository.ErrNoResetTokenFound).Times(1)
+			},
+			wantErr: service.ErrInvalidResetToken,
+		},
+		{
+			name: "used token",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				used := active
+				used.UsedAt = &usedAt
+				m.resets.EXPECT().GetByHashForUpdate(ctx, hasher.HashToken(token)).Return(used, nil).Times(1)
+			},
+			wantErr: service.ErrInvalidResetToken,
+		},
+		{
+			name: "expired token",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				expired := active
+				expired.ExpiresAt = time.Now().Add(-time.Minute)
+				m.resets.EXPECT().GetByHashForUpdate(ctx, hasher.HashToken(token)).Return(expired, nil).Times(1)
+			},
+			wantErr: service.ErrInvalidResetToken,
+		},
+		{
+			name: "user of the identity provider",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.resets.EXPECT().GetByHashForUpdate(ctx, hasher.HashToken(token)).Return(active, nil).Times(1)
+				idpOnly := user
+				idpOnly.PasswordHash = ""
+				m.users.EXPECT().GetByID(ctx, userID).Return(idpOnly, nil).Times(1)
+			},
+			wantErr: service.ErrInvalidResetToken,
+		},
+		{
+			name: "update password error",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.resets.EXPECT().GetByHashForUpdate(ctx, hasher.HashToken(token)).Return(active, nil).Times(1)
+				m.users.EXPECT().GetByID(ctx, userID).Return(user, nil).Times(1)
+				m.resets.EXPECT().MarkUsed(ctx, tokenID).Return(nil).Times(1)
+				m.hasher.EXPECT().HashPassword(newPassword).Return("new hash", nil).Times(1)
+				m.users.EXPECT().UpdatePassword(ctx, userID, "new hash").Return(arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s, m := newService(ctrl)
+			tc.mockBehavior(m)
+
+			err := s.ResetPassword(ctx, token, newPassword)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
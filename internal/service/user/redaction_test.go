@@ -0,0 +1,287 @@
+package user_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/internal/auth"
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	"github.com/4udiwe/avito-pvz/internal/repository"
+	"github.com/4udiwe/avito-pvz/pkg/hasher"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/4udiwe/avito-pvz/pkg/notifier"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// captureLogs sends the log of the test, redaction included, to a buffer.
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+
+	var out bytes.Buffer
+	logrus.SetOutput(&out)
+	t.Cleanup(func() {
+		logrus.SetOutput(os.Stderr)
+		_ = logger.Setup("info", logger.FormatJSON)
+	})
+	require.NoError(t, logger.Setup("debug", logger.FormatJSON))
+	return &out
+}
+
+func newSigner(t *testing.T) *auth.Auth {
+	t.Helper()
+
+	signer, err := auth.New(auth.Options{
+		Algorithm:     auth.AlgorithmHS256,
+		AccessSecret:  strings.Repeat("a", auth.MinSecretLength),
+		RefreshSecret: strings.Repeat("r", auth.MinSecretLength),
+	})
+	require.NoError(t, err)
+	return signer
+}
+
+// TestNoSecretsInLogs runs register, login and refresh with a real password
+// hasher and token signer and checks that neither the hash nor a token
+// reaches the log, even when the repositories log the user and the caller
+// logs the tokens with %+v.
+func TestNoSecretsInLogs(t *testing.T) {
+	const (
+		email    = "email123@gmail.com"
+		password = "12345678"
+	)
+	var (
+		ctx    = context.Background()
+		client = entity.ClientInfo{UserAgent: "scanner/1.0", IP: "10.0.0.1"}
+	)
+
+	out := captureLogs(t)
+
+	passwords, err := hasher.NewArgon2(hasher.Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32})
+	require.NoError(t, err)
+	signer := newSigner(t)
+
+	ctrl := gomock.NewController(t)
+	s, m := newService(ctrl)
+
+	m.tx.EXPECT().WithinTransaction(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		}).AnyTimes()
+	m.hasher.EXPECT().HashPassword(gomock.Any()).DoAndReturn(passwords.HashPassword).AnyTimes()
+	m.hasher.EXPECT().CheckPasswordHash(gomock.Any(), gomock.Any()).DoAndReturn(passwords.CheckPasswordHash).AnyTimes()
+	m.hasher.EXPECT().NeedsRehash(gomock.Any()).DoAndReturn(passwords.NeedsRehash).AnyTimes()
+	m.auth.EXPECT().GenerateTokens(gomock.Any(), gomock.Any()).DoAndReturn(signer.GenerateTokens).AnyTimes()
+	m.auth.EXPECT().ValidateRefreshToken(gomock.Any()).DoAndReturn(signer.ValidateRefreshToken).AnyTimes()
+	m.audit.EXPECT().Create(ctx, gomock.Any()).Return(nil).AnyTimes()
+
+	// The repositories log users the way repo_user did before users got a
+	// String method
+	var (
+		stored  entity.User
+		session entity.Session
+	)
+	m.users.EXPECT().GetByEmail(ctx, email).Return(entity.User{}, repository.ErrNoUserFound).Times(1)
+	m.users.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, user entity.User) (entity.User, error) {
+		user.ID = uuid.New()
+		stored = user
+		logger.FromContext(ctx).Infof("User created: %+v", user)
+		return user, nil
+	}).Times(1)
+
+	_, err = s.Register(ctx, email, password, entity.RoleEmployee, "")
+	require.NoError(t, err)
+	require.NotEmpty(t, stored.PasswordHash)
+
+	m.users.EXPECT().GetByEmail(ctx, email).DoAndReturn(func(ctx context.Context, email string) (entity.User, error) {
+		logger.FromContext(ctx).Infof("Fetched user: %+v", stored)
+		return stored, nil
+	}).Times(1)
+	notLocked(ctx, m, email, client.IP)
+	m.failures.EXPECT().Reset(ctx, entity.LoginScopeEmail, email).Return(nil).Times(1)
+	noMFA(ctx, m, stored.ID)
+	m.sessions.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, s entity.Session) (entity.Session, error) {
+		session = s
+		return s, nil
+	}).Times(1)
+
+	login, err := s.Authenticate(ctx, email, password, client)
+	require.NoError(t, err)
+	require.NotNil(t, login.Tokens)
+	logger.FromContext(ctx).Infof("Issued tokens: %+v", login.Tokens)
+
+	m.sessions.EXPECT().GetByIDForUpdate(ctx, session.ID).Return(session, nil).Times(1)
+	m.users.EXPECT().GetByID(ctx, stored.ID).DoAndReturn(func(ctx context.Context, _ uuid.UUID) (entity.User, error) {
+		logger.FromContext(ctx).Debugf("Fetched user: %v", stored)
+		return stored, nil
+	}).Times(1)
+	m.sessions.EXPECT().Rotate(ctx, gomock.Any()).Return(nil).Times(1)
+
+	refreshed, err := s.RefreshTokens(ctx, login.Tokens.RefreshToken, client)
+	require.NoError(t, err)
+	logger.FromContext(ctx).Infof("Issued tokens: %v", *refreshed)
+
+	logged := out.String()
+	require.Contains(t, logged, "User created")
+	for name, secret := range map[string]string{
+		"password hash":         stored.PasswordHash,
+		"password":              password,
+		"email":                 email,
+		"access token":          login.Tokens.AccessToken,
+		"refresh token":         login.Tokens.RefreshToken,
+		"rotated access token":  refreshed.AccessToken,
+		"rotated refresh token": refreshed.RefreshToken,
+	} {
+		assert.NotContains(t, logged, secret, "%s reached the log", name)
+	}
+	// A hash or token cut by the formatter would not match whole
+	assert.NotContains(t, logged, "$argon2")
+	assert.NotContains(t, logged, "eyJ")
+}
+
+// TestNoMFATokenInLogs runs a login that needs the second factor and its
+// verification, with the pending token logged by the caller and by the
+// challenge repository.
+func TestNoMFATokenInLogs(t *testing.T) {
+	const (
+		email    = "moderator@mail.com"
+		password = "12345678"
+		hash     = "hashed_password_123"
+	)
+	var (
+		ctx       = context.Background()
+		client    = entity.ClientInfo{UserAgent: "scanner/1.0", IP: "10.0.0.1"}
+		user      = entity.User{ID: uuid.New(), Email: email, PasswordHash: hash, Role: entity.RoleModerator}
+		confirmed = time.Now().Add(-24 * time.Hour)
+		enabled   = entity.UserMFA{UserID: user.ID, Secret: mfaEncrypted, ConfirmedAt: &confirmed}
+		signer    = newSigner(t)
+	)
+
+	out := captureLogs(t)
+
+	ctrl := gomock.NewController(t)
+	s, m := newServiceWithPolicy(ctrl, mfaPolicy())
+
+	m.tx.EXPECT().WithinTransaction(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		}).AnyTimes()
+	notLocked(ctx, m, email, client.IP)
+	m.users.EXPECT().GetByEmail(ctx, email).Return(user, nil).Times(1)
+	m.hasher.EXPECT().CheckPasswordHash(password, hash).Return(true).Times(1)
+	m.hasher.EXPECT().NeedsRehash(hash).Return(false).Times(1)
+	m.mfa.EXPECT().Get(ctx, user.ID).Return(enabled, nil).Times(1)
+
+	var challenge entity.MFAChallenge
+	m.mfaCh.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, c entity.MFAChallenge) (entity.MFAChallenge, error) {
+		c.ID = uuid.New()
+		challenge = c
+		logger.FromContext(ctx).Infof("MFA challenge created: %+v", c)
+		return c, nil
+	}).Times(1)
+
+	login, err := s.Authenticate(ctx, email, password, client)
+	require.NoError(t, err)
+	require.NotNil(t, login.MFA)
+	logger.FromContext(ctx).Infof("Second factor pending: %+v", *login.MFA)
+
+	m.mfaCh.EXPECT().GetByHashForUpdate(ctx, challenge.TokenHash).Return(challenge, nil).Times(1)
+	m.users.EXPECT().GetByID(ctx, user.ID).Return(user, nil).Times(1)
+	notLocked(ctx, m, email, client.IP)
+	m.mfa.EXPECT().GetForUpdate(ctx, user.ID).Return(enabled, nil).Times(1)
+	m.crypt.EXPECT().Decrypt(mfaEncrypted, user.ID[:]).Return([]byte(mfaSecret), nil).Times(1)
+	m.mfa.EXPECT().SetLastUsedStep(ctx, user.ID, recentStep()).Return(nil).Times(1)
+	m.mfaCh.EXPECT().Delete(ctx, challenge.ID).Return(nil).Times(1)
+	m.failures.EXPECT().Reset(ctx, entity.LoginScopeEmail, email).Return(nil).Times(1)
+	m.auth.EXPECT().GenerateTokens(gomock.Any(), gomock.Any()).DoAndReturn(signer.GenerateTokens).Times(1)
+	m.sessions.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, s entity.Session) (entity.Session, error) {
+		return s, nil
+	}).Times(1)
+
+	logger.FromContext(ctx).Infof("Verifying mfa_token %s", login.MFA.Token)
+	tokens, _, err := s.VerifyMFA(ctx, login.MFA.Token, currentCode(t), client)
+	require.NoError(t, err)
+	logger.FromContext(ctx).Infof("Issued tokens: %+v", tokens)
+
+	logged := out.String()
+	require.Contains(t, logged, "Second factor pending")
+	for name, secret := range map[string]string{
+		"mfa token":     login.MFA.Token,
+		"access token":  tokens.AccessToken,
+		"refresh token": tokens.RefreshToken,
+	} {
+		assert.NotContains(t, logged, secret, "%s reached the log", name)
+	}
+	assert.NotContains(t, logged, "pvzm_")
+}
+
+// TestNoResetTokenInLogs runs a password reset through the log notifier,
+// which writes the token into the message, and logs the token on use. The
+// token taken from the notifier output must still reset the password: the
+// notifier is the delivery channel and is not redacted.
+func TestNoResetTokenInLogs(t *testing.T) {
+	const newPassword = "NewPassword1"
+	var (
+		ctx  = context.Background()
+		user = entity.User{ID: uuid.New(), Email: "user@mail.com", PasswordHash: "old hash"}
+	)
+
+	out := captureLogs(t)
+
+	ctrl := gomock.NewController(t)
+	s, m := newService(ctrl)
+
+	m.tx.EXPECT().WithinTransaction(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		}).AnyTimes()
+	m.users.EXPECT().GetByEmail(ctx, user.Email).Return(user, nil).Times(1)
+	m.resets.EXPECT().InvalidateByUser(ctx, user.ID).Return(nil).Times(1)
+
+	var stored entity.PasswordResetToken
+	m.resets.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, token entity.PasswordResetToken) (entity.PasswordResetToken, error) {
+		token.ID = uuid.New()
+		stored = token
+		return token, nil
+	}).Times(1)
+
+	var sent bytes.Buffer
+	m.notifier.EXPECT().Notify(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, msg notifier.Message) error {
+		return notifier.NewLogNotifierTo(&sent).Notify(ctx, msg)
+	}).Times(1)
+
+	require.NoError(t, s.RequestPasswordReset(ctx, user.Email))
+
+	var delivered struct {
+		Recipient string `json:"recipient"`
+		Msg       string `json:"msg"`
+	}
+	require.NoError(t, json.Unmarshal(sent.Bytes(), &delivered))
+	assert.Equal(t, user.Email, delivered.Recipient)
+	token := delivered.Msg[strings.LastIndex(delivered.Msg, " ")+1:]
+	require.True(t, strings.HasPrefix(token, "pvzr_"), delivered.Msg)
+	require.Equal(t, hasher.HashToken(token), stored.TokenHash)
+
+	m.resets.EXPECT().GetByHashForUpdate(ctx, stored.TokenHash).Return(stored, nil).Times(1)
+	m.users.EXPECT().GetByID(ctx, user.ID).Return(user, nil).Times(1)
+	m.resets.EXPECT().MarkUsed(ctx, stored.ID).Return(nil).Times(1)
+	m.hasher.EXPECT().HashPassword(newPassword).Return("new hash", nil).Times(1)
+	m.users.EXPECT().UpdatePassword(ctx, user.ID, "new hash").Return(nil).Times(1)
+	m.sessions.EXPECT().RevokeAllByUser(ctx, user.ID).Return(nil).Times(1)
+	m.audit.EXPECT().Create(ctx, gomock.Any()).Return(nil).Times(1)
+
+	logger.FromContext(ctx).WithField("body", "token="+token).Infof("Resetting password with %s", token)
+	require.NoError(t, s.ResetPassword(ctx, token, newPassword))
+
+	logged := out.String()
+	require.Contains(t, logged, "Password reset")
+	assert.NotContains(t, logged, token, "reset token reached the log")
+	assert.NotContains(t, logged, "pvzr_")
+}
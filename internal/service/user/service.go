@@ -3,55 +3,162 @@ package user
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/4udiwe/avito-pvz/internal/auth"
 	"github.com/4udiwe/avito-pvz/internal/entity"
 	"github.com/4udiwe/avito-pvz/internal/repository"
+	"github.com/4udiwe/avito-pvz/pkg/hasher"
+	"github.com/4udiwe/avito-pvz/pkg/logger"
+	"github.com/4udiwe/avito-pvz/pkg/redact"
 	"github.com/4udiwe/avito-pvz/pkg/transactor"
 	"github.com/google/uuid"
-	"github.com/sirupsen/logrus"
 )
 
+// Policy controls registration, password reset tokens, login throttling,
+// single sign-on and the second factor. With OpenRegistration off a user
+// can only register with an invitation.
+type Policy struct {
+	ResetTokenTTL    time.Duration
+	InvitationTTL    time.Duration
+	OpenRegistration bool
+	Login            LoginPolicy
+	OIDC             OIDCPolicy
+	MFA              MFAPolicy
+}
+
 type Service struct {
-	userRepository UserRepository
-	txManager      transactor.Transactor
-	auth           Auth
-	hasher         Hasher
+	userRepository    UserRepository
+	sessionRepository SessionRepository
+	txManager         transactor.Transactor
+	auth              Auth
+	hasher            Hasher
+	revoker           TokenRevoker
+	resetRepository   ResetTokenRepository
+	inviteRepository  InvitationRepository
+	notifier          Notifier
+	failureRepository LoginFailureRepository
+	metrics           Metrics
+	auditRepository   AuditRepository
+	disabledUsers     DisabledUsers
+	oidcProvider      OIDCProvider
+	oidcStates        OIDCStateRepository
+	identities        IdentityRepository
+	mfaRepository     MFARepository
+	mfaChallenges     MFAChallengeRepository
+	encryptor         Encryptor
+	policy            Policy
 }
 
-func New(r UserRepository, tx transactor.Transactor, a Auth, h Hasher) *Service {
+func New(
+	r UserRepository,
+	sr SessionRepository,
+	tx transactor.Transactor,
+	a Auth,
+	h Hasher,
+	rv TokenRevoker,
+	rt ResetTokenRepository,
+	iv InvitationRepository,
+	n Notifier,
+	lf LoginFailureRepository,
+	m Metrics,
+	ar AuditRepository,
+	du DisabledUsers,
+	op OIDCProvider,
+	st OIDCStateRepository,
+	ir IdentityRepository,
+	mr MFARepository,
+	mc MFAChallengeRepository,
+	e Encryptor,
+	policy Policy,
+) *Service {
 	return &Service{
-		userRepository: r,
-		txManager:      tx,
-		auth:           a,
-		hasher:         h,
+		userRepository:    r,
+		sessionRepository: sr,
+		txManager:         tx,
+		auth:              a,
+		hasher:            h,
+		revoker:           rv,
+		resetRepository:   rt,
+		inviteRepository:  iv,
+		notifier:          n,
+		failureRepository: lf,
+		metrics:           m,
+		auditRepository:   ar,
+		disabledUsers:     du,
+		oidcProvider:      op,
+		oidcStates:        st,
+		identities:        ir,
+		mfaRepository:     mr,
+		mfaChallenges:     mc,
+		encryptor:         e,
+		policy:            policy,
 	}
 }
 
 func (s *Service) DummyLogin(ctx context.Context, role entity.UserRole) (string, error) {
-	logrus.Infof("Service: generating dummy login with role %s", role)
+	logger.FromContext(ctx).Infof("Service: generating dummy login with role %s", role)
 
 	user := entity.User{
 		Email: "dummyemail@google.com",
 		Role:  role,
 	}
 
-	tokens, err := s.auth.GenerateTokens(user)
+	tokens, err := s.auth.GenerateTokens(user, uuid.Nil)
 	if err != nil {
-		logrus.Errorf("Service: Failed to generate tokens: %v", err)
+		logger.FromContext(ctx).Errorf("Service: Failed to generate tokens: %v", err)
 		return "", err
 	}
 
-	logrus.Infof("Service: generated tokens for user dummy user with role %s", role)
-	return tokens.RefreshToken, nil
+	logger.FromContext(ctx).Infof("Service: generated tokens for user dummy user with role %s", role)
+	return tokens.AccessToken, nil
+}
+
+// Register creates a user, who then logs in with Authenticate. With an
+// invitation code the role and point assignments come from the invitation,
+// which must be issued for the same email. Without one, registration is
+// allowed only when Policy.OpenRegistration is set and always creates an
+// employee: role is ignored, so nobody can register themselves a moderator.
+func (s *Service) Register(ctx context.Context, email string, password string, role entity.UserRole, invitationCode string) (entity.User, error) {
+	logger.FromContext(ctx).Infof("Service: Registering user %s", redact.Email(email))
+
+	if invitationCode == "" {
+		if !s.policy.OpenRegistration {
+			logger.FromContext(ctx).Warnf("Service: Registration of %s without invitation", redact.Email(email))
+			return entity.User{}, ErrRegistrationClosed
+		}
+		if role != "" && role != entity.RoleEmployee {
+			logger.FromContext(ctx).Warnf("Service: Ignoring role %s in open registration of %s", role, redact.Email(email))
+		}
+		role = entity.RoleEmployee
+	}
+
+	return s.createUser(ctx, email, password, role, invitationCode)
 }
 
-func (s *Service) Register(ctx context.Context, email string, password string, role entity.UserRole) (*auth.Tokens, error) {
-	logrus.Infof("Service: Registering user %s with role %s", email, role)
+// CreateUser creates a user with any role, bypassing invitations. It is
+// meant for the create-user command that sets up the first moderator and
+// must not be reachable over HTTP.
+func (s *Service) CreateUser(ctx context.Context, email string, password string, role entity.UserRole) (entity.User, error) {
+	logger.FromContext(ctx).Infof("Service: Creating user %s with role %s", redact.Email(email), role)
+
+	return s.createUser(ctx, email, password, role, "")
+}
 
-	var tokens *auth.Tokens
+func (s *Service) createUser(ctx context.Context, email string, password string, role entity.UserRole, invitationCode string) (entity.User, error) {
+	var user entity.User
 
 	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		var invitation *entity.Invitation
+		if invitationCode != "" {
+			inv, err := s.useInvitation(ctx, email, invitationCode)
+			if err != nil {
+				return err
+			}
+			invitation = &inv
+			role = inv.Role
+		}
+
 		// Check if there is no user with this email
 		if _, err := s.userRepository.GetByEmail(ctx, email); !errors.Is(err, repository.ErrNoUserFound) {
 			return ErrUserAlreadyExists
@@ -61,147 +168,322 @@ func (s *Service) Register(ctx context.Context, email string, password string, r
 		hash, err := s.hasher.HashPassword(password)
 
 		if err != nil {
-			logrus.Errorf("Service: Failed to hash password: %v", err)
+			logger.FromContext(ctx).Errorf("Service: Failed to hash password: %v", err)
 			return err
 		}
 
-		user := entity.User{
+		user, err = s.userRepository.Create(ctx, entity.User{
 			Email:        email,
 			PasswordHash: hash,
 			Role:         role,
-		}
-
-		// Generate tokens
-		tokens, err = s.auth.GenerateTokens(user)
+		})
 		if err != nil {
-			logrus.Errorf("Service: Failed to generate tokens: %v", err)
+			if errors.Is(err, repository.ErrNoRoleFound) {
+				return ErrUnknownRole
+			}
+			logger.FromContext(ctx).Errorf("Service: Failed to create user: %v", err)
 			return err
 		}
 
-		// Assigning refresh token to user and saving to DB
-		user.RefreshToken = tokens.RefreshToken
-
-		_, err = s.userRepository.Create(ctx, user)
-		if err != nil {
-			logrus.Errorf("Service: Failed to create user: %v", err)
-			return err
+		if invitation != nil {
+			if err = s.acceptInvitation(ctx, *invitation, user.ID); err != nil {
+				return err
+			}
 		}
-		return err
+
+		return s.audit(ctx, user.ID, entity.AuditActionUserRegistered, entity.AuditTargetUser, user.ID, nil, snapshotOf(user))
 	})
 
 	if err != nil {
-		return nil, err
+		return entity.User{}, err
 	}
 
-	logrus.Infof("Service: Registered user %s", email)
+	logger.FromContext(ctx).Infof("Service: Registered user %s with role %s", user.ID, role)
 
-	return tokens, nil
+	return user, nil
 }
 
-func (s *Service) Authenticate(ctx context.Context, email string, password string) (*auth.Tokens, error) {
-	logrus.Infof("Service: Authenticating user %s", email)
+// Authenticate checks the credentials and starts a session. Unknown emails,
+// wrong passwords and locked logins give the same ErrInvalidCredentials, so
+// the answer does not tell whether an account exists. Failures are counted
+// per email and per IP; see LoginPolicy. Users with a second factor
+// get an MFA pending token instead of tokens; see VerifyMFA.
+func (s *Service) Authenticate(ctx context.Context, email string, password string, client entity.ClientInfo) (*LoginResult, error) {
+	logger.FromContext(ctx).Infof("Service: Authenticating user %s", redact.Email(email))
 
-	var tokens *auth.Tokens
+	var (
+		result  *LoginResult
+		authErr error
+	)
+	keys := loginKeys(email, client)
 
 	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		locked, err := s.isLocked(ctx, keys)
+		if err != nil {
+			return err
+		}
+		// The password is not checked while locked, otherwise the answer
+		// would still reveal a correct guess
+		if locked {
+			s.metrics.FailureInc(failureReasonLocked)
+			authErr = ErrInvalidCredentials
+			return nil
+		}
+
 		// Receiveing user
 		user, err := s.userRepository.GetByEmail(ctx, email)
-		if err != nil {
-			if errors.Is(err, repository.ErrNoUserFound) {
-				logrus.Errorf("Service: No user found by email: %v", err)
-				return ErrNoUserFound
-			}
-			logrus.Errorf("Service: Failed to get user by email: %v", err)
+		if err != nil && !errors.Is(err, repository.ErrNoUserFound) {
+			logger.FromContext(ctx).Errorf("Service: Failed to get user by email: %v", err)
 			return err
 		}
 
-		// Comparing password hashes
-		if !s.hasher.CheckPasswordHash(password, user.PasswordHash) {
-			logrus.Warnf("Service: Invalid password for user %s", email)
-			return ErrInvalidCredentials
+		// Comparing password hashes. Failures must be persisted, so the
+		// transaction is committed and the error is returned afterwards
+		if err != nil || !user.HasPassword() || !s.hasher.CheckPasswordHash(password, user.PasswordHash) {
+			logger.FromContext(ctx).Warnf("Service: Invalid credentials for %s", redact.Email(email))
+			s.metrics.FailureInc(failureReasonInvalidCredentials)
+			authErr = ErrInvalidCredentials
+			return s.recordFailure(ctx, keys)
 		}
 
-		// Generating new tokens
-		tokens, err = s.auth.GenerateTokens(user)
-		if err != nil {
-			logrus.Errorf("Service: Failed to generate tokens: %v", err)
+		if user.Disabled() {
+			logger.FromContext(ctx).Warnf("Service: Login of disabled user %s", user.ID)
+			authErr = ErrUserDisabled
+			return nil
+		}
+
+		if err = s.rehashPassword(ctx, user, password); err != nil {
 			return err
 		}
 
-		// Updating refresh token for user
-		_, err = s.userRepository.UpdateRefreshToken(ctx, user.ID, tokens.RefreshToken)
-		if err != nil {
-			logrus.Errorf("Service: Failed to update refresh token: %v", err)
+		if result, err = s.login(ctx, user, client, false); err != nil {
+			return err
 		}
-		return err
+		// With a second factor pending the counter is kept, so wrong codes
+		// add to the password failures; VerifyMFA resets it
+		if result.Tokens == nil {
+			return nil
+		}
+		return s.resetFailures(ctx, keys)
 	})
 
 	if err != nil {
 		return nil, err
 	}
+	if authErr != nil {
+		return nil, authErr
+	}
 
-	logrus.Infof("Service: Authenticated user %s", email)
-	return tokens, nil
+	logger.FromContext(ctx).Infof("Service: Authenticated user %s", redact.Email(email))
+	return result, nil
 }
 
-func (s *Service) RefreshTokens(ctx context.Context, refreshToken string) (*auth.Tokens, error) {
-	logrus.Info("Service: Refreshing tokens")
+// RefreshTokens rotates the refresh token of a session. Presenting a token
+// that has already been rotated means it leaked, so the whole session
+// (the token family) is revoked. So is a session that did not pass a second
+// factor now required for the user's role.
+func (s *Service) RefreshTokens(ctx context.Context, refreshToken string, client entity.ClientInfo) (*auth.Tokens, error) {
+	logger.FromContext(ctx).Info("Service: Refreshing tokens")
 
 	// Validating refresh token
-	email, err := s.auth.ValidateRefreshToken(refreshToken)
+	claims, err := s.auth.ValidateRefreshToken(refreshToken)
 	if err != nil {
-		logrus.Warnf("Service: Invalid refresh token: %v", err)
+		logger.FromContext(ctx).Warnf("Service: Invalid refresh token: %v", err)
 		return nil, ErrInvalidRefreshToken
 	}
-	var tokens *auth.Tokens
+
+	var (
+		tokens     *auth.Tokens
+		reused     bool
+		mfaMissing bool
+	)
 
 	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
-		// Receiving user
-		user, err := s.userRepository.GetByEmail(ctx, email)
+		session, err := s.sessionRepository.GetByIDForUpdate(ctx, claims.SessionID)
 		if err != nil {
-			logrus.Errorf("Service: Failed to get user by email: %v", err)
+			if errors.Is(err, repository.ErrNoSessionFound) {
+				return ErrInvalidRefreshToken
+			}
+			logger.FromContext(ctx).Errorf("Service: Failed to get session: %v", err)
 			return err
 		}
 
-		// Check if refresh token is the same as in DB
-		if user.RefreshToken != refreshToken {
-			logrus.Warnf("Service: Refresh token mismatch for user %s", email)
+		if session.RevokedAt != nil || !session.ExpiresAt.After(time.Now()) {
+			logger.FromContext(ctx).Warnf("Service: Refresh token of inactive session %s", session.ID)
 			return ErrInvalidRefreshToken
 		}
 
-		// Generating new tokens
-		tokens, err = s.auth.GenerateTokens(user)
+		// Token was already rotated: revoke the family and commit
+		if session.RefreshTokenHash != hasher.HashToken(refreshToken) {
+			logger.FromContext(ctx).Warnf("Service: Refresh token reuse detected for session %s, revoking", session.ID)
+			reused = true
+			if err := s.sessionRepository.Revoke(ctx, session.UserID, session.ID); err != nil {
+				logger.FromContext(ctx).Errorf("Service: Failed to revoke session: %v", err)
+				return err
+			}
+			return nil
+		}
+
+		user, err := s.userRepository.GetByID(ctx, session.UserID)
 		if err != nil {
-			logrus.Errorf("Service: Failed to generate tokens: %v", err)
+			if errors.Is(err, repository.ErrNoUserFound) {
+				return ErrInvalidRefreshToken
+			}
+			logger.FromContext(ctx).Errorf("Service: Failed to get user: %v", err)
 			return err
 		}
 
-		// Updating refresh token for user
-		_, err = s.userRepository.UpdateRefreshToken(ctx, user.ID, tokens.RefreshToken)
+		if user.Disabled() {
+			logger.FromContext(ctx).Warnf("Service: Refresh of disabled user %s", user.ID)
+			return ErrUserDisabled
+		}
+
+		// The session was started before the second factor became
+		// required for the user, e.g. before a promotion: end it and
+		// commit, the next login asks for the second factor
+		if !session.MFAVerified && s.policy.MFA.required(user.Role) {
+			logger.FromContext(ctx).Warnf("Service: Session %s of user %s did not pass the required second factor, revoking", session.ID, user.ID)
+			mfaMissing = true
+			if err := s.sessionRepository.Revoke(ctx, session.UserID, session.ID); err != nil {
+				logger.FromContext(ctx).Errorf("Service: Failed to revoke session: %v", err)
+				return err
+			}
+			return nil
+		}
+
+		// Generating new tokens
+		tokens, err = s.auth.GenerateTokens(user, session.ID)
 		if err != nil {
-			logrus.Errorf("Service: Failed to update refresh token: %v", err)
+			logger.FromContext(ctx).Errorf("Service: Failed to generate tokens: %v", err)
+			return err
 		}
-		return err
+
+		session.RefreshTokenHash = hasher.HashToken(tokens.RefreshToken)
+		session.UserAgent = client.UserAgent
+		session.IP = client.IP
+		session.ExpiresAt = time.Now().Add(auth.RefreshTokenTTL)
+
+		if err := s.sessionRepository.Rotate(ctx, session); err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to rotate session: %v", err)
+			return err
+		}
+		return nil
 	})
 
 	if err != nil {
 		return nil, err
 	}
+	if reused {
+		return nil, ErrInvalidRefreshToken
+	}
+	if mfaMissing {
+		return nil, ErrMFARequired
+	}
 
-	logrus.Infof("Service: Tokens refreshed for user %s", email)
+	logger.FromContext(ctx).Infof("Service: Tokens refreshed for session %s", claims.SessionID)
 	return tokens, nil
 }
 
-func (s *Service) Logout(ctx context.Context, userID uuid.UUID) error {
-	logrus.Infof("Service: Logging out user %d", userID)
+// Logout revokes the current session and the access token identified by
+// jti, so it is rejected before its natural expiry.
+func (s *Service) Logout(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID, jti string, expiresAt time.Time) error {
+	logger.FromContext(ctx).Infof("Service: Logging out user %s", userID)
+
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := s.sessionRepository.Revoke(ctx, userID, sessionID)
+		switch {
+		case errors.Is(err, repository.ErrNoSessionFound):
+		case err != nil:
+			logger.FromContext(ctx).Errorf("Service: Failed to revoke session: %v", err)
+			return err
+		default:
+			if err = s.audit(ctx, userID, entity.AuditActionSessionRevoked, entity.AuditTargetSession, sessionID, nil, nil); err != nil {
+				return err
+			}
+		}
+
+		if err := s.revoker.Revoke(ctx, jti, expiresAt); err != nil {
+			logger.FromContext(ctx).Errorf("Service: Failed to revoke access token: %v", err)
+			return err
+		}
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx).Infof("Service: User %s logged out", userID)
+	return nil
+}
+
+func (s *Service) GetSessions(ctx context.Context, userID uuid.UUID) ([]entity.Session, error) {
+	logger.FromContext(ctx).Infof("Service: Fetching sessions of user %s", userID)
+
+	sessions, err := s.sessionRepository.GetActiveByUser(ctx, userID)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to get sessions: %v", err)
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Infof("Service: Fetched %d sessions of user %s", len(sessions), userID)
+	return sessions, nil
+}
+
+func (s *Service) RevokeSession(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) error {
+	logger.FromContext(ctx).Infof("Service: Revoking session %s of user %s", sessionID, userID)
+
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.sessionRepository.Revoke(ctx, userID, sessionID); err != nil {
+			if errors.Is(err, repository.ErrNoSessionFound) {
+				return ErrNoSessionFound
+			}
+			logger.FromContext(ctx).Errorf("Service: Failed to revoke session: %v", err)
+			return err
+		}
+
+		return s.audit(ctx, userID, entity.AuditActionSessionRevoked, entity.AuditTargetSession, sessionID, nil, nil)
+	})
 
-	// Deleting refresh token (assigning empty string)
-	_, err := s.userRepository.UpdateRefreshToken(ctx, userID, "")
 	if err != nil {
-		logrus.Errorf("Service: Failed to clear refresh token: %v", err)
 		return err
 	}
 
-	logrus.Infof("Service: User %d logged out", userID)
+	logger.FromContext(ctx).Infof("Service: Session %s revoked", sessionID)
 	return nil
 }
+
+// startSession issues tokens for a new device and stores the hash of the
+// refresh token. mfaVerified tells that the login passed a second factor;
+// without it no session is started for roles in MFAPolicy.RequiredRoles.
+// Must be called within a transaction.
+func (s *Service) startSession(ctx context.Context, user entity.User, client entity.ClientInfo, mfaVerified bool) (*auth.Tokens, error) {
+	if !mfaVerified && s.policy.MFA.required(user.Role) {
+		logger.FromContext(ctx).Warnf("Service: Session of user %s without required second factor", user.ID)
+		return nil, ErrMFARequired
+	}
+
+	sessionID := uuid.New()
+
+	tokens, err := s.auth.GenerateTokens(user, sessionID)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to generate tokens: %v", err)
+		return nil, err
+	}
+
+	_, err = s.sessionRepository.Create(ctx, entity.Session{
+		ID:               sessionID,
+		UserID:           user.ID,
+		RefreshTokenHash: hasher.HashToken(tokens.RefreshToken),
+		UserAgent:        client.UserAgent,
+		IP:               client.IP,
+		ExpiresAt:        time.Now().Add(auth.RefreshTokenTTL),
+		MFAVerified:      mfaVerified,
+	})
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Service: Failed to create session: %v", err)
+		return nil, err
+	}
+
+	return tokens, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/4udiwe/avito-pvz/internal/auth"
 	"github.com/4udiwe/avito-pvz/internal/entity"
@@ -11,33 +12,159 @@ import (
 	"github.com/4udiwe/avito-pvz/internal/repository"
 	service "github.com/4udiwe/avito-pvz/internal/service/user"
 	"github.com/4udiwe/avito-pvz/internal/service/user/mocks"
+	"github.com/4udiwe/avito-pvz/pkg/hasher"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
 
+const resetTokenTTL = 30 * time.Minute
+
+const invitationTTL = 72 * time.Hour
+
+var policy = service.Policy{
+	ResetTokenTTL:    resetTokenTTL,
+	InvitationTTL:    invitationTTL,
+	OpenRegistration: true,
+	Login: service.LoginPolicy{
+		MaxEmailFailures: 3,
+		MaxIPFailures:    10,
+		BaseDelay:        time.Second,
+		LockDuration:     15 * time.Minute,
+		FailureWindow:    time.Hour,
+	},
+}
+
+type serviceMocks struct {
+	users    *mocks.MockUserRepository
+	sessions *mocks.MockSessionRepository
+	tx       *mock_transactor.MockTransactor
+	auth     *mocks.MockAuth
+	hasher   *mocks.MockHasher
+	revoker  *mocks.MockTokenRevoker
+	resets   *mocks.MockResetTokenRepository
+	invites  *mocks.MockInvitationRepository
+	notifier *mocks.MockNotifier
+	failures *mocks.MockLoginFailureRepository
+	metrics  *mocks.MockMetrics
+	audit    *mocks.MockAuditRepository
+	disabled *mocks.MockDisabledUsers
+	states   *mocks.MockOIDCStateRepository
+	idents   *mocks.MockIdentityRepository
+	mfa      *mocks.MockMFARepository
+	mfaCh    *mocks.MockMFAChallengeRepository
+	crypt    *mocks.MockEncryptor
+}
+
+func newService(ctrl *gomock.Controller) (*service.Service, serviceMocks) {
+	return newServiceWithPolicy(ctrl, policy)
+}
+
+func newServiceWithPolicy(ctrl *gomock.Controller, p service.Policy) (*service.Service, serviceMocks) {
+	return newServiceWithProvider(ctrl, mocks.NewMockOIDCProvider(ctrl), p)
+}
+
+// newServiceWithProvider builds the service around an identity provider,
+// such as the fake one from oidctest.
+func newServiceWithProvider(ctrl *gomock.Controller, provider service.OIDCProvider, p service.Policy) (*service.Service, serviceMocks) {
+	m := serviceMocks{
+		users:    mocks.NewMockUserRepository(ctrl),
+		sessions: mocks.NewMockSessionRepository(ctrl),
+		tx:       mock_transactor.NewMockTransactor(ctrl),
+		auth:     mocks.NewMockAuth(ctrl),
+		hasher:   mocks.NewMockHasher(ctrl),
+		revoker:  mocks.NewMockTokenRevoker(ctrl),
+		resets:   mocks.NewMockResetTokenRepository(ctrl),
+		invites:  mocks.NewMockInvitationRepository(ctrl),
+		notifier: mocks.NewMockNotifier(ctrl),
+		failures: mocks.NewMockLoginFailureRepository(ctrl),
+		metrics:  mocks.NewMockMetrics(ctrl),
+		audit:    mocks.NewMockAuditRepository(ctrl),
+		disabled: mocks.NewMockDisabledUsers(ctrl),
+		states:   mocks.NewMockOIDCStateRepository(ctrl),
+		idents:   mocks.NewMockIdentityRepository(ctrl),
+		mfa:      mocks.NewMockMFARepository(ctrl),
+		mfaCh:    mocks.NewMockMFAChallengeRepository(ctrl),
+		crypt:    mocks.NewMockEncryptor(ctrl),
+	}
+	return service.New(
+		m.users, m.sessions, m.tx, m.auth, m.hasher, m.revoker, m.resets,
+		m.invites, m.notifier, m.failures, m.metrics, m.audit, m.disabled,
+		provider, m.states, m.idents, m.mfa, m.mfaCh, m.crypt, p,
+	), m
+}
+
+func withinTx(ctx context.Context, tx *mock_transactor.MockTransactor) {
+	tx.EXPECT().WithinTransaction(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+}
+
+// lockedUntil matches a lock time about d from now.
+func lockedUntil(d time.Duration) gomock.Matcher {
+	return gomock.Cond(func(until time.Time) bool {
+		return until.Sub(time.Now().Add(d)).Abs() < time.Minute/2
+	})
+}
+
+// notLocked expects the email and IP login counters to be checked and found unlocked.
+func notLocked(ctx context.Context, m serviceMocks, email string, ip string) {
+	m.failures.EXPECT().GetForUpdate(ctx, entity.LoginScopeEmail, email).
+		Return(entity.LoginFailures{Scope: entity.LoginScopeEmail, Key: email}, nil).Times(1)
+	m.failures.EXPECT().GetForUpdate(ctx, entity.LoginScopeIP, ip).
+		Return(entity.LoginFailures{Scope: entity.LoginScopeIP, Key: ip}, nil).Times(1)
+}
+
+// noMFA expects a login of a user without a second factor.
+func noMFA(ctx context.Context, m serviceMocks, userID uuid.UUID) {
+	m.mfa.EXPECT().Get(ctx, userID).Return(entity.UserMFA{}, repository.ErrNoMFAFound).Times(1)
+}
+
+// tokensOf returns the tokens of a login, or nil.
+func tokensOf(r *service.LoginResult) *auth.Tokens {
+	if r == nil {
+		return nil
+	}
+	return r.Tokens
+}
+
+// newSession matches a session created for the user with the given refresh
+// token and client by a login without a second factor.
+func newSession(userID uuid.UUID, refreshToken string, client entity.ClientInfo) gomock.Matcher {
+	return sessionMatcher(userID, refreshToken, client, false)
+}
+
+// verifiedSession is newSession for a login that passed a second factor.
+func verifiedSession(userID uuid.UUID, refreshToken string, client entity.ClientInfo) gomock.Matcher {
+	return sessionMatcher(userID, refreshToken, client, true)
+}
+
+func sessionMatcher(userID uuid.UUID, refreshToken string, client entity.ClientInfo, mfaVerified bool) gomock.Matcher {
+	return gomock.Cond(func(s entity.Session) bool {
+		return s.ID != uuid.Nil &&
+			s.UserID == userID &&
+			s.RefreshTokenHash == hasher.HashToken(refreshToken) &&
+			s.UserAgent == client.UserAgent &&
+			s.IP == client.IP &&
+			s.ExpiresAt.After(time.Now()) &&
+			s.MFAVerified == mfaVerified
+	})
+}
+
 func TestRegister(t *testing.T) {
 	var (
-		ctx          = context.Background()
-		arbitraryErr = errors.New("arbitrary error")
-		email        = "email123@gmail.com"
-		password     = "12345678"
-		role         = entity.RoleEmployee
-		emptyUser    = entity.User{}
-		tokens       = auth.Tokens{
-			AccessToken:  "access token",
-			RefreshToken: "refresh token",
-			ExpiresIn:    100,
-		}
+		ctx            = context.Background()
+		arbitraryErr   = errors.New("arbitrary error")
+		email          = "email123@gmail.com"
+		password       = "12345678"
+		role           = entity.RoleEmployee
+		emptyUser      = entity.User{}
+		userID         = uuid.New()
 		hashedPassword = "hashed_password_123"
 	)
 
-	type MockBehavior func(
-		u *mocks.MockUserRepository,
-		tx *mock_transactor.MockTransactor,
-		a *mocks.MockAuth,
-		h *mocks.MockHasher,
-	)
+	type MockBehavior func(m serviceMocks)
 
 	for _, tc := range []struct {
 		name         string
@@ -45,7 +172,7 @@ func TestRegister(t *testing.T) {
 		password     string
 		role         entity.UserRole
 		mockBehavior MockBehavior
-		want         *auth.Tokens
+		want         entity.User
 		wantErr      error
 	}{
 		{
@@ -53,57 +180,55 @@ func TestRegister(t *testing.T) {
 			email:    email,
 			password: password,
 			role:     entity.RoleEmployee,
-			mockBehavior: func(u *mocks.MockUserRepository, tx *mock_transactor.MockTransactor, a *mocks.MockAuth, h *mocks.MockHasher) {
-				tx.EXPECT().WithinTransaction(ctx, gomock.Any()).
-					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
-						return fn(ctx)
-					})
-				u.EXPECT().GetByEmail(ctx, email).Return(emptyUser, repository.ErrNoUserFound).Times(1)
-				h.EXPECT().HashPassword(password).Return(hashedPassword, nil).Times(1)
-				userWithHash := entity.User{
-					Email:        email,
-					PasswordHash: hashedPassword,
-					Role:         entity.RoleEmployee,
-				}
-				a.EXPECT().GenerateTokens(userWithHash).Return(&tokens, nil).Times(1)
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByEmail(ctx, email).Return(emptyUser, repository.ErrNoUserFound).Times(1)
+				m.hasher.EXPECT().HashPassword(password).Return(hashedPassword, nil).Times(1)
 				userToCreate := entity.User{
 					Email:        email,
 					PasswordHash: hashedPassword,
 					Role:         entity.RoleEmployee,
-					RefreshToken: tokens.RefreshToken,
 				}
-				u.EXPECT().Create(ctx, userToCreate).Return(userToCreate, nil).Times(1)
+				created := userToCreate
+				created.ID = userID
+				m.users.EXPECT().Create(ctx, userToCreate).Return(created, nil).Times(1)
+				m.audit.EXPECT().Create(ctx, auditRecord(userID, entity.AuditActionUserRegistered, userID,
+					"", `{"role":"employee","disabled":false}`)).Return(nil).Times(1)
+			},
+			want: entity.User{
+				ID:           userID,
+				Email:        email,
+				PasswordHash: hashedPassword,
+				Role:         entity.RoleEmployee,
 			},
-			want:    &tokens,
 			wantErr: nil,
 		},
 		{
-			name:     "success moderator",
+			name:     "moderator role is ignored",
 			email:    "moderator@mail.com",
 			password: password,
 			role:     entity.RoleModerator,
-			mockBehavior: func(u *mocks.MockUserRepository, tx *mock_transactor.MockTransactor, a *mocks.MockAuth, h *mocks.MockHasher) {
-				tx.EXPECT().WithinTransaction(ctx, gomock.Any()).
-					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
-						return fn(ctx)
-					})
-				u.EXPECT().GetByEmail(ctx, "moderator@mail.com").Return(emptyUser, repository.ErrNoUserFound).Times(1)
-				h.EXPECT().HashPassword(password).Return(hashedPassword, nil).Times(1)
-				userWithHash := entity.User{
-					Email:        "moderator@mail.com",
-					PasswordHash: hashedPassword,
-					Role:         entity.RoleModerator,
-				}
-				a.EXPECT().GenerateTokens(userWithHash).Return(&tokens, nil).Times(1)
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByEmail(ctx, "moderator@mail.com").Return(emptyUser, repository.ErrNoUserFound).Times(1)
+				m.hasher.EXPECT().HashPassword(password).Return(hashedPassword, nil).Times(1)
 				userToCreate := entity.User{
 					Email:        "moderator@mail.com",
 					PasswordHash: hashedPassword,
-					Role:         entity.RoleModerator,
-					RefreshToken: tokens.RefreshToken,
+					Role:         entity.RoleEmployee,
 				}
-				u.EXPECT().Create(ctx, userToCreate).Return(userToCreate, nil).Times(1)
+				created := userToCreate
+				created.ID = userID
+				m.users.EXPECT().Create(ctx, userToCreate).Return(created, nil).Times(1)
+				m.audit.EXPECT().Create(ctx, auditRecord(userID, entity.AuditActionUserRegistered, userID,
+					"", `{"role":"employee","disabled":false}`)).Return(nil).Times(1)
+			},
+			want: entity.User{
+				ID:           userID,
+				Email:        "moderator@mail.com",
+				PasswordHash: hashedPassword,
+				Role:         entity.RoleEmployee,
 			},
-			want:    &tokens,
 			wantErr: nil,
 		},
 		{
@@ -111,15 +236,12 @@ func TestRegister(t *testing.T) {
 			email:    email,
 			password: password,
 			role:     role,
-			mockBehavior: func(u *mocks.MockUserRepository, tx *mock_transactor.MockTransactor, a *mocks.MockAuth, h *mocks.MockHasher) {
-				tx.EXPECT().WithinTransaction(ctx, gomock.Any()).
-					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
-						return fn(ctx)
-					})
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
 				existingUser := entity.User{Email: email, Role: role}
-				u.EXPECT().GetByEmail(ctx, email).Return(existingUser, nil).Times(1)
+				m.users.EXPECT().GetByEmail(ctx, email).Return(existingUser, nil).Times(1)
 			},
-			want:    nil,
+			want:    entity.User{},
 			wantErr: service.ErrUserAlreadyExists,
 		},
 		{
@@ -127,66 +249,55 @@ func TestRegister(t *testing.T) {
 			email:    email,
 			password: password,
 			role:     role,
-			mockBehavior: func(u *mocks.MockUserRepository, tx *mock_transactor.MockTransactor, a *mocks.MockAuth, h *mocks.MockHasher) {
-				tx.EXPECT().WithinTransaction(ctx, gomock.Any()).
-					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
-						return fn(ctx)
-					})
-				u.EXPECT().GetByEmail(ctx, email).Return(emptyUser, repository.ErrNoUserFound).Times(1)
-				h.EXPECT().HashPassword(password).Return("", arbitraryErr).Times(1)
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByEmail(ctx, email).Return(emptyUser, repository.ErrNoUserFound).Times(1)
+				m.hasher.EXPECT().HashPassword(password).Return("", arbitraryErr).Times(1)
 			},
-			want:    nil,
+			want:    entity.User{},
 			wantErr: arbitraryErr,
 		},
 		{
-			name:     "generate tokens error",
+			name:     "create user error",
 			email:    email,
 			password: password,
 			role:     role,
-			mockBehavior: func(u *mocks.MockUserRepository, tx *mock_transactor.MockTransactor, a *mocks.MockAuth, h *mocks.MockHasher) {
-				tx.EXPECT().WithinTransaction(ctx, gomock.Any()).
-					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
-						return fn(ctx)
-					})
-				u.EXPECT().GetByEmail(ctx, email).Return(emptyUser, repository.ErrNoUserFound).Times(1)
-				h.EXPECT().HashPassword(password).Return(hashedPassword, nil).Times(1)
-				userWithHash := entity.User{
-					Email:        email,
-					PasswordHash: hashedPassword,
-					Role:         role,
-				}
-				a.EXPECT().GenerateTokens(userWithHash).Return(nil, arbitraryErr).Times(1)
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByEmail(ctx, email).Return(emptyUser, repository.ErrNoUserFound).Times(1)
+				m.hasher.EXPECT().HashPassword(password).Return(hashedPassword, nil).Times(1)
+				m.users.EXPECT().Create(ctx, gomock.Any()).Return(emptyUser, arbitraryErr).Times(1)
 			},
-			want:    nil,
+			want:    entity.User{},
 			wantErr: arbitraryErr,
 		},
 		{
-			name:     "create user error",
+			name:     "unknown role",
 			email:    email,
 			password: password,
 			role:     role,
-			mockBehavior: func(u *mocks.MockUserRepository, tx *mock_transactor.MockTransactor, a *mocks.MockAuth, h *mocks.MockHasher) {
-				tx.EXPECT().WithinTransaction(ctx, gomock.Any()).
-					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
-						return fn(ctx)
-					})
-				u.EXPECT().GetByEmail(ctx, email).Return(emptyUser, repository.ErrNoUserFound).Times(1)
-				h.EXPECT().HashPassword(password).Return(hashedPassword, nil).Times(1)
-				userWithHash := entity.User{
-					Email:        email,
-					PasswordHash: hashedPassword,
-					Role:         role,
-				}
-				a.EXPECT().GenerateTokens(userWithHash).Return(&tokens, nil).Times(1)
-				userToCreate := entity.User{
-					Email:        email,
-					PasswordHash: hashedPassword,
-					Role:         role,
-					RefreshToken: tokens.RefreshToken,
-				}
-				u.EXPECT().Create(ctx, userToCreate).Return(emptyUser, arbitraryErr).Times(1)
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByEmail(ctx, email).Return(emptyUser, repository.ErrNoUserFound).Times(1)
+				m.hasher.EXPECT().HashPassword(password).Return(hashedPassword, nil).Times(1)
+				m.users.EXPECT().Create(ctx, gomock.Any()).Return(emptyUser, repository.ErrNoRoleFound).Times(1)
 			},
-			want:    nil,
+			want:    entity.User{},
+			wantErr: service.ErrUnknownRole,
+		},
+		{
+			name:     "audit error",
+			email:    email,
+			password: password,
+			role:     role,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.users.EXPECT().GetByEmail(ctx, email).Return(emptyUser, repository.ErrNoUserFound).Times(1)
+				m.hasher.EXPECT().HashPassword(password).Return(hashedPassword, nil).Times(1)
+				m.users.EXPECT().Create(ctx, gomock.Any()).Return(entity.User{ID: userID}, nil).Times(1)
+				m.audit.EXPECT().Create(ctx, gomock.Any()).Return(arbitraryErr).Times(1)
+			},
+			want:    entity.User{},
 			wantErr: arbitraryErr,
 		},
 	} {
@@ -195,22 +306,51 @@ func TestRegister(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
-			MockUserRepo := mocks.NewMockUserRepository(ctrl)
-			MockAuth := mocks.NewMockAuth(ctrl)
-			MockHasher := mocks.NewMockHasher(ctrl)
-			MockTransactor := mock_transactor.NewMockTransactor(ctrl)
+			s, m := newService(ctrl)
+			tc.mockBehavior(m)
 
-			tc.mockBehavior(MockUserRepo, MockTransactor, MockAuth, MockHasher)
-
-			s := service.New(MockUserRepo, MockTransactor, MockAuth, MockHasher)
-
-			out, err := s.Register(ctx, tc.email, tc.password, tc.role)
+			out, err := s.Register(ctx, tc.email, tc.password, tc.role, "")
 			assert.ErrorIs(t, err, tc.wantErr)
 			assert.Equal(t, tc.want, out)
 		})
 	}
 }
 
+func TestCreateUser(t *testing.T) {
+	var (
+		ctx            = context.Background()
+		email          = "moderator@mail.com"
+		password       = "12345678"
+		userID         = uuid.New()
+		hashedPassword = "hashed_password_123"
+	)
+
+	// Works with registration closed and keeps the role
+	closed := policy
+	closed.OpenRegistration = false
+
+	ctrl := gomock.NewController(t)
+	s, m := newServiceWithPolicy(ctrl, closed)
+
+	withinTx(ctx, m.tx)
+	m.users.EXPECT().GetByEmail(ctx, email).Return(entity.User{}, repository.ErrNoUserFound).Times(1)
+	m.hasher.EXPECT().HashPassword(password).Return(hashedPassword, nil).Times(1)
+	userToCreate := entity.User{
+		Email:        email,
+		PasswordHash: hashedPassword,
+		Role:         entity.RoleModerator,
+	}
+	created := userToCreate
+	created.ID = userID
+	m.users.EXPECT().Create(ctx, userToCreate).Return(created, nil).Times(1)
+	m.audit.EXPECT().Create(ctx, auditRecord(userID, entity.AuditActionUserRegistered, userID,
+		"", `{"role":"moderator","disabled":false}`)).Return(nil).Times(1)
+
+	out, err := s.CreateUser(ctx, email, password, entity.RoleModerator)
+	assert.NoError(t, err)
+	assert.Equal(t, created, out)
+}
+
 func TestAuthenticate(t *testing.T) {
 	var (
 		ctx            = context.Background()
@@ -219,6 +359,7 @@ func TestAuthenticate(t *testing.T) {
 		password       = "12345678"
 		hashedPassword = "$2a$10$hashedpassword123"
 		userID         = uuid.New()
+		client         = entity.ClientInfo{UserAgent: "scanner/1.0", IP: "10.0.0.1"}
 	)
 
 	validUser := entity.User{
@@ -234,12 +375,7 @@ func TestAuthenticate(t *testing.T) {
 		ExpiresIn:    3600,
 	}
 
-	type MockBehavior func(
-		u *mocks.MockUserRepository,
-		tx *mock_transactor.MockTransactor,
-		a *mocks.MockAuth,
-		h *mocks.MockHasher,
-	)
+	type MockBehavior func(m serviceMocks)
 
 	for _, tc := range []struct {
 		name         string
@@ -253,15 +389,16 @@ func TestAuthenticate(t *testing.T) {
 			name:     "success employee",
 			email:    email,
 			password: password,
-			mockBehavior: func(u *mocks.MockUserRepository, tx *mock_transactor.MockTransactor, a *mocks.MockAuth, h *mocks.MockHasher) {
-				tx.EXPECT().WithinTransaction(ctx, gomock.Any()).
-					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
-						return fn(ctx)
-					})
-				u.EXPECT().GetByEmail(ctx, email).Return(validUser, nil).Times(1)
-				h.EXPECT().CheckPasswordHash(password, hashedPassword).Return(true).Times(1)
-				a.EXPECT().GenerateTokens(validUser).Return(tokens, nil).Times(1)
-				u.EXPECT().UpdateRefreshToken(ctx, userID, tokens.RefreshToken).Return(validUser, nil).Times(1)
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				notLocked(ctx, m, email, client.IP)
+				m.users.EXPECT().GetByEmail(ctx, email).Return(validUser, nil).Times(1)
+				m.hasher.EXPECT().CheckPasswordHash(password, hashedPassword).Return(true).Times(1)
+				m.failures.EXPECT().Reset(ctx, entity.LoginScopeEmail, email).Return(nil).Times(1)
+				m.hasher.EXPECT().NeedsRehash(hashedPassword).Return(false).Times(1)
+				noMFA(ctx, m, validUser.ID)
+				m.auth.EXPECT().GenerateTokens(validUser, gomock.Any()).Return(tokens, nil).Times(1)
+				m.sessions.EXPECT().Create(ctx, newSession(userID, tokens.RefreshToken, client)).Return(entity.Session{}, nil).Times(1)
 			},
 			want:    tokens,
 			wantErr: nil,
@@ -270,97 +407,261 @@ func TestAuthenticate(t *testing.T) {
 			name:     "success moderator",
 			email:    "moderator@mail.com",
 			password: password,
-			mockBehavior: func(u *mocks.MockUserRepository, tx *mock_transactor.MockTransactor, a *mocks.MockAuth, h *mocks.MockHasher) {
-				tx.EXPECT().WithinTransaction(ctx, gomock.Any()).
-					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
-						return fn(ctx)
-					})
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				notLocked(ctx, m, "moderator@mail.com", client.IP)
 				moderatorUser := entity.User{
 					ID:           uuid.New(),
 					Email:        "moderator@mail.com",
 					PasswordHash: hashedPassword,
 					Role:         entity.RoleModerator,
 				}
-				u.EXPECT().GetByEmail(ctx, "moderator@mail.com").Return(moderatorUser, nil).Times(1)
-				h.EXPECT().CheckPasswordHash(password, hashedPassword).Return(true).Times(1)
-				a.EXPECT().GenerateTokens(moderatorUser).Return(tokens, nil).Times(1)
-				u.EXPECT().UpdateRefreshToken(ctx, moderatorUser.ID, tokens.RefreshToken).Return(moderatorUser, nil).Times(1)
+				m.users.EXPECT().GetByEmail(ctx, "moderator@mail.com").Return(moderatorUser, nil).Times(1)
+				m.hasher.EXPECT().CheckPasswordHash(password, hashedPassword).Return(true).Times(1)
+				m.failures.EXPECT().Reset(ctx, entity.LoginScopeEmail, "moderator@mail.com").Return(nil).Times(1)
+				m.hasher.EXPECT().NeedsRehash(hashedPassword).Return(false).Times(1)
+				noMFA(ctx, m, moderatorUser.ID)
+				m.auth.EXPECT().GenerateTokens(moderatorUser, gomock.Any()).Return(tokens, nil).Times(1)
+				m.sessions.EXPECT().Create(ctx, newSession(moderatorUser.ID, tokens.RefreshToken, client)).Return(entity.Session{}, nil).Times(1)
 			},
 			want:    tokens,
 			wantErr: nil,
 		},
 		{
-			name:     "user not found",
+			name:     "outdated hash is upgraded",
 			email:    email,
 			password: password,
-			mockBehavior: func(u *mocks.MockUserRepository, tx *mock_transactor.MockTransactor, a *mocks.MockAuth, h *mocks.MockHasher) {
-				tx.EXPECT().WithinTransaction(ctx, gomock.Any()).
-					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
-						return fn(ctx)
-					})
-				u.EXPECT().GetByEmail(ctx, email).Return(entity.User{}, repository.ErrNoUserFound).Times(1)
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				notLocked(ctx, m, email, client.IP)
+				m.users.EXPECT().GetByEmail(ctx, email).Return(validUser, nil).Times(1)
+				m.hasher.EXPECT().CheckPasswordHash(password, hashedPassword).Return(true).Times(1)
+				m.failures.EXPECT().Reset(ctx, entity.LoginScopeEmail, email).Return(nil).Times(1)
+				m.hasher.EXPECT().NeedsRehash(hashedPassword).Return(true).Times(1)
+				m.hasher.EXPECT().HashPassword(password).Return("$argon2id$v=19$m=65536,t=3,p=4$c2FsdA$a2V5", nil).Times(1)
+				m.users.EXPECT().UpdatePassword(ctx, userID, "$argon2id$v=19$m=65536,t=3,p=4$c2FsdA$a2V5").Return(nil).Times(1)
+				noMFA(ctx, m, validUser.ID)
+				m.auth.EXPECT().GenerateTokens(validUser, gomock.Any()).Return(tokens, nil).Times(1)
+				m.sessions.EXPECT().Create(ctx, newSession(userID, tokens.RefreshToken, client)).Return(entity.Session{}, nil).Times(1)
+			},
+			want:    tokens,
+			wantErr: nil,
+		},
+		{
+			name:     "rehash failure keeps the old hash",
+			email:    email,
+			password: password,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				notLocked(ctx, m, email, client.IP)
+				m.users.EXPECT().GetByEmail(ctx, email).Return(validUser, nil).Times(1)
+				m.hasher.EXPECT().CheckPasswordHash(password, hashedPassword).Return(true).Times(1)
+				m.failures.EXPECT().Reset(ctx, entity.LoginScopeEmail, email).Return(nil).Times(1)
+				m.hasher.EXPECT().NeedsRehash(hashedPassword).Return(true).Times(1)
+				m.hasher.EXPECT().HashPassword(password).Return("", arbitraryErr).Times(1)
+				noMFA(ctx, m, validUser.ID)
+				m.auth.EXPECT().GenerateTokens(validUser, gomock.Any()).Return(tokens, nil).Times(1)
+				m.sessions.EXPECT().Create(ctx, newSession(userID, tokens.RefreshToken, client)).Return(entity.Session{}, nil).Times(1)
+			},
+			want:    tokens,
+			wantErr: nil,
+		},
+		{
+			name:     "store rehashed password error",
+			email:    email,
+			password: password,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				notLocked(ctx, m, email, client.IP)
+				m.users.EXPECT().GetByEmail(ctx, email).Return(validUser, nil).Times(1)
+				m.hasher.EXPECT().CheckPasswordHash(password, hashedPassword).Return(true).Times(1)
+				m.hasher.EXPECT().NeedsRehash(hashedPassword).Return(true).Times(1)
+				m.hasher.EXPECT().HashPassword(password).Return("$argon2id$v=19$m=65536,t=3,p=4$c2FsdA$a2V5", nil).Times(1)
+				m.users.EXPECT().UpdatePassword(ctx, userID, "$argon2id$v=19$m=65536,t=3,p=4$c2FsdA$a2V5").Return(arbitraryErr).Times(1)
 			},
 			want:    nil,
-			wantErr: service.ErrNoUserFound,
+			wantErr: arbitraryErr,
+		},
+		{
+			name:     "user not found gives invalid credentials",
+			email:    email,
+			password: password,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				notLocked(ctx, m, email, client.IP)
+				m.users.EXPECT().GetByEmail(ctx, email).Return(entity.User{}, repository.ErrNoUserFound).Times(1)
+				m.metrics.EXPECT().FailureInc("invalid_credentials").Times(1)
+				m.failures.EXPECT().RecordFailure(ctx, entity.LoginScopeEmail, email, time.Hour).Return(1, nil).Times(1)
+				m.failures.EXPECT().Lock(ctx, entity.LoginScopeEmail, email, lockedUntil(time.Second)).Return(nil).Times(1)
+				m.failures.EXPECT().RecordFailure(ctx, entity.LoginScopeIP, client.IP, time.Hour).Return(1, nil).Times(1)
+				m.failures.EXPECT().Lock(ctx, entity.LoginScopeIP, client.IP, lockedUntil(time.Second)).Return(nil).Times(1)
+			},
+			want:    nil,
+			wantErr: service.ErrInvalidCredentials,
+		},
+		{
+			name:     "user of the identity provider gives invalid credentials",
+			email:    email,
+			password: password,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				notLocked(ctx, m, email, client.IP)
+				idpOnly := validUser
+				idpOnly.PasswordHash = ""
+				m.users.EXPECT().GetByEmail(ctx, email).Return(idpOnly, nil).Times(1)
+				m.metrics.EXPECT().FailureInc("invalid_credentials").Times(1)
+				m.failures.EXPECT().RecordFailure(ctx, entity.LoginScopeEmail, email, time.Hour).Return(1, nil).Times(1)
+				m.failures.EXPECT().Lock(ctx, entity.LoginScopeEmail, email, lockedUntil(time.Second)).Return(nil).Times(1)
+				m.failures.EXPECT().RecordFailure(ctx, entity.LoginScopeIP, client.IP, time.Hour).Return(1, nil).Times(1)
+				m.failures.EXPECT().Lock(ctx, entity.LoginScopeIP, client.IP, lockedUntil(time.Second)).Return(nil).Times(1)
+			},
+			want:    nil,
+			wantErr: service.ErrInvalidCredentials,
 		},
 		{
 			name:     "get user error",
 			email:    email,
 			password: password,
-			mockBehavior: func(u *mocks.MockUserRepository, tx *mock_transactor.MockTransactor, a *mocks.MockAuth, h *mocks.MockHasher) {
-				tx.EXPECT().WithinTransaction(ctx, gomock.Any()).
-					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
-						return fn(ctx)
-					})
-				u.EXPECT().GetByEmail(ctx, email).Return(entity.User{}, arbitraryErr).Times(1)
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				notLocked(ctx, m, email, client.IP)
+				m.users.EXPECT().GetByEmail(ctx, email).Return(entity.User{}, arbitraryErr).Times(1)
 			},
 			want:    nil,
 			wantErr: arbitraryErr,
 		},
 		{
-			name:     "invalid password",
+			name:     "invalid password with exponential backoff",
 			email:    email,
 			password: "wrong_password",
-			mockBehavior: func(u *mocks.MockUserRepository, tx *mock_transactor.MockTransactor, a *mocks.MockAuth, h *mocks.MockHasher) {
-				tx.EXPECT().WithinTransaction(ctx, gomock.Any()).
-					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
-						return fn(ctx)
-					})
-				u.EXPECT().GetByEmail(ctx, email).Return(validUser, nil).Times(1)
-				h.EXPECT().CheckPasswordHash("wrong_password", hashedPassword).Return(false).Times(1)
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				notLocked(ctx, m, email, client.IP)
+				m.users.EXPECT().GetByEmail(ctx, email).Return(validUser, nil).Times(1)
+				m.hasher.EXPECT().CheckPasswordHash("wrong_password", hashedPassword).Return(false).Times(1)
+				m.metrics.EXPECT().FailureInc("invalid_credentials").Times(1)
+				m.failures.EXPECT().RecordFailure(ctx, entity.LoginScopeEmail, email, time.Hour).Return(2, nil).Times(1)
+				m.failures.EXPECT().Lock(ctx, entity.LoginScopeEmail, email, lockedUntil(2*time.Second)).Return(nil).Times(1)
+				m.failures.EXPECT().RecordFailure(ctx, entity.LoginScopeIP, client.IP, time.Hour).Return(4, nil).Times(1)
+				m.failures.EXPECT().Lock(ctx, entity.LoginScopeIP, client.IP, lockedUntil(8*time.Second)).Return(nil).Times(1)
+			},
+			want:    nil,
+			wantErr: service.ErrInvalidCredentials,
+		},
+		{
+			name:     "lockout after max failures",
+			email:    email,
+			password: "wrong_password",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				notLocked(ctx, m, email, client.IP)
+				m.users.EXPECT().GetByEmail(ctx, email).Return(validUser, nil).Times(1)
+				m.hasher.EXPECT().CheckPasswordHash("wrong_password", hashedPassword).Return(false).Times(1)
+				m.metrics.EXPECT().FailureInc("invalid_credentials").Times(1)
+				m.failures.EXPECT().RecordFailure(ctx, entity.LoginScopeEmail, email, time.Hour).Return(3, nil).Times(1)
+				m.metrics.EXPECT().LockoutInc("email").Times(1)
+				m.failures.EXPECT().Lock(ctx, entity.LoginScopeEmail, email, lockedUntil(15*time.Minute)).Return(nil).Times(1)
+				m.failures.EXPECT().RecordFailure(ctx, entity.LoginScopeIP, client.IP, time.Hour).Return(3, nil).Times(1)
+				m.failures.EXPECT().Lock(ctx, entity.LoginScopeIP, client.IP, lockedUntil(4*time.Second)).Return(nil).Times(1)
+			},
+			want:    nil,
+			wantErr: service.ErrInvalidCredentials,
+		},
+		{
+			name:     "disabled user",
+			email:    email,
+			password: password,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				notLocked(ctx, m, email, client.IP)
+				disabledAt := time.Now()
+				disabled := validUser
+				disabled.DisabledAt = &disabledAt
+				m.users.EXPECT().GetByEmail(ctx, email).Return(disabled, nil).Times(1)
+				m.hasher.EXPECT().CheckPasswordHash(password, hashedPassword).Return(true).Times(1)
+			},
+			want:    nil,
+			wantErr: service.ErrUserDisabled,
+		},
+		{
+			name:     "locked email",
+			email:    email,
+			password: password,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				until := time.Now().Add(time.Minute)
+				m.failures.EXPECT().GetForUpdate(ctx, entity.LoginScopeEmail, email).
+					Return(entity.LoginFailures{Failures: 3, LockedUntil: &until}, nil).Times(1)
+				m.metrics.EXPECT().FailureInc("locked").Times(1)
 			},
 			want:    nil,
 			wantErr: service.ErrInvalidCredentials,
 		},
+		{
+			name:     "locked IP",
+			email:    email,
+			password: password,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				until := time.Now().Add(time.Minute)
+				m.failures.EXPECT().GetForUpdate(ctx, entity.LoginScopeEmail, email).Return(entity.LoginFailures{}, nil).Times(1)
+				m.failures.EXPECT().GetForUpdate(ctx, entity.LoginScopeIP, client.IP).
+					Return(entity.LoginFailures{Failures: 10, LockedUntil: &until}, nil).Times(1)
+				m.metrics.EXPECT().FailureInc("locked").Times(1)
+			},
+			want:    nil,
+			wantErr: service.ErrInvalidCredentials,
+		},
+		{
+			name:     "expired lock",
+			email:    email,
+			password: password,
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				until := time.Now().Add(-time.Minute)
+				m.failures.EXPECT().GetForUpdate(ctx, entity.LoginScopeEmail, email).
+					Return(entity.LoginFailures{Failures: 3, LockedUntil: &until}, nil).Times(1)
+				m.failures.EXPECT().GetForUpdate(ctx, entity.LoginScopeIP, client.IP).Return(entity.LoginFailures{}, nil).Times(1)
+				m.users.EXPECT().GetByEmail(ctx, email).Return(validUser, nil).Times(1)
+				m.hasher.EXPECT().CheckPasswordHash(password, hashedPassword).Return(true).Times(1)
+				m.failures.EXPECT().Reset(ctx, entity.LoginScopeEmail, email).Return(nil).Times(1)
+				m.hasher.EXPECT().NeedsRehash(hashedPassword).Return(false).Times(1)
+				noMFA(ctx, m, validUser.ID)
+				m.auth.EXPECT().GenerateTokens(validUser, gomock.Any()).Return(tokens, nil).Times(1)
+				m.sessions.EXPECT().Create(ctx, newSession(userID, tokens.RefreshToken, client)).Return(entity.Session{}, nil).Times(1)
+			},
+			want:    tokens,
+			wantErr: nil,
+		},
 		{
 			name:     "generate tokens error",
 			email:    email,
 			password: password,
-			mockBehavior: func(u *mocks.MockUserRepository, tx *mock_transactor.MockTransactor, a *mocks.MockAuth, h *mocks.MockHasher) {
-				tx.EXPECT().WithinTransaction(ctx, gomock.Any()).
-					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
-						return fn(ctx)
-					})
-				u.EXPECT().GetByEmail(ctx, email).Return(validUser, nil).Times(1)
-				h.EXPECT().CheckPasswordHash(password, hashedPassword).Return(true).Times(1)
-				a.EXPECT().GenerateTokens(validUser).Return(nil, arbitraryErr).Times(1)
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				notLocked(ctx, m, email, client.IP)
+				m.users.EXPECT().GetByEmail(ctx, email).Return(validUser, nil).Times(1)
+				m.hasher.EXPECT().CheckPasswordHash(password, hashedPassword).Return(true).Times(1)
+				m.hasher.EXPECT().NeedsRehash(hashedPassword).Return(false).Times(1)
+				noMFA(ctx, m, validUser.ID)
+				m.auth.EXPECT().GenerateTokens(validUser, gomock.Any()).Return(nil, arbitraryErr).Times(1)
 			},
 			want:    nil,
 			wantErr: arbitraryErr,
 		},
 		{
-			name:     "update refresh token error",
+			name:     "create session error",
 			email:    email,
 			password: password,
-			mockBehavior: func(u *mocks.MockUserRepository, tx *mock_transactor.MockTransactor, a *mocks.MockAuth, h *mocks.MockHasher) {
-				tx.EXPECT().WithinTransaction(ctx, gomock.Any()).
-					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
-						return fn(ctx)
-					})
-				u.EXPECT().GetByEmail(ctx, email).Return(validUser, nil).Times(1)
-				h.EXPECT().CheckPasswordHash(password, hashedPassword).Return(true).Times(1)
-				a.EXPECT().GenerateTokens(validUser).Return(tokens, nil).Times(1)
-				u.EXPECT().UpdateRefreshToken(ctx, userID, tokens.RefreshToken).Return(entity.User{}, arbitraryErr).Times(1)
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				notLocked(ctx, m, email, client.IP)
+				m.users.EXPECT().GetByEmail(ctx, email).Return(validUser, nil).Times(1)
+				m.hasher.EXPECT().CheckPasswordHash(password, hashedPassword).Return(true).Times(1)
+				m.hasher.EXPECT().NeedsRehash(hashedPassword).Return(false).Times(1)
+				noMFA(ctx, m, validUser.ID)
+				m.auth.EXPECT().GenerateTokens(validUser, gomock.Any()).Return(tokens, nil).Times(1)
+				m.sessions.EXPECT().Create(ctx, gomock.Any()).Return(entity.Session{}, arbitraryErr).Times(1)
 			},
 			want:    nil,
 			wantErr: arbitraryErr,
@@ -371,186 +672,238 @@ func TestAuthenticate(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
-			MockUserRepo := mocks.NewMockUserRepository(ctrl)
-			MockAuth := mocks.NewMockAuth(ctrl)
-			MockHasher := mocks.NewMockHasher(ctrl)
-			MockTransactor := mock_transactor.NewMockTransactor(ctrl)
+			s, m := newService(ctrl)
+			tc.mockBehavior(m)
 
-			tc.mockBehavior(MockUserRepo, MockTransactor, MockAuth, MockHasher)
+			out, err := s.Authenticate(ctx, tc.email, tc.password, client)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.want, tokensOf(out))
+		})
+	}
+}
 
-			s := service.New(MockUserRepo, MockTransactor, MockAuth, MockHasher)
+func TestPruneLoginFailures(t *testing.T) {
+	ctx := context.Background()
+	arbitraryErr := errors.New("arbitrary error")
+	outsideWindow := gomock.Cond(func(before time.Time) bool {
+		return before.Sub(time.Now().Add(-policy.Login.FailureWindow)).Abs() < time.Minute/2
+	})
 
-			out, err := s.Authenticate(ctx, tc.email, tc.password)
-			assert.ErrorIs(t, err, tc.wantErr)
-			assert.Equal(t, tc.want, out)
+	for _, tc := range []struct {
+		name    string
+		err     error
+		wantErr error
+	}{
+		{name: "success"},
+		{name: "repository error", err: arbitraryErr, wantErr: arbitraryErr},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s, m := newService(gomock.NewController(t))
+			m.failures.EXPECT().DeleteStale(ctx, outsideWindow).Return(int64(2), tc.err)
+
+			assert.ErrorIs(t, s.PruneLoginFailures(ctx), tc.wantErr)
 		})
 	}
 }
 
 func TestRefreshTokens(t *testing.T) {
 	var (
-		ctx             = context.Background()
-		arbitraryErr    = errors.New("arbitrary error")
-		email           = "email123@gmail.com"
-		refreshToken    = "valid_refresh_token_123"
-		oldRefreshToken = "old_refresh_token_456"
-		userID          = uuid.New()
+		ctx          = context.Background()
+		arbitraryErr = errors.New("arbitrary error")
+		refreshToken = "valid_refresh_token_123"
+		userID       = uuid.New()
+		sessionID    = uuid.New()
+		client       = entity.ClientInfo{UserAgent: "scanner/2.0", IP: "10.0.0.2"}
+		revokedAt    = time.Now().Add(-time.Hour)
 	)
 
+	claims := &auth.RefreshClaims{SessionID: sessionID}
+
 	validUser := entity.User{
-		ID:           userID,
-		Email:        email,
-		Role:         entity.RoleEmployee,
-		RefreshToken: refreshToken,
+		ID:    userID,
+		Email: "email123@gmail.com",
+		Role:  entity.RoleEmployee,
 	}
 
-	userWithDifferentToken := entity.User{
-		ID:           userID,
-		Email:        email,
-		Role:         entity.RoleEmployee,
-		RefreshToken: oldRefreshToken, // отличается от переданного токена
+	activeSession := entity.Session{
+		ID:               sessionID,
+		UserID:           userID,
+		RefreshTokenHash: hasher.HashToken(refreshToken),
+		ExpiresAt:        time.Now().Add(time.Hour),
 	}
 
+	rotatedSession := activeSession
+	rotatedSession.RefreshTokenHash = hasher.HashToken("newer_refresh_token")
+
+	revokedSession := activeSession
+	revokedSession.RevokedAt = &revokedAt
+
+	expiredSession := activeSession
+	expiredSession.ExpiresAt = time.Now().Add(-time.Minute)
+
 	tokens := &auth.Tokens{
 		AccessToken:  "new_access_token_123",
 		RefreshToken: "new_refresh_token_456",
 		ExpiresIn:    3600,
 	}
 
-	type MockBehavior func(
-		u *mocks.MockUserRepository,
-		tx *mock_transactor.MockTransactor,
-		a *mocks.MockAuth,
-	)
+	rotation := gomock.Cond(func(s entity.Session) bool {
+		return s.ID == sessionID &&
+			s.RefreshTokenHash == hasher.HashToken(tokens.RefreshToken) &&
+			s.UserAgent == client.UserAgent &&
+			s.IP == client.IP
+	})
+
+	type MockBehavior func(m serviceMocks)
 
 	for _, tc := range []struct {
 		name         string
-		refreshToken string
 		mockBehavior MockBehavior
 		want         *auth.Tokens
 		wantErr      error
 	}{
 		{
-			name:         "success",
-			refreshToken: refreshToken,
-			mockBehavior: func(u *mocks.MockUserRepository, tx *mock_transactor.MockTransactor, a *mocks.MockAuth) {
-				a.EXPECT().ValidateRefreshToken(refreshToken).Return(email, nil).Times(1)
-				tx.EXPECT().WithinTransaction(ctx, gomock.Any()).
-					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
-						return fn(ctx)
-					})
-				u.EXPECT().GetByEmail(ctx, email).Return(validUser, nil).Times(1)
-				a.EXPECT().GenerateTokens(validUser).Return(tokens, nil).Times(1)
-				u.EXPECT().UpdateRefreshToken(ctx, userID, tokens.RefreshToken).Return(validUser, nil).Times(1)
+			name: "success",
+			mockBehavior: func(m serviceMocks) {
+				m.auth.EXPECT().ValidateRefreshToken(refreshToken).Return(claims, nil).Times(1)
+				withinTx(ctx, m.tx)
+				m.sessions.EXPECT().GetByIDForUpdate(ctx, sessionID).Return(activeSession, nil).Times(1)
+				m.users.EXPECT().GetByID(ctx, userID).Return(validUser, nil).Times(1)
+				m.auth.EXPECT().GenerateTokens(validUser, sessionID).Return(tokens, nil).Times(1)
+				m.sessions.EXPECT().Rotate(ctx, rotation).Return(nil).Times(1)
 			},
 			want:    tokens,
 			wantErr: nil,
 		},
 		{
-			name:         "success different role",
-			refreshToken: refreshToken,
-			mockBehavior: func(u *mocks.MockUserRepository, tx *mock_transactor.MockTransactor, a *mocks.MockAuth) {
-				a.EXPECT().ValidateRefreshToken(refreshToken).Return("moderator@mail.com", nil).Times(1)
-				tx.EXPECT().WithinTransaction(ctx, gomock.Any()).
-					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
-						return fn(ctx)
-					})
-				moderatorUser := entity.User{
-					ID:           uuid.New(),
-					Email:        "moderator@mail.com",
-					Role:         entity.RoleModerator,
-					RefreshToken: refreshToken,
-				}
-				u.EXPECT().GetByEmail(ctx, "moderator@mail.com").Return(moderatorUser, nil).Times(1)
-				a.EXPECT().GenerateTokens(moderatorUser).Return(tokens, nil).Times(1)
-				u.EXPECT().UpdateRefreshToken(ctx, moderatorUser.ID, tokens.RefreshToken).Return(moderatorUser, nil).Times(1)
+			name: "invalid refresh token",
+			mockBehavior: func(m serviceMocks) {
+				m.auth.EXPECT().ValidateRefreshToken(refreshToken).Return(nil, arbitraryErr).Times(1)
+				// Транзакция не должна начинаться
 			},
-			want:    tokens,
-			wantErr: nil,
+			want:    nil,
+			wantErr: service.ErrInvalidRefreshToken,
 		},
 		{
-			name:         "invalid refresh token",
-			refreshToken: "invalid_token",
-			mockBehavior: func(u *mocks.MockUserRepository, tx *mock_transactor.MockTransactor, a *mocks.MockAuth) {
-				a.EXPECT().ValidateRefreshToken("invalid_token").Return("", arbitraryErr).Times(1)
-				// Транзакция не должна начинаться
+			name: "session not found",
+			mockBehavior: func(m serviceMocks) {
+				m.auth.EXPECT().ValidateRefreshToken(refreshToken).Return(claims, nil).Times(1)
+				withinTx(ctx, m.tx)
+				m.sessions.EXPECT().GetByIDForUpdate(ctx, sessionID).Return(entity.Session{}, repository.ErrNoSessionFound).Times(1)
 			},
 			want:    nil,
 			wantErr: service.ErrInvalidRefreshToken,
 		},
 		{
-			name:         "user not found",
-			refreshToken: refreshToken,
-			mockBehavior: func(u *mocks.MockUserRepository, tx *mock_transactor.MockTransactor, a *mocks.MockAuth) {
-				a.EXPECT().ValidateRefreshToken(refreshToken).Return(email, nil).Times(1)
-				tx.EXPECT().WithinTransaction(ctx, gomock.Any()).
-					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
-						return fn(ctx)
-					})
-				u.EXPECT().GetByEmail(ctx, email).Return(entity.User{}, repository.ErrNoUserFound).Times(1)
-				// GenerateTokens и UpdateRefreshToken не должны вызываться
+			name: "get session error",
+			mockBehavior: func(m serviceMocks) {
+				m.auth.EXPECT().ValidateRefreshToken(refreshToken).Return(claims, nil).Times(1)
+				withinTx(ctx, m.tx)
+				m.sessions.EXPECT().GetByIDForUpdate(ctx, sessionID).Return(entity.Session{}, arbitraryErr).Times(1)
+			},
+			want:    nil,
+			wantErr: arbitraryErr,
+		},
+		{
+			name: "revoked session",
+			mockBehavior: func(m serviceMocks) {
+				m.auth.EXPECT().ValidateRefreshToken(refreshToken).Return(claims, nil).Times(1)
+				withinTx(ctx, m.tx)
+				m.sessions.EXPECT().GetByIDForUpdate(ctx, sessionID).Return(revokedSession, nil).Times(1)
 			},
 			want:    nil,
-			wantErr: repository.ErrNoUserFound,
+			wantErr: service.ErrInvalidRefreshToken,
 		},
 		{
-			name:         "get user error",
-			refreshToken: refreshToken,
-			mockBehavior: func(u *mocks.MockUserRepository, tx *mock_transactor.MockTransactor, a *mocks.MockAuth) {
-				a.EXPECT().ValidateRefreshToken(refreshToken).Return(email, nil).Times(1)
-				tx.EXPECT().WithinTransaction(ctx, gomock.Any()).
-					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
-						return fn(ctx)
-					})
-				u.EXPECT().GetByEmail(ctx, email).Return(entity.User{}, arbitraryErr).Times(1)
-				// GenerateTokens и UpdateRefreshToken не должны вызываться
+			name: "expired session",
+			mockBehavior: func(m serviceMocks) {
+				m.auth.EXPECT().ValidateRefreshToken(refreshToken).Return(claims, nil).Times(1)
+				withinTx(ctx, m.tx)
+				m.sessions.EXPECT().GetByIDForUpdate(ctx, sessionID).Return(expiredSession, nil).Times(1)
+			},
+			want:    nil,
+			wantErr: service.ErrInvalidRefreshToken,
+		},
+		{
+			name: "reused token revokes family",
+			mockBehavior: func(m serviceMocks) {
+				m.auth.EXPECT().ValidateRefreshToken(refreshToken).Return(claims, nil).Times(1)
+				withinTx(ctx, m.tx)
+				m.sessions.EXPECT().GetByIDForUpdate(ctx, sessionID).Return(rotatedSession, nil).Times(1)
+				m.sessions.EXPECT().Revoke(ctx, userID, sessionID).Return(nil).Times(1)
+				// Новые токены не выдаются
+			},
+			want:    nil,
+			wantErr: service.ErrInvalidRefreshToken,
+		},
+		{
+			name: "reused token revoke error",
+			mockBehavior: func(m serviceMocks) {
+				m.auth.EXPECT().ValidateRefreshToken(refreshToken).Return(claims, nil).Times(1)
+				withinTx(ctx, m.tx)
+				m.sessions.EXPECT().GetByIDForUpdate(ctx, sessionID).Return(rotatedSession, nil).Times(1)
+				m.sessions.EXPECT().Revoke(ctx, userID, sessionID).Return(arbitraryErr).Times(1)
 			},
 			want:    nil,
 			wantErr: arbitraryErr,
 		},
 		{
-			name:         "refresh token mismatch",
-			refreshToken: refreshToken,
-			mockBehavior: func(u *mocks.MockUserRepository, tx *mock_transactor.MockTransactor, a *mocks.MockAuth) {
-				a.EXPECT().ValidateRefreshToken(refreshToken).Return(email, nil).Times(1)
-				tx.EXPECT().WithinTransaction(ctx, gomock.Any()).
-					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
-						return fn(ctx)
-					})
-				u.EXPECT().GetByEmail(ctx, email).Return(userWithDifferentToken, nil).Times(1)
-				// GenerateTokens и UpdateRefreshToken не должны вызываться
+			name: "user not found",
+			mockBehavior: func(m serviceMocks) {
+				m.auth.EXPECT().ValidateRefreshToken(refreshToken).Return(claims, nil).Times(1)
+				withinTx(ctx, m.tx)
+				m.sessions.EXPECT().GetByIDForUpdate(ctx, sessionID).Return(activeSession, nil).Times(1)
+				m.users.EXPECT().GetByID(ctx, userID).Return(entity.User{}, repository.ErrNoUserFound).Times(1)
 			},
 			want:    nil,
 			wantErr: service.ErrInvalidRefreshToken,
 		},
 		{
-			name:         "generate tokens error",
-			refreshToken: refreshToken,
-			mockBehavior: func(u *mocks.MockUserRepository, tx *mock_transactor.MockTransactor, a *mocks.MockAuth) {
-				a.EXPECT().ValidateRefreshToken(refreshToken).Return(email, nil).Times(1)
-				tx.EXPECT().WithinTransaction(ctx, gomock.Any()).
-					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
-						return fn(ctx)
-					})
-				u.EXPECT().GetByEmail(ctx, email).Return(validUser, nil).Times(1)
-				a.EXPECT().GenerateTokens(validUser).Return(nil, arbitraryErr).Times(1)
-				// UpdateRefreshToken не должен вызываться
+			name: "get user error",
+			mockBehavior: func(m serviceMocks) {
+				m.auth.EXPECT().ValidateRefreshToken(refreshToken).Return(claims, nil).Times(1)
+				withinTx(ctx, m.tx)
+				m.sessions.EXPECT().GetByIDForUpdate(ctx, sessionID).Return(activeSession, nil).Times(1)
+				m.users.EXPECT().GetByID(ctx, userID).Return(entity.User{}, arbitraryErr).Times(1)
+			},
+			want:    nil,
+			wantErr: arbitraryErr,
+		},
+		{
+			name: "disabled user",
+			mockBehavior: func(m serviceMocks) {
+				m.auth.EXPECT().ValidateRefreshToken(refreshToken).Return(claims, nil).Times(1)
+				withinTx(ctx, m.tx)
+				disabledAt := time.Now()
+				disabled := validUser
+				disabled.DisabledAt = &disabledAt
+				m.sessions.EXPECT().GetByIDForUpdate(ctx, sessionID).Return(activeSession, nil).Times(1)
+				m.users.EXPECT().GetByID(ctx, userID).Return(disabled, nil).Times(1)
+			},
+			want:    nil,
+			wantErr: service.ErrUserDisabled,
+		},
+		{
+			name: "generate tokens error",
+			mockBehavior: func(m serviceMocks) {
+				m.auth.EXPECT().ValidateRefreshToken(refreshToken).Return(claims, nil).Times(1)
+				withinTx(ctx, m.tx)
+				m.sessions.EXPECT().GetByIDForUpdate(ctx, sessionID).Return(activeSession, nil).Times(1)
+				m.users.EXPECT().GetByID(ctx, userID).Return(validUser, nil).Times(1)
+				m.auth.EXPECT().GenerateTokens(validUser, sessionID).Return(nil, arbitraryErr).Times(1)
 			},
 			want:    nil,
 			wantErr: arbitraryErr,
 		},
 		{
-			name:         "update refresh token error",
-			refreshToken: refreshToken,
-			mockBehavior: func(u *mocks.MockUserRepository, tx *mock_transactor.MockTransactor, a *mocks.MockAuth) {
-				a.EXPECT().ValidateRefreshToken(refreshToken).Return(email, nil).Times(1)
-				tx.EXPECT().WithinTransaction(ctx, gomock.Any()).
-					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
-						return fn(ctx)
-					})
-				u.EXPECT().GetByEmail(ctx, email).Return(validUser, nil).Times(1)
-				a.EXPECT().GenerateTokens(validUser).Return(tokens, nil).Times(1)
-				u.EXPECT().UpdateRefreshToken(ctx, userID, tokens.RefreshToken).Return(entity.User{}, arbitraryErr).Times(1)
+			name: "rotate error",
+			mockBehavior: func(m serviceMocks) {
+				m.auth.EXPECT().ValidateRefreshToken(refreshToken).Return(claims, nil).Times(1)
+				withinTx(ctx, m.tx)
+				m.sessions.EXPECT().GetByIDForUpdate(ctx, sessionID).Return(activeSession, nil).Times(1)
+				m.users.EXPECT().GetByID(ctx, userID).Return(validUser, nil).Times(1)
+				m.auth.EXPECT().GenerateTokens(validUser, sessionID).Return(tokens, nil).Times(1)
+				m.sessions.EXPECT().Rotate(ctx, rotation).Return(arbitraryErr).Times(1)
 			},
 			want:    nil,
 			wantErr: arbitraryErr,
@@ -561,16 +914,10 @@ func TestRefreshTokens(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
-			MockUserRepo := mocks.NewMockUserRepository(ctrl)
-			MockAuth := mocks.NewMockAuth(ctrl)
-			MockHasher := mocks.NewMockHasher(ctrl)
-			MockTransactor := mock_transactor.NewMockTransactor(ctrl)
-
-			tc.mockBehavior(MockUserRepo, MockTransactor, MockAuth)
+			s, m := newService(ctrl)
+			tc.mockBehavior(m)
 
-			s := service.New(MockUserRepo, MockTransactor, MockAuth, MockHasher)
-
-			out, err := s.RefreshTokens(ctx, tc.refreshToken)
+			out, err := s.RefreshTokens(ctx, refreshToken, client)
 			assert.ErrorIs(t, err, tc.wantErr)
 			assert.Equal(t, tc.want, out)
 		})
@@ -606,9 +953,9 @@ func TestDummyLogin(t *testing.T) {
 					Email: "dummyemail@google.com",
 					Role:  entity.RoleEmployee,
 				}
-				a.EXPECT().GenerateTokens(expectedUser).Return(tokens, nil).Times(1)
+				a.EXPECT().GenerateTokens(expectedUser, uuid.Nil).Return(tokens, nil).Times(1)
 			},
-			want:    tokens.RefreshToken,
+			want:    tokens.AccessToken,
 			wantErr: nil,
 		},
 		{
@@ -619,9 +966,9 @@ func TestDummyLogin(t *testing.T) {
 					Email: "dummyemail@google.com",
 					Role:  entity.RoleModerator,
 				}
-				a.EXPECT().GenerateTokens(expectedUser).Return(tokens, nil).Times(1)
+				a.EXPECT().GenerateTokens(expectedUser, uuid.Nil).Return(tokens, nil).Times(1)
 			},
-			want:    tokens.RefreshToken,
+			want:    tokens.AccessToken,
 			wantErr: nil,
 		},
 		{
@@ -632,7 +979,7 @@ func TestDummyLogin(t *testing.T) {
 					Email: "dummyemail@google.com",
 					Role:  entity.RoleEmployee,
 				}
-				a.EXPECT().GenerateTokens(expectedUser).Return(nil, arbitraryErr).Times(1)
+				a.EXPECT().GenerateTokens(expectedUser, uuid.Nil).Return(nil, arbitraryErr).Times(1)
 			},
 			want:    "",
 			wantErr: arbitraryErr,
@@ -643,14 +990,8 @@ func TestDummyLogin(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
-			MockUserRepo := mocks.NewMockUserRepository(ctrl)
-			MockAuth := mocks.NewMockAuth(ctrl)
-			MockHasher := mocks.NewMockHasher(ctrl)
-			MockTransactor := mock_transactor.NewMockTransactor(ctrl)
-
-			tc.mockBehavior(MockAuth)
-
-			s := service.New(MockUserRepo, MockTransactor, MockAuth, MockHasher)
+			s, m := newService(ctrl)
+			tc.mockBehavior(m.auth)
 
 			out, err := s.DummyLogin(ctx, tc.role)
 			assert.ErrorIs(t, err, tc.wantErr)
@@ -663,37 +1004,115 @@ func TestLogout(t *testing.T) {
 	var (
 		ctx          = context.Background()
 		userID       = uuid.New()
+		sessionID    = uuid.New()
+		jti          = uuid.NewString()
+		expiresAt    = time.Now().Add(10 * time.Minute)
 		arbitraryErr = errors.New("arbitrary error")
 	)
 
-	updatedUser := entity.User{
-		ID:           userID,
-		Email:        "test@mail.com",
-		Role:         entity.RoleEmployee,
-		RefreshToken: "", // после логаута должен быть nil или пустая строка
+	type MockBehavior func(m serviceMocks)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		wantErr      error
+	}{
+		{
+			name: "success",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.sessions.EXPECT().Revoke(ctx, userID, sessionID).Return(nil).Times(1)
+				m.audit.EXPECT().Create(ctx, entity.AuditRecord{
+					ActorID:    userID,
+					Action:     entity.AuditActionSessionRevoked,
+					TargetType: entity.AuditTargetSession,
+					TargetID:   sessionID,
+				}).Return(nil).Times(1)
+				m.revoker.EXPECT().Revoke(ctx, jti, expiresAt).Return(nil).Times(1)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "token without session still revokes access token",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.sessions.EXPECT().Revoke(ctx, userID, sessionID).Return(repository.ErrNoSessionFound).Times(1)
+				m.revoker.EXPECT().Revoke(ctx, jti, expiresAt).Return(nil).Times(1)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "revoke session error",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.sessions.EXPECT().Revoke(ctx, userID, sessionID).Return(arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+		{
+			name: "audit error",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.sessions.EXPECT().Revoke(ctx, userID, sessionID).Return(nil).Times(1)
+				m.audit.EXPECT().Create(ctx, gomock.Any()).Return(arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+		{
+			name: "revoke access token error",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.sessions.EXPECT().Revoke(ctx, userID, sessionID).Return(nil).Times(1)
+				m.audit.EXPECT().Create(ctx, gomock.Any()).Return(nil).Times(1)
+				m.revoker.EXPECT().Revoke(ctx, jti, expiresAt).Return(arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s, m := newService(ctrl)
+			tc.mockBehavior(m)
+
+			err := s.Logout(ctx, userID, sessionID, jti, expiresAt)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
 	}
+}
+
+func TestGetSessions(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		userID       = uuid.New()
+		arbitraryErr = errors.New("arbitrary error")
+		sessions     = []entity.Session{
+			{ID: uuid.New(), UserID: userID, UserAgent: "scanner/1.0"},
+			{ID: uuid.New(), UserID: userID, UserAgent: "scanner/2.0"},
+		}
+	)
 
-	type MockBehavior func(u *mocks.MockUserRepository)
+	type MockBehavior func(m serviceMocks)
 
 	for _, tc := range []struct {
 		name         string
-		userID       uuid.UUID
 		mockBehavior MockBehavior
+		want         []entity.Session
 		wantErr      error
 	}{
 		{
-			name:   "success",
-			userID: userID,
-			mockBehavior: func(u *mocks.MockUserRepository) {
-				u.EXPECT().UpdateRefreshToken(ctx, userID, "").Return(updatedUser, nil).Times(1)
+			name: "success",
+			mockBehavior: func(m serviceMocks) {
+				m.sessions.EXPECT().GetActiveByUser(ctx, userID).Return(sessions, nil).Times(1)
 			},
-			wantErr: nil,
+			want: sessions,
 		},
 		{
-			name:   "update refresh token error",
-			userID: userID,
-			mockBehavior: func(u *mocks.MockUserRepository) {
-				u.EXPECT().UpdateRefreshToken(ctx, userID, "").Return(entity.User{}, arbitraryErr).Times(1)
+			name: "repository error",
+			mockBehavior: func(m serviceMocks) {
+				m.sessions.EXPECT().GetActiveByUser(ctx, userID).Return(nil, arbitraryErr).Times(1)
 			},
 			wantErr: arbitraryErr,
 		},
@@ -703,16 +1122,79 @@ func TestLogout(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
-			MockUserRepo := mocks.NewMockUserRepository(ctrl)
-			MockAuth := mocks.NewMockAuth(ctrl)
-			MockHasher := mocks.NewMockHasher(ctrl)
-			MockTransactor := mock_transactor.NewMockTransactor(ctrl)
+			s, m := newService(ctrl)
+			tc.mockBehavior(m)
+
+			out, err := s.GetSessions(ctx, userID)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.want, out)
+		})
+	}
+}
 
-			tc.mockBehavior(MockUserRepo)
+func TestRevokeSession(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		userID       = uuid.New()
+		sessionID    = uuid.New()
+		arbitraryErr = errors.New("arbitrary error")
+	)
+
+	type MockBehavior func(m serviceMocks)
+
+	for _, tc := range []struct {
+		name         string
+		mockBehavior MockBehavior
+		wantErr      error
+	}{
+		{
+			name: "success",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.sessions.EXPECT().Revoke(ctx, userID, sessionID).Return(nil).Times(1)
+				m.audit.EXPECT().Create(ctx, entity.AuditRecord{
+					ActorID:    userID,
+					Action:     entity.AuditActionSessionRevoked,
+					TargetType: entity.AuditTargetSession,
+					TargetID:   sessionID,
+				}).Return(nil).Times(1)
+			},
+		},
+		{
+			name: "foreign or unknown session",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.sessions.EXPECT().Revoke(ctx, userID, sessionID).Return(repository.ErrNoSessionFound).Times(1)
+			},
+			wantErr: service.ErrNoSessionFound,
+		},
+		{
+			name: "repository error",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.sessions.EXPECT().Revoke(ctx, userID, sessionID).Return(arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+		{
+			name: "audit error",
+			mockBehavior: func(m serviceMocks) {
+				withinTx(ctx, m.tx)
+				m.sessions.EXPECT().Revoke(ctx, userID, sessionID).Return(nil).Times(1)
+				m.audit.EXPECT().Create(ctx, gomock.Any()).Return(arbitraryErr).Times(1)
+			},
+			wantErr: arbitraryErr,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
 
-			s := service.New(MockUserRepo, MockTransactor, MockAuth, MockHasher)
+			s, m := newService(ctrl)
+			tc.mockBehavior(m)
 
-			err := s.Logout(ctx, tc.userID)
+			err := s.RevokeSession(ctx, userID, sessionID)
 			assert.ErrorIs(t, err, tc.wantErr)
 		})
 	}
@@ -0,0 +1,67 @@
+package encryptor
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+const KeySize = 32
+
+var (
+	ErrInvalidKey        = errors.New("encryption key must be 32 bytes, base64 encoded")
+	ErrInvalidCiphertext = errors.New("invalid ciphertext")
+)
+
+// AESEncryptor seals small secrets with AES-256-GCM. The associated data
+// binds a ciphertext to its owner, so it cannot be copied to another row.
+type AESEncryptor struct {
+	aead cipher.AEAD
+}
+
+// New takes a base64 encoded 32 byte key.
+func New(key string) (*AESEncryptor, error) {
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil || len(raw) != KeySize {
+		return nil, ErrInvalidKey
+	}
+
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, fmt.Errorf("encryptor - New - aes.NewCipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("encryptor - New - cipher.NewGCM: %w", err)
+	}
+
+	return &AESEncryptor{aead: aead}, nil
+}
+
+// Encrypt returns base64(nonce || ciphertext).
+func (e *AESEncryptor) Encrypt(plaintext []byte, associatedData []byte) (string, error) {
+	nonce := make([]byte, e.aead.NonceSize(), e.aead.NonceSize()+len(plaintext)+e.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := e.aead.Seal(nonce, nonce, plaintext, associatedData)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (e *AESEncryptor) Decrypt(ciphertext string, associatedData []byte) ([]byte, error) {
+	sealed, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil || len(sealed) < e.aead.NonceSize() {
+		return nil, ErrInvalidCiphertext
+	}
+
+	nonce, sealed := sealed[:e.aead.NonceSize()], sealed[e.aead.NonceSize():]
+	plaintext, err := e.aead.Open(nil, nonce, sealed, associatedData)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,68 @@
+package encryptor_test
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/pkg/encryptor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newKey(t *testing.T) string {
+	t.Helper()
+	key := make([]byte, encryptor.KeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func TestNew(t *testing.T) {
+	for _, key := range []string{"", "not base64!", base64.StdEncoding.EncodeToString([]byte("short"))} {
+		_, err := encryptor.New(key)
+		assert.ErrorIs(t, err, encryptor.ErrInvalidKey)
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	e, err := encryptor.New(newKey(t))
+	require.NoError(t, err)
+
+	secret := []byte("JBSWY3DPEHPK3PXP")
+	owner := []byte("user-1")
+
+	ciphertext, err := e.Encrypt(secret, owner)
+	require.NoError(t, err)
+	assert.NotContains(t, ciphertext, string(secret))
+
+	again, err := e.Encrypt(secret, owner)
+	require.NoError(t, err)
+	assert.NotEqual(t, ciphertext, again, "nonce must be random")
+
+	plaintext, err := e.Decrypt(ciphertext, owner)
+	require.NoError(t, err)
+	assert.Equal(t, secret, plaintext)
+
+	t.Run("other owner", func(t *testing.T) {
+		_, err := e.Decrypt(ciphertext, []byte("user-2"))
+		assert.ErrorIs(t, err, encryptor.ErrInvalidCiphertext)
+	})
+
+	t.Run("other key", func(t *testing.T) {
+		other, err := encryptor.New(newKey(t))
+		require.NoError(t, err)
+		_, err = other.Decrypt(ciphertext, owner)
+		assert.ErrorIs(t, err, encryptor.ErrInvalidCiphertext)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		raw, _ := base64.StdEncoding.DecodeString(ciphertext)
+		raw[len(raw)-1] ^= 1
+		_, err := e.Decrypt(base64.StdEncoding.EncodeToString(raw), owner)
+		assert.ErrorIs(t, err, encryptor.ErrInvalidCiphertext)
+
+		_, err = e.Decrypt("AAAA", owner)
+		assert.ErrorIs(t, err, encryptor.ErrInvalidCiphertext)
+	})
+}
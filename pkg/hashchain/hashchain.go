@@ -0,0 +1,58 @@
+package hashchain
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Genesis is the previous hash of the first link.
+var Genesis = strings.Repeat("0", 2*sha256.Size)
+
+var ErrInvalidKey = errors.New("signing key must be a 32 byte ed25519 seed, base64 encoded")
+
+// Link returns the hex encoded SHA-256 of prev and payload. Changing any
+// earlier payload changes every later hash.
+func Link(prev string, payload []byte) string {
+	h := sha256.New()
+	h.Write([]byte(prev))
+	h.Write([]byte{'\n'})
+	h.Write(payload)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Signer signs checkpoints: the position and hash of a link, so that the
+// chain up to it cannot be rewritten without the key.
+type Signer struct {
+	key ed25519.PrivateKey
+}
+
+// NewSigner takes a base64 encoded 32 byte ed25519 seed.
+func NewSigner(seed string) (*Signer, error) {
+	raw, err := base64.StdEncoding.DecodeString(seed)
+	if err != nil || len(raw) != ed25519.SeedSize {
+		return nil, ErrInvalidKey
+	}
+	return &Signer{key: ed25519.NewKeyFromSeed(raw)}, nil
+}
+
+// Sign returns the base64 encoded signature of the link at seq.
+func (s *Signer) Sign(seq int64, hash string) string {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, message(seq, hash)))
+}
+
+func (s *Signer) Verify(seq int64, hash, signature string) bool {
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(s.key.Public().(ed25519.PublicKey), message(seq, hash), sig)
+}
+
+func message(seq int64, hash string) []byte {
+	return []byte(strconv.FormatInt(seq, 10) + ":" + hash)
+}
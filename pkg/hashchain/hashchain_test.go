@@ -0,0 +1,53 @@
+package hashchain_test
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/pkg/hashchain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSeed = base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32)))
+
+func TestLink(t *testing.T) {
+	first := hashchain.Link(hashchain.Genesis, []byte(`{"seq":1}`))
+	assert.Len(t, first, 64)
+	assert.Equal(t, first, hashchain.Link(hashchain.Genesis, []byte(`{"seq":1}`)), "deterministic")
+
+	assert.NotEqual(t, first, hashchain.Link(hashchain.Genesis, []byte(`{"seq":2}`)), "payload")
+	assert.NotEqual(t, first, hashchain.Link(strings.Repeat("1", 64), []byte(`{"seq":1}`)), "previous hash")
+}
+
+func TestNewSigner(t *testing.T) {
+	_, err := hashchain.NewSigner(testSeed)
+	assert.NoError(t, err)
+
+	for _, seed := range []string{
+		"",
+		"not base64!",
+		base64.StdEncoding.EncodeToString([]byte("short")),
+	} {
+		_, err := hashchain.NewSigner(seed)
+		assert.ErrorIs(t, err, hashchain.ErrInvalidKey, seed)
+	}
+}
+
+func TestSigner(t *testing.T) {
+	s, err := hashchain.NewSigner(testSeed)
+	require.NoError(t, err)
+
+	hash := hashchain.Link(hashchain.Genesis, []byte("payload"))
+	sig := s.Sign(7, hash)
+
+	assert.True(t, s.Verify(7, hash, sig))
+	assert.False(t, s.Verify(8, hash, sig), "other position")
+	assert.False(t, s.Verify(7, hashchain.Genesis, sig), "other hash")
+	assert.False(t, s.Verify(7, hash, "not base64!"))
+
+	other, err := hashchain.NewSigner(base64.StdEncoding.EncodeToString([]byte(strings.Repeat("o", 32))))
+	require.NoError(t, err)
+	assert.False(t, other.Verify(7, hash, sig), "other key")
+}
@@ -0,0 +1,88 @@
+package hasher_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/pkg/hasher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Cheap parameters keep the tests fast
+var testParams = hasher.Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func TestNewArgon2(t *testing.T) {
+	_, err := hasher.NewArgon2(hasher.DefaultArgon2Params)
+	assert.NoError(t, err)
+
+	for _, p := range []hasher.Argon2Params{
+		{Memory: 64, Iterations: 0, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+		{Memory: 64, Iterations: 1, Parallelism: 0, SaltLength: 16, KeyLength: 32},
+		{Memory: 4, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+		{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 4, KeyLength: 32},
+		{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 8},
+	} {
+		_, err := hasher.NewArgon2(p)
+		assert.ErrorIs(t, err, hasher.ErrInvalidParams, "%+v", p)
+	}
+}
+
+func TestArgon2Hasher(t *testing.T) {
+	h, err := hasher.NewArgon2(testParams)
+	require.NoError(t, err)
+
+	hash, err := h.HashPassword("Secret123")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$"), hash)
+
+	again, err := h.HashPassword("Secret123")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, again, "salted")
+
+	assert.True(t, h.CheckPasswordHash("Secret123", hash))
+	assert.False(t, h.CheckPasswordHash("Secret124", hash))
+	assert.False(t, h.NeedsRehash(hash))
+}
+
+func TestArgon2HasherKnownHash(t *testing.T) {
+	h, err := hasher.NewArgon2(testParams)
+	require.NoError(t, err)
+
+	// Written by the reference implementation: argon2 -id -t 2 -m 16 -p 1
+	hash := "$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"
+	assert.True(t, h.CheckPasswordHash("password", hash))
+	assert.False(t, h.CheckPasswordHash("Password", hash))
+	assert.True(t, h.NeedsRehash(hash), "other parameters")
+}
+
+func TestArgon2HasherLegacyBcrypt(t *testing.T) {
+	h, err := hasher.NewArgon2(testParams)
+	require.NoError(t, err)
+
+	legacy, err := bcrypt.GenerateFromPassword([]byte("Secret123"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	assert.True(t, h.CheckPasswordHash("Secret123", string(legacy)))
+	assert.False(t, h.CheckPasswordHash("Secret124", string(legacy)))
+	assert.True(t, h.NeedsRehash(string(legacy)))
+}
+
+func TestArgon2HasherMalformed(t *testing.T) {
+	h, err := hasher.NewArgon2(testParams)
+	require.NoError(t, err)
+
+	for _, hash := range []string{
+		"",
+		"plain",
+		"$argon2i$v=19$m=64,t=1,p=1$c29tZXNhbHQ$a2V5",
+		"$argon2id$v=16$m=64,t=1,p=1$c29tZXNhbHQ$a2V5",
+		"$argon2id$v=19$m=64,t=0,p=1$c29tZXNhbHQ$a2V5",
+		"$argon2id$v=19$m=64,t=1,p=1$!!!$a2V5",
+		"$argon2id$v=19$m=64,t=1,p=1$c29tZXNhbHQ$",
+	} {
+		assert.False(t, h.CheckPasswordHash("password", hash), hash)
+		assert.True(t, h.NeedsRehash(hash), hash)
+	}
+}
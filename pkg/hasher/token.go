@@ -0,0 +1,14 @@
+package hasher
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// HashToken returns the hex SHA-256 of a high-entropy token. Unlike
+// passwords such tokens cannot be brute-forced, so a fast deterministic
+// hash is enough and allows lookups by hash.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
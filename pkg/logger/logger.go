@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Fields every line of a request may carry, so that the handler, service
+// and repository lines of one request can be joined.
+const (
+	RequestID = "request_id"
+	UserID    = "user_id"
+	PointID   = "point_id"
+	Route     = "route"
+	Method    = "method"
+)
+
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// Setup configures the standard logger, which the loggers of requests are
+// derived from, and installs RedactHook on it.
+func Setup(level, format string) error {
+	logrusLevel, err := logrus.ParseLevel(level)
+	if err != nil {
+		return fmt.Errorf("logger - Setup - ParseLevel: %w", err)
+	}
+	logrus.SetLevel(logrusLevel)
+
+	switch format {
+	case FormatJSON, "":
+		logrus.SetFormatter(&logrus.JSONFormatter{TimestampFormat: time.DateTime})
+	case FormatText:
+		logrus.SetFormatter(&logrus.TextFormatter{TimestampFormat: time.DateTime, FullTimestamp: true})
+	default:
+		return fmt.Errorf("logger - Setup: unknown format %q", format)
+	}
+
+	hooks := make(logrus.LevelHooks)
+	hooks.Add(RedactHook{})
+	logrus.StandardLogger().ReplaceHooks(hooks)
+	return nil
+}
+
+type contextKey struct{}
+
+func NewContext(ctx context.Context, entry *logrus.Entry) context.Context {
+	return context.WithValue(ctx, contextKey{}, entry)
+}
+
+// FromContext returns the logger of the request, or the standard logger
+// outside of a request.
+func FromContext(ctx context.Context) *logrus.Entry {
+	if entry, ok := ctx.Value(contextKey{}).(*logrus.Entry); ok {
+		return entry
+	}
+	return logrus.NewEntry(logrus.StandardLogger())
+}
+
+// With returns ctx with a logger that adds fields to every line.
+func With(ctx context.Context, fields logrus.Fields) context.Context {
+	return NewContext(ctx, FromContext(ctx).WithFields(fields))
+}
@@ -0,0 +1,36 @@
+package logger
+
+import (
+	"github.com/4udiwe/avito-pvz/pkg/redact"
+	"github.com/sirupsen/logrus"
+)
+
+// RedactHook masks secrets and emails in every line: fields named like a
+// secret are replaced whole, and tokens, password hashes and emails found
+// in the message or in other string fields are masked. It is the last line
+// of defence; values should not be logged in the first place.
+type RedactHook struct{}
+
+func (RedactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire gets a copy of the entry made for this line, so its fields can be
+// changed in place.
+func (RedactHook) Fire(entry *logrus.Entry) error {
+	entry.Message = redact.String(entry.Message)
+
+	for key, value := range entry.Data {
+		if redact.IsSensitive(key) {
+			entry.Data[key] = redact.Mask
+			continue
+		}
+		switch v := value.(type) {
+		case string:
+			entry.Data[key] = redact.String(v)
+		case error:
+			entry.Data[key] = redact.String(v.Error())
+		}
+	}
+	return nil
+}
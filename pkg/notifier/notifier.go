@@ -0,0 +1,108 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Message is a notification addressed to a single recipient (email, phone, etc.).
+type Message struct {
+	Recipient string `json:"recipient"`
+	Subject   string `json:"subject"`
+	Body      string `json:"body"`
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+const (
+	TypeLog  = "log"
+	TypeFile = "file"
+)
+
+// New returns a notifier of the given type: "log" or "file" (which requires filePath).
+func New(kind string, filePath string) (Notifier, error) {
+	switch kind {
+	case TypeLog, "":
+		return NewLogNotifier(), nil
+	case TypeFile:
+		if filePath == "" {
+			return nil, fmt.Errorf("notifier - New: file path is required for %q notifier", kind)
+		}
+		return NewFileNotifier(filePath), nil
+	default:
+		return nil, fmt.Errorf("notifier - New: unknown notifier type %q", kind)
+	}
+}
+
+// LogNotifier writes notifications as log lines to stdout. Intended for
+// local use. It has its own logger, apart from the application log: it is
+// the delivery channel, so the links, tokens and addresses it carries must
+// stay readable where the application log masks them.
+type LogNotifier struct {
+	log *logrus.Logger
+}
+
+func NewLogNotifier() *LogNotifier {
+	return NewLogNotifierTo(os.Stdout)
+}
+
+// NewLogNotifierTo is NewLogNotifier writing to w.
+func NewLogNotifierTo(w io.Writer) *LogNotifier {
+	return &LogNotifier{log: &logrus.Logger{
+		Out:       w,
+		Formatter: &logrus.JSONFormatter{TimestampFormat: time.DateTime},
+		Hooks:     make(logrus.LevelHooks),
+		Level:     logrus.InfoLevel,
+	}}
+}
+
+func (n *LogNotifier) Notify(_ context.Context, msg Message) error {
+	n.log.WithFields(logrus.Fields{
+		"recipient": msg.Recipient,
+		"subject":   msg.Subject,
+	}).Info(msg.Body)
+	return nil
+}
+
+// FileNotifier appends notifications as JSON lines to a file. Intended for local use.
+type FileNotifier struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileNotifier(path string) *FileNotifier {
+	return &FileNotifier{path: path}
+}
+
+func (n *FileNotifier) Notify(_ context.Context, msg Message) error {
+	line, err := json.Marshal(struct {
+		Message
+		SentAt time.Time `json:"sent_at"`
+	}{msg, time.Now()})
+	if err != nil {
+		return fmt.Errorf("notifier - FileNotifier.Notify - json.Marshal: %w", err)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	f, err := os.OpenFile(n.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("notifier - FileNotifier.Notify - os.OpenFile: %w", err)
+	}
+	defer f.Close()
+
+	if _, err = f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("notifier - FileNotifier.Notify - Write: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+package oidc
+
+import "time"
+
+// SetKeyRefreshInterval lets tests reload keys without waiting.
+func SetKeyRefreshInterval(d time.Duration) func() {
+	old := keyRefreshInterval
+	keyRefreshInterval = d
+	return func() { keyRefreshInterval = old }
+}
@@ -0,0 +1,203 @@
+package oidc_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/pkg/oidc"
+	"github.com/4udiwe/avito-pvz/pkg/oidc/oidctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// login runs the authorization code flow against the fake provider and
+// returns the raw ID token.
+func login(t *testing.T, p *oidc.Provider, idp *oidctest.Server, nonce string, claims map[string]any) (string, error) {
+	t.Helper()
+	ctx := context.Background()
+
+	verifier, err := oidc.RandomString()
+	require.NoError(t, err)
+
+	authURL, err := p.AuthCodeURL(ctx, "state", nonce, verifier)
+	require.NoError(t, err)
+
+	code, err := idp.Login(authURL, claims)
+	require.NoError(t, err)
+
+	return p.Exchange(ctx, code, verifier)
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	idp := oidctest.NewServer()
+	defer idp.Close()
+
+	p := oidc.New(idp.Config(), nil)
+
+	authURL, err := p.AuthCodeURL(context.Background(), "state-1", "nonce-1", "verifier-1")
+	require.NoError(t, err)
+
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+	q := u.Query()
+
+	assert.Equal(t, idp.URL+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+	assert.Equal(t, "code", q.Get("response_type"))
+	assert.Equal(t, oidctest.ClientID, q.Get("client_id"))
+	assert.Equal(t, oidctest.RedirectURL, q.Get("redirect_uri"))
+	assert.Equal(t, "openid email groups", q.Get("scope"))
+	assert.Equal(t, "state-1", q.Get("state"))
+	assert.Equal(t, "nonce-1", q.Get("nonce"))
+	assert.Equal(t, oidc.CodeChallenge("verifier-1"), q.Get("code_challenge"))
+	assert.Equal(t, "S256", q.Get("code_challenge_method"))
+}
+
+func TestDiscovery(t *testing.T) {
+	idp := oidctest.NewServer()
+	defer idp.Close()
+
+	cfg := idp.Config()
+	cfg.Issuer = idp.URL + "/other"
+
+	_, err := oidc.New(cfg, nil).AuthCodeURL(context.Background(), "state", "nonce", "verifier")
+	assert.ErrorIs(t, err, oidc.ErrDiscovery)
+
+	cfg.Issuer = "http://127.0.0.1:1"
+	_, err = oidc.New(cfg, nil).AuthCodeURL(context.Background(), "state", "nonce", "verifier")
+	assert.ErrorIs(t, err, oidc.ErrDiscovery)
+}
+
+func TestExchangeAndVerify(t *testing.T) {
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name    string
+		nonce   string
+		claims  map[string]any
+		wantErr error
+		want    oidc.IDToken
+	}{
+		{
+			name:  "success",
+			nonce: "nonce",
+			claims: map[string]any{
+				"sub":            "user-1",
+				"email":          "staff@corp.example",
+				"email_verified": true,
+				"groups":         []string{"pvz-moderators", "everyone"},
+			},
+			want: oidc.IDToken{Subject: "user-1", Email: "staff@corp.example", EmailVerified: true},
+		},
+		{
+			name:    "wrong audience",
+			nonce:   "nonce",
+			claims:  map[string]any{"aud": "another-client"},
+			wantErr: oidc.ErrInvalidIDToken,
+		},
+		{
+			name:    "wrong issuer",
+			nonce:   "nonce",
+			claims:  map[string]any{"iss": "https://evil.example"},
+			wantErr: oidc.ErrInvalidIDToken,
+		},
+		{
+			name:    "expired",
+			nonce:   "nonce",
+			claims:  map[string]any{"exp": time.Now().Add(-time.Minute).Unix()},
+			wantErr: oidc.ErrInvalidIDToken,
+		},
+		{
+			name:    "nonce mismatch",
+			nonce:   "nonce",
+			claims:  map[string]any{"nonce": "replayed"},
+			wantErr: oidc.ErrInvalidIDToken,
+		},
+		{
+			name:    "several audiences without azp",
+			nonce:   "nonce",
+			claims:  map[string]any{"aud": []string{oidctest.ClientID, "another-client"}},
+			wantErr: oidc.ErrInvalidIDToken,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			idp := oidctest.NewServer()
+			defer idp.Close()
+			p := oidc.New(idp.Config(), nil)
+
+			rawIDToken, err := login(t, p, idp, tc.nonce, tc.claims)
+			require.NoError(t, err)
+
+			out, err := p.Verify(ctx, rawIDToken, tc.nonce)
+			assert.ErrorIs(t, err, tc.wantErr)
+			if tc.wantErr == nil {
+				assert.Equal(t, tc.want.Subject, out.Subject)
+				assert.Equal(t, tc.want.Email, out.Email)
+				assert.Equal(t, tc.want.EmailVerified, out.EmailVerified)
+				assert.Equal(t, []string{"pvz-moderators", "everyone"}, out.StringsClaim("groups"))
+			}
+		})
+	}
+}
+
+func TestExchangeErrors(t *testing.T) {
+	ctx := context.Background()
+
+	idp := oidctest.NewServer()
+	defer idp.Close()
+	p := oidc.New(idp.Config(), nil)
+
+	authURL, err := p.AuthCodeURL(ctx, "state", "nonce", "verifier")
+	require.NoError(t, err)
+	code, err := idp.Login(authURL, nil)
+	require.NoError(t, err)
+
+	t.Run("wrong code verifier", func(t *testing.T) {
+		_, err := p.Exchange(ctx, code, "another-verifier")
+		assert.ErrorIs(t, err, oidc.ErrExchange)
+	})
+
+	t.Run("code is single use", func(t *testing.T) {
+		_, err := p.Exchange(ctx, code, "verifier")
+		assert.ErrorIs(t, err, oidc.ErrExchange)
+	})
+
+	t.Run("wrong client secret", func(t *testing.T) {
+		cfg := idp.Config()
+		cfg.ClientSecret = "wrong"
+		other := oidc.New(cfg, nil)
+
+		authURL, err := other.AuthCodeURL(ctx, "state", "nonce", "verifier")
+		require.NoError(t, err)
+		code, err := idp.Login(authURL, nil)
+		require.NoError(t, err)
+
+		_, err = other.Exchange(ctx, code, "verifier")
+		assert.ErrorIs(t, err, oidc.ErrExchange)
+	})
+}
+
+func TestVerifyAfterKeyRotation(t *testing.T) {
+	ctx := context.Background()
+
+	idp := oidctest.NewServer()
+	defer idp.Close()
+	p := oidc.New(idp.Config(), nil)
+
+	rawIDToken, err := login(t, p, idp, "nonce", nil)
+	require.NoError(t, err)
+	_, err = p.Verify(ctx, rawIDToken, "nonce")
+	require.NoError(t, err)
+
+	// Keys are cached; a token signed with a new key makes the client reload them
+	defer oidc.SetKeyRefreshInterval(0)()
+	idp.RotateKey()
+
+	rawIDToken, err = login(t, p, idp, "nonce", nil)
+	require.NoError(t, err)
+	_, err = p.Verify(ctx, rawIDToken, "nonce")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,225 @@
+// Package oidctest provides an in-process OpenID provider for tests.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/pkg/oidc"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	ClientID     = "pvz-test-client"
+	ClientSecret = "pvz-test-secret"
+	RedirectURL  = "https://pvz.example.com/oidc/callback"
+)
+
+type grant struct {
+	challenge   string
+	redirectURI string
+	nonce       string
+	claims      map[string]any
+}
+
+// Server is a fake OpenID provider. Instead of a login page it has Login,
+// which authorizes an authorization request with the given claims.
+type Server struct {
+	URL string
+
+	srv *httptest.Server
+
+	mu     sync.Mutex
+	kid    string
+	key    *rsa.PrivateKey
+	keys   map[string]*rsa.PrivateKey
+	grants map[string]grant
+}
+
+func NewServer() *Server {
+	s := &Server{
+		keys:   make(map[string]*rsa.PrivateKey),
+		grants: make(map[string]grant),
+	}
+	s.RotateKey()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", s.discovery)
+	mux.HandleFunc("GET /jwks", s.jwks)
+	mux.HandleFunc("POST /token", s.token)
+
+	s.srv = httptest.NewServer(mux)
+	s.URL = s.srv.URL
+	return s
+}
+
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Config returns a client configuration for this provider.
+func (s *Server) Config() oidc.Config {
+	return oidc.Config{
+		Issuer:       s.URL,
+		ClientID:     ClientID,
+		ClientSecret: ClientSecret,
+		RedirectURL:  RedirectURL,
+		Scopes:       []string{"openid", "email", "groups"},
+	}
+}
+
+// RotateKey starts signing with a new key. Old keys stay published.
+func (s *Server) RotateKey() {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.kid = fmt.Sprintf("key-%d", len(s.keys)+1)
+	s.key = key
+	s.keys[s.kid] = key
+}
+
+// Login authorizes the request behind authURL for a user with the given
+// claims and returns the code the provider would redirect with. Claims
+// override the defaults (iss, aud, sub, iat, exp, nonce).
+func (s *Server) Login(authURL string, claims map[string]any) (code string, err error) {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+
+	switch {
+	case q.Get("response_type") != "code":
+		return "", fmt.Errorf("unsupported response_type %q", q.Get("response_type"))
+	case q.Get("client_id") != ClientID:
+		return "", fmt.Errorf("unknown client %q", q.Get("client_id"))
+	case q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "":
+		return "", fmt.Errorf("PKCE S256 is required")
+	}
+
+	code, err = oidc.RandomString()
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.grants[code] = grant{
+		challenge:   q.Get("code_challenge"),
+		redirectURI: q.Get("redirect_uri"),
+		nonce:       q.Get("nonce"),
+		claims:      claims,
+	}
+	return code, nil
+}
+
+func (s *Server) discovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                s.URL,
+		"authorization_endpoint":                s.URL + "/authorize",
+		"token_endpoint":                        s.URL + "/token",
+		"jwks_uri":                              s.URL + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (s *Server) jwks(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]map[string]string, 0, len(s.keys))
+	for kid, key := range s.keys {
+		keys = append(keys, map[string]string{
+			"kty": "RSA",
+			"kid": kid,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"keys": keys})
+}
+
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		tokenError(w, "invalid_request")
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok || clientID != ClientID || clientSecret != ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		tokenError(w, "unsupported_grant_type")
+		return
+	}
+
+	s.mu.Lock()
+	code := r.PostForm.Get("code")
+	g, ok := s.grants[code]
+	// Codes are single use
+	delete(s.grants, code)
+	kid, key := s.kid, s.key
+	s.mu.Unlock()
+
+	if !ok || g.redirectURI != r.PostForm.Get("redirect_uri") || oidc.CodeChallenge(r.PostForm.Get("code_verifier")) != g.challenge {
+		tokenError(w, "invalid_grant")
+		return
+	}
+
+	claims := jwt.MapClaims{
+		"iss":   s.URL,
+		"aud":   ClientID,
+		"sub":   "subject",
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(5 * time.Minute).Unix(),
+		"nonce": g.nonce,
+	}
+	for k, v := range g.claims {
+		claims[k] = v
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	idToken, err := token.SignedString(key)
+	if err != nil {
+		tokenError(w, "server_error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": "provider-access-token",
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	})
+}
+
+func tokenError(w http.ResponseWriter, code string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,233 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// keyRefreshInterval limits how often an unknown kid makes the client
+// reload the provider's keys.
+var keyRefreshInterval = 30 * time.Second
+
+// IDToken holds the verified claims of an ID token. Claims has all of them,
+// including provider specific ones such as groups.
+type IDToken struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Claims        map[string]any
+}
+
+type idTokenClaims struct {
+	Nonce         string `json:"nonce"`
+	Email         string `json:"email"`
+	EmailVerified any    `json:"email_verified"`
+	AuthorizedBy  string `json:"azp"`
+	jwt.RegisteredClaims
+}
+
+// Verify checks the signature, issuer, audience, expiry and nonce of an ID
+// token obtained from Exchange.
+func (p *Provider) Verify(ctx context.Context, rawIDToken string, nonce string) (IDToken, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return IDToken{}, err
+	}
+
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{
+		jwt.SigningMethodRS256.Alg(),
+		jwt.SigningMethodES256.Alg(),
+	}))
+
+	var claims idTokenClaims
+	token, err := parser.ParseWithClaims(rawIDToken, &claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.keys.get(ctx, kid)
+	})
+	if err != nil || !token.Valid {
+		return IDToken{}, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	switch {
+	case claims.Issuer != meta.Issuer:
+		return IDToken{}, fmt.Errorf("%w: issuer %q", ErrInvalidIDToken, claims.Issuer)
+	case !slices.Contains(claims.Audience, p.cfg.ClientID):
+		return IDToken{}, fmt.Errorf("%w: audience %v", ErrInvalidIDToken, claims.Audience)
+	case len(claims.Audience) > 1 && claims.AuthorizedBy != p.cfg.ClientID:
+		return IDToken{}, fmt.Errorf("%w: authorized party %q", ErrInvalidIDToken, claims.AuthorizedBy)
+	case claims.ExpiresAt == nil:
+		return IDToken{}, fmt.Errorf("%w: no expiry", ErrInvalidIDToken)
+	case claims.Subject == "":
+		return IDToken{}, fmt.Errorf("%w: no subject", ErrInvalidIDToken)
+	case claims.Nonce != nonce:
+		return IDToken{}, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+
+	// Provider specific claims are read from the payload again, untyped
+	all := jwt.MapClaims{}
+	if _, _, err = parser.ParseUnverified(rawIDToken, all); err != nil {
+		return IDToken{}, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	return IDToken{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified == true || claims.EmailVerified == "true",
+		Claims:        all,
+	}, nil
+}
+
+// StringsClaim returns a claim that is either a string or a list of
+// strings, such as groups or roles.
+func (t IDToken) StringsClaim(name string) []string {
+	switch v := t.Claims[name].(type) {
+	case string:
+		return []string{v}
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+// keyCache holds the provider's signing keys and reloads them when a token
+// is signed with an unknown kid, which happens after key rotation.
+type keyCache struct {
+	uri   string
+	fetch func(ctx context.Context, uri string) (map[string]any, error)
+
+	mu        sync.Mutex
+	keys      map[string]any
+	fetchedAt time.Time
+}
+
+func newKeyCache(uri string, fetch func(ctx context.Context, uri string) (map[string]any, error)) *keyCache {
+	return &keyCache{uri: uri, fetch: fetch}
+}
+
+func (c *keyCache) get(ctx context.Context, kid string) (any, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if key, ok := c.keys[kid]; ok {
+		return key, nil
+	}
+	if c.keys != nil && time.Since(c.fetchedAt) < keyRefreshInterval {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+
+	keys, err := c.fetch(ctx, c.uri)
+	if err != nil {
+		return nil, err
+	}
+	c.keys, c.fetchedAt = keys, time.Now()
+
+	if key, ok := c.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown key id %q", kid)
+}
+
+func (p *Provider) fetchKeys(ctx context.Context, uri string) (map[string]any, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var set jwks
+	status, err := p.do(req, &set)
+	if err != nil {
+		return nil, fmt.Errorf("fetch jwks: %w", err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("fetch jwks: status %d", status)
+	}
+
+	keys := make(map[string]any, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			// Keys of unsupported types are skipped, not fatal
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	return keys, nil
+}
+
+func (k jwk) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("empty key parameter")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -99,8 +99,28 @@ func (pg *Postgres) GetTxManager(ctx context.Context) TxManager {
 	return pg.Pool
 }
 
+// WithinTransaction runs fn in a transaction. Inside a transaction already
+// in ctx fn joins it, and its changes commit or roll back with it.
 func (pg *Postgres) WithinTransaction(ctx context.Context, fn func(context.Context) error) error {
-	tx, err := pg.Pool.Begin(ctx)
+	if _, ok := extractTx(ctx); ok {
+		return fn(ctx)
+	}
+	return pg.within(ctx, pg.Pool.Begin, fn)
+}
+
+// WithinSavepoint runs fn in a savepoint of the transaction in ctx, so fn
+// rolls back on its own while its changes commit with the transaction.
+// Without a transaction in ctx it opens one.
+func (pg *Postgres) WithinSavepoint(ctx context.Context, fn func(context.Context) error) error {
+	if outer, ok := extractTx(ctx); ok {
+		return pg.within(ctx, outer.Begin, fn)
+	}
+	return pg.within(ctx, pg.Pool.Begin, fn)
+}
+
+// within runs fn in the transaction or savepoint begin opens.
+func (pg *Postgres) within(ctx context.Context, begin func(context.Context) (pgx.Tx, error), fn func(context.Context) error) error {
+	tx, err := begin(ctx)
 	if err != nil {
 		return fmt.Errorf("postgres - Begin transaction: %w", err)
 	}
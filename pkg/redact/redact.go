@@ -0,0 +1,66 @@
+// Package redact masks secrets and personal data before they are logged.
+package redact
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Mask replaces a secret.
+const Mask = "[REDACTED]"
+
+// Values that are secrets wherever they appear: JWTs (access and refresh
+// tokens), password hashes, API keys (pvz_) and the password reset, MFA
+// pending and invitation tokens (pvzr_, pvzm_ and pvzi_).
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+	regexp.MustCompile(`\$argon2(?:id|i|d)\$[^\s"']+`),
+	regexp.MustCompile(`\$2[aby]?\$\d{2}\$[./A-Za-z0-9]{53}`),
+	regexp.MustCompile(`pvz[rmi]?_[A-Za-z0-9_-]{16,}`),
+}
+
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+
+// sensitiveKeys name fields whose values are masked whole.
+var sensitiveKeys = map[string]struct{}{
+	"password":        {},
+	"password_hash":   {},
+	"passwordhash":    {},
+	"token":           {},
+	"access_token":    {},
+	"refresh_token":   {},
+	"mfa_token":       {},
+	"reset_token":     {},
+	"secret":          {},
+	"api_key":         {},
+	"code":            {},
+	"recovery_code":   {},
+	"invitation_code": {},
+	"authorization":   {},
+	"cookie":          {},
+}
+
+// IsSensitive tells whether a field of this name holds a secret.
+func IsSensitive(key string) bool {
+	_, ok := sensitiveKeys[strings.ToLower(key)]
+	return ok
+}
+
+// Email keeps the first letter of the local part and the domain, which is
+// enough to tell users apart while debugging: moderator@mail.com becomes
+// m***@mail.com.
+func Email(email string) string {
+	at := strings.LastIndexByte(email, '@')
+	if at < 1 {
+		return Mask
+	}
+	return email[:1] + "***" + email[at:]
+}
+
+// String masks the secrets and emails found in s.
+func String(s string) string {
+	for _, pattern := range secretPatterns {
+		s = pattern.ReplaceAllString(s, Mask)
+	}
+	return emailPattern.ReplaceAllStringFunc(s, Email)
+}
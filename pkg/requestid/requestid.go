@@ -0,0 +1,40 @@
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Header carries the request ID in requests and responses.
+const Header = "X-Request-ID"
+
+// maxLength bounds IDs taken from clients, they end up in logs and the
+// audit log.
+const maxLength = 128
+
+type contextKey struct{}
+
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID, or "" outside of a request.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// Resolve keeps a well-formed ID sent by the client, so a request can be
+// traced across services, and generates one otherwise.
+func Resolve(id string) string {
+	if id == "" || len(id) > maxLength {
+		return uuid.NewString()
+	}
+	for _, c := range id {
+		if c < 0x21 || c > 0x7e {
+			return uuid.NewString()
+		}
+	}
+	return id
+}
@@ -0,0 +1,28 @@
+package requestid_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/pkg/requestid"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext(t *testing.T) {
+	assert.Equal(t, "", requestid.FromContext(context.Background()))
+
+	ctx := requestid.NewContext(context.Background(), "req-1")
+	assert.Equal(t, "req-1", requestid.FromContext(ctx))
+}
+
+func TestResolve(t *testing.T) {
+	assert.Equal(t, "req-1", requestid.Resolve("req-1"))
+
+	for _, id := range []string{"", "with space", "line\nbreak", "юникод", strings.Repeat("a", 129)} {
+		generated := requestid.Resolve(id)
+		_, err := uuid.Parse(generated)
+		assert.NoError(t, err, "%q", id)
+	}
+}
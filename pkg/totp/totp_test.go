@@ -0,0 +1,88 @@
+package totp_test
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/avito-pvz/pkg/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RFC 6238 appendix B, SHA1 key, truncated to six digits.
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCode(t *testing.T) {
+	for _, tc := range []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+		{unix: 20000000000, want: "353130"},
+	} {
+		code, err := totp.Code(rfcSecret, time.Unix(tc.unix, 0))
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, code, "time %d", tc.unix)
+	}
+
+	_, err := totp.Code("not base32!", time.Now())
+	assert.ErrorIs(t, err, totp.ErrInvalidSecret)
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	previous, err := totp.Code(rfcSecret, now.Add(-totp.Period))
+	require.NoError(t, err)
+	stale, err := totp.Code(rfcSecret, now.Add(-2*totp.Period))
+	require.NoError(t, err)
+
+	step, ok := totp.Validate(rfcSecret, "050471", now, 1)
+	assert.True(t, ok)
+	assert.Equal(t, totp.Step(now), step)
+
+	step, ok = totp.Validate(rfcSecret, previous, now, 1)
+	assert.True(t, ok)
+	assert.Equal(t, totp.Step(now)-1, step)
+
+	_, ok = totp.Validate(rfcSecret, stale, now, 1)
+	assert.False(t, ok)
+
+	_, ok = totp.Validate(rfcSecret, previous, now, 0)
+	assert.False(t, ok)
+
+	_, ok = totp.Validate(rfcSecret, "50471", now, 1)
+	assert.False(t, ok)
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	other, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	assert.NotEqual(t, secret, other)
+
+	_, err = totp.Code(secret, time.Now())
+	assert.NoError(t, err)
+}
+
+func TestURI(t *testing.T) {
+	uri := totp.URI("avito-pvz", "moderator@example.com", "JBSWY3DPEHPK3PXP")
+
+	u, err := url.Parse(uri)
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", u.Scheme)
+	assert.Equal(t, "totp", u.Host)
+	assert.Equal(t, "/avito-pvz:moderator@example.com", u.Path)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", u.Query().Get("secret"))
+	assert.Equal(t, "avito-pvz", u.Query().Get("issuer"))
+	assert.Equal(t, "6", u.Query().Get("digits"))
+	assert.Equal(t, "30", u.Query().Get("period"))
+}
@@ -0,0 +1,94 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// PasswordPolicy describes what a new password must look like. It is
+// enforced by the "password" validation tag.
+type PasswordPolicy struct {
+	MinLength      int
+	MaxLength      int
+	RequireUpper   bool
+	RequireLower   bool
+	RequireDigit   bool
+	RequireSpecial bool
+}
+
+// DefaultPasswordPolicy is used when no policy is configured. MaxLength
+// stays within the 72 bytes bcrypt actually hashes.
+var DefaultPasswordPolicy = PasswordPolicy{
+	MinLength:    8,
+	MaxLength:    72,
+	RequireUpper: true,
+	RequireLower: true,
+	RequireDigit: true,
+}
+
+func (p PasswordPolicy) Allows(password string) bool {
+	length := utf8.RuneCountInString(password)
+	if length < p.MinLength || (p.MaxLength > 0 && length > p.MaxLength) {
+		return false
+	}
+
+	var upper, lower, digit, special bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			special = true
+		}
+	}
+
+	return (!p.RequireUpper || upper) &&
+		(!p.RequireLower || lower) &&
+		(!p.RequireDigit || digit) &&
+		(!p.RequireSpecial || special)
+}
+
+// Describe returns the policy as a human readable requirement.
+func (p PasswordPolicy) Describe() string {
+	var b strings.Builder
+	if p.MaxLength > 0 {
+		fmt.Fprintf(&b, "must be %d to %d characters long", p.MinLength, p.MaxLength)
+	} else {
+		fmt.Fprintf(&b, "must be at least %d characters long", p.MinLength)
+	}
+
+	var required []string
+	if p.RequireUpper {
+		required = append(required, "an uppercase letter")
+	}
+	if p.RequireLower {
+		required = append(required, "a lowercase letter")
+	}
+	if p.RequireDigit {
+		required = append(required, "a digit")
+	}
+	if p.RequireSpecial {
+		required = append(required, "a special character")
+	}
+
+	switch len(required) {
+	case 0:
+	case 1:
+		b.WriteString(" and contain " + required[0])
+	default:
+		b.WriteString(" and contain " + strings.Join(required[:len(required)-1], ", ") + " and " + required[len(required)-1])
+	}
+	return b.String()
+}
+
+func (p PasswordPolicy) validate(fl validator.FieldLevel) bool {
+	return p.Allows(fl.Field().String())
+}
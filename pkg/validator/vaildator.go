@@ -10,12 +10,28 @@ import (
 )
 
 type CustomValidator struct {
-	v *validator.Validate
+	v              *validator.Validate
+	passwordPolicy PasswordPolicy
 }
 
-func NewCustomValidator() *CustomValidator {
+type Option func(*CustomValidator)
+
+// WithPasswordPolicy overrides DefaultPasswordPolicy for the "password" tag.
+func WithPasswordPolicy(policy PasswordPolicy) Option {
+	return func(cv *CustomValidator) {
+		cv.passwordPolicy = policy
+	}
+}
+
+func NewCustomValidator(opts ...Option) *CustomValidator {
 	v := validator.New()
-	cv := &CustomValidator{v: v}
+	cv := &CustomValidator{v: v, passwordPolicy: DefaultPasswordPolicy}
+
+	for _, opt := range opts {
+		opt(cv)
+	}
+
+	_ = v.RegisterValidation("password", cv.passwordPolicy.validate)
 
 	v.RegisterTagNameFunc(func(fld reflect.StructField) string {
 		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
@@ -54,6 +70,8 @@ func (cv *CustomValidator) newValidationError(field string, tag string, param st
 		return fmt.Errorf("field %s must be at least %s characters", field, param)
 	case "max":
 		return fmt.Errorf("field %s must be at most %s characters", field, param)
+	case "password":
+		return fmt.Errorf("field %s %s", field, cv.passwordPolicy.Describe())
 	default:
 		return fmt.Errorf("field %s is invalid", field)
 	}
@@ -16,7 +16,8 @@ const (
 	defaultAttempts = 20
 	host            = "app:8080"
 	healthPath      = "http://" + host + "/health"
-	basePath        = "http://app:8080"
+	rootPath        = "http://" + host
+	basePath        = rootPath + "/api/v1"
 )
 
 func Login(role string) (string, error) {
@@ -30,17 +31,30 @@ func Login(role string) (string, error) {
 	body := map[string]string{
 		"email":    email,
 		"password": password,
-		"role":     role,
+	}
+
+	// The moderator is created by avito-pvz create-user in
+	// docker-compose.test.yaml, registration only creates employees
+	if email == employeeEmail {
+		err := hit.Do(
+			hit.Post(basePath+"/register"),
+			hit.Send().Headers("Content-Type").Add("application/json"),
+			hit.Send().Body().JSON(body),
+			// Tests share the users, the first one registers them
+			hit.Expect().Status().OneOf(http.StatusCreated, http.StatusConflict),
+		)
+		if err != nil {
+			return "", fmt.Errorf("register failed: %w", err)
+		}
 	}
 
 	err := hit.Do(
-		hit.Post(basePath+"/register"),
+		hit.Post(basePath+"/login"),
 		hit.Send().Headers("Content-Type").Add("application/json"),
 		hit.Send().Body().JSON(body),
-		hit.Expect().Status().Equal(http.StatusCreated),
+		hit.Expect().Status().Equal(http.StatusOK),
 		hit.Store().Response().Body().JSON().JQ(".access_token").In(&accessToken),
 	)
-
 	if err != nil {
 		return "", fmt.Errorf("login failed: %w", err)
 	}
@@ -0,0 +1,62 @@
+//go:build integration
+
+package integration_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	. "github.com/Eun/go-hit"
+	"github.com/google/uuid"
+)
+
+func TestIdempotentRetry(t *testing.T) {
+	moderatorToken, err := Login(string(entity.RoleModerator))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := uuid.NewString()
+
+	var first, retried uuid.UUID
+	if err := Do(
+		Post(basePath+"/pvz"),
+		Send().Headers("Content-Type").Add("application/json"),
+		Send().Headers("Authorization").Add("Bearer "+moderatorToken),
+		Send().Headers("Idempotency-Key").Add(key),
+		Send().Body().JSON(map[string]string{"city": "Казань"}),
+		Expect().Status().Equal(http.StatusCreated),
+		Store().Response().Body().JSON().JQ(".id").In(&first),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	// The retry gets the first point back instead of a second one
+	if err := Do(
+		Post(basePath+"/pvz"),
+		Send().Headers("Content-Type").Add("application/json"),
+		Send().Headers("Authorization").Add("Bearer "+moderatorToken),
+		Send().Headers("Idempotency-Key").Add(key),
+		Send().Body().JSON(map[string]string{"city": "Казань"}),
+		Expect().Status().Equal(http.StatusCreated),
+		Expect().Headers("Idempotent-Replayed").Equal("true"),
+		Store().Response().Body().JSON().JQ(".id").In(&retried),
+	); err != nil {
+		t.Fatal(err)
+	}
+	if first != retried {
+		t.Fatalf("retry created point %s, want %s", retried, first)
+	}
+
+	if err := Do(
+		Post(basePath+"/pvz"),
+		Send().Headers("Content-Type").Add("application/json"),
+		Send().Headers("Authorization").Add("Bearer "+moderatorToken),
+		Send().Headers("Idempotency-Key").Add(key),
+		Send().Body().JSON(map[string]string{"city": "Москва"}),
+		Expect().Status().Equal(http.StatusUnprocessableEntity),
+		Expect().Body().JSON().JQ(".code").Equal("idempotency_key_reused"),
+	); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,47 @@
+//go:build integration
+
+package integration_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	. "github.com/Eun/go-hit"
+)
+
+func TestLegacyPathsAreDeprecatedAliases(t *testing.T) {
+	body := map[string]string{"role": string(entity.RoleModerator)}
+
+	if err := Do(
+		Post(rootPath+"/dummyLogin"),
+		Send().Headers("Content-Type").Add("application/json"),
+		Send().Body().JSON(body),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Headers("Deprecation").NotEmpty(),
+		Expect().Headers("Sunset").NotEmpty(),
+		Expect().Headers("Link").Equal(`</api/v1/dummyLogin>; rel="successor-version"`),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Do(
+		Post(basePath+"/dummyLogin"),
+		Send().Headers("Content-Type").Add("application/json"),
+		Send().Body().JSON(body),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Headers("Deprecation").Empty(),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	// Unknown paths are not aliases
+	if err := Do(
+		Get(rootPath+"/no/such/path"),
+		Expect().Status().Equal(http.StatusNotFound),
+		Expect().Headers("Deprecation").Empty(),
+		Expect().Headers("Sunset").Empty(),
+	); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,39 @@
+//go:build integration
+
+package integration_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/4udiwe/avito-pvz/internal/entity"
+	. "github.com/Eun/go-hit"
+)
+
+// TestAuthenticationBeforeValidation checks that a request without a token
+// is refused as such, not for its body.
+func TestAuthenticationBeforeValidation(t *testing.T) {
+	if err := Do(
+		Post(basePath+"/pvz"),
+		Send().Headers("Content-Type").Add("application/json"),
+		Send().Body().String(`{"city":42}`),
+		Expect().Status().Equal(http.StatusUnauthorized),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := Login(string(entity.RoleModerator))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Do(
+		Post(basePath+"/pvz"),
+		Send().Headers("Authorization").Add("Bearer "+token),
+		Send().Headers("Content-Type").Add("application/json"),
+		Send().Body().String(`{"city":42}`),
+		Expect().Status().Equal(http.StatusBadRequest),
+	); err != nil {
+		t.Fatal(err)
+	}
+}
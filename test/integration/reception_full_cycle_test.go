@@ -90,7 +90,8 @@ func closeReception(employeeToken string, pointID uuid.UUID) error {
 		Post(basePath+"/pvz/"+pointID.String()+"/close_last_reception"),
 		Send().Headers("Content-Type").Add("application/json"),
 		Send().Headers("Authorization").Add("Bearer "+employeeToken),
-		Expect().Status().Equal(http.StatusAccepted),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().JSON().JQ(".status").Equal("close"),
 	); err != nil {
 		return err
 	}